@@ -0,0 +1,398 @@
+// Package backup snapshots the database and named volumes to a directory or
+// an S3-compatible bucket on a cron schedule, prunes old backups and
+// restores them.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/reporting"
+	"github.com/AkMo3/simplify/internal/store"
+)
+
+// log is the backup scheduler's component logger, see logging.levels
+var log = logger.NewComponent("backup")
+
+// Files of a backup, under a directory named after it
+const (
+	databaseFile = "simplify.db"
+	manifestFile = "backup.json" // written last, so only complete backups have one
+	volumesDir   = "volumes"
+	namePrefix   = "simplify-"
+	nameLayout   = "20060102-150405"
+)
+
+// Engine is the part of the container engine that backs up volumes
+type Engine interface {
+	ListVolumes(ctx context.Context) ([]container.VolumeInfo, error)
+	CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error)
+	ExportVolume(ctx context.Context, name string, w io.Writer) error
+	ImportVolume(ctx context.Context, name string, r io.Reader) error
+}
+
+// Backup describes a stored backup. It is saved as the backup's manifest.
+type Backup struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Records   map[string]int `json:"records"` // database record count per bucket
+	Name      string         `json:"name"`
+	Volumes   []string       `json:"volumes,omitempty"`
+	Size      int64          `json:"size"` // bytes of the database and volume archives
+}
+
+// Options configures a Runner
+type Options struct {
+	Volumes []string // names of the volumes archived with the database
+	Retain  int      // backups to keep, 0 keeps all
+}
+
+// Runner creates, prunes and restores backups
+type Runner struct {
+	store  *store.Store
+	engine Engine
+	target Target
+	opts   Options
+	mu     sync.Mutex // one backup or restore at a time
+}
+
+// New creates a Runner that stores backups in target
+func New(s *store.Store, engine Engine, target Target, opts Options) *Runner {
+	return &Runner{store: s, engine: engine, target: target, opts: opts}
+}
+
+// Start runs a backup each time the schedule matches, until ctx is canceled
+func (r *Runner) Start(ctx context.Context, schedule *Schedule) {
+	log.Info("Starting backup scheduler", "target", r.target.String())
+
+	for {
+		next := schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Error("Backup schedule never matches, scheduled backups are disabled")
+			return
+		}
+		log.Debug("Next backup scheduled", "at", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info("Stopping backup scheduler")
+			return
+		case <-timer.C:
+		}
+
+		opCtx := logger.WithOperationID(ctx)
+		if _, err := r.Run(opCtx); err != nil {
+			log.ErrorCtx(opCtx, "Scheduled backup failed", "error", err)
+			reporting.CaptureError(opCtx, err, "component", "backup")
+		}
+	}
+}
+
+// Run creates a backup now and prunes the oldest beyond the retention
+func (r *Runner) Run(ctx context.Context) (*Backup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	b := &Backup{CreatedAt: now, Name: namePrefix + now.Format(nameLayout)}
+	log.InfoCtx(ctx, "Creating backup", "name", b.Name, "target", r.target.String())
+
+	staging, err := os.MkdirTemp("", "simplify-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Database, verified before it is stored
+	dbPath := filepath.Join(staging, databaseFile)
+	if err := r.snapshot(ctx, dbPath); err != nil {
+		return nil, err
+	}
+	summary, err := store.VerifyBackup(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("database snapshot failed verification: %w", err)
+	}
+	b.Records = summary.Records
+	if err := r.upload(ctx, dbPath, b.Name+"/"+databaseFile, b); err != nil {
+		return nil, err
+	}
+
+	// Volumes, each exported to a tar archive
+	for _, volume := range r.opts.Volumes {
+		tarPath := filepath.Join(staging, volume+".tar")
+		if err := r.exportVolume(ctx, volume, tarPath); err != nil {
+			return nil, err
+		}
+		if err := r.upload(ctx, tarPath, b.Name+"/"+volumesDir+"/"+volume+".tar", b); err != nil {
+			return nil, err
+		}
+		b.Volumes = append(b.Volumes, volume)
+	}
+
+	manifest, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	if err := r.target.Put(ctx, b.Name+"/"+manifestFile, bytes.NewReader(manifest), int64(len(manifest))); err != nil {
+		return nil, fmt.Errorf("failed to store backup manifest: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Backup created", "name", b.Name, "size", b.Size, "volumes", len(b.Volumes))
+
+	if err := r.prune(ctx); err != nil {
+		log.WarnCtx(ctx, "Failed to prune old backups", "error", err)
+	}
+	return b, nil
+}
+
+// snapshot writes the database to path
+func (r *Runner) snapshot(ctx context.Context, path string) error {
+	f, err := os.Create(path) //nolint:gosec // path is in our staging directory
+	if err != nil {
+		return fmt.Errorf("failed to stage database snapshot: %w", err)
+	}
+	if _, err := r.store.WithContext(ctx).Backup(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to stage database snapshot: %w", err)
+	}
+	return nil
+}
+
+// exportVolume writes a volume's tar archive to path
+func (r *Runner) exportVolume(ctx context.Context, volume, path string) error {
+	f, err := os.Create(path) //nolint:gosec // path is in our staging directory
+	if err != nil {
+		return fmt.Errorf("failed to stage volume %s: %w", volume, err)
+	}
+	if err := r.engine.ExportVolume(ctx, volume, f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to export volume %s: %w", volume, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to stage volume %s: %w", volume, err)
+	}
+	return nil
+}
+
+// upload stores a staged file under key and adds its size to b
+func (r *Runner) upload(ctx context.Context, path, key string, b *Backup) error {
+	f, err := os.Open(path) //nolint:gosec // path is in our staging directory
+	if err != nil {
+		return fmt.Errorf("failed to open staged file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open staged file: %w", err)
+	}
+	if err := r.target.Put(ctx, key, f, info.Size()); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	b.Size += info.Size()
+	return nil
+}
+
+// List returns the complete backups in the target, newest first
+func (r *Runner) List(ctx context.Context) ([]Backup, error) {
+	keys, err := r.target.List(ctx, namePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []Backup{}
+	for _, key := range keys {
+		name, file, _ := strings.Cut(key, "/")
+		if file != manifestFile {
+			continue
+		}
+		b, err := r.manifest(ctx, name)
+		if err != nil {
+			log.WarnCtx(ctx, "Skipping unreadable backup", "name", name, "error", err)
+			continue
+		}
+		backups = append(backups, *b)
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int { return strings.Compare(b.Name, a.Name) })
+	return backups, nil
+}
+
+// manifest reads the manifest of a backup
+func (r *Runner) manifest(ctx context.Context, name string) (*Backup, error) {
+	rc, err := r.target.Get(ctx, name+"/"+manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var b Backup
+	if err := json.NewDecoder(rc).Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	return &b, nil
+}
+
+// prune deletes backups beyond the retention, oldest first, along with
+// incomplete backups older than the newest complete one
+func (r *Runner) prune(ctx context.Context) error {
+	keys, err := r.target.List(ctx, namePrefix)
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string][]string) // backup name -> keys
+	var complete []string
+	for _, key := range keys {
+		name, file, _ := strings.Cut(key, "/")
+		files[name] = append(files[name], key)
+		if file == manifestFile {
+			complete = append(complete, name)
+		}
+	}
+	if len(complete) == 0 {
+		return nil
+	}
+	slices.Sort(complete)
+	newest := complete[len(complete)-1]
+
+	var expired []string
+	if r.opts.Retain > 0 && len(complete) > r.opts.Retain {
+		expired = slices.Clone(complete[:len(complete)-r.opts.Retain])
+	}
+	for name := range files {
+		if !slices.Contains(complete, name) && name < newest {
+			expired = append(expired, name)
+		}
+	}
+
+	for _, name := range expired {
+		for _, key := range files[name] {
+			if err := r.target.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete backup %s: %w", name, err)
+			}
+		}
+		log.InfoCtx(ctx, "Pruned backup", "name", name)
+	}
+	return nil
+}
+
+// Restore replaces the database with a stored backup and, when volumes is
+// set, extracts its volume archives into the volumes, creating missing
+// ones. Applications using a restored volume should be stopped first.
+func (r *Runner) Restore(ctx context.Context, name string, volumes bool) (*Backup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if path.Base(name) != name || !strings.HasPrefix(name, namePrefix) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	b, err := r.manifest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	log.InfoCtx(ctx, "Restoring backup", "name", name, "volumes", volumes)
+
+	staging, err := os.MkdirTemp("", "simplify-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Fetch everything before changing anything
+	dbPath := filepath.Join(staging, databaseFile)
+	if err := r.download(ctx, name+"/"+databaseFile, dbPath); err != nil {
+		return nil, err
+	}
+	if volumes {
+		for _, volume := range b.Volumes {
+			if err := r.download(ctx, name+"/"+volumesDir+"/"+volume+".tar", filepath.Join(staging, volume+".tar")); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := r.store.WithContext(ctx).Restore(dbPath); err != nil {
+		return nil, err
+	}
+	if !volumes {
+		return b, nil
+	}
+
+	existing, err := r.engine.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	for _, volume := range b.Volumes {
+		if !slices.ContainsFunc(existing, func(v container.VolumeInfo) bool { return v.Name == volume }) {
+			if _, err := r.engine.CreateVolume(ctx, volume, nil); err != nil {
+				return nil, fmt.Errorf("failed to create volume %s: %w", volume, err)
+			}
+		}
+		if err := r.importVolume(ctx, volume, filepath.Join(staging, volume+".tar")); err != nil {
+			return nil, err
+		}
+	}
+
+	log.InfoCtx(ctx, "Backup restored", "name", name, "volumes", len(b.Volumes))
+	return b, nil
+}
+
+// download copies a stored file to path
+func (r *Runner) download(ctx context.Context, key, path string) error {
+	rc, err := r.target.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.Create(path) //nolint:gosec // path is in our staging directory
+	if err != nil {
+		return fmt.Errorf("failed to stage %s: %w", key, err)
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", key, err)
+	}
+	return nil
+}
+
+// importVolume extracts a staged archive into a volume
+func (r *Runner) importVolume(ctx context.Context, volume, path string) error {
+	f, err := os.Open(path) //nolint:gosec // path is in our staging directory
+	if err != nil {
+		return fmt.Errorf("failed to open staged archive: %w", err)
+	}
+	defer f.Close()
+
+	if err := r.engine.ImportVolume(ctx, volume, f); err != nil {
+		return fmt.Errorf("failed to import volume %s: %w", volume, err)
+	}
+	return nil
+}
+
+// IsNotFound reports whether err means a backup or one of its files does
+// not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,159 @@
+package backup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // bit n set when value n matches
+	anyDOM, anyDOW                bool   // the field was *, see matchesDay
+}
+
+// cronMacros are the shorthands accepted in place of five fields
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// ParseSchedule parses a standard five-field cron expression (minute, hour,
+// day of month, month, day of week) or one of @hourly, @daily, @weekly and
+// @monthly. Fields accept *, numbers, ranges (1-5), lists (1,15) and steps
+// (*/15 or 0-30/10). Sunday is 0 or 7.
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &Schedule{
+		anyDOM: fields[2] == "*",
+		anyDOW: fields[4] == "*",
+	}
+	specs := []struct {
+		bits     *uint64
+		name     string
+		min, max int
+	}{
+		{&s.minute, "minute", 0, 59},
+		{&s.hour, "hour", 0, 23},
+		{&s.dom, "day of month", 1, 31},
+		{&s.month, "month", 1, 12},
+		{&s.dow, "day of week", 0, 7},
+	}
+	for i, spec := range specs {
+		bits, err := parseField(fields[i], spec.min, spec.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron %s %q: %w", spec.name, fields[i], err)
+		}
+		*spec.bits = bits
+	}
+
+	// 7 is another name for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseField parses one comma-separated cron field into a bit set
+func parseField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			if end, err = strconv.Atoi(b); err != nil {
+				return 0, fmt.Errorf("invalid value %q", b)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rng)
+			}
+			start, end = n, n
+			if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("values must be between %d and %d", lo, hi)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v) //nolint:gosec // v is between lo and hi
+		}
+	}
+	return bits, nil
+}
+
+// maxScheduleSearch bounds the search for the next run of expressions that
+// never match, such as February 30th
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first time after t that the schedule matches, in t's
+// location, or the zero time when it never matches
+func (s *Schedule) Next(t time.Time) time.Time {
+	limit := t.Add(maxScheduleSearch)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay applies cron's rule for the two day fields: when both are
+// restricted, a day matching either one matches
+func (s *Schedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.anyDOM && s.anyDOW:
+		return true
+	case s.anyDOM:
+		return dow
+	case s.anyDOW:
+		return dom
+	default:
+		return dom || dow
+	}
+}
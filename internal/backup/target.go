@@ -0,0 +1,156 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/config"
+)
+
+// Target stores backup files by key. Keys use / as separator.
+type Target interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	List(ctx context.Context, prefix string) ([]string, error) // keys starting with prefix, sorted
+	Delete(ctx context.Context, key string) error
+	String() string // describes the target in logs
+}
+
+// NewTarget returns the target configured for backups, an S3 bucket when
+// one is set and the directory otherwise
+func NewTarget(cfg *config.BackupConfig) Target {
+	if s3 := cfg.S3; s3.Bucket != "" {
+		return &S3{
+			Endpoint:  s3.Endpoint,
+			Bucket:    s3.Bucket,
+			Prefix:    s3.Prefix,
+			Region:    s3.Region,
+			AccessKey: s3.AccessKey,
+			SecretKey: s3.SecretKey,
+			PathStyle: s3.PathStyle,
+		}
+	}
+	return &Dir{Path: cfg.Dir}
+}
+
+// ErrNotFound is returned by Target.Get for a missing key
+var ErrNotFound = errors.New("backup file not found")
+
+// Dir stores backups in a local directory
+type Dir struct {
+	Path string
+}
+
+// path returns the file of a key, refusing keys that escape the directory
+func (d *Dir) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid backup key %q", key)
+	}
+	return filepath.Join(d.Path, clean), nil
+}
+
+// Put writes a file next to its destination and renames it into place, so
+// a failed write never leaves a partial file
+func (d *Dir) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".simplify-backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return nil
+}
+
+// Get opens a stored file
+func (d *Dir) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path) //nolint:gosec // path is confined to the backup directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup file: %w", err)
+	}
+	return f, nil
+}
+
+// List returns the keys of the stored files starting with prefix
+func (d *Dir) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(d.Path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == d.Path {
+				return fs.SkipAll // nothing backed up yet
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".simplify-backup-") {
+			return nil
+		}
+		rel, err := filepath.Rel(d.Path, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup directory: %w", err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Delete removes a stored file, and its directory once empty
+func (d *Dir) Delete(ctx context.Context, key string) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete backup file: %w", err)
+	}
+	// Fails while other files remain, which is what we want
+	for dir := filepath.Dir(path); dir != filepath.Clean(d.Path); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// String describes the directory
+func (d *Dir) String() string {
+	return "dir " + d.Path
+}
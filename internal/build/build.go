@@ -0,0 +1,252 @@
+// Package build turns a commit of a git repository into a container image.
+//
+// Every step runs in a one-off task container on the engine: a git image
+// clones the repository into a workspace volume, then either a Podman
+// image builds its Containerfile or the pack CLI runs Cloud Native
+// Buildpacks. Both talk to the host engine through its socket, so the
+// image lands in the engine's local storage where applications can use it.
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+)
+
+// Engine runs the build steps. *container.Client implements it.
+type Engine interface {
+	RunTask(ctx context.Context, task container.TaskSpec, out io.Writer) (int, error)
+	CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error)
+	RemoveVolume(ctx context.Context, name string, force bool) error
+}
+
+// Options holds the images the steps run in and the engine socket they
+// build against
+type Options struct {
+	GitImage    string // clones the repository
+	PodmanImage string // runs podman build against Socket
+	PackImage   string // runs pack build against Socket
+	Builder     string // buildpacks builder image
+	Socket      string // path of the engine's API socket on the host
+}
+
+// Builder runs builds on an engine
+type Builder struct {
+	engine Engine
+	opts   Options
+}
+
+// New returns a Builder running its steps on engine
+func New(engine Engine, opts Options) *Builder {
+	return &Builder{engine: engine, opts: opts}
+}
+
+// Paths inside the step containers
+const (
+	workspaceDir = "/workspace"
+	sourceDir    = workspaceDir + "/src"
+)
+
+// Markers the clone step prints for the builder to read
+const (
+	commitMarker        = "simplify-commit="
+	containerfileMarker = "simplify-containerfile="
+)
+
+// cloneScript clones GIT_URL at GIT_REF, or the default branch, and prints
+// the commit and the Containerfile found in BUILD_CONTEXT
+const cloneScript = `set -e
+rm -rf ` + sourceDir + `
+if [ -n "$GIT_REF" ]; then
+  git clone --depth 1 --branch "$GIT_REF" -- "$GIT_URL" ` + sourceDir + `
+else
+  git clone --depth 1 -- "$GIT_URL" ` + sourceDir + `
+fi
+cd ` + sourceDir + `
+echo "` + commitMarker + `$(git rev-parse HEAD)"
+cd "./$BUILD_CONTEXT"
+for f in Containerfile Dockerfile; do
+  if [ -f "$f" ]; then echo "` + containerfileMarker + `$f"; break; fi
+done
+`
+
+// namePattern matches the characters allowed in image repository names
+var namePattern = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// ImageTag returns the local tag of an application's image built by a build
+func ImageTag(appName, buildID string) string {
+	name := strings.Trim(namePattern.ReplaceAllString(strings.ToLower(appName), "-"), "-._")
+	if name == "" {
+		name = "app"
+	}
+	return fmt.Sprintf("localhost/simplify/%s:%s", name, shortID(buildID))
+}
+
+// Build clones b.RepoURL at b.Ref and builds b.Image with b.Strategy,
+// writing the steps' output to out. It records the commit that was built
+// in b.Commit and the strategy used in b.Strategy.
+func (bl *Builder) Build(ctx context.Context, b *core.Build, out io.Writer) error {
+	if b.Image == "" {
+		return fmt.Errorf("build %s has no image tag", b.ID)
+	}
+	contextDir, err := cleanContext(b.Context)
+	if err != nil {
+		return err
+	}
+
+	volume := "simplify-build-" + b.ID
+	if _, err := bl.engine.CreateVolume(ctx, volume, map[string]string{container.LabelTask: "true"}); err != nil {
+		return fmt.Errorf("failed to create build workspace: %w", err)
+	}
+	defer func() {
+		// ctx may be canceled already; the workspace must still go
+		if err := bl.engine.RemoveVolume(context.WithoutCancel(ctx), volume, true); err != nil {
+			fmt.Fprintf(out, "warning: failed to remove build workspace %s: %v\n", volume, err)
+		}
+	}()
+	workspace := []container.VolumeMount{{Volume: volume, Target: workspaceDir}}
+
+	// Clone, keeping the output to read the markers
+	var cloneOut bytes.Buffer
+	fmt.Fprintf(out, "==> Cloning %s\n", b.RepoURL)
+	code, err := bl.engine.RunTask(ctx, container.TaskSpec{
+		Name:       stepName(b.ID, "clone"),
+		Image:      bl.opts.GitImage,
+		Entrypoint: []string{"/bin/sh", "-c"},
+		Command:    []string{cloneScript},
+		Env:        []string{"GIT_URL=" + b.RepoURL, "GIT_REF=" + b.Ref, "BUILD_CONTEXT=" + contextDir},
+		Volumes:    workspace,
+	}, io.MultiWriter(out, &cloneOut))
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+	if code != 0 {
+		return fmt.Errorf("cloning %s failed with exit code %d", b.RepoURL, code)
+	}
+	markers := parseMarkers(&cloneOut)
+	b.Commit = markers[commitMarker]
+
+	strategy := b.Strategy
+	if strategy == core.BuildAuto {
+		strategy = core.BuildBuildpacks
+		if b.Containerfile != "" || markers[containerfileMarker] != "" {
+			strategy = core.BuildContainerfile
+		}
+	}
+	b.Strategy = strategy
+
+	task, err := bl.buildTask(b, contextDir, markers[containerfileMarker])
+	if err != nil {
+		return err
+	}
+	task.Volumes = workspace
+
+	fmt.Fprintf(out, "==> Building %s with %s\n", b.Image, strategy)
+	code, err = bl.engine.RunTask(ctx, task, out)
+	if err != nil {
+		return fmt.Errorf("failed to build image: %w", err)
+	}
+	if code != 0 {
+		return fmt.Errorf("%s build failed with exit code %d", strategy, code)
+	}
+	fmt.Fprintf(out, "==> Built %s from %s\n", b.Image, shortID(b.Commit))
+	return nil
+}
+
+// buildTask returns the step that builds the cloned source with the
+// build's strategy
+func (bl *Builder) buildTask(b *core.Build, contextDir, found string) (container.TaskSpec, error) {
+	socket := container.BindMount{Source: bl.opts.Socket, Target: bl.opts.Socket}
+	source := path.Join(sourceDir, contextDir)
+
+	switch b.Strategy {
+	case core.BuildContainerfile:
+		file := b.Containerfile
+		if file == "" {
+			file = found
+		}
+		if file == "" {
+			return container.TaskSpec{}, fmt.Errorf("no Containerfile or Dockerfile in %s", displayContext(contextDir))
+		}
+		return container.TaskSpec{
+			Name:  stepName(b.ID, "build"),
+			Image: bl.opts.PodmanImage,
+			Command: []string{
+				"podman", "--remote", "--url", "unix://" + bl.opts.Socket,
+				"build", "--tag", b.Image, "--file", path.Join(source, file), source,
+			},
+			Binds: []container.BindMount{socket},
+		}, nil
+	case core.BuildBuildpacks:
+		// The pack image's entrypoint is pack; inherit hands the socket
+		// from DOCKER_HOST on to the lifecycle containers
+		return container.TaskSpec{
+			Name:  stepName(b.ID, "build"),
+			Image: bl.opts.PackImage,
+			Command: []string{
+				"build", b.Image, "--path", source, "--builder", bl.opts.Builder,
+				"--docker-host", "inherit", "--trust-builder",
+			},
+			Env:   []string{"DOCKER_HOST=unix://" + bl.opts.Socket},
+			Binds: []container.BindMount{socket},
+		}, nil
+	default:
+		return container.TaskSpec{}, fmt.Errorf("unknown build strategy %q", b.Strategy)
+	}
+}
+
+// cleanContext validates a build context directory, which must stay
+// within the repository
+func cleanContext(dir string) (string, error) {
+	for _, part := range strings.Split(dir, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("build context %q must be within the repository", dir)
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+dir), "/"), nil
+}
+
+// displayContext names a context directory in messages
+func displayContext(dir string) string {
+	if dir == "" {
+		return "the repository root"
+	}
+	return dir
+}
+
+// parseMarkers reads the marker lines printed by the clone step
+func parseMarkers(r io.Reader) map[string]string {
+	markers := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		for _, m := range []string{commitMarker, containerfileMarker} {
+			if value, ok := strings.CutPrefix(line, m); ok {
+				markers[m] = value
+			}
+		}
+	}
+	return markers
+}
+
+// stepName names the task container of a build step
+func stepName(buildID, step string) string {
+	return fmt.Sprintf("build-%s-%s", shortID(buildID), step)
+}
+
+// shortID shortens build IDs and commits for tags and messages
+func shortID(id string) string {
+	id = strings.ReplaceAll(id, "-", "")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
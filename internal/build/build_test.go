@@ -0,0 +1,128 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine records tasks and replays canned output for each step
+type fakeEngine struct {
+	output  map[string]string // step name suffix to output
+	codes   map[string]int
+	volumes map[string]bool
+	tasks   []container.TaskSpec
+}
+
+func newFakeEngine() *fakeEngine {
+	return &fakeEngine{output: map[string]string{}, codes: map[string]int{}, volumes: map[string]bool{}}
+}
+
+func (f *fakeEngine) RunTask(ctx context.Context, task container.TaskSpec, out io.Writer) (int, error) {
+	f.tasks = append(f.tasks, task)
+	for step, text := range f.output {
+		if strings.HasSuffix(task.Name, "-"+step) {
+			fmt.Fprint(out, text)
+			return f.codes[step], nil
+		}
+	}
+	return 0, nil
+}
+
+func (f *fakeEngine) CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error) {
+	f.volumes[name] = true
+	return name, nil
+}
+
+func (f *fakeEngine) RemoveVolume(ctx context.Context, name string, force bool) error {
+	delete(f.volumes, name)
+	return nil
+}
+
+var testOptions = Options{
+	GitImage:    "git",
+	PodmanImage: "podman",
+	PackImage:   "pack",
+	Builder:     "builder",
+	Socket:      "/run/podman/podman.sock",
+}
+
+func TestBuildContainerfile(t *testing.T) {
+	engine := newFakeEngine()
+	engine.output["clone"] = "Cloning...\nsimplify-commit=0123456789abcdef\nsimplify-containerfile=Dockerfile\n"
+
+	b := &core.Build{ID: "b1", RepoURL: "https://example.com/shop.git", Ref: "main", Context: "web/", Image: "localhost/simplify/web:b1"}
+	var out bytes.Buffer
+	require.NoError(t, New(engine, testOptions).Build(context.Background(), b, &out))
+
+	assert.Equal(t, "0123456789abcdef", b.Commit)
+	assert.Equal(t, core.BuildContainerfile, b.Strategy)
+	assert.Empty(t, engine.volumes, "workspace volume removed")
+
+	require.Len(t, engine.tasks, 2)
+	clone := engine.tasks[0]
+	assert.Equal(t, "git", clone.Image)
+	assert.Contains(t, clone.Env, "GIT_REF=main")
+	assert.Contains(t, clone.Env, "BUILD_CONTEXT=web")
+
+	build := engine.tasks[1]
+	assert.Equal(t, "podman", build.Image)
+	assert.Equal(t, []string{
+		"podman", "--remote", "--url", "unix:///run/podman/podman.sock",
+		"build", "--tag", "localhost/simplify/web:b1", "--file", "/workspace/src/web/Dockerfile", "/workspace/src/web",
+	}, build.Command)
+	require.Len(t, build.Binds, 1)
+	assert.Equal(t, "/run/podman/podman.sock", build.Binds[0].Source)
+	assert.Equal(t, clone.Volumes, build.Volumes)
+	assert.Contains(t, out.String(), "Cloning...")
+}
+
+func TestBuildBuildpacks(t *testing.T) {
+	engine := newFakeEngine()
+	engine.output["clone"] = "simplify-commit=abc\n"
+
+	b := &core.Build{ID: "b2", RepoURL: "https://example.com/api.git", Image: "localhost/simplify/api:b2"}
+	require.NoError(t, New(engine, testOptions).Build(context.Background(), b, io.Discard))
+
+	assert.Equal(t, core.BuildBuildpacks, b.Strategy)
+	require.Len(t, engine.tasks, 2)
+	build := engine.tasks[1]
+	assert.Equal(t, "pack", build.Image)
+	assert.Equal(t, []string{"DOCKER_HOST=unix:///run/podman/podman.sock"}, build.Env)
+	assert.Contains(t, build.Command, "builder")
+}
+
+func TestBuildFailures(t *testing.T) {
+	engine := newFakeEngine()
+	engine.output["clone"] = "fatal: repository not found\n"
+	engine.codes["clone"] = 128
+
+	b := &core.Build{ID: "b3", RepoURL: "https://example.com/missing.git", Image: "x"}
+	err := New(engine, testOptions).Build(context.Background(), b, io.Discard)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit code 128")
+	assert.Empty(t, engine.volumes)
+
+	// A Containerfile build needs a Containerfile
+	engine = newFakeEngine()
+	b = &core.Build{ID: "b4", RepoURL: "r", Image: "x", Strategy: core.BuildContainerfile}
+	err = New(engine, testOptions).Build(context.Background(), b, io.Discard)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no Containerfile")
+
+	b = &core.Build{ID: "b5", RepoURL: "r", Image: "x", Context: "../etc"}
+	assert.Error(t, New(engine, testOptions).Build(context.Background(), b, io.Discard))
+}
+
+func TestImageTag(t *testing.T) {
+	assert.Equal(t, "localhost/simplify/my-app:0123456789ab", ImageTag("My App", "01234567-89ab-cdef"))
+	assert.Equal(t, "localhost/simplify/app:b1", ImageTag("!!", "b1"))
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show recent changes and events",
+	Long: `Show the activity feed of the Simplify server, newest first: who
+created, changed or deleted which resource, and events such as deploys and
+health changes reported by the reconciler (actor "system").
+
+The feed can be narrowed to an application, or to a team or project by ID.`,
+	Example: `  simplify activity
+  simplify activity --app web
+  simplify activity --team 3f2a... --limit 100 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runActivity,
+}
+
+var (
+	activityApp     string
+	activityTeam    string
+	activityProject string
+	activityLimit   int
+)
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+
+	activityCmd.Flags().StringVar(&activityApp, "app", "", "Only show activity of this application")
+	activityCmd.Flags().StringVar(&activityTeam, "team", "", "Only show activity of the team with this ID")
+	activityCmd.Flags().StringVar(&activityProject, "project", "", "Only show activity of the project with this ID")
+	activityCmd.Flags().IntVar(&activityLimit, "limit", core.DefaultActivityLimit, "Maximum number of entries")
+}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	filter := core.ActivityFilter{TeamID: activityTeam, ProjectID: activityProject, Limit: activityLimit}
+	if activityApp != "" {
+		ids, err := applicationIDs(ctx, client, activityApp)
+		if err != nil {
+			return err
+		}
+		filter.ApplicationID = ids[0]
+	}
+
+	entries, err := client.ListActivity(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+
+	return printOutput(entries, func(out io.Writer) error {
+		if len(entries) == 0 {
+			fmt.Fprintln(out, "No activity recorded")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "WHEN\tACTOR\tACTION\tRESOURCE\tMESSAGE")
+		for i := range entries {
+			a := &entries[i]
+			resource := a.ResourceType + " " + a.ResourceName
+			if a.ResourceName == "" {
+				resource = a.ResourceType + " " + a.ResourceID
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", formatCreatedTime(a.CreatedAt), a.Actor, a.Action, resource, a.Message)
+		}
+		return w.Flush()
+	})
+}
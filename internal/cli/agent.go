@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/permissions"
+	"github.com/AkMo3/simplify/internal/reconciler"
+	"github.com/AkMo3/simplify/internal/server"
+	"github.com/AkMo3/simplify/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run the applications a Simplify server schedules to this host",
+	Long: `Register this host as a node with a Simplify server and run the
+applications scheduled to it.
+
+The agent reports the host's capacity and the status of its applications
+in a heartbeat, and copies the applications and the pods, networks,
+volumes and secrets they use into a local database. Its reconciler runs
+from that copy, so applications keep running while the server is away.
+
+Applications are scheduled to nodes whose labels match their node
+selector, for example 'simplify deploy --node-selector region=eu'.
+
+The agent registers with the server's join token (server.join_token), given
+with --join-token or SIMPLIFY_JOIN_TOKEN. The token issued to the node is
+kept in agent-node.token next to the agent's database; the server needs it
+to let the node register again under the same name.`,
+	Example: `  simplify agent --server http://control.example.com:8080 --join-token "$JOIN_TOKEN" --label region=eu
+  simplify agent --server http://10.0.0.1:8080 --name worker-1 --data /var/lib/simplify/agent.db`,
+	Args: cobra.NoArgs,
+	RunE: runAgent,
+}
+
+var (
+	agentName      string
+	agentJoinToken string
+	agentLabels    []string
+	agentDataPath  string
+	agentInterval  time.Duration
+)
+
+// defaultAgentDataPath is the agent's local copy of its node's state
+const defaultAgentDataPath = "/var/lib/simplify/agent.db"
+
+// agentJoinTokenEnv sets the join token without putting it on the command line
+const agentJoinTokenEnv = "SIMPLIFY_JOIN_TOKEN"
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+
+	agentCmd.Flags().StringVar(&agentName, "name", "", "Node name (default: the hostname)")
+	agentCmd.Flags().StringVar(&agentJoinToken, "join-token", "", "The server's join token (default: $"+agentJoinTokenEnv+")")
+	agentCmd.Flags().StringSliceVarP(&agentLabels, "label", "l", []string{}, "Node labels matched by node selectors (KEY=VALUE)")
+	agentCmd.Flags().StringVar(&agentDataPath, "data", defaultAgentDataPath, "Path of the agent's local database")
+	agentCmd.Flags().DurationVar(&agentInterval, "interval", 10*time.Second, "How often to sync with the server and reconcile")
+}
+
+// agent keeps a node registered with the server and its local store in
+// sync with the node's state
+type agent struct {
+	client    *apiClient
+	store     *store.Store
+	labels    map[string]string
+	engine    container.ContainerManager
+	worker    *reconciler.Worker // nil until the first sync has placed the node's applications
+	name      string
+	nodeID    string
+	joinToken string
+	token     string // the node's current token, needed to register again
+	tokenPath string
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	name := agentName
+	if name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to read hostname, set --name: %w", err)
+		}
+		name = host
+	}
+	labels, err := parseLabels(agentLabels)
+	if err != nil {
+		return err
+	}
+	if agentInterval <= 0 {
+		return errors.NewInvalidInputErrorWithField("interval", "interval must be positive")
+	}
+	joinToken := agentJoinToken
+	if joinToken == "" {
+		joinToken = os.Getenv(agentJoinTokenEnv)
+	}
+	if joinToken == "" {
+		return errors.NewInvalidInputErrorWithField("join-token", "the server's join token is required, set --join-token or "+agentJoinTokenEnv)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	st, err := store.New(agentDataPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := st.Close(); err != nil {
+			logger.Error("Failed to close store", "error", err)
+		}
+	}()
+
+	// Secrets copied from the server are encrypted at rest with the agent's own key
+	keyPath := filepath.Join(filepath.Dir(agentDataPath), "agent-secret.key")
+	key, err := store.LoadSecretKey(keyPath)
+	if err != nil {
+		return err
+	}
+	if err := st.SetSecretKey(key); err != nil {
+		return err
+	}
+
+	tokenPath := filepath.Join(filepath.Dir(agentDataPath), "agent-node.token")
+	token, err := os.ReadFile(tokenPath) //nolint:gosec // path is from the agent's own flags
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read node token: %w", err)
+	}
+
+	if err := permissions.CheckPodmanSocket(container.SocketPath()); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	podman, err := container.Shared(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	a := &agent{
+		client:    client,
+		store:     st,
+		labels:    labels,
+		engine:    podman,
+		name:      name,
+		joinToken: joinToken,
+		token:     strings.TrimSpace(string(token)),
+		tokenPath: tokenPath,
+	}
+	if err := a.register(ctx); err != nil {
+		return err
+	}
+	if err := a.sync(ctx); err != nil {
+		return err
+	}
+
+	worker := reconciler.New(st, podman)
+	worker.SetNodeID(a.nodeID)
+	worker.SetInterval(agentInterval)
+	worker.SetConfigDir(filepath.Join(filepath.Dir(agentDataPath), "configs"))
+	a.worker = worker
+	go worker.Start(ctx)
+	logger.Info("Agent started", "node", a.name, "id", a.nodeID, "server", client.BaseURL())
+
+	ticker := time.NewTicker(agentInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Agent stopped")
+			return nil
+		case <-ticker.C:
+			err := a.sync(ctx)
+			if errors.IsNotFound(err) {
+				// The server lost the node or its token; register again
+				logger.Warn("Node not recognized by the server, registering again", "node", a.name)
+				err = a.register(ctx)
+			}
+			if err != nil {
+				if a.nodeID == "" {
+					return err
+				}
+				logger.Error("Failed to sync with server", "error", err)
+			}
+		}
+	}
+}
+
+// register registers the node with the server and authenticates further
+// requests with the token it returns, which is saved for the next time the
+// node registers
+func (a *agent) register(ctx context.Context) error {
+	req := server.RegisterNodeRequest{Name: a.name, Labels: a.labels, JoinToken: a.joinToken, Capacity: hostCapacity()}
+
+	node, err := a.client.WithToken(a.token).RegisterNode(ctx, &req)
+	if err != nil {
+		return fmt.Errorf("failed to register node: %w", err)
+	}
+	a.token = node.Token
+	if err := os.WriteFile(a.tokenPath, []byte(node.Token+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to save node token: %w", err)
+	}
+	if a.nodeID != "" && node.ID != a.nodeID {
+		// The reconciler runs the applications of the old ID; a node that was
+		// removed starts over
+		a.nodeID = ""
+		return fmt.Errorf("node %s was removed from the server and registered as %s; restart the agent", a.name, node.ID)
+	}
+
+	a.nodeID = node.ID
+	a.client = a.client.WithToken(node.Token)
+	return nil
+}
+
+// sync sends a heartbeat and copies the node's state into the local store
+func (a *agent) sync(ctx context.Context) error {
+	hb := server.HeartbeatRequest{AppStatus: a.appStatus(ctx), Capacity: hostCapacity()}
+	if a.worker != nil {
+		hb.AppHealth = a.worker.Health()
+	}
+	if _, err := a.client.Heartbeat(ctx, a.nodeID, &hb); err != nil {
+		return err
+	}
+
+	state, err := a.client.GetNodeState(ctx, a.nodeID)
+	if err != nil {
+		return err
+	}
+	if err := a.store.WithContext(ctx).SyncNodeState(state); err != nil {
+		return fmt.Errorf("failed to store node state: %w", err)
+	}
+	return nil
+}
+
+// appStatus returns the status of the first replica of each managed
+// container's application
+func (a *agent) appStatus(ctx context.Context) map[string]string {
+	containers, err := a.engine.List(ctx, true)
+	if err != nil {
+		logger.Warn("Failed to list containers", "error", err)
+		return nil
+	}
+
+	status := make(map[string]string)
+	for i := range containers {
+		c := &containers[i]
+		appID := c.Labels["simplify.app.id"]
+		replica, _ := strconv.Atoi(c.Labels["simplify.app.replica"]) //nolint:errcheck // unlabeled containers are the first replica
+		if appID == "" || replica != 0 {
+			continue
+		}
+		status[appID] = c.Status
+	}
+	return status
+}
+
+// hostCapacity returns the CPUs and memory of this host
+func hostCapacity() core.NodeCapacity {
+	return core.NodeCapacity{MemoryBytes: hostMemory(), CPUs: runtime.NumCPU()}
+}
+
+// hostMemory returns the total memory in bytes from /proc/meminfo, or 0
+// when it cannot be read
+func hostMemory() int64 {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb * 1024
+		}
+	}
+	return 0
+}
+
+// parseLabels parses KEY=VALUE labels
+func parseLabels(labels []string) (map[string]string, error) {
+	result := make(map[string]string, len(labels))
+	for _, l := range labels {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || key == "" {
+			return nil, errors.NewInvalidInputError(fmt.Sprintf("invalid label %q (use KEY=VALUE format)", l))
+		}
+		result[key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"os/user"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/pkg/client"
+)
+
+// apiClient is the client of the Simplify HTTP API the commands share
+type apiClient = client.Client
+
+// newAPIClient creates a client for the server selected by --server or the
+// active context, falling back to the local server from the loaded configuration.
+func newAPIClient() (*apiClient, error) {
+	return newAPIClientWith(client.Options{})
+}
+
+// newAPIClientWith is newAPIClient with the timeouts and retries of opts.
+// The token and actor always come from the active context.
+func newAPIClientWith(opts client.Options) (*apiClient, error) {
+	active, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := serverURL
+	if baseURL == "" {
+		baseURL = active.Server
+	}
+	if baseURL == "" {
+		baseURL = config.Get().LocalURL()
+	}
+
+	opts.Token = active.Token
+	opts.Actor = currentActor()
+	return client.New(baseURL, opts), nil
+}
+
+// currentActor names the local user as user@host for the server's
+// deployment history
+func currentActor() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		name += "@" + host
+	}
+	return name
+}
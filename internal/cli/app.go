@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var appCmd = &cobra.Command{
+	Use:     "app",
+	Aliases: []string{"apps"},
+	Short:   "Manage applications on the Simplify server",
+}
+
+var appListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List applications",
+	Example: `  simplify app list
+  simplify app list --watch`,
+	Args: cobra.NoArgs,
+	RunE: runAppList,
+}
+
+var appScaleCmd = &cobra.Command{
+	Use:   "scale NAME REPLICAS",
+	Short: "Set the number of replicas of an application",
+	Long: `Set the number of replicas of an application and wait until the
+reconciler runs that many up-to-date containers, reporting the status of
+each replica as it changes.
+
+Applications that publish host ports or run in a pod can only run one
+replica, as further containers would conflict on the same ports.`,
+	Example: `  simplify app scale worker 3
+  simplify app scale worker 1 --no-wait`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppScale,
+}
+
+var appHistoryCmd = &cobra.Command{
+	Use:               "history NAME",
+	Short:             "Show the deployment history of an application",
+	Example:           `  simplify app history web`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppHistory,
+}
+
+var appRollbackCmd = &cobra.Command{
+	Use:   "rollback NAME [REVISION]",
+	Short: "Restore an earlier revision of an application",
+	Long: `Restore the image, environment, ports, secrets and volumes recorded at
+an earlier revision, then wait until the reconciler has replaced the
+running containers. Without REVISION the previous deployment is restored.
+Revisions are listed by 'simplify app history'.`,
+	Example: `  simplify app rollback web
+  simplify app rollback web 2026-10-01T09:30:00.123456Z`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppRollback,
+}
+
+var appExportCmd = &cobra.Command{
+	Use:   "export NAME",
+	Short: "Export an application as a manifest for another server",
+	Long: `Write an application as a YAML manifest that 'simplify apply' accepts on
+another Simplify server. IDs, the environment, pod, stack, network and node
+it belongs to, and its runtime state are left out. Sensitive environment
+variables are exported with empty values and secrets by name only: a
+comment at the top of the manifest lists what to fill in or create first.`,
+	Example: `  simplify app export web > web.yaml
+  simplify app export web -f web.yaml`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppExport,
+}
+
+var (
+	appWatch           time.Duration
+	appScaleTimeout    time.Duration
+	appScaleNoWait     bool
+	appRollbackTimeout time.Duration
+	appRollbackNoWait  bool
+	appExportFile      string
+)
+
+func init() {
+	rootCmd.AddCommand(appCmd)
+	appCmd.AddCommand(appListCmd)
+	appCmd.AddCommand(appScaleCmd)
+	appCmd.AddCommand(appHistoryCmd)
+	appCmd.AddCommand(appRollbackCmd)
+	appCmd.AddCommand(appExportCmd)
+
+	addWatchFlag(appListCmd, &appWatch)
+
+	appScaleCmd.Flags().DurationVar(&appScaleTimeout, "timeout", 2*time.Minute, "How long to wait for the replicas to run")
+	appScaleCmd.Flags().BoolVar(&appScaleNoWait, "no-wait", false, "Return once the new replica count is stored")
+
+	appRollbackCmd.Flags().DurationVar(&appRollbackTimeout, "timeout", 2*time.Minute, "How long to wait for the restored revision to run")
+	appRollbackCmd.Flags().BoolVar(&appRollbackNoWait, "no-wait", false, "Return once the restored spec is stored")
+
+	appExportCmd.Flags().StringVarP(&appExportFile, "file", "f", "", "Manifest file to write instead of stdout")
+}
+
+func runAppList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	return runWatched(ctx, appWatch, "simplify app list", func(ctx context.Context, out io.Writer) error {
+		apps, err := client.ListApplications(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list applications: %w", err)
+		}
+
+		return writeOutput(out, apps, func(out io.Writer) error {
+			if len(apps) == 0 {
+				fmt.Fprintln(out, "No applications found")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tIMAGE\tSTATUS\tHEALTH\tREPLICAS\tPORTS")
+			for i := range apps {
+				a := &apps[i]
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+					a.Name,
+					truncateString(a.Image, 30),
+					a.Status,
+					a.HealthStatus,
+					a.Replicas,
+					truncateString(formatPortMap(a.Ports), 40),
+				)
+			}
+			return w.Flush()
+		})
+	})
+}
+
+func runAppScale(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	name := args[0]
+
+	replicas, err := strconv.Atoi(args[1])
+	if err != nil || replicas < 1 {
+		return errors.NewInvalidInputErrorWithField("replicas", fmt.Sprintf("invalid replica count %q (must be at least 1)", args[1]))
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, name)
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", name)
+	}
+
+	scaled, err := client.ScaleApplication(ctx, app.ID, replicas)
+	if err != nil {
+		return fmt.Errorf("failed to scale application: %w", err)
+	}
+
+	if appScaleNoWait {
+		fmt.Printf("Application %s scaled to %d replica(s)\n", scaled.Name, replicas)
+		return nil
+	}
+
+	fmt.Printf("Scaling %s from %d to %d replica(s)...\n", scaled.Name, max(app.Replicas, 1), replicas)
+	instances, err := waitForReplicas(ctx, client, scaled, appScaleTimeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Application %s is running %d replica(s)\n", scaled.Name, len(instances))
+	return nil
+}
+
+func runAppHistory(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, history, err := fetchHistory(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	return printOutput(history, func(out io.Writer) error {
+		if len(history) == 0 {
+			fmt.Fprintln(out, "No deployments recorded")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "REVISION\tIMAGE\tACTOR\tDEPLOYED\tNOTE")
+		for i := range history {
+			d := &history[i]
+			var notes []string
+			if d.Revision == app.Revision() {
+				notes = append(notes, "current")
+			}
+			if d.RollbackOf != "" {
+				notes = append(notes, "rollback of "+d.RollbackOf)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Revision, d.Image, d.Actor, formatCreatedTime(d.CreatedAt), strings.Join(notes, ", "))
+		}
+		return w.Flush()
+	})
+}
+
+func runAppRollback(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, history, err := fetchHistory(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	var revision string
+	if len(args) > 1 {
+		revision = args[1]
+	} else {
+		// The newest deployment other than the current revision
+		for i := range history {
+			if history[i].Revision != app.Revision() {
+				revision = history[i].Revision
+				break
+			}
+		}
+		if revision == "" {
+			return errors.NewConflictStateError("application", app.Name, "no earlier deployment to roll back to")
+		}
+	}
+
+	restored, err := client.RollbackApplication(ctx, app.ID, revision)
+	if err != nil {
+		return fmt.Errorf("failed to roll back application: %w", err)
+	}
+
+	fmt.Printf("Application %s rolled back to revision %s (image %s)\n", restored.Name, revision, restored.Image)
+	if appRollbackNoWait {
+		return nil
+	}
+
+	instances, err := waitForReplicas(ctx, client, restored, appRollbackTimeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Application %s is running %d replica(s)\n", restored.Name, len(instances))
+	return nil
+}
+
+func runAppExport(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", args[0])
+	}
+
+	if appExportFile == "" {
+		if _, err := client.ExportApplication(ctx, app.ID, os.Stdout); err != nil {
+			return fmt.Errorf("failed to export application: %w", err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.ExportApplication(ctx, app.ID, &buf); err != nil {
+		return fmt.Errorf("failed to export application: %w", err)
+	}
+	if err := os.WriteFile(appExportFile, buf.Bytes(), 0o644); err != nil { //nolint:gosec // manifests are meant to be shared
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", appExportFile)
+	return nil
+}
+
+// fetchHistory returns the application with the given name and its
+// deployment history, newest first
+func fetchHistory(ctx context.Context, client *apiClient, name string) (*core.Application, []core.Deployment, error) {
+	app, err := findApplicationByName(ctx, client, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return nil, nil, errors.NewNotFoundError("application", name)
+	}
+
+	history, err := client.ListDeployments(ctx, app.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	return app, history, nil
+}
+
+// waitForReplicas polls the API until the application runs its desired
+// number of up-to-date replicas, printing each replica's status as it changes
+func waitForReplicas(ctx context.Context, client *apiClient, app *core.Application, timeout time.Duration) ([]core.Instance, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(deployPollInterval)
+	defer ticker.Stop()
+
+	want := app.DesiredReplicas()
+	seen := make(map[string]string) // container name -> last printed status
+	for {
+		apps, err := client.ListApplications(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checking replica status: %w", err)
+		}
+
+		idx := slices.IndexFunc(apps, func(a core.Application) bool { return a.ID == app.ID })
+		if idx < 0 {
+			return nil, fmt.Errorf("application %s was removed while scaling", app.Name)
+		}
+
+		instances := apps[idx].Instances
+		ready := 0
+		for _, inst := range instances {
+			status := inst.Status
+			if inst.Revision != "" && inst.Revision != app.Revision() {
+				status = "outdated"
+			}
+			if seen[inst.Name] != status {
+				fmt.Printf("  replica %d (%s): %s\n", inst.Replica+1, inst.Name, status)
+				seen[inst.Name] = status
+			}
+			if inst.Replica < want && isRunningStatus(status) {
+				ready++
+			}
+		}
+		for name := range seen {
+			if !slices.ContainsFunc(instances, func(i core.Instance) bool { return i.Name == name }) {
+				fmt.Printf("  %s: removed\n", name)
+				delete(seen, name)
+			}
+		}
+
+		if ready == want && len(instances) == want {
+			return instances, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for replicas (%d of %d running)", timeout, ready, want)
+		case <-ticker.C:
+		}
+	}
+}
+
+// isRunningStatus reports whether an engine status describes a running container
+func isRunningStatus(status string) bool {
+	return status == "running" || strings.HasPrefix(status, "Up")
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply resources from a manifest file",
+	Long: `Create or update teams, projects, environments and applications
+declared in a YAML manifest. Resources are matched by ID, or by name when
+no ID is given, and sent to the Simplify server in dependency order.`,
+	Example: `  simplify apply -f app.yaml
+  simplify apply -f manifests/`,
+	RunE: runApply,
+}
+
+var applyFile string
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "Manifest file or directory (required)")
+	_ = applyCmd.MarkFlagRequired("file") //nolint:errcheck // flag registration rarely fails
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	m, err := manifest.Load(applyFile)
+	if err != nil {
+		return err
+	}
+	if m.IsEmpty() {
+		return fmt.Errorf("no resources found in %s", applyFile)
+	}
+
+	logger.DebugCtx(ctx, "Applying manifest",
+		"file", applyFile,
+		"teams", len(m.Teams),
+		"projects", len(m.Projects),
+		"environments", len(m.Environments),
+		"applications", len(m.Applications),
+	)
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	return applyManifest(ctx, client, m)
+}
+
+// applyManifest upserts every resource in the manifest through the API
+func applyManifest(ctx context.Context, client *apiClient, m *manifest.Manifest) error {
+	for i := range m.Teams {
+		t := &m.Teams[i]
+		if err := upsertResource(ctx, client, "team", "/teams", &t.ID, t.Name, t); err != nil {
+			return err
+		}
+	}
+	for i := range m.Projects {
+		p := &m.Projects[i]
+		if err := upsertResource(ctx, client, "project", "/projects", &p.ID, p.Name, p); err != nil {
+			return err
+		}
+	}
+	for i := range m.Environments {
+		e := &m.Environments[i]
+		if err := upsertResource(ctx, client, "environment", "/environments", &e.ID, e.Name, e); err != nil {
+			return err
+		}
+	}
+	for i := range m.Applications {
+		a := &m.Applications[i]
+		if err := upsertResource(ctx, client, "application", "/applications", &a.ID, a.Name, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// namedResource is the subset of fields used to match existing resources
+type namedResource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// upsertResource updates the resource matching id (or name when id is empty),
+// creating it when no match exists. The resolved ID is written back to id.
+func upsertResource(ctx context.Context, client *apiClient, kind, path string, id *string, name string, body any) error {
+	var existing []namedResource
+	if err := client.Do(ctx, http.MethodGet, path, nil, &existing); err != nil {
+		return fmt.Errorf("listing %ss: %w", kind, err)
+	}
+
+	for _, r := range existing {
+		if (*id != "" && r.ID == *id) || (*id == "" && r.Name == name) {
+			*id = r.ID
+			if err := client.Do(ctx, http.MethodPut, path+"/"+r.ID, body, nil); err != nil {
+				return fmt.Errorf("updating %s %q: %w", kind, name, err)
+			}
+			fmt.Printf("%s/%s configured\n", kind, name)
+			return nil
+		}
+	}
+
+	var created namedResource
+	if err := client.Do(ctx, http.MethodPost, path, body, &created); err != nil {
+		return fmt.Errorf("creating %s %q: %w", kind, name, err)
+	}
+	*id = created.ID
+	fmt.Printf("%s/%s created\n", kind, name)
+	return nil
+}
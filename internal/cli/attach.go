@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var appAttachCmd = &cobra.Command{
+	Use:   "attach NAME",
+	Short: "Attach to the main process of an application",
+	Long: `Connect the terminal to the main process of one replica of an
+application through the server, for interactive images such as REPLs and
+game servers.
+
+Input is only passed on to applications deployed with --interactive; others
+just stream their output. Press Ctrl+C to detach.`,
+	Example: `  simplify app attach repl
+  simplify app attach game --replica 1
+  simplify app attach web --no-stdin`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppAttach,
+}
+
+var (
+	attachReplica int
+	attachNoStdin bool
+)
+
+func init() {
+	appCmd.AddCommand(appAttachCmd)
+
+	appAttachCmd.Flags().IntVar(&attachReplica, "replica", 0, "Replica to attach to, counting from 0")
+	appAttachCmd.Flags().BoolVar(&attachNoStdin, "no-stdin", false, "Only show output, without sending input")
+}
+
+func runAppAttach(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", args[0])
+	}
+
+	var stdin io.Reader
+	if !attachNoStdin && app.Interactive {
+		stdin = os.Stdin
+	}
+	err = client.Attach(ctx, app.ID, attachReplica, stdin, os.Stdout, os.Stderr)
+	if ctx.Err() != nil {
+		// Detached with Ctrl+C
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to attach: %w", err)
+	}
+	return nil
+}
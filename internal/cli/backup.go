@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore the Simplify database",
+	Long: `Create and restore snapshots of the Simplify database.
+
+When the current context points at a remote server (or --server is given),
+the server's backup API is used. Otherwise the local database is opened
+directly, falling back to the local server's API when the server is running
+and holds the database lock.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:     "create [file]",
+	Short:   "Write a snapshot of the database to a file",
+	Example: `  simplify backup create simplify-backup.db`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runBackupCreate,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore [file]",
+	Short: "Replace the database contents with a snapshot",
+	Long: `Verify a snapshot and replace all teams, projects, environments,
+applications, pods and networks with its contents. The reconciler then
+converges running containers to the restored state.
+
+With --stored, the argument names a backup in the server's backup target,
+as listed by 'simplify backup list'. Add --volumes to also extract its
+volume archives into the volumes; stop the applications using them first.`,
+	Example: `  simplify backup restore simplify-backup.db --yes
+  simplify backup restore simplify-20260301-030000 --stored --volumes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the backups in the server's backup target",
+	Long: `List the backups the server stored in its backup target (backup.dir or
+backup.s3 in the server config), newest first.`,
+	Example: `  simplify backup list`,
+	Args:    cobra.NoArgs,
+	RunE:    runBackupList,
+}
+
+var backupRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Back up to the server's backup target now",
+	Long: `Back up the database and the configured volumes to the server's backup
+target now, as a scheduled backup would, and prune the oldest backups.`,
+	Example: `  simplify backup run`,
+	Args:    cobra.NoArgs,
+	RunE:    runBackupRun,
+}
+
+var (
+	restoreStored  bool
+	restoreVolumes bool
+)
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRunCmd)
+
+	backupRestoreCmd.Flags().BoolVar(&restoreStored, "stored", false, "Restore a backup from the server's backup target by name")
+	backupRestoreCmd.Flags().BoolVar(&restoreVolumes, "volumes", false, "With --stored, also restore the backup's volumes")
+}
+
+// openLocalStore opens the local database unless a remote server is targeted.
+// It returns nil when the API should be used instead.
+func openLocalStore(ctx context.Context) (*store.Store, error) {
+	active, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+	if serverURL != "" || active.Server != "" {
+		return nil, nil
+	}
+
+	s, err := store.New(config.Get().Database.Path)
+	if err != nil {
+		logger.DebugCtx(ctx, "Local database unavailable, using the server API", "error", err)
+		return nil, nil
+	}
+	return s, nil
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	path := args[0]
+
+	// Write next to the destination and rename, so a failed backup never leaves a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".simplify-backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeBackup(ctx, tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	summary, err := store.VerifyBackup(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	fmt.Printf("Backup written to %s (%s)\n", path, formatBytes(summary.Size))
+	printBackupSummary(summary)
+	return nil
+}
+
+// writeBackup writes a snapshot from the local database or the server to w
+func writeBackup(ctx context.Context, w io.Writer) error {
+	s, err := openLocalStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s != nil {
+		defer s.Close()
+		if _, err := s.Backup(w); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	if _, err := client.DownloadBackup(ctx, w); err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	return nil
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	path := args[0]
+
+	if restoreStored {
+		return restoreStoredBackup(ctx, args[0])
+	}
+	if restoreVolumes {
+		return fmt.Errorf("--volumes requires --stored: snapshot files only hold the database")
+	}
+
+	summary, err := store.VerifyBackup(path)
+	if err != nil {
+		return fmt.Errorf("snapshot failed verification: %w", err)
+	}
+
+	fmt.Printf("Snapshot %s is valid:\n", path)
+	printBackupSummary(summary)
+
+	ok, err := confirm("Replace all current data with this snapshot?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	s, err := openLocalStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s != nil {
+		defer s.Close()
+		if _, err := s.Restore(path); err != nil {
+			return err
+		}
+	} else {
+		data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user on purpose
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		client, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+		if _, err := client.RestoreBackup(ctx, data); err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+	}
+
+	fmt.Println("Backup restored")
+	return nil
+}
+
+// printBackupSummary lists the record count of each bucket in a snapshot
+func printBackupSummary(summary *store.BackupSummary) {
+	buckets := make([]string, 0, len(summary.Records))
+	for b := range summary.Records {
+		buckets = append(buckets, b)
+	}
+	sort.Strings(buckets)
+
+	for _, b := range buckets {
+		fmt.Printf("  %-14s %d\n", b+":", summary.Records[b])
+	}
+}
+
+// restoreStoredBackup restores a backup from the server's backup target
+func restoreStoredBackup(ctx context.Context, name string) error {
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	what := "the database"
+	if restoreVolumes {
+		what = "the database and volumes"
+	}
+	ok, err := confirm(fmt.Sprintf("Replace %s with backup %s?", what, name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	b, err := client.RestoreStoredBackup(ctx, name, restoreVolumes)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	fmt.Printf("Backup %s restored\n", b.Name)
+	if restoreVolumes && len(b.Volumes) > 0 {
+		fmt.Printf("Volumes restored: %s\n", strings.Join(b.Volumes, ", "))
+	}
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	backups, err := client.ListBackups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	return printOutput(backups, func(out io.Writer) error {
+		if len(backups) == 0 {
+			fmt.Fprintln(out, "No backups found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSIZE\tVOLUMES\tCREATED")
+		for i := range backups {
+			b := &backups[i]
+			volumes := strings.Join(b.Volumes, ",")
+			if volumes == "" {
+				volumes = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Name, formatBytes(b.Size), volumes, formatCreatedTime(b.CreatedAt))
+		}
+		return w.Flush()
+	})
+}
+
+func runBackupRun(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	b, err := client.CreateBackup(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to back up: %w", err)
+	}
+
+	fmt.Printf("Backup %s created (%s)\n", b.Name, formatBytes(b.Size))
+	if len(b.Volumes) > 0 {
+		fmt.Printf("Volumes: %s\n", strings.Join(b.Volumes, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var appBuildCmd = &cobra.Command{
+	Use:   "build NAME",
+	Short: "Build an application's image from its project repository",
+	Long: `Clone the repository of the application's project, build an image from
+it and update the application to that image.
+
+Repositories with a Containerfile or Dockerfile are built with Podman;
+others are built with Cloud Native Buildpacks. Use --strategy to choose.
+The build runs on the server; its output is streamed until it finishes.`,
+	Example: `  simplify app build web
+  simplify app build web --ref v1.4.0
+  simplify app build api --context services/api --containerfile Containerfile.prod
+  simplify app build web --strategy buildpacks --no-wait`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppBuild,
+}
+
+var appBuildsCmd = &cobra.Command{
+	Use:               "builds NAME",
+	Short:             "List the builds of an application",
+	Example:           `  simplify app builds web`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppBuilds,
+}
+
+var (
+	buildRef           string
+	buildStrategy      string
+	buildContainerfile string
+	buildContext       string
+	buildTimeout       time.Duration
+	buildNoWait        bool
+)
+
+func init() {
+	appCmd.AddCommand(appBuildCmd)
+	appCmd.AddCommand(appBuildsCmd)
+
+	appBuildCmd.Flags().StringVar(&buildRef, "ref", "", "Branch or tag to build (default: the default branch)")
+	appBuildCmd.Flags().StringVar(&buildStrategy, "strategy", "", "Build with containerfile or buildpacks (default: containerfile when the repository has one)")
+	appBuildCmd.Flags().StringVar(&buildContainerfile, "containerfile", "", "Containerfile to build, relative to --context")
+	appBuildCmd.Flags().StringVar(&buildContext, "context", "", "Directory of the repository to build (default: the root)")
+	appBuildCmd.Flags().DurationVar(&buildTimeout, "timeout", 30*time.Minute, "How long to wait for the build")
+	appBuildCmd.Flags().BoolVar(&buildNoWait, "no-wait", false, "Return once the build has started")
+}
+
+func runAppBuild(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", args[0])
+	}
+
+	req := server.BuildRequest{Ref: buildRef, Strategy: buildStrategy, Containerfile: buildContainerfile, Context: buildContext}
+	b, err := client.CreateBuild(ctx, app.ID, &req)
+	if err != nil {
+		return fmt.Errorf("failed to start build: %w", err)
+	}
+
+	fmt.Printf("Build %s of %s started (%s)\n", b.ID, app.Name, b.RepoURL)
+	if buildNoWait {
+		return nil
+	}
+
+	done, err := followBuild(ctx, client, app.ID, b.ID, buildTimeout)
+	if err != nil {
+		return err
+	}
+	if done.Status != core.BuildSucceeded {
+		return fmt.Errorf("build %s failed: %s", done.ID, done.Error)
+	}
+
+	fmt.Printf("Application %s updated to %s\n", app.Name, done.Image)
+	return nil
+}
+
+// followBuild prints a build's log as it grows until the build finishes
+func followBuild(ctx context.Context, client *apiClient, appID, buildID string, timeout time.Duration) (*core.Build, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(deployPollInterval)
+	defer ticker.Stop()
+
+	printed := 0
+	for {
+		b, err := client.GetBuild(ctx, appID, buildID)
+		if err != nil {
+			return nil, fmt.Errorf("checking build status: %w", err)
+		}
+
+		// The server keeps the end of long logs, which can shift them
+		if printed > len(b.Log) {
+			printed = 0
+		}
+		fmt.Print(b.Log[printed:])
+		printed = len(b.Log)
+
+		if b.Finished() {
+			return b, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for build %s (status: %s)", timeout, buildID, b.Status)
+		case <-ticker.C:
+		}
+	}
+}
+
+func runAppBuilds(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", args[0])
+	}
+
+	builds, err := client.ListBuilds(ctx, app.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list builds: %w", err)
+	}
+
+	return printOutput(builds, func(out io.Writer) error {
+		if len(builds) == 0 {
+			fmt.Fprintln(out, "No builds found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tSTATUS\tREF\tCOMMIT\tSTRATEGY\tIMAGE\tCREATED")
+		for i := range builds {
+			b := &builds[i]
+			commit := b.Commit
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", b.ID, b.Status, b.Ref, commit, b.Strategy, b.Image, formatCreatedTime(b.CreatedAt))
+		}
+		return w.Flush()
+	})
+}
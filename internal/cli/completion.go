@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for the given shell.
+
+Bash:
+  source <(simplify completion bash)
+  # To load for every session (Linux):
+  simplify completion bash > /etc/bash_completion.d/simplify
+
+Zsh:
+  simplify completion zsh > "${fpath[1]}/_simplify"
+
+Fish:
+  simplify completion fish > ~/.config/fish/completions/simplify.fish
+
+PowerShell:
+  simplify completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+// completionTimeout bounds how long dynamic completion waits for the container engine
+const completionTimeout = 3 * time.Second
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		return rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// completeNames builds a ValidArgsFunction that completes names returned by list.
+// When maxArgs is positive, no further completions are offered once it is reached.
+func completeNames(maxArgs int, list func(ctx context.Context, client *container.Client) ([]string, error)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		client, err := newEngineClient(ctx)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names, err := list(ctx, client)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		matches := make([]string, 0, len(names))
+		for _, name := range names {
+			if strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+				matches = append(matches, name)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// listContainerNames returns the names of all containers known to the engine
+func listContainerNames(ctx context.Context, client *container.Client) ([]string, error) {
+	containers, err := client.List(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(containers))
+	for i := range containers {
+		names = append(names, containers[i].Name)
+	}
+	return names, nil
+}
+
+// listPodNames returns the names of all pods known to the engine
+func listPodNames(ctx context.Context, client *container.Client) ([]string, error) {
+	pods, err := client.ListPods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// listNetworkNames returns the names of all networks known to the engine
+func listNetworkNames(ctx context.Context, client *container.Client) ([]string, error) {
+	networks, err := client.ListNetworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(networks))
+	for _, n := range networks {
+		names = append(names, n.Name)
+	}
+	return names, nil
+}
+
+// completeSecretNames completes the names of secrets stored by the server.
+// Secrets live in Simplify's database rather than the engine, so they are
+// listed through the API.
+func completeSecretNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	client, err := newAPIClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	secrets, err := client.ListSecrets(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	matches := make([]string, 0, len(secrets))
+	for i := range secrets {
+		if name := secrets[i].Name; strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
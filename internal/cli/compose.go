@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/AkMo3/simplify/internal/compose"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Run Docker Compose files on Simplify",
+	Long: `Translate a Docker Compose file into Simplify applications and networks.
+
+Services become applications named <project>-<service> and labeled with
+the project. Each keeps its service name as a network alias, so services
+reach each other by service name. Applications of other projects, or
+created outside Compose, are never updated or deleted.
+
+Networks are created as <project>-<network>, and services without networks
+join <project>-default. Named volumes are created as <project>-<volume>,
+external ones are used by their own name, and bind mounts are resolved
+against the Compose file's directory. Builds, anonymous volumes and
+additional networks per service are not supported and produce warnings.`,
+}
+
+var composeUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Create or update the applications of a Compose file",
+	Example: `  simplify compose up
+  simplify compose up -f docker-compose.prod.yml -p shop`,
+	Args: cobra.NoArgs,
+	RunE: runComposeUp,
+}
+
+var composeDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Delete the applications and networks of a Compose file",
+	Long: `Delete the applications and networks defined by a Compose file.
+Only applications of the project are deleted. Volumes and their data are kept; delete them with 'simplify volume rm'.
+
+The reconciler removes the containers shortly after. Engine networks that
+were still in use at that moment can be cleaned up with 'simplify system prune'.`,
+	Args: cobra.NoArgs,
+	RunE: runComposeDown,
+}
+
+var (
+	composeFile    string
+	composeProject string
+)
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+	composeCmd.AddCommand(composeUpCmd)
+	composeCmd.AddCommand(composeDownCmd)
+
+	composeCmd.PersistentFlags().StringVarP(&composeFile, "file", "f", "docker-compose.yml", "Compose file")
+	composeCmd.PersistentFlags().StringVarP(&composeProject, "project-name", "p", "", "Project name (defaults to the compose file's directory name)")
+}
+
+// loadComposePlan reads the compose file and translates it for the selected project
+func loadComposePlan() (*compose.Plan, error) {
+	f, err := compose.Load(composeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	project := composeProject
+	if project == "" {
+		abs, err := filepath.Abs(composeFile)
+		if err != nil {
+			return nil, fmt.Errorf("resolving compose file path: %w", err)
+		}
+		project = filepath.Base(filepath.Dir(abs))
+	}
+
+	plan, err := f.Translate(project)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range plan.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	return plan, nil
+}
+
+func runComposeUp(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	plan, err := loadComposePlan()
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return fmt.Errorf("listing applications: %w", err)
+	}
+	if err := plan.Claim(apps); err != nil {
+		return err
+	}
+
+	networkIDs, err := ensureNetworks(ctx, client, plan.Networks)
+	if err != nil {
+		return err
+	}
+	if err := ensureVolumes(ctx, client, plan.Volumes); err != nil {
+		return err
+	}
+
+	for i := range plan.Applications {
+		app := &plan.Applications[i]
+		app.NetworkID = networkIDs[plan.AppNetworks[app.Name]]
+		if err := upsertResource(ctx, client, "application", "/applications", &app.ID, app.Name, app); err != nil {
+			return err
+		}
+	}
+	return ensureDependencies(ctx, client, plan)
+}
+
+// ensureDependencies creates the depends_on links of the plan that do not
+// exist yet
+func ensureDependencies(ctx context.Context, client *apiClient, plan *compose.Plan) error {
+	if len(plan.DependsOn) == 0 {
+		return nil
+	}
+	existing, err := client.ListLinks(ctx, "")
+	if err != nil {
+		return fmt.Errorf("listing links: %w", err)
+	}
+
+	ids := make(map[string]string, len(plan.Applications))
+	for i := range plan.Applications {
+		ids[plan.Applications[i].Name] = plan.Applications[i].ID
+	}
+	for i := range plan.Applications {
+		from := &plan.Applications[i]
+		for _, dep := range plan.DependsOn[from.Name] {
+			link := core.Link{From: from.ID, To: ids[dep], Type: core.LinkDependsOn}
+			if slices.ContainsFunc(existing, func(l core.Link) bool {
+				return l.From == link.From && l.To == link.To && l.Type == link.Type
+			}) {
+				continue
+			}
+			if _, err := client.CreateLink(ctx, &link); err != nil {
+				return fmt.Errorf("linking %q to %q: %w", from.Name, dep, err)
+			}
+			fmt.Printf("link/%s depends_on %s created\n", from.Name, dep)
+		}
+	}
+	return nil
+}
+
+// ensureNetworks creates the networks that do not exist yet and returns the IDs of all of them by name
+func ensureNetworks(ctx context.Context, client *apiClient, networks []core.Network) (map[string]string, error) {
+	existing, err := client.ListNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing networks: %w", err)
+	}
+
+	ids := make(map[string]string, len(existing))
+	for i := range existing {
+		ids[existing[i].Name] = existing[i].ID
+	}
+
+	for i := range networks {
+		n := &networks[i]
+		if _, ok := ids[n.Name]; ok {
+			continue
+		}
+
+		created, err := client.CreateNetwork(ctx, n)
+		if err != nil {
+			return nil, fmt.Errorf("creating network %q: %w", n.Name, err)
+		}
+		ids[n.Name] = created.ID
+		fmt.Printf("network/%s created\n", n.Name)
+	}
+	return ids, nil
+}
+
+// ensureVolumes creates the volumes that do not exist yet
+func ensureVolumes(ctx context.Context, client *apiClient, volumes []core.Volume) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+	existing, err := client.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("listing volumes: %w", err)
+	}
+
+	for i := range volumes {
+		v := &volumes[i]
+		if slices.ContainsFunc(existing, func(e core.Volume) bool { return e.Name == v.Name }) {
+			continue
+		}
+		if _, err := client.CreateVolume(ctx, v); err != nil {
+			return fmt.Errorf("creating volume %q: %w", v.Name, err)
+		}
+		fmt.Printf("volume/%s created\n", v.Name)
+	}
+	return nil
+}
+
+func runComposeDown(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	plan, err := loadComposePlan()
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return fmt.Errorf("listing applications: %w", err)
+	}
+
+	owned := plan.Owned(apps)
+	for i := range owned {
+		if err := client.DeleteApplication(ctx, owned[i].ID); err != nil {
+			return fmt.Errorf("deleting application %q: %w", owned[i].Name, err)
+		}
+		fmt.Printf("application/%s deleted\n", owned[i].Name)
+	}
+
+	networks, err := client.ListNetworks(ctx)
+	if err != nil {
+		return fmt.Errorf("listing networks: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(plan.Networks))
+	for i := range plan.Networks {
+		wanted[plan.Networks[i].Name] = true
+	}
+
+	for i := range networks {
+		if !wanted[networks[i].Name] {
+			continue
+		}
+		if err := client.DeleteNetwork(ctx, networks[i].ID); err != nil {
+			return fmt.Errorf("deleting network %q: %w", networks[i].Name, err)
+		}
+		fmt.Printf("network/%s deleted\n", networks[i].Name)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and edit the Simplify configuration",
+	Long: `View and edit the server configuration file (see --config).
+
+Values are validated before they are written, so a bad edit is rejected
+here instead of failing at server startup. These commands work even when
+the current file is invalid, so they can be used to repair it.`,
+	// Skip the root hook, which refuses to run with an invalid config
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFlags(); err != nil {
+			return err
+		}
+		return initLogger(cmd)
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the effective configuration",
+	Long: `Print the effective configuration: the config file merged with
+its config.d overlays, the selected profile, defaults and SIMPLIFY_*
+environment overrides.`,
+	Args: cobra.NoArgs,
+	RunE: viewConfig,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get [key]",
+	Short:             "Print a single configuration value",
+	Example:           `  simplify config get server.port`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	RunE:              getConfigValue,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a configuration value",
+	Long: `Set a configuration value in the config file. Comments and other
+settings are preserved. Restart the server for the change to take effect.
+
+Only the base file is edited; overlays and profile files still win.`,
+	Example: `  simplify config set server.port 9090
+  simplify config set env production`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	RunE:              setConfigValue,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for errors",
+	Args:  cobra.NoArgs,
+	RunE:  validateConfigFile,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func viewConfig(cmd *cobra.Command, args []string) error {
+	if configErr != nil {
+		return fmt.Errorf("failed to load config: %w", configErr)
+	}
+
+	settings := config.Settings()
+	return printOutput(settings, func(w io.Writer) error {
+		return writeYAML(w, settings)
+	})
+}
+
+func getConfigValue(cmd *cobra.Command, args []string) error {
+	if configErr != nil {
+		return fmt.Errorf("failed to load config: %w", configErr)
+	}
+
+	value, err := config.Value(args[0])
+	if err != nil {
+		return err
+	}
+	return printOutput(value, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, value)
+		return err
+	})
+}
+
+func setConfigValue(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	if err := config.Set(cfgFile, key, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %s = %s in %s\n", key, value, cfgFile)
+	fmt.Println("Restart the server to apply the change")
+	return nil
+}
+
+func validateConfigFile(cmd *cobra.Command, args []string) error {
+	if _, err := config.ValidateFile(cfgFile); err != nil {
+		return fmt.Errorf("%s: %w", cfgFile, err)
+	}
+
+	fmt.Printf("%s is valid\n", cfgFile)
+	return nil
+}
+
+// completeConfigKeys completes the key argument of config get and set
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return config.Keys(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/clientconfig"
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage server contexts",
+	Long: `Manage named contexts that tell the CLI which Simplify server and
+Podman socket to talk to.
+
+Contexts are stored in ~/.config/simplify/contexts.yaml. The built-in
+"local" context targets the local server and Podman socket.`,
+}
+
+var contextAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add or update a context",
+	Example: `  simplify context add prod --server https://simplify.example.com --token s3cr3t
+  simplify context add edge --socket ssh://core@edge-1/run/podman/podman.sock`,
+	Args: cobra.ExactArgs(1),
+	RunE: addContext,
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:               "use [name]",
+	Short:             "Switch the current context",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextNames,
+	RunE:              useContext,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List contexts",
+	RunE:    listContexts,
+}
+
+var contextRmCmd = &cobra.Command{
+	Use:               "rm [name]",
+	Short:             "Remove a context",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeContextNames,
+	RunE:              removeContext,
+}
+
+var (
+	contextServer string
+	contextToken  string
+	contextSocket string
+)
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextAddCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextRmCmd)
+
+	contextAddCmd.Flags().StringVar(&contextServer, "server", "", "Simplify API server URL")
+	contextAddCmd.Flags().StringVar(&contextToken, "token", "", "API token for the server")
+	contextAddCmd.Flags().StringVar(&contextSocket, "socket", "", "Podman socket URI for engine commands")
+}
+
+// contextView is the listing representation of a context. Tokens are never printed.
+type contextView struct {
+	Name    string `json:"name"`
+	Server  string `json:"server,omitempty"`
+	Socket  string `json:"socket,omitempty"`
+	Current bool   `json:"current"`
+	HasAuth bool   `json:"has_token"`
+}
+
+func addContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if name == clientconfig.LocalContext {
+		return errors.NewInvalidInputErrorWithField("name", "the local context is built in and cannot be redefined")
+	}
+	if contextServer == "" && contextSocket == "" {
+		return fmt.Errorf("at least one of --server or --socket is required")
+	}
+	if contextServer != "" {
+		u, err := url.Parse(contextServer)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.NewInvalidInputErrorWithField("server", "server must be an http(s) URL")
+		}
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Set(clientconfig.Context{
+		Name:   name,
+		Server: strings.TrimSuffix(contextServer, "/"),
+		Token:  contextToken,
+		Socket: contextSocket,
+	}); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Context %s saved\n", name)
+	return nil
+}
+
+func useContext(cmd *cobra.Command, args []string) error {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Use(args[0]); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to context %s\n", args[0])
+	return nil
+}
+
+func listContexts(cmd *cobra.Command, args []string) error {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	current := cfg.CurrentName()
+	local, err := cfg.Get(clientconfig.LocalContext)
+	if err != nil {
+		return err
+	}
+
+	contexts := []clientconfig.Context{*local}
+	for _, c := range cfg.Contexts {
+		if c.Name != clientconfig.LocalContext {
+			contexts = append(contexts, c)
+		}
+	}
+
+	views := make([]contextView, 0, len(contexts))
+	for _, c := range contexts {
+		views = append(views, contextView{
+			Name:    c.Name,
+			Server:  c.Server,
+			Socket:  c.Socket,
+			Current: c.Name == current,
+			HasAuth: c.Token != "",
+		})
+	}
+
+	return printOutput(views, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tSOCKET")
+		for _, v := range views {
+			marker := ""
+			if v.Current {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, v.Name, v.Server, v.Socket)
+		}
+		return w.Flush()
+	})
+}
+
+func removeContext(cmd *cobra.Command, args []string) error {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Remove(args[0]); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Context %s removed\n", args[0])
+	return nil
+}
+
+// completeContextNames completes the names of configured contexts
+func completeContextNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := make([]string, 0, len(cfg.Contexts)+1)
+	if strings.HasPrefix(clientconfig.LocalContext, toComplete) {
+		names = append(names, clientconfig.LocalContext)
+	}
+	for _, c := range cfg.Contexts {
+		if c.Name != clientconfig.LocalContext && strings.HasPrefix(c.Name, toComplete) {
+			names = append(names, c.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// loadClientConfig reads the CLI's client configuration file
+func loadClientConfig() (*clientconfig.File, error) {
+	path, err := clientconfig.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return clientconfig.Load(path)
+}
+
+// activeContext returns the context selected by --context or the current context
+func activeContext() (*clientconfig.Context, error) {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Active(contextOverride)
+}
+
+// newEngineClient returns the Podman client of the active context's socket,
+// or of the default socket, shared by everything the command does
+func newEngineClient(ctx context.Context) (*container.Client, error) {
+	active, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+	return container.Shared(ctx, active.Socket)
+}
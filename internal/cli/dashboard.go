@@ -0,0 +1,388 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Interactive terminal dashboard",
+	Long: `Show the applications of the Simplify server, their status and ports,
+and the containers of each beneath it in a live terminal view.
+
+Keys:
+  up/down, k/j   select a container
+  l              toggle the log view for the selected container
+  r              restart the selected container
+  s              stop the selected container
+  a              toggle showing stopped containers
+  q, ctrl+c      quit`,
+	RunE: runDashboard,
+}
+
+var dashboardRefresh time.Duration
+
+// dashboardLogLines is the number of log lines shown in the log view
+const dashboardLogLines = 20
+
+// ANSI escape sequences used by the dashboard
+const (
+	ansiAltScreenOn  = "\x1b[?1049h"
+	ansiAltScreenOff = "\x1b[?1049l"
+	ansiHideCursor   = "\x1b[?25l"
+	ansiShowCursor   = "\x1b[?25h"
+	ansiClear        = "\x1b[H\x1b[2J"
+	ansiReverse      = "\x1b[7m"
+	ansiBold         = "\x1b[1m"
+	ansiDim          = "\x1b[2m"
+	ansiReset        = "\x1b[0m"
+)
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+
+	dashboardCmd.Flags().DurationVar(&dashboardRefresh, "refresh", 2*time.Second, "Refresh interval")
+}
+
+// dashboard holds the state of the interactive view
+type dashboard struct {
+	client     *container.Client
+	api        *apiClient
+	status     string
+	rows       []dashboardRow
+	containers []container.ContainerInfo // in the order they are listed
+	logs       []string
+	selected   int // index in containers
+	showAll    bool
+	showLogs   bool
+}
+
+// dashboardRow is one line of the list: an application, or one of its
+// containers when container is set
+type dashboardRow struct {
+	app       *core.Application
+	container int // index in containers, -1 on the application's line
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	fd := int(os.Stdin.Fd()) //nolint:gosec // file descriptors fit in int
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("dashboard requires an interactive terminal")
+	}
+
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Podman: %w", err)
+	}
+	api, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	fmt.Print(ansiAltScreenOn + ansiHideCursor)
+	defer func() {
+		fmt.Print(ansiShowCursor + ansiAltScreenOff)
+		_ = term.Restore(fd, oldState) //nolint:errcheck // best effort terminal restore
+	}()
+	// Log lines would be drawn over the view
+	defer logger.Silence()()
+
+	keys := make(chan byte, 16)
+	go readKeys(keys)
+
+	d := &dashboard{client: client, api: api, showAll: true}
+	d.refresh(ctx)
+	d.render()
+
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.refresh(ctx)
+		case key, ok := <-keys:
+			if !ok || !d.handleKey(ctx, key, keys) {
+				return nil
+			}
+		}
+		d.render()
+	}
+}
+
+// readKeys forwards raw bytes from stdin until it is closed
+func readKeys(keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil || n == 0 {
+			close(keys)
+			return
+		}
+		keys <- buf[0]
+	}
+}
+
+// handleKey applies a key press and reports whether the dashboard should keep running
+func (d *dashboard) handleKey(ctx context.Context, key byte, keys <-chan byte) bool {
+	switch key {
+	case 'q', 3: // 3 = ctrl+c in raw mode
+		return false
+	case 'k':
+		d.move(-1)
+	case 'j':
+		d.move(1)
+	case 0x1b: // escape sequence, arrows are ESC [ A / ESC [ B
+		if next := readEscape(keys); next == 'A' {
+			d.move(-1)
+		} else if next == 'B' {
+			d.move(1)
+		}
+	case 'a':
+		d.showAll = !d.showAll
+		d.refresh(ctx)
+	case 'l':
+		d.showLogs = !d.showLogs
+		d.refreshLogs(ctx)
+	case 'r':
+		d.act(ctx, "Restarted", d.client.Restart)
+	case 's':
+		d.act(ctx, "Stopped", func(ctx context.Context, name string) error {
+			return d.client.Stop(ctx, name, nil)
+		})
+	}
+	return true
+}
+
+// readEscape reads the final byte of a CSI escape sequence, if one follows promptly
+func readEscape(keys <-chan byte) byte {
+	timeout := time.After(50 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		select {
+		case b := <-keys:
+			if b != '[' {
+				return b
+			}
+		case <-timeout:
+			return 0
+		}
+	}
+	return 0
+}
+
+// move changes the selection by delta, staying within bounds
+func (d *dashboard) move(delta int) {
+	d.selected += delta
+	if d.selected >= len(d.containers) {
+		d.selected = len(d.containers) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+	d.logs = nil
+}
+
+// current returns the selected container, if any
+func (d *dashboard) current() *container.ContainerInfo {
+	if d.selected < 0 || d.selected >= len(d.containers) {
+		return nil
+	}
+	return &d.containers[d.selected]
+}
+
+// act runs an operation against the selected container and records the outcome
+func (d *dashboard) act(ctx context.Context, verb string, op func(ctx context.Context, name string) error) {
+	c := d.current()
+	if c == nil {
+		return
+	}
+
+	d.status = fmt.Sprintf("Working on %s...", c.Name)
+	d.render()
+
+	if err := op(ctx, c.Name); err != nil {
+		d.status = fmt.Sprintf("Error: %v", err)
+	} else {
+		d.status = fmt.Sprintf("%s %s", verb, c.Name)
+	}
+	d.refresh(ctx)
+}
+
+// refresh reloads the applications and their containers (and logs, when
+// shown). Containers that belong to no application are not listed.
+func (d *dashboard) refresh(ctx context.Context) {
+	apps, err := d.api.ListApplications(ctx)
+	if err != nil {
+		d.status = fmt.Sprintf("Error listing applications: %v", err)
+		return
+	}
+	containers, err := d.client.List(ctx, d.showAll)
+	if err != nil {
+		d.status = fmt.Sprintf("Error listing containers: %v", err)
+		return
+	}
+
+	byApp := make(map[string][]container.ContainerInfo)
+	for i := range containers {
+		if id := containers[i].Labels["simplify.app.id"]; id != "" {
+			byApp[id] = append(byApp[id], containers[i])
+		}
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+
+	d.rows = d.rows[:0]
+	d.containers = d.containers[:0]
+	for i := range apps {
+		app := &apps[i]
+		d.rows = append(d.rows, dashboardRow{app: app, container: -1})
+		owned := byApp[app.ID]
+		sort.Slice(owned, func(i, j int) bool {
+			return owned[i].Name < owned[j].Name
+		})
+		for _, c := range owned {
+			d.rows = append(d.rows, dashboardRow{app: app, container: len(d.containers)})
+			d.containers = append(d.containers, c)
+		}
+	}
+	d.move(0)
+	d.refreshLogs(ctx)
+}
+
+// refreshLogs loads the last log lines of the selected container
+func (d *dashboard) refreshLogs(ctx context.Context) {
+	c := d.current()
+	if !d.showLogs || c == nil {
+		return
+	}
+
+	lines := make([]string, 0, dashboardLogLines)
+	opts := container.LogOptions{Tail: fmt.Sprintf("%d", dashboardLogLines)}
+	err := d.client.StreamLogs(ctx, c.Name, opts, func(line container.LogLine) error {
+		lines = append(lines, line.Text)
+		return nil
+	})
+	if err != nil {
+		d.status = fmt.Sprintf("Error reading logs: %v", err)
+		return
+	}
+	d.logs = lines
+}
+
+// render draws the whole screen
+func (d *dashboard) render() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd())) //nolint:gosec // file descriptors fit in int
+	if err != nil {
+		width, height = 120, 40
+	}
+
+	var b strings.Builder
+	b.WriteString(ansiClear)
+
+	scope := "running"
+	if d.showAll {
+		scope = "all"
+	}
+	writeLine(&b, width, fmt.Sprintf("%sSimplify dashboard%s  %d applications, %d containers (%s)  %s",
+		ansiBold, ansiReset, len(d.rows)-len(d.containers), len(d.containers), scope, time.Now().Format("15:04:05")))
+	writeLine(&b, width, "")
+	writeLine(&b, width, fmt.Sprintf("%s%-24s %-12s %-30s %s%s", ansiBold, "NAME", "STATUS", "IMAGE", "PORTS", ansiReset))
+
+	// Leave room for the header, footer and optional log pane
+	rows := height - 6
+	if d.showLogs {
+		rows -= dashboardLogLines + 2
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	// Scroll so the selected container's line is visible
+	selectedRow := 0
+	for i := range d.rows {
+		if d.rows[i].container == d.selected {
+			selectedRow = i
+		}
+	}
+	offset := 0
+	if selectedRow >= rows {
+		offset = selectedRow - rows + 1
+	}
+	for i := offset; i < len(d.rows) && i < offset+rows; i++ {
+		r := &d.rows[i]
+		if r.container < 0 {
+			a := r.app
+			writeLine(&b, width, fmt.Sprintf("%s%-24s %-12s %-30s %s%s",
+				ansiBold, truncateString(a.Name, 24), a.Status, truncateString(a.Image, 30), formatPortMap(a.Ports), ansiReset))
+			continue
+		}
+		c := &d.containers[r.container]
+		row := fmt.Sprintf("  %-22s %-12s %-30s %s",
+			truncateString(c.Name, 22), c.Status, truncateString(c.Image, 30), formatPortMap(c.Ports))
+		if r.container == d.selected {
+			row = ansiReverse + row + ansiReset
+		}
+		writeLine(&b, width, row)
+	}
+
+	if d.showLogs {
+		writeLine(&b, width, "")
+		name := ""
+		if c := d.current(); c != nil {
+			name = c.Name
+		}
+		writeLine(&b, width, fmt.Sprintf("%sLogs: %s%s", ansiBold, name, ansiReset))
+		for _, line := range d.logs {
+			writeLine(&b, width, line)
+		}
+	}
+
+	writeLine(&b, width, "")
+	writeLine(&b, width, ansiDim+"[j/k] select  [l] logs  [r] restart  [s] stop  [a] all/running  [q] quit"+ansiReset)
+	if d.status != "" {
+		writeLine(&b, width, d.status)
+	}
+
+	fmt.Print(b.String())
+}
+
+// writeLine appends a line clipped to the terminal width. Raw mode needs explicit CR.
+func writeLine(b *strings.Builder, width int, s string) {
+	if width > 0 && len(s) > width && !strings.Contains(s, "\x1b") {
+		s = s[:width]
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+// formatPortMap renders a container port map as "host->container" pairs
+func formatPortMap(ports map[string]string) string {
+	parts := make([]string, 0, len(ports))
+	for containerPort, host := range ports {
+		if host == "" {
+			parts = append(parts, containerPort)
+			continue
+		}
+		parts = append(parts, host+"->"+containerPort)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
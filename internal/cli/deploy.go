@@ -0,0 +1,397 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Create or update an application and wait for it to run",
+	Long: `Create or update an application on the Simplify server in one step.
+
+The application is matched by name. If it exists its image, ports and
+environment are updated, otherwise a new application is created. The
+command then waits until the reconciler reports the application as running.`,
+	Example: `  simplify deploy --name web --image nginx:latest --port 8080:80
+  simplify deploy --name api --image myapp:v2 --env DB_HOST=db --timeout 2m
+  simplify deploy --name api --image myapp:v2 --sensitive-env STRIPE_WEBHOOK=whsec_123
+  simplify deploy --name api --image myapp:v2 --secret db-password=DB_PASSWORD --secret tls-key=/run/secrets/tls.key
+  simplify deploy --name db --image postgres:17 --volume pgdata:/var/lib/postgresql/data
+  simplify deploy --name web --image nginx:latest --config-file /etc/nginx/conf.d/default.conf=./site.conf
+  simplify deploy --name worker --image myapp:v2 --node-selector region=eu
+  simplify deploy --name api --image myapp:v2 --cpus 0.5 --memory 512MiB`,
+	RunE: runDeploy,
+}
+
+var (
+	deployName        string
+	deployImage       string
+	deployPorts       []string
+	deployEnv         []string
+	deploySensitive   []string
+	deploySecrets     []string
+	deployVolumes     []string
+	deployConfigs     []string
+	deploySelector    []string
+	deployEnvironment string
+	deployMemory      string
+	deployCPUs        float64
+	deployTimeout     time.Duration
+	deployNoWait      bool
+	deployInteractive bool
+)
+
+// deployPollInterval is how often the application status is checked while waiting
+const deployPollInterval = 2 * time.Second
+
+func init() {
+	rootCmd.AddCommand(deployCmd)
+
+	deployCmd.Flags().StringVarP(&deployName, "name", "n", "", "Application name (required)")
+	deployCmd.Flags().StringVarP(&deployImage, "image", "i", "", "Container image (required)")
+	deployCmd.Flags().StringSliceVarP(&deployPorts, "port", "p", []string{}, "Port mappings (host:container)")
+	deployCmd.Flags().StringSliceVarP(&deployEnv, "env", "e", []string{}, "Environment variables (KEY=VALUE)")
+	deployCmd.Flags().StringSliceVar(&deploySensitive, "sensitive-env", []string{}, "Environment variables whose values the API redacts (KEY=VALUE)")
+	deployCmd.Flags().StringSliceVarP(&deploySecrets, "secret", "s", []string{}, "Secrets to inject (NAME=ENV_VAR or NAME=/file/path)")
+	deployCmd.Flags().StringSliceVarP(&deployVolumes, "volume", "v", []string{}, "Volumes to attach (NAME:/path or NAME:/path:ro)")
+	deployCmd.Flags().StringSliceVar(&deployConfigs, "config-file", []string{}, "Local files to mount read-only (/container/path=LOCAL_FILE)")
+	deployCmd.Flags().StringSliceVar(&deploySelector, "node-selector", []string{}, "Labels of the nodes that may run the application (KEY=VALUE)")
+	deployCmd.Flags().StringVar(&deployEnvironment, "environment", "", "Environment ID the application belongs to")
+	deployCmd.Flags().Float64Var(&deployCPUs, "cpus", 0, "CPU cores each container may use, e.g. 0.5 (0 uses the environment default)")
+	deployCmd.Flags().StringVar(&deployMemory, "memory", "", "Memory each container may use, e.g. 512MiB (empty uses the environment default)")
+	deployCmd.Flags().DurationVar(&deployTimeout, "timeout", 2*time.Minute, "How long to wait for the application to run")
+	deployCmd.Flags().BoolVar(&deployNoWait, "no-wait", false, "Return immediately without waiting for the application to run")
+	deployCmd.Flags().BoolVar(&deployInteractive, "interactive", false, "Keep stdin open so 'simplify app attach' can send input")
+
+	_ = deployCmd.MarkFlagRequired("name")  //nolint:errcheck // flag registration rarely fails
+	_ = deployCmd.MarkFlagRequired("image") //nolint:errcheck // flag registration rarely fails
+}
+
+func runDeploy(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ports, err := parsePorts(deployPorts)
+	if err != nil {
+		return err
+	}
+
+	env, err := parseEnvVars(deployEnv)
+	if err != nil {
+		return err
+	}
+	sensitive, err := parseEnvVars(deploySensitive)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := parseSecretRefs(deploySecrets)
+	if err != nil {
+		return err
+	}
+
+	volumes, err := parseVolumeMounts(deployVolumes)
+	if err != nil {
+		return err
+	}
+
+	configs, err := readConfigFiles(deployConfigs)
+	if err != nil {
+		return err
+	}
+
+	selector, err := parseLabels(deploySelector)
+	if err != nil {
+		return err
+	}
+
+	var memory int64
+	if deployMemory != "" {
+		if memory, err = parseByteSize(deployMemory); err != nil {
+			return err
+		}
+	}
+
+	app, err := findApplicationByName(ctx, client, deployName)
+	if err != nil {
+		return err
+	}
+
+	if app == nil {
+		app = &core.Application{Name: deployName, Replicas: 1}
+	}
+
+	app.Image = deployImage
+	if deployEnvironment != "" {
+		app.EnvironmentID = deployEnvironment
+	}
+	if cmd.Flags().Changed("port") {
+		app.Ports = make(map[string]string, len(ports))
+		for host, containerPort := range ports {
+			app.Ports[fmt.Sprintf("%d", host)] = fmt.Sprintf("%d", containerPort)
+		}
+	}
+	if len(env) > 0 {
+		if app.EnvVars == nil {
+			app.EnvVars = make(map[string]string, len(env))
+		}
+		for k, v := range env {
+			app.EnvVars[k] = v
+		}
+	}
+	if len(sensitive) > 0 {
+		if app.EnvVars == nil {
+			app.EnvVars = make(map[string]string, len(sensitive))
+		}
+		for k, v := range sensitive {
+			app.EnvVars[k] = v
+			if !slices.Contains(app.SensitiveEnv, k) {
+				app.SensitiveEnv = append(app.SensitiveEnv, k)
+			}
+		}
+	}
+
+	if cmd.Flags().Changed("secret") {
+		app.Secrets = secrets
+	}
+	if cmd.Flags().Changed("volume") {
+		app.Volumes = volumes
+	}
+	if cmd.Flags().Changed("config-file") {
+		app.ConfigFiles = configs
+	}
+	if cmd.Flags().Changed("node-selector") {
+		app.NodeSelector = selector
+	}
+	if cmd.Flags().Changed("interactive") {
+		app.Interactive = deployInteractive
+	}
+	if cmd.Flags().Changed("cpus") || cmd.Flags().Changed("memory") {
+		if app.Resources == nil {
+			app.Resources = &core.Resources{}
+		}
+		if cmd.Flags().Changed("cpus") {
+			app.Resources.CPUs = deployCPUs
+		}
+		if cmd.Flags().Changed("memory") {
+			app.Resources.MemoryBytes = memory
+		}
+	}
+
+	logger.InfoCtx(ctx, "Deploying application", "name", app.Name, "image", app.Image, "update", app.ID != "")
+
+	var saved *core.Application
+	if app.ID != "" {
+		saved, err = client.UpdateApplication(ctx, app)
+	} else {
+		saved, err = client.CreateApplication(ctx, app)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deploy application: %w", err)
+	}
+
+	fmt.Printf("Application %s deployed (ID: %s)\n", saved.Name, saved.ID)
+	if deployNoWait {
+		return nil
+	}
+
+	running, err := waitForApplication(ctx, client, saved.ID, deployTimeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Application %s is running\n", running.Name)
+	for _, url := range applicationURLs(running) {
+		fmt.Printf("  %s\n", url)
+	}
+	return nil
+}
+
+// findApplicationByName returns the application with the given name, or nil if none exists
+func findApplicationByName(ctx context.Context, client *apiClient, name string) (*core.Application, error) {
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing applications: %w", err)
+	}
+
+	for i := range apps {
+		if apps[i].Name != name {
+			continue
+		}
+		// The listing reports runtime port bindings, so re-read the stored spec
+		app, err := client.GetApplication(ctx, apps[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting application %s: %w", name, err)
+		}
+		return app, nil
+	}
+	return nil, nil
+}
+
+// waitForApplication polls the API until the application is running or the timeout expires
+func waitForApplication(ctx context.Context, client *apiClient, id string, timeout time.Duration) (*core.Application, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(deployPollInterval)
+	defer ticker.Stop()
+
+	lastStatus := ""
+	for {
+		apps, err := client.ListApplications(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checking application status: %w", err)
+		}
+
+		for i := range apps {
+			if apps[i].ID != id {
+				continue
+			}
+			if apps[i].Status != lastStatus {
+				logger.DebugCtx(ctx, "Application status changed", "id", id, "status", apps[i].Status)
+				lastStatus = apps[i].Status
+			}
+			if apps[i].Status == "running" {
+				return &apps[i], nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for application to run (last status: %q)", timeout, lastStatus)
+		case <-ticker.C:
+		}
+	}
+}
+
+// applicationURLs builds browsable URLs from the published ports of a running application
+func applicationURLs(app *core.Application) []string {
+	urls := make([]string, 0, len(app.Ports))
+	for _, binding := range app.Ports {
+		if binding == "" {
+			continue
+		}
+		if !strings.Contains(binding, ":") {
+			binding = "127.0.0.1:" + binding
+		}
+		urls = append(urls, "http://"+binding)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// parseEnvVars converts "KEY=VALUE" strings to a map
+func parseEnvVars(vars []string) (map[string]string, error) {
+	result := make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid environment variable %q (use KEY=VALUE format)", v)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// parseSecretRefs parses NAME=ENV_VAR and NAME=/file/path secret references.
+// Targets starting with '/' are files, anything else an environment variable.
+func parseSecretRefs(refs []string) ([]core.SecretRef, error) {
+	result := make([]core.SecretRef, 0, len(refs))
+	for _, r := range refs {
+		name, target, ok := strings.Cut(r, "=")
+		if !ok || name == "" || target == "" {
+			return nil, fmt.Errorf("invalid secret %q (use NAME=ENV_VAR or NAME=/file/path format)", r)
+		}
+		ref := core.SecretRef{Name: name}
+		if strings.HasPrefix(target, "/") {
+			ref.File = target
+		} else {
+			ref.Env = target
+		}
+		result = append(result, ref)
+	}
+	return result, nil
+}
+
+// parseVolumeMounts parses NAME:/path and NAME:/path:ro volume attachments
+func parseVolumeMounts(mounts []string) ([]core.VolumeMount, error) {
+	result := make([]core.VolumeMount, 0, len(mounts))
+	for _, m := range mounts {
+		parts := strings.Split(m, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid volume %q (use NAME:/path or NAME:/path:ro format)", m)
+		}
+		mount := core.VolumeMount{Name: parts[0], Path: parts[1]}
+		if len(parts) == 3 {
+			if parts[2] != "ro" && parts[2] != "rw" {
+				return nil, fmt.Errorf("invalid volume %q: mode must be ro or rw", m)
+			}
+			mount.ReadOnly = parts[2] == "ro"
+		}
+		result = append(result, mount)
+	}
+	return result, nil
+}
+
+// readConfigFiles reads "/container/path=LOCAL_FILE" specs into config files
+// that keep the local file's permissions
+func readConfigFiles(specs []string) ([]core.ConfigFile, error) {
+	result := make([]core.ConfigFile, 0, len(specs))
+	for _, spec := range specs {
+		target, local, ok := strings.Cut(spec, "=")
+		if !ok || target == "" || local == "" {
+			return nil, fmt.Errorf("invalid config file %q (use /container/path=LOCAL_FILE format)", spec)
+		}
+		info, err := os.Stat(local)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if info.Size() > core.MaxConfigFileSize {
+			return nil, fmt.Errorf("config file %s is larger than %d bytes; use a volume instead", local, core.MaxConfigFileSize)
+		}
+		content, err := os.ReadFile(local) //nolint:gosec // reading the file the user named is the point
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		result = append(result, core.ConfigFile{Path: target, Content: string(content), Mode: uint32(info.Mode().Perm())})
+	}
+	return result, nil
+}
+
+// parseByteSize parses sizes such as 512MiB, 2g or 1048576. Units are
+// powers of 1024, as in podman's --memory.
+func parseByteSize(size string) (int64, error) {
+	number := strings.TrimRightFunc(size, unicode.IsLetter)
+	unit := strings.ToLower(strings.TrimSpace(size[len(number):]))
+	n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use a number with an optional unit, e.g. 512MiB)", size)
+	}
+
+	shift, ok := map[string]uint{
+		"": 0, "b": 0,
+		"k": 10, "kb": 10, "kib": 10,
+		"m": 20, "mb": 20, "mib": 20,
+		"g": 30, "gb": 30, "gib": 30,
+		"t": 40, "tb": 40, "tib": 40,
+	}[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", size, unit)
+	}
+	return int64(n * float64(int64(1)<<shift)), nil
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/permissions"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that this host can run Simplify",
+	Long: `Run preflight checks and report how to fix any problems:
+
+  - the config file loads and is valid
+  - the database and log file directories are writable
+  - the Podman socket exists and is usable by the current user
+  - the Podman API answers on that socket
+
+The command exits non-zero when a check fails.`,
+	Args: cobra.NoArgs,
+	// Config errors are reported as a failed check rather than aborting
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return initLogger(cmd)
+	},
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorCheck is the outcome of one preflight check
+type doctorCheck struct {
+	err    error
+	name   string
+	detail string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	var checks []doctorCheck
+	add := func(name, detail string, err error) {
+		checks = append(checks, doctorCheck{name: name, detail: detail, err: err})
+	}
+
+	if configErr != nil {
+		add("Config", cfgFile, configErr)
+	} else {
+		add("Config", cfgFile, nil)
+
+		cfg := config.Get()
+		add("Database", cfg.Database.Path, permissions.EnsureFileWritable(cfg.Database.Path))
+		if path := cfg.Logging.File.Path; path != "" {
+			add("Log file", path, permissions.EnsureFileWritable(path))
+		}
+	}
+
+	socket, err := doctorSocketPath()
+	switch {
+	case err != nil:
+		add("Podman socket", "", err)
+	case socket == "":
+		add("Podman socket", "remote socket, skipped", nil)
+	default:
+		add("Podman socket", socket, permissions.CheckPodmanSocket(socket))
+	}
+
+	client, err := newEngineClient(ctx)
+	if err == nil {
+		_, err = client.List(ctx, false)
+	}
+	add("Podman API", "", err)
+
+	failed := 0
+	for _, c := range checks {
+		mark := "✓"
+		if c.err != nil {
+			mark = "✗"
+			failed++
+		}
+		fmt.Printf("%s %-14s %s\n", mark, c.name, c.detail)
+		if c.err != nil {
+			fmt.Printf("    %s\n", strings.ReplaceAll(c.err.Error(), "\n", "\n    "))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	fmt.Println("\nAll checks passed")
+	return nil
+}
+
+// doctorSocketPath returns the local Podman socket used by engine commands,
+// or "" when the active context uses a remote (ssh or tcp) socket
+func doctorSocketPath() (string, error) {
+	active, err := activeContext()
+	if err != nil {
+		return "", err
+	}
+	if active.Socket == "" {
+		return container.SocketPath(), nil
+	}
+	if path, ok := strings.CutPrefix(active.Socket, "unix://"); ok {
+		return path, nil
+	}
+	return "", nil
+}
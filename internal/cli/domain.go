@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var domainCmd = &cobra.Command{
+	Use:   "domain",
+	Short: "Manage the domains routed to applications",
+	Long: `Manage domains stored by the Simplify server.
+
+A domain routes a hostname to an application. Before it is trusted, prove
+ownership by publishing the TXT record printed by 'simplify domain add' and
+running 'simplify domain verify'.`,
+}
+
+var domainAddCmd = &cobra.Command{
+	Use:   "add [hostname]",
+	Short: "Route a hostname to an application",
+	Example: `  simplify domain add app.example.com --app web
+  simplify domain add intranet.corp.lan --app wiki --tls internal --port 8080`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDomainAdd,
+}
+
+var domainListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List domains",
+	Args:    cobra.NoArgs,
+	RunE:    runDomainList,
+}
+
+var domainVerifyCmd = &cobra.Command{
+	Use:   "verify [hostname]",
+	Short: "Check a domain's DNS verification record",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDomainVerify,
+}
+
+var domainRmCmd = &cobra.Command{
+	Use:   "rm [hostname...]",
+	Short: "Remove domains",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDomainRm,
+}
+
+var (
+	domainApp  string
+	domainTLS  string
+	domainPort int
+)
+
+func init() {
+	rootCmd.AddCommand(domainCmd)
+	domainCmd.AddCommand(domainAddCmd)
+	domainCmd.AddCommand(domainListCmd)
+	domainCmd.AddCommand(domainVerifyCmd)
+	domainCmd.AddCommand(domainRmCmd)
+
+	domainAddCmd.Flags().StringVar(&domainApp, "app", "", "Application to route to (required)")
+	domainAddCmd.Flags().StringVar(&domainTLS, "tls", core.TLSAuto, "TLS mode: auto, internal or off")
+	domainAddCmd.Flags().IntVar(&domainPort, "port", 0, "Container port to route to (default: the app's first port)")
+	_ = domainAddCmd.MarkFlagRequired("app")
+}
+
+func runDomainAdd(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, domainApp)
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", domainApp)
+	}
+
+	domain := core.Domain{
+		Hostname:      args[0],
+		ApplicationID: app.ID,
+		TLS:           domainTLS,
+		Port:          domainPort,
+	}
+	saved, err := client.CreateDomain(ctx, &domain)
+	if err != nil {
+		return fmt.Errorf("failed to add domain: %w", err)
+	}
+
+	fmt.Printf("Domain %s added for %s\n\n", saved.Hostname, app.Name)
+	fmt.Println("Publish this DNS record, then run 'simplify domain verify " + saved.Hostname + "':")
+	fmt.Printf("  %s  TXT  %q\n", saved.VerificationRecord(), saved.VerificationToken)
+	return nil
+}
+
+func runDomainList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListDomains(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list domains: %w", err)
+	}
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+	names := make(map[string]string, len(apps))
+	for i := range apps {
+		names[apps[i].ID] = apps[i].Name
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No domains found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "HOSTNAME\tAPP\tPORT\tTLS\tSTATUS\tREADY\tCREATED")
+		for i := range list {
+			d := &list[i]
+			app := names[d.ApplicationID]
+			if app == "" {
+				app = truncateString(d.ApplicationID, 12)
+			}
+			port := "-"
+			if d.Port != 0 {
+				port = fmt.Sprint(d.Port)
+			}
+			ready := "no"
+			if d.Ready {
+				ready = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.Hostname, app, port, d.TLS, d.Status, ready, formatCreatedTime(d.CreatedAt))
+		}
+		return w.Flush()
+	})
+}
+
+func runDomainVerify(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	domain, err := findDomainByHostname(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	checked, err := client.VerifyDomain(ctx, domain.ID)
+	if err != nil {
+		return fmt.Errorf("failed to verify domain: %w", err)
+	}
+
+	if checked.Status != core.DomainVerified {
+		return fmt.Errorf("domain %s is not verified: %s", checked.Hostname, checked.StatusMessage)
+	}
+	fmt.Printf("Domain %s verified\n", checked.Hostname)
+	return nil
+}
+
+func runDomainRm(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, hostname := range args {
+		domain, err := findDomainByHostname(ctx, client, hostname)
+		if err == nil {
+			err = client.DeleteDomain(ctx, domain.ID)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", hostname, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Domain %s removed\n", hostname)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d domain(s)", failed)
+	}
+	return nil
+}
+
+// findDomainByHostname looks up a domain through the API
+func findDomainByHostname(ctx context.Context, client *apiClient, hostname string) (*core.Domain, error) {
+	domains, err := client.ListDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %w", err)
+	}
+
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	for i := range domains {
+		if domains[i].Hostname == hostname {
+			return &domains[i], nil
+		}
+	}
+	return nil, errors.NewNotFoundError("domain", hostname)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// jsonErrorsEnv enables JSON error output regardless of --output
+const jsonErrorsEnv = "SIMPLIFY_JSON_ERRORS"
+
+// errorOutput is the JSON document written to stderr for a failed command.
+// The error object matches the server's error body.
+type errorOutput struct {
+	Error    errorDetail `json:"error"`
+	ExitCode int         `json:"exit_code"`
+}
+
+type errorDetail struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Resource string `json:"resource,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Field    string `json:"field,omitempty"`
+	DocURL   string `json:"doc_url,omitempty"`
+}
+
+// HandleError reports a command error on w and returns the exit code for
+// it. Errors are written as JSON when --output json is used or
+// SIMPLIFY_JSON_ERRORS is set, and as a plain "Error:" line otherwise.
+func HandleError(w io.Writer, err error) int {
+	var status *exitStatusError
+	if stderrors.As(err, &status) {
+		return status.code
+	}
+
+	err = errors.Classify(err)
+	code := errors.ExitCode(err)
+	if !jsonErrors() {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return code
+	}
+
+	out := errorOutput{
+		Error: errorDetail{
+			Code:    errors.GetErrorCode(err),
+			Message: err.Error(),
+		},
+		ExitCode: code,
+	}
+	out.Error.DocURL = errors.DocURL(out.Error.Code)
+	if base := errors.GetBaseError(err); base != nil {
+		out.Error.Resource = base.Resource
+		out.Error.ID = base.ID
+	}
+	var invalid *errors.InvalidInputError
+	if stderrors.As(err, &invalid) {
+		out.Error.Field = invalid.Field
+	}
+
+	if encErr := json.NewEncoder(w).Encode(out); encErr != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+	}
+	return code
+}
+
+// jsonErrors reports whether errors should be written as JSON
+func jsonErrors() bool {
+	if enabled, err := strconv.ParseBool(os.Getenv(jsonErrorsEnv)); err == nil && enabled {
+		return true
+	}
+	return outputFormat == outputJSON
+}
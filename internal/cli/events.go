@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream container engine events",
+	Long: `Stream events from the container engine, such as containers and pods
+being created, started, stopped or removed. This shows what the reconciler
+and the engine are doing live.
+
+Events are streamed until interrupted, unless --until is given. Filters use
+the engine's keys, for example type, event, container, pod, image or label.
+Repeating a key matches any of its values.`,
+	Example: `  simplify events
+  simplify events --since 1h --filter type=container
+  simplify events --managed --filter event=start --filter event=die
+  simplify events --since 30m --until 10m -o json`,
+	Args: cobra.NoArgs,
+	RunE: runEvents,
+}
+
+var (
+	eventsSince   string
+	eventsUntil   string
+	eventsFilters []string
+	eventsManaged bool
+)
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Show events since a timestamp or duration (e.g. 1h)")
+	eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "Stop at a timestamp or duration instead of streaming")
+	eventsCmd.Flags().StringArrayVar(&eventsFilters, "filter", nil, "Filter events (KEY=VALUE, e.g. type=container)")
+	eventsCmd.Flags().BoolVar(&eventsManaged, "managed", false, "Only show events for resources managed by Simplify")
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	filters, err := parseEventFilters(eventsFilters)
+	if err != nil {
+		return err
+	}
+	if eventsManaged {
+		filters["label"] = append(filters["label"], container.LabelManaged+"=true")
+	}
+
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Podman: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	opts := container.EventOptions{
+		Filters: filters,
+		Since:   eventsSince,
+		Until:   eventsUntil,
+		Follow:  eventsUntil == "",
+	}
+
+	err = client.StreamEvents(ctx, opts, func(e container.Event) error {
+		return printEvent(os.Stdout, &e)
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to stream events: %w", err)
+	}
+	return nil
+}
+
+// parseEventFilters parses "key=value" filter expressions into the engine's filter map
+func parseEventFilters(raw []string) (map[string][]string, error) {
+	filters := make(map[string][]string, len(raw))
+	for _, f := range raw {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid filter %q (use key=value)", f)
+		}
+		filters[key] = append(filters[key], value)
+	}
+	return filters, nil
+}
+
+// printEvent writes one event per line. JSON output is written as compact
+// JSON lines so the stream can be piped into other tools.
+func printEvent(w io.Writer, e *container.Event) error {
+	if formatTemplate == "" && outputFormat == outputJSON {
+		return json.NewEncoder(w).Encode(e)
+	}
+	if isMachineOutput() {
+		if outputFormat == outputYAML && formatTemplate == "" {
+			fmt.Fprintln(w, "---")
+		}
+		return writeOutput(w, e, nil)
+	}
+
+	name := e.Name
+	if name == "" {
+		name = truncateString(e.ID, 12)
+	}
+
+	// Show a few identifying attributes, leaving out labels and the name already shown
+	var attrs []string
+	for _, key := range []string{"image", "podId", "network", "exitCode", "health_status"} {
+		if v := e.Attributes[key]; v != "" {
+			attrs = append(attrs, key+"="+v)
+		}
+	}
+	sort.Strings(attrs)
+
+	line := fmt.Sprintf("%s  %-9s %-12s %s", e.Time.Format("2006-01-02 15:04:05"), e.Type, e.Action, name)
+	if len(attrs) > 0 {
+		line += "  (" + strings.Join(attrs, ", ") + ")"
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec CONTAINER -- COMMAND [ARG...]",
+	Short: "Run a command in a running container",
+	Long: `Run a one-off command in a running container, such as a shell or a
+database migration. Use -i to pass stdin to the command and -t to give it a
+terminal; -it together start an interactive session.
+
+simplify exec exits with the exit code of the command. Commands in
+containers of the Simplify server can also be run through its API with
+POST /api/v1/applications/{id}/exec.`,
+	Example: `  simplify exec web -- ls /usr/share/nginx/html
+  simplify exec -it db -- psql -U postgres
+  simplify exec -e DEBUG=1 -u app worker -- ./manage.py migrate`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeNames(1, listContainerNames),
+	RunE:              runExec,
+}
+
+var (
+	execInteractive bool
+	execTTY         bool
+	execEnv         []string
+	execUser        string
+	execWorkDir     string
+)
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().BoolVarP(&execInteractive, "interactive", "i", false, "Pass stdin to the command")
+	execCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a terminal for the command")
+	execCmd.Flags().StringArrayVarP(&execEnv, "env", "e", nil, "Environment variables (KEY=VALUE)")
+	execCmd.Flags().StringVarP(&execUser, "user", "u", "", "User to run the command as")
+	execCmd.Flags().StringVarP(&execWorkDir, "workdir", "w", "", "Working directory of the command")
+}
+
+// exitStatusError ends the CLI with the exit code of a command it ran. The
+// command has already reported its own failure, so no error is printed.
+type exitStatusError struct {
+	code int
+}
+
+func (e *exitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	if cmd.ArgsLenAtDash() != 1 {
+		return fmt.Errorf("separate the command from the container with --, as in: simplify exec web -- ls")
+	}
+	if execTTY && !term.IsTerminal(int(os.Stdin.Fd())) { //nolint:gosec // file descriptors fit in int
+		return fmt.Errorf("--tty needs a terminal on stdin")
+	}
+	if _, err := parseEnvVars(execEnv); err != nil {
+		return err
+	}
+
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Podman: %w", err)
+	}
+
+	opts := container.ExecOptions{
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Cmd:     args[1:],
+		Env:     execEnv,
+		User:    execUser,
+		WorkDir: execWorkDir,
+		TTY:     execTTY,
+	}
+	if execInteractive {
+		opts.Stdin = os.Stdin
+	}
+
+	logger.DebugCtx(ctx, "Executing command", "container", args[0], "cmd", opts.Cmd)
+	code, err := client.Exec(ctx, args[0], opts)
+	if err != nil {
+		return fmt.Errorf("failed to run command in %s: %w", args[0], err)
+	}
+	if code != 0 {
+		return &exitStatusError{code: code}
+	}
+	return nil
+}
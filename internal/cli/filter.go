@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+)
+
+// Filter keys supported by --filter
+const (
+	filterLabel  = "label"
+	filterStatus = "status"
+	filterName   = "name"
+	filterID     = "id"
+)
+
+// containerFilters holds parsed --filter values grouped by key.
+// Values for the same key are OR'ed, different keys are AND'ed.
+type containerFilters map[string][]string
+
+// parseContainerFilters parses "key=value" filter expressions
+func parseContainerFilters(raw []string) (containerFilters, error) {
+	filters := make(containerFilters, len(raw))
+	for _, f := range raw {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid filter %q (use key=value)", f)
+		}
+
+		switch key {
+		case filterLabel, filterStatus, filterID:
+		case filterName:
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, fmt.Errorf("invalid name pattern %q: %w", value, err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown filter %q (supported: label, status, name, id)", key)
+		}
+		filters[key] = append(filters[key], value)
+	}
+	return filters, nil
+}
+
+// needsAll reports whether the filters can match stopped containers, which
+// requires listing all containers instead of just running ones
+func (f containerFilters) needsAll() bool {
+	return len(f[filterStatus]) > 0
+}
+
+// match reports whether the container satisfies every filter key
+func (f containerFilters) match(c *container.ContainerInfo) bool {
+	for key, values := range f {
+		matched := false
+		for _, v := range values {
+			if matchFilter(c, key, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the containers matching the filters
+func (f containerFilters) apply(containers []container.ContainerInfo) []container.ContainerInfo {
+	if len(f) == 0 {
+		return containers
+	}
+
+	result := make([]container.ContainerInfo, 0, len(containers))
+	for i := range containers {
+		if f.match(&containers[i]) {
+			result = append(result, containers[i])
+		}
+	}
+	return result
+}
+
+func matchFilter(c *container.ContainerInfo, key, value string) bool {
+	switch key {
+	case filterLabel:
+		return matchLabel(c.Labels, value)
+	case filterStatus:
+		return strings.EqualFold(c.Status, value)
+	case filterName:
+		ok, _ := path.Match(value, c.Name) //nolint:errcheck // pattern validated in parseContainerFilters
+		return ok
+	case filterID:
+		return strings.HasPrefix(c.ID, value)
+	default:
+		return false
+	}
+}
+
+// matchLabel matches a "key" or "key=value" selector against labels
+func matchLabel(labels map[string]string, selector string) bool {
+	key, value, hasValue := strings.Cut(selector, "=")
+	actual, ok := labels[key]
+	if !ok {
+		return false
+	}
+	return !hasValue || actual == value
+}
+
+// selectContainers resolves the targets of a bulk command: the explicit names,
+// plus every managed container when all is set, plus containers matching any
+// of the label selectors. Stopped containers are only considered when
+// includeStopped is set.
+func selectContainers(ctx context.Context, client *container.Client, names []string, all bool, labels []string, includeStopped bool) ([]string, error) {
+	if len(names) > 0 && all {
+		return nil, fmt.Errorf("cannot combine container names with --all")
+	}
+	if len(names) == 0 && !all && len(labels) == 0 {
+		return nil, fmt.Errorf("specify at least one container, --all or --label")
+	}
+
+	targets := append([]string(nil), names...)
+	if !all && len(labels) == 0 {
+		return targets, nil
+	}
+
+	containers, err := client.List(ctx, includeStopped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	for i := range containers {
+		c := &containers[i]
+		if all && c.Labels[container.LabelManaged] != "true" {
+			continue
+		}
+		if len(labels) > 0 && !slices.ContainsFunc(labels, func(l string) bool { return matchLabel(c.Labels, l) }) {
+			continue
+		}
+		targets = append(targets, c.Name)
+	}
+
+	targets = uniqueStrings(targets)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no containers matched")
+	}
+	return targets, nil
+}
+
+// runBulk applies op to every name, reporting each outcome and a final summary
+func runBulk(ctx context.Context, names []string, verb string, op func(name string) error) error {
+	var failed []string
+	for _, name := range names {
+		if err := op(name); err != nil {
+			logger.ErrorCtx(ctx, "Operation failed", "verb", verb, "name", name, "error", err)
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			failed = append(failed, name)
+			continue
+		}
+		fmt.Printf("Container %s %s\n", name, verb)
+	}
+
+	if len(names) > 1 {
+		fmt.Printf("%d %s, %d failed\n", len(names)-len(failed), verb, len(failed))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed on %d container(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// runningAmong returns which of the given containers (by name or ID) are running
+func runningAmong(ctx context.Context, client *container.Client, names []string) ([]string, error) {
+	running, err := client.List(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var result []string
+	for _, name := range names {
+		for i := range running {
+			if running[i].Name == name || strings.HasPrefix(running[i].ID, name) {
+				result = append(result, name)
+				break
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate an application manifest",
+	Long: `Generate a manifest describing a single application, ready for
+'simplify apply'. Values not given as flags are prompted for when running
+in a terminal.`,
+	Example: `  simplify init
+  simplify init --name web --image nginx:latest --port 8080:80
+  simplify init -f web.yaml --env LOG_LEVEL=debug --force`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+var (
+	initFile        string
+	initName        string
+	initImage       string
+	initPorts       []string
+	initEnv         []string
+	initEnvironment string
+	initForce       bool
+)
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVarP(&initFile, "file", "f", "simplify.yaml", "Manifest file to write")
+	initCmd.Flags().StringVarP(&initName, "name", "n", "", "Application name")
+	initCmd.Flags().StringVarP(&initImage, "image", "i", "", "Container image")
+	initCmd.Flags().StringSliceVarP(&initPorts, "port", "p", []string{}, "Port mappings (host:container)")
+	initCmd.Flags().StringSliceVarP(&initEnv, "env", "e", []string{}, "Environment variables (KEY=VALUE)")
+	initCmd.Flags().StringVar(&initEnvironment, "environment", "", "Environment ID the application belongs to")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite an existing manifest")
+}
+
+// initApplication is the application written by init. Unlike core.Application
+// it leaves out server-managed fields such as status and timestamps.
+type initApplication struct {
+	Ports         map[string]string `json:"ports,omitempty"`
+	EnvVars       map[string]string `json:"env_vars,omitempty"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	EnvironmentID string            `json:"environment_id,omitempty"`
+	Replicas      int               `json:"replicas"`
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(initFile); err == nil && !initForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", initFile)
+	}
+
+	app, err := collectInitApplication(cmd)
+	if err != nil {
+		return err
+	}
+
+	data, err := renderInitManifest(app)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(initFile, data, 0o644); err != nil { //nolint:gosec // manifests are meant to be shared
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", initFile)
+	fmt.Printf("Deploy it with: simplify apply -f %s\n", initFile)
+	return nil
+}
+
+// collectInitApplication builds the application from flags, prompting for
+// anything missing when stdin is a terminal
+func collectInitApplication(cmd *cobra.Command) (*initApplication, error) {
+	interactive := isInteractive()
+	app := &initApplication{
+		Name:          initName,
+		Image:         initImage,
+		EnvironmentID: initEnvironment,
+		Replicas:      1,
+	}
+
+	var err error
+	if app.Name == "" && interactive {
+		if app.Name, err = ask("Application name", defaultAppName()); err != nil {
+			return nil, err
+		}
+	}
+	if app.Name == "" {
+		return nil, fmt.Errorf("application name is required (use --name)")
+	}
+
+	if app.Image == "" && interactive {
+		if app.Image, err = ask("Container image", ""); err != nil {
+			return nil, err
+		}
+	}
+	if app.Image == "" {
+		return nil, fmt.Errorf("container image is required (use --image)")
+	}
+
+	ports := initPorts
+	if !cmd.Flags().Changed("port") && interactive {
+		answer, err := ask("Published ports (host:container, comma separated)", "")
+		if err != nil {
+			return nil, err
+		}
+		ports = splitList(answer)
+	}
+	parsed, err := parsePorts(ports)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) > 0 {
+		app.Ports = make(map[string]string, len(parsed))
+		for host, containerPort := range parsed {
+			app.Ports[fmt.Sprintf("%d", host)] = fmt.Sprintf("%d", containerPort)
+		}
+	}
+
+	env := initEnv
+	if !cmd.Flags().Changed("env") && interactive {
+		answer, err := ask("Environment variables (KEY=VALUE, comma separated)", "")
+		if err != nil {
+			return nil, err
+		}
+		env = splitList(answer)
+	}
+	if app.EnvVars, err = parseEnvVars(env); err != nil {
+		return nil, err
+	}
+
+	if app.EnvironmentID == "" && interactive {
+		if app.EnvironmentID, err = ask("Environment ID (optional)", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return app, nil
+}
+
+// renderInitManifest encodes the application as a manifest and checks that
+// apply will accept it
+func renderInitManifest(app *initApplication) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by 'simplify init'. Deploy with: simplify apply -f %s\n", initFile)
+	if err := writeYAML(&buf, map[string]any{"applications": []*initApplication{app}}); err != nil {
+		return nil, err
+	}
+
+	if _, err := manifest.Parse(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("generated manifest is invalid: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// defaultAppName suggests an application name from the current directory
+func defaultAppName() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, filepath.Base(wd))
+	return strings.Trim(name, "-")
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [name...]",
+	Short: "Show full engine details of containers, pods or networks",
+	Long: `Print the complete inspect data reported by Podman, including network
+settings, port bindings, mounts and state. Names are looked up as containers,
+then pods, then networks unless --type is given.`,
+	Example: `  simplify inspect web
+  simplify inspect --type network my-net
+  simplify inspect web --format '{{.State.Status}}'`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeNames(0, listContainerNames),
+	RunE:              runInspect,
+}
+
+var inspectType string
+
+// inspectKinds is the lookup order used when --type is not given
+var inspectKinds = []string{container.KindContainer, container.KindPod, container.KindNetwork}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().StringVarP(&inspectType, "type", "t", "", "Resource type: container, pod or network")
+	_ = inspectCmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions( //nolint:errcheck // flag registration rarely fails
+		inspectKinds, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	kinds := inspectKinds
+	if inspectType != "" {
+		kinds = []string{inspectType}
+	}
+
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Podman: %w", err)
+	}
+
+	results := make([]any, 0, len(args))
+	for _, name := range args {
+		data, err := inspectAny(ctx, client, kinds, name)
+		if err != nil {
+			return err
+		}
+		results = append(results, data)
+	}
+
+	return printOutput(results, func(out io.Writer) error {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	})
+}
+
+// inspectAny returns the inspect data of the first resource kind that matches name
+func inspectAny(ctx context.Context, client *container.Client, kinds []string, name string) (any, error) {
+	var lastErr error
+	for _, kind := range kinds {
+		data, err := client.InspectRaw(ctx, kind, name)
+		if err == nil {
+			return data, nil
+		}
+		logger.DebugCtx(ctx, "Inspect lookup failed", "kind", kind, "name", name, "error", err)
+		lastErr = err
+	}
+
+	if len(kinds) == 1 {
+		return nil, lastErr
+	}
+	return nil, errors.NewNotFoundErrorWithCause("resource", name, lastErr)
+}
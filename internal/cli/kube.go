@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/kube"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var kubeCmd = &cobra.Command{
+	Use:   "kube",
+	Short: "Work with Kubernetes manifests",
+}
+
+var kubeGenerateCmd = &cobra.Command{
+	Use:   "generate (app|pod) NAME",
+	Short: "Generate Kubernetes YAML from the desired state",
+	Long: `Render an application or pod stored on the Simplify server as a
+Kubernetes Deployment, plus a Service when ports are published.
+
+An application becomes a Deployment with its replicas, image, environment
+and container ports. A pod becomes a single Deployment whose pod template
+runs every member application, since containers in a Kubernetes pod share
+a network namespace just like a Podman pod.`,
+	Example: `  simplify kube generate app web
+  simplify kube generate pod stack -f stack.yaml
+  simplify kube generate app api | kubectl apply -f -`,
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: []string{"app", "pod"},
+	RunE:      runKubeGenerate,
+}
+
+var kubeOutputFile string
+
+func init() {
+	rootCmd.AddCommand(kubeCmd)
+	kubeCmd.AddCommand(kubeGenerateCmd)
+
+	kubeGenerateCmd.Flags().StringVarP(&kubeOutputFile, "file", "f", "", "Write the YAML to a file instead of stdout")
+}
+
+func runKubeGenerate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	kind, name := args[0], args[1]
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var objs []kube.Object
+	switch kind {
+	case "app", "application":
+		objs, err = kubeApplicationObjects(ctx, client, name)
+	case "pod":
+		objs, err = kubePodObjects(ctx, client, name)
+	default:
+		return fmt.Errorf("unknown resource %q (use app or pod)", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := kube.Marshal(objs)
+	if err != nil {
+		return fmt.Errorf("failed to render manifest: %w", err)
+	}
+
+	if kubeOutputFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(kubeOutputFile, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", kubeOutputFile, err)
+	}
+	fmt.Printf("Wrote %d object(s) to %s\n", len(objs), kubeOutputFile)
+	return nil
+}
+
+// kubeApplicationObjects renders the stored spec of an application
+func kubeApplicationObjects(ctx context.Context, client *apiClient, name string) ([]kube.Object, error) {
+	app, err := findApplicationByName(ctx, client, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return nil, fmt.Errorf("application %q not found on the Simplify server", name)
+	}
+	return kube.ApplicationObjects(app)
+}
+
+// kubePodObjects renders a pod together with its member applications
+func kubePodObjects(ctx context.Context, client *apiClient, name string) ([]kube.Object, error) {
+	pods, err := client.ListPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	idx := slices.IndexFunc(pods, func(p core.Pod) bool { return p.Name == name || p.ID == name })
+	if idx < 0 {
+		return nil, fmt.Errorf("pod %q not found on the Simplify server", name)
+	}
+	pod := &pods[idx]
+
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	apps = slices.DeleteFunc(apps, func(a core.Application) bool { return a.PodID != pod.ID })
+
+	return kube.PodObjects(pod, apps)
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var appLinkCmd = &cobra.Command{
+	Use:   "link FROM TO",
+	Short: "Record that one application depends on or connects to another",
+	Long: `Link two applications. With --type depends_on (the default) the
+reconciler deploys TO before FROM; connects_to only shows on the service
+map. Dependency cycles are refused.`,
+	Example: `  simplify app link web api
+  simplify app link api cache --type connects_to`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppLink,
+}
+
+var appUnlinkCmd = &cobra.Command{
+	Use:               "unlink FROM TO",
+	Short:             "Remove the link between two applications",
+	Example:           `  simplify app unlink web api`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppUnlink,
+}
+
+var appGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the links between applications",
+	Long: `List the applications in dependency order with the links between
+them, the service map served at /api/v1/graph.`,
+	Example: `  simplify app graph
+  simplify app graph --output json`,
+	Args: cobra.NoArgs,
+	RunE: runAppGraph,
+}
+
+var appLinkType string
+
+func init() {
+	appCmd.AddCommand(appLinkCmd)
+	appCmd.AddCommand(appUnlinkCmd)
+	appCmd.AddCommand(appGraphCmd)
+
+	for _, cmd := range []*cobra.Command{appLinkCmd, appUnlinkCmd} {
+		cmd.Flags().StringVar(&appLinkType, "type", core.LinkDependsOn, "Link type: depends_on or connects_to")
+	}
+}
+
+// applicationIDs returns the IDs of the named applications
+func applicationIDs(ctx context.Context, client *apiClient, names ...string) ([]string, error) {
+	apps, err := client.ListApplications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing applications: %w", err)
+	}
+
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		id := ""
+		for i := range apps {
+			if apps[i].Name == name {
+				id = apps[i].ID
+				break
+			}
+		}
+		if id == "" {
+			return nil, fmt.Errorf("application %q not found", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func runAppLink(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ids, err := applicationIDs(ctx, client, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	link := core.Link{From: ids[0], To: ids[1], Type: appLinkType}
+	if _, err := client.CreateLink(ctx, &link); err != nil {
+		return fmt.Errorf("failed to link applications: %w", err)
+	}
+	fmt.Printf("%s %s %s\n", args[0], appLinkType, args[1])
+	return nil
+}
+
+func runAppUnlink(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ids, err := applicationIDs(ctx, client, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	links, err := client.ListLinks(ctx, ids[0])
+	if err != nil {
+		return fmt.Errorf("failed to list links: %w", err)
+	}
+	for i := range links {
+		l := &links[i]
+		if l.From != ids[0] || l.To != ids[1] || l.Type != appLinkType {
+			continue
+		}
+		if err := client.DeleteLink(ctx, l.ID); err != nil {
+			return fmt.Errorf("failed to unlink applications: %w", err)
+		}
+		fmt.Printf("Removed link %s %s %s\n", args[0], appLinkType, args[1])
+		return nil
+	}
+	return fmt.Errorf("%s has no %s link to %s", args[0], appLinkType, args[1])
+}
+
+func runAppGraph(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	graph, err := client.GetGraph(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get graph: %w", err)
+	}
+	names := make(map[string]string, len(graph.Nodes))
+	for i := range graph.Nodes {
+		names[graph.Nodes[i].ID] = graph.Nodes[i].Name
+	}
+
+	return printOutput(graph, func(out io.Writer) error {
+		if len(graph.Nodes) == 0 {
+			fmt.Fprintln(out, "No applications found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "APPLICATION\tDEPENDS ON\tCONNECTS TO")
+		for i := range graph.Nodes {
+			n := &graph.Nodes[i]
+			var dependsOn, connectsTo []string
+			for j := range graph.Links {
+				l := &graph.Links[j]
+				if l.From != n.ID {
+					continue
+				}
+				if l.Type == core.LinkDependsOn {
+					dependsOn = append(dependsOn, names[l.To])
+				} else {
+					connectsTo = append(connectsTo, names[l.To])
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", n.Name, formatNames(dependsOn), formatNames(connectsTo))
+		}
+		return w.Flush()
+	})
+}
+
+// formatNames joins names with commas, or returns "-" for none
+func formatNames(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",")
+}
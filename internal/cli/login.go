@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store an API token for the current context",
+	Long: `Store an API token for the current context (or the one selected with
+--context). The token is read from --token, from stdin when --token-stdin is
+set, or prompted for interactively. Tokens are API keys, created with
+'simplify token create' (on the server's host, with --user, for the first
+one). The server is asked which user the token belongs to before it is
+saved, so an unknown or expired token is refused.`,
+	Example: `  simplify login
+  simplify --context prod login --token s3cr3t
+  echo "$SIMPLIFY_TOKEN" | simplify login --token-stdin`,
+	Args: cobra.NoArgs,
+	RunE: runLogin,
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API token for the current context",
+	Args:  cobra.NoArgs,
+	RunE:  runLogout,
+}
+
+var (
+	loginToken      string
+	loginTokenStdin bool
+	loginNoVerify   bool
+)
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().StringVar(&loginToken, "token", "", "API token")
+	loginCmd.Flags().BoolVar(&loginTokenStdin, "token-stdin", false, "Read the API token from stdin")
+	loginCmd.Flags().BoolVar(&loginNoVerify, "no-verify", false, "Save the token without contacting the server")
+	loginCmd.MarkFlagsMutuallyExclusive("token", "token-stdin")
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	active, err := cfg.Active(contextOverride)
+	if err != nil {
+		return err
+	}
+
+	token, err := readLoginToken(active.Name)
+	if err != nil {
+		return err
+	}
+
+	updated := *active
+	updated.Token = token
+
+	username := ""
+	if !loginNoVerify {
+		if username, err = verifyToken(ctx, token); err != nil {
+			return err
+		}
+	}
+
+	if err := cfg.Set(updated); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	if username != "" {
+		fmt.Printf("Logged in to context %s as %s\n", updated.Name, username)
+		return nil
+	}
+	fmt.Printf("Login succeeded for context %s\n", updated.Name)
+	return nil
+}
+
+func runLogout(cmd *cobra.Command, args []string) error {
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+
+	active, err := cfg.Active(contextOverride)
+	if err != nil {
+		return err
+	}
+
+	if active.Token == "" {
+		fmt.Printf("Not logged in to context %s\n", active.Name)
+		return nil
+	}
+
+	updated := *active
+	updated.Token = ""
+	if err := cfg.Set(updated); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed credentials for context %s\n", updated.Name)
+	return nil
+}
+
+// readLoginToken obtains the token from the flags, stdin or an interactive prompt
+func readLoginToken(contextName string) (string, error) {
+	var token string
+
+	switch {
+	case loginToken != "":
+		token = loginToken
+	case loginTokenStdin:
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read token from stdin: %w", err)
+		}
+		token = line
+	default:
+		fd := int(os.Stdin.Fd()) //nolint:gosec // file descriptors fit in int
+		if !term.IsTerminal(fd) {
+			return "", fmt.Errorf("no token given: use --token or --token-stdin when not running interactively")
+		}
+		fmt.Fprintf(os.Stderr, "Token for context %s: ", contextName)
+		data, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token: %w", err)
+		}
+		token = string(data)
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("token must not be empty")
+	}
+	return token, nil
+}
+
+// verifyToken asks the active context's server which user token belongs
+// to, and returns the username
+func verifyToken(ctx context.Context, token string) (string, error) {
+	client, err := newAPIClient()
+	if err != nil {
+		return "", err
+	}
+	client = client.WithToken(token)
+
+	me, err := client.WhoAmI(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify token against %s: %w", client.BaseURL(), err)
+	}
+	return me.User.Username, nil
+}
@@ -3,54 +3,87 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/logger"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var logsCmd = &cobra.Command{
-	Use:   "logs [container]",
+	Use:   "logs [container...]",
 	Short: "Fetch container logs",
-	Long:  `Fetch logs from a container. Use --follow to stream logs in real-time.`,
+	Long: `Fetch logs from one or more containers. Use --follow to stream logs in real-time.
+
+With several containers (or --app), lines are interleaved as they arrive and
+prefixed with the container name, like docker-compose does.`,
 	Example: `  simplify logs web
   simplify logs web --follow
-  simplify logs web --tail 100`,
-	Args: cobra.ExactArgs(1),
-	RunE: getContainerLogs,
+  simplify logs web --tail 100
+  simplify logs web api worker -f
+  simplify logs --app shop -f`,
+	ValidArgsFunction: completeNames(0, listContainerNames),
+	RunE:              getContainerLogs,
 }
 
 var (
-	followLogs bool
-	tailLines  string
+	followLogs   bool
+	tailLines    string
+	logsApp      string
+	logsNoPrefix bool
+	logsNoColor  bool
 )
 
+// logColors are the ANSI colors cycled through for container prefixes
+var logColors = []string{"\x1b[36m", "\x1b[33m", "\x1b[32m", "\x1b[35m", "\x1b[34m", "\x1b[91m", "\x1b[96m", "\x1b[93m"}
+
 func init() {
 	rootCmd.AddCommand(logsCmd)
 
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Follow log output")
 	logsCmd.Flags().StringVarP(&tailLines, "tail", "n", "", "Number of lines to show from the end")
+	logsCmd.Flags().StringVar(&logsApp, "app", "", "Include every container of an application (replicas and pod sidecars)")
+	logsCmd.Flags().BoolVar(&logsNoPrefix, "no-prefix", false, "Do not prefix lines with the container name")
+	logsCmd.Flags().BoolVar(&logsNoColor, "no-color", false, "Do not color the container name prefixes")
 }
 
 func getContainerLogs(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	name := args[0]
+
+	if len(args) == 0 && logsApp == "" {
+		return fmt.Errorf("specify at least one container or --app")
+	}
 
 	logger.DebugCtx(ctx, "Getting container logs",
-		"name", name,
+		"names", args,
+		"app", logsApp,
 		"follow", followLogs,
 		"tail", tailLines,
 	)
 
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to connect to Podman", "error", err)
 		return fmt.Errorf("failed to connect to Podman: %w", err)
 	}
 
+	names := args
+	if logsApp != "" {
+		appNames, err := appContainerNames(ctx, client, logsApp)
+		if err != nil {
+			return err
+		}
+		names = append(names, appNames...)
+	}
+	names = uniqueStrings(names)
+
 	// Handle Ctrl+C gracefully
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -62,14 +95,126 @@ func getContainerLogs(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	if err := client.Logs(ctx, name, followLogs, tailLines); err != nil {
-		if ctx.Err() != nil {
-			// User canceled with Ctrl+C
-			return nil
-		}
-		logger.ErrorCtx(ctx, "Failed to get logs", "name", name, "error", err)
-		return fmt.Errorf("failed to get logs: %w", err)
+	prefixed := !logsNoPrefix && (len(names) > 1 || logsApp != "")
+	colored := prefixed && !logsNoColor && term.IsTerminal(int(os.Stdout.Fd())) //nolint:gosec // file descriptors fit in int
+	out := newLogPrinter(os.Stdout, names, prefixed, colored)
+
+	opts := container.LogOptions{
+		Follow:     followLogs,
+		Tail:       tailLines,
+		Timestamps: true,
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = client.StreamLogs(ctx, name, opts, func(line container.LogLine) error {
+				out.print(name, line.String())
+				return nil
+			})
+		}()
 	}
+	wg.Wait()
 
+	if ctx.Err() != nil {
+		// User canceled with Ctrl+C
+		return nil
+	}
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			logger.ErrorCtx(ctx, "Failed to get logs", "name", names[i], "error", err)
+			failed++
+		}
+	}
+	if failed == 1 && len(names) == 1 {
+		return fmt.Errorf("failed to get logs: %w", errs[0])
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to get logs for %d container(s)", failed)
+	}
 	return nil
 }
+
+// appContainerNames returns the containers of an application: its replicas
+// plus any other containers sharing their pods (sidecars)
+func appContainerNames(ctx context.Context, client *container.Client, app string) ([]string, error) {
+	containers, err := client.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	pods := make(map[string]bool)
+	var names []string
+	for i := range containers {
+		if containers[i].Labels["simplify.app.name"] == app {
+			names = append(names, containers[i].Name)
+			if containers[i].PodID != "" {
+				pods[containers[i].PodID] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no containers found for application %q", app)
+	}
+
+	for i := range containers {
+		c := &containers[i]
+		if c.PodID != "" && pods[c.PodID] && !strings.HasSuffix(c.Name, "-infra") {
+			names = append(names, c.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// uniqueStrings removes duplicates while keeping the first occurrence order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// logPrinter serializes log lines from concurrent streams, adding name prefixes
+type logPrinter struct {
+	w        io.Writer
+	prefixes map[string]string
+	mu       sync.Mutex
+}
+
+func newLogPrinter(w io.Writer, names []string, prefixed, colored bool) *logPrinter {
+	p := &logPrinter{w: w, prefixes: make(map[string]string, len(names))}
+	if !prefixed {
+		return p
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for i, name := range names {
+		prefix := fmt.Sprintf("%-*s | ", width, name)
+		if colored {
+			prefix = logColors[i%len(logColors)] + prefix + ansiReset
+		}
+		p.prefixes[name] = prefix
+	}
+	return p
+}
+
+func (p *logPrinter) print(name, text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w, p.prefixes[name]+text)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var appMetricsCmd = &cobra.Command{
+	Use:   "metrics NAME",
+	Short: "Show an application's CPU, memory and network usage over time",
+	Long: `Show the resource usage of an application's running replicas, summed
+over the replicas, as sampled by the server.
+
+Long ranges are averaged into wider steps; use --step to choose the width.`,
+	Example: `  simplify app metrics web
+  simplify app metrics web --range 24h --step 1h
+  simplify app metrics web --range 15m -o json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE:              runAppMetrics,
+}
+
+var (
+	metricsRange time.Duration
+	metricsStep  time.Duration
+)
+
+func init() {
+	appCmd.AddCommand(appMetricsCmd)
+
+	appMetricsCmd.Flags().DurationVar(&metricsRange, "range", time.Hour, "How far back to show")
+	appMetricsCmd.Flags().DurationVar(&metricsStep, "step", 0, "Average samples over this period (default: chosen from --range)")
+}
+
+func runAppMetrics(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	app, err := findApplicationByName(ctx, client, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find application: %w", err)
+	}
+	if app == nil {
+		return errors.NewNotFoundError("application", args[0])
+	}
+
+	result, err := client.GetMetrics(ctx, app.ID, metricsRange, metricsStep)
+	if err != nil {
+		return fmt.Errorf("failed to get metrics: %w", err)
+	}
+
+	return printOutput(result, func(out io.Writer) error {
+		if len(result.Samples) == 0 {
+			fmt.Fprintf(out, "No samples in the last %s\n", result.Range)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "TIME\tCPU\tMEMORY\tNET IN\tNET OUT\tREPLICAS")
+		for i := range result.Samples {
+			s := &result.Samples[i]
+			fmt.Fprintf(w, "%s\t%.1f%%\t%s\t%s/s\t%s/s\t%d\n",
+				s.Time.Local().Format("2006-01-02 15:04:05"), s.CPUPercent,
+				formatBytes(int64(s.MemoryBytes)), //nolint:gosec // memory usage fits in int64
+				formatBytes(int64(s.NetRxRate)), formatBytes(int64(s.NetTxRate)), s.Replicas)
+		}
+		return w.Flush()
+	})
+}
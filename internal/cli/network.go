@@ -3,7 +3,9 @@ package cli
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
+	"slices"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/AkMo3/simplify/internal/container"
@@ -31,22 +33,56 @@ var networkCreateCmd = &cobra.Command{
 }
 
 var networkRmCmd = &cobra.Command{
-	Use:   "rm [name]",
-	Short: "Remove a network",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runNetworkRm,
+	Use:               "rm [name]",
+	Short:             "Remove a network",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNames(1, listNetworkNames),
+	RunE:              runNetworkRm,
+}
+
+var networkInspectCmd = &cobra.Command{
+	Use:               "inspect [name]",
+	Short:             "Show a network and its attached containers",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNames(1, listNetworkNames),
+	RunE:              runNetworkInspect,
+}
+
+var networkConnectCmd = &cobra.Command{
+	Use:               "connect [network] [container]",
+	Short:             "Attach a container to a network",
+	Example:           `  simplify network connect backend web`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeNetworkThenContainer,
+	RunE:              runNetworkConnect,
+}
+
+var networkDisconnectCmd = &cobra.Command{
+	Use:               "disconnect [network] [container]",
+	Short:             "Detach a container from a network",
+	Example:           `  simplify network disconnect backend web`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeNetworkThenContainer,
+	RunE:              runNetworkDisconnect,
 }
 
+var networkDisconnectForce bool
+
 func init() {
 	rootCmd.AddCommand(networkCmd)
 	networkCmd.AddCommand(networkListCmd)
 	networkCmd.AddCommand(networkCreateCmd)
 	networkCmd.AddCommand(networkRmCmd)
+	networkCmd.AddCommand(networkInspectCmd)
+	networkCmd.AddCommand(networkConnectCmd)
+	networkCmd.AddCommand(networkDisconnectCmd)
+
+	networkDisconnectCmd.Flags().BoolVarP(&networkDisconnectForce, "force", "f", false, "Force the disconnection")
 }
 
 func runNetworkList(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
@@ -56,18 +92,19 @@ func runNetworkList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list networks: %w", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tDRIVER\tSUBNET\tCREATED")
-	for _, n := range networks {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", n.ID[:12], n.Name, n.Driver, n.Subnet, n.Created.Format("2006-01-02 15:04:05"))
-	}
-	w.Flush()
-	return nil
+	return printOutput(networks, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tDRIVER\tSUBNET\tCREATED")
+		for _, n := range networks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", n.ID[:12], n.Name, n.Driver, n.Subnet, n.Created.Format("2006-01-02 15:04:05"))
+		}
+		return w.Flush()
+	})
 }
 
 func runNetworkCreate(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
@@ -83,11 +120,15 @@ func runNetworkCreate(cmd *cobra.Command, args []string) error {
 
 func runNetworkRm(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
 
+	if err := confirmNetworkRemoval(ctx, client, args[0]); err != nil {
+		return err
+	}
+
 	if err := client.RemoveNetwork(ctx, args[0]); err != nil {
 		return fmt.Errorf("failed to remove network: %w", err)
 	}
@@ -95,3 +136,106 @@ func runNetworkRm(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Network %s removed\n", args[0])
 	return nil
 }
+
+func runNetworkInspect(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to container engine: %w", err)
+	}
+
+	details, err := client.InspectNetwork(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to inspect network: %w", err)
+	}
+
+	return printOutput(details, func(out io.Writer) error {
+		fmt.Fprintf(out, "ID:      %s\n", details.ID)
+		fmt.Fprintf(out, "Name:    %s\n", details.Name)
+		fmt.Fprintf(out, "Driver:  %s\n", details.Driver)
+		fmt.Fprintf(out, "Subnet:  %s\n", details.Subnet)
+		fmt.Fprintf(out, "Created: %s\n", details.Created.Format("2006-01-02 15:04:05"))
+
+		if len(details.Containers) == 0 {
+			fmt.Fprintln(out, "Containers: none")
+			return nil
+		}
+
+		fmt.Fprintln(out, "Containers:")
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "  ID\tNAME\tIP\tMAC")
+		for _, c := range details.Containers {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.ID, c.Name, c.IPAddress, c.MACAddress)
+		}
+		return w.Flush()
+	})
+}
+
+func runNetworkConnect(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to container engine: %w", err)
+	}
+
+	if err := client.ConnectNetwork(ctx, args[0], args[1]); err != nil {
+		return fmt.Errorf("failed to connect container: %w", err)
+	}
+
+	fmt.Printf("Container %s connected to network %s\n", args[1], args[0])
+	return nil
+}
+
+func runNetworkDisconnect(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to container engine: %w", err)
+	}
+
+	if err := client.DisconnectNetwork(ctx, args[0], args[1], networkDisconnectForce); err != nil {
+		return fmt.Errorf("failed to disconnect container: %w", err)
+	}
+
+	fmt.Printf("Container %s disconnected from network %s\n", args[1], args[0])
+	return nil
+}
+
+// completeNetworkThenContainer completes a network name, then a container name
+func completeNetworkThenContainer(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeNames(1, listNetworkNames)(cmd, args, toComplete)
+	case 1:
+		return completeNames(2, listContainerNames)(cmd, args, toComplete)
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// confirmNetworkRemoval asks before removing a network that running containers are attached to
+func confirmNetworkRemoval(ctx context.Context, client *container.Client, name string) error {
+	containers, err := client.List(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var attached []string
+	for i := range containers {
+		if slices.Contains(containers[i].Networks, name) {
+			attached = append(attached, containers[i].Name)
+		}
+	}
+	if len(attached) == 0 {
+		return nil
+	}
+
+	ok, err := confirm(fmt.Sprintf("Network %s is used by %s. Remove it?", name, strings.Join(attached, ", ")))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("aborted")
+	}
+	return nil
+}
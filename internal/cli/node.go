@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage the nodes running the Simplify agent",
+	Long: `List and remove the hosts registered with 'simplify agent'.
+
+Applications with a node selector run on a ready node whose labels match
+it; those without one run on the server itself.`,
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List nodes",
+	Args:    cobra.NoArgs,
+	RunE:    runNodeList,
+}
+
+var nodeRmCmd = &cobra.Command{
+	Use:   "rm [name]",
+	Short: "Remove a node and schedule its applications elsewhere",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNodeRm,
+}
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	nodeCmd.AddCommand(nodeRmCmd)
+}
+
+func runNodeList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No nodes found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSTATUS\tCPUS\tMEMORY\tAPPLICATIONS\tLABELS\tLAST HEARTBEAT")
+		for i := range list {
+			n := &list[i]
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%s\t%s\n", n.Name, n.Status, n.Capacity.CPUs,
+				formatBytes(n.Capacity.MemoryBytes), len(n.AppStatus), formatLabels(n.Labels), formatCreatedTime(n.LastHeartbeat))
+		}
+		return w.Flush()
+	})
+}
+
+func runNodeRm(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := client.ListNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var node *core.Node
+	for i := range nodes {
+		if nodes[i].Name == args[0] {
+			node = &nodes[i]
+		}
+	}
+	if node == nil {
+		return errors.NewNotFoundError("node", args[0])
+	}
+
+	ok, err := confirm(fmt.Sprintf("Remove node %s? Its applications move to other matching nodes.", node.Name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	if err := client.DeleteNode(ctx, node.ID); err != nil {
+		return fmt.Errorf("failed to remove node: %w", err)
+	}
+
+	fmt.Printf("Node %s removed\n", node.Name)
+	return nil
+}
+
+// formatLabels renders labels as sorted KEY=VALUE pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats supported by the --output flag
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var (
+	outputFormat   string
+	formatTemplate string
+)
+
+// validateOutputFlags checks the global output flags before a command runs
+func validateOutputFlags() error {
+	switch outputFormat {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("invalid output format %q: must be %q, %q or %q",
+			outputFormat, outputTable, outputJSON, outputYAML)
+	}
+
+	if formatTemplate != "" {
+		if _, err := template.New("format").Parse(formatTemplate); err != nil {
+			return fmt.Errorf("invalid --format template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// isMachineOutput reports whether the user asked for JSON, YAML or template output
+func isMachineOutput() bool {
+	return formatTemplate != "" || outputFormat != outputTable
+}
+
+// printOutput renders data according to the --output and --format flags.
+// The table callback is only invoked for the default human-readable format.
+func printOutput(data any, table func(w io.Writer) error) error {
+	return writeOutput(os.Stdout, data, table)
+}
+
+// writeOutput is the writer-based implementation of printOutput
+func writeOutput(w io.Writer, data any, table func(w io.Writer) error) error {
+	if formatTemplate != "" {
+		return writeTemplate(w, formatTemplate, data)
+	}
+
+	switch outputFormat {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	case outputYAML:
+		return writeYAML(w, data)
+	default:
+		return table(w)
+	}
+}
+
+// writeYAML encodes data as YAML using its JSON field names, so both
+// machine-readable formats expose the same keys.
+func writeYAML(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	return enc.Close()
+}
+
+// writeTemplate executes a Go template against data. Slices are expanded so the
+// template is applied to each element, one per line.
+func writeTemplate(w io.Writer, text string, data any) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice {
+		if err := tmpl.Execute(w, data); err != nil {
+			return fmt.Errorf("executing --format template: %w", err)
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := tmpl.Execute(w, v.Index(i).Interface()); err != nil {
+			return fmt.Errorf("executing --format template: %w", err)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
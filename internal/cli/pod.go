@@ -3,10 +3,15 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
 	"github.com/AkMo3/simplify/internal/logger"
 	"github.com/spf13/cobra"
 )
@@ -20,14 +25,17 @@ var podCmd = &cobra.Command{
 var podListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List pods",
-	RunE:  runPodList,
+	Example: `  simplify pod list
+  simplify pod list --watch`,
+	RunE: runPodList,
 }
 
 var podInspectCmd = &cobra.Command{
-	Use:   "inspect [name]",
-	Short: "Inspect a pod",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runPodInspect,
+	Use:               "inspect [name]",
+	Short:             "Inspect a pod",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNames(1, listPodNames),
+	RunE:              runPodInspect,
 }
 
 var podCreateCmd = &cobra.Command{
@@ -37,16 +45,50 @@ var podCreateCmd = &cobra.Command{
 }
 
 var podRmCmd = &cobra.Command{
-	Use:   "rm [name]",
-	Short: "Remove a pod",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runPodRm,
+	Use:               "rm [name]",
+	Short:             "Remove a pod",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNames(1, listPodNames),
+	RunE:              runPodRm,
+}
+
+var podStartCmd = &cobra.Command{
+	Use:               "start [name...]",
+	Short:             "Start pods and their containers",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeNames(0, listPodNames),
+	RunE:              runPodStart,
+}
+
+var podStopCmd = &cobra.Command{
+	Use:               "stop [name...]",
+	Short:             "Stop pods and their containers",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeNames(0, listPodNames),
+	RunE:              runPodStop,
+}
+
+var podUpdateCmd = &cobra.Command{
+	Use:   "update [name]",
+	Short: "Change the published ports of a pod on the Simplify server",
+	Long: `Replace the published ports stored for a pod. Ports cannot be changed on
+a running pod, so the reconciler recreates the pod and its member
+containers with the new ports.`,
+	Example: `  simplify pod update web-pod --port 8080:80 --port 8443:443`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPodUpdate,
 }
 
 var (
-	podName  string
-	podPorts []string
-	podForce bool
+	podName        string
+	podPorts       []string
+	podInfraImage  string
+	podHostname    string
+	podNetwork     string
+	podForce       bool
+	podWatch       time.Duration
+	podStopTimeout int
+	podUpdatePorts []string
 )
 
 func init() {
@@ -55,40 +97,59 @@ func init() {
 	podCmd.AddCommand(podInspectCmd)
 	podCmd.AddCommand(podCreateCmd)
 	podCmd.AddCommand(podRmCmd)
+	podCmd.AddCommand(podStartCmd)
+	podCmd.AddCommand(podStopCmd)
+	podCmd.AddCommand(podUpdateCmd)
+
+	// List flags
+	addWatchFlag(podListCmd, &podWatch)
 
 	// Create flags
 	podCreateCmd.Flags().StringVarP(&podName, "name", "n", "", "Pod name (required)")
 	podCreateCmd.Flags().StringSliceVarP(&podPorts, "port", "p", []string{}, "Port mappings (host:container)")
+	podCreateCmd.Flags().StringVar(&podInfraImage, "infra-image", "", "Image of the infra container (default: the engine's)")
+	podCreateCmd.Flags().StringVar(&podHostname, "hostname", "", "Hostname shared by the pod's containers (default: the pod name)")
+	podCreateCmd.Flags().StringVar(&podNetwork, "network", "", "Network the pod joins")
 	_ = podCreateCmd.MarkFlagRequired("name") //nolint:errcheck // flag registration rarely fails
 
 	// Rm flags
 	podRmCmd.Flags().BoolVarP(&podForce, "force", "f", false, "Force removal")
+
+	// Stop flags
+	podStopCmd.Flags().IntVarP(&podStopTimeout, "timeout", "t", 10, "Seconds to wait before force killing")
+
+	// Update flags
+	podUpdateCmd.Flags().StringSliceVarP(&podUpdatePorts, "port", "p", []string{}, "Port mappings (host:container), replacing the current ones")
+	_ = podUpdateCmd.MarkFlagRequired("port") //nolint:errcheck // flag registration rarely fails
 }
 
 func runPodList(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
 
-	pods, err := client.ListPods(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to list pods: %w", err)
-	}
+	return runWatched(ctx, podWatch, "simplify pod list", func(ctx context.Context, out io.Writer) error {
+		pods, err := client.ListPods(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED")
-	for _, p := range pods {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.ID[:12], p.Name, p.Status, p.Created.Format("2006-01-02 15:04:05"))
-	}
-	w.Flush()
-	return nil
+		return writeOutput(out, pods, func(out io.Writer) error {
+			w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED")
+			for _, p := range pods {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.ID[:12], p.Name, p.Status, p.Created.Format("2006-01-02 15:04:05"))
+			}
+			return w.Flush()
+		})
+	})
 }
 
 func runPodInspect(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
@@ -98,16 +159,19 @@ func runPodInspect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to inspect pod: %w", err)
 	}
 
-	fmt.Printf("ID:      %s\n", pod.ID)
-	fmt.Printf("Name:    %s\n", pod.Name)
-	fmt.Printf("Status:  %s\n", pod.Status)
-	fmt.Printf("Created: %s\n", pod.Created)
-	return nil
+	return printOutput(pod, func(out io.Writer) error {
+		fmt.Fprintf(out, "ID:      %s\n", pod.ID)
+		fmt.Fprintf(out, "Name:    %s\n", pod.Name)
+		fmt.Fprintf(out, "Status:  %s\n", pod.Status)
+		fmt.Fprintf(out, "Ports:   %s\n", formatPortMap(pod.Ports))
+		fmt.Fprintf(out, "Created: %s\n", pod.Created)
+		return nil
+	})
 }
 
 func runPodCreate(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
@@ -117,7 +181,12 @@ func runPodCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	id, err := client.CreatePod(ctx, podName, ports)
+	id, err := client.CreatePod(ctx, podName, container.PodOptions{
+		Ports:      ports,
+		InfraImage: podInfraImage,
+		Hostname:   podHostname,
+		Network:    podNetwork,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to create pod: %w", err)
 	}
@@ -128,11 +197,15 @@ func runPodCreate(cmd *cobra.Command, args []string) error {
 
 func runPodRm(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to connect to container engine: %w", err)
 	}
 
+	if err := confirmPodRemoval(ctx, client, args[0]); err != nil {
+		return err
+	}
+
 	if err := client.RemovePod(ctx, args[0], podForce); err != nil {
 		return fmt.Errorf("failed to remove pod: %w", err)
 	}
@@ -140,3 +213,122 @@ func runPodRm(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Pod %s removed\n", args[0])
 	return nil
 }
+
+func runPodStart(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to container engine: %w", err)
+	}
+
+	return forEachPod(args, "started", func(name string) error {
+		return client.StartPod(ctx, name)
+	})
+}
+
+func runPodStop(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to container engine: %w", err)
+	}
+
+	return forEachPod(args, "stopped", func(name string) error {
+		timeout := podStopTimeout
+		return client.StopPod(ctx, name, &timeout)
+	})
+}
+
+// forEachPod applies op to every pod, reporting each outcome
+func forEachPod(names []string, verb string, op func(name string) error) error {
+	failed := 0
+	for _, name := range names {
+		if err := op(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Pod %s %s\n", name, verb)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed on %d pod(s)", failed)
+	}
+	return nil
+}
+
+func runPodUpdate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	name := args[0]
+
+	parsed, err := parsePorts(podUpdatePorts)
+	if err != nil {
+		return err
+	}
+	ports := make(map[string]string, len(parsed))
+	for host, containerPort := range parsed {
+		ports[fmt.Sprintf("%d", host)] = fmt.Sprintf("%d", containerPort)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.ListPods(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	idx := slices.IndexFunc(pods, func(p core.Pod) bool { return p.Name == name || p.ID == name })
+	if idx < 0 {
+		return fmt.Errorf("pod %q not found on the Simplify server", name)
+	}
+
+	pod := pods[idx]
+	pod.Ports = ports
+	if _, err := client.UpdatePod(ctx, &pod); err != nil {
+		return fmt.Errorf("failed to update pod: %w", err)
+	}
+
+	fmt.Printf("Pod %s updated, the reconciler will recreate it with ports %s\n", pod.Name, strings.Join(podUpdatePorts, ", "))
+	return nil
+}
+
+// confirmPodRemoval asks before removing a pod that is running or still has containers
+func confirmPodRemoval(ctx context.Context, client *container.Client, name string) error {
+	pod, err := client.InspectPod(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to inspect pod: %w", err)
+	}
+
+	containers, err := client.List(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var members []string
+	for i := range containers {
+		c := &containers[i]
+		if c.PodID != "" && strings.HasPrefix(c.PodID, pod.ID) && !strings.HasSuffix(c.Name, "-infra") {
+			members = append(members, c.Name)
+		}
+	}
+
+	running := strings.EqualFold(pod.Status, "running")
+	if !running && len(members) == 0 {
+		return nil
+	}
+
+	question := fmt.Sprintf("Pod %s is %s", name, strings.ToLower(pod.Status))
+	if len(members) > 0 {
+		question += fmt.Sprintf(" and has containers %s", strings.Join(members, ", "))
+	}
+	ok, err := confirm(question + ". Remove it?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("aborted")
+	}
+	return nil
+}
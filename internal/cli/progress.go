@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"golang.org/x/term"
+)
+
+// progressBarWidth is the number of cells in a pull progress bar
+const progressBarWidth = 30
+
+// pullProgressPrinter renders image pull progress on stderr. On a terminal
+// a single bar is redrawn in place; otherwise each new status line from the
+// engine is printed so logs still show what the pull is doing.
+type pullProgressPrinter struct {
+	lastStatus string
+	tty        bool
+	drawn      bool
+}
+
+func newPullProgressPrinter() *pullProgressPrinter {
+	return &pullProgressPrinter{
+		tty: term.IsTerminal(int(os.Stderr.Fd())), //nolint:gosec // file descriptors fit in int
+	}
+}
+
+// Update renders one progress report
+func (p *pullProgressPrinter) Update(pr container.PullProgress) {
+	if !p.tty {
+		if pr.Status != p.lastStatus {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", pr.Image, pr.Status)
+			p.lastStatus = pr.Status
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\r\x1b[KPulling %s %s %d/%d layers",
+		truncateString(pr.Image, 40), progressBar(pr.Done, pr.Layers), pr.Done, pr.Layers)
+	p.drawn = true
+}
+
+// Finish ends the progress line so following output starts on a new line
+func (p *pullProgressPrinter) Finish() {
+	if p.drawn {
+		fmt.Fprintln(os.Stderr)
+		p.drawn = false
+	}
+}
+
+// progressBar renders a fixed width bar such as "[=========>          ]"
+func progressBar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * progressBarWidth / total
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	b.WriteString(strings.Repeat("=", filled))
+	if filled < progressBarWidth {
+		b.WriteByte('>')
+		b.WriteString(strings.Repeat(" ", progressBarWidth-filled-1))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
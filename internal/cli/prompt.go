@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// assumeYes is set by the global --yes flag to skip confirmation prompts
+var assumeYes bool
+
+// stdinReader is shared by all prompts so buffered input is never lost
+// between questions
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// isInteractive reports whether stdin is a terminal
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) //nolint:gosec // file descriptors fit in int
+}
+
+// confirm asks a yes/no question on the terminal. It returns true without
+// asking when --yes is set, and refuses when stdin is not a terminal.
+func confirm(question string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+
+	if !isInteractive() {
+		return false, fmt.Errorf("confirmation required: re-run with --yes to proceed non-interactively")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// ask prompts for a line of input, returning def when the answer is empty
+func ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+	}
+
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// splitList splits a comma separated answer into its trimmed, non-empty items
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
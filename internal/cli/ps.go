@@ -3,70 +3,215 @@ package cli
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
+	"slices"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/pkg/client"
 	"github.com/spf13/cobra"
 )
 
 var psCmd = &cobra.Command{
 	Use:   "ps",
 	Short: "List containers",
-	Long:  `List running containers. Use --all to show all containers.`,
+	Long: `List running containers. Use --all to show all containers.
+
+Filters narrow the list. Repeating a filter key matches any of its values,
+different keys must all match. A status filter implies --all.`,
 	Example: `  simplify ps
-  simplify ps --all`,
+  simplify ps --all
+  simplify ps --filter status=exited
+  simplify ps --filter label=simplify.managed=true --filter name='web*'
+  simplify ps -q --filter status=exited | xargs simplify rm
+  simplify ps --output json
+  simplify ps --format '{{.ID}}'
+  simplify ps --watch
+  simplify ps --watch=5s --all`,
 	RunE: listContainers,
 }
 
-var showAll bool
+var (
+	showAll   bool
+	psFilters []string
+	quietPS   bool
+	widePS    bool
+	psWatch   time.Duration
+)
 
 func init() {
 	rootCmd.AddCommand(psCmd)
 
 	psCmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all containers (default shows just running)")
+	psCmd.Flags().StringArrayVar(&psFilters, "filter", nil, "Filter output (label=KEY[=VALUE], status=STATE, name=PATTERN, id=PREFIX)")
+	psCmd.Flags().BoolVarP(&quietPS, "quiet", "q", false, "Only display container IDs")
+	psCmd.Flags().BoolVarP(&widePS, "wide", "w", false, "Do not truncate columns")
+	addWatchFlag(psCmd, &psWatch)
 }
 
 func listContainers(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
 
-	logger.DebugCtx(ctx, "Listing containers", "all", showAll)
+	filters, err := parseContainerFilters(psFilters)
+	if err != nil {
+		return err
+	}
+	all := showAll || filters.needsAll()
+
+	logger.DebugCtx(ctx, "Listing containers", "all", all, "filters", psFilters)
 
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to connect to Podman", "error", err)
 		return fmt.Errorf("failed to connect to Podman: %w", err)
 	}
 
-	containers, err := client.List(ctx, showAll)
+	return runWatched(ctx, psWatch, "simplify ps", func(ctx context.Context, out io.Writer) error {
+		return renderContainers(ctx, client, filters, all, out)
+	})
+}
+
+// renderContainers lists the containers matching filters and writes them to out
+func renderContainers(ctx context.Context, client *container.Client, filters containerFilters, all bool, out io.Writer) error {
+	containers, err := client.List(ctx, all)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to list containers", "error", err)
 		return fmt.Errorf("failed to list containers: %w", err)
 	}
+	containers = filters.apply(containers)
 
-	if len(containers) == 0 {
-		fmt.Println("No containers found")
+	if quietPS {
+		for i := range containers {
+			fmt.Fprintln(out, containers[i].ID)
+		}
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tIMAGE\tSTATUS\tPORTS\tCREATED")
+	podNames := podNamesByID(ctx, client, containers)
+	appHealth, appAlerting := appStateByID(ctx, containers)
+
+	return writeOutput(out, containers, func(out io.Writer) error {
+		if len(containers) == 0 {
+			fmt.Fprintln(out, "No containers found")
+			return nil
+		}
+
+		trunc := func(s string, n int) string {
+			if widePS {
+				return s
+			}
+			return truncateString(s, n)
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tIMAGE\tSTATUS\tHEALTH\tPORTS\tPOD\tNETWORKS\tIP\tCREATED")
+
+		for i := range containers {
+			c := &containers[i]
+			health := c.Health
+			if health == "" {
+				health = appHealth[c.Labels["simplify.app.id"]]
+			}
+			if health == "" {
+				health = "-"
+			}
+			if alerting := appAlerting[c.Labels["simplify.app.id"]]; alerting != "" {
+				health += " (alerting: " + alerting + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.ID,
+				trunc(c.Name, 24),
+				trunc(c.Image, 30),
+				c.Status,
+				health,
+				trunc(formatPortMap(c.Ports), 40),
+				trunc(podNames[c.PodID], 20),
+				trunc(strings.Join(c.Networks, ","), 24),
+				c.IPAddress,
+				formatCreatedTime(c.Created),
+			)
+		}
+
+		return w.Flush()
+	})
+}
+
+// podNamesByID resolves the pod names of the listed containers. Lookup
+// failures only cost the POD column, so they are logged and ignored.
+func podNamesByID(ctx context.Context, client *container.Client, containers []container.ContainerInfo) map[string]string {
+	names := make(map[string]string)
+
+	needed := false
+	for i := range containers {
+		if containers[i].PodID != "" {
+			needed = true
+			break
+		}
+	}
+	if !needed || isMachineOutput() {
+		return names
+	}
+
+	pods, err := client.ListPods(ctx)
+	if err != nil {
+		logger.DebugCtx(ctx, "Failed to list pods", "error", err)
+		return names
+	}
 
 	for i := range containers {
-		c := &containers[i]
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			c.ID,
-			c.Name,
-			truncateString(c.Image, 30),
-			c.Status,
-			c.Ports,
-			formatCreatedTime(c.Created),
-		)
+		id := containers[i].PodID
+		for _, p := range pods {
+			if id != "" && strings.HasPrefix(id, p.ID) {
+				names[id] = p.Name
+			}
+		}
+	}
+	return names
+}
+
+// appStateTimeout bounds how long ps waits for the server's view of the
+// applications
+const appStateTimeout = time.Second
+
+// appStateByID returns the health and the firing alerts the server reports
+// for the applications of the listed containers, by application ID. Http
+// and tcp checks are probed by Simplify rather than podman, and alerts are
+// evaluated by its metrics collector, so only the server knows them.
+// Failures only cost the HEALTH column, so they are logged and ignored.
+func appStateByID(ctx context.Context, containers []container.ContainerInfo) (health, alerting map[string]string) {
+	health = make(map[string]string)
+	alerting = make(map[string]string)
+
+	needed := slices.ContainsFunc(containers, func(c container.ContainerInfo) bool {
+		return c.Labels["simplify.app.id"] != ""
+	})
+	if !needed || isMachineOutput() {
+		return health, alerting
 	}
 
-	return w.Flush()
+	// The listing must not wait on a server that is not running
+	api, err := newAPIClientWith(client.Options{MaxRetries: -1, Timeout: appStateTimeout})
+	if err != nil {
+		logger.DebugCtx(ctx, "Failed to create API client", "error", err)
+		return health, alerting
+	}
+	apps, err := api.ListApplications(ctx)
+	if err != nil {
+		logger.DebugCtx(ctx, "Failed to list applications", "error", err)
+		return health, alerting
+	}
+	for i := range apps {
+		if apps[i].HealthStatus != "" {
+			health[apps[i].ID] = apps[i].HealthStatus
+		}
+		if len(apps[i].Alerting) > 0 {
+			alerting[apps[i].ID] = strings.Join(apps[i].Alerting, ",")
+		}
+	}
+	return health, alerting
 }
 
 func truncateString(s string, maxLen int) string {
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/logger"
@@ -12,46 +13,87 @@ import (
 var rmCmd = &cobra.Command{
 	Use:   "rm [containers...]",
 	Short: "Remove one or more containers",
-	Long:  `Remove one or more containers. Use --force to remove running containers.`,
+	Long: `Remove one or more containers. Use --force to remove running containers.
+
+Containers can be named explicitly, selected with --label, or all
+Simplify-managed containers can be removed with --all. Removing running
+containers with --force, or everything with --all, asks for confirmation
+unless --yes is given.`,
 	Example: `  simplify rm web
   simplify rm web api worker
-  simplify rm --force web`,
-	Args: cobra.MinimumNArgs(1),
-	RunE: removeContainers,
+  simplify rm --force web
+  simplify rm --label simplify.app.name=shop
+  simplify rm --all --force`,
+	ValidArgsFunction: completeNames(0, listContainerNames),
+	RunE:              removeContainers,
 }
 
-var forceRemove bool
+var (
+	forceRemove bool
+	rmAll       bool
+	rmLabels    []string
+)
 
 func init() {
 	rootCmd.AddCommand(rmCmd)
 
 	rmCmd.Flags().BoolVarP(&forceRemove, "force", "f", false, "Force remove running containers")
+	rmCmd.Flags().BoolVarP(&rmAll, "all", "a", false, "Remove all containers managed by Simplify")
+	rmCmd.Flags().StringArrayVarP(&rmLabels, "label", "l", nil, "Remove containers matching a label (KEY or KEY=VALUE)")
 }
 
 func removeContainers(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
 
-	logger.InfoCtx(ctx, "Removing containers", "names", args, "force", forceRemove)
-
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to connect to Podman", "error", err)
 		return fmt.Errorf("failed to connect to Podman: %w", err)
 	}
 
-	var errors []error
-	for _, name := range args {
-		if err := client.Remove(ctx, name, forceRemove); err != nil {
-			logger.ErrorCtx(ctx, "Failed to remove container", "name", name, "error", err)
-			errors = append(errors, fmt.Errorf("%s: %w", name, err))
-		} else {
-			fmt.Printf("Container %s removed\n", name)
+	names, err := selectContainers(ctx, client, args, rmAll, rmLabels, true)
+	if err != nil {
+		return err
+	}
+
+	if err := confirmRemoval(ctx, client, names); err != nil {
+		return err
+	}
+
+	logger.InfoCtx(ctx, "Removing containers", "names", names, "force", forceRemove)
+
+	return runBulk(ctx, names, "removed", func(name string) error {
+		return client.Remove(ctx, name, forceRemove)
+	})
+}
+
+// confirmRemoval asks before force-removing running containers or removing with --all
+func confirmRemoval(ctx context.Context, client *container.Client, names []string) error {
+	var running []string
+	if forceRemove {
+		var err error
+		if running, err = runningAmong(ctx, client, names); err != nil {
+			return err
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to remove %d container(s)", len(errors))
+	var question string
+	switch {
+	case len(running) > 0:
+		question = fmt.Sprintf("Remove %d container(s), including running %s?",
+			len(names), strings.Join(running, ", "))
+	case rmAll:
+		question = fmt.Sprintf("Remove %d container(s) (%s)?", len(names), strings.Join(names, ", "))
+	default:
+		return nil
 	}
 
+	ok, err := confirm(question)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("aborted")
+	}
 	return nil
 }
@@ -3,15 +3,26 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/internal/errors"
 	"github.com/AkMo3/simplify/internal/logger"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
+	cfgFile         string
+	cfgProfile      string
+	serverURL       string
+	contextOverride string
+	logLevel        string
+	logFormat       string
+
+	// configErr holds the config load error, reported before any command
+	// runs except the config subcommands, which must work on broken files
+	configErr error
 
 	// Version information (set via ldflags during build)
 	Version   = "dev"
@@ -34,16 +45,6 @@ Features:
 	SilenceErrors: true,
 }
 
-var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Simplify %s\n", Version)
-		fmt.Printf("  Git commit: %s\n", GitCommit)
-		fmt.Printf("  Build date: %s\n", BuildDate)
-	},
-}
-
 // Execute runs the root command
 func Execute() error {
 	return rootCmd.Execute()
@@ -51,27 +52,77 @@ func Execute() error {
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", config.DefaultConfigPath, "config file path")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return errors.NewInvalidInputErrorWithCause(err.Error(), err)
+	})
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", config.DefaultConfigPath, "config file path (.yaml, .yml, .json or .toml)")
+	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "Config profile merged over the config file, e.g. staging (default $SIMPLIFY_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "", "Simplify API server URL (overrides the current context)")
+	rootCmd.PersistentFlags().StringVar(&contextOverride, "context", "", "Context to use for this command (see 'simplify context')")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "Output format: table, json or yaml")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes for confirmation prompts")
+	rootCmd.PersistentFlags().StringVar(&formatTemplate, "format", "", "Format output using a Go template (e.g. '{{.ID}}')")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn or error (overrides config env)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log format: text, console or json (overrides config env)")
+	_ = rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions( //nolint:errcheck // flag registration rarely fails
+		[]string{outputTable, outputJSON, outputYAML}, cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions( //nolint:errcheck // flag registration rarely fails
+		[]string{"debug", "info", "warn", "error"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions( //nolint:errcheck // flag registration rarely fails
+		[]string{logger.FormatText, logger.FormatConsole, logger.FormatJSON}, cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.RegisterFlagCompletionFunc("context", completeContextNames) //nolint:errcheck // flag registration rarely fails
 
 	// Initialize logger after config is loaded but before command execution
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		return initLogger()
+		if configErr != nil {
+			return fmt.Errorf("failed to load config: %w", configErr)
+		}
+		if err := validateOutputFlags(); err != nil {
+			return err
+		}
+		// Internal errors carry their stack in development, for the API and logs
+		errors.SetStackCapture(config.IsDevelopment())
+		return initLogger(cmd)
 	}
-
-	rootCmd.AddCommand(versionCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if err := config.Load(cfgFile); err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		os.Exit(1)
-	}
+	config.SetProfile(cfgProfile)
+	configErr = config.Load(cfgFile)
 }
 
-// initLogger initializes the logger
-func initLogger() error {
-	return logger.Init()
+// initLogger initializes the logger. --log-level and --log-format take
+// precedence over the logging.level and logging.format config keys, which
+// can themselves be set with SIMPLIFY_LOG_LEVEL and SIMPLIFY_LOG_FORMAT.
+// Shell completion requests log nothing, as the shell reads their stdout,
+// and other commands except the server and agent log to stderr, so their
+// output can be piped or parsed with -o json.
+func initLogger(cmd *cobra.Command) error {
+	cfg := config.Get()
+	level := logLevel
+	if level == "" {
+		level = cfg.Logging.Level
+	}
+	format := logFormat
+	if format == "" {
+		format = cfg.Logging.Format
+	}
+
+	opts := logger.Options{Level: level, Format: format, Components: cfg.LogLevels()}
+	switch {
+	case cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd:
+		opts.Output = io.Discard
+	case cmd != serverCmd && cmd != agentCmd:
+		opts.Output = os.Stderr
+	}
+	if cfg.LogSampling() {
+		opts.Sampling = &logger.SamplingOptions{
+			Initial:    cfg.Logging.Sampling.Initial,
+			Thereafter: cfg.Logging.Sampling.Thereafter,
+		}
+	}
+	return logger.InitWithOptions(opts)
 }
 
 // GetConfigPath returns the config file path from flag
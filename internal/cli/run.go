@@ -45,7 +45,7 @@ func runContainer(cmd *cobra.Command, args []string) error {
 		"image", imageName,
 	)
 
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to connect to Podman", "error", err)
 		return fmt.Errorf("failed to connect to Podman: %w", err)
@@ -62,7 +62,16 @@ func runContainer(cmd *cobra.Command, args []string) error {
 		"env_count", len(envVars),
 	)
 
-	id, err := client.Run(ctx, containerName, imageName, ports, envVars, nil, "", "")
+	// Pull up front so large images show progress instead of blocking silently
+	progress := newPullProgressPrinter()
+	_, err = client.EnsureImage(ctx, imageName, progress.Update)
+	progress.Finish()
+	if err != nil {
+		logger.ErrorCtx(ctx, "Failed to pull image", "error", err)
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+
+	id, err := client.Run(ctx, containerName, container.RunOptions{Image: imageName, Ports: ports, Env: envVars})
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to run container", "error", err)
 		return fmt.Errorf("failed to run container: %w", err)
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets",
+	Long: `Manage secrets stored by the Simplify server.
+
+Secrets are encrypted at rest and their values are never shown again once
+set. Applications reference them by name, for example with
+'simplify deploy --secret db-password=DB_PASSWORD', instead of carrying
+credentials in plain environment variables.
+
+Secret values are read from a file, from stdin, or prompted for without
+echo. They are never accepted as command-line arguments, which would leak
+them into shell history and process listings.`,
+}
+
+var secretCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a secret",
+	Example: `  simplify secret create db-password
+  simplify secret create tls-key --file ./server.key
+  printf '%s' "$TOKEN" | simplify secret create api-token --replace`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretCreate,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List secrets",
+	Args:    cobra.NoArgs,
+	RunE:    runSecretList,
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:               "rm [name...]",
+	Short:             "Remove secrets",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeSecretNames,
+	RunE:              runSecretRm,
+}
+
+var (
+	secretFile    string
+	secretReplace bool
+)
+
+// maxSecretSize is the largest value the server accepts for a secret
+const maxSecretSize = 512 * 1024
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretCreateCmd)
+	secretCmd.AddCommand(secretListCmd)
+	secretCmd.AddCommand(secretRmCmd)
+
+	secretCreateCmd.Flags().StringVarP(&secretFile, "file", "f", "", "Read the value from a file ('-' for stdin)")
+	secretCreateCmd.Flags().BoolVar(&secretReplace, "replace", false, "Replace the value of an existing secret with the same name")
+}
+
+func runSecretCreate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	name := args[0]
+
+	value, err := readSecretValue(name)
+	if err != nil {
+		return err
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	existing, err := findSecretByName(ctx, client, name)
+	if err != nil {
+		return err
+	}
+
+	body := core.Secret{Name: name, Value: string(value)}
+	switch {
+	case existing == nil:
+		saved, err := client.CreateSecret(ctx, &body)
+		if err != nil {
+			return fmt.Errorf("failed to create secret: %w", err)
+		}
+		fmt.Printf("Secret %s created (ID: %s)\n", name, truncateString(saved.ID, 12))
+	case secretReplace:
+		body.ID = existing.ID
+		if _, err := client.UpdateSecret(ctx, &body); err != nil {
+			return fmt.Errorf("failed to update secret: %w", err)
+		}
+		fmt.Printf("Secret %s updated, applications using it will be redeployed\n", name)
+	default:
+		return errors.NewAlreadyExistsError("secret", name)
+	}
+	return nil
+}
+
+// findSecretByName returns the secret with the given name, or nil when
+// there is none
+func findSecretByName(ctx context.Context, client *apiClient, name string) (*core.Secret, error) {
+	secrets, err := client.ListSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing secrets: %w", err)
+	}
+	for i := range secrets {
+		if secrets[i].Name == name {
+			return &secrets[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// readSecretValue reads a secret from --file, from piped stdin, or from a
+// prompt that does not echo
+func readSecretValue(name string) ([]byte, error) {
+	var r io.Reader
+	switch {
+	case secretFile == "-":
+		r = os.Stdin
+	case secretFile != "":
+		f, err := os.Open(secretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open secret file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	case !isInteractive():
+		r = os.Stdin
+	default:
+		fmt.Fprintf(os.Stderr, "Value for secret %s: ", name)
+		data, err := term.ReadPassword(int(os.Stdin.Fd())) //nolint:gosec // file descriptors fit in int
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret: %w", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	value, err := io.ReadAll(io.LimitReader(r, maxSecretSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret: %w", err)
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("secret value must not be empty")
+	}
+	if len(value) > maxSecretSize {
+		return nil, fmt.Errorf("secret value exceeds %s", formatBytes(maxSecretSize))
+	}
+	return value, nil
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListSecrets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No secrets found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tCREATED\tUPDATED")
+		for i := range list {
+			s := &list[i]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", truncateString(s.ID, 12), s.Name, formatCreatedTime(s.CreatedAt), formatCreatedTime(s.UpdatedAt))
+		}
+		return w.Flush()
+	})
+}
+
+func runSecretRm(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ok, err := confirm(fmt.Sprintf("Remove %d secret(s)?", len(args)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	failed := 0
+	for _, name := range args {
+		if err := removeSecret(ctx, client, name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Secret %s removed\n", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d secret(s)", failed)
+	}
+	return nil
+}
+
+// removeSecret deletes the secret with the given name. The server refuses
+// while an application still references it.
+func removeSecret(ctx context.Context, client *apiClient, name string) error {
+	secret, err := findSecretByName(ctx, client, name)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return errors.NewNotFoundError("secret", name)
+	}
+	return client.DeleteSecret(ctx, secret.ID)
+}
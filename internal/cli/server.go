@@ -2,17 +2,28 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"reflect"
 	"syscall"
+	"time"
 
+	"github.com/AkMo3/simplify/internal/backup"
+	"github.com/AkMo3/simplify/internal/build"
 	"github.com/AkMo3/simplify/internal/config"
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/metrics"
+	"github.com/AkMo3/simplify/internal/notify"
 	"github.com/AkMo3/simplify/internal/permissions"
 	"github.com/AkMo3/simplify/internal/reconciler"
+	"github.com/AkMo3/simplify/internal/reporting"
 	"github.com/AkMo3/simplify/internal/server"
 	"github.com/AkMo3/simplify/internal/store"
+	"github.com/AkMo3/simplify/internal/tracing"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +34,11 @@ var serverCmd = &cobra.Command{
 for managing applications, teams, projects, and environments.
 
 The server also runs the reconciliation loop which ensures containers
-match the desired state in the database.`,
+match the desired state in the database.
+
+The log level and reconcile interval are reloaded from the config file when
+it changes or when the server receives SIGHUP. Other settings take effect
+on restart.`,
 	RunE: runServer,
 }
 
@@ -41,6 +56,23 @@ func runServer(cmd *cobra.Command, args []string) error {
 		"database", cfg.Database.Path,
 	)
 
+	// Also write logs to a rotated file when configured
+	if f := cfg.Logging.File; f.Path != "" {
+		if err := permissions.EnsureFileWritable(f.Path); err != nil {
+			logger.Error("Log file path not writable", "path", f.Path, "error", err)
+			return err
+		}
+		if err := logger.EnableFile(logger.FileOptions{
+			Path:       f.Path,
+			MaxSize:    f.MaxSize,
+			MaxBackups: f.MaxBackups,
+			MaxAge:     f.MaxAge,
+		}); err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logger.Info("Writing logs to file", "path", f.Path)
+	}
+
 	// Ensure database directory exists and is writable
 	if err := permissions.EnsureFileWritable(cfg.Database.Path); err != nil {
 		logger.Error("Database path not writable", "path", cfg.Database.Path, "error", err)
@@ -59,17 +91,18 @@ func runServer(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	// Initialize Podman client
-	ctx := context.Background()
-	podman, err := container.NewClient(ctx)
+	// Load or generate the key that encrypts secrets at rest
+	key, err := store.LoadSecretKey(cfg.SecretKeyPath())
 	if err != nil {
-		logger.Error("Failed to connect to Podman", "error", err)
+		logger.Error("Failed to load secret key", "path", cfg.SecretKeyPath(), "error", err)
+		return err
+	}
+	if err := s.SetSecretKey(key); err != nil {
 		return err
 	}
-	logger.Info("Connected to Podman")
 
 	// Create context that cancels on SIGINT/SIGTERM
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Handle shutdown signals
@@ -81,16 +114,142 @@ func runServer(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	// Start reconciler in background
-	worker := reconciler.New(s, podman)
-	go worker.Start(ctx)
-	logger.Info("Reconciler started")
+	// Connect to Podman. When its socket is not up yet, as is common during
+	// boot, the server starts without it: the API serves stored data
+	// read-only and /readyz reports the outage until a retry connects.
+	podman := container.NewDeferred()
+	client, err := connectPodman(ctx)
+	if err != nil {
+		logger.Warn("Podman unavailable, serving read-only until it is up", "error", err)
+		podman.SetReason(err)
+	} else {
+		podman.Connect(client)
+		logger.Info("Connected to Podman")
+	}
+
+	// Export traces of API requests, store transactions, engine calls and
+	// reconcile cycles when enabled
+	var engine container.ContainerManager = podman
+	if cfg.Tracing.Enabled {
+		shutdown, err := tracing.Init(ctx, tracing.Options{
+			Endpoint:       cfg.Tracing.Endpoint,
+			Insecure:       cfg.Tracing.Insecure,
+			SampleRatio:    cfg.Tracing.SampleRatio,
+			ServiceName:    "simplify",
+			ServiceVersion: Version,
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdown(flushCtx); err != nil {
+				logger.Error("Failed to flush traces", "error", err)
+			}
+		}()
+		engine = container.WithTracing(podman)
+		logger.Info("Tracing enabled", "endpoint", cfg.Tracing.Endpoint, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
+	// Send panics, internal API errors and reconcile failures to the error
+	// tracker when a DSN is configured
+	if cfg.Reporting.DSN != "" {
+		flush, err := reporting.Init(reporting.Options{
+			DSN:         cfg.Reporting.DSN,
+			Environment: cfg.Env,
+			Release:     "simplify@" + Version,
+			SampleRate:  cfg.Reporting.SampleRate,
+		})
+		if err != nil {
+			return err
+		}
+		defer flush(5 * time.Second)
+		logger.Info("Error reporting enabled", "sample_rate", cfg.Reporting.SampleRate)
+	}
+
+	// Route deploy, crash loop, health and alert events to the configured notifiers
+	dispatcher, err := notify.New(&cfg.Notifications)
+	if err != nil {
+		return fmt.Errorf("invalid notifications config: %w", err)
+	}
+
+	worker := reconciler.New(s, engine)
+	worker.SetPublisher(dispatcher)
+	worker.SetInterval(cfg.ReconcileInterval())
+	worker.SetConfigDir(filepath.Join(filepath.Dir(cfg.Database.Path), "configs"))
+
+	// Apply config changes on SIGHUP or when the file is edited
+	go watchConfig(ctx, worker, dispatcher)
 
 	// Create and start HTTP server
-	srv := server.New(cfg, s, podman)
+	srv := server.New(cfg, s, engine)
+	srv.SetReconcileTrigger(worker.Trigger)
+	srv.SetPublisher(dispatcher)
+	srv.SetEngineCheck(podman.Connected)
+
+	// Build images from project repositories on the local engine
+	srv.SetBuilder(build.New(podman, build.Options{
+		GitImage:    cfg.Build.GitImage,
+		PodmanImage: cfg.Build.PodmanImage,
+		PackImage:   cfg.Build.PackImage,
+		Builder:     cfg.Build.Builder,
+		Socket:      container.SocketPath(),
+	}))
+
+	// Back up the database and volumes to the configured target, on the
+	// schedule when there is one and through the API
+	var (
+		backups  *backup.Runner
+		schedule *backup.Schedule
+	)
+	if cfg.Backup.Enabled() {
+		backups = backup.New(s, podman, backup.NewTarget(&cfg.Backup), backup.Options{
+			Volumes: cfg.Backup.Volumes,
+			Retain:  cfg.Backup.Retain,
+		})
+		srv.SetBackups(backups)
+		if cfg.Backup.Schedule != "" {
+			if schedule, err = backup.ParseSchedule(cfg.Backup.Schedule); err != nil {
+				return fmt.Errorf("invalid backup schedule: %w", err)
+			}
+		}
+	}
+
+	// The reconciler, metrics and scheduled backups need the engine, so they
+	// start once it is connected
+	startEngineWork := func() {
+		go worker.Start(ctx)
+		logger.Info("Reconciler started")
+
+		// Sample container resource usage for the metrics API and alerts,
+		// from the engine itself since stats are not part of ContainerManager
+		if cfg.Metrics.Interval > 0 {
+			collector := metrics.New(s, podman, time.Duration(cfg.Metrics.Interval)*time.Second, cfg.MetricsRetention())
+			collector.SetPublisher(dispatcher)
+			go collector.Start(ctx)
+		}
+
+		if schedule != nil {
+			go backups.Start(ctx, schedule)
+		}
+	}
+	if podman.Connected() {
+		startEngineWork()
+	} else {
+		go func() {
+			client, err := waitForPodman(ctx, podman)
+			if err != nil {
+				return
+			}
+			podman.Connect(client)
+			logger.Info("Connected to Podman, leaving read-only mode")
+			startEngineWork()
+		}()
+	}
 
 	logger.Info("HTTP server starting",
-		"addr", cfg.Server.Port,
+		"addr", cfg.ListenAddr(),
 		"healthz", "/healthz",
 		"readyz", "/readyz",
 		"api", "/api/v1",
@@ -105,3 +264,136 @@ func runServer(cmd *cobra.Command, args []string) error {
 	logger.Info("Simplify server stopped")
 	return nil
 }
+
+// Backoff between attempts to connect to Podman at start up
+const (
+	podmanRetryWait    = time.Second
+	podmanRetryMaxWait = 30 * time.Second
+)
+
+// connectPodman checks the Podman socket, for a clearer error than a failed
+// connection, and connects to it
+func connectPodman(ctx context.Context) (*container.Client, error) {
+	if err := permissions.CheckPodmanSocket(container.SocketPath()); err != nil {
+		return nil, err
+	}
+	return container.Shared(ctx, "")
+}
+
+// waitForPodman retries connectPodman with exponential backoff until it
+// succeeds or ctx is canceled. Each failure is recorded as the reason the
+// engine is unavailable.
+func waitForPodman(ctx context.Context, podman *container.Deferred) (*container.Client, error) {
+	wait := podmanRetryWait
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+
+		client, err := connectPodman(ctx)
+		if err == nil {
+			return client, nil
+		}
+		podman.SetReason(err)
+		wait = min(wait*2, podmanRetryMaxWait)
+		logger.Warn("Podman still unavailable", "retry_in", wait.String(), "error", err)
+	}
+}
+
+// configReloadDelay groups the burst of file events editors produce for one save
+const configReloadDelay = 500 * time.Millisecond
+
+// watchConfig reloads the configuration on SIGHUP and when the config file
+// changes, until ctx is canceled
+func watchConfig(ctx context.Context, worker *reconciler.Worker, dispatcher *notify.Dispatcher) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	// Watch the directory rather than the file, since editors often replace
+	// the file on save, which would drop a watch on the file itself
+	path := filepath.Clean(config.Path())
+	var (
+		events <-chan fsnotify.Event
+		errs   <-chan error
+	)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Warn("Config file watching unavailable, reload with SIGHUP", "error", err)
+	} else {
+		defer watcher.Close()
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			logger.Warn("Config file watching unavailable, reload with SIGHUP", "path", path, "error", err)
+		} else {
+			events, errs = watcher.Events, watcher.Errors
+		}
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			logger.Info("Received SIGHUP, reloading configuration")
+			reloadConfig(worker, dispatcher)
+		case ev := <-events:
+			if filepath.Clean(ev.Name) == path && ev.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				debounce = time.After(configReloadDelay)
+			}
+		case err := <-errs:
+			logger.Warn("Config file watch error", "error", err)
+		case <-debounce:
+			debounce = nil
+			logger.Info("Config file changed, reloading configuration", "path", path)
+			reloadConfig(worker, dispatcher)
+		}
+	}
+}
+
+// reloadConfig re-reads the config file and applies the settings that can
+// change at runtime. An invalid file leaves the running configuration alone.
+func reloadConfig(worker *reconciler.Worker, dispatcher *notify.Dispatcher) {
+	prev, cfg, err := config.Reload()
+	if err != nil {
+		logger.Error("Config reload failed, keeping the current configuration", "error", err)
+		return
+	}
+
+	// --log-level takes precedence over the config file
+	if logLevel == "" {
+		if err := logger.SetLevel(cfg.Logging.Level); err != nil {
+			logger.Error("Failed to apply log level", "error", err)
+		}
+	}
+
+	if err := logger.SetComponentLevels(cfg.LogLevels()); err != nil {
+		logger.Error("Failed to apply module log levels", "error", err)
+	}
+
+	if cfg.ReconcileInterval() != prev.ReconcileInterval() {
+		worker.SetInterval(cfg.ReconcileInterval())
+	}
+
+	if !reflect.DeepEqual(cfg.Notifications, prev.Notifications) {
+		if err := dispatcher.Configure(&cfg.Notifications); err != nil {
+			logger.Error("Failed to apply notifications, keeping the current notifiers", "error", err)
+		}
+	}
+
+	if cfg.Env != prev.Env || !reflect.DeepEqual(cfg.Server, prev.Server) || cfg.Database != prev.Database ||
+		cfg.Logging.Format != prev.Logging.Format || cfg.Logging.File != prev.Logging.File ||
+		cfg.LogSampling() != prev.LogSampling() ||
+		cfg.Logging.Sampling.Initial != prev.Logging.Sampling.Initial ||
+		cfg.Logging.Sampling.Thereafter != prev.Logging.Sampling.Thereafter ||
+		cfg.Reporting != prev.Reporting {
+		logger.Warn("Changes to env, server, database, log format, log file, sampling and error reporting settings take effect on restart")
+	}
+
+	logger.Info("Configuration reloaded",
+		"log_level", cfg.Logging.Level,
+		"reconcile_interval", cfg.ReconcileInterval().String(),
+	)
+}
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Manage groups of applications deployed together",
+	Long: `Manage stacks: groups of applications that are deployed, stopped and
+removed as one unit, like a compose project.
+
+The applications of a stack share a network named <stack>_default, so they
+reach each other by name, and are started in the order they are declared.`,
+}
+
+var stackUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Create or update a stack from a manifest and start it",
+	Long: `Apply the applications declared in a manifest, group them into a stack
+and start them. The stack is named after the file unless --name is given.
+Running it again updates the applications and the stack's membership.`,
+	Example: `  simplify stack up -f shop.yaml
+  simplify stack up -f manifests/ --name shop`,
+	Args: cobra.NoArgs,
+	RunE: runStackUp,
+}
+
+var stackDownCmd = &cobra.Command{
+	Use:   "down [name]",
+	Short: "Stop every application of a stack",
+	Long: `Stop every application of a stack. The containers are removed; the
+applications, their volumes and the stack itself are kept, so 'simplify
+stack up' starts them again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStackDown,
+}
+
+var stackListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List stacks",
+	Args:    cobra.NoArgs,
+	RunE:    runStackList,
+}
+
+var stackRmCmd = &cobra.Command{
+	Use:   "rm [name]",
+	Short: "Remove a stack with its applications and network",
+	Long: `Remove a stack together with its applications and network. Volumes and
+secrets are kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStackRm,
+}
+
+var (
+	stackFile    string
+	stackName    string
+	stackTimeout time.Duration
+	stackNoWait  bool
+)
+
+func init() {
+	rootCmd.AddCommand(stackCmd)
+	stackCmd.AddCommand(stackUpCmd)
+	stackCmd.AddCommand(stackDownCmd)
+	stackCmd.AddCommand(stackListCmd)
+	stackCmd.AddCommand(stackRmCmd)
+
+	stackUpCmd.Flags().StringVarP(&stackFile, "file", "f", "", "Manifest file or directory (required)")
+	stackUpCmd.Flags().StringVar(&stackName, "name", "", "Stack name (default: the file or directory name)")
+	stackUpCmd.Flags().DurationVar(&stackTimeout, "timeout", 5*time.Minute, "How long to wait for the applications to run")
+	stackUpCmd.Flags().BoolVar(&stackNoWait, "no-wait", false, "Return immediately without waiting for the applications to run")
+	_ = stackUpCmd.MarkFlagRequired("file") //nolint:errcheck // flag registration rarely fails
+}
+
+func runStackUp(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	m, err := manifest.Load(stackFile)
+	if err != nil {
+		return err
+	}
+	if len(m.Applications) == 0 {
+		return fmt.Errorf("no applications found in %s", stackFile)
+	}
+
+	name := stackName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(stackFile), filepath.Ext(stackFile))
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	stack, err := findStackByName(ctx, client, name)
+	if err != nil {
+		return err
+	}
+
+	// Keep existing members on the stack network rather than moving them
+	// off it and back again
+	if stack != nil {
+		for i := range m.Applications {
+			a := &m.Applications[i]
+			if a.NetworkID == "" && a.PodID == "" {
+				a.NetworkID = stack.NetworkID
+			}
+		}
+	}
+
+	if err := applyManifest(ctx, client, m); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(m.Applications))
+	for i := range m.Applications {
+		ids = append(ids, m.Applications[i].ID)
+	}
+
+	var saved *core.Stack
+	if stack == nil {
+		body := core.Stack{Name: name, ApplicationIDs: ids}
+		if saved, err = client.CreateStack(ctx, &body); err != nil {
+			return fmt.Errorf("failed to create stack: %w", err)
+		}
+	} else {
+		body := core.Stack{ID: stack.ID, ApplicationIDs: ids}
+		if saved, err = client.UpdateStack(ctx, &body); err != nil {
+			return fmt.Errorf("failed to update stack: %w", err)
+		}
+	}
+	if saved, err = client.DeployStack(ctx, saved.ID); err != nil {
+		return fmt.Errorf("failed to start stack: %w", err)
+	}
+
+	fmt.Printf("Stack %s is up (%d applications)\n", saved.Name, len(saved.ApplicationIDs))
+	if stackNoWait {
+		return nil
+	}
+
+	for i := range m.Applications {
+		running, err := waitForApplication(ctx, client, m.Applications[i].ID, stackTimeout)
+		if err != nil {
+			return fmt.Errorf("application %s: %w", m.Applications[i].Name, err)
+		}
+		fmt.Printf("Application %s is running\n", running.Name)
+	}
+	return nil
+}
+
+func runStackDown(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	stack, err := requireStack(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.StopStack(ctx, stack.ID); err != nil {
+		return fmt.Errorf("failed to stop stack: %w", err)
+	}
+
+	fmt.Printf("Stack %s stopped\n", stack.Name)
+	return nil
+}
+
+func runStackList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListStacks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list stacks: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No stacks found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tAPPLICATIONS\tSTATE\tCREATED")
+		for i := range list {
+			st := &list[i]
+			state := "up"
+			if st.Stopped {
+				state = "stopped"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", st.Name, len(st.ApplicationIDs), state, formatCreatedTime(st.CreatedAt))
+		}
+		return w.Flush()
+	})
+}
+
+func runStackRm(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	stack, err := requireStack(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	ok, err := confirm(fmt.Sprintf("Remove stack %s and its %d application(s)?", stack.Name, len(stack.ApplicationIDs)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	if err := client.DeleteStack(ctx, stack.ID); err != nil {
+		return fmt.Errorf("failed to remove stack: %w", err)
+	}
+
+	fmt.Printf("Stack %s removed\n", stack.Name)
+	return nil
+}
+
+// findStackByName returns the stack with the given name, or nil if none exists
+func findStackByName(ctx context.Context, client *apiClient, name string) (*core.Stack, error) {
+	stacks, err := client.ListStacks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing stacks: %w", err)
+	}
+	for i := range stacks {
+		if stacks[i].Name == name {
+			return &stacks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// requireStack returns the stack with the given name, or NotFoundError
+func requireStack(ctx context.Context, client *apiClient, name string) (*core.Stack, error) {
+	stack, err := findStackByName(ctx, client, name)
+	if err != nil {
+		return nil, err
+	}
+	if stack == nil {
+		return nil, errors.NewNotFoundError("stack", name)
+	}
+	return stack, nil
+}
@@ -4,47 +4,57 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/logger"
 	"github.com/spf13/cobra"
 )
 
 var stopCmd = &cobra.Command{
-	Use:   "stop [container]",
-	Short: "Stop a running container",
-	Long:  `Stop a running container gracefully with optional timeout.`,
+	Use:   "stop [containers...]",
+	Short: "Stop one or more running containers",
+	Long: `Stop running containers gracefully with optional timeout.
+
+Containers can be named explicitly, selected with --label, or all
+Simplify-managed containers can be stopped with --all.`,
 	Example: `  simplify stop web
-  simplify stop web --timeout 30`,
-	Args: cobra.ExactArgs(1),
-	RunE: stopContainer,
+  simplify stop web api --timeout 30
+  simplify stop --label simplify.app.name=shop
+  simplify stop --all`,
+	ValidArgsFunction: completeNames(0, listContainerNames),
+	RunE:              stopContainers,
 }
 
-var stopTimeout uint
+var (
+	stopTimeout uint
+	stopAll     bool
+	stopLabels  []string
+)
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
 
 	stopCmd.Flags().UintVarP(&stopTimeout, "timeout", "t", 10, "Seconds to wait before force killing")
+	stopCmd.Flags().BoolVarP(&stopAll, "all", "a", false, "Stop all containers managed by Simplify")
+	stopCmd.Flags().StringArrayVarP(&stopLabels, "label", "l", nil, "Stop containers matching a label (KEY or KEY=VALUE)")
 }
 
-func stopContainer(cmd *cobra.Command, args []string) error {
+func stopContainers(cmd *cobra.Command, args []string) error {
 	ctx := logger.WithOperationID(context.Background())
-	name := args[0]
-
-	logger.InfoCtx(ctx, "Stopping container", "name", name, "timeout", stopTimeout)
 
-	client, err := container.NewClient(ctx)
+	client, err := newEngineClient(ctx)
 	if err != nil {
 		logger.ErrorCtx(ctx, "Failed to connect to Podman", "error", err)
 		return fmt.Errorf("failed to connect to Podman: %w", err)
 	}
 
-	timeout := stopTimeout
-	if err := client.Stop(ctx, name, &timeout); err != nil {
-		logger.ErrorCtx(ctx, "Failed to stop container", "name", name, "error", err)
-		return fmt.Errorf("failed to stop container: %w", err)
+	names, err := selectContainers(ctx, client, args, stopAll, stopLabels, false)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Container %s stopped\n", name)
-	return nil
+	logger.InfoCtx(ctx, "Stopping containers", "names", names, "timeout", stopTimeout)
+
+	return runBulk(ctx, names, "stopped", func(name string) error {
+		timeout := stopTimeout
+		return client.Stop(ctx, name, &timeout)
+	})
 }
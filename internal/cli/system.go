@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var systemCmd = &cobra.Command{
+	Use:   "system",
+	Short: "Manage the Simplify host",
+}
+
+var systemPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unused Simplify resources",
+	Long: `Remove resources that are no longer in use:
+
+  - stopped containers managed by Simplify
+  - dangling (untagged) images
+  - Simplify networks with no containers attached
+
+Networks that are still defined on the Simplify server are kept. If the
+server cannot be reached, networks are left untouched.`,
+	Example: `  simplify system prune --dry-run
+  simplify system prune --yes`,
+	Args: cobra.NoArgs,
+	RunE: runSystemPrune,
+}
+
+var systemLogLevelCmd = &cobra.Command{
+	Use:   "log-level [LEVEL]",
+	Short: "Show or change the log level of the running server",
+	Long: `Show the log level of the running Simplify server, or change it without a
+restart when LEVEL is given (debug, info, warn or error).
+
+The change lasts until the server restarts or reloads its config file.
+Set logging.level in the config file to make it permanent.`,
+	Example: `  simplify system log-level
+  simplify system log-level debug`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"debug", "info", "warn", "error"},
+	RunE:      runSystemLogLevel,
+}
+
+var systemDfCmd = &cobra.Command{
+	Use:   "df",
+	Short: "Show the disk space used by images, containers and volumes",
+	Long: `Show how much disk space the server's Podman uses for images, containers
+and volumes, and how much removing the unused ones would reclaim.
+See simplify system prune.`,
+	Example: `  simplify system df
+  simplify system df -o json`,
+	Args: cobra.NoArgs,
+	RunE: runSystemDf,
+}
+
+var systemInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the Podman version and the capacity of the host",
+	Long: `Show the version and storage driver of the server's Podman, and the
+operating system, CPUs, memory and storage of the host it runs on.`,
+	Example: `  simplify system info
+  simplify system info -o json`,
+	Args: cobra.NoArgs,
+	RunE: runSystemInfo,
+}
+
+var pruneDryRun bool
+
+// stoppedStates are the container states considered safe to prune
+var stoppedStates = map[string]bool{
+	"created": true,
+	"exited":  true,
+	"stopped": true,
+	"dead":    true,
+}
+
+func init() {
+	rootCmd.AddCommand(systemCmd)
+	systemCmd.AddCommand(systemPruneCmd)
+	systemCmd.AddCommand(systemLogLevelCmd)
+	systemCmd.AddCommand(systemDfCmd)
+	systemCmd.AddCommand(systemInfoCmd)
+
+	systemPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without removing anything")
+}
+
+// prunePlan lists the resources selected for removal
+type prunePlan struct {
+	containers []container.ContainerInfo
+	images     []container.DanglingImage
+	networks   []container.NetworkInfo
+}
+
+func (p *prunePlan) empty() bool {
+	return len(p.containers) == 0 && len(p.images) == 0 && len(p.networks) == 0
+}
+
+func runSystemPrune(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	client, err := newEngineClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Podman: %w", err)
+	}
+
+	plan, err := planPrune(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if plan.empty() {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	printPrunePlan(plan)
+	if pruneDryRun {
+		return nil
+	}
+
+	ok, err := confirm("Remove these resources?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	failed := 0
+	for i := range plan.containers {
+		if err := client.Remove(ctx, plan.containers[i].Name, false); err != nil {
+			logger.ErrorCtx(ctx, "Failed to remove container", "name", plan.containers[i].Name, "error", err)
+			failed++
+		}
+	}
+
+	var reclaimed int64
+	for _, img := range plan.images {
+		if err := client.RemoveImage(ctx, img.ID); err != nil {
+			logger.ErrorCtx(ctx, "Failed to remove image", "id", img.ID, "error", err)
+			failed++
+			continue
+		}
+		reclaimed += img.Size
+	}
+
+	// Networks go last so the containers removed above no longer hold them
+	for _, n := range plan.networks {
+		if err := client.RemoveNetwork(ctx, n.Name); err != nil {
+			logger.ErrorCtx(ctx, "Failed to remove network", "name", n.Name, "error", err)
+			failed++
+		}
+	}
+
+	fmt.Printf("Reclaimed %s\n", formatBytes(reclaimed))
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d resource(s)", failed)
+	}
+	return nil
+}
+
+// planPrune collects the resources that prune would remove
+func planPrune(ctx context.Context, client *container.Client) (*prunePlan, error) {
+	plan := &prunePlan{}
+
+	containers, err := client.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	inUse := make(map[string]bool)
+	for i := range containers {
+		c := &containers[i]
+		for _, n := range c.Networks {
+			inUse[n] = true
+		}
+		if c.Labels[container.LabelManaged] == "true" && stoppedStates[c.Status] {
+			plan.containers = append(plan.containers, *c)
+		}
+	}
+
+	plan.images, err = client.ListDanglingImages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	declared, err := declaredNetworks(ctx)
+	if err != nil {
+		logger.WarnCtx(ctx, "Skipping network prune, Simplify server unavailable", "error", err)
+		return plan, nil
+	}
+
+	networks, err := client.ListNetworks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list networks: %w", err)
+	}
+	for _, n := range networks {
+		if n.Labels[container.LabelManaged] == "true" && !inUse[n.Name] && !declared[n.Name] {
+			plan.networks = append(plan.networks, n)
+		}
+	}
+
+	return plan, nil
+}
+
+// declaredNetworks returns the names of networks defined on the Simplify server
+func declaredNetworks(ctx context.Context) (map[string]bool, error) {
+	api, err := newAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	networks, err := api.ListNetworks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(networks))
+	for i := range networks {
+		names[networks[i].Name] = true
+	}
+	return names, nil
+}
+
+// printPrunePlan lists the resources selected for removal
+func printPrunePlan(plan *prunePlan) {
+	verb := "Will remove"
+	if pruneDryRun {
+		verb = "Would remove"
+	}
+
+	for i := range plan.containers {
+		fmt.Printf("%s container %s (%s)\n", verb, plan.containers[i].Name, plan.containers[i].Status)
+	}
+
+	var size int64
+	for _, img := range plan.images {
+		fmt.Printf("%s image %s (%s)\n", verb, img.ID, formatBytes(img.Size))
+		size += img.Size
+	}
+
+	for _, n := range plan.networks {
+		fmt.Printf("%s network %s\n", verb, n.Name)
+	}
+
+	fmt.Printf("Total: %d container(s), %d image(s) (%s), %d network(s)\n",
+		len(plan.containers), len(plan.images), formatBytes(size), len(plan.networks))
+}
+
+// formatBytes renders a byte count using binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func runSystemLogLevel(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		level, err := client.GetLogLevel(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get log level: %w", err)
+		}
+		fmt.Println(level)
+		return nil
+	}
+
+	level, err := client.SetLogLevel(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to set log level: %w", err)
+	}
+	fmt.Printf("Server log level set to %s\n", level)
+	return nil
+}
+
+func runSystemDf(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	usage, err := client.DiskUsage(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get disk usage: %w", err)
+	}
+
+	return printOutput(usage, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+		row := func(name string, total, active int, size, reclaimable int64) {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", name, total, active, formatBytes(size), formatBytes(reclaimable))
+		}
+		row("Images", usage.Images.Total, usage.Images.Active, usage.Images.Size, usage.Images.Reclaimable)
+		row("Containers", usage.Containers.Total, usage.Containers.Active, usage.Containers.Size, usage.Containers.Reclaimable)
+		row("Volumes", usage.Volumes.Total, usage.Volumes.Active, usage.Volumes.Size, usage.Volumes.Reclaimable)
+		return w.Flush()
+	})
+}
+
+func runSystemInfo(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	info, err := client.SystemInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get system info: %w", err)
+	}
+
+	return printOutput(info, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "Podman:\t%s\n", info.Version)
+		fmt.Fprintf(w, "Storage driver:\t%s\n", info.StorageDriver)
+		fmt.Fprintf(w, "Hostname:\t%s\n", info.Hostname)
+		if info.Distribution != "" {
+			fmt.Fprintf(w, "Distribution:\t%s\n", info.Distribution)
+		}
+		fmt.Fprintf(w, "OS:\t%s/%s\n", info.OS, info.Arch)
+		fmt.Fprintf(w, "Kernel:\t%s\n", info.Kernel)
+		fmt.Fprintf(w, "CPUs:\t%d\n", info.CPUs)
+		fmt.Fprintf(w, "Memory:\t%s free of %s\n", formatBytes(info.MemFree), formatBytes(info.MemTotal))
+		if info.StorageSize > 0 {
+			fmt.Fprintf(w, "Storage:\t%s used of %s\n", formatBytes(int64(info.StorageUsed)), formatBytes(int64(info.StorageSize)))
+		}
+		return w.Flush()
+	})
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var systemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Run the Simplify server as a systemd service",
+	Long: `Install, remove and inspect a systemd unit that runs 'simplify server'.
+
+Run as a regular user, a user unit is installed next to the rootless
+podman.socket in ~/.config/systemd/user. Run as root, a system unit is
+installed in /etc/systemd/system and uses the rootful podman.socket.`,
+}
+
+var systemdInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Write and enable the systemd unit",
+	Example: `  simplify systemd install
+  simplify systemd install --env SIMPLIFY_ENV=production --force
+  simplify systemd install --no-start`,
+	Args: cobra.NoArgs,
+	RunE: runSystemdInstall,
+}
+
+var systemdUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop, disable and remove the systemd unit",
+	Args:  cobra.NoArgs,
+	RunE:  runSystemdUninstall,
+}
+
+var systemdStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the systemd unit is installed, enabled and running",
+	Args:  cobra.NoArgs,
+	RunE:  runSystemdStatus,
+}
+
+var (
+	systemdEnv     []string
+	systemdForce   bool
+	systemdNoStart bool
+)
+
+// systemdUnitName is the name of the installed unit
+const systemdUnitName = "simplify.service"
+
+// systemdUnitTemplate renders the unit for 'simplify server'
+var systemdUnitTemplate = template.Must(template.New("unit").Parse(`# Installed by 'simplify systemd install'
+[Unit]
+Description=Simplify PaaS server
+Documentation=https://github.com/AkMo3/simplify
+Wants=network-online.target
+After=network-online.target podman.socket
+Requires=podman.socket
+
+[Service]
+Type=simple
+{{- range .Env}}
+Environment={{.}}
+{{- end}}
+ExecStart={{.ExecStart}}
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy={{.WantedBy}}
+`))
+
+// systemdScope describes where units live for the current user
+type systemdScope struct {
+	dir        string
+	socket     string
+	wantedBy   string
+	journalctl string
+	user       bool
+}
+
+func init() {
+	rootCmd.AddCommand(systemdCmd)
+	systemdCmd.AddCommand(systemdInstallCmd)
+	systemdCmd.AddCommand(systemdUninstallCmd)
+	systemdCmd.AddCommand(systemdStatusCmd)
+
+	systemdInstallCmd.Flags().StringArrayVarP(&systemdEnv, "env", "e", nil, "Extra environment for the server (KEY=VALUE)")
+	systemdInstallCmd.Flags().BoolVar(&systemdForce, "force", false, "Overwrite an existing unit file")
+	systemdInstallCmd.Flags().BoolVar(&systemdNoStart, "no-start", false, "Enable the unit without starting it now")
+}
+
+// currentSystemdScope picks user units for regular users and system units for root
+func currentSystemdScope() (*systemdScope, error) {
+	if os.Geteuid() == 0 {
+		return &systemdScope{
+			dir:        "/etc/systemd/system",
+			socket:     "/run/podman/podman.sock",
+			wantedBy:   "multi-user.target",
+			journalctl: "journalctl -u simplify",
+		}, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return &systemdScope{
+		dir:        filepath.Join(configDir, "systemd", "user"),
+		socket:     "%t/podman/podman.sock", // %t is the user's runtime directory
+		wantedBy:   "default.target",
+		journalctl: "journalctl --user -u simplify",
+		user:       true,
+	}, nil
+}
+
+func (s *systemdScope) unitPath() string {
+	return filepath.Join(s.dir, systemdUnitName)
+}
+
+// systemctl builds a systemctl command in this scope
+func (s *systemdScope) systemctl(ctx context.Context, args ...string) *exec.Cmd {
+	if s.user {
+		args = append([]string{"--user"}, args...)
+	}
+	return exec.CommandContext(ctx, "systemctl", args...)
+}
+
+// run invokes systemctl, passing its output through
+func (s *systemdScope) run(ctx context.Context, args ...string) error {
+	cmd := s.systemctl(ctx, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// query runs a systemctl query such as is-active and returns its answer.
+// These commands exit non-zero for negative answers, so the exit status is ignored.
+func (s *systemdScope) query(ctx context.Context, args ...string) string {
+	out, _ := s.systemctl(ctx, args...).Output() //nolint:errcheck // the answer is on stdout either way
+	answer := strings.TrimSpace(string(out))
+	if answer == "" {
+		return "unknown"
+	}
+	return answer
+}
+
+func runSystemdInstall(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	scope, err := currentSystemdScope()
+	if err != nil {
+		return err
+	}
+
+	unit, err := renderSystemdUnit(scope)
+	if err != nil {
+		return err
+	}
+
+	path := scope.unitPath()
+	if _, err := os.Stat(path); err == nil && !systemdForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	if err := os.MkdirAll(scope.dir, 0o755); err != nil { //nolint:gosec // systemd unit directories are world readable
+		return fmt.Errorf("failed to create %s: %w", scope.dir, err)
+	}
+	if err := os.WriteFile(path, unit, 0o644); err != nil { //nolint:gosec // unit files are world readable
+		return fmt.Errorf("failed to write unit file: %w", err)
+	}
+	fmt.Printf("Wrote %s\n", path)
+
+	if err := scope.run(ctx, "daemon-reload"); err != nil {
+		return err
+	}
+	enable := []string{"enable", "podman.socket", systemdUnitName}
+	if !systemdNoStart {
+		enable = []string{"enable", "--now", "podman.socket", systemdUnitName}
+	}
+	if err := scope.run(ctx, enable...); err != nil {
+		return err
+	}
+
+	if systemdNoStart {
+		fmt.Println("Simplify service enabled, it will start on next boot")
+	} else {
+		fmt.Println("Simplify service enabled and started")
+	}
+	if scope.user {
+		fmt.Println("To keep it running after you log out, run: loginctl enable-linger $USER")
+	}
+	fmt.Printf("Logs: %s -f\n", scope.journalctl)
+	return nil
+}
+
+// renderSystemdUnit builds the unit for the current binary, config file and environment
+func renderSystemdUnit(scope *systemdScope) ([]byte, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the simplify binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return nil, fmt.Errorf("failed to resolve the simplify binary: %w", err)
+	}
+
+	cfgPath, err := filepath.Abs(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+
+	env := []string{"PODMAN_SOCK=" + scope.socket}
+	if cfg := config.Get(); cfg != nil && cfg.Env != "" {
+		env = append(env, "SIMPLIFY_ENV="+cfg.Env)
+	}
+	for _, e := range systemdEnv {
+		if key, _, ok := strings.Cut(e, "="); !ok || key == "" {
+			return nil, fmt.Errorf("invalid environment variable %q (use KEY=VALUE format)", e)
+		}
+		env = append(env, e)
+	}
+	for i, e := range env {
+		env[i] = systemdQuote(e)
+	}
+
+	var buf bytes.Buffer
+	err = systemdUnitTemplate.Execute(&buf, map[string]any{
+		"Env":       env,
+		"ExecStart": systemdQuote(exe) + " --config " + systemdQuote(cfgPath) + " server",
+		"WantedBy":  scope.wantedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render unit file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// systemdQuote quotes a value for a unit file when it contains spaces or quotes
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func runSystemdUninstall(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	scope, err := currentSystemdScope()
+	if err != nil {
+		return err
+	}
+
+	path := scope.unitPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("Simplify service is not installed (%s not found)\n", path)
+		return nil
+	}
+
+	if err := scope.run(ctx, "disable", "--now", systemdUnitName); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove unit file: %w", err)
+	}
+	if err := scope.run(ctx, "daemon-reload"); err != nil {
+		return err
+	}
+
+	fmt.Printf("Simplify service removed (%s)\n", path)
+	return nil
+}
+
+func runSystemdStatus(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+
+	scope, err := currentSystemdScope()
+	if err != nil {
+		return err
+	}
+
+	path := scope.unitPath()
+	installed := "yes"
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		installed = "no"
+	}
+
+	fmt.Printf("Unit:          %s\n", path)
+	fmt.Printf("Installed:     %s\n", installed)
+	if installed == "no" {
+		fmt.Println("Run 'simplify systemd install' to install it")
+		return nil
+	}
+
+	fmt.Printf("Enabled:       %s\n", scope.query(ctx, "is-enabled", systemdUnitName))
+	fmt.Printf("Active:        %s\n", scope.query(ctx, "is-active", systemdUnitName))
+	fmt.Printf("podman.socket: %s\n", scope.query(ctx, "is-active", "podman.socket"))
+	fmt.Printf("Logs:          %s -f\n", scope.journalctl)
+	return nil
+}
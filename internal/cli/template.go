@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:     "template",
+	Aliases: []string{"templates"},
+	Short:   "Deploy applications from the template catalog",
+	Long: `Browse the catalog of bundled application templates and deploy them.
+
+A template is an image with its ports, volumes and environment already
+configured. Deploying one creates the application, a volume for each of
+its data directories and a secret for each credential. Generated
+passwords are printed once; store them somewhere safe.`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List available templates",
+	Args:    cobra.NoArgs,
+	RunE:    runTemplateList,
+}
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show [template]",
+	Short: "Show a template's image, ports, volumes and variables",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateShow,
+}
+
+var templateDeployCmd = &cobra.Command{
+	Use:   "deploy [template] [name]",
+	Short: "Create an application from a template and wait for it to run",
+	Example: `  simplify template deploy postgres db
+  simplify template deploy ghost blog --set url=https://blog.example.com --port 8080:2368`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTemplateDeploy,
+}
+
+var (
+	templateSet         []string
+	templatePorts       []string
+	templateEnvironment string
+	templateTimeout     time.Duration
+	templateNoWait      bool
+)
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateDeployCmd)
+
+	templateDeployCmd.Flags().StringSliceVar(&templateSet, "set", []string{}, "Template variable values (KEY=VALUE)")
+	templateDeployCmd.Flags().StringSliceVarP(&templatePorts, "port", "p", []string{}, "Port mappings replacing the template's (host:container)")
+	templateDeployCmd.Flags().StringVar(&templateEnvironment, "environment", "", "Environment ID the application belongs to")
+	templateDeployCmd.Flags().DurationVar(&templateTimeout, "timeout", 5*time.Minute, "How long to wait for the application to run")
+	templateDeployCmd.Flags().BoolVar(&templateNoWait, "no-wait", false, "Return immediately without waiting for the application to run")
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListTemplates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tIMAGE\tDESCRIPTION")
+		for i := range list {
+			t := &list[i]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.ID, t.Name, t.Image, t.Description)
+		}
+		return w.Flush()
+	})
+}
+
+func runTemplateShow(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	t, err := client.GetTemplate(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return printOutput(t, func(out io.Writer) error {
+		fmt.Fprintf(out, "%s (%s)\n%s\n\nImage: %s\n", t.Name, t.ID, t.Description, t.Image)
+
+		if len(t.Ports) > 0 {
+			hosts := make([]string, 0, len(t.Ports))
+			for host := range t.Ports {
+				hosts = append(hosts, host)
+			}
+			sort.Strings(hosts)
+			fmt.Fprintln(out, "\nPorts:")
+			for _, host := range hosts {
+				fmt.Fprintf(out, "  %s:%s\n", host, t.Ports[host])
+			}
+		}
+
+		if len(t.Volumes) > 0 {
+			fmt.Fprintln(out, "\nVolumes:")
+			for _, v := range t.Volumes {
+				fmt.Fprintf(out, "  %s\t%s\n", v.VolumeName("<name>"), v.Path)
+			}
+		}
+
+		if len(t.Env) > 0 {
+			fmt.Fprintln(out, "\nVariables:")
+			w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+			for i := range t.Env {
+				v := &t.Env[i]
+				value := v.Default
+				switch {
+				case v.Required:
+					value = "(required)"
+				case v.Generate && value == "":
+					value = "(generated)"
+				}
+				if v.Secret {
+					value += " [secret]"
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\n", v.Name, value, v.Description)
+			}
+			return w.Flush()
+		}
+		return nil
+	})
+}
+
+func runTemplateDeploy(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	values, err := parseEnvVars(templateSet)
+	if err != nil {
+		return err
+	}
+
+	req := server.InstantiateRequest{
+		Values:        values,
+		Name:          args[1],
+		EnvironmentID: templateEnvironment,
+	}
+	if cmd.Flags().Changed("port") {
+		ports, err := parsePorts(templatePorts)
+		if err != nil {
+			return err
+		}
+		req.Ports = make(map[string]string, len(ports))
+		for host, containerPort := range ports {
+			req.Ports[fmt.Sprintf("%d", host)] = fmt.Sprintf("%d", containerPort)
+		}
+	}
+
+	// Ask for required variables that were not given on the command line
+	t, err := client.GetTemplate(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+	if isInteractive() {
+		for i := range t.Env {
+			v := &t.Env[i]
+			if !v.Required || values[v.Name] != "" {
+				continue
+			}
+			question := v.Name
+			if v.Description != "" {
+				question = fmt.Sprintf("%s (%s)", v.Name, v.Description)
+			}
+			answer, err := ask(question, "")
+			if err != nil {
+				return err
+			}
+			values[v.Name] = answer
+		}
+	}
+
+	resp, err := client.InstantiateTemplate(ctx, args[0], &req)
+	if err != nil {
+		return fmt.Errorf("failed to deploy template: %w", err)
+	}
+
+	app := &resp.Application
+	fmt.Printf("Application %s created from template %s (ID: %s)\n", app.Name, t.ID, app.ID)
+	if len(resp.Generated) > 0 {
+		names := make([]string, 0, len(resp.Generated))
+		for name := range resp.Generated {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Println("\nGenerated values (shown only once):")
+		for _, name := range names {
+			fmt.Printf("  %s=%s\n", name, resp.Generated[name])
+		}
+		fmt.Println()
+	}
+	if templateNoWait {
+		return nil
+	}
+
+	running, err := waitForApplication(ctx, client, app.ID, templateTimeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Application %s is running\n", running.Name)
+	for _, u := range applicationURLs(running) {
+		fmt.Printf("  %s\n", u)
+	}
+	return nil
+}
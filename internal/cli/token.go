@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage API tokens",
+	Long: `Manage the API tokens (API keys) of a user on the Simplify server.
+
+Commands act on the tokens of the user logged in to the current context
+(see 'simplify login'), or of the user given with --user. A new token is
+shown once, when it is created; afterwards only its prefix is listed.`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an API token",
+	Example: `  simplify token create laptop
+  simplify token create ci --expires 720h
+  simplify token create deploy-bot --user bob`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTokenCreate,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List API tokens",
+	Args:    cobra.NoArgs,
+	RunE:    runTokenList,
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke [id...]",
+	Short: "Revoke API tokens",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runTokenRevoke,
+}
+
+var (
+	tokenUser    string
+	tokenExpires time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	tokenCmd.PersistentFlags().StringVar(&tokenUser, "user", "", "Username or ID of the user whose tokens to manage (default: the logged-in user)")
+	tokenCreateCmd.Flags().DurationVar(&tokenExpires, "expires", 0, "Lifetime of the token, such as 720h (default: never expires)")
+}
+
+// tokenOwner returns the ID of the user whose tokens are managed: --user,
+// or else the user of the current context's token
+func tokenOwner(ctx context.Context, client *apiClient) (string, error) {
+	if tokenUser == "" {
+		me, err := client.WhoAmI(ctx)
+		if err != nil {
+			if errors.IsPermissionError(err) {
+				return "", fmt.Errorf("not logged in: run simplify login, or pass --user")
+			}
+			return "", fmt.Errorf("failed to look up the logged-in user: %w", err)
+		}
+		return me.User.ID, nil
+	}
+
+	users, err := client.ListUsers(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list users: %w", err)
+	}
+	for i := range users {
+		if users[i].ID == tokenUser || users[i].Username == tokenUser {
+			return users[i].ID, nil
+		}
+	}
+	return "", errors.NewNotFoundError("user", tokenUser)
+}
+
+func runTokenCreate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	if tokenExpires < 0 {
+		return fmt.Errorf("--expires must not be negative")
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	userID, err := tokenOwner(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	body := core.APIKey{Name: args[0]}
+	if tokenExpires > 0 {
+		expires := time.Now().Add(tokenExpires).UTC()
+		body.ExpiresAt = &expires
+	}
+	key, err := client.CreateAPIKey(ctx, userID, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return printOutput(key, func(out io.Writer) error {
+		fmt.Fprintf(out, "Token %s created (ID: %s). It is not shown again:\n\n", key.Name, key.ID)
+		fmt.Fprintf(out, "  %s\n\n", key.Key)
+		fmt.Fprintln(out, "Use it with: simplify login --token-stdin")
+		return nil
+	})
+}
+
+func runTokenList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	userID, err := tokenOwner(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No tokens found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tCREATED\tLAST USED\tEXPIRES")
+		for i := range list {
+			k := &list[i]
+			lastUsed, expires := "never", "never"
+			if k.LastUsedAt != nil {
+				lastUsed = formatCreatedTime(*k.LastUsedAt)
+			}
+			if k.ExpiresAt != nil {
+				expires = k.ExpiresAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s...\t%s\t%s\t%s\n", k.ID, k.Name, k.Prefix, formatCreatedTime(k.CreatedAt), lastUsed, expires)
+		}
+		return w.Flush()
+	})
+}
+
+func runTokenRevoke(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	userID, err := tokenOwner(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		if err := client.DeleteAPIKey(ctx, userID, id); err != nil {
+			return fmt.Errorf("failed to revoke token %s: %w", id, err)
+		}
+		fmt.Printf("Token %s revoked\n", id)
+	}
+	return nil
+}
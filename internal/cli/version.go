@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	Example: `  simplify version
+  simplify version --check`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+var versionCheck bool
+
+// releasesURL is the GitHub API endpoint for the latest published release
+const releasesURL = "https://api.github.com/repos/AkMo3/simplify/releases/latest"
+
+// releaseCheckTimeout bounds the GitHub request made by --check
+const releaseCheckTimeout = 10 * time.Second
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check GitHub for a newer release")
+}
+
+// release is the subset of the GitHub release document used by --check
+type release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	fmt.Printf("Simplify %s\n", Version)
+	fmt.Printf("  Git commit: %s\n", GitCommit)
+	fmt.Printf("  Build date: %s\n", BuildDate)
+
+	if !versionCheck {
+		return nil
+	}
+
+	ctx := logger.WithOperationID(context.Background())
+	latest, err := latestRelease(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	fmt.Println()
+	newer, comparable := isNewerVersion(latest.TagName, Version)
+	switch {
+	case !comparable:
+		fmt.Printf("Latest release is %s (this is a %s build and cannot be compared)\n", latest.TagName, Version)
+	case !newer:
+		fmt.Printf("Simplify %s is up to date\n", Version)
+		return nil
+	default:
+		fmt.Printf("A newer release is available: %s\n", latest.TagName)
+	}
+
+	fmt.Printf("  Release notes: %s\n", latest.HTMLURL)
+	fmt.Println("  To upgrade from a source checkout:")
+	fmt.Printf("    git fetch --tags && git checkout %s && make build\n", latest.TagName)
+	return nil
+}
+
+// latestRelease fetches the latest published release from GitHub
+func latestRelease(ctx context.Context) (*release, error) {
+	ctx, cancel := context.WithTimeout(ctx, releaseCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "simplify/"+Version)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("no releases have been published yet")
+	default:
+		return nil, fmt.Errorf("GitHub returned %s", resp.Status)
+	}
+
+	var r release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decoding release: %w", err)
+	}
+	if r.TagName == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return &r, nil
+}
+
+// isNewerVersion reports whether latest is a newer semantic version than
+// current. comparable is false when either is not a vX.Y.Z version, such as
+// the "dev" version of local builds.
+func isNewerVersion(latest, current string) (newer, comparable bool) {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false, false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false, false
+	}
+
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i], true
+		}
+	}
+	return false, true
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring any pre-release or build suffix
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var volumeCmd = &cobra.Command{
+	Use:   "volume",
+	Short: "Manage persistent volumes",
+	Long: `Manage volumes stored by the Simplify server.
+
+Volumes keep data across container recreation. Applications attach them by
+name, for example with 'simplify deploy --volume pgdata:/var/lib/postgresql/data',
+and the reconciler creates them in the container engine.`,
+}
+
+var volumeCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a volume",
+	Example: `  simplify volume create pgdata --size 10GiB --backup daily --retain 7
+  simplify volume create cache`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVolumeCreate,
+}
+
+var volumeListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List volumes",
+	Args:    cobra.NoArgs,
+	RunE:    runVolumeList,
+}
+
+var volumeRmCmd = &cobra.Command{
+	Use:   "rm [name...]",
+	Short: "Remove volumes and their data",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runVolumeRm,
+}
+
+var (
+	volumeSize   string
+	volumeBackup string
+	volumeRetain int
+)
+
+func init() {
+	rootCmd.AddCommand(volumeCmd)
+	volumeCmd.AddCommand(volumeCreateCmd)
+	volumeCmd.AddCommand(volumeListCmd)
+	volumeCmd.AddCommand(volumeRmCmd)
+
+	volumeCreateCmd.Flags().StringVar(&volumeSize, "size", "", "Size hint, e.g. 10GiB (recorded, not enforced)")
+	volumeCreateCmd.Flags().StringVar(&volumeBackup, "backup", "", "Backup schedule: daily or weekly")
+	volumeCreateCmd.Flags().IntVar(&volumeRetain, "retain", 0, "Backups to keep (0 keeps all)")
+}
+
+func runVolumeCreate(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	volume := core.Volume{
+		Name:   args[0],
+		Size:   volumeSize,
+		Backup: core.BackupPolicy{Schedule: volumeBackup, Retain: volumeRetain},
+	}
+	saved, err := client.CreateVolume(ctx, &volume)
+	if err != nil {
+		return fmt.Errorf("failed to create volume: %w", err)
+	}
+
+	fmt.Printf("Volume %s created (ID: %s)\n", saved.Name, truncateString(saved.ID, 12))
+	return nil
+}
+
+func runVolumeList(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	return printOutput(list, func(out io.Writer) error {
+		if len(list) == 0 {
+			fmt.Fprintln(out, "No volumes found")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSIZE\tBACKUP\tCREATED")
+		for i := range list {
+			v := &list[i]
+			backup := "none"
+			if v.Backup.Schedule != core.BackupNone {
+				backup = v.Backup.Schedule
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", truncateString(v.ID, 12), v.Name, v.Size, backup, formatCreatedTime(v.CreatedAt))
+		}
+		return w.Flush()
+	})
+}
+
+func runVolumeRm(cmd *cobra.Command, args []string) error {
+	ctx := logger.WithOperationID(context.Background())
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	ok, err := confirm(fmt.Sprintf("Remove %d volume(s)? Their data will be deleted.", len(args)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	volumes, err := client.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list volumes: %w", err)
+	}
+	ids := make(map[string]string, len(volumes))
+	for i := range volumes {
+		ids[volumes[i].Name] = volumes[i].ID
+	}
+
+	failed := 0
+	for _, name := range args {
+		var err error = errors.NewNotFoundError("volume", name)
+		if id, ok := ids[name]; ok {
+			err = client.DeleteVolume(ctx, id)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Volume %s removed\n", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d volume(s)", failed)
+	}
+	return nil
+}
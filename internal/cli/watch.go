@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// watchDefault is the refresh interval used when --watch is given without a value
+const watchDefault = "2s"
+
+// addWatchFlag registers --watch on a listing command
+func addWatchFlag(cmd *cobra.Command, interval *time.Duration) {
+	cmd.Flags().DurationVarP(interval, "watch", "W", 0, "Refresh the listing every interval until interrupted")
+	cmd.Flags().Lookup("watch").NoOptDefVal = watchDefault
+}
+
+// runWatched renders a listing once, or every interval until Ctrl+C when
+// interval is positive. On a terminal the screen is redrawn in place with a
+// header; otherwise successive snapshots are appended, which suits piping
+// JSON output into other tools. Errors while watching are shown and retried
+// on the next tick instead of ending the watch.
+func runWatched(ctx context.Context, interval time.Duration, title string, render func(ctx context.Context, w io.Writer) error) error {
+	if interval <= 0 {
+		return render(ctx, os.Stdout)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	redraw := !isMachineOutput() && term.IsTerminal(int(os.Stdout.Fd())) //nolint:gosec // file descriptors fit in int
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var buf bytes.Buffer
+		err := render(ctx, &buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if redraw {
+			fmt.Print(ansiClear)
+			fmt.Printf("Every %s: %s    %s\n\n", interval, title, time.Now().Format(time.TimeOnly))
+			if err != nil {
+				fmt.Fprintf(&buf, "Error: %v\n", err)
+			}
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package clientconfig manages CLI-side settings such as named server contexts.
+package clientconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// LocalContext is the implicit context that targets the local server and Podman socket
+const LocalContext = "local"
+
+// Context describes how the CLI reaches a Simplify installation
+type Context struct {
+	Name   string `yaml:"name"`
+	Server string `yaml:"server,omitempty"` // Simplify API URL
+	Token  string `yaml:"token,omitempty"`  // API token sent as a bearer credential
+	Socket string `yaml:"socket,omitempty"` // Podman socket URI for engine commands
+}
+
+// File is the on-disk representation of the client configuration
+type File struct {
+	path     string
+	Current  string    `yaml:"current,omitempty"`
+	Contexts []Context `yaml:"contexts,omitempty"`
+}
+
+// DefaultPath returns the location of the client configuration file,
+// honoring SIMPLIFY_CLIENT_CONFIG when set.
+func DefaultPath() (string, error) {
+	if p := os.Getenv("SIMPLIFY_CLIENT_CONFIG"); p != "" {
+		return p, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "simplify", "contexts.yaml"), nil
+}
+
+// Load reads the client configuration from path. A missing file yields an empty configuration.
+func Load(path string) (*File, error) {
+	f := &File{path: path}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is the user's own config file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("reading client config %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parsing client config %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Save writes the configuration back to the file it was loaded from.
+// The file is private to the user because it may contain tokens.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("creating client config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("encoding client config: %w", err)
+	}
+
+	if err := os.WriteFile(f.path, data, 0o600); err != nil {
+		return fmt.Errorf("writing client config %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Get returns the named context. The local context always exists.
+func (f *File) Get(name string) (*Context, error) {
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == name {
+			return &f.Contexts[i], nil
+		}
+	}
+	if name == LocalContext || name == "" {
+		return &Context{Name: LocalContext}, nil
+	}
+	return nil, errors.NewNotFoundError("context", name)
+}
+
+// Active returns the current context, or the named override when given
+func (f *File) Active(override string) (*Context, error) {
+	if override != "" {
+		return f.Get(override)
+	}
+	return f.Get(f.Current)
+}
+
+// Set adds a context or replaces the one with the same name
+func (f *File) Set(ctx Context) error {
+	if ctx.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "context name is required")
+	}
+
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == ctx.Name {
+			f.Contexts[i] = ctx
+			return nil
+		}
+	}
+	f.Contexts = append(f.Contexts, ctx)
+	return nil
+}
+
+// Use makes the named context current
+func (f *File) Use(name string) error {
+	if _, err := f.Get(name); err != nil {
+		return err
+	}
+	f.Current = name
+	return nil
+}
+
+// Remove deletes a context. Removing the current context switches back to local.
+func (f *File) Remove(name string) error {
+	for i := range f.Contexts {
+		if f.Contexts[i].Name == name {
+			f.Contexts = append(f.Contexts[:i], f.Contexts[i+1:]...)
+			if f.Current == name {
+				f.Current = ""
+			}
+			return nil
+		}
+	}
+	return errors.NewNotFoundError("context", name)
+}
+
+// CurrentName returns the name of the current context
+func (f *File) CurrentName() string {
+	if f.Current == "" {
+		return LocalContext
+	}
+	return f.Current
+}
@@ -0,0 +1,70 @@
+package clientconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoad_MissingFile tests that a missing file yields the local context
+func TestLoad_MissingFile(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "contexts.yaml"))
+	require.NoError(t, err)
+
+	active, err := f.Active("")
+	require.NoError(t, err)
+	assert.Equal(t, LocalContext, active.Name)
+	assert.Empty(t, active.Server)
+}
+
+// TestSaveAndLoad tests that contexts round-trip through the file
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "simplify", "contexts.yaml")
+
+	f, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Set(Context{Name: "prod", Server: "https://prod.example.com", Token: "secret"}))
+	require.NoError(t, f.Use("prod"))
+	require.NoError(t, f.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	active, err := loaded.Active("")
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com", active.Server)
+	assert.Equal(t, "secret", active.Token)
+
+	// An explicit override wins over the current context
+	active, err = loaded.Active(LocalContext)
+	require.NoError(t, err)
+	assert.Equal(t, LocalContext, active.Name)
+}
+
+// TestUseUnknownContext tests switching to a context that does not exist
+func TestUseUnknownContext(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "contexts.yaml"))
+	require.NoError(t, err)
+
+	err = f.Use("missing")
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// TestRemoveCurrentContext tests that removing the current context falls back to local
+func TestRemoveCurrentContext(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "contexts.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, f.Set(Context{Name: "staging", Server: "http://staging:8080"}))
+	require.NoError(t, f.Use("staging"))
+
+	require.NoError(t, f.Remove("staging"))
+	assert.Equal(t, LocalContext, f.CurrentName())
+	assert.True(t, errors.IsNotFound(f.Remove("staging")))
+}
@@ -0,0 +1,535 @@
+// Package compose translates Docker Compose files into Simplify resources.
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"go.yaml.in/yaml/v3"
+)
+
+// File is the subset of the Compose specification that Simplify understands
+type File struct {
+	Services map[string]Service       `yaml:"services"`
+	Networks map[string]any           `yaml:"networks"`
+	Volumes  map[string]*volumeConfig `yaml:"volumes"`
+	// Dir is the directory relative bind mount sources are resolved
+	// against, the one of the file when read by Load
+	Dir string `yaml:"-"`
+}
+
+// volumeConfig is a top-level Compose volume
+type volumeConfig struct {
+	Name     string `yaml:"name"`     // volume name, instead of <project>-<volume>
+	External bool   `yaml:"external"` // created outside the project and used by its own name
+}
+
+// Service is a single Compose service
+type Service struct {
+	Environment stringMap   `yaml:"environment"`
+	DependsOn   stringSet   `yaml:"depends_on"`
+	Networks    stringSet   `yaml:"networks"`
+	Deploy      *deploySpec `yaml:"deploy"`
+	Build       any         `yaml:"build"`
+	Image       string      `yaml:"image"`
+	Ports       []portSpec  `yaml:"ports"`
+	Volumes     []mountSpec `yaml:"volumes"`
+}
+
+type deploySpec struct {
+	Replicas *int `yaml:"replicas"`
+}
+
+// Plan is the set of Simplify resources derived from a Compose file.
+// Applications are ordered so that dependencies come first.
+type Plan struct {
+	Project      string
+	Networks     []core.Network
+	Volumes      []core.Volume // named volumes to create; external ones are not listed
+	Applications []core.Application
+	// AppNetworks maps application names to the name of their network
+	AppNetworks map[string]string
+	// DependsOn maps application names to the names of the applications
+	// they depend on, which become depends_on links. Both are names of
+	// the plan's applications, not service names.
+	DependsOn map[string][]string
+	Warnings  []string
+}
+
+// DefaultNetwork is the network services join when they list none, as in Compose
+const DefaultNetwork = "default"
+
+// ProjectLabel is the application label naming the Compose project that
+// owns the application
+const ProjectLabel = "simplify.compose.project"
+
+// Load reads and parses a Compose file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("reading compose file: %w", err)
+	}
+
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	f.Dir = filepath.Dir(path)
+	return f, nil
+}
+
+// Parse decodes a Compose document
+func Parse(data []byte) (*File, error) {
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing compose file: %w", err)
+	}
+	if len(f.Services) == 0 {
+		return nil, fmt.Errorf("compose file defines no services")
+	}
+	return &f, nil
+}
+
+// NetworkName returns the Simplify network name for a Compose network in a project
+func NetworkName(project, network string) string {
+	return project + "-" + network
+}
+
+// AppName returns the Simplify application name for a Compose service in a project
+func AppName(project, service string) string {
+	return project + "-" + service
+}
+
+// VolumeName returns the Simplify volume name for a Compose volume in a project
+func VolumeName(project, volume string) string {
+	return project + "-" + volume
+}
+
+// Translate converts the Compose file into Simplify networks and applications.
+// Applications are named <project>-<service> and labeled with the project;
+// the service name is a network alias, so containers still reach each other
+// by service name on the project's networks.
+func (f *File) Translate(project string) (*Plan, error) {
+	plan := &Plan{Project: project, AppNetworks: make(map[string]string), DependsOn: make(map[string][]string)}
+
+	order, err := f.serviceOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	usedNetworks := make(map[string]bool)
+	for _, name := range order {
+		svc := f.Services[name]
+
+		if svc.Image == "" {
+			if svc.Build != nil {
+				return nil, fmt.Errorf("service %q: build is not supported, set an image", name)
+			}
+			return nil, fmt.Errorf("service %q: image is required", name)
+		}
+
+		ports, warnings := translatePorts(name, svc.Ports)
+		plan.Warnings = append(plan.Warnings, warnings...)
+
+		mounts, err := f.translateMounts(plan, project, name, svc.Volumes)
+		if err != nil {
+			return nil, err
+		}
+
+		replicas := 1
+		if svc.Deploy != nil && svc.Deploy.Replicas != nil {
+			replicas = *svc.Deploy.Replicas
+		}
+
+		network := DefaultNetwork
+		if len(svc.Networks) > 0 {
+			network = svc.Networks[0]
+			if len(svc.Networks) > 1 {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"service %q: only the first network (%s) is joined", name, network))
+			}
+		}
+		if network != DefaultNetwork {
+			if _, ok := f.Networks[network]; !ok {
+				return nil, fmt.Errorf("service %q: undefined network %q", name, network)
+			}
+		}
+		appName := AppName(project, name)
+		usedNetworks[network] = true
+		plan.AppNetworks[appName] = NetworkName(project, network)
+		for _, dep := range svc.DependsOn {
+			plan.DependsOn[appName] = append(plan.DependsOn[appName], AppName(project, dep))
+		}
+
+		plan.Applications = append(plan.Applications, core.Application{
+			Name:           appName,
+			Image:          svc.Image,
+			Ports:          ports,
+			EnvVars:        map[string]string(svc.Environment),
+			Labels:         map[string]string{ProjectLabel: project},
+			NetworkAliases: []string{name},
+			Volumes:        mounts,
+			Replicas:       replicas,
+		})
+	}
+
+	networks := make([]string, 0, len(usedNetworks))
+	for n := range usedNetworks {
+		networks = append(networks, n)
+	}
+	sort.Strings(networks)
+	for _, n := range networks {
+		plan.Networks = append(plan.Networks, core.Network{Name: NetworkName(project, n), Driver: "bridge"})
+	}
+
+	return plan, nil
+}
+
+// Owns reports whether app belongs to the plan's project
+func (p *Plan) Owns(app *core.Application) bool {
+	return app.Labels[ProjectLabel] == p.Project
+}
+
+// Claim sets the IDs of the plan's applications that already exist, so
+// they are updated rather than created again. It fails when an existing
+// application has the name of one of the plan's but belongs to no or
+// another project, as updating it would take over an unrelated app.
+func (p *Plan) Claim(existing []core.Application) error {
+	for i := range p.Applications {
+		app := &p.Applications[i]
+		for j := range existing {
+			if existing[j].Name != app.Name {
+				continue
+			}
+			if !p.Owns(&existing[j]) {
+				return fmt.Errorf("application %q already exists and does not belong to compose project %q", app.Name, p.Project)
+			}
+			app.ID = existing[j].ID
+		}
+	}
+	return nil
+}
+
+// Owned returns the applications of existing that the plan defines and its
+// project owns
+func (p *Plan) Owned(existing []core.Application) []core.Application {
+	var owned []core.Application
+	for i := range existing {
+		app := &existing[i]
+		if p.Owns(app) && slices.ContainsFunc(p.Applications, func(a core.Application) bool { return a.Name == app.Name }) {
+			owned = append(owned, *app)
+		}
+	}
+	return owned
+}
+
+// serviceOrder returns service names sorted so that depends_on targets come first
+func (f *File) serviceOrder() ([]string, error) {
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(names))
+	order := make([]string, 0, len(names))
+
+	var visit func(name string, from string) error
+	visit = func(name, from string) error {
+		svc, ok := f.Services[name]
+		if !ok {
+			return fmt.Errorf("service %q depends on undefined service %q", from, name)
+		}
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle involving service %q", name)
+		case done:
+			return nil
+		}
+
+		state[name] = visiting
+		deps := append([]string(nil), svc.DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// translatePorts converts Compose port entries into host->container mappings
+func translatePorts(service string, specs []portSpec) (map[string]string, []string) {
+	ports := make(map[string]string, len(specs))
+	var warnings []string
+
+	for _, p := range specs {
+		if p.Published == "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"service %q: port %s is not published to the host and was ignored", service, p.Target))
+			continue
+		}
+		if p.HostIP != "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"service %q: host IP %s for port %s is not supported, binding on all interfaces", service, p.HostIP, p.Published))
+		}
+		ports[p.Published] = p.Target
+	}
+	return ports, warnings
+}
+
+// translateMounts converts the volumes of a service into volume mounts.
+// Named volumes must be declared at the top level and are added to the
+// plan's volumes unless external; bind mounts are resolved against f.Dir.
+func (f *File) translateMounts(plan *Plan, project, service string, specs []mountSpec) ([]core.VolumeMount, error) {
+	var mounts []core.VolumeMount
+	for _, m := range specs {
+		switch {
+		case m.Type == mountBind:
+			source := m.Source
+			if rest, ok := strings.CutPrefix(source, "~"); ok {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return nil, fmt.Errorf("service %q: resolving %s: %w", service, source, err)
+				}
+				source = filepath.Join(home, rest)
+			}
+			if !filepath.IsAbs(source) {
+				abs, err := filepath.Abs(filepath.Join(f.Dir, source))
+				if err != nil {
+					return nil, fmt.Errorf("service %q: resolving %s: %w", service, source, err)
+				}
+				source = abs
+			}
+			mounts = append(mounts, core.VolumeMount{Source: filepath.Clean(source), Path: m.Target, ReadOnly: m.ReadOnly})
+		case m.Type == mountVolume && m.Source == "":
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"service %q: anonymous volume at %s is not supported and was ignored", service, m.Target))
+		case m.Type == mountVolume:
+			config, ok := f.Volumes[m.Source]
+			if !ok {
+				return nil, fmt.Errorf("service %q: undefined volume %q", service, m.Source)
+			}
+			external := config != nil && config.External
+			name := VolumeName(project, m.Source)
+			switch {
+			case config != nil && config.Name != "":
+				name = config.Name
+			case external:
+				name = m.Source
+			}
+			if !external && !slices.ContainsFunc(plan.Volumes, func(v core.Volume) bool { return v.Name == name }) {
+				plan.Volumes = append(plan.Volumes, core.Volume{Name: name})
+			}
+			mounts = append(mounts, core.VolumeMount{Name: name, Path: m.Target, ReadOnly: m.ReadOnly})
+		default:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"service %q: %s mount at %s is not supported and was ignored", service, m.Type, m.Target))
+		}
+	}
+	return mounts, nil
+}
+
+// Compose mount types Simplify translates
+const (
+	mountVolume = "volume"
+	mountBind   = "bind"
+)
+
+// mountSpec accepts both the short ("data:/path:ro") and long Compose
+// volume syntax
+type mountSpec struct {
+	Type     string
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+func (m *mountSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Type     string `yaml:"type"`
+			Source   string `yaml:"source"`
+			Target   string `yaml:"target"`
+			ReadOnly bool   `yaml:"read_only"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		if long.Target == "" {
+			return fmt.Errorf("line %d: volume target is required", node.Line)
+		}
+		*m = mountSpec{Type: long.Type, Source: long.Source, Target: long.Target, ReadOnly: long.ReadOnly}
+		if m.Type == "" {
+			m.Type = mountVolume
+		}
+		return nil
+	}
+
+	var short string
+	if err := node.Decode(&short); err != nil {
+		return err
+	}
+	parts := strings.Split(short, ":")
+	switch len(parts) {
+	case 1:
+		m.Target = parts[0]
+	case 2:
+		m.Source, m.Target = parts[0], parts[1]
+	case 3:
+		m.Source, m.Target = parts[0], parts[1]
+		m.ReadOnly = slices.Contains(strings.Split(parts[2], ","), "ro")
+	default:
+		return fmt.Errorf("line %d: invalid volume %q", node.Line, short)
+	}
+	if m.Target == "" {
+		return fmt.Errorf("line %d: invalid volume %q", node.Line, short)
+	}
+
+	// Sources that look like paths are bind mounts, others name volumes
+	m.Type = mountVolume
+	if strings.HasPrefix(m.Source, "/") || strings.HasPrefix(m.Source, ".") || strings.HasPrefix(m.Source, "~") {
+		m.Type = mountBind
+	}
+	return nil
+}
+
+// portSpec accepts both the short ("8080:80") and long Compose port syntax
+type portSpec struct {
+	HostIP    string
+	Published string
+	Target    string
+}
+
+func (p *portSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Published any    `yaml:"published"`
+			Target    any    `yaml:"target"`
+			HostIP    string `yaml:"host_ip"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+		p.HostIP = long.HostIP
+		p.Published = scalarString(long.Published)
+		p.Target = scalarString(long.Target)
+		if p.Target == "" {
+			return fmt.Errorf("line %d: port target is required", node.Line)
+		}
+		return nil
+	}
+
+	var short string
+	if err := node.Decode(&short); err != nil {
+		return err
+	}
+	short, _, _ = strings.Cut(short, "/") // drop the protocol suffix
+
+	parts := strings.Split(short, ":")
+	switch len(parts) {
+	case 1:
+		p.Target = parts[0]
+	case 2:
+		p.Published, p.Target = parts[0], parts[1]
+	case 3:
+		p.HostIP, p.Published, p.Target = parts[0], parts[1], parts[2]
+	default:
+		return fmt.Errorf("line %d: invalid port %q", node.Line, short)
+	}
+
+	for _, port := range []string{p.Published, p.Target} {
+		if port == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("line %d: invalid port %q (port ranges are not supported)", node.Line, port)
+		}
+	}
+	return nil
+}
+
+// stringMap accepts both the map and the "KEY=VALUE" list forms of environment
+type stringMap map[string]string
+
+func (m *stringMap) UnmarshalYAML(node *yaml.Node) error {
+	result := make(map[string]string)
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		var raw map[string]any
+		if err := node.Decode(&raw); err != nil {
+			return err
+		}
+		for k, v := range raw {
+			result[k] = scalarString(v)
+		}
+	case yaml.SequenceNode:
+		var raw []string
+		if err := node.Decode(&raw); err != nil {
+			return err
+		}
+		for _, entry := range raw {
+			k, v, _ := strings.Cut(entry, "=")
+			result[k] = v
+		}
+	default:
+		return fmt.Errorf("line %d: environment must be a map or a list", node.Line)
+	}
+
+	*m = result
+	return nil
+}
+
+// stringSet accepts both the list and the map forms used by depends_on and networks
+type stringSet []string
+
+func (s *stringSet) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var raw []string
+		if err := node.Decode(&raw); err != nil {
+			return err
+		}
+		*s = raw
+	case yaml.MappingNode:
+		// Keys are the names, in document order
+		names := make([]string, 0, len(node.Content)/2)
+		for i := 0; i < len(node.Content); i += 2 {
+			names = append(names, node.Content[i].Value)
+		}
+		*s = names
+	default:
+		return fmt.Errorf("line %d: expected a list or a map", node.Line)
+	}
+	return nil
+}
+
+// scalarString formats a YAML scalar (string, number or bool) as a string
+func scalarString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
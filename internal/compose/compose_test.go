@@ -0,0 +1,163 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	f, err := Parse([]byte(`
+services:
+  web:
+    image: nginx:latest
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8443:443/tcp"
+      - "9000"
+    environment:
+      - MODE=prod
+    depends_on:
+      - api
+    networks: [front]
+  api:
+    image: myapp:v2
+    environment:
+      DB_HOST: db
+      WORKERS: 4
+    depends_on:
+      db:
+        condition: service_healthy
+    deploy:
+      replicas: 2
+  db:
+    image: postgres:16
+    volumes:
+      - data:/var/lib/postgresql/data
+      - ./initdb:/docker-entrypoint-initdb.d:ro
+      - type: volume
+        source: shared
+        target: /shared
+        read_only: true
+      - /var/cache/db
+networks:
+  front: {}
+volumes:
+  data: {}
+  shared:
+    external: true
+`))
+	require.NoError(t, err)
+	f.Dir = "/srv/shop"
+
+	plan, err := f.Translate("shop")
+	require.NoError(t, err)
+
+	require.Len(t, plan.Applications, 3)
+	assert.Equal(t, "shop-db", plan.Applications[0].Name)
+	assert.Equal(t, "shop-api", plan.Applications[1].Name)
+	assert.Equal(t, "shop-web", plan.Applications[2].Name)
+
+	// Applications belong to the project and answer to the service name
+	for i := range plan.Applications {
+		app := &plan.Applications[i]
+		assert.Equal(t, map[string]string{ProjectLabel: "shop"}, app.Labels, app.Name)
+	}
+	assert.Equal(t, []string{"db"}, plan.Applications[0].NetworkAliases)
+
+	api := plan.Applications[1]
+	assert.Equal(t, 2, api.Replicas)
+	assert.Equal(t, "4", api.EnvVars["WORKERS"])
+
+	web := plan.Applications[2]
+	assert.Equal(t, map[string]string{"8080": "80", "8443": "443"}, web.Ports)
+	assert.Equal(t, "prod", web.EnvVars["MODE"])
+
+	assert.Equal(t, map[string][]string{"shop-web": {"shop-api"}, "shop-api": {"shop-db"}}, plan.DependsOn)
+
+	assert.Equal(t, "shop-front", plan.AppNetworks["shop-web"])
+	assert.Equal(t, "shop-default", plan.AppNetworks["shop-db"])
+	require.Len(t, plan.Networks, 2)
+	assert.Equal(t, "shop-default", plan.Networks[0].Name)
+	assert.Equal(t, "shop-front", plan.Networks[1].Name)
+
+	// Named volumes are created for the project, external ones are not
+	db := plan.Applications[0]
+	assert.Equal(t, []core.VolumeMount{
+		{Name: "shop-data", Path: "/var/lib/postgresql/data"},
+		{Source: "/srv/shop/initdb", Path: "/docker-entrypoint-initdb.d", ReadOnly: true},
+		{Name: "shared", Path: "/shared", ReadOnly: true},
+	}, db.Volumes)
+	assert.Equal(t, []core.Volume{{Name: "shop-data"}}, plan.Volumes)
+
+	// Unpublished port, host IP and the anonymous volume produce warnings
+	assert.Len(t, plan.Warnings, 3)
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		compose string
+	}{
+		{"no services", "version: '3'\n"},
+		{"build only", "services:\n  web:\n    build: .\n"},
+		{"undefined dependency", "services:\n  web:\n    image: nginx\n    depends_on: [db]\n"},
+		{"dependency cycle", "services:\n  a:\n    image: x\n    depends_on: [b]\n  b:\n    image: x\n    depends_on: [a]\n"},
+		{"undefined network", "services:\n  web:\n    image: nginx\n    networks: [back]\n"},
+		{"port range", "services:\n  web:\n    image: nginx\n    ports: ['8000-8010:80']\n"},
+		{"undefined volume", "services:\n  web:\n    image: nginx\n    volumes: ['data:/data']\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse([]byte(tt.compose))
+			if err != nil {
+				return
+			}
+			_, err = f.Translate("test")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPlanOwnership(t *testing.T) {
+	f, err := Parse([]byte("services:\n  web:\n    image: nginx\n  db:\n    image: postgres\n"))
+	require.NoError(t, err)
+	plan, err := f.Translate("shop")
+	require.NoError(t, err)
+
+	shop := map[string]string{ProjectLabel: "shop"}
+	existing := []core.Application{
+		{ID: "1", Name: "shop-web", Labels: shop},
+		{ID: "2", Name: "shop-api", Labels: shop},                                   // not in the file
+		{ID: "3", Name: "web"},                                                      // created outside Compose
+		{ID: "4", Name: "blog-db", Labels: map[string]string{ProjectLabel: "blog"}}, // another project
+	}
+
+	// up updates its own applications and creates the others
+	require.NoError(t, plan.Claim(existing))
+	ids := map[string]string{}
+	for i := range plan.Applications {
+		ids[plan.Applications[i].Name] = plan.Applications[i].ID
+	}
+	assert.Equal(t, map[string]string{"shop-db": "", "shop-web": "1"}, ids)
+
+	// down deletes only the project's applications defined in the file
+	owned := plan.Owned(existing)
+	require.Len(t, owned, 1)
+	assert.Equal(t, "1", owned[0].ID)
+
+	// An application of the same name outside the project is not taken over
+	existing = append(existing, core.Application{ID: "5", Name: "shop-db"})
+	err = plan.Claim(existing)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "shop-db")
+
+	other, err := f.Translate("blog")
+	require.NoError(t, err)
+	require.NoError(t, other.Claim(existing))
+	assert.Equal(t, "4", other.Applications[0].ID, "blog-db belongs to the blog project")
+	assert.Empty(t, other.Owned(existing[:1]), "shop-web belongs to another project")
+}
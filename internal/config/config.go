@@ -3,8 +3,17 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,35 +24,289 @@ const (
 	EnvProduction     = "production"
 
 	// Default values
-	DefaultServerPort   = 8080
-	DefaultDatabasePath = "/var/lib/simplify/data.db"
+	DefaultServerPort          = 8080
+	DefaultDatabasePath        = "/var/lib/simplify/data.db"
+	DefaultReconcileInterval   = 10  // seconds
+	DefaultLogMaxSize          = 100 // megabytes
+	DefaultLogMaxBackups       = 5
+	DefaultLogMaxAge           = 30 // days
+	DefaultLogSampleInitial    = 100
+	DefaultLogSampleThereafter = 100
+	DefaultBuildGitImage       = "docker.io/alpine/git:latest"
+	DefaultBuildPodmanImage    = "quay.io/podman/stable:latest"
+	DefaultBuildPackImage      = "docker.io/buildpacksio/pack:latest"
+	DefaultBuildBuilder        = "docker.io/paketobuildpacks/builder-jammy-base:latest"
+	DefaultBackupRetain        = 7
+	DefaultMetricsInterval     = 15 // seconds
+	DefaultMetricsRetention    = 24 // hours
 )
 
 // Config is the root configuration structure
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Env      string         `mapstructure:"env"`
-	Server   ServerConfig   `mapstructure:"server"`
+	Backup        BackupConfig        `mapstructure:"backup"`
+	Build         BuildConfig         `mapstructure:"build"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Env           string              `mapstructure:"env"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Reporting     ReportingConfig     `mapstructure:"reporting"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Reconciler    ReconcilerConfig    `mapstructure:"reconciler"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port            int `mapstructure:"port"`
-	ReadTimeout     int `mapstructure:"read_timeout"`     // seconds
-	WriteTimeout    int `mapstructure:"write_timeout"`    // seconds
-	IdleTimeout     int `mapstructure:"idle_timeout"`     // seconds
-	ShutdownTimeout int `mapstructure:"shutdown_timeout"` // seconds
+	Host            string   `mapstructure:"host"`            // empty listens on 127.0.0.1 in development and all interfaces in production
+	JoinToken       string   `mapstructure:"join_token"`      // shared secret agents register nodes with; empty refuses registration
+	TrustedProxies  []string `mapstructure:"trusted_proxies"` // CIDRs or addresses whose forwarded headers are honoured
+	Port            int      `mapstructure:"port"`
+	ReadTimeout     int      `mapstructure:"read_timeout"`     // seconds
+	WriteTimeout    int      `mapstructure:"write_timeout"`    // seconds
+	IdleTimeout     int      `mapstructure:"idle_timeout"`     // seconds
+	ShutdownTimeout int      `mapstructure:"shutdown_timeout"` // seconds
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Path          string `mapstructure:"path"`
+	SecretKeyFile string `mapstructure:"secret_key_file"` // key that encrypts secrets, empty uses secret.key next to the database
 }
 
-var globalConfig *Config
+// LoggingConfig holds logging configuration
+type LoggingConfig struct {
+	Levels     map[string]string `mapstructure:"levels"`     // module name (reconciler, server, http, container) to level or "off"
+	Components map[string]string `mapstructure:"components"` // deprecated alias of Levels
+	Level      string            `mapstructure:"level"`      // debug, info, warn or error; empty uses the env default
+	Format     string            `mapstructure:"format"`     // text (or console) or json; empty uses the env default
+	Sampling   LogSamplingConfig `mapstructure:"sampling"`
+	File       LogFileConfig     `mapstructure:"file"`
+}
+
+// LogSamplingConfig limits repeated log entries. Each second, the first
+// Initial entries with the same level and message are logged, then every
+// Thereafter-th one.
+type LogSamplingConfig struct {
+	Enabled    *bool `mapstructure:"enabled"` // unset enables sampling in production only
+	Initial    int   `mapstructure:"initial"`
+	Thereafter int   `mapstructure:"thereafter"`
+}
+
+// LogFileConfig configures the server's log file, written in addition to stdout
+type LogFileConfig struct {
+	Path       string `mapstructure:"path"`        // empty disables file logging
+	MaxSize    int    `mapstructure:"max_size"`    // megabytes before the file is rotated
+	MaxBackups int    `mapstructure:"max_backups"` // rotated files to keep, 0 keeps all
+	MaxAge     int    `mapstructure:"max_age"`     // days to keep rotated files, 0 keeps all
+}
+
+// ReconcilerConfig holds reconciliation loop configuration
+type ReconcilerConfig struct {
+	Interval int `mapstructure:"interval"` // seconds
+}
+
+// BackupConfig schedules backups of the database and named volumes to a
+// directory or an S3-compatible bucket
+type BackupConfig struct {
+	Volumes  []string       `mapstructure:"volumes"`  // names of volumes archived with the database
+	Schedule string         `mapstructure:"schedule"` // cron expression, empty disables scheduled backups
+	Dir      string         `mapstructure:"dir"`      // directory to store backups in
+	S3       BackupS3Config `mapstructure:"s3"`       // used when bucket is set
+	Retain   int            `mapstructure:"retain"`   // backups to keep, 0 keeps all
+}
+
+// BackupS3Config is an S3-compatible bucket that stores backups
+type BackupS3Config struct {
+	Endpoint  string `mapstructure:"endpoint"` // e.g. https://s3.eu-central-1.amazonaws.com
+	Bucket    string `mapstructure:"bucket"`
+	Prefix    string `mapstructure:"prefix"`
+	Region    string `mapstructure:"region"` // us-east-1 when empty
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"` // bucket in the path instead of the host name, as MinIO expects
+}
+
+// Enabled reports whether a backup target is configured
+func (b *BackupConfig) Enabled() bool {
+	return b.Dir != "" || b.S3.Bucket != ""
+}
+
+// MetricsConfig controls how often container resource usage is sampled
+// and how long the samples are kept
+type MetricsConfig struct {
+	Interval  int `mapstructure:"interval"`  // seconds, 0 disables collection
+	Retention int `mapstructure:"retention"` // hours
+}
+
+// BuildConfig holds the images the build pipeline runs its steps in
+type BuildConfig struct {
+	GitImage    string `mapstructure:"git_image"`    // clones the repository
+	PodmanImage string `mapstructure:"podman_image"` // builds Containerfiles against the host engine
+	PackImage   string `mapstructure:"pack_image"`   // runs Cloud Native Buildpacks
+	Builder     string `mapstructure:"builder"`      // buildpacks builder image
+}
+
+// NotificationsConfig sends events such as failed deploys to notifiers.
+// Each event goes to the notifiers of every route it matches.
+type NotificationsConfig struct {
+	Notifiers map[string]NotifierConfig `mapstructure:"notifiers"` // by name, which is case-insensitive
+	Routes    []NotificationRoute       `mapstructure:"routes"`
+}
+
+// NotifierConfig configures one destination for notifications
+type NotifierConfig struct {
+	Type     string   `mapstructure:"type"`     // slack, webhook or smtp
+	URL      string   `mapstructure:"url"`      // slack incoming webhook or webhook endpoint
+	Secret   string   `mapstructure:"secret"`   // webhook: signs the body in X-Simplify-Signature
+	Host     string   `mapstructure:"host"`     // smtp server
+	Username string   `mapstructure:"username"` // smtp login, empty to send without authentication
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`   // smtp recipients
+	Port     int      `mapstructure:"port"` // smtp port, 587 when unset
+}
+
+// NotificationRoute sends matching events to notifiers
+type NotificationRoute struct {
+	Teams     []string `mapstructure:"teams"`     // team IDs or names; empty matches every event, with a team or not
+	Events    []string `mapstructure:"events"`    // event types; empty matches every type
+	Notifiers []string `mapstructure:"notifiers"` // names of notifiers
+}
+
+// Notifier types
+const (
+	NotifierSlack   = "slack"
+	NotifierWebhook = "webhook"
+	NotifierSMTP    = "smtp"
+)
+
+// LogSampling reports whether log sampling is on, defaulting to on in
+// production when logging.sampling.enabled is unset
+func (c *Config) LogSampling() bool {
+	if c.Logging.Sampling.Enabled != nil {
+		return *c.Logging.Sampling.Enabled
+	}
+	return c.Env == EnvProduction
+}
+
+// LogLevels returns the per-module log levels: logging.levels merged over
+// the older logging.components
+func (c *Config) LogLevels() map[string]string {
+	if len(c.Logging.Components) == 0 {
+		return c.Logging.Levels
+	}
+	levels := make(map[string]string, len(c.Logging.Components)+len(c.Logging.Levels))
+	maps.Copy(levels, c.Logging.Components)
+	maps.Copy(levels, c.Logging.Levels)
+	return levels
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP collector, host:port or URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	SampleRatio float64 `mapstructure:"sample_ratio"` // fraction of traces to record, between 0 and 1
+	Enabled     bool    `mapstructure:"enabled"`
+	Insecure    bool    `mapstructure:"insecure"` // plain HTTP to the collector
+}
+
+// ReportingConfig holds error reporting configuration for a Sentry-compatible
+// service. Reporting is enabled when DSN is set.
+type ReportingConfig struct {
+	DSN        string  `mapstructure:"dsn"`         // project DSN, e.g. https://key@sentry.example.com/1
+	SampleRate float64 `mapstructure:"sample_rate"` // fraction of events to send, between 0 and 1
+}
+
+// ListenAddr returns the address the API server binds to. An empty
+// server.host keeps the API on localhost in development and listens on all
+// interfaces in production.
+func (c *Config) ListenAddr() string {
+	host := c.Server.Host
+	if host == "" && c.Env != EnvProduction {
+		host = localhost
+	}
+	return net.JoinHostPort(host, strconv.Itoa(c.Server.Port))
+}
+
+// LocalURL returns the URL the CLI uses to reach the API server on this
+// host, going through loopback when the server listens on all interfaces
+func (c *Config) LocalURL() string {
+	host := c.Server.Host
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = localhost
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(c.Server.Port))
+}
+
+// SecretKeyPath returns the file holding the key that encrypts secrets
+func (c *Config) SecretKeyPath() string {
+	if c.Database.SecretKeyFile != "" {
+		return c.Database.SecretKeyFile
+	}
+	return filepath.Join(filepath.Dir(c.Database.Path), "secret.key")
+}
+
+// TrustedProxies returns the parsed server.trusted_proxies. Entries are
+// validated by Load, so invalid ones are skipped here.
+func (c *Config) TrustedProxies() []netip.Prefix {
+	prefixes, _ := parseTrustedProxies(c.Server.TrustedProxies) //nolint:errcheck // validated on load
+	return prefixes
+}
+
+// parseTrustedProxies parses CIDRs and bare addresses, which are treated
+// as single-host prefixes
+func parseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: must be an IP address or CIDR", entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ReconcileInterval returns the reconciliation interval, or the default when unset
+func (c *Config) ReconcileInterval() time.Duration {
+	if c.Reconciler.Interval <= 0 {
+		return DefaultReconcileInterval * time.Second
+	}
+	return time.Duration(c.Reconciler.Interval) * time.Second
+}
+
+// MetricsRetention returns how long metric samples are kept, or the
+// default when unset
+func (c *Config) MetricsRetention() time.Duration {
+	if c.Metrics.Retention <= 0 {
+		return DefaultMetricsRetention * time.Hour
+	}
+	return time.Duration(c.Metrics.Retention) * time.Hour
+}
+
+// DefaultTrustedProxies trusts forwarded headers from a reverse proxy on
+// the same host only
+var DefaultTrustedProxies = []string{"127.0.0.0/8", "::1/128"}
+
+// localhost is the loopback address the API listens on by default in development
+const localhost = "127.0.0.1"
+
+// hostnamePattern matches DNS host names such as localhost or api.example.com
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*$`)
+
+// globalConfig is swapped atomically so Reload is safe while requests read it
+var globalConfig atomic.Pointer[Config]
+
+// loadedPath is the config file read by Load, used by Reload
+var loadedPath string
 
 // Load initializes configuration from file, env vars, with priority:
-// config file -> environment variables -> CLI flags
+// config file -> config.d overlays -> profile file -> environment variables -> CLI flags
 func Load(configPath string) error {
 	if configPath == "" {
 		configPath = DefaultConfigPath
@@ -52,50 +315,96 @@ func Load(configPath string) error {
 	// Reset viper for testing
 	viper.Reset()
 
+	format, err := FileFormat(configPath)
+	if err != nil {
+		return err
+	}
+
 	// Create default config if it doesn't exist
 	if err := ensureConfigExists(configPath); err != nil {
 		return fmt.Errorf("ensuring config exists: %w", err)
 	}
 
 	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
+	viper.SetConfigType(format)
 
 	// Bind environment variables
-	if err := bindEnvVariables(); err != nil {
+	if err := bindEnvVariables(viper.GetViper()); err != nil {
 		return err
 	}
 
 	// Set defaults
-	setDefaults()
+	setDefaults(viper.GetViper())
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("reading config file: %w", err)
 	}
 
-	globalConfig = &Config{}
-	if err := viper.Unmarshal(globalConfig); err != nil {
+	// Merge overlays and the profile file over the base config
+	layers, err := Layers(configPath)
+	if err != nil {
+		return err
+	}
+	if err := mergeLayers(viper.GetViper(), layers); err != nil {
+		return err
+	}
+
+	cfg := &Config{}
+	if err := viper.Unmarshal(cfg, expandReferences()); err != nil {
 		return fmt.Errorf("unmarshaling config: %w", err)
 	}
 
 	// Validate configuration
-	if err := validateConfig(globalConfig); err != nil {
+	if err := validateConfig(cfg); err != nil {
 		return err
 	}
 
+	globalConfig.Store(cfg)
+	loadedPath = configPath
 	return nil
 }
 
+// Reload re-reads the config file used by Load and replaces the global
+// configuration. The previous configuration stays active if the file is
+// invalid. It returns the previous and the new configuration so callers
+// can apply what changed.
+func Reload() (previous, current *Config, err error) {
+	if loadedPath == "" {
+		return nil, nil, fmt.Errorf("no config file loaded")
+	}
+
+	cfg, err := ValidateFile(loadedPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous = Get()
+	globalConfig.Store(cfg)
+	return previous, cfg, nil
+}
+
+// Path returns the config file read by Load
+func Path() string {
+	return loadedPath
+}
+
 // bindEnvVariables binds environment variables to config keys
-func bindEnvVariables() error {
+func bindEnvVariables(v *viper.Viper) error {
 	bindings := map[string]string{
-		"env":           "SIMPLIFY_ENV",
-		"server.port":   "SIMPLIFY_SERVER_PORT",
-		"database.path": "SIMPLIFY_DATABASE_PATH",
+		"env":                      "SIMPLIFY_ENV",
+		"server.host":              "SIMPLIFY_SERVER_HOST",
+		"server.port":              "SIMPLIFY_SERVER_PORT",
+		"server.join_token":        "SIMPLIFY_SERVER_JOIN_TOKEN",
+		"database.path":            "SIMPLIFY_DATABASE_PATH",
+		"database.secret_key_file": "SIMPLIFY_DATABASE_SECRET_KEY_FILE",
+		"logging.level":            "SIMPLIFY_LOG_LEVEL",
+		"logging.format":           "SIMPLIFY_LOG_FORMAT",
+		"reporting.dsn":            "SIMPLIFY_REPORTING_DSN",
 	}
 
 	for key, envVar := range bindings {
-		if err := viper.BindEnv(key, envVar); err != nil {
+		if err := v.BindEnv(key, envVar); err != nil {
 			return fmt.Errorf("binding env variable %s: %w", envVar, err)
 		}
 	}
@@ -104,19 +413,61 @@ func bindEnvVariables() error {
 }
 
 // setDefaults sets default values for all configuration options
-func setDefaults() {
+func setDefaults(v *viper.Viper) {
 	// Environment
-	viper.SetDefault("env", EnvDevelopment)
+	v.SetDefault("env", EnvDevelopment)
 
 	// Server defaults
-	viper.SetDefault("server.port", DefaultServerPort)
-	viper.SetDefault("server.read_timeout", 30)
-	viper.SetDefault("server.write_timeout", 30)
-	viper.SetDefault("server.idle_timeout", 120)
-	viper.SetDefault("server.shutdown_timeout", 30)
+	v.SetDefault("server.host", "")
+	v.SetDefault("server.port", DefaultServerPort)
+	v.SetDefault("server.trusted_proxies", DefaultTrustedProxies)
+	v.SetDefault("server.join_token", "")
+	v.SetDefault("server.read_timeout", 30)
+	v.SetDefault("server.write_timeout", 30)
+	v.SetDefault("server.idle_timeout", 120)
+	v.SetDefault("server.shutdown_timeout", 30)
 
 	// Database defaults
-	viper.SetDefault("database.path", DefaultDatabasePath)
+	v.SetDefault("database.path", DefaultDatabasePath)
+	v.SetDefault("database.secret_key_file", "")
+
+	// Logging defaults (empty level and format use the env default, empty path disables the file)
+	v.SetDefault("logging.level", "")
+	v.SetDefault("logging.format", "")
+	v.SetDefault("logging.file.path", "")
+	v.SetDefault("logging.file.max_size", DefaultLogMaxSize)
+	v.SetDefault("logging.file.max_backups", DefaultLogMaxBackups)
+	v.SetDefault("logging.file.max_age", DefaultLogMaxAge)
+	v.SetDefault("logging.sampling.initial", DefaultLogSampleInitial)
+	v.SetDefault("logging.sampling.thereafter", DefaultLogSampleThereafter)
+
+	// Reconciler defaults
+	v.SetDefault("reconciler.interval", DefaultReconcileInterval)
+
+	// Backup defaults (no target, so no backups)
+	v.SetDefault("backup.schedule", "")
+	v.SetDefault("backup.dir", "")
+	v.SetDefault("backup.retain", DefaultBackupRetain)
+
+	// Metrics defaults
+	v.SetDefault("metrics.interval", DefaultMetricsInterval)
+	v.SetDefault("metrics.retention", DefaultMetricsRetention)
+
+	// Error reporting defaults (disabled, every event sent once enabled)
+	v.SetDefault("reporting.dsn", "")
+	v.SetDefault("reporting.sample_rate", 1.0)
+
+	// Build step images
+	v.SetDefault("build.git_image", DefaultBuildGitImage)
+	v.SetDefault("build.podman_image", DefaultBuildPodmanImage)
+	v.SetDefault("build.pack_image", DefaultBuildPackImage)
+	v.SetDefault("build.builder", DefaultBuildBuilder)
+
+	// Tracing defaults (disabled, every trace sampled once enabled)
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.sample_ratio", 1.0)
 }
 
 // validateConfig validates the loaded configuration
@@ -133,6 +484,15 @@ func validateConfig(cfg *Config) error {
 			cfg.Server.Port)
 	}
 
+	// Validate listen host, an IP address or a host name
+	if h := cfg.Server.Host; h != "" && net.ParseIP(h) == nil && !hostnamePattern.MatchString(h) {
+		return fmt.Errorf("invalid server host %q: must be an IP address or host name", h)
+	}
+
+	if _, err := parseTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("server trusted_proxies: %w", err)
+	}
+
 	// Validate database path is not empty
 	if cfg.Database.Path == "" {
 		return fmt.Errorf("database path cannot be empty")
@@ -152,16 +512,151 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("server shutdown_timeout must be positive")
 	}
 
+	switch cfg.Logging.Level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid log level %q: must be debug, info, warn or error", cfg.Logging.Level)
+	}
+
+	switch cfg.Logging.Format {
+	case "", "text", "console", "json":
+	default:
+		return fmt.Errorf("invalid log format %q: must be text, console or json", cfg.Logging.Format)
+	}
+
+	for name, level := range cfg.LogLevels() {
+		switch level {
+		case "debug", "info", "warn", "error", "off":
+		default:
+			return fmt.Errorf("invalid log level %q for module %s: must be debug, info, warn, error or off", level, name)
+		}
+	}
+
+	if s := cfg.Logging.Sampling; cfg.LogSampling() && (s.Initial < 1 || s.Thereafter < 1) {
+		return fmt.Errorf("logging sampling initial and thereafter must be positive")
+	}
+
+	if f := cfg.Logging.File; f.Path != "" {
+		if f.MaxSize <= 0 {
+			return fmt.Errorf("logging file max_size must be positive")
+		}
+		if f.MaxBackups < 0 || f.MaxAge < 0 {
+			return fmt.Errorf("logging file max_backups and max_age must not be negative")
+		}
+	}
+
+	if cfg.Reconciler.Interval < 0 {
+		return fmt.Errorf("reconciler interval must not be negative")
+	}
+
+	if err := validateBackup(&cfg.Backup); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+
+	if cfg.Metrics.Interval < 0 || cfg.Metrics.Retention < 0 {
+		return fmt.Errorf("metrics interval and retention must not be negative")
+	}
+
+	if r := cfg.Reporting; r.DSN != "" {
+		u, err := url.Parse(r.DSN)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User == nil || u.Host == "" {
+			return fmt.Errorf("invalid reporting dsn: must look like https://key@host/project")
+		}
+	}
+	if cfg.Reporting.SampleRate < 0 || cfg.Reporting.SampleRate > 1 {
+		return fmt.Errorf("reporting sample_rate must be between 0 and 1")
+	}
+
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample_ratio must be between 0 and 1")
+	}
+
+	if err := validateNotifications(&cfg.Notifications); err != nil {
+		return fmt.Errorf("notifications: %w", err)
+	}
+
+	return nil
+}
+
+// volumeNamePattern matches names of volumes that can be backed up
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// validateBackup checks that scheduled backups have a target and that the
+// target is complete. The schedule itself is parsed when the server starts.
+func validateBackup(b *BackupConfig) error {
+	if b.Dir != "" && b.S3.Bucket != "" {
+		return fmt.Errorf("set either dir or s3, not both")
+	}
+	if b.Schedule != "" && !b.Enabled() {
+		return fmt.Errorf("a schedule needs a dir or s3 target")
+	}
+	if b.Dir != "" && !filepath.IsAbs(b.Dir) {
+		return fmt.Errorf("dir must be an absolute path")
+	}
+	if s3 := b.S3; s3.Bucket != "" {
+		u, err := url.Parse(s3.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("s3 endpoint must be an http or https URL")
+		}
+		if s3.AccessKey == "" || s3.SecretKey == "" {
+			return fmt.Errorf("s3 access_key and secret_key are required")
+		}
+	}
+	if b.Retain < 0 {
+		return fmt.Errorf("retain must not be negative")
+	}
+	for _, v := range b.Volumes {
+		if !volumeNamePattern.MatchString(v) {
+			return fmt.Errorf("invalid volume name %q", v)
+		}
+	}
+	return nil
+}
+
+// validateNotifications checks that notifiers have what their type needs
+// and that routes name existing notifiers
+func validateNotifications(n *NotificationsConfig) error {
+	for name, nc := range n.Notifiers {
+		switch nc.Type {
+		case NotifierSlack, NotifierWebhook:
+			u, err := url.Parse(nc.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("notifier %s: url must be an http or https URL", name)
+			}
+		case NotifierSMTP:
+			if nc.Host == "" || nc.From == "" || len(nc.To) == 0 {
+				return fmt.Errorf("notifier %s: host, from and to are required", name)
+			}
+			if nc.Port < 0 || nc.Port > 65535 {
+				return fmt.Errorf("notifier %s: invalid port %d", name, nc.Port)
+			}
+		default:
+			return fmt.Errorf("notifier %s: type must be %s, %s or %s", name, NotifierSlack, NotifierWebhook, NotifierSMTP)
+		}
+	}
+
+	for i, route := range n.Routes {
+		if len(route.Notifiers) == 0 {
+			return fmt.Errorf("route %d: notifiers cannot be empty", i+1)
+		}
+		for _, name := range route.Notifiers {
+			if _, ok := n.Notifiers[strings.ToLower(name)]; !ok {
+				return fmt.Errorf("route %d: unknown notifier %q", i+1, name)
+			}
+		}
+	}
 	return nil
 }
 
 // Get returns the global configuration
 func Get() *Config {
-	if globalConfig == nil {
+	cfg := globalConfig.Load()
+	if cfg == nil {
 		// Return default config if not loaded
 		return &Config{
 			Env: EnvDevelopment,
 			Server: ServerConfig{
+				TrustedProxies:  DefaultTrustedProxies,
 				Port:            DefaultServerPort,
 				ReadTimeout:     30,
 				WriteTimeout:    30,
@@ -171,9 +666,37 @@ func Get() *Config {
 			Database: DatabaseConfig{
 				Path: DefaultDatabasePath,
 			},
+			Reconciler: ReconcilerConfig{
+				Interval: DefaultReconcileInterval,
+			},
+			Backup: BackupConfig{
+				Retain: DefaultBackupRetain,
+			},
+			Metrics: MetricsConfig{
+				Interval:  DefaultMetricsInterval,
+				Retention: DefaultMetricsRetention,
+			},
+			Logging: LoggingConfig{
+				Sampling: LogSamplingConfig{
+					Initial:    DefaultLogSampleInitial,
+					Thereafter: DefaultLogSampleThereafter,
+				},
+			},
+			Reporting: ReportingConfig{
+				SampleRate: 1,
+			},
+			Tracing: TracingConfig{
+				SampleRatio: 1,
+			},
+			Build: BuildConfig{
+				GitImage:    DefaultBuildGitImage,
+				PodmanImage: DefaultBuildPodmanImage,
+				PackImage:   DefaultBuildPackImage,
+				Builder:     DefaultBuildBuilder,
+			},
 		}
 	}
-	return globalConfig
+	return cfg
 }
 
 // IsDevelopment returns true if running in development mode
@@ -186,7 +709,8 @@ func IsProduction() bool {
 	return Get().Env == EnvProduction
 }
 
-// ensureConfigExists creates the config file with defaults if it doesn't exist
+// ensureConfigExists creates the config file with defaults if it doesn't
+// exist, in the format given by its extension
 func ensureConfigExists(configPath string) error {
 	if _, err := os.Stat(configPath); err == nil {
 		// File exists
@@ -205,9 +729,19 @@ func ensureConfigExists(configPath string) error {
 # Environment: development | production
 env: development
 
-# HTTP Server configuration
+# HTTP Server configuration. An empty host listens on 127.0.0.1 in
+# development and on all interfaces in production.
 server:
+  host: ""
   port: 8080
+  # Proxies (CIDRs or addresses) whose X-Forwarded-For and X-Real-IP headers
+  # are trusted for the client address. Other peers cannot spoof it.
+  trusted_proxies:
+    - 127.0.0.0/8
+    - ::1/128
+  # Secret agents present to register their node (simplify agent
+  # --join-token). Empty refuses all node registrations.
+  join_token: ""
   read_timeout: 30      # seconds
   write_timeout: 30     # seconds
   idle_timeout: 120     # seconds
@@ -216,8 +750,108 @@ server:
 # Database configuration
 database:
   path: /var/lib/simplify/data.db
+  # Key that encrypts secrets, generated on first start. Empty uses
+  # secret.key next to the database. Back it up separately from the data.
+  secret_key_file: ""
+
+# Logging: level is debug | info | warn | error and format is text | console | json
+# (empty uses the env default).
+# Set file.path to also write server logs to a size-rotated JSON file.
+logging:
+  level: ""
+  format: ""
+  file:
+    path: ""
+    max_size: 100    # megabytes
+    max_backups: 5
+    max_age: 30      # days
+  # Each second, log the first 'initial' entries with the same message, then
+  # every 'thereafter'-th one. Unset 'enabled' samples in production only.
+  sampling:
+    initial: 100
+    thereafter: 100
+  # Per-module levels, replacing 'level' for that module in either direction:
+  # reconciler, server, http (API request logs) and container (Podman calls).
+  # "off" silences a module.
+  levels: {}
+
+# Reconciliation loop
+reconciler:
+  interval: 10  # seconds
+
+# Container CPU, memory and network usage, sampled for each application
+# every 'interval' seconds (0 disables) and kept for 'retention' hours.
+metrics:
+  interval: 15   # seconds
+  retention: 24  # hours
+
+# Error reporting to a Sentry-compatible service (Sentry, GlitchTip).
+# Panics, internal API errors and reconcile failures are sent when dsn is set.
+reporting:
+  dsn: ""
+  sample_rate: 1.0
+
+# OpenTelemetry tracing, exported over OTLP/HTTP. An empty endpoint uses
+# OTEL_EXPORTER_OTLP_ENDPOINT, or localhost:4318.
+tracing:
+  enabled: false
+  endpoint: ""
+  insecure: false
+  sample_ratio: 1.0
+
+# Images the build pipeline runs in. Builds clone the project repository
+# with git_image, then build its Containerfile with podman_image or run
+# Cloud Native Buildpacks with pack_image and builder.
+build:
+  git_image: docker.io/alpine/git:latest
+  podman_image: quay.io/podman/stable:latest
+  pack_image: docker.io/buildpacksio/pack:latest
+  builder: docker.io/paketobuildpacks/builder-jammy-base:latest
+
+# Scheduled backups of the database, and of the listed volumes as tar
+# archives, to a directory or an S3-compatible bucket. The schedule is a
+# cron expression in the server's local time, e.g. "0 3 * * *" or @daily.
+# The newest 'retain' backups are kept (0 keeps all).
+backup:
+  schedule: ""
+  retain: 7
+  dir: ""
+  volumes: []
+  # s3:
+  #   endpoint: https://s3.eu-central-1.amazonaws.com
+  #   bucket: my-backups
+  #   prefix: simplify/
+  #   region: eu-central-1
+  #   access_key: ${env:S3_ACCESS_KEY}
+  #   secret_key: ${env:S3_SECRET_KEY}
+  #   path_style: false   # true for MinIO
+
+# Notifications for deploys, crash loops and health changes. Routes send
+# the events they match (all when 'events' is empty) of the teams they
+# list (all when 'teams' is empty) to named notifiers, for example:
+#
+# notifications:
+#   notifiers:
+#     ops:
+#       type: slack            # slack | webhook | smtp
+#       url: https://hooks.slack.com/services/...
+#   routes:
+#     - teams: [payments]
+#       events: [deploy.failed, app.crash_loop]
+#       notifiers: [ops]
+notifications:
+  notifiers: {}
+  routes: []
 `)
 
+	format, err := FileFormat(configPath)
+	if err != nil {
+		return err
+	}
+	if defaultConfig, err = convertConfig(format, defaultConfig); err != nil {
+		return fmt.Errorf("converting default config: %w", err)
+	}
+
 	if err := os.WriteFile(configPath, defaultConfig, 0o600); err != nil {
 		return fmt.Errorf("writing default config: %w", err)
 	}
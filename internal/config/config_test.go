@@ -1,9 +1,11 @@
 package config
 
 import (
+	"net/netip"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -186,6 +188,28 @@ database:
 	assert.Equal(t, "/var/data/override.db", cfg.Database.Path)
 }
 
+// TestLoad_LoggingEnvOverride tests the log level and format env overrides
+func TestLoad_LoggingEnvOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("logging:\n  level: info\n  format: text\n"), 0o644)
+	require.NoError(t, err)
+
+	t.Setenv("SIMPLIFY_LOG_LEVEL", "error")
+	t.Setenv("SIMPLIFY_LOG_FORMAT", "json")
+
+	err = Load(configPath)
+	require.NoError(t, err)
+
+	cfg := Get()
+	assert.Equal(t, "error", cfg.Logging.Level)
+	assert.Equal(t, "json", cfg.Logging.Format)
+
+	t.Setenv("SIMPLIFY_LOG_FORMAT", "xml")
+	assert.Error(t, Load(configPath))
+}
+
 // TestLoad_CreatesDefaultConfig tests that a default config is created if missing
 func TestLoad_CreatesDefaultConfig(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -303,10 +327,209 @@ func TestIsProduction(t *testing.T) {
 	assert.False(t, IsDevelopment())
 }
 
+// TestReload tests that Reload swaps in a changed file and keeps the old config on errors
+func TestReload(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("env: development\nreconciler:\n  interval: 10\n"), 0o644)
+	require.NoError(t, err)
+	require.NoError(t, Load(configPath))
+	assert.Equal(t, 10*time.Second, Get().ReconcileInterval())
+
+	err = os.WriteFile(configPath, []byte("env: development\nlogging:\n  level: warn\nreconciler:\n  interval: 30\n"), 0o644)
+	require.NoError(t, err)
+
+	prev, cfg, err := Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, prev.ReconcileInterval())
+	assert.Equal(t, 30*time.Second, cfg.ReconcileInterval())
+	assert.Equal(t, "warn", Get().Logging.Level)
+
+	// An invalid file keeps the current configuration
+	err = os.WriteFile(configPath, []byte("logging:\n  level: loud\n"), 0o644)
+	require.NoError(t, err)
+
+	_, _, err = Reload()
+	assert.Error(t, err)
+	assert.Equal(t, "warn", Get().Logging.Level)
+}
+
+// TestListenAddr tests the default listen host per env, the local client URL
+// and trusted proxy parsing
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		host     string
+		listen   string
+		localURL string
+	}{
+		{name: "development default", env: EnvDevelopment, listen: "127.0.0.1:8080", localURL: "http://127.0.0.1:8080"},
+		{name: "production default", env: EnvProduction, listen: ":8080", localURL: "http://127.0.0.1:8080"},
+		{name: "all interfaces", env: EnvDevelopment, host: "0.0.0.0", listen: "0.0.0.0:8080", localURL: "http://127.0.0.1:8080"},
+		{name: "private address", env: EnvProduction, host: "10.0.0.5", listen: "10.0.0.5:8080", localURL: "http://10.0.0.5:8080"},
+		{name: "ipv6 loopback", env: EnvProduction, host: "::1", listen: "[::1]:8080", localURL: "http://[::1]:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Env: tt.env, Server: ServerConfig{Host: tt.host, Port: 8080}}
+			assert.Equal(t, tt.listen, cfg.ListenAddr())
+			assert.Equal(t, tt.localURL, cfg.LocalURL())
+		})
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: localhost\n"), 0o644))
+	require.NoError(t, Load(configPath))
+	assert.Equal(t, "localhost:8080", Get().ListenAddr())
+
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+		Get().TrustedProxies(), "loopback proxies are trusted by default")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  trusted_proxies: [10.0.0.0/8, 192.168.1.10]\n"), 0o644))
+	require.NoError(t, Load(configPath))
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")},
+		Get().TrustedProxies())
+
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  trusted_proxies: [10.0.0.0/33]\n"), 0o644))
+	assert.Error(t, Load(configPath))
+
+	require.NoError(t, os.WriteFile(configPath, []byte("server:\n  host: \"not a host\"\n"), 0o644))
+	err := Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid server host")
+}
+
+// TestReconcileInterval_Default tests the fallback for an unset interval
+func TestReconcileInterval_Default(t *testing.T) {
+	cfg := &Config{}
+	assert.Equal(t, DefaultReconcileInterval*time.Second, cfg.ReconcileInterval())
+}
+
+// TestLoad_LoggingFile tests the rotation defaults and validation of file logging
+func TestLoad_LoggingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("logging:\n  file:\n    path: /var/log/simplify.log\n"), 0o644)
+	require.NoError(t, err)
+	require.NoError(t, Load(configPath))
+
+	f := Get().Logging.File
+	assert.Equal(t, "/var/log/simplify.log", f.Path)
+	assert.Equal(t, DefaultLogMaxSize, f.MaxSize)
+	assert.Equal(t, DefaultLogMaxBackups, f.MaxBackups)
+	assert.Equal(t, DefaultLogMaxAge, f.MaxAge)
+
+	err = os.WriteFile(configPath, []byte("logging:\n  file:\n    path: /var/log/simplify.log\n    max_size: 0\n"), 0o644)
+	require.NoError(t, err)
+	err = Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_size")
+
+	err = os.WriteFile(configPath, []byte("logging:\n  file:\n    path: /var/log/simplify.log\n    max_age: -1\n"), 0o644)
+	require.NoError(t, err)
+	assert.Error(t, Load(configPath))
+}
+
+// TestLoad_LoggingSamplingAndComponents tests sampling defaults and component levels
+func TestLoad_LoggingSamplingAndComponents(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("env: production\nlogging:\n  components:\n    reconciler: warn\n    http: off\n"), 0o644)
+	require.NoError(t, err)
+	require.NoError(t, Load(configPath))
+
+	cfg := Get()
+	assert.True(t, cfg.LogSampling(), "sampling defaults to on in production")
+	assert.Equal(t, DefaultLogSampleInitial, cfg.Logging.Sampling.Initial)
+	assert.Equal(t, map[string]string{"reconciler": "warn", "http": "off"}, cfg.Logging.Components)
+
+	err = os.WriteFile(configPath, []byte("env: production\nlogging:\n  sampling:\n    enabled: false\n"), 0o644)
+	require.NoError(t, err)
+	require.NoError(t, Load(configPath))
+	assert.False(t, Get().LogSampling())
+
+	err = os.WriteFile(configPath, []byte("logging:\n  components:\n    reconciler: quiet\n"), 0o644)
+	require.NoError(t, err)
+	err = Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconciler")
+}
+
+// TestLoad_Layers tests that config.d overlays and the profile file are
+// merged over the base config in order
+func TestLoad_Layers(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	overlayDir := filepath.Join(tmpDir, OverlayDirName)
+	require.NoError(t, os.Mkdir(overlayDir, 0o755))
+
+	write := func(path, content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write(configPath, "server:\n  port: 8080\n  read_timeout: 10\ndatabase:\n  path: /tmp/base.db\n")
+	write(filepath.Join(overlayDir, "10-port.yaml"), "server:\n  port: 9000\n")
+	write(filepath.Join(overlayDir, "20-port.yaml"), "server:\n  port: 9100\n")
+	write(filepath.Join(overlayDir, "30-timeout.toml"), "[server]\nwrite_timeout = 45\n")
+	write(filepath.Join(overlayDir, "notes.txt"), "ignored")
+	write(filepath.Join(tmpDir, "config.staging.yaml"), "env: production\ndatabase:\n  path: /tmp/staging.db\n")
+
+	require.NoError(t, Load(configPath))
+	cfg := Get()
+	assert.Equal(t, 9100, cfg.Server.Port, "later overlays win")
+	assert.Equal(t, 10, cfg.Server.ReadTimeout, "unset keys keep the base value")
+	assert.Equal(t, 45, cfg.Server.WriteTimeout, "overlays can use another format")
+	assert.Equal(t, "/tmp/base.db", cfg.Database.Path)
+	assert.Equal(t, EnvDevelopment, cfg.Env)
+
+	SetProfile("staging")
+	t.Cleanup(func() { SetProfile("") })
+
+	require.NoError(t, Load(configPath))
+	cfg = Get()
+	assert.Equal(t, EnvProduction, cfg.Env)
+	assert.Equal(t, "/tmp/staging.db", cfg.Database.Path)
+	assert.Equal(t, 9100, cfg.Server.Port)
+
+	validated, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/staging.db", validated.Database.Path)
+
+	// A selected profile must exist
+	SetProfile("prod")
+	err = Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config.prod.yaml")
+
+	SetProfile("../etc")
+	assert.Error(t, Load(configPath))
+}
+
+// TestLoad_LoggingLevels tests per-module levels and the components alias
+func TestLoad_LoggingLevels(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	content := "logging:\n  level: info\n  components:\n    http: off\n    reconciler: warn\n  levels:\n    reconciler: debug\n    container: warn\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o644))
+	require.NoError(t, Load(configPath))
+
+	assert.Equal(t, map[string]string{"http": "off", "reconciler": "debug", "container": "warn"}, Get().LogLevels(),
+		"levels win over the older components key")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("logging:\n  levels:\n    server: loud\n"), 0o644))
+	err := Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server")
+}
+
 // TestGet_BeforeLoad tests Get returns defaults before Load is called
 func TestGet_BeforeLoad(t *testing.T) {
 	// Reset global config
-	globalConfig = nil
+	globalConfig.Store(nil)
 
 	cfg := Get()
 	assert.NotNil(t, cfg)
@@ -444,3 +667,80 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestLoad_Notifications tests loading notifiers and routes
+func TestLoad_Notifications(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := `
+env: development
+notifications:
+  notifiers:
+    ops-slack:
+      type: slack
+      url: https://hooks.slack.com/services/T000/B000/XXXX
+    oncall:
+      type: smtp
+      host: smtp.example.com
+      from: simplify@example.com
+      to: [oncall@example.com]
+  routes:
+    - teams: [payments]
+      events: [deploy.failed, app.crash_loop]
+      notifiers: [ops-slack, oncall]
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+
+	require.NoError(t, Load(configPath))
+
+	cfg := Get()
+	require.Len(t, cfg.Notifications.Notifiers, 2)
+	assert.Equal(t, NotifierSMTP, cfg.Notifications.Notifiers["oncall"].Type)
+	assert.Equal(t, []string{"oncall@example.com"}, cfg.Notifications.Notifiers["oncall"].To)
+	require.Len(t, cfg.Notifications.Routes, 1)
+	assert.Equal(t, []string{"ops-slack", "oncall"}, cfg.Notifications.Routes[0].Notifiers)
+
+	content += "    - notifiers: [pager]\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	err := Load(configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown notifier "pager"`)
+}
+
+func TestLoad_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := `
+env: development
+backup:
+  schedule: "0 3 * * *"
+  volumes: [pgdata]
+  s3:
+    endpoint: http://minio:9000
+    bucket: backups
+    access_key: key
+    secret_key: secret
+    path_style: true
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	require.NoError(t, Load(configPath))
+
+	cfg := Get()
+	assert.True(t, cfg.Backup.Enabled())
+	assert.Equal(t, DefaultBackupRetain, cfg.Backup.Retain)
+	assert.True(t, cfg.Backup.S3.PathStyle)
+
+	tests := map[string]string{
+		"backup:\n  schedule: \"@daily\"\n":                               "a schedule needs a dir or s3 target",
+		"backup:\n  dir: backups\n":                                       "dir must be an absolute path",
+		"backup:\n  dir: /backups\n  retain: -1\n":                        "retain must not be negative",
+		"backup:\n  s3:\n    endpoint: minio:9000\n    bucket: backups\n": "s3 endpoint must be an http or https URL",
+		"backup:\n  dir: /backups\n  s3:\n    bucket: backups\n":          "set either dir or s3, not both",
+	}
+	for content, want := range tests {
+		require.NoError(t, os.WriteFile(configPath, []byte("env: development\n"+content), 0o600))
+		err := Load(configPath)
+		require.Error(t, err, content)
+		assert.Contains(t, err.Error(), want)
+	}
+}
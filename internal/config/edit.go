@@ -0,0 +1,234 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// Keys returns every supported configuration key in dotted form, sorted
+func Keys() []string {
+	v := viper.New()
+	setDefaults(v)
+	keys := v.AllKeys()
+	slices.Sort(keys)
+	return keys
+}
+
+// IsKey reports whether key is a supported configuration key
+func IsKey(key string) bool {
+	return slices.Contains(Keys(), key)
+}
+
+// Settings returns the effective configuration loaded by Load as a nested map,
+// including defaults and environment overrides
+func Settings() map[string]any {
+	return viper.AllSettings()
+}
+
+// Value returns the effective value of a dotted configuration key
+func Value(key string) (any, error) {
+	if !IsKey(key) {
+		return nil, fmt.Errorf("unknown config key %q (valid keys: %s)", key, strings.Join(Keys(), ", "))
+	}
+	return viper.Get(key), nil
+}
+
+// ValidateFile loads the config file at path the same way the server does at
+// startup (defaults and environment overrides included) and validates it.
+// Overlays and the selected profile are merged over it, as in Load.
+// Unlike Load, it never creates the file and leaves the global config untouched.
+func ValidateFile(path string) (*Config, error) {
+	format, err := FileFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // config path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	layers, err := Layers(path)
+	if err != nil {
+		return nil, err
+	}
+	return validateBytes(data, format, layers)
+}
+
+// validateBytes parses config content in format, merges the overlay files
+// in layers over it and validates the result
+func validateBytes(data []byte, format string, layers []string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType(format)
+	if err := bindEnvVariables(v); err != nil {
+		return nil, err
+	}
+	setDefaults(v)
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	if err := mergeLayers(v, layers); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := v.UnmarshalExact(cfg, expandReferences()); err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Set writes a single dotted key into the config file at path. Unrelated
+// keys are preserved, and so are comments in YAML files. The change is
+// validated before anything is written, so an invalid value never reaches
+// the file.
+func Set(path, key, value string) error {
+	if !IsKey(key) {
+		return fmt.Errorf("unknown config key %q (valid keys: %s)", key, strings.Join(Keys(), ", "))
+	}
+
+	format, err := FileFormat(path)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureConfigExists(path); err != nil {
+		return fmt.Errorf("ensuring config exists: %w", err)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // config path is provided by the operator
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	if format == FormatYAML {
+		data, err = setYAML(path, data, key, value)
+	} else {
+		data, err = setEncoded(format, data, key, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	layers, err := Layers(path)
+	if err != nil {
+		return err
+	}
+	if _, err := validateBytes(data, format, layers); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// setYAML sets key in YAML content through its node tree, so comments survive
+func setYAML(path string, data []byte, key, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	if doc.Kind == 0 {
+		// Empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s is not a YAML mapping", path)
+	}
+
+	setNode(doc.Content[0], strings.Split(key, "."), value)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encoding config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding config file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// setEncoded sets key in JSON or TOML content by decoding it to a map and
+// encoding it again
+func setEncoded(format string, data []byte, key, value string) ([]byte, error) {
+	m, err := decodeConfig(format, data)
+	if err != nil {
+		return nil, err
+	}
+	setMapValue(m, key, value)
+	return encodeConfig(format, m)
+}
+
+// setNode sets the scalar at path inside a mapping node, creating
+// intermediate mappings as needed
+func setNode(m *yaml.Node, path []string, value string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != path[0] {
+			continue
+		}
+		child := m.Content[i+1]
+		if len(path) == 1 {
+			setScalar(child, value)
+			return
+		}
+		if child.Kind != yaml.MappingNode {
+			*child = yaml.Node{Kind: yaml.MappingNode}
+		}
+		setNode(child, path[1:], value)
+		return
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	if len(path) == 1 {
+		setScalar(child, value)
+	} else {
+		setNode(child, path[1:], value)
+	}
+	m.Content = append(m.Content, keyNode, child)
+}
+
+// setScalar replaces a node with a plain scalar, letting YAML resolve its
+// type (so "8080" becomes an int), while keeping any line comment
+func setScalar(n *yaml.Node, value string) {
+	comment := n.LineComment
+	*n = yaml.Node{Kind: yaml.ScalarNode, Value: value, LineComment: comment}
+}
+
+// writeFileAtomic replaces path with data via a temp file and rename,
+// keeping the config file mode private
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentedConfig = `# Simplify Configuration
+env: development
+
+server:
+  port: 8080 # HTTP port
+  read_timeout: 30
+
+database:
+  path: /tmp/test.db
+`
+
+// TestValidateFile_Valid tests validating a correct config file
+func TestValidateFile_Valid(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(commentedConfig), 0o600))
+
+	cfg, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, 30, cfg.Server.WriteTimeout, "defaults should apply")
+}
+
+// TestValidateFile_Invalid tests that validation errors are reported
+func TestValidateFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{name: "bad env", content: "env: staging\n", errorMsg: "invalid env value"},
+		{name: "bad port", content: "server:\n  port: 70000\n", errorMsg: "invalid server port"},
+		{name: "unknown key", content: "server:\n  prot: 8080\n", errorMsg: "prot"},
+		{name: "malformed", content: "env: [oops", errorMsg: "parsing config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.content), 0o600))
+
+			_, err := ValidateFile(configPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+// TestValidateFile_Missing tests that a missing file is an error, not created
+func TestValidateFile_Missing(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	_, err := ValidateFile(configPath)
+	assert.Error(t, err)
+	assert.NoFileExists(t, configPath)
+}
+
+// TestSet_PreservesComments tests that Set changes one value and keeps the rest
+func TestSet_PreservesComments(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(commentedConfig), 0o600))
+
+	require.NoError(t, Set(configPath, "server.port", "9090"))
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Simplify Configuration")
+	assert.Contains(t, string(data), "port: 9090 # HTTP port")
+
+	cfg, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "/tmp/test.db", cfg.Database.Path)
+}
+
+// TestSet_AddsMissingKey tests that Set creates keys absent from the file
+func TestSet_AddsMissingKey(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(commentedConfig), 0o600))
+
+	require.NoError(t, Set(configPath, "server.idle_timeout", "300"))
+
+	cfg, err := ValidateFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 300, cfg.Server.IdleTimeout)
+	assert.Equal(t, 8080, cfg.Server.Port)
+}
+
+// TestSet_RejectsInvalid tests that invalid values never reach the file
+func TestSet_RejectsInvalid(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(commentedConfig), 0o600))
+
+	err := Set(configPath, "server.port", "0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid server port")
+
+	err = Set(configPath, "server.read_timeout", "soon")
+	require.Error(t, err)
+
+	err = Set(configPath, "server.bogus", "1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown config key")
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, commentedConfig, string(data))
+}
+
+// TestFormats tests loading, creating and editing JSON and TOML config files
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "json", file: "config.json", content: `{"env": "production", "server": {"port": 8181}}`},
+		{name: "toml", file: "config.toml", content: "env = \"production\"\n\n[server]\nport = 8181\n"},
+		{name: "yml", file: "config.yml", content: "env: production\nserver:\n  port: 8181\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.content), 0o600))
+
+			require.NoError(t, Load(configPath))
+			assert.Equal(t, EnvProduction, Get().Env)
+			assert.Equal(t, 8181, Get().Server.Port)
+
+			require.NoError(t, Set(configPath, "server.port", "9090"))
+			require.NoError(t, Set(configPath, "logging.level", "warn"))
+			cfg, err := ValidateFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, 9090, cfg.Server.Port)
+			assert.Equal(t, "warn", cfg.Logging.Level)
+			assert.Equal(t, EnvProduction, cfg.Env)
+		})
+
+		t.Run(tt.name+" default", func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), tt.file)
+
+			require.NoError(t, Load(configPath))
+			assert.FileExists(t, configPath)
+			assert.Equal(t, DefaultServerPort, Get().Server.Port)
+
+			_, err := ValidateFile(configPath)
+			assert.NoError(t, err, "generated default config should be valid")
+		})
+	}
+
+	_, err := FileFormat("config.ini")
+	assert.Error(t, err)
+}
+
+// TestKeys tests that every default is exposed as a key
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	assert.Contains(t, keys, "env")
+	assert.Contains(t, keys, "server.port")
+	assert.Contains(t, keys, "database.path")
+	assert.True(t, IsKey("server.shutdown_timeout"))
+	assert.False(t, IsKey("server"))
+}
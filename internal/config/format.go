@@ -0,0 +1,117 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// Supported config file formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// formatExtensions maps config file extensions to their format
+var formatExtensions = map[string]string{
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".json": FormatJSON,
+	".toml": FormatTOML,
+}
+
+// FileFormat returns the format of the config file at path from its
+// extension. Files without an extension are read as YAML.
+func FileFormat(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return FormatYAML, nil
+	}
+	format, ok := formatExtensions[ext]
+	if !ok {
+		return "", fmt.Errorf("unsupported config format %q (use .yaml, .yml, .json or .toml)", ext)
+	}
+	return format, nil
+}
+
+// decodeConfig parses config content in format into a map
+func decodeConfig(format string, data []byte) (map[string]any, error) {
+	m := map[string]any{}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &m)
+	case FormatTOML:
+		err = toml.Unmarshal(data, &m)
+	default:
+		err = yaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file: %w", err)
+	}
+	if m == nil {
+		// Empty YAML document
+		m = map[string]any{}
+	}
+	return m, nil
+}
+
+// encodeConfig writes a config map in format
+func encodeConfig(format string, m map[string]any) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch format {
+	case FormatJSON:
+		data, err = json.MarshalIndent(m, "", "  ")
+		data = append(data, '\n')
+	case FormatTOML:
+		data, err = toml.Marshal(m)
+	default:
+		data, err = yaml.Marshal(m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encoding config file: %w", err)
+	}
+	return data, nil
+}
+
+// convertConfig re-encodes YAML config content in format. Comments are
+// only kept for YAML.
+func convertConfig(format string, data []byte) ([]byte, error) {
+	if format == FormatYAML {
+		return data, nil
+	}
+	m, err := decodeConfig(FormatYAML, data)
+	if err != nil {
+		return nil, err
+	}
+	return encodeConfig(format, m)
+}
+
+// setMapValue sets the dotted key in m, creating intermediate maps as
+// needed. The value is typed the way YAML would type it, so "8080" is
+// stored as a number.
+func setMapValue(m map[string]any, key, value string) {
+	var typed any
+	if err := yaml.Unmarshal([]byte(value), &typed); err != nil || typed == nil {
+		typed = value
+	}
+
+	path := strings.Split(key, ".")
+	for _, part := range path[:len(path)-1] {
+		child, ok := m[part].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			m[part] = child
+		}
+		m = child
+	}
+	m[path[len(path)-1]] = typed
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// referencePattern matches ${env:NAME} and ${file:/path} references in config values
+var referencePattern = regexp.MustCompile(`\$\{(env|file):([^}]*)\}`)
+
+// expandReferences is a decoder option that resolves secret references while
+// the config is unmarshaled. Viper keeps the raw values, so 'config view'
+// and 'config get' show the reference rather than the secret.
+func expandReferences() viper.DecoderConfigOption {
+	return func(c *mapstructure.DecoderConfig) {
+		c.DecodeHook = mapstructure.ComposeDecodeHookFunc(expandReferencesHook, c.DecodeHook)
+	}
+}
+
+// expandReferencesHook expands references in string values before they are
+// converted to their field types, so numeric settings can use them too
+func expandReferencesHook(from, _ reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	s, ok := data.(string)
+	if !ok {
+		return data, nil
+	}
+	return expandValue(s)
+}
+
+// expandValue replaces every ${env:NAME} with the environment variable NAME
+// and every ${file:/path} with the contents of the file, without the
+// trailing newline. A missing variable or unreadable file is an error, so a
+// typo never silently becomes an empty password.
+func expandValue(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var expandErr error
+	result := referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		m := referencePattern.FindStringSubmatch(ref)
+		kind, target := m[1], strings.TrimSpace(m[2])
+		if target == "" {
+			expandErr = fmt.Errorf("empty %s reference in config value", kind)
+			return ""
+		}
+
+		switch kind {
+		case "env":
+			value, ok := os.LookupEnv(target)
+			if !ok {
+				expandErr = fmt.Errorf("environment variable %s referenced in config is not set", target)
+			}
+			return value
+		default:
+			data, err := os.ReadFile(target) //nolint:gosec // paths come from the operator's config file
+			if err != nil {
+				expandErr = fmt.Errorf("reading file referenced in config: %w", err)
+			}
+			return strings.TrimRight(string(data), "\r\n")
+		}
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
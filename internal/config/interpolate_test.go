@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandValue(t *testing.T) {
+	t.Setenv("SIMPLIFY_TEST_SECRET", "s3cret")
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("from-file\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "plain value", input: "/var/lib/simplify/data.db", expected: "/var/lib/simplify/data.db"},
+		{name: "env reference", input: "${env:SIMPLIFY_TEST_SECRET}", expected: "s3cret"},
+		{name: "file reference trims newline", input: "${file:" + secretFile + "}", expected: "from-file"},
+		{name: "embedded references", input: "user:${env:SIMPLIFY_TEST_SECRET}@host", expected: "user:s3cret@host"},
+		{name: "unknown kind is left alone", input: "${vault:x}", expected: "${vault:x}"},
+		{name: "missing env", input: "${env:SIMPLIFY_TEST_UNSET}", wantErr: true},
+		{name: "missing file", input: "${file:/nonexistent/secret}", wantErr: true},
+		{name: "empty reference", input: "${env:}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandValue(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+// TestLoad_ExpandsReferences tests that references are resolved in loaded config,
+// including numeric settings
+func TestLoad_ExpandsReferences(t *testing.T) {
+	t.Setenv("SIMPLIFY_TEST_DB", "/tmp/secret.db")
+	t.Setenv("SIMPLIFY_TEST_PORT", "9191")
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "database:\n  path: ${env:SIMPLIFY_TEST_DB}\nserver:\n  port: ${env:SIMPLIFY_TEST_PORT}\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+
+	require.NoError(t, Load(configPath))
+	assert.Equal(t, "/tmp/secret.db", Get().Database.Path)
+	assert.Equal(t, 9191, Get().Server.Port)
+
+	// The raw reference is kept for display
+	value, err := Value("database.path")
+	require.NoError(t, err)
+	assert.Equal(t, "${env:SIMPLIFY_TEST_DB}", value)
+
+	_, err = ValidateFile(configPath)
+	assert.NoError(t, err)
+}
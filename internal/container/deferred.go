@@ -0,0 +1,305 @@
+package container
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// Deferred is an engine whose connection is made after start up, such as
+// when the Podman socket only appears late during boot. Until Connect is
+// called every call fails with an UnavailableError, so callers can keep
+// serving what they know without the engine.
+type Deferred struct {
+	engine *Client
+	reason error // why the engine is not connected yet, nil if unknown
+	mu     sync.RWMutex
+}
+
+// NewDeferred returns an engine that is not connected yet
+func NewDeferred() *Deferred {
+	return &Deferred{}
+}
+
+// Connect makes d forward its calls to c
+func (d *Deferred) Connect(c *Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.engine, d.reason = c, nil
+}
+
+// SetReason records why the engine is not connected yet, which calls
+// report until Connect
+func (d *Deferred) SetReason(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reason = err
+}
+
+// Connected reports whether Connect has been called
+func (d *Deferred) Connected() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.engine != nil
+}
+
+// client returns the connected engine, or an UnavailableError before Connect
+func (d *Deferred) client() (*Client, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.engine != nil {
+		return d.engine, nil
+	}
+	msg := "container engine is not connected yet"
+	if base := errors.GetBaseError(d.reason); base != nil {
+		msg += ": " + base.Message
+	} else if d.reason != nil {
+		msg += ": " + d.reason.Error()
+	}
+	return nil, errors.NewUnavailableErrorWithCause("podman", msg, d.reason)
+}
+
+func (d *Deferred) Run(ctx context.Context, name string, opts RunOptions) (string, error) {
+	c, err := d.client()
+	if err != nil {
+		return "", err
+	}
+	return c.Run(ctx, name, opts)
+}
+
+func (d *Deferred) Stop(ctx context.Context, name string, timeout *uint) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.Stop(ctx, name, timeout)
+}
+
+func (d *Deferred) Remove(ctx context.Context, name string, force bool) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.Remove(ctx, name, force)
+}
+
+func (d *Deferred) List(ctx context.Context, all bool) ([]ContainerInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.List(ctx, all)
+}
+
+func (d *Deferred) Restart(ctx context.Context, name string) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.Restart(ctx, name)
+}
+
+func (d *Deferred) Logs(ctx context.Context, name string, follow bool, tail string) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.Logs(ctx, name, follow, tail)
+}
+
+func (d *Deferred) StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.StreamLogs(ctx, name, opts, fn)
+}
+
+func (d *Deferred) Attach(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.Attach(ctx, name, stdin, stdout, stderr)
+}
+
+func (d *Deferred) Exec(ctx context.Context, name string, opts ExecOptions) (int, error) {
+	c, err := d.client()
+	if err != nil {
+		return -1, err
+	}
+	return c.Exec(ctx, name, opts)
+}
+
+func (d *Deferred) GetContainer(ctx context.Context, nameOrID string) (*ContainerInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetContainer(ctx, nameOrID)
+}
+
+func (d *Deferred) InspectImage(ctx context.Context, image string) (*ImageInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.InspectImage(ctx, image)
+}
+
+func (d *Deferred) CreatePod(ctx context.Context, name string, opts PodOptions) (string, error) {
+	c, err := d.client()
+	if err != nil {
+		return "", err
+	}
+	return c.CreatePod(ctx, name, opts)
+}
+
+func (d *Deferred) RemovePod(ctx context.Context, nameOrID string, force bool) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.RemovePod(ctx, nameOrID, force)
+}
+
+func (d *Deferred) PodExists(ctx context.Context, nameOrID string) (bool, error) {
+	c, err := d.client()
+	if err != nil {
+		return false, err
+	}
+	return c.PodExists(ctx, nameOrID)
+}
+
+func (d *Deferred) ListPods(ctx context.Context) ([]PodInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListPods(ctx)
+}
+
+func (d *Deferred) InspectPod(ctx context.Context, nameOrID string) (*PodInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.InspectPod(ctx, nameOrID)
+}
+
+func (d *Deferred) CreateNetwork(ctx context.Context, name string) (string, error) {
+	c, err := d.client()
+	if err != nil {
+		return "", err
+	}
+	return c.CreateNetwork(ctx, name)
+}
+
+func (d *Deferred) RemoveNetwork(ctx context.Context, nameOrID string) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.RemoveNetwork(ctx, nameOrID)
+}
+
+func (d *Deferred) ListNetworks(ctx context.Context) ([]NetworkInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListNetworks(ctx)
+}
+
+func (d *Deferred) CreateSecret(ctx context.Context, name string, data io.Reader, replace bool) (string, error) {
+	c, err := d.client()
+	if err != nil {
+		return "", err
+	}
+	return c.CreateSecret(ctx, name, data, replace)
+}
+
+func (d *Deferred) RemoveSecret(ctx context.Context, nameOrID string) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.RemoveSecret(ctx, nameOrID)
+}
+
+func (d *Deferred) CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error) {
+	c, err := d.client()
+	if err != nil {
+		return "", err
+	}
+	return c.CreateVolume(ctx, name, labels)
+}
+
+func (d *Deferred) RemoveVolume(ctx context.Context, name string, force bool) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.RemoveVolume(ctx, name, force)
+}
+
+func (d *Deferred) ListVolumes(ctx context.Context) ([]VolumeInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListVolumes(ctx)
+}
+
+func (d *Deferred) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.DiskUsage(ctx)
+}
+
+func (d *Deferred) SystemInfo(ctx context.Context) (*SystemInfo, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.SystemInfo(ctx)
+}
+
+func (d *Deferred) Stats(ctx context.Context, names []string) ([]ContainerStats, error) {
+	c, err := d.client()
+	if err != nil {
+		return nil, err
+	}
+	return c.Stats(ctx, names)
+}
+
+func (d *Deferred) RunTask(ctx context.Context, task TaskSpec, out io.Writer) (int, error) {
+	c, err := d.client()
+	if err != nil {
+		return 0, err
+	}
+	return c.RunTask(ctx, task, out)
+}
+
+func (d *Deferred) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.ExportVolume(ctx, name, w)
+}
+
+func (d *Deferred) ImportVolume(ctx context.Context, name string, r io.Reader) error {
+	c, err := d.client()
+	if err != nil {
+		return err
+	}
+	return c.ImportVolume(ctx, name, r)
+}
+
+// Ensure Deferred implements ContainerManager
+var _ ContainerManager = (*Deferred)(nil)
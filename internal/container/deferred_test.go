@@ -0,0 +1,32 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferredBeforeConnect(t *testing.T) {
+	d := NewDeferred()
+	assert.False(t, d.Connected())
+
+	_, err := d.List(context.Background(), true)
+	require.Error(t, err)
+	assert.True(t, errors.IsUnavailable(err))
+
+	d.SetReason(errors.NewUnavailableError("podman", "Podman socket not found: /run/podman/podman.sock"))
+	_, err = d.Stats(context.Background(), nil)
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Contains(t, err.Error(), "not connected yet: Podman socket not found")
+
+	d.SetReason(fmt.Errorf("dial unix: connection refused"))
+	err = d.RemoveVolume(context.Background(), "data", false)
+	assert.Contains(t, err.Error(), "connection refused")
+
+	d.Connect(&Client{ctx: context.Background()})
+	assert.True(t, d.Connected())
+}
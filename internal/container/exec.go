@@ -0,0 +1,94 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/containers/podman/v5/pkg/api/handlers"
+	"github.com/containers/podman/v5/pkg/bindings/containers"
+)
+
+// ExecOptions describes a command run in a running container
+type ExecOptions struct {
+	Stdin   io.Reader // nil runs the command without input
+	Stdout  io.Writer
+	Stderr  io.Writer // unused with TTY, which sends all output to Stdout
+	User    string
+	WorkDir string
+	Cmd     []string
+	Env     []string // KEY=VALUE, added to the container's environment
+	// TTY allocates a terminal for the command. When the caller's own stdin
+	// is a terminal it is switched to raw mode and resized with the command's.
+	TTY bool
+}
+
+// Exec runs a command in a running container until it exits or ctx is
+// canceled, and returns its exit code
+func (c *Client) Exec(ctx context.Context, name string, opts ExecOptions) (int, error) {
+	if len(opts.Cmd) == 0 {
+		return -1, fmt.Errorf("no command given")
+	}
+	log.DebugCtx(ctx, "Executing in container", "name", name, "cmd", opts.Cmd, "stdin", opts.Stdin != nil, "tty", opts.TTY)
+
+	config := new(handlers.ExecCreateConfig)
+	config.Cmd = opts.Cmd
+	config.Env = opts.Env
+	config.User = opts.User
+	config.WorkingDir = opts.WorkDir
+	config.Tty = opts.TTY
+	config.AttachStdin = opts.Stdin != nil
+	config.AttachStdout = true
+	config.AttachStderr = true
+	session, err := containers.ExecCreate(c.ctx, name, config)
+	if err != nil {
+		return -1, fmt.Errorf("creating exec session: %w", err)
+	}
+	defer func() {
+		if err := containers.ExecRemove(c.ctx, session, new(containers.ExecRemoveOptions).WithForce(true)); err != nil {
+			log.DebugCtx(ctx, "Failed to remove exec session", "name", name, "session", session, "error", err)
+		}
+	}()
+
+	// As with Attach, the bindings only stop reading once the command writes
+	// again, so cancel their context when ctx is done
+	execCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-execCtx.Done():
+		}
+	}()
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	start := new(containers.ExecStartAndAttachOptions).
+		WithOutputStream(stdout).
+		WithErrorStream(stderr).
+		WithAttachOutput(true).
+		WithAttachError(true)
+	if opts.Stdin != nil {
+		start = start.WithInputStream(*bufio.NewReader(opts.Stdin)).WithAttachInput(true)
+	}
+	if err := containers.ExecStartAndAttach(execCtx, session, start); err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return -1, fmt.Errorf("running exec session: %w", err)
+	}
+
+	inspect, err := containers.ExecInspect(c.ctx, session, nil)
+	if err != nil {
+		return -1, fmt.Errorf("inspecting exec session: %w", err)
+	}
+	log.DebugCtx(ctx, "Exec finished", "name", name, "exit_code", inspect.ExitCode)
+	return inspect.ExitCode, nil
+}
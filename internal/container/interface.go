@@ -2,20 +2,25 @@ package container
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
 // ContainerManager defines the interface for container operations.
 // This interface is used for mocking in tests.
 type ContainerManager interface {
-	Run(ctx context.Context, name, image string, ports map[uint16]uint16, env []string, labels map[string]string, podName string, networkName string) (string, error)
+	Run(ctx context.Context, name string, opts RunOptions) (string, error)
 	Stop(ctx context.Context, name string, timeout *uint) error
 	Remove(ctx context.Context, name string, force bool) error
 	List(ctx context.Context, all bool) ([]ContainerInfo, error)
+	Restart(ctx context.Context, name string) error
 	Logs(ctx context.Context, name string, follow bool, tail string) error
+	StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) error
+	Attach(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error
+	Exec(ctx context.Context, name string, opts ExecOptions) (int, error)
 	GetContainer(ctx context.Context, nameOrID string) (*ContainerInfo, error)
 	InspectImage(ctx context.Context, image string) (*ImageInfo, error)
-	CreatePod(ctx context.Context, name string, ports map[uint16]uint16) (string, error)
+	CreatePod(ctx context.Context, name string, opts PodOptions) (string, error)
 	RemovePod(ctx context.Context, nameOrID string, force bool) error
 	PodExists(ctx context.Context, nameOrID string) (bool, error)
 	ListPods(ctx context.Context) ([]PodInfo, error)
@@ -23,6 +28,13 @@ type ContainerManager interface {
 	CreateNetwork(ctx context.Context, name string) (string, error)
 	RemoveNetwork(ctx context.Context, nameOrID string) error
 	ListNetworks(ctx context.Context) ([]NetworkInfo, error)
+	CreateSecret(ctx context.Context, name string, data io.Reader, replace bool) (string, error)
+	RemoveSecret(ctx context.Context, nameOrID string) error
+	CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error)
+	RemoveVolume(ctx context.Context, name string, force bool) error
+	ListVolumes(ctx context.Context) ([]VolumeInfo, error)
+	DiskUsage(ctx context.Context) (*DiskUsage, error)
+	SystemInfo(ctx context.Context) (*SystemInfo, error)
 }
 
 // ImageInfo holds image metadata
@@ -31,21 +43,156 @@ type ImageInfo struct {
 	ExposedPorts []string `json:"exposed_ports"`
 }
 
+// PullProgress reports the state of an image pull. The engine reports
+// progress per layer rather than per byte, so Layers and Done count blobs.
+// Layers grows as the engine discovers them.
+type PullProgress struct {
+	Image  string `json:"image"`
+	Status string `json:"status"` // latest status line from the engine
+	Layers int    `json:"layers"`
+	Done   int    `json:"done"`
+}
+
+// Percent returns the share of discovered layers that are finished
+func (p PullProgress) Percent() int {
+	if p.Layers == 0 {
+		return 0
+	}
+	return p.Done * 100 / p.Layers
+}
+
+// DanglingImage is an untagged image left behind by pulls or builds
+type DanglingImage struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+// RunOptions configures a container created by Run
+type RunOptions struct {
+	Ports       map[uint16]uint16 // host port to container port, ignored in a pod
+	Labels      map[string]string
+	Health      *HealthCheck // nil for none
+	Image       string
+	Pod         string   // pod the container joins, empty for none
+	Network     string   // network the container joins, empty for the default
+	Env         []string // KEY=VALUE
+	Aliases     []string // DNS names of the container on Network, besides its name
+	Secrets     []SecretMount
+	Volumes     []VolumeMount
+	Resources   Resources
+	Interactive bool // keeps stdin open for clients attaching to the main process
+}
+
+// PodOptions configures a pod created by CreatePod. The zero value creates
+// a pod with the engine's defaults.
+type PodOptions struct {
+	Ports      map[uint16]uint16 // host port to container port
+	Labels     map[string]string
+	InfraImage string        // image of the infra container, empty for the engine default
+	Hostname   string        // empty for the pod name
+	Network    string        // network the pod joins, empty for the default
+	Volumes    []VolumeMount // mounted in every container of the pod
+	Resources  Resources     // limits shared by all containers of the pod
+}
+
 // PodInfo holds pod metadata from the container engine
 type PodInfo struct {
-	Created time.Time
-	ID      string
-	Name    string
-	Status  string
+	Created time.Time         `json:"created"`
+	Ports   map[string]string `json:"ports,omitempty"` // ContainerPort/Proto:HostIP:HostPort, only set by InspectPod
+	Labels  map[string]string `json:"labels,omitempty"`
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Status  string            `json:"status"`
 }
 
 // NetworkInfo holds network metadata from the container engine
 type NetworkInfo struct {
-	Created time.Time
-	ID      string
-	Name    string
-	Driver  string
-	Subnet  string
+	Created time.Time         `json:"created"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Driver  string            `json:"driver"`
+	Subnet  string            `json:"subnet"`
+}
+
+// NetworkDetails holds a network and the containers attached to it
+type NetworkDetails struct {
+	NetworkInfo
+	Containers []NetworkAttachment `json:"containers"`
+}
+
+// NetworkAttachment describes a container's interface on a network
+type NetworkAttachment struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
+// Event is a single container engine event, such as a container starting
+type Event struct {
+	Time       time.Time         `json:"time"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+}
+
+// SecretInfo holds secret metadata from the container engine
+type SecretInfo struct {
+	Created time.Time         `json:"created"`
+	Updated time.Time         `json:"updated"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Driver  string            `json:"driver"`
+}
+
+// SecretMount exposes an engine secret to a container as an environment
+// variable, a file, or both
+type SecretMount struct {
+	Secret string // name of the engine secret
+	Env    string // environment variable set to the value, empty for none
+	Target string // absolute path of a file holding the value, empty for none
+}
+
+// VolumeMount mounts a named engine volume, or a host path, into a container
+type VolumeMount struct {
+	Volume   string // name of the engine volume
+	Source   string // host file or directory bind-mounted instead of a volume
+	Target   string // absolute mount path in the container
+	ReadOnly bool
+}
+
+// Resources limits a container's CPU and memory. Zero values are unlimited.
+type Resources struct {
+	MemoryBytes int64
+	CPUs        float64 // cores
+}
+
+// HealthCheck is a command the engine runs in a container to check its health
+type HealthCheck struct {
+	Command     []string
+	Interval    time.Duration
+	Timeout     time.Duration
+	StartPeriod time.Duration
+	Retries     int
+}
+
+// VolumeInfo holds volume metadata from the container engine
+type VolumeInfo struct {
+	Created    time.Time         `json:"created"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Name       string            `json:"name"`
+	Driver     string            `json:"driver"`
+	Mountpoint string            `json:"mountpoint"`
+}
+
+// ManagedSecretName returns the engine secret that holds the value of the
+// Simplify secret name
+func ManagedSecretName(name string) string {
+	return "simplify-" + name
 }
 
 // Ensure Client implements ContainerManager
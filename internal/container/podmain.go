@@ -2,9 +2,15 @@
 package container
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/logger"
@@ -14,11 +20,22 @@ import (
 	"github.com/containers/podman/v5/pkg/bindings/images"
 	"github.com/containers/podman/v5/pkg/bindings/network"
 	"github.com/containers/podman/v5/pkg/bindings/pods"
+	"github.com/containers/podman/v5/pkg/bindings/secrets"
+	"github.com/containers/podman/v5/pkg/bindings/system"
+	"github.com/containers/podman/v5/pkg/bindings/volumes"
 	"github.com/containers/podman/v5/pkg/domain/entities"
 	"github.com/containers/podman/v5/pkg/specgen"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
 	nettypes "go.podman.io/common/libnetwork/types"
+	"go.podman.io/image/v5/manifest"
 )
 
+// LabelManaged marks containers, networks, secrets and volumes created by Simplify
+const LabelManaged = "simplify.managed"
+
+// log is the container engine's component logger, see logging.levels
+var log = logger.NewComponent("container")
+
 // Client wraps the Podman bindings
 type Client struct {
 	ctx context.Context
@@ -26,66 +43,131 @@ type Client struct {
 
 // ContainerInfo holds container information for listing
 type ContainerInfo struct {
-	Created      time.Time
-	Ports        map[string]string
-	Labels       map[string]string
-	ID           string
-	Name         string
-	Image        string
-	Status       string
-	IPAddress    string
-	ExposedPorts []string
-	PodID        string
-	Networks     []string
+	Created      time.Time         `json:"created"`
+	Ports        map[string]string `json:"ports"`
+	Labels       map[string]string `json:"labels"`
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	Status       string            `json:"status"`
+	Health       string            `json:"health,omitempty"` // starting, healthy or unhealthy; empty without a healthcheck
+	IPAddress    string            `json:"ip_address,omitempty"`
+	ExposedPorts []string          `json:"exposed_ports,omitempty"`
+	PodID        string            `json:"pod_id,omitempty"`
+	Networks     []string          `json:"networks,omitempty"`
 }
 
 // NewClient creates a new Podman client
 func NewClient(ctx context.Context) (*Client, error) {
-	logger.DebugCtx(ctx, "Connecting to Podman socket")
+	return NewClientWithSocket(ctx, getSocketPath())
+}
+
+// NewClientWithSocket creates a Podman client for an explicit socket URI
+// (e.g. unix:///run/podman/podman.sock or ssh://user@host/run/podman/podman.sock)
+func NewClientWithSocket(ctx context.Context, socketPath string) (*Client, error) {
+	log.DebugCtx(ctx, "Connecting to Podman socket")
 
-	socketPath := getSocketPath()
 	ctx, err := bindings.NewConnection(ctx, socketPath)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to podman: %w", err)
 	}
 
-	logger.DebugCtx(ctx, "Connected to Podman", "socket", socketPath)
+	log.DebugCtx(ctx, "Connected to Podman", "socket", socketPath)
 	return &Client{ctx: ctx}, nil
 }
 
-// Context returns the connection context for direct API calls if needed
-func (c *Client) Context() context.Context {
-	return c.ctx
+// shared holds the clients handed out by Shared, by socket URI
+var shared struct {
+	clients map[string]*Client
+	mu      sync.Mutex
 }
 
-// Run creates and starts a container
-func (c *Client) Run(ctx context.Context, name, image string, ports map[uint16]uint16, env []string, labels map[string]string, podName, networkName string) (string, error) {
-	logger.DebugCtx(ctx, "Checking if image exists", "image", image)
+// Shared returns the process-wide client of socketURI, or of the default
+// socket when it is empty, connecting on first use. Connecting negotiates
+// with the engine, so commands and subsystems reuse one client rather than
+// each paying for it. ctx only bounds connecting. Failed connections are
+// not cached, so the next call tries again.
+func Shared(ctx context.Context, socketURI string) (*Client, error) {
+	if socketURI == "" {
+		socketURI = getSocketPath()
+	}
 
-	// Pull image if not exists
-	exists, err := images.Exists(c.ctx, image, nil)
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	if c, ok := shared.clients[socketURI]; ok {
+		return c, nil
+	}
+	c, err := NewClientWithSocket(ctx, socketURI)
 	if err != nil {
-		return "", fmt.Errorf("checking image: %w", err)
+		return nil, err
+	}
+	// The connection outlives the context of whoever asked for it first
+	c.ctx = context.WithoutCancel(c.ctx)
+	if shared.clients == nil {
+		shared.clients = make(map[string]*Client)
 	}
+	shared.clients[socketURI] = c
+	return c, nil
+}
 
-	if !exists {
-		logger.InfoCtx(ctx, "Pulling image", "image", image)
-		_, err = images.Pull(c.ctx, image, nil)
-		if err != nil {
-			return "", fmt.Errorf("pulling image: %w", err)
-		}
-		logger.DebugCtx(ctx, "Image pulled successfully", "image", image)
+// Context returns the connection context for direct API calls if needed
+func (c *Client) Context() context.Context {
+	return c.ctx
+}
+
+// Run creates and starts a container
+func (c *Client) Run(ctx context.Context, name string, opts RunOptions) (string, error) {
+	if _, err := c.EnsureImage(ctx, opts.Image, nil); err != nil {
+		return "", err
 	}
 
 	// Create spec
-	s := specgen.NewSpecGenerator(image, false)
+	s := specgen.NewSpecGenerator(opts.Image, false)
 	s.Name = name
-	s.Env = envSliceToMap(env)
-	s.Labels = labels
+	s.Env = envSliceToMap(opts.Env)
+	s.Labels = opts.Labels
+	if opts.Interactive {
+		// Keep stdin open for clients attaching to the main process
+		s.Stdin = ptrBool(true)
+	}
+	for _, m := range opts.Secrets {
+		if m.Env != "" {
+			if s.EnvSecrets == nil {
+				s.EnvSecrets = make(map[string]string, len(opts.Secrets))
+			}
+			s.EnvSecrets[m.Env] = m.Secret
+		}
+		if m.Target != "" {
+			s.Secrets = append(s.Secrets, specgen.Secret{Source: m.Secret, Target: m.Target, Mode: 0o400})
+		}
+	}
+	for _, v := range opts.Volumes {
+		var options []string
+		if v.ReadOnly {
+			options = append(options, "ro")
+		}
+		if v.Source != "" {
+			// z shares the SELinux label of the source with containers
+			options = append(options, "bind", "z")
+			s.Mounts = append(s.Mounts, spec.Mount{Type: "bind", Source: v.Source, Destination: v.Target, Options: options})
+			continue
+		}
+		s.Volumes = append(s.Volumes, &specgen.NamedVolume{Name: v.Volume, Dest: v.Target, Options: options})
+	}
+	s.ResourceLimits = resourceLimits(opts.Resources)
+	if opts.Health != nil {
+		s.HealthConfig = &manifest.Schema2HealthConfig{
+			Test:        append([]string{"CMD"}, opts.Health.Command...),
+			Interval:    opts.Health.Interval,
+			Timeout:     opts.Health.Timeout,
+			StartPeriod: opts.Health.StartPeriod,
+			Retries:     opts.Health.Retries,
+		}
+	}
 
 	switch {
-	case podName != "":
-		s.Pod = podName
+	case opts.Pod != "":
+		s.Pod = opts.Pod
 		// When in a pod, ports are ignored here (handled by pod) typically,
 		// but if we want to expose ports from the container specifically (uncommon in shared net),
 		// we can. However, usually ports are on the Pod.
@@ -97,13 +179,13 @@ func (c *Client) Run(ctx context.Context, name, image string, ports map[uint16]u
 		// So if podName is set, we likely SHOULD NOT set PortMappings on the container spec
 		// unless we want double mapping or something.
 		// Let's omit port mappings if in a Pod, to be safe.
-		if len(ports) > 0 {
-			logger.DebugCtx(ctx, "Ignoring container ports because running in a Pod", "pod", podName)
+		if len(opts.Ports) > 0 {
+			log.DebugCtx(ctx, "Ignoring container ports because running in a Pod", "pod", opts.Pod)
 		}
-	case len(ports) > 0:
-		s.PortMappings = make([]nettypes.PortMapping, 0, len(ports))
-		for hostPort, containerPort := range ports {
-			logger.DebugCtx(ctx, "Adding port mapping",
+	case len(opts.Ports) > 0:
+		s.PortMappings = make([]nettypes.PortMapping, 0, len(opts.Ports))
+		for hostPort, containerPort := range opts.Ports {
+			log.DebugCtx(ctx, "Adding port mapping",
 				"host_port", hostPort,
 				"container_port", containerPort,
 			)
@@ -115,28 +197,28 @@ func (c *Client) Run(ctx context.Context, name, image string, ports map[uint16]u
 			})
 		}
 	default:
-		logger.DebugCtx(ctx, "No port mappings provided")
+		log.DebugCtx(ctx, "No port mappings provided")
 	}
 
-	if networkName != "" {
-		logger.DebugCtx(ctx, "Setting network", "network", networkName)
-		s.CNINetworks = []string{networkName}
+	if opts.Network != "" {
+		log.DebugCtx(ctx, "Setting network", "network", opts.Network, "aliases", opts.Aliases)
+		s.Networks = map[string]nettypes.PerNetworkOptions{opts.Network: {Aliases: opts.Aliases}}
 	}
 
 	// Create container
-	logger.DebugCtx(ctx, "Creating container", "name", name)
+	log.DebugCtx(ctx, "Creating container", "name", name)
 	createResponse, err := containers.CreateWithSpec(c.ctx, s, nil)
 	if err != nil {
 		return "", fmt.Errorf("creating container: %w", err)
 	}
 
 	// Start container
-	logger.DebugCtx(ctx, "Starting container", "id", createResponse.ID[:12])
+	log.DebugCtx(ctx, "Starting container", "id", createResponse.ID[:12])
 	if err := containers.Start(c.ctx, createResponse.ID, nil); err != nil {
 		return "", fmt.Errorf("starting container: %w", err)
 	}
 
-	logger.InfoCtx(ctx, "Container running",
+	log.InfoCtx(ctx, "Container running",
 		"name", name,
 		"id", createResponse.ID[:12],
 	)
@@ -144,34 +226,54 @@ func (c *Client) Run(ctx context.Context, name, image string, ports map[uint16]u
 	return createResponse.ID, nil
 }
 
+// cpuPeriod is the CFS period CPU limits are expressed in, as podman's --cpus uses
+const cpuPeriod = 100000
+
+// resourceLimits converts resources into the runtime's limits, nil when unlimited
+func resourceLimits(r Resources) *spec.LinuxResources {
+	if r.CPUs <= 0 && r.MemoryBytes <= 0 {
+		return nil
+	}
+	limits := &spec.LinuxResources{}
+	if r.CPUs > 0 {
+		period := uint64(cpuPeriod)
+		quota := int64(r.CPUs * cpuPeriod)
+		limits.CPU = &spec.LinuxCPU{Period: &period, Quota: &quota}
+	}
+	if r.MemoryBytes > 0 {
+		limits.Memory = &spec.LinuxMemory{Limit: &r.MemoryBytes}
+	}
+	return limits
+}
+
 // Stop stops a running container
 func (c *Client) Stop(ctx context.Context, name string, timeout *uint) error {
-	logger.DebugCtx(ctx, "Stopping container", "name", name)
+	log.DebugCtx(ctx, "Stopping container", "name", name)
 
 	if err := containers.Stop(c.ctx, name, &containers.StopOptions{Timeout: timeout}); err != nil {
 		return fmt.Errorf("stopping container: %w", err)
 	}
 
-	logger.InfoCtx(ctx, "Container stopped", "name", name)
+	log.InfoCtx(ctx, "Container stopped", "name", name)
 	return nil
 }
 
 // Remove removes a container
 func (c *Client) Remove(ctx context.Context, name string, force bool) error {
-	logger.DebugCtx(ctx, "Removing container", "name", name, "force", force)
+	log.DebugCtx(ctx, "Removing container", "name", name, "force", force)
 
 	_, err := containers.Remove(c.ctx, name, &containers.RemoveOptions{Force: &force})
 	if err != nil {
 		return fmt.Errorf("removing container: %w", err)
 	}
 
-	logger.InfoCtx(ctx, "Container removed", "name", name)
+	log.InfoCtx(ctx, "Container removed", "name", name)
 	return nil
 }
 
 // List returns containers based on filters
 func (c *Client) List(ctx context.Context, all bool) ([]ContainerInfo, error) {
-	logger.DebugCtx(ctx, "Listing containers", "all", all)
+	log.DebugCtx(ctx, "Listing containers", "all", all)
 
 	listContainers, err := containers.List(c.ctx, &containers.ListOptions{All: &all})
 	if err != nil {
@@ -210,76 +312,291 @@ func (c *Client) List(ctx context.Context, all bool) ([]ContainerInfo, error) {
 				result[idx].IPAddress = getIPAddress(inspectData.NetworkSettings.Networks)
 				result[idx].ExposedPorts = getExposedPorts(inspectData.Config.ExposedPorts)
 				result[idx].Networks = getNetworkNames(inspectData.NetworkSettings.Networks)
+				if inspectData.State != nil && inspectData.State.Health != nil {
+					result[idx].Health = inspectData.State.Health.Status
+				}
 			}
 		}
 	}
 
-	logger.DebugCtx(ctx, "Found containers", "count", len(result))
+	log.DebugCtx(ctx, "Found containers", "count", len(result))
 	return result, nil
 }
 
+// LogOptions controls which log lines are returned by StreamLogs
+type LogOptions struct {
+	Tail       string
+	Since      string
+	Follow     bool
+	Timestamps bool // set LogLine.Time
+}
+
+// LogLine is a single line of container output
+type LogLine struct {
+	Time   time.Time `json:"time,omitzero"` // when the line was written, with LogOptions.Timestamps
+	Stream string    `json:"stream"`        // "stdout" or "stderr"
+	Text   string    `json:"text"`
+}
+
+// String returns the line as podman logs prints it, after its timestamp
+// when it has one
+func (l *LogLine) String() string {
+	if l.Time.IsZero() {
+		return l.Text
+	}
+	return l.Time.Format(time.RFC3339Nano) + " " + l.Text
+}
+
 // Logs streams container logs
 func (c *Client) Logs(ctx context.Context, name string, follow bool, tail string) error {
-	logger.DebugCtx(ctx, "Getting container logs",
+	opts := LogOptions{
+		Follow:     follow,
+		Tail:       tail,
+		Timestamps: true,
+	}
+
+	return c.StreamLogs(ctx, name, opts, func(line LogLine) error {
+		fmt.Println(line.String())
+		return nil
+	})
+}
+
+// StreamLogs delivers container log lines to fn until the stream ends,
+// the context is canceled or fn returns an error.
+func (c *Client) StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) error {
+	log.DebugCtx(ctx, "Getting container logs",
 		"name", name,
-		"follow", follow,
-		"tail", tail,
+		"follow", opts.Follow,
+		"tail", opts.Tail,
+		"since", opts.Since,
 	)
 
 	stdoutCh := make(chan string)
 	stderrCh := make(chan string)
 
-	opts := &containers.LogOptions{
-		Follow:     &follow,
+	logOpts := &containers.LogOptions{
+		Follow:     &opts.Follow,
 		Stdout:     ptrBool(true),
 		Stderr:     ptrBool(true),
-		Timestamps: ptrBool(true),
+		Timestamps: &opts.Timestamps,
 	}
 
-	if tail != "" {
-		opts.Tail = &tail
+	if opts.Tail != "" {
+		logOpts.Tail = &opts.Tail
+	}
+	if opts.Since != "" {
+		logOpts.Since = &opts.Since
 	}
 
+	// Cancel the bindings stream if we stop reading early
+	streamCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
 	go func() {
-		if err := containers.Logs(c.ctx, name, opts, stdoutCh, stderrCh); err != nil {
-			logger.ErrorCtx(ctx, "error streaming logs", "error", err)
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-streamCtx.Done():
 		}
+	}()
 
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- containers.Logs(streamCtx, name, logOpts, stdoutCh, stderrCh)
 		close(stdoutCh)
 		close(stderrCh)
 	}()
 
-	// Print logs from both channels
-	for {
+	// Deliver lines from both channels
+	for stdoutCh != nil || stderrCh != nil {
+		var line LogLine
 		select {
-		case line, ok := <-stdoutCh:
+		case text, ok := <-stdoutCh:
 			if !ok {
 				stdoutCh = nil
-			} else {
-				fmt.Println(line)
+				continue
 			}
+			line = newLogLine("stdout", text, opts.Timestamps)
 
-		case line, ok := <-stderrCh:
+		case text, ok := <-stderrCh:
 			if !ok {
 				stderrCh = nil
-			} else {
-				fmt.Println(line)
+				continue
 			}
+			line = newLogLine("stderr", text, opts.Timestamps)
+
 		case <-ctx.Done():
+			cancel()
+			drainLogChannels(stdoutCh, stderrCh)
 			return ctx.Err()
 		}
 
-		if stdoutCh == nil && stderrCh == nil {
-			break
+		if err := fn(line); err != nil {
+			cancel()
+			drainLogChannels(stdoutCh, stderrCh)
+			return err
+		}
+	}
+
+	if err := <-errCh; err != nil && ctx.Err() == nil {
+		return fmt.Errorf("streaming logs: %w", err)
+	}
+	return nil
+}
+
+// newLogLine returns a line of a log stream. With timestamps the engine
+// prefixes each line with the time it was written, which is moved to Time.
+func newLogLine(stream, text string, timestamps bool) LogLine {
+	line := LogLine{Stream: stream, Text: text}
+	if !timestamps {
+		return line
+	}
+	if prefix, rest, ok := strings.Cut(text, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, prefix); err == nil {
+			line.Time, line.Text = t, rest
+		}
+	}
+	return line
+}
+
+// drainLogChannels discards pending lines so the bindings goroutine can exit
+func drainLogChannels(stdoutCh, stderrCh chan string) {
+	go func() {
+		for stdoutCh != nil || stderrCh != nil {
+			select {
+			case _, ok := <-stdoutCh:
+				if !ok {
+					stdoutCh = nil
+				}
+			case _, ok := <-stderrCh:
+				if !ok {
+					stderrCh = nil
+				}
+			}
+		}
+	}()
+}
+
+// Attach connects stdin, stdout and stderr to the main process of a running
+// container until the process exits, ctx is canceled or a write to stdout
+// or stderr fails. stdin may be nil to only receive output; input reaches
+// the process only if the container was run interactive.
+func (c *Client) Attach(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error {
+	log.DebugCtx(ctx, "Attaching to container", "name", name, "stdin", stdin != nil)
+
+	// The bindings keep reading the hijacked connection until the container
+	// writes again after ctx is canceled, so cancel their context too
+	attachCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-attachCtx.Done():
 		}
+	}()
+
+	opts := new(containers.AttachOptions).WithStream(true).WithLogs(false)
+	if err := containers.Attach(attachCtx, name, stdin, stdout, stderr, nil, opts); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("attaching to container: %w", err)
+	}
+	return nil
+}
+
+// EventOptions controls which engine events StreamEvents delivers
+type EventOptions struct {
+	Filters map[string][]string
+	Since   string
+	Until   string
+	Follow  bool
+}
+
+// StreamEvents delivers engine events to fn until the stream ends (when not
+// following), the context is canceled or fn returns an error.
+func (c *Client) StreamEvents(ctx context.Context, opts EventOptions, fn func(Event) error) error {
+	log.DebugCtx(ctx, "Streaming events",
+		"filters", opts.Filters,
+		"since", opts.Since,
+		"until", opts.Until,
+		"follow", opts.Follow,
+	)
+
+	eventOpts := &system.EventsOptions{
+		Filters: opts.Filters,
+		Stream:  &opts.Follow,
+	}
+	if opts.Since != "" {
+		eventOpts.Since = &opts.Since
+	}
+	if opts.Until != "" {
+		eventOpts.Until = &opts.Until
+	}
+
+	eventCh := make(chan entities.Event)
+	cancelCh := make(chan bool, 1)
+	if err := system.Events(c.ctx, eventCh, cancelCh, eventOpts); err != nil {
+		cancelCh <- true
+		return fmt.Errorf("streaming events: %w", err)
 	}
 
+	// Close the response body and let the bindings goroutine finish
+	stop := func() {
+		cancelCh <- true
+		go func() {
+			for range eventCh {
+				// Discard events until the stream closes
+			}
+		}()
+	}
+
+	for {
+		select {
+		case e, ok := <-eventCh:
+			if !ok {
+				return nil
+			}
+			if err := fn(convertEvent(&e)); err != nil {
+				stop()
+				return err
+			}
+		case <-ctx.Done():
+			stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// convertEvent maps a bindings event to the Simplify representation
+func convertEvent(e *entities.Event) Event {
+	ts := time.Unix(e.Time, 0)
+	if e.TimeNano != 0 {
+		ts = time.Unix(0, e.TimeNano)
+	}
+
+	return Event{
+		Time:       ts,
+		Type:       string(e.Type),
+		Action:     string(e.Action),
+		ID:         e.Actor.ID,
+		Name:       e.Actor.Attributes["name"],
+		Attributes: e.Actor.Attributes,
+	}
+}
+
+// Restart restarts a container
+func (c *Client) Restart(ctx context.Context, name string) error {
+	log.DebugCtx(ctx, "Restarting container", "name", name)
+
+	if err := containers.Restart(c.ctx, name, nil); err != nil {
+		return fmt.Errorf("restarting container: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Container restarted", "name", name)
 	return nil
 }
 
 // GetContainer returns information about a specific container
 func (c *Client) GetContainer(ctx context.Context, nameOrID string) (*ContainerInfo, error) {
-	logger.DebugCtx(ctx, "Getting container info", "id", nameOrID)
+	log.DebugCtx(ctx, "Getting container info", "id", nameOrID)
 
 	data, err := containers.Inspect(c.ctx, nameOrID, nil)
 	if err != nil {
@@ -307,22 +624,189 @@ func (c *Client) GetContainer(ctx context.Context, nameOrID string) (*ContainerI
 	}, nil
 }
 
-// InspectImage returns information about an image
-func (c *Client) InspectImage(ctx context.Context, name string) (*ImageInfo, error) {
-	logger.DebugCtx(ctx, "Inspecting image", "image", name)
+// ContainerStats is a point-in-time resource usage sample of a container.
+// CPU and network figures are counters since the container started.
+type ContainerStats struct {
+	Time        time.Time `json:"time"`
+	Name        string    `json:"name"`
+	CPUNano     uint64    `json:"cpu_nano"`     // CPU time used
+	MemoryBytes uint64    `json:"memory_bytes"` // current memory usage
+	MemoryLimit uint64    `json:"memory_limit"`
+	NetRxBytes  uint64    `json:"net_rx_bytes"` // received on all interfaces
+	NetTxBytes  uint64    `json:"net_tx_bytes"` // sent on all interfaces
+}
 
-	// Pull if not exists (optional, but good for inspection)
-	exists, err := images.Exists(c.ctx, name, nil)
+// Stats samples the resource usage of running containers by name
+func (c *Client) Stats(ctx context.Context, names []string) ([]ContainerStats, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	stream := false
+	reports, err := containers.Stats(c.ctx, names, &containers.StatsOptions{Stream: &stream})
 	if err != nil {
-		return nil, fmt.Errorf("checking image: %w", err)
+		return nil, fmt.Errorf("getting container stats: %w", err)
 	}
-	if !exists {
-		logger.InfoCtx(ctx, "Pulling image for inspection", "image", name)
-		_, err = images.Pull(c.ctx, name, nil)
-		if err != nil {
-			return nil, fmt.Errorf("pulling image: %w", err)
+
+	var result []ContainerStats
+	var statsErr error
+	for report := range reports {
+		if report.Error != nil {
+			statsErr = report.Error
+			continue
+		}
+		for i := range report.Stats {
+			st := &report.Stats[i]
+			sample := ContainerStats{
+				Time:        time.Unix(0, int64(st.SystemNano)).UTC(), //nolint:gosec // nanoseconds since the epoch fit in int64
+				Name:        st.Name,
+				CPUNano:     st.CPUNano,
+				MemoryBytes: st.MemUsage,
+				MemoryLimit: st.MemLimit,
+			}
+			for _, n := range st.Network {
+				sample.NetRxBytes += n.RxBytes
+				sample.NetTxBytes += n.TxBytes
+			}
+			result = append(result, sample)
+		}
+	}
+	if statsErr != nil && len(result) == 0 {
+		return nil, fmt.Errorf("getting container stats: %w", statsErr)
+	}
+
+	log.DebugCtx(ctx, "Sampled container stats", "count", len(result))
+	return result, nil
+}
+
+// EnsureImage pulls image if it is not present locally and reports whether
+// a pull happened. When progress is non-nil it is called as the pull
+// advances; otherwise the engine's progress output goes to stderr.
+func (c *Client) EnsureImage(ctx context.Context, image string, progress func(PullProgress)) (bool, error) {
+	log.DebugCtx(ctx, "Checking if image exists", "image", image)
+
+	exists, err := images.Exists(c.ctx, image, nil)
+	if err != nil {
+		return false, fmt.Errorf("checking image: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	log.InfoCtx(ctx, "Pulling image", "image", image)
+
+	var opts *images.PullOptions
+	if progress != nil {
+		pw := newPullProgressWriter(image, progress)
+		defer pw.Flush()
+		opts = new(images.PullOptions).WithProgressWriter(pw)
+	}
+
+	if _, err := images.Pull(c.ctx, image, opts); err != nil {
+		return false, fmt.Errorf("pulling image: %w", err)
+	}
+
+	log.DebugCtx(ctx, "Image pulled successfully", "image", image)
+	return true, nil
+}
+
+// pullProgressWriter turns the engine's textual pull output into
+// PullProgress updates. Lines look like "Copying blob sha256:abc..." with an
+// optional "done" or "skipped" suffix, followed by "Copying config" once all
+// layers are in place and "Writing manifest to image destination".
+type pullProgressWriter struct {
+	fn       func(PullProgress)
+	layers   map[string]bool // blob digest -> finished
+	buf      []byte
+	progress PullProgress
+}
+
+func newPullProgressWriter(image string, fn func(PullProgress)) *pullProgressWriter {
+	return &pullProgressWriter{
+		fn:       fn,
+		layers:   make(map[string]bool),
+		progress: PullProgress{Image: image},
+	}
+}
+
+// Write buffers partial lines and handles each complete line
+func (w *pullProgressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.handleLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush handles any trailing line without a newline
+func (w *pullProgressWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.handleLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *pullProgressWriter) handleLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(line, "Copying blob "):
+		fields := strings.Fields(strings.TrimPrefix(line, "Copying blob "))
+		if len(fields) == 0 {
+			return
+		}
+		finished := len(fields) > 1 && (fields[1] == "done" || strings.HasPrefix(fields[1], "skipped"))
+		w.layers[fields[0]] = w.layers[fields[0]] || finished
+	case strings.HasPrefix(line, "Copying config "), strings.HasPrefix(line, "Writing manifest"):
+		// The config is copied after every layer has been written
+		for digest := range w.layers {
+			w.layers[digest] = true
+		}
+	case isImageID(line):
+		// The final line is the ID of the pulled image
+		return
+	}
+
+	w.progress.Status = line
+	w.progress.Layers = len(w.layers)
+	w.progress.Done = 0
+	for _, finished := range w.layers {
+		if finished {
+			w.progress.Done++
+		}
+	}
+	w.fn(w.progress)
+}
+
+// isImageID reports whether s looks like a full 64 character image ID
+func isImageID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
 		}
 	}
+	return true
+}
+
+// InspectImage returns information about an image
+func (c *Client) InspectImage(ctx context.Context, name string) (*ImageInfo, error) {
+	log.DebugCtx(ctx, "Inspecting image", "image", name)
+
+	// Pull if not exists (optional, but good for inspection)
+	if _, err := c.EnsureImage(ctx, name, nil); err != nil {
+		return nil, err
+	}
 
 	data, err := images.GetImage(c.ctx, name, nil)
 	if err != nil {
@@ -342,6 +826,41 @@ func (c *Client) InspectImage(ctx context.Context, name string) (*ImageInfo, err
 	}, nil
 }
 
+// ListDanglingImages returns untagged images that are not referenced by any tag
+func (c *Client) ListDanglingImages(ctx context.Context) ([]DanglingImage, error) {
+	log.DebugCtx(ctx, "Listing dangling images")
+
+	summaries, err := images.List(c.ctx, &images.ListOptions{
+		Filters: map[string][]string{"dangling": {"true"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing images: %w", err)
+	}
+
+	result := make([]DanglingImage, 0, len(summaries))
+	for _, img := range summaries {
+		id := img.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		result = append(result, DanglingImage{ID: id, Size: img.Size})
+	}
+	return result, nil
+}
+
+// RemoveImage removes an image by name or ID
+func (c *Client) RemoveImage(ctx context.Context, nameOrID string) error {
+	log.DebugCtx(ctx, "Removing image", "image", nameOrID)
+
+	_, errs := images.Remove(c.ctx, []string{nameOrID}, nil)
+	if len(errs) > 0 {
+		return fmt.Errorf("removing image: %w", errs[0])
+	}
+
+	log.InfoCtx(ctx, "Image removed", "image", nameOrID)
+	return nil
+}
+
 // formatInspectPorts formats port mappings from inspect data
 func formatInspectPorts(ports map[string][]define.InspectHostPort) map[string]string {
 	result := make(map[string]string)
@@ -378,8 +897,15 @@ func getIPAddress(networks map[string]*define.InspectAdditionalNetwork) string {
 
 // getSocketPath returns the Podman socket path based on environment
 func getSocketPath() string {
+	return "unix://" + SocketPath()
+}
+
+// SocketPath returns the filesystem path of the Podman socket NewClient
+// connects to: PODMAN_SOCK, the Podman machine socket, or the user's
+// rootless socket
+func SocketPath() string {
 	if sock := os.Getenv("PODMAN_SOCK"); sock != "" {
-		return "unix://" + sock
+		return sock
 	}
 
 	homeDir, err := os.UserHomeDir()
@@ -388,10 +914,10 @@ func getSocketPath() string {
 	}
 	macSocket := fmt.Sprintf("%s/.local/share/containers/podman/machine/podman.sock", homeDir)
 	if _, err := os.Stat(macSocket); err == nil {
-		return "unix://" + macSocket
+		return macSocket
 	}
 
-	return fmt.Sprintf("unix://run/user/%d/podman/podman.sock", os.Getuid())
+	return fmt.Sprintf("/run/user/%d/podman/podman.sock", os.Getuid())
 }
 
 func envSliceToMap(env []string) map[string]string {
@@ -436,17 +962,28 @@ func ptrBool(b bool) *bool {
 	return &b
 }
 
-// CreatePod creates a new pod
-func (c *Client) CreatePod(ctx context.Context, name string, ports map[uint16]uint16) (string, error) {
-	logger.DebugCtx(ctx, "Creating pod", "name", name)
+// CreatePod creates a pod with the given options. Its infra image is
+// pulled first when one is set.
+func (c *Client) CreatePod(ctx context.Context, name string, opts PodOptions) (string, error) {
+	log.DebugCtx(ctx, "Creating pod", "name", name)
+
+	if opts.InfraImage != "" {
+		if _, err := c.EnsureImage(ctx, opts.InfraImage, nil); err != nil {
+			return "", err
+		}
+	}
 
 	s := specgen.NewPodSpecGenerator()
 	s.Name = name
+	s.Labels = opts.Labels
+	s.InfraImage = opts.InfraImage
+	s.Hostname = opts.Hostname
+	s.ResourceLimits = resourceLimits(opts.Resources)
 
 	// Configure ports
-	if len(ports) > 0 {
-		s.PortMappings = make([]nettypes.PortMapping, 0, len(ports))
-		for hostPort, containerPort := range ports {
+	if len(opts.Ports) > 0 {
+		s.PortMappings = make([]nettypes.PortMapping, 0, len(opts.Ports))
+		for hostPort, containerPort := range opts.Ports {
 			s.PortMappings = append(s.PortMappings, nettypes.PortMapping{
 				HostIP:        "127.0.0.1", // Default to localhost for safety
 				HostPort:      hostPort,
@@ -455,6 +992,18 @@ func (c *Client) CreatePod(ctx context.Context, name string, ports map[uint16]ui
 			})
 		}
 	}
+	if opts.Network != "" {
+		s.CNINetworks = []string{opts.Network}
+	}
+
+	// Volumes of the pod are mounted in each of its containers
+	for _, v := range opts.Volumes {
+		var options []string
+		if v.ReadOnly {
+			options = append(options, "ro")
+		}
+		s.Volumes = append(s.Volumes, &specgen.NamedVolume{Name: v.Volume, Dest: v.Target, Options: options})
+	}
 
 	// Create the pod
 	// CreatePodFromSpec expects entities.PodSpec which wraps PodSpecGen
@@ -467,13 +1016,13 @@ func (c *Client) CreatePod(ctx context.Context, name string, ports map[uint16]ui
 		return "", fmt.Errorf("creating pod: %w", err)
 	}
 
-	logger.InfoCtx(ctx, "Pod created", "name", name, "id", response.Id[:12])
+	log.InfoCtx(ctx, "Pod created", "name", name, "id", response.Id[:12])
 	return response.Id, nil
 }
 
 // RemovePod removes a pod
 func (c *Client) RemovePod(ctx context.Context, nameOrID string, force bool) error {
-	logger.DebugCtx(ctx, "Removing pod", "name", nameOrID, "force", force)
+	log.DebugCtx(ctx, "Removing pod", "name", nameOrID, "force", force)
 
 	_, err := pods.Remove(c.ctx, nameOrID, &pods.RemoveOptions{Force: &force})
 	if err != nil {
@@ -494,7 +1043,7 @@ func (c *Client) PodExists(ctx context.Context, nameOrID string) (bool, error) {
 
 // ListPods returns a list of all pods
 func (c *Client) ListPods(ctx context.Context) ([]PodInfo, error) {
-	logger.DebugCtx(ctx, "Listing pods")
+	log.DebugCtx(ctx, "Listing pods")
 
 	reports, err := pods.List(c.ctx, nil)
 	if err != nil {
@@ -508,6 +1057,7 @@ func (c *Client) ListPods(ctx context.Context) ([]PodInfo, error) {
 			Name:    p.Name,
 			Status:  p.Status,
 			Created: p.Created,
+			Labels:  p.Labels,
 		})
 	}
 
@@ -516,30 +1066,96 @@ func (c *Client) ListPods(ctx context.Context) ([]PodInfo, error) {
 
 // InspectPod returns information about a specific pod
 func (c *Client) InspectPod(ctx context.Context, nameOrID string) (*PodInfo, error) {
-	logger.DebugCtx(ctx, "Inspecting pod", "name", nameOrID)
+	log.DebugCtx(ctx, "Inspecting pod", "name", nameOrID)
 
 	data, err := pods.Inspect(c.ctx, nameOrID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("inspecting pod: %w", err)
 	}
 
-	return &PodInfo{
+	info := &PodInfo{
 		ID:      data.ID[:12],
 		Name:    data.Name,
 		Status:  data.State,
 		Created: data.Created,
-	}, nil
+		Labels:  data.Labels,
+	}
+	if data.InfraConfig != nil {
+		info.Ports = formatInspectPorts(data.InfraConfig.PortBindings)
+	}
+	return info, nil
+}
+
+// StartPod starts a pod and all of its containers
+func (c *Client) StartPod(ctx context.Context, nameOrID string) error {
+	log.DebugCtx(ctx, "Starting pod", "name", nameOrID)
+
+	if _, err := pods.Start(c.ctx, nameOrID, nil); err != nil {
+		return fmt.Errorf("starting pod: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Pod started", "name", nameOrID)
+	return nil
+}
+
+// StopPod stops a pod and all of its containers
+func (c *Client) StopPod(ctx context.Context, nameOrID string, timeout *int) error {
+	log.DebugCtx(ctx, "Stopping pod", "name", nameOrID)
+
+	if _, err := pods.Stop(c.ctx, nameOrID, &pods.StopOptions{Timeout: timeout}); err != nil {
+		return fmt.Errorf("stopping pod: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Pod stopped", "name", nameOrID)
+	return nil
+}
+
+// Resource kinds accepted by InspectRaw
+const (
+	KindContainer = "container"
+	KindPod       = "pod"
+	KindNetwork   = "network"
+)
+
+// InspectRaw returns the engine's complete inspect document for a container,
+// pod or network, unlike the abbreviated *Info types used for listings.
+func (c *Client) InspectRaw(ctx context.Context, kind, nameOrID string) (any, error) {
+	log.DebugCtx(ctx, "Inspecting resource", "kind", kind, "name", nameOrID)
+
+	switch kind {
+	case KindContainer:
+		data, err := containers.Inspect(c.ctx, nameOrID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting container: %w", err)
+		}
+		return data, nil
+	case KindPod:
+		data, err := pods.Inspect(c.ctx, nameOrID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting pod: %w", err)
+		}
+		return data, nil
+	case KindNetwork:
+		data, err := network.Inspect(c.ctx, nameOrID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("inspecting network: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown resource kind %q", kind)
+	}
 }
 
 // CreateNetwork creates a new bridge network
 func (c *Client) CreateNetwork(ctx context.Context, name string) (string, error) {
-	logger.DebugCtx(ctx, "Creating network", "name", name)
+	log.DebugCtx(ctx, "Creating network", "name", name)
 
 	// In this version of bindings, it seems we pass the Network struct directly?
 	// Based on error: want (context.Context, *"go.podman.io/common/libnetwork/types".Network)
 	net := &nettypes.Network{
 		Name:   name,
 		Driver: "bridge",
+		Labels: map[string]string{LabelManaged: "true"},
 	}
 
 	// Assuming network.Create returns (*types.NetworkCreateReport, error) or similar
@@ -556,13 +1172,13 @@ func (c *Client) CreateNetwork(ctx context.Context, name string) (string, error)
 		return "", fmt.Errorf("creating network: %w", err)
 	}
 
-	logger.InfoCtx(ctx, "Network created", "name", name, "id", newNet.ID)
+	log.InfoCtx(ctx, "Network created", "name", name, "id", newNet.ID)
 	return newNet.ID, nil
 }
 
 // RemoveNetwork removes a network
 func (c *Client) RemoveNetwork(ctx context.Context, nameOrID string) error {
-	logger.DebugCtx(ctx, "Removing network", "name", nameOrID)
+	log.DebugCtx(ctx, "Removing network", "name", nameOrID)
 
 	// Force removal? Maybe careful.
 	force := false
@@ -576,7 +1192,7 @@ func (c *Client) RemoveNetwork(ctx context.Context, nameOrID string) error {
 
 // ListNetworks lists all networks
 func (c *Client) ListNetworks(ctx context.Context) ([]NetworkInfo, error) {
-	logger.DebugCtx(ctx, "Listing networks")
+	log.DebugCtx(ctx, "Listing networks")
 
 	reports, err := network.List(c.ctx, nil)
 	if err != nil {
@@ -599,6 +1215,130 @@ func (c *Client) ListNetworks(ctx context.Context) ([]NetworkInfo, error) {
 			Driver:  n.Driver,
 			Subnet:  subnet,
 			Created: n.Created,
+			Labels:  n.Labels,
+		})
+	}
+	return result, nil
+}
+
+// InspectNetwork returns a network and the containers attached to it
+func (c *Client) InspectNetwork(ctx context.Context, nameOrID string) (*NetworkDetails, error) {
+	log.DebugCtx(ctx, "Inspecting network", "name", nameOrID)
+
+	report, err := network.Inspect(c.ctx, nameOrID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting network: %w", err)
+	}
+
+	subnet := ""
+	if len(report.Subnets) > 0 {
+		subnet = report.Subnets[0].Subnet.String()
+	}
+
+	details := &NetworkDetails{
+		NetworkInfo: NetworkInfo{
+			ID:      report.ID[:12],
+			Name:    report.Name,
+			Driver:  report.Driver,
+			Subnet:  subnet,
+			Created: report.Created,
+			Labels:  report.Labels,
+		},
+		Containers: make([]NetworkAttachment, 0, len(report.Containers)),
+	}
+
+	for id, info := range report.Containers {
+		attachment := NetworkAttachment{ID: id[:12], Name: info.Name}
+		for _, iface := range info.Interfaces {
+			if len(iface.Subnets) > 0 {
+				attachment.IPAddress = iface.Subnets[0].IPNet.IP.String()
+			}
+			attachment.MACAddress = iface.MacAddress.String()
+			break
+		}
+		details.Containers = append(details.Containers, attachment)
+	}
+	sort.Slice(details.Containers, func(i, j int) bool {
+		return details.Containers[i].Name < details.Containers[j].Name
+	})
+
+	return details, nil
+}
+
+// ConnectNetwork attaches a container to a network
+func (c *Client) ConnectNetwork(ctx context.Context, networkName, containerNameOrID string) error {
+	log.DebugCtx(ctx, "Connecting container to network", "network", networkName, "container", containerNameOrID)
+
+	if err := network.Connect(c.ctx, networkName, containerNameOrID, nil); err != nil {
+		return fmt.Errorf("connecting to network: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Container connected to network", "network", networkName, "container", containerNameOrID)
+	return nil
+}
+
+// DisconnectNetwork detaches a container from a network
+func (c *Client) DisconnectNetwork(ctx context.Context, networkName, containerNameOrID string, force bool) error {
+	log.DebugCtx(ctx, "Disconnecting container from network", "network", networkName, "container", containerNameOrID)
+
+	if err := network.Disconnect(c.ctx, networkName, containerNameOrID, &network.DisconnectOptions{Force: &force}); err != nil {
+		return fmt.Errorf("disconnecting from network: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Container disconnected from network", "network", networkName, "container", containerNameOrID)
+	return nil
+}
+
+// CreateSecret stores a secret in the engine, reading its value from data.
+// An existing secret with the same name is replaced when replace is set.
+func (c *Client) CreateSecret(ctx context.Context, name string, data io.Reader, replace bool) (string, error) {
+	log.DebugCtx(ctx, "Creating secret", "name", name, "replace", replace)
+
+	report, err := secrets.Create(c.ctx, data, &secrets.CreateOptions{
+		Name:    &name,
+		Labels:  map[string]string{LabelManaged: "true"},
+		Replace: &replace,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating secret: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Secret created", "name", name, "id", report.ID)
+	return report.ID, nil
+}
+
+// RemoveSecret removes a secret by name or ID
+func (c *Client) RemoveSecret(ctx context.Context, nameOrID string) error {
+	log.DebugCtx(ctx, "Removing secret", "name", nameOrID)
+
+	if err := secrets.Remove(c.ctx, nameOrID); err != nil {
+		return fmt.Errorf("removing secret: %w", err)
+	}
+	return nil
+}
+
+// ListSecrets lists secret metadata. Values are never returned.
+func (c *Client) ListSecrets(ctx context.Context) ([]SecretInfo, error) {
+	log.DebugCtx(ctx, "Listing secrets")
+
+	reports, err := secrets.List(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing secrets: %w", err)
+	}
+
+	result := make([]SecretInfo, 0, len(reports))
+	for _, r := range reports {
+		id := r.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		result = append(result, SecretInfo{
+			ID:      id,
+			Name:    r.Spec.Name,
+			Driver:  r.Spec.Driver.Name,
+			Created: r.CreatedAt,
+			Updated: r.UpdatedAt,
+			Labels:  r.Spec.Labels,
 		})
 	}
 	return result, nil
@@ -621,3 +1361,76 @@ func getNetworkNames(networks map[string]*define.InspectAdditionalNetwork) []str
 	}
 	return result
 }
+
+// CreateVolume creates a named volume. Labels are added to the managed label.
+func (c *Client) CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error) {
+	log.DebugCtx(ctx, "Creating volume", "name", name)
+
+	all := map[string]string{LabelManaged: "true"}
+	maps.Copy(all, labels)
+	report, err := volumes.Create(c.ctx, entities.VolumeCreateOptions{Name: name, Labels: all}, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating volume: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Volume created", "name", report.Name)
+	return report.Name, nil
+}
+
+// RemoveVolume removes a volume and its data. Force also removes the
+// containers using it.
+func (c *Client) RemoveVolume(ctx context.Context, name string, force bool) error {
+	log.DebugCtx(ctx, "Removing volume", "name", name, "force", force)
+
+	if err := volumes.Remove(c.ctx, name, &volumes.RemoveOptions{Force: &force}); err != nil {
+		return fmt.Errorf("removing volume: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Volume removed", "name", name)
+	return nil
+}
+
+// ListVolumes lists all volumes in the engine
+func (c *Client) ListVolumes(ctx context.Context) ([]VolumeInfo, error) {
+	log.DebugCtx(ctx, "Listing volumes")
+
+	reports, err := volumes.List(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing volumes: %w", err)
+	}
+
+	result := make([]VolumeInfo, 0, len(reports))
+	for _, r := range reports {
+		result = append(result, VolumeInfo{
+			Name:       r.Name,
+			Driver:     r.Driver,
+			Mountpoint: r.Mountpoint,
+			Created:    r.CreatedAt,
+			Labels:     r.Labels,
+		})
+	}
+	return result, nil
+}
+
+// ExportVolume writes the contents of a volume to w as a tar archive
+func (c *Client) ExportVolume(ctx context.Context, name string, w io.Writer) error {
+	log.DebugCtx(ctx, "Exporting volume", "name", name)
+
+	if err := volumes.Export(c.ctx, name, w); err != nil {
+		return fmt.Errorf("exporting volume: %w", err)
+	}
+	return nil
+}
+
+// ImportVolume extracts a tar archive into a volume. Files in the volume
+// that are not in the archive are kept.
+func (c *Client) ImportVolume(ctx context.Context, name string, r io.Reader) error {
+	log.DebugCtx(ctx, "Importing volume", "name", name)
+
+	if err := volumes.Import(c.ctx, name, r); err != nil {
+		return fmt.Errorf("importing volume: %w", err)
+	}
+
+	log.InfoCtx(ctx, "Volume imported", "name", name)
+	return nil
+}
@@ -2,6 +2,7 @@ package container
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	nettypes "go.podman.io/common/libnetwork/types"
@@ -169,3 +170,72 @@ func TestGetSocketPath_WithEnvVar(t *testing.T) {
 	socketPath := getSocketPath()
 	assert.Equal(t, "unix:///custom/path/podman.sock", socketPath)
 }
+
+// TestPullProgressWriter tests parsing of the engine's pull output into progress updates
+func TestPullProgressWriter(t *testing.T) {
+	var updates []PullProgress
+	w := newPullProgressWriter("nginx:latest", func(p PullProgress) {
+		updates = append(updates, p)
+	})
+
+	output := "Trying to pull docker.io/library/nginx:latest...\n" +
+		"Getting image source signatures\n" +
+		"Copying blob sha256:aaa\n" +
+		"Copying blob sha256:bbb skipped: already exists\n" +
+		"Copying blob sha256:ccc\n" +
+		"Copying blob sha256:aaa done\n" +
+		"Copying config sha256:ddd\n" +
+		"Writing manifest to image destination\n" +
+		"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef\n"
+
+	// Split writes mid-line to check buffering
+	_, err := w.Write([]byte(output[:40]))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(output[40:]))
+	assert.NoError(t, err)
+	w.Flush()
+
+	assert.Len(t, updates, 8)
+	assert.Equal(t, "nginx:latest", updates[0].Image)
+	assert.Equal(t, 0, updates[1].Layers)
+
+	// After "Copying blob sha256:ccc"
+	assert.Equal(t, 3, updates[4].Layers)
+	assert.Equal(t, 1, updates[4].Done)
+
+	// After "Copying blob sha256:aaa done"
+	assert.Equal(t, 2, updates[5].Done)
+
+	// Copying the config finishes every layer
+	assert.Equal(t, 3, updates[6].Done)
+	assert.Equal(t, 100, updates[7].Percent())
+	assert.Equal(t, "Writing manifest to image destination", updates[7].Status)
+}
+
+func TestPullProgressPercent(t *testing.T) {
+	assert.Equal(t, 0, PullProgress{}.Percent())
+	assert.Equal(t, 50, PullProgress{Layers: 4, Done: 2}.Percent())
+}
+
+func TestResourceLimits(t *testing.T) {
+	assert.Nil(t, resourceLimits(Resources{}))
+
+	limits := resourceLimits(Resources{CPUs: 1.5, MemoryBytes: 512 << 20})
+	assert.Equal(t, int64(150000), *limits.CPU.Quota)
+	assert.Equal(t, uint64(100000), *limits.CPU.Period)
+	assert.Equal(t, int64(512<<20), *limits.Memory.Limit)
+
+	limits = resourceLimits(Resources{MemoryBytes: 1 << 30})
+	assert.Nil(t, limits.CPU)
+}
+
+func TestNewLogLine(t *testing.T) {
+	line := newLogLine("stdout", "2026-10-16T09:30:00.123456789Z GET / 200", true)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 123456789, time.UTC), line.Time)
+	assert.Equal(t, "GET / 200", line.Text)
+	assert.Equal(t, "2026-10-16T09:30:00.123456789Z GET / 200", line.String())
+
+	// Lines without a timestamp, or read without asking for one, are kept whole
+	assert.Equal(t, LogLine{Stream: "stderr", Text: "panic: oops"}, newLogLine("stderr", "panic: oops", true))
+	assert.Equal(t, LogLine{Stream: "stdout", Text: "2026-10-16T09:30:00Z x"}, newLogLine("stdout", "2026-10-16T09:30:00Z x", false))
+}
@@ -0,0 +1,22 @@
+package container
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedDoesNotCacheFailures(t *testing.T) {
+	socket := "unix://" + filepath.Join(t.TempDir(), "podman.sock")
+
+	_, err := Shared(context.Background(), socket)
+	require.Error(t, err)
+
+	shared.mu.Lock()
+	_, cached := shared.clients[socket]
+	shared.mu.Unlock()
+	assert.False(t, cached, "a failed connection is retried on the next call")
+}
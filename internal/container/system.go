@@ -0,0 +1,127 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containers/podman/v5/libpod/define"
+	"github.com/containers/podman/v5/pkg/bindings/system"
+	"github.com/containers/podman/v5/pkg/domain/entities"
+)
+
+// DiskUsage is the space the engine uses, like podman system df
+type DiskUsage struct {
+	Images     DiskUsageSummary `json:"images"`
+	Containers DiskUsageSummary `json:"containers"`
+	Volumes    DiskUsageSummary `json:"volumes"`
+}
+
+// DiskUsageSummary is the space used by one kind of resource. Reclaimable
+// is what removing the unused ones would free.
+type DiskUsageSummary struct {
+	Size        int64 `json:"size"`
+	Reclaimable int64 `json:"reclaimable"`
+	Total       int   `json:"total"`
+	Active      int   `json:"active"` // images used by a container, running containers, volumes mounted by a container
+}
+
+// DiskUsage reports the space used by images, containers and volumes
+func (c *Client) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	report, err := system.DiskUsage(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting disk usage: %w", err)
+	}
+
+	usage := summarizeDiskUsage(report)
+	log.DebugCtx(ctx, "Read disk usage", "images", usage.Images.Size, "containers", usage.Containers.Size, "volumes", usage.Volumes.Size)
+	return usage, nil
+}
+
+// summarizeDiskUsage totals an engine disk usage report the way podman
+// system df does
+func summarizeDiskUsage(report *entities.SystemDfReport) *DiskUsage {
+	usage := &DiskUsage{}
+
+	// Layers shared between images are counted once in ImagesSize, so an
+	// unused image frees only its unique layers
+	usage.Images.Size = report.ImagesSize
+	for _, img := range report.Images {
+		usage.Images.Total++
+		if img.Containers > 0 {
+			usage.Images.Active++
+		} else {
+			usage.Images.Reclaimable += img.UniqueSize
+		}
+	}
+
+	for _, ctr := range report.Containers {
+		usage.Containers.Total++
+		usage.Containers.Size += ctr.RWSize
+		if ctr.Status == "running" {
+			usage.Containers.Active++
+		} else {
+			usage.Containers.Reclaimable += ctr.RWSize
+		}
+	}
+
+	for _, v := range report.Volumes {
+		usage.Volumes.Total++
+		usage.Volumes.Size += v.Size
+		usage.Volumes.Reclaimable += v.ReclaimableSize
+		if v.Links > 0 {
+			usage.Volumes.Active++
+		}
+	}
+	return usage
+}
+
+// SystemInfo describes the host running the engine, like podman info
+type SystemInfo struct {
+	Version       string `json:"version"`        // Podman version
+	StorageDriver string `json:"storage_driver"` // e.g. overlay
+	OS            string `json:"os"`
+	Distribution  string `json:"distribution,omitempty"` // e.g. fedora 40
+	Kernel        string `json:"kernel"`
+	Arch          string `json:"arch"`
+	Hostname      string `json:"hostname"`
+	MemTotal      int64  `json:"mem_total"`
+	MemFree       int64  `json:"mem_free"`
+	StorageSize   uint64 `json:"storage_size"` // size of the file system holding images and containers
+	StorageUsed   uint64 `json:"storage_used"`
+	CPUs          int    `json:"cpus"`
+}
+
+// SystemInfo reports the engine version and the capacity of its host
+func (c *Client) SystemInfo(ctx context.Context) (*SystemInfo, error) {
+	report, err := system.Info(c.ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting system info: %w", err)
+	}
+
+	info := summarizeSystemInfo(report)
+	log.DebugCtx(ctx, "Read system info", "version", info.Version, "cpus", info.CPUs, "mem_total", info.MemTotal)
+	return info, nil
+}
+
+// summarizeSystemInfo picks the host details simplify reports out of an
+// engine info report
+func summarizeSystemInfo(report *define.Info) *SystemInfo {
+	info := &SystemInfo{Version: report.Version.Version}
+	if h := report.Host; h != nil {
+		info.OS = h.OS
+		info.Kernel = h.Kernel
+		info.Arch = h.Arch
+		info.Hostname = h.Hostname
+		info.MemTotal = h.MemTotal
+		info.MemFree = h.MemFree
+		info.CPUs = h.CPUs
+		info.Distribution = strings.TrimSpace(h.Distribution.Distribution + " " + h.Distribution.Version)
+	}
+	if s := report.Store; s != nil {
+		info.StorageDriver = s.GraphDriverName
+		info.StorageSize = s.GraphRootAllocated
+		info.StorageUsed = s.GraphRootUsed
+	}
+	return info
+}
@@ -0,0 +1,68 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/containers/podman/v5/libpod/define"
+	"github.com/containers/podman/v5/pkg/domain/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeDiskUsage(t *testing.T) {
+	report := &entities.SystemDfReport{
+		ImagesSize: 300,
+		Images: []*entities.SystemDfImageReport{
+			{Size: 200, UniqueSize: 150, Containers: 2},
+			{Size: 100, UniqueSize: 50},
+		},
+		Containers: []*entities.SystemDfContainerReport{
+			{RWSize: 10, Status: "running"},
+			{RWSize: 5, Status: "exited"},
+		},
+		Volumes: []*entities.SystemDfVolumeReport{
+			{Size: 1000, Links: 1},
+			{Size: 400, ReclaimableSize: 400},
+		},
+	}
+
+	assert.Equal(t, &DiskUsage{
+		Images:     DiskUsageSummary{Size: 300, Reclaimable: 50, Total: 2, Active: 1},
+		Containers: DiskUsageSummary{Size: 15, Reclaimable: 5, Total: 2, Active: 1},
+		Volumes:    DiskUsageSummary{Size: 1400, Reclaimable: 400, Total: 2, Active: 1},
+	}, summarizeDiskUsage(report))
+}
+
+func TestSummarizeSystemInfo(t *testing.T) {
+	report := &define.Info{
+		Host: &define.HostInfo{
+			Arch:         "amd64",
+			CPUs:         4,
+			Distribution: define.DistributionInfo{Distribution: "fedora", Version: "40"},
+			Hostname:     "node1",
+			Kernel:       "6.8.5",
+			MemFree:      1 << 30,
+			MemTotal:     8 << 30,
+			OS:           "linux",
+		},
+		Store:   &define.StoreInfo{GraphDriverName: "overlay", GraphRootAllocated: 100, GraphRootUsed: 40},
+		Version: define.Version{Version: "5.2.0"},
+	}
+
+	assert.Equal(t, &SystemInfo{
+		Version:       "5.2.0",
+		StorageDriver: "overlay",
+		OS:            "linux",
+		Distribution:  "fedora 40",
+		Kernel:        "6.8.5",
+		Arch:          "amd64",
+		Hostname:      "node1",
+		MemTotal:      8 << 30,
+		MemFree:       1 << 30,
+		StorageSize:   100,
+		StorageUsed:   40,
+		CPUs:          4,
+	}, summarizeSystemInfo(report))
+
+	// A report without host or store details leaves them empty
+	assert.Equal(t, &SystemInfo{Version: "5.2.0"}, summarizeSystemInfo(&define.Info{Version: define.Version{Version: "5.2.0"}}))
+}
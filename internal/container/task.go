@@ -0,0 +1,112 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/containers/podman/v5/libpod/define"
+	"github.com/containers/podman/v5/pkg/bindings/containers"
+	"github.com/containers/podman/v5/pkg/specgen"
+	spec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// LabelTask marks one-off task containers, such as build steps. They are
+// not managed, so the reconciler leaves them alone.
+const LabelTask = "simplify.task"
+
+// TaskSpec describes a container that runs a command to completion
+type TaskSpec struct {
+	Labels     map[string]string
+	Name       string
+	Image      string
+	Entrypoint []string // overrides the image entrypoint when set
+	Command    []string
+	Env        []string
+	Volumes    []VolumeMount
+	Binds      []BindMount
+}
+
+// BindMount mounts a path of the engine host into a container
+type BindMount struct {
+	Source   string // absolute path on the engine host
+	Target   string // absolute mount path in the container
+	ReadOnly bool
+}
+
+// RunTask runs a task container to completion, copying its output to out,
+// and removes it. It returns the exit code of the command.
+func (c *Client) RunTask(ctx context.Context, task TaskSpec, out io.Writer) (int, error) {
+	if _, err := c.EnsureImage(ctx, task.Image, nil); err != nil {
+		return -1, err
+	}
+
+	s := specgen.NewSpecGenerator(task.Image, false)
+	s.Name = task.Name
+	s.Entrypoint = task.Entrypoint
+	s.Command = task.Command
+	s.Env = envSliceToMap(task.Env)
+	s.Labels = map[string]string{LabelTask: "true"}
+	for k, v := range task.Labels {
+		s.Labels[k] = v
+	}
+	for _, v := range task.Volumes {
+		var options []string
+		if v.ReadOnly {
+			options = append(options, "ro")
+		}
+		s.Volumes = append(s.Volumes, &specgen.NamedVolume{Name: v.Volume, Dest: v.Target, Options: options})
+	}
+	for _, b := range task.Binds {
+		options := []string{"rbind"}
+		if b.ReadOnly {
+			options = append(options, "ro")
+		}
+		s.Mounts = append(s.Mounts, spec.Mount{Type: "bind", Source: b.Source, Destination: b.Target, Options: options})
+	}
+
+	log.DebugCtx(ctx, "Creating task container", "name", task.Name, "image", task.Image)
+	created, err := containers.CreateWithSpec(c.ctx, s, nil)
+	if err != nil {
+		return -1, fmt.Errorf("creating task container: %w", err)
+	}
+	defer func() {
+		if _, err := containers.Remove(c.ctx, created.ID, &containers.RemoveOptions{Force: ptrBool(true)}); err != nil {
+			log.WarnCtx(ctx, "Failed to remove task container", "name", task.Name, "error", err)
+		}
+	}()
+
+	if err := containers.Start(c.ctx, created.ID, nil); err != nil {
+		return -1, fmt.Errorf("starting task container: %w", err)
+	}
+
+	logsDone := make(chan error, 1)
+	go func() {
+		logsDone <- c.StreamLogs(ctx, created.ID, LogOptions{Follow: true}, func(line LogLine) error {
+			_, err := fmt.Fprintln(out, line.Text)
+			return err
+		})
+	}()
+
+	// Stop waiting when ctx is canceled; the deferred remove kills the task
+	waitCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	code, err := containers.Wait(waitCtx, created.ID, &containers.WaitOptions{
+		Conditions: []string{define.ContainerStateExited.String()},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return -1, fmt.Errorf("waiting for task container: %w", err)
+	}
+	if err := <-logsDone; err != nil {
+		log.DebugCtx(ctx, "Task log stream ended", "name", task.Name, "error", err)
+	}
+
+	log.InfoCtx(ctx, "Task finished", "name", task.Name, "exit_code", code)
+	return int(code), nil
+}
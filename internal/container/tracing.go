@@ -0,0 +1,180 @@
+package container
+
+import (
+	"context"
+	"io"
+
+	"github.com/AkMo3/simplify/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedManager wraps a ContainerManager, recording a span for every call
+// into the container engine
+type tracedManager struct {
+	next ContainerManager
+}
+
+// WithTracing returns a ContainerManager that traces each call to m
+func WithTracing(m ContainerManager) ContainerManager {
+	return &tracedManager{next: m}
+}
+
+func (t *tracedManager) Run(ctx context.Context, name string, opts RunOptions) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "podman.Run",
+		attribute.String("container.name", name),
+		attribute.String("container.image", opts.Image),
+		attribute.String("container.pod", opts.Pod),
+	)
+	defer func() { tracing.End(span, err) }()
+	return t.next.Run(ctx, name, opts)
+}
+
+func (t *tracedManager) Stop(ctx context.Context, name string, timeout *uint) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.Stop", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Stop(ctx, name, timeout)
+}
+
+func (t *tracedManager) Remove(ctx context.Context, name string, force bool) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.Remove", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Remove(ctx, name, force)
+}
+
+func (t *tracedManager) List(ctx context.Context, all bool) (list []ContainerInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.List")
+	defer func() { tracing.End(span, err) }()
+	return t.next.List(ctx, all)
+}
+
+func (t *tracedManager) Restart(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.Restart", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Restart(ctx, name)
+}
+
+func (t *tracedManager) Logs(ctx context.Context, name string, follow bool, tail string) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.Logs", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Logs(ctx, name, follow, tail)
+}
+
+func (t *tracedManager) StreamLogs(ctx context.Context, name string, opts LogOptions, fn func(LogLine) error) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.StreamLogs", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.StreamLogs(ctx, name, opts, fn)
+}
+
+func (t *tracedManager) Attach(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.Attach", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Attach(ctx, name, stdin, stdout, stderr)
+}
+
+func (t *tracedManager) Exec(ctx context.Context, name string, opts ExecOptions) (code int, err error) {
+	ctx, span := tracing.Start(ctx, "podman.Exec", attribute.String("container.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.Exec(ctx, name, opts)
+}
+
+func (t *tracedManager) GetContainer(ctx context.Context, nameOrID string) (info *ContainerInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.GetContainer", attribute.String("container.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetContainer(ctx, nameOrID)
+}
+
+func (t *tracedManager) InspectImage(ctx context.Context, image string) (info *ImageInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.InspectImage", attribute.String("container.image", image))
+	defer func() { tracing.End(span, err) }()
+	return t.next.InspectImage(ctx, image)
+}
+
+func (t *tracedManager) CreatePod(ctx context.Context, name string, opts PodOptions) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "podman.CreatePod", attribute.String("pod.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreatePod(ctx, name, opts)
+}
+
+func (t *tracedManager) RemovePod(ctx context.Context, nameOrID string, force bool) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.RemovePod", attribute.String("pod.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RemovePod(ctx, nameOrID, force)
+}
+
+func (t *tracedManager) PodExists(ctx context.Context, nameOrID string) (exists bool, err error) {
+	ctx, span := tracing.Start(ctx, "podman.PodExists", attribute.String("pod.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.PodExists(ctx, nameOrID)
+}
+
+func (t *tracedManager) ListPods(ctx context.Context) (pods []PodInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.ListPods")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListPods(ctx)
+}
+
+func (t *tracedManager) InspectPod(ctx context.Context, nameOrID string) (info *PodInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.InspectPod", attribute.String("pod.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.InspectPod(ctx, nameOrID)
+}
+
+func (t *tracedManager) CreateNetwork(ctx context.Context, name string) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "podman.CreateNetwork", attribute.String("network.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateNetwork(ctx, name)
+}
+
+func (t *tracedManager) RemoveNetwork(ctx context.Context, nameOrID string) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.RemoveNetwork", attribute.String("network.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RemoveNetwork(ctx, nameOrID)
+}
+
+func (t *tracedManager) ListNetworks(ctx context.Context) (networks []NetworkInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.ListNetworks")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListNetworks(ctx)
+}
+
+func (t *tracedManager) CreateSecret(ctx context.Context, name string, data io.Reader, replace bool) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "podman.CreateSecret", attribute.String("secret.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateSecret(ctx, name, data, replace)
+}
+
+func (t *tracedManager) RemoveSecret(ctx context.Context, nameOrID string) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.RemoveSecret", attribute.String("secret.name", nameOrID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RemoveSecret(ctx, nameOrID)
+}
+
+func (t *tracedManager) CreateVolume(ctx context.Context, name string, labels map[string]string) (id string, err error) {
+	ctx, span := tracing.Start(ctx, "podman.CreateVolume", attribute.String("volume.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateVolume(ctx, name, labels)
+}
+
+func (t *tracedManager) RemoveVolume(ctx context.Context, name string, force bool) (err error) {
+	ctx, span := tracing.Start(ctx, "podman.RemoveVolume", attribute.String("volume.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RemoveVolume(ctx, name, force)
+}
+
+func (t *tracedManager) ListVolumes(ctx context.Context) (list []VolumeInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.ListVolumes")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListVolumes(ctx)
+}
+
+func (t *tracedManager) DiskUsage(ctx context.Context) (usage *DiskUsage, err error) {
+	ctx, span := tracing.Start(ctx, "podman.DiskUsage")
+	defer func() { tracing.End(span, err) }()
+	return t.next.DiskUsage(ctx)
+}
+
+func (t *tracedManager) SystemInfo(ctx context.Context) (info *SystemInfo, err error) {
+	ctx, span := tracing.Start(ctx, "podman.SystemInfo")
+	defer func() { tracing.End(span, err) }()
+	return t.next.SystemInfo(ctx)
+}
@@ -1,7 +1,17 @@
 // Package core provides all the core domain types for Simplify
 package core
 
-import "time"
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
 
 // Team represents a group of users (e.g. "Engineering", "Platform")
 type Team struct {
@@ -11,51 +21,603 @@ type Team struct {
 	Slug      string    `json:"slug"`
 }
 
+// Team roles, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleMember = "member"
+	RoleViewer = "viewer"
+)
+
+// User is a person or automation account that belongs to teams
+type User struct {
+	CreatedAt time.Time        `json:"created_at"`
+	ID        string           `json:"id"`
+	Username  string           `json:"username"`
+	Email     string           `json:"email,omitempty"`
+	Teams     []TeamMembership `json:"teams,omitempty"`
+}
+
+// TeamMembership links a user to a team with a role
+type TeamMembership struct {
+	TeamID string `json:"team_id"`
+	Role   string `json:"role"` // RoleOwner, RoleMember or RoleViewer
+}
+
+// Role returns the user's role in a team, or "" when not a member
+func (u *User) Role(teamID string) string {
+	for _, m := range u.Teams {
+		if m.TeamID == teamID {
+			return m.Role
+		}
+	}
+	return ""
+}
+
+// APIKey is a bearer token that authenticates as a user. Only a hash of the
+// key is stored; the key itself is returned once, when it is created.
+type APIKey struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`        // leading characters of the key, to tell keys apart
+	Key        string     `json:"key,omitempty"` // set only in the create response
+}
+
+// Expired reports whether the key has expired at the given time
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
 // Project represents a specific codebase or service group (e.g., "simplify-api")
 type Project struct {
-	CreatedAt time.Time `json:"created_at"`
-	ID        string    `json:"id"`
-	TeamID    string    `json:"team_id"`
-	Name      string    `json:"name"`
-	RepoURL   string    `json:"repo_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	TeamID        string    `json:"team_id"`
+	Name          string    `json:"name"`
+	RepoURL       string    `json:"repo_url"`
+	WebhookSecret string    `json:"webhook_secret,omitempty"` // name of the secret that signs push webhooks from RepoURL
 }
 
 // Environment represents a deployment target (e.g., "prod", "staging")
 type Environment struct {
-	CreatedAt time.Time         `json:"created_at"`
-	Config    map[string]string `json:"config"`
-	ID        string            `json:"id"`
-	ProjectID string            `json:"project_id"`
-	Name      string            `json:"name"`
+	CreatedAt time.Time          `json:"created_at"`
+	Limits    *EnvironmentLimits `json:"limits,omitempty"` // resource budget of each application in the environment
+	Config    map[string]string  `json:"config"`
+	ID        string             `json:"id"`
+	ProjectID string             `json:"project_id"`
+	Name      string             `json:"name"`
+}
+
+// EnvironmentLimits are the resource defaults and maximums inherited by the
+// applications of an environment. Zero values leave a resource unbounded.
+type EnvironmentLimits struct {
+	Default  Resources `json:"default"`             // used by applications that set no limit
+	Max      Resources `json:"max"`                 // largest limit an application may set
+	MaxPorts int       `json:"max_ports,omitempty"` // host ports an application may publish
+}
+
+// Resources limits the CPU and memory of each container of an application.
+// Zero values are unlimited.
+type Resources struct {
+	MemoryBytes int64   `json:"memory_bytes,omitempty"`
+	CPUs        float64 `json:"cpus,omitempty"` // cores, 0.5 is half a core
+}
+
+// CheckLimits returns an error naming the first limit of the environment
+// the application exceeds
+func (e *Environment) CheckLimits(app *Application) error {
+	if e.Limits == nil {
+		return nil
+	}
+	l := e.Limits
+	r := app.EffectiveResources(e)
+	if l.Max.CPUs > 0 && r.CPUs > l.Max.CPUs {
+		return fmt.Errorf("cpus %g exceeds the maximum of %g in environment %s", r.CPUs, l.Max.CPUs, e.Name)
+	}
+	if l.Max.MemoryBytes > 0 && r.MemoryBytes > l.Max.MemoryBytes {
+		return fmt.Errorf("memory of %d bytes exceeds the maximum of %d in environment %s", r.MemoryBytes, l.Max.MemoryBytes, e.Name)
+	}
+	if l.MaxPorts > 0 && len(app.Ports) > l.MaxPorts {
+		return fmt.Errorf("%d published ports exceed the maximum of %d in environment %s", len(app.Ports), l.MaxPorts, e.Name)
+	}
+	return nil
 }
 
 // Application represents a running service configuration
 type Application struct {
 	CreatedAt         time.Time         `json:"created_at"`
 	UpdatedAt         time.Time         `json:"updated_at"`
+	AutoDeploy        *AutoDeploy       `json:"auto_deploy,omitempty"` // redeploys on pushes to the project repository
+	Resources         *Resources        `json:"resources,omitempty"`   // per container; unset values come from the environment
+	HealthCheck       *HealthCheck      `json:"health_check,omitempty"`
 	EnvVars           map[string]string `json:"env_vars"`
 	Ports             map[string]string `json:"ports"`
+	NodeSelector      map[string]string `json:"node_selector,omitempty"` // labels a node must have to run the app; empty runs it on the server
+	Labels            map[string]string `json:"labels,omitempty"`        // metadata, such as the Compose project that owns the app
 	Name              string            `json:"name"`
 	ID                string            `json:"id"`
 	EnvironmentID     string            `json:"environment_id"`
 	Image             string            `json:"image"`
 	Status            string            `json:"status"`
-	HealthStatus      string            `json:"health_status"`
+	HealthStatus      string            `json:"health_status"` // HealthStarting, HealthHealthy or HealthUnhealthy; empty without a health check
 	PodID             string            `json:"pod_id,omitempty"`
 	NetworkID         string            `json:"network_id,omitempty"`
+	StackID           string            `json:"stack_id,omitempty"`
+	NodeID            string            `json:"node_id,omitempty"` // node the scheduler placed the app on
 	IPAddress         string            `json:"ip_address,omitempty"`
 	ConnectedNetworks []string          `json:"connected_networks,omitempty"`
+	NetworkAliases    []string          `json:"network_aliases,omitempty"` // DNS names of the containers on NetworkID, besides the app name
 	ExposedPorts      []string          `json:"exposed_ports,omitempty"`
+	SensitiveEnv      []string          `json:"sensitive_env,omitempty"` // env vars redacted in API responses, besides those matched by name
+	Secrets           []SecretRef       `json:"secrets,omitempty"`
+	Volumes           []VolumeMount     `json:"volumes,omitempty"`
+	ConfigFiles       []ConfigFile      `json:"config_files,omitempty"`
+	Alerts            []AlertRule       `json:"alerts,omitempty"`
+	Alerting          []string          `json:"alerting,omitempty"` // metrics of the alerts that fire, set by the metrics collector
+	Instances         []Instance        `json:"instances,omitempty"`
 	Replicas          int               `json:"replicas"`
+	Stopped           bool              `json:"stopped,omitempty"`     // desired state: no containers run
+	Interactive       bool              `json:"interactive,omitempty"` // keeps stdin open for clients attaching to the main process
+}
+
+// Health check types
+const (
+	HealthCheckHTTP = "http" // a GET of Path on Port answers 2xx or 3xx, probed by the server
+	HealthCheckTCP  = "tcp"  // Port accepts connections, probed by the server
+	HealthCheckExec = "exec" // Command exits 0 in the container, run by podman
+)
+
+// Health states of an application's containers
+const (
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Health check defaults, used for zero values
+const (
+	DefaultHealthInterval = 10 * time.Second
+	DefaultHealthTimeout  = 5 * time.Second
+	DefaultHealthRetries  = 3
+)
+
+// HealthCheck tells whether an application's containers are ready to serve
+type HealthCheck struct {
+	Type        string   `json:"type"`                   // HealthCheckHTTP, HealthCheckTCP or HealthCheckExec
+	Path        string   `json:"path,omitempty"`         // http: request path, / when empty
+	Command     []string `json:"command,omitempty"`      // exec: command and its arguments
+	Port        int      `json:"port,omitempty"`         // http and tcp: container port
+	Interval    int      `json:"interval,omitempty"`     // seconds between checks
+	Timeout     int      `json:"timeout,omitempty"`      // seconds before a check fails
+	Retries     int      `json:"retries,omitempty"`      // consecutive failures before unhealthy
+	StartPeriod int      `json:"start_period,omitempty"` // seconds after start during which failures are not counted
+}
+
+// IntervalDuration returns the time between checks
+func (h *HealthCheck) IntervalDuration() time.Duration {
+	return secondsOr(h.Interval, DefaultHealthInterval)
+}
+
+// TimeoutDuration returns how long a check may take
+func (h *HealthCheck) TimeoutDuration() time.Duration {
+	return secondsOr(h.Timeout, DefaultHealthTimeout)
+}
+
+// StartPeriodDuration returns how long failures are ignored after a start
+func (h *HealthCheck) StartPeriodDuration() time.Duration {
+	return secondsOr(h.StartPeriod, 0)
+}
+
+// RetryCount returns the consecutive failures that make a container unhealthy
+func (h *HealthCheck) RetryCount() int {
+	return cmp.Or(h.Retries, DefaultHealthRetries)
+}
+
+func secondsOr(seconds int, def time.Duration) time.Duration {
+	if seconds <= 0 {
+		return def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Metrics alert rules watch
+const (
+	AlertCPU    = "cpu"    // CPUPercent summed over the replicas
+	AlertMemory = "memory" // MemoryBytes summed over the replicas
+)
+
+// AlertRule fires when a metric of an application stays above a threshold.
+// It is evaluated against each sample of the metrics collector.
+type AlertRule struct {
+	Metric    string  `json:"metric"`             // AlertCPU or AlertMemory
+	Threshold float64 `json:"threshold"`          // percent for cpu, where 100 is one core; bytes for memory
+	Duration  int     `json:"duration,omitempty"` // seconds the metric must stay above the threshold, 0 to fire on the first sample
+}
+
+// Exceeded reports whether a sample is above the rule's threshold
+func (r *AlertRule) Exceeded(s *MetricSample) bool {
+	switch r.Metric {
+	case AlertCPU:
+		return s.CPUPercent > r.Threshold
+	case AlertMemory:
+		return float64(s.MemoryBytes) > r.Threshold
+	}
+	return false
+}
+
+// Value returns the sampled value of the rule's metric
+func (r *AlertRule) Value(s *MetricSample) float64 {
+	if r.Metric == AlertMemory {
+		return float64(s.MemoryBytes)
+	}
+	return s.CPUPercent
+}
+
+// DurationValue returns how long the metric must stay above the threshold
+func (r *AlertRule) DurationValue() time.Duration {
+	return secondsOr(r.Duration, 0)
+}
+
+// AutoDeploy redeploys an application when its project repository's push
+// webhook reports a change
+type AutoDeploy struct {
+	Branch string `json:"branch,omitempty"` // pushes to this branch start a build of the application
+	Tags   bool   `json:"tags,omitempty"`   // pushed tags become the tag of the application's image
+}
+
+// SecretRef injects a secret into an application's containers, as an
+// environment variable, a file, or both
+type SecretRef struct {
+	Name string `json:"name"`           // name of the secret
+	Env  string `json:"env,omitempty"`  // environment variable set to the value
+	File string `json:"file,omitempty"` // absolute path of a file holding the value
+}
+
+// VolumeMount attaches a volume, or a directory or file of the host, to an
+// application's containers
+type VolumeMount struct {
+	Name     string `json:"name"`                // name of the volume, empty for a bind mount
+	Source   string `json:"source,omitempty"`    // absolute host path bind-mounted instead of a volume
+	Path     string `json:"path"`                // absolute mount path in the container
+	ReadOnly bool   `json:"read_only,omitempty"` // mount without write access
+}
+
+// MaxConfigFileSize is the largest content of a config file, which is
+// stored in the database with the application
+const MaxConfigFileSize = 64 << 10
+
+// ConfigFile is a small file whose content is stored with the application
+// and mounted read-only into its containers, like a Kubernetes ConfigMap
+type ConfigFile struct {
+	Path    string `json:"path"`           // absolute path in the container
+	Content string `json:"content"`        // file content
+	Mode    uint32 `json:"mode,omitempty"` // permission bits, 0644 when zero
+}
+
+// FileMode returns the permission bits the file is written with
+func (f *ConfigFile) FileMode() uint32 {
+	return cmp.Or(f.Mode, 0o644)
+}
+
+// ConfigChecksum identifies the content of an application's config files.
+// It is empty without config files, and changes with any path, mode or
+// content so containers are recreated when a file changes.
+func ConfigChecksum(files []ConfigFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for i := range files {
+		f := &files[i]
+		fmt.Fprintf(h, "%s\x00%o\x00%d\x00%s", f.Path, f.FileMode(), len(f.Content), f.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Instance is the runtime state of one replica of an application
+type Instance struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Revision string `json:"revision,omitempty"`
+	Replica  int    `json:"replica"`
+}
+
+// Revision identifies the current spec of the application. It changes on every
+// update and is stamped on containers so that outdated ones can be detected.
+func (a *Application) Revision() string {
+	return a.UpdatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// Scalable reports whether the application can run more than one replica.
+// Host ports can only be bound once, and apps in a pod share its network
+// namespace, so either pins the application to a single container.
+func (a *Application) Scalable() bool {
+	return len(a.Ports) == 0 && a.PodID == ""
+}
+
+// DesiredReplicas returns the number of containers the application should run
+func (a *Application) DesiredReplicas() int {
+	if a.Replicas < 1 || !a.Scalable() {
+		return 1
+	}
+	return a.Replicas
+}
+
+// EffectiveResources returns the limits the application's containers run
+// with: its own, or else the environment's default, or else the
+// environment's maximum. env may be nil.
+func (a *Application) EffectiveResources(env *Environment) Resources {
+	var r Resources
+	if a.Resources != nil {
+		r = *a.Resources
+	}
+	if env == nil || env.Limits == nil {
+		return r
+	}
+	l := env.Limits
+	if r.CPUs == 0 {
+		r.CPUs = cmp.Or(l.Default.CPUs, l.Max.CPUs)
+	}
+	if r.MemoryBytes == 0 {
+		r.MemoryBytes = cmp.Or(l.Default.MemoryBytes, l.Max.MemoryBytes)
+	}
+	return r
+}
+
+// RedactedValue replaces the values of sensitive environment variables in
+// API responses. Sent back in an update, it keeps the stored value.
+const RedactedValue = "********"
+
+// sensitiveEnvPattern matches the names of environment variables that
+// usually hold credentials
+var sensitiveEnvPattern = regexp.MustCompile(`(?i)(PASSWORD|PASSWD|SECRET|TOKEN|API_?KEY|PRIVATE_?KEY|ACCESS_?KEY|CREDENTIAL)`)
+
+// IsSensitiveEnv reports whether the value of an environment variable is
+// hidden from API responses: it is listed in sensitive, or its name looks
+// like it holds a password, token or key
+func IsSensitiveEnv(name string, sensitive []string) bool {
+	return slices.Contains(sensitive, name) || sensitiveEnvPattern.MatchString(name)
+}
+
+// RedactEnv returns a copy of env with the values of sensitive variables
+// replaced by RedactedValue
+func RedactEnv(env map[string]string, sensitive []string) map[string]string {
+	if env == nil {
+		return nil
+	}
+	redacted := maps.Clone(env)
+	for k := range redacted {
+		if IsSensitiveEnv(k, sensitive) {
+			redacted[k] = RedactedValue
+		}
+	}
+	return redacted
+}
+
+// Redact hides the values of the application's sensitive environment
+// variables. Containers still get them: only copies sent out are redacted.
+func (a *Application) Redact() {
+	a.EnvVars = RedactEnv(a.EnvVars, a.SensitiveEnv)
 }
 
-// Pod represents a shared network namespace for multiple applications
+// KeepRedactedEnv restores the stored value of each environment variable
+// sent back as RedactedValue, so a spec read from the API can be updated
+// without knowing its sensitive values
+func (a *Application) KeepRedactedEnv(existing *Application) {
+	for k, v := range a.EnvVars {
+		if old, ok := existing.EnvVars[k]; ok && v == RedactedValue {
+			a.EnvVars[k] = old
+		}
+	}
+}
+
+// ScheduledOn reports whether the application runs on the given node. The
+// server itself is node "": it runs the applications without a node
+// selector. Applications whose selector matches no node run nowhere until
+// one registers.
+func (a *Application) ScheduledOn(nodeID string) bool {
+	if nodeID == "" {
+		return a.NodeID == "" && len(a.NodeSelector) == 0
+	}
+	return a.NodeID == nodeID
+}
+
+// Stack groups applications that are deployed, stopped and deleted as one
+// unit, like a compose project. Members share the stack's network and are
+// started in the order listed.
+type Stack struct {
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	NetworkID      string    `json:"network_id"`
+	ApplicationIDs []string  `json:"application_ids"` // in start order
+	Stopped        bool      `json:"stopped,omitempty"`
+}
+
+// NetworkName returns the name of the network shared by the stack's
+// applications
+func (s *Stack) NetworkName() string {
+	return s.Name + "_default"
+}
+
+// Link types between applications
+const (
+	LinkDependsOn  = "depends_on"  // From needs To: the reconciler deploys To first
+	LinkConnectsTo = "connects_to" // From talks to To; informational, for the service map
+)
+
+// Link is a typed edge from one application to another
+type Link struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	From      string    `json:"from"` // application ID
+	To        string    `json:"to"`   // application ID
+	Type      string    `json:"type"` // LinkDependsOn or LinkConnectsTo
+}
+
+// Graph is the service map: applications and the links between them
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Links []Link      `json:"links"`
+}
+
+// GraphNode is an application on the service map
+type GraphNode struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Image         string `json:"image"`
+	StackID       string `json:"stack_id,omitempty"`
+	EnvironmentID string `json:"environment_id,omitempty"`
+	HealthStatus  string `json:"health_status,omitempty"`
+	Stopped       bool   `json:"stopped,omitempty"`
+}
+
+// OrderByDependencies sorts application IDs so that each comes after the
+// applications it depends on, keeping the given order otherwise. Links to
+// IDs not in ids are ignored. It fails on a dependency cycle.
+func OrderByDependencies(ids []string, links []Link) ([]string, error) {
+	known := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	deps := make(map[string][]string)
+	for i := range links {
+		l := &links[i]
+		if l.Type == LinkDependsOn && known[l.From] && known[l.To] {
+			deps[l.From] = append(deps[l.From], l.To)
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(ids))
+	order := make([]string, 0, len(ids))
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, id), " -> "))
+		}
+		state[id] = visiting
+		for _, dep := range deps[id] {
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		order = append(order, id)
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Node states
+const (
+	NodeReady    = "ready"
+	NodeNotReady = "not_ready"
+)
+
+// NodeHeartbeatTimeout is how long a node stays ready after its last heartbeat
+const NodeHeartbeatTimeout = time.Minute
+
+// Node is a host running the Simplify agent. Applications whose node
+// selector matches its labels are scheduled to it, and the agent runs them.
+type Node struct {
+	CreatedAt     time.Time         `json:"created_at"`
+	LastHeartbeat time.Time         `json:"last_heartbeat"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	AppStatus     map[string]string `json:"app_status,omitempty"` // application ID to status, as last reported by the agent
+	AppHealth     map[string]string `json:"app_health,omitempty"` // application ID to health, for applications with a health check
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Status        string            `json:"status"`          // NodeReady or NodeNotReady, computed when read
+	Token         string            `json:"token,omitempty"` // set only in the register response
+	Capacity      NodeCapacity      `json:"capacity"`
+}
+
+// NodeCapacity is the hardware a node reports
+type NodeCapacity struct {
+	MemoryBytes int64 `json:"memory_bytes"`
+	CPUs        int   `json:"cpus"`
+}
+
+// Ready reports whether the node sent a heartbeat recently enough at the
+// given time
+func (n *Node) Ready(now time.Time) bool {
+	return now.Sub(n.LastHeartbeat) < NodeHeartbeatTimeout
+}
+
+// Matches reports whether the node has every label of selector
+func (n *Node) Matches(selector map[string]string) bool {
+	for k, v := range selector {
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// NodeState is everything an agent needs to run the applications scheduled
+// to its node: the applications and the resources they reference. Secret
+// values are included.
+type NodeState struct {
+	Applications []Application `json:"applications"`
+	Environments []Environment `json:"environments"`
+	Pods         []Pod         `json:"pods"`
+	Networks     []Network     `json:"networks"`
+	Volumes      []Volume      `json:"volumes"`
+	Secrets      []Secret      `json:"secrets"`
+	Stacks       []Stack       `json:"stacks"`
+	Links        []Link        `json:"links"`
+}
+
+// Pod represents a shared network namespace for multiple applications. The
+// namespace is held by the pod's infra container.
 type Pod struct {
-	CreatedAt time.Time         `json:"created_at"`
-	ID        string            `json:"id"`
-	Name      string            `json:"name"`
-	Ports     map[string]string `json:"ports"` // Host:Container
-	Status    string            `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Resources  *Resources        `json:"resources,omitempty"` // limits shared by all containers of the pod
+	Ports      map[string]string `json:"ports"`               // Host:Container
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	InfraImage string            `json:"infra_image,omitempty"` // image of the infra container, empty for the engine default
+	Hostname   string            `json:"hostname,omitempty"`    // empty for the pod name
+	NetworkID  string            `json:"network_id,omitempty"`  // network the pod joins, empty for the default
+	Volumes    []VolumeMount     `json:"volumes,omitempty"`     // mounted in every container of the pod
+}
+
+// SpecChecksum identifies the settings of the pod that are fixed when the
+// engine creates it, other than its ports, so that the pod can be recreated
+// when they change. It is empty when none are set.
+func (p *Pod) SpecChecksum() string {
+	var spec strings.Builder
+	if p.InfraImage != "" || p.Hostname != "" || p.NetworkID != "" {
+		fmt.Fprintf(&spec, "%s\x00%s\x00%s\x00", p.InfraImage, p.Hostname, p.NetworkID)
+	}
+	for _, v := range p.Volumes {
+		fmt.Fprintf(&spec, "volume\x00%s\x00%s\x00%t\x00", v.Name, v.Path, v.ReadOnly)
+	}
+	if r := p.Resources; r != nil && (r.CPUs > 0 || r.MemoryBytes > 0) {
+		fmt.Fprintf(&spec, "resources\x00%g\x00%d\x00", r.CPUs, r.MemoryBytes)
+	}
+	if spec.Len() == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(spec.String()))
+	return hex.EncodeToString(sum[:])[:16]
 }
 
 // Network represents a bridge network for container communication
@@ -66,3 +628,238 @@ type Network struct {
 	Subnet    string    `json:"subnet"`
 	Driver    string    `json:"driver"`
 }
+
+// Secret is a named credential. The value is encrypted at rest and write-only:
+// it is accepted on create and update but never returned by the API.
+type Secret struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Value     string    `json:"value,omitempty"`
+}
+
+// Backup schedules of a volume's backup policy
+const (
+	BackupNone   = ""
+	BackupDaily  = "daily"
+	BackupWeekly = "weekly"
+)
+
+// Volume is persistent storage that outlives the containers using it
+type Volume struct {
+	CreatedAt time.Time    `json:"created_at"`
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Size      string       `json:"size,omitempty"` // size hint such as "10GiB", recorded but not enforced by the engine
+	Backup    BackupPolicy `json:"backup"`
+}
+
+// BackupPolicy describes how often a volume is backed up and how many
+// backups are kept
+type BackupPolicy struct {
+	Schedule string `json:"schedule,omitempty"` // BackupNone, BackupDaily or BackupWeekly
+	Retain   int    `json:"retain,omitempty"`   // backups to keep, 0 keeps all
+}
+
+// TLS modes of a domain
+const (
+	TLSAuto     = "auto"     // certificate from a public ACME CA
+	TLSInternal = "internal" // certificate from a local CA, for private hosts
+	TLSOff      = "off"      // plain HTTP
+)
+
+// Verification states of a domain
+const (
+	DomainPending  = "pending"
+	DomainVerified = "verified"
+	DomainFailed   = "failed"
+)
+
+// Domain routes a hostname to an application. Ownership is proven with a
+// DNS TXT record holding VerificationToken, see VerificationRecord.
+type Domain struct {
+	CreatedAt         time.Time  `json:"created_at"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	ID                string     `json:"id"`
+	Hostname          string     `json:"hostname"`
+	ApplicationID     string     `json:"application_id"`
+	TLS               string     `json:"tls"`    // TLSAuto, TLSInternal or TLSOff
+	Status            string     `json:"status"` // DomainPending, DomainVerified or DomainFailed
+	StatusMessage     string     `json:"status_message,omitempty"`
+	VerificationToken string     `json:"verification_token"`
+	Port              int        `json:"port,omitempty"` // container port to route to, 0 for the app's first port
+	Ready             bool       `json:"ready"`          // verified and routed to a healthy application, computed when read
+}
+
+// VerificationRecord returns the DNS name that must hold the domain's
+// verification token in a TXT record
+func (d *Domain) VerificationRecord() string {
+	return "_simplify-challenge." + d.Hostname
+}
+
+// Build strategies
+const (
+	BuildAuto          = ""              // Containerfile if the repository has one, buildpacks otherwise
+	BuildContainerfile = "containerfile" // podman build with the repository's Containerfile or Dockerfile
+	BuildBuildpacks    = "buildpacks"    // Cloud Native Buildpacks
+)
+
+// Build states
+const (
+	BuildQueued    = "queued"
+	BuildRunning   = "running"
+	BuildSucceeded = "succeeded"
+	BuildFailed    = "failed"
+)
+
+// Build turns a commit of an application's project repository into an
+// image. A successful build updates the application to the new image.
+type Build struct {
+	CreatedAt     time.Time  `json:"created_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	ID            string     `json:"id"`
+	ApplicationID string     `json:"application_id"`
+	RepoURL       string     `json:"repo_url"`
+	Ref           string     `json:"ref,omitempty"`           // branch or tag, empty for the default branch
+	Commit        string     `json:"commit,omitempty"`        // commit that was built
+	Strategy      string     `json:"strategy,omitempty"`      // BuildContainerfile or BuildBuildpacks, resolved when BuildAuto
+	Containerfile string     `json:"containerfile,omitempty"` // path within Context, empty to look for Containerfile then Dockerfile
+	Context       string     `json:"context,omitempty"`       // directory of the repository to build, empty for the root
+	Image         string     `json:"image,omitempty"`         // tag of the built image
+	Status        string     `json:"status"`                  // BuildQueued, BuildRunning, BuildSucceeded or BuildFailed
+	Error         string     `json:"error,omitempty"`
+	Log           string     `json:"log,omitempty"` // end of the build output
+}
+
+// Finished reports whether the build has stopped, successfully or not
+func (b *Build) Finished() bool {
+	return b.Status == BuildSucceeded || b.Status == BuildFailed
+}
+
+// Deployment records one applied revision of an application's spec, so it
+// can be listed and rolled back to
+type Deployment struct {
+	CreatedAt      time.Time         `json:"created_at"`
+	EnvVars        map[string]string `json:"env_vars,omitempty"`
+	Ports          map[string]string `json:"ports,omitempty"`
+	ApplicationID  string            `json:"application_id"`
+	Revision       string            `json:"revision"`
+	Image          string            `json:"image"`
+	Actor          string            `json:"actor"` // who applied it, as reported by the client
+	PodID          string            `json:"pod_id,omitempty"`
+	NetworkID      string            `json:"network_id,omitempty"`
+	RollbackOf     string            `json:"rollback_of,omitempty"` // revision restored by a rollback
+	SensitiveEnv   []string          `json:"sensitive_env,omitempty"`
+	NetworkAliases []string          `json:"network_aliases,omitempty"`
+	Secrets        []SecretRef       `json:"secrets,omitempty"`
+	Volumes        []VolumeMount     `json:"volumes,omitempty"`
+	ConfigFiles    []ConfigFile      `json:"config_files,omitempty"`
+	Interactive    bool              `json:"interactive,omitempty"`
+}
+
+// NewDeployment snapshots the spec of app at its current revision
+func NewDeployment(app *Application, actor string) *Deployment {
+	return &Deployment{
+		CreatedAt:      app.UpdatedAt,
+		EnvVars:        app.EnvVars,
+		Ports:          app.Ports,
+		ApplicationID:  app.ID,
+		Revision:       app.Revision(),
+		Image:          app.Image,
+		Actor:          actor,
+		PodID:          app.PodID,
+		NetworkID:      app.NetworkID,
+		NetworkAliases: app.NetworkAliases,
+		SensitiveEnv:   app.SensitiveEnv,
+		Secrets:        app.Secrets,
+		Volumes:        app.Volumes,
+		ConfigFiles:    app.ConfigFiles,
+		Interactive:    app.Interactive,
+	}
+}
+
+// Redact hides the values of the recorded sensitive environment variables
+func (d *Deployment) Redact() {
+	d.EnvVars = RedactEnv(d.EnvVars, d.SensitiveEnv)
+}
+
+// Restore applies the spec recorded in d to app. Runtime fields, the name
+// and the replica count are left unchanged.
+func (d *Deployment) Restore(app *Application) {
+	app.Image = d.Image
+	app.EnvVars = d.EnvVars
+	app.SensitiveEnv = d.SensitiveEnv
+	app.Ports = d.Ports
+	app.PodID = d.PodID
+	app.NetworkID = d.NetworkID
+	app.NetworkAliases = d.NetworkAliases
+	app.Secrets = d.Secrets
+	app.Volumes = d.Volumes
+	app.ConfigFiles = d.ConfigFiles
+	app.Interactive = d.Interactive
+}
+
+// MetricSample is the resource usage of an application's running replicas
+// at one point in time, summed over the replicas. Rates are averaged over
+// the interval since the previous sample.
+type MetricSample struct {
+	Time        time.Time `json:"time"`
+	CPUPercent  float64   `json:"cpu_percent"`  // 100 is one core fully used
+	NetRxRate   float64   `json:"net_rx_rate"`  // bytes received per second
+	NetTxRate   float64   `json:"net_tx_rate"`  // bytes sent per second
+	MemoryBytes uint64    `json:"memory_bytes"` // memory in use
+	Replicas    int       `json:"replicas"`     // running replicas sampled
+}
+
+// ActorSystem is the actor of activity not caused by a request, such as a
+// deploy finishing in the reconciler
+const ActorSystem = "system"
+
+// Activity actions on resources. Events raised by the reconciler and by
+// builds use their notification type, such as "deploy.failed".
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionDeleted  = "deleted"
+	ActionDeployed = "deployed" // applications and stacks started or scaled
+	ActionStopped  = "stopped"
+	ActionExecuted = "executed" // commands run in an application's container
+)
+
+// Activity is an entry of the activity feed: who did what to which
+// resource. The team, project and application it belongs to are recorded
+// with it, so the feed can be read per scope after the resources are gone.
+type Activity struct {
+	CreatedAt     time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Actor         string    `json:"actor"`         // user@host from the client, or ActorSystem
+	Action        string    `json:"action"`        // an Action constant or an event type
+	ResourceType  string    `json:"resource_type"` // "application", "team", "project", ...
+	ResourceID    string    `json:"resource_id"`
+	ResourceName  string    `json:"resource_name,omitempty"`
+	TeamID        string    `json:"team_id,omitempty"`
+	ProjectID     string    `json:"project_id,omitempty"`
+	ApplicationID string    `json:"application_id,omitempty"`
+	Message       string    `json:"message,omitempty"`
+}
+
+// ActivityFilter selects activity by scope. Empty fields match everything.
+type ActivityFilter struct {
+	Before        time.Time // only older entries, for paging; zero for the newest
+	TeamID        string
+	ProjectID     string
+	ApplicationID string
+	Limit         int // 0 for DefaultActivityLimit
+}
+
+// DefaultActivityLimit is the number of entries returned when no limit is given
+const DefaultActivityLimit = 50
+
+// Matches reports whether an entry is within the filter's scope
+func (f *ActivityFilter) Matches(a *Activity) bool {
+	return (f.TeamID == "" || a.TeamID == f.TeamID) &&
+		(f.ProjectID == "" || a.ProjectID == f.ProjectID) &&
+		(f.ApplicationID == "" || a.ApplicationID == f.ApplicationID) &&
+		(f.Before.IsZero() || a.CreatedAt.Before(f.Before))
+}
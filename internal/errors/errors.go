@@ -2,8 +2,14 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
 )
 
 // Error codes for consistent error identification
@@ -13,8 +19,39 @@ const (
 	CodeInvalidInput     = "INVALID_INPUT"
 	CodeInternal         = "INTERNAL_ERROR"
 	CodePermissionDenied = "PERMISSION_DENIED"
+	CodeTimeout          = "TIMEOUT"
+	CodeUnavailable      = "UNAVAILABLE"
+	CodeConflictState    = "CONFLICT_STATE"
 )
 
+// Exit codes used by the CLI for each error class, so scripts can tell
+// failures apart without parsing messages. Errors without a class, such as
+// failures to reach the engine, exit with ExitGeneral.
+const (
+	ExitGeneral          = 1
+	ExitInvalidInput     = 2
+	ExitNotFound         = 3
+	ExitAlreadyExists    = 4
+	ExitPermissionDenied = 5
+	ExitTimeout          = 6
+	ExitUnavailable      = 7
+	ExitConflictState    = 8
+)
+
+// DocsURL is the page documenting each error code and how to resolve it
+const DocsURL = "https://github.com/AkMo3/simplify/blob/main/docs/errors.md"
+
+// DocURL returns the documentation link for an error code, or "" for codes
+// without a documented section
+func DocURL(code string) string {
+	switch code {
+	case CodeNotFound, CodeAlreadyExists, CodeInvalidInput, CodeInternal,
+		CodePermissionDenied, CodeTimeout, CodeUnavailable, CodeConflictState:
+		return DocsURL + "#" + strings.ToLower(code)
+	}
+	return ""
+}
+
 // BaseError contains common fields for all custom errors
 type BaseError struct {
 	Cause    error
@@ -133,6 +170,7 @@ func (e *InvalidInputError) Error() string {
 // InternalError indicates an internal server error
 type InternalError struct {
 	BaseError
+	Stack string // where the error was created, only set when stack capture is enabled
 }
 
 // NewInternalError creates a new InternalError
@@ -142,6 +180,7 @@ func NewInternalError(message string) *InternalError {
 			Code:    CodeInternal,
 			Message: message,
 		},
+		Stack: captureStack(),
 	}
 }
 
@@ -153,9 +192,55 @@ func NewInternalErrorWithCause(message string, cause error) *InternalError {
 			Message: message,
 			Cause:   cause,
 		},
+		Stack: captureStack(),
 	}
 }
 
+// captureStacks enables stack capture in InternalError constructors
+var captureStacks atomic.Bool
+
+// maxStackDepth bounds the number of frames captured for an InternalError
+const maxStackDepth = 32
+
+// SetStackCapture turns stack capture for internal errors on or off. It is
+// meant for development, where the extra cost helps debugging.
+func SetStackCapture(enabled bool) {
+	captureStacks.Store(enabled)
+}
+
+// captureStack formats the stack of the constructor's caller, or returns ""
+// when stack capture is disabled
+func captureStack() string {
+	if !captureStacks.Load() {
+		return ""
+	}
+
+	// Skip runtime.Callers, captureStack and the constructor
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// StackTrace returns the stack captured by the first InternalError in err's
+// chain, or "" when there is none
+func StackTrace(err error) string {
+	var internal *InternalError
+	if errors.As(err, &internal) {
+		return internal.Stack
+	}
+	return ""
+}
+
 // PermissionError indicates a permission-related error
 type PermissionError struct {
 	BaseError
@@ -211,6 +296,99 @@ func (e *PermissionError) Error() string {
 	return fmt.Sprintf("%s: %s (path=%s)", e.Code, e.Message, e.Path)
 }
 
+// TimeoutError indicates an operation did not finish in time
+type TimeoutError struct {
+	BaseError
+}
+
+// NewTimeoutError creates a new TimeoutError
+func NewTimeoutError(message string) *TimeoutError {
+	return &TimeoutError{
+		BaseError: BaseError{
+			Code:    CodeTimeout,
+			Message: message,
+		},
+	}
+}
+
+// NewTimeoutErrorWithCause creates a TimeoutError with an underlying cause
+func NewTimeoutErrorWithCause(message string, cause error) *TimeoutError {
+	err := NewTimeoutError(message)
+	err.Cause = cause
+	return err
+}
+
+// UnavailableError indicates a dependency, such as the Podman socket, could not be reached
+type UnavailableError struct {
+	BaseError
+}
+
+// NewUnavailableError creates a new UnavailableError for a dependency
+func NewUnavailableError(dependency, message string) *UnavailableError {
+	return &UnavailableError{
+		BaseError: BaseError{
+			Code:     CodeUnavailable,
+			Message:  message,
+			Resource: dependency,
+		},
+	}
+}
+
+// NewUnavailableErrorWithCause creates an UnavailableError with an underlying cause
+func NewUnavailableErrorWithCause(dependency, message string, cause error) *UnavailableError {
+	err := NewUnavailableError(dependency, message)
+	err.Cause = cause
+	return err
+}
+
+// ConflictStateError indicates a resource exists but is in a state that
+// does not allow the operation, such as deleting a pod that is still in use
+type ConflictStateError struct {
+	BaseError
+}
+
+// NewConflictStateError creates a new ConflictStateError
+func NewConflictStateError(resource, id, message string) *ConflictStateError {
+	return &ConflictStateError{
+		BaseError: BaseError{
+			Code:     CodeConflictState,
+			Message:  message,
+			Resource: resource,
+			ID:       id,
+		},
+	}
+}
+
+// NewConflictStateErrorWithCause creates a ConflictStateError with an underlying cause
+func NewConflictStateErrorWithCause(resource, id, message string, cause error) *ConflictStateError {
+	err := NewConflictStateError(resource, id, message)
+	err.Cause = cause
+	return err
+}
+
+// Classify returns err as a TimeoutError when it was caused by a deadline
+// or network timeout, or as an UnavailableError when a socket could not be
+// dialed. Errors that already have a class, and other errors, are returned
+// unchanged. It lets engine failures be reported by kind rather than as
+// generic internal errors.
+func Classify(err error) error {
+	if err == nil || GetBaseError(err) != nil {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewTimeoutErrorWithCause(err.Error(), err)
+	}
+
+	var opErr *net.OpError
+	if errors.Is(err, syscall.ECONNREFUSED) || (errors.As(err, &opErr) && opErr.Op == "dial") {
+		return NewUnavailableErrorWithCause("", err.Error(), err)
+	}
+
+	return err
+}
+
 // Type checking helper functions
 
 // IsNotFound checks if an error is a NotFoundError
@@ -243,6 +421,24 @@ func IsPermissionError(err error) bool {
 	return errors.As(err, &permErr)
 }
 
+// IsTimeout checks if an error is a TimeoutError
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// IsUnavailable checks if an error is an UnavailableError
+func IsUnavailable(err error) bool {
+	var unavailableErr *UnavailableError
+	return errors.As(err, &unavailableErr)
+}
+
+// IsConflictState checks if an error is a ConflictStateError
+func IsConflictState(err error) bool {
+	var conflictErr *ConflictStateError
+	return errors.As(err, &conflictErr)
+}
+
 // GetErrorCode extracts the error code from a custom error, or returns INTERNAL_ERROR
 func GetErrorCode(err error) string {
 	var base *BaseError
@@ -272,6 +468,21 @@ func GetErrorCode(err error) string {
 		return permission.Code
 	}
 
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) {
+		return timeout.Code
+	}
+
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return unavailable.Code
+	}
+
+	var conflict *ConflictStateError
+	if errors.As(err, &conflict) {
+		return conflict.Code
+	}
+
 	if errors.As(err, &base) {
 		return base.Code
 	}
@@ -279,6 +490,32 @@ func GetErrorCode(err error) string {
 	return CodeInternal
 }
 
+// ExitCode returns the CLI exit code for an error, or 0 for nil
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	switch GetErrorCode(err) {
+	case CodeInvalidInput:
+		return ExitInvalidInput
+	case CodeNotFound:
+		return ExitNotFound
+	case CodeAlreadyExists:
+		return ExitAlreadyExists
+	case CodePermissionDenied:
+		return ExitPermissionDenied
+	case CodeTimeout:
+		return ExitTimeout
+	case CodeUnavailable:
+		return ExitUnavailable
+	case CodeConflictState:
+		return ExitConflictState
+	default:
+		return ExitGeneral
+	}
+}
+
 // GetBaseError extracts the BaseError from any custom error type
 func GetBaseError(err error) *BaseError {
 	var notFound *NotFoundError
@@ -306,5 +543,20 @@ func GetBaseError(err error) *BaseError {
 		return &permission.BaseError
 	}
 
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) {
+		return &timeout.BaseError
+	}
+
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return &unavailable.BaseError
+	}
+
+	var conflict *ConflictStateError
+	if errors.As(err, &conflict) {
+		return &conflict.BaseError
+	}
+
 	return nil
 }
@@ -1,8 +1,11 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -200,6 +203,21 @@ func TestGetErrorCode(t *testing.T) {
 			err:      NewPermissionErrorWithPath("/path", "denied"),
 			expected: CodePermissionDenied,
 		},
+		{
+			name:     "TimeoutError",
+			err:      NewTimeoutError("took too long"),
+			expected: CodeTimeout,
+		},
+		{
+			name:     "UnavailableError",
+			err:      NewUnavailableError("podman", "socket unreachable"),
+			expected: CodeUnavailable,
+		},
+		{
+			name:     "ConflictStateError",
+			err:      NewConflictStateError("pod", "1", "pod has applications"),
+			expected: CodeConflictState,
+		},
 		{
 			name:     "unknown error returns internal",
 			err:      fmt.Errorf("some random error"),
@@ -215,6 +233,32 @@ func TestGetErrorCode(t *testing.T) {
 	}
 }
 
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		name     string
+		expected int
+	}{
+		{nil, "nil", 0},
+		{NewInvalidInputError("bad"), "InvalidInputError", ExitInvalidInput},
+		{NewNotFoundError("app", "1"), "NotFoundError", ExitNotFound},
+		{NewAlreadyExistsError("app", "1"), "AlreadyExistsError", ExitAlreadyExists},
+		{NewPermissionError("denied"), "PermissionError", ExitPermissionDenied},
+		{NewInternalError("failed"), "InternalError", ExitGeneral},
+		{NewTimeoutError("took too long"), "TimeoutError", ExitTimeout},
+		{NewUnavailableError("podman", "down"), "UnavailableError", ExitUnavailable},
+		{NewConflictStateError("pod", "1", "in use"), "ConflictStateError", ExitConflictState},
+		{fmt.Errorf("failed to get app: %w", NewNotFoundError("app", "1")), "wrapped NotFoundError", ExitNotFound},
+		{fmt.Errorf("connection refused"), "untyped error", ExitGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExitCode(tt.err))
+		})
+	}
+}
+
 func TestGetBaseError(t *testing.T) {
 	t.Run("from NotFoundError", func(t *testing.T) {
 		err := NewNotFoundError("app", "123")
@@ -275,3 +319,45 @@ func TestErrorMessages(t *testing.T) {
 		assert.Equal(t, "PERMISSION_DENIED: cannot create directory (path=/var/lib/simplify)", msg)
 	})
 }
+
+func TestClassify(t *testing.T) {
+	assert.NoError(t, Classify(nil))
+
+	timeout := Classify(fmt.Errorf("listing containers: %w", context.DeadlineExceeded))
+	assert.True(t, IsTimeout(timeout))
+	assert.ErrorIs(t, timeout, context.DeadlineExceeded)
+
+	dial := &net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}
+	unavailable := Classify(fmt.Errorf("listing containers: %w", dial))
+	assert.True(t, IsUnavailable(unavailable))
+	assert.Equal(t, ExitUnavailable, ExitCode(unavailable))
+
+	refused := Classify(fmt.Errorf("ping: %w", syscall.ECONNREFUSED))
+	assert.True(t, IsUnavailable(refused))
+
+	// Classified and unrelated errors are unchanged
+	notFound := NewNotFoundError("app", "1")
+	assert.Same(t, notFound, Classify(notFound))
+	plain := fmt.Errorf("image not found")
+	assert.Equal(t, plain, Classify(plain))
+}
+
+func TestStackCapture(t *testing.T) {
+	assert.Empty(t, NewInternalError("disabled").Stack)
+
+	SetStackCapture(true)
+	defer SetStackCapture(false)
+
+	err := fmt.Errorf("saving: %w", NewInternalErrorWithCause("failed", errors.New("disk full")))
+	stack := StackTrace(err)
+	assert.Contains(t, stack, "TestStackCapture")
+	assert.NotContains(t, stack, "captureStack")
+
+	assert.Empty(t, StackTrace(NewNotFoundError("app", "1")))
+}
+
+func TestDocURL(t *testing.T) {
+	assert.Equal(t, DocsURL+"#conflict_state", DocURL(CodeConflictState))
+	assert.Equal(t, DocsURL+"#internal_error", DocURL(CodeInternal))
+	assert.Empty(t, DocURL("SOMETHING_ELSE"))
+}
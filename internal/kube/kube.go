@@ -0,0 +1,280 @@
+// Package kube renders Simplify resources as Kubernetes manifests.
+package kube
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"go.yaml.in/yaml/v3"
+)
+
+// Labels set on every generated object
+const (
+	LabelName      = "app.kubernetes.io/name"
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+)
+
+// maxNameLength is the longest name Kubernetes accepts for these objects
+const maxNameLength = 63
+
+// The types below are the subset of the Kubernetes API needed to describe a
+// Simplify application. They are declared here rather than importing the
+// Kubernetes client libraries for a handful of fields.
+
+// Object is a generated Kubernetes object
+type Object struct { //nolint:govet // field order is the YAML key order
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   Metadata `yaml:"metadata"`
+	Spec       any      `yaml:"spec"`
+}
+
+// Metadata is the object metadata of a generated object
+type Metadata struct {
+	Labels map[string]string `yaml:"labels,omitempty"`
+	Name   string            `yaml:"name"`
+}
+
+// DeploymentSpec is the spec of an apps/v1 Deployment
+type DeploymentSpec struct {
+	Selector LabelSelector   `yaml:"selector"`
+	Template PodTemplateSpec `yaml:"template"`
+	Replicas int             `yaml:"replicas"`
+}
+
+// LabelSelector selects pods by label
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// PodTemplateSpec describes the pods a Deployment creates
+type PodTemplateSpec struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     PodSpec  `yaml:"spec"`
+}
+
+// PodSpec lists the containers of a pod
+type PodSpec struct {
+	Containers []Container `yaml:"containers"`
+}
+
+// Container is a single container in a pod
+type Container struct {
+	Name  string          `yaml:"name"`
+	Image string          `yaml:"image"`
+	Env   []EnvVar        `yaml:"env,omitempty"`
+	Ports []ContainerPort `yaml:"ports,omitempty"`
+}
+
+// EnvVar is an environment variable of a container
+type EnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// ContainerPort is a port exposed by a container
+type ContainerPort struct {
+	ContainerPort int `yaml:"containerPort"`
+}
+
+// ServiceSpec is the spec of a v1 Service
+type ServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+	Ports    []ServicePort     `yaml:"ports"`
+}
+
+// ServicePort maps a service port to a container port
+type ServicePort struct {
+	Name       string `yaml:"name"`
+	Port       int    `yaml:"port"`
+	TargetPort int    `yaml:"targetPort"`
+}
+
+// ApplicationObjects returns a Deployment for the application, and a Service
+// when it publishes ports. Published host ports become service ports.
+func ApplicationObjects(app *core.Application) ([]Object, error) {
+	name := Name(app.Name)
+	if name == "" {
+		return nil, fmt.Errorf("application %q has no usable name", app.Name)
+	}
+
+	c, err := container(app)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := servicePorts(app.Ports)
+	if err != nil {
+		return nil, fmt.Errorf("application %s: %w", app.Name, err)
+	}
+
+	return objects(name, app.Replicas, []Container{c}, ports), nil
+}
+
+// PodObjects returns a Deployment running every application of the pod as
+// containers of a single Kubernetes pod, which shares the network namespace
+// like a Podman pod does, and a Service for the pod's published ports.
+func PodObjects(pod *core.Pod, apps []core.Application) ([]Object, error) {
+	name := Name(pod.Name)
+	if name == "" {
+		return nil, fmt.Errorf("pod %q has no usable name", pod.Name)
+	}
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("pod %s has no applications", pod.Name)
+	}
+
+	containers := make([]Container, 0, len(apps))
+	for i := range apps {
+		c, err := container(&apps[i])
+		if err != nil {
+			return nil, err
+		}
+		// Ports are published by the pod, not its members
+		c.Ports = nil
+		containers = append(containers, c)
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+
+	ports, err := servicePorts(pod.Ports)
+	if err != nil {
+		return nil, fmt.Errorf("pod %s: %w", pod.Name, err)
+	}
+	for _, p := range ports {
+		containers[0].Ports = append(containers[0].Ports, ContainerPort{ContainerPort: p.TargetPort})
+	}
+
+	return objects(name, 1, containers, ports), nil
+}
+
+// Marshal renders objects as a multi-document YAML stream
+func Marshal(objs []Object) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for i := range objs {
+		if err := enc.Encode(&objs[i]); err != nil {
+			return nil, fmt.Errorf("encoding %s %s: %w", objs[i].Kind, objs[i].Metadata.Name, err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Name converts a Simplify name into a valid Kubernetes object name:
+// lowercase alphanumerics and dashes, at most 63 characters
+func Name(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+
+	name := b.String()
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return strings.Trim(name, "-")
+}
+
+// objects builds the Deployment and, when there are ports, the Service
+func objects(name string, replicas int, containers []Container, ports []ServicePort) []Object {
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	selector := map[string]string{LabelName: name}
+	labels := map[string]string{LabelName: name, LabelManagedBy: "simplify"}
+
+	objs := []Object{{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Metadata:   Metadata{Name: name, Labels: labels},
+		Spec: DeploymentSpec{
+			Replicas: replicas,
+			Selector: LabelSelector{MatchLabels: selector},
+			Template: PodTemplateSpec{
+				Metadata: Metadata{Labels: selector},
+				Spec:     PodSpec{Containers: containers},
+			},
+		},
+	}}
+
+	if len(ports) > 0 {
+		objs = append(objs, Object{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Metadata:   Metadata{Name: name, Labels: labels},
+			Spec:       ServiceSpec{Selector: selector, Ports: ports},
+		})
+	}
+	return objs
+}
+
+// container converts an application into a container spec
+func container(app *core.Application) (Container, error) {
+	if app.Image == "" {
+		return Container{}, fmt.Errorf("application %s has no image", app.Name)
+	}
+
+	c := Container{Name: Name(app.Name), Image: app.Image}
+
+	keys := make([]string, 0, len(app.EnvVars))
+	for k := range app.EnvVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		c.Env = append(c.Env, EnvVar{Name: k, Value: app.EnvVars[k]})
+	}
+
+	ports, err := servicePorts(app.Ports)
+	if err != nil {
+		return Container{}, fmt.Errorf("application %s: %w", app.Name, err)
+	}
+	for _, p := range ports {
+		c.Ports = append(c.Ports, ContainerPort{ContainerPort: p.TargetPort})
+	}
+	return c, nil
+}
+
+// servicePorts converts host:container port maps into service ports, sorted by port
+func servicePorts(m map[string]string) ([]ServicePort, error) {
+	ports := make([]ServicePort, 0, len(m))
+	for host, target := range m {
+		port, err := parsePort(host)
+		if err != nil {
+			return nil, err
+		}
+		targetPort, err := parsePort(target)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, ServicePort{
+			Name:       "port-" + strconv.Itoa(port),
+			Port:       port,
+			TargetPort: targetPort,
+		})
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+	return ports, nil
+}
+
+// parsePort parses a port number, ignoring a "/tcp" style protocol suffix
+func parsePort(s string) (int, error) {
+	s, _, _ = strings.Cut(s, "/")
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
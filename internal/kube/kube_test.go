@@ -0,0 +1,101 @@
+package kube
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationObjects(t *testing.T) {
+	app := &core.Application{
+		Name:     "Web_App",
+		Image:    "nginx:latest",
+		Replicas: 3,
+		Ports:    map[string]string{"8443": "443", "8080": "80"},
+		EnvVars:  map[string]string{"B": "2", "A": "1"},
+	}
+
+	objs, err := ApplicationObjects(app)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	deploy := objs[0]
+	assert.Equal(t, "Deployment", deploy.Kind)
+	assert.Equal(t, "web-app", deploy.Metadata.Name)
+
+	spec := deploy.Spec.(DeploymentSpec)
+	assert.Equal(t, 3, spec.Replicas)
+	require.Len(t, spec.Template.Spec.Containers, 1)
+	c := spec.Template.Spec.Containers[0]
+	assert.Equal(t, "nginx:latest", c.Image)
+	assert.Equal(t, []EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}}, c.Env)
+	assert.Equal(t, []ContainerPort{{ContainerPort: 80}, {ContainerPort: 443}}, c.Ports)
+
+	svc := objs[1]
+	assert.Equal(t, "Service", svc.Kind)
+	ports := svc.Spec.(ServiceSpec).Ports
+	require.Len(t, ports, 2)
+	assert.Equal(t, ServicePort{Name: "port-8080", Port: 8080, TargetPort: 80}, ports[0])
+}
+
+func TestApplicationObjectsWithoutPorts(t *testing.T) {
+	objs, err := ApplicationObjects(&core.Application{Name: "worker", Image: "busybox"})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, 1, objs[0].Spec.(DeploymentSpec).Replicas)
+}
+
+func TestApplicationObjectsErrors(t *testing.T) {
+	_, err := ApplicationObjects(&core.Application{Name: "web"})
+	assert.Error(t, err)
+
+	_, err = ApplicationObjects(&core.Application{Name: "web", Image: "nginx", Ports: map[string]string{"x": "80"}})
+	assert.Error(t, err)
+
+	_, err = ApplicationObjects(&core.Application{Name: "__", Image: "nginx"})
+	assert.Error(t, err)
+}
+
+func TestPodObjects(t *testing.T) {
+	pod := &core.Pod{Name: "stack", Ports: map[string]string{"8080": "80"}}
+	apps := []core.Application{
+		{Name: "web", Image: "nginx", Ports: map[string]string{"9999": "80"}},
+		{Name: "api", Image: "myapi"},
+	}
+
+	objs, err := PodObjects(pod, apps)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	containers := objs[0].Spec.(DeploymentSpec).Template.Spec.Containers
+	require.Len(t, containers, 2)
+	assert.Equal(t, "api", containers[0].Name)
+	assert.Equal(t, []ContainerPort{{ContainerPort: 80}}, containers[0].Ports)
+	assert.Empty(t, containers[1].Ports)
+
+	_, err = PodObjects(pod, nil)
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	objs, err := ApplicationObjects(&core.Application{Name: "web", Image: "nginx", Ports: map[string]string{"8080": "80"}})
+	require.NoError(t, err)
+
+	data, err := Marshal(objs)
+	require.NoError(t, err)
+
+	out := string(data)
+	assert.Contains(t, out, "apiVersion: apps/v1\nkind: Deployment\n")
+	assert.Contains(t, out, "---\napiVersion: v1\nkind: Service\n")
+	assert.Contains(t, out, "  replicas: 1\n")
+	assert.Contains(t, out, "targetPort: 80")
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "my-app", Name("My_App"))
+	assert.Equal(t, "web", Name("-web-"))
+	assert.Len(t, Name(strings.Repeat("a", 100)), maxNameLength)
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelOff silences a component entirely
+const LevelOff = "off"
+
+// levelOff is above every zap level, so nothing passes it
+const levelOff = zapcore.FatalLevel + 1
+
+// componentLevels maps component names to the minimum level they log at
+var componentLevels atomic.Pointer[map[string]zapcore.Level]
+
+// Component is a named part of Simplify, such as the reconciler, whose
+// level can be set separately from the global level
+type Component struct {
+	name string
+}
+
+// NewComponent returns the logger of a named component. Entries carry a
+// "component" field. It is safe to call before Init.
+func NewComponent(name string) Component {
+	return Component{name: name}
+}
+
+// SetComponentLevels sets the minimum level of named components, e.g.
+// {"reconciler": "debug", "http": "off"}. A component level replaces the
+// global level for that component, so it can be quieter or more verbose.
+// Components not listed log at the global level, so nil clears all overrides.
+func SetComponentLevels(levels map[string]string) error {
+	parsed := make(map[string]zapcore.Level, len(levels))
+	for name, level := range levels {
+		if level == LevelOff {
+			parsed[name] = levelOff
+			continue
+		}
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q for component %s: must be debug, info, warn, error or off", level, name)
+		}
+		parsed[name] = l
+	}
+	componentLevels.Store(&parsed)
+	return nil
+}
+
+// enabled reports whether the component's level, or the global level when
+// it has none, lets l through
+func (c Component) enabled(l zapcore.Level) bool {
+	if componentLogger == nil {
+		return false
+	}
+	if levels := componentLevels.Load(); levels != nil {
+		if min, ok := (*levels)[c.name]; ok {
+			return l >= min
+		}
+	}
+	return globalLevel.Enabled(l)
+}
+
+func (c Component) fields(keysAndValues []any) []any {
+	return append([]any{"component", c.name}, keysAndValues...)
+}
+
+// Debug logs a debug message
+func (c Component) Debug(msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.DebugLevel) {
+		componentLogger.Debugw(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// DebugCtx logs a debug message with context
+func (c Component) DebugCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.DebugLevel) {
+		withOperationID(ctx, componentLogger).Debugw(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// Info logs an info message
+func (c Component) Info(msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.InfoLevel) {
+		componentLogger.Infow(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// InfoCtx logs an info message with context
+func (c Component) InfoCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.InfoLevel) {
+		withOperationID(ctx, componentLogger).Infow(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// Warn logs a warning message
+func (c Component) Warn(msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.WarnLevel) {
+		componentLogger.Warnw(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// WarnCtx logs a warning message with context
+func (c Component) WarnCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.WarnLevel) {
+		withOperationID(ctx, componentLogger).Warnw(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// Error logs an error message
+func (c Component) Error(msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.ErrorLevel) {
+		componentLogger.Errorw(msg, c.fields(keysAndValues)...)
+	}
+}
+
+// ErrorCtx logs an error message with context
+func (c Component) ErrorCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	if c.enabled(zapcore.ErrorLevel) {
+		withOperationID(ctx, componentLogger).Errorw(msg, c.fields(keysAndValues)...)
+	}
+}
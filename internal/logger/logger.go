@@ -3,45 +3,217 @@ package logger
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/AkMo3/simplify/internal/config"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type contextKey string
 
 const operationIDKey contextKey = "operation_id"
 
+// Log formats accepted by Options.Format. FormatConsole is an alias of FormatText.
+const (
+	FormatText    = "text"
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
 var globalLogger *zap.SugaredLogger
 
+// componentLogger writes component entries. Its cores let every level
+// through, since components filter by their own level before the global one.
+var componentLogger *zap.SugaredLogger
+
+// The global logger is rebuilt from these cores when a file is enabled
+var (
+	stdoutCore zapcore.Core
+	fileCore   zapcore.Core // nil unless EnableFile was called
+	sampling   *SamplingOptions
+)
+
+// globalLevel is shared by the global logger so SetLevel can change it at runtime
+var globalLevel = zap.NewAtomicLevel()
+
+// Options override the environment-based logger defaults.
+// Empty fields keep the default for the environment.
+type Options struct {
+	Output     io.Writer         // where entries are written, nil for stdout
+	Sampling   *SamplingOptions  // nil logs every entry
+	Components map[string]string // component name to level, see SetComponentLevels
+	Level      string            // debug, info, warn or error
+	Format     string            // FormatText or FormatJSON
+}
+
+// SamplingOptions limit repeated log entries. Each second, the first Initial
+// entries with the same level and message are logged, then every
+// Thereafter-th one.
+type SamplingOptions struct {
+	Initial    int
+	Thereafter int
+}
+
 // Init initializes the global logger based on environment
 func Init() error {
+	return InitWithOptions(Options{})
+}
+
+// InitWithOptions initializes the global logger based on environment, with
+// the level and format overridden by opts. Development defaults to debug
+// text output, production to info JSON output.
+func InitWithOptions(opts Options) error {
 	if globalLogger != nil {
 		return nil
 	}
 
-	var zapLogger *zap.Logger
-	var err error
+	level, err := parseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
 
+	format := FormatJSON
 	if config.IsDevelopment() {
-		zapLogger, err = newDevelopmentLogger()
+		format = FormatText
+	}
+
+	switch opts.Format {
+	case "":
+	case FormatText, FormatConsole:
+		format = FormatText
+	case FormatJSON:
+		format = FormatJSON
+	default:
+		return fmt.Errorf("invalid log format %q: must be %q, %q or %q", opts.Format, FormatText, FormatConsole, FormatJSON)
+	}
+
+	if err := SetComponentLevels(opts.Components); err != nil {
+		return err
+	}
+
+	globalLevel.SetLevel(level)
+	sampling = opts.Sampling
+
+	out := opts.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	stdout := zapcore.AddSync(out)
+	if format == FormatText {
+		stdoutCore = newTextCore(stdout, zapcore.DebugLevel)
 	} else {
-		zapLogger, err = newProductionLogger()
+		stdoutCore = newJSONCore(stdout, zapcore.DebugLevel)
+	}
+
+	rebuild()
+	return nil
+}
+
+// rebuild creates the global and component loggers from the stdout and
+// file cores. The global logger is filtered by globalLevel.
+func rebuild() {
+	core := stdoutCore
+	if fileCore != nil {
+		core = zapcore.NewTee(stdoutCore, fileCore)
+	}
+	if sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, sampling.Initial, sampling.Thereafter)
 	}
+	base := newLogger(core)
+	componentLogger = base.Sugar()
+	globalLogger = base.WithOptions(zap.IncreaseLevel(globalLevel)).Sugar()
+}
+
+// FileOptions configure a log file written in addition to stdout
+type FileOptions struct {
+	Path       string
+	MaxSize    int // megabytes before the file is rotated
+	MaxBackups int // rotated files to keep, 0 keeps all
+	MaxAge     int // days to keep rotated files, 0 keeps all
+}
+
+// EnableFile also writes log entries to a size-rotated file, as JSON so the
+// file can be parsed regardless of the stdout format. Only the server calls
+// it, so CLI commands never write to the server's log file.
+func EnableFile(opts FileOptions) error {
+	if globalLogger == nil {
+		return fmt.Errorf("logger is not initialized")
+	}
+
+	file := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   opts.Path,
+		MaxSize:    opts.MaxSize,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAge,
+	})
+
+	fileCore = newJSONCore(file, zapcore.DebugLevel)
+	rebuild()
+	return nil
+}
+
+// Silence stops writing entries to stdout, or Options.Output, until the
+// returned function is called. Full-screen commands use it so log lines do
+// not overwrite their view; a log file keeps receiving entries.
+func Silence() (restore func()) {
+	if globalLogger == nil {
+		return func() {}
+	}
+	saved := stdoutCore
+	stdoutCore = zapcore.NewNopCore()
+	rebuild()
+	return func() {
+		stdoutCore = saved
+		rebuild()
+	}
+}
 
+// SetLevel changes the level of the global logger at runtime. An empty
+// level restores the default for the environment.
+func SetLevel(level string) error {
+	parsed, err := parseLevel(level)
 	if err != nil {
 		return err
 	}
-
-	globalLogger = zapLogger.Sugar()
+	globalLevel.SetLevel(parsed)
 	return nil
 }
 
-// newDevelopmentLogger creates a human-readable logger for development
-func newDevelopmentLogger() (*zap.Logger, error) {
+// Level returns the current level of the global logger
+func Level() string {
+	return globalLevel.Level().String()
+}
+
+// parseLevel parses a level name, falling back to debug in development and
+// info in production when it is empty
+func parseLevel(level string) (zapcore.Level, error) {
+	if level == "" {
+		if config.IsDevelopment() {
+			return zapcore.DebugLevel, nil
+		}
+		return zapcore.InfoLevel, nil
+	}
+
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+	return parsed, nil
+}
+
+// newLogger wraps a core with the caller annotations used by every logger
+func newLogger(core zapcore.Core) *zap.Logger {
+	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+}
+
+// newTextCore creates a human-readable core, used by default in development
+func newTextCore(w zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "time",
 		LevelKey:       "level",
@@ -56,17 +228,11 @@ func newDevelopmentLogger() (*zap.Logger, error) {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	core := zapcore.NewCore(
-		zapcore.NewConsoleEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zapcore.DebugLevel,
-	)
-
-	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)), nil
+	return zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), w, level)
 }
 
-// newProductionLogger creates a JSON logger for production
-func newProductionLogger() (*zap.Logger, error) {
+// newJSONCore creates a JSON core, used by default in production
+func newJSONCore(w zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
 		LevelKey:       "level",
@@ -81,13 +247,7 @@ func newProductionLogger() (*zap.Logger, error) {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(os.Stdout),
-		zapcore.InfoLevel,
-	)
-
-	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1)), nil
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), w, level)
 }
 
 // Sync flushes any buffered log entries
@@ -120,13 +280,18 @@ func loggerWithContext(ctx context.Context) *zap.SugaredLogger {
 	if globalLogger == nil {
 		Init() //nolint:errcheck // fallback initialization
 	}
+	return withOperationID(ctx, globalLogger)
+}
+
+// withOperationID adds the operation ID in ctx, if any, to l
+func withOperationID(ctx context.Context, l *zap.SugaredLogger) *zap.SugaredLogger {
 	if ctx == nil {
-		return globalLogger
+		return l
 	}
 	if opID := OperationIDFromContext(ctx); opID != "" {
-		return globalLogger.With("operation_id", opID)
+		return l.With("operation_id", opID)
 	}
-	return globalLogger
+	return l
 }
 
 // Debug logs a debug message (development only)
@@ -0,0 +1,234 @@
+// Package manifest handles declarative YAML descriptions of Simplify resources.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"go.yaml.in/yaml/v3"
+)
+
+// Manifest is the root of a declarative resource file.
+// Field names follow the JSON names used by the HTTP API.
+type Manifest struct {
+	Teams        []core.Team        `json:"teams,omitempty"`
+	Projects     []core.Project     `json:"projects,omitempty"`
+	Environments []core.Environment `json:"environments,omitempty"`
+	Applications []core.Application `json:"applications,omitempty"`
+}
+
+// IsEmpty reports whether the manifest declares no resources
+func (m *Manifest) IsEmpty() bool {
+	return len(m.Teams) == 0 && len(m.Projects) == 0 &&
+		len(m.Environments) == 0 && len(m.Applications) == 0
+}
+
+// Merge appends all resources from other into m
+func (m *Manifest) Merge(other *Manifest) {
+	m.Teams = append(m.Teams, other.Teams...)
+	m.Projects = append(m.Projects, other.Projects...)
+	m.Environments = append(m.Environments, other.Environments...)
+	m.Applications = append(m.Applications, other.Applications...)
+}
+
+// Parse decodes one or more YAML documents into a single manifest
+func Parse(data []byte) (*Manifest, error) {
+	result := &Manifest{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("parsing yaml: %w", err)
+		}
+		if doc == nil {
+			continue
+		}
+
+		// Round-trip through JSON so the core types' json tags apply
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("converting yaml document: %w", err)
+		}
+
+		var m Manifest
+		dj := json.NewDecoder(bytes.NewReader(raw))
+		dj.DisallowUnknownFields()
+		if err := dj.Decode(&m); err != nil {
+			return nil, fmt.Errorf("decoding manifest: %w", err)
+		}
+		result.Merge(&m)
+	}
+
+	return result, nil
+}
+
+// Load reads a manifest from a file, or from every .yaml/.yml file in a directory
+func Load(path string) (*Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	if !info.IsDir() {
+		return loadFile(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest directory: %w", err)
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if ext == ".yaml" || ext == ".yml" {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	result := &Manifest{}
+	for _, f := range files {
+		m, err := loadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(m)
+	}
+
+	return result, nil
+}
+
+// loadFile reads and parses a single manifest file
+func loadFile(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Marshal encodes a manifest as YAML using the API field names
+func Marshal(m *Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportedApplication is an application as exported for another host: its
+// spec, without IDs or state managed by the server
+type exportedApplication struct {
+	AutoDeploy   *core.AutoDeploy   `json:"auto_deploy,omitempty"`
+	Resources    *core.Resources    `json:"resources,omitempty"`
+	HealthCheck  *core.HealthCheck  `json:"health_check,omitempty"`
+	EnvVars      map[string]string  `json:"env_vars,omitempty"`
+	Ports        map[string]string  `json:"ports,omitempty"`
+	NodeSelector map[string]string  `json:"node_selector,omitempty"`
+	Name         string             `json:"name"`
+	Image        string             `json:"image"`
+	SensitiveEnv []string           `json:"sensitive_env,omitempty"`
+	Secrets      []core.SecretRef   `json:"secrets,omitempty"`
+	Volumes      []core.VolumeMount `json:"volumes,omitempty"`
+	ConfigFiles  []core.ConfigFile  `json:"config_files,omitempty"`
+	Alerts       []core.AlertRule   `json:"alerts,omitempty"`
+	Replicas     int                `json:"replicas"`
+	Stopped      bool               `json:"stopped,omitempty"`
+	Interactive  bool               `json:"interactive,omitempty"`
+}
+
+// ExportApplication encodes an application as a manifest that can be
+// applied on another host. IDs, the environment, pod, stack, network and
+// node it belongs to here, and its runtime state are left out. Sensitive
+// environment variables are kept with empty values, and secrets by name
+// only; a comment at the top lists what must be provided before applying.
+func ExportApplication(app *core.Application) ([]byte, error) {
+	exported := exportedApplication{
+		AutoDeploy:   app.AutoDeploy,
+		Resources:    app.Resources,
+		HealthCheck:  app.HealthCheck,
+		EnvVars:      maps.Clone(app.EnvVars),
+		Ports:        app.Ports,
+		NodeSelector: app.NodeSelector,
+		Name:         app.Name,
+		Image:        app.Image,
+		SensitiveEnv: app.SensitiveEnv,
+		Secrets:      app.Secrets,
+		Volumes:      app.Volumes,
+		ConfigFiles:  app.ConfigFiles,
+		Alerts:       app.Alerts,
+		Replicas:     app.Replicas,
+		Stopped:      app.Stopped,
+		Interactive:  app.Interactive,
+	}
+
+	var emptied []string
+	for k := range exported.EnvVars {
+		if core.IsSensitiveEnv(k, app.SensitiveEnv) {
+			exported.EnvVars[k] = ""
+			emptied = append(emptied, k)
+		}
+	}
+	sort.Strings(emptied)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Application %s exported from Simplify. Apply with: simplify apply -f <file>\n", app.Name)
+	if len(emptied) > 0 {
+		fmt.Fprintf(&buf, "# Set the values of %s before applying.\n", strings.Join(emptied, ", "))
+	}
+	if len(app.Secrets) > 0 {
+		names := make([]string, 0, len(app.Secrets))
+		for _, ref := range app.Secrets {
+			names = append(names, ref.Name)
+		}
+		fmt.Fprintf(&buf, "# Create the secrets %s on the target host first.\n", strings.Join(names, ", "))
+	}
+	if err := encode(&buf, map[string]any{"applications": []exportedApplication{exported}}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encode writes v as YAML using its JSON field names
+func encode(w io.Writer, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	return nil
+}
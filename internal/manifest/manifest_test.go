@@ -0,0 +1,127 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("single document", func(t *testing.T) {
+		data := []byte(`
+teams:
+  - id: platform
+    name: Platform
+applications:
+  - name: web
+    image: nginx:latest
+    environment_id: prod
+    ports:
+      "8080": "80"
+    env_vars:
+      LOG_LEVEL: debug
+`)
+		m, err := Parse(data)
+		require.NoError(t, err)
+
+		require.Len(t, m.Teams, 1)
+		assert.Equal(t, "platform", m.Teams[0].ID)
+		require.Len(t, m.Applications, 1)
+		assert.Equal(t, "prod", m.Applications[0].EnvironmentID)
+		assert.Equal(t, "80", m.Applications[0].Ports["8080"])
+		assert.Equal(t, "debug", m.Applications[0].EnvVars["LOG_LEVEL"])
+	})
+
+	t.Run("multiple documents are merged", func(t *testing.T) {
+		data := []byte(`
+projects:
+  - name: api
+---
+projects:
+  - name: worker
+`)
+		m, err := Parse(data)
+		require.NoError(t, err)
+		assert.Len(t, m.Projects, 2)
+	})
+
+	t.Run("unknown fields are rejected", func(t *testing.T) {
+		_, err := Parse([]byte("applications:\n  - name: web\n    imgae: nginx\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		m, err := Parse([]byte(""))
+		require.NoError(t, err)
+		assert.True(t, m.IsEmpty())
+	})
+}
+
+func TestLoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("teams:\n  - name: a\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("teams:\n  - name: b\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600))
+
+	m, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, m.Teams, 2)
+	assert.Equal(t, "a", m.Teams[0].Name)
+	assert.Equal(t, "b", m.Teams[1].Name)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	m, err := Parse([]byte("applications:\n  - name: web\n    image: nginx\n    replicas: 2\n"))
+	require.NoError(t, err)
+
+	data, err := Marshal(m)
+	require.NoError(t, err)
+
+	again, err := Parse(data)
+	require.NoError(t, err)
+	require.Len(t, again.Applications, 1)
+	assert.Equal(t, "nginx", again.Applications[0].Image)
+	assert.Equal(t, 2, again.Applications[0].Replicas)
+}
+
+func TestExportApplication(t *testing.T) {
+	app := &core.Application{
+		ID:            "a1",
+		Name:          "web",
+		Image:         "nginx",
+		EnvironmentID: "prod",
+		NodeID:        "node1",
+		Status:        "running",
+		Replicas:      2,
+		EnvVars:       map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": "hunter2", "API_URL": "https://api"},
+		SensitiveEnv:  []string{"API_URL"},
+		Secrets:       []core.SecretRef{{Name: "tls-key", File: "/run/secrets/key"}},
+		Alerting:      []string{core.AlertCPU},
+	}
+
+	data, err := ExportApplication(app)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "hunter2")
+	assert.Contains(t, string(data), "# Set the values of API_URL, DB_PASSWORD before applying.")
+	assert.Contains(t, string(data), "# Create the secrets tls-key on the target host first.")
+
+	m, err := Parse(data)
+	require.NoError(t, err)
+	require.Len(t, m.Applications, 1)
+	exported := m.Applications[0]
+	assert.Empty(t, exported.ID)
+	assert.Empty(t, exported.EnvironmentID)
+	assert.Empty(t, exported.NodeID)
+	assert.Empty(t, exported.Status)
+	assert.Empty(t, exported.Alerting)
+	assert.Equal(t, 2, exported.Replicas)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": "", "API_URL": ""}, exported.EnvVars)
+	assert.Equal(t, app.Secrets, exported.Secrets)
+
+	// The exported copy is independent of the stored application
+	assert.Equal(t, "hunter2", app.EnvVars["DB_PASSWORD"])
+}
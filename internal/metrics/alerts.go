@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/notify"
+)
+
+// SetPublisher sends notifications when alerts fire and resolve to p. It
+// must be called before Start.
+func (c *Collector) SetPublisher(p notify.Publisher) {
+	c.events = p
+}
+
+// evaluateAlerts checks the alert rules of each application against its
+// latest sample. A rule fires once its metric has stayed above the
+// threshold for the rule's duration, and resolves on the first sample
+// under it. Applications without running replicas have no sample, which
+// resolves their alerts.
+func (c *Collector) evaluateAlerts(ctx context.Context, samples map[string]core.MetricSample, now time.Time) error {
+	apps, err := c.store.WithContext(ctx).ListApplications()
+	if err != nil {
+		return fmt.Errorf("listing applications: %w", err)
+	}
+
+	since := make(map[string]time.Time, len(c.since))
+	for i := range apps {
+		app := &apps[i]
+		if len(app.Alerts) == 0 && len(app.Alerting) == 0 {
+			continue
+		}
+		sample, sampled := samples[app.ID]
+
+		var firing []string
+		for j := range app.Alerts {
+			rule := &app.Alerts[j]
+			if !sampled || !rule.Exceeded(&sample) {
+				continue
+			}
+			key := app.ID + "/" + rule.Metric
+			start, ok := c.since[key]
+			if !ok {
+				start = now
+			}
+			since[key] = start
+			// Alerts recorded before a restart keep firing without waiting again
+			if now.Sub(start) >= rule.DurationValue() || slices.Contains(app.Alerting, rule.Metric) {
+				firing = append(firing, rule.Metric)
+			}
+		}
+		if slices.Equal(firing, app.Alerting) {
+			continue
+		}
+
+		if err := c.store.WithContext(ctx).SetApplicationAlerting(app.ID, firing); err != nil {
+			log.WarnCtx(ctx, "Failed to record alert state", "app", app.Name, "error", err)
+			continue
+		}
+		for j := range app.Alerts {
+			rule := &app.Alerts[j]
+			was, is := slices.Contains(app.Alerting, rule.Metric), slices.Contains(firing, rule.Metric)
+			switch {
+			case is && !was:
+				c.publish(ctx, app, notify.EventAlertFiring, fmt.Sprintf("%s is %s, above %s for %s",
+					rule.Metric, formatMetric(rule.Metric, rule.Value(&sample)), formatMetric(rule.Metric, rule.Threshold), rule.DurationValue()))
+			case was && !is:
+				c.publish(ctx, app, notify.EventAlertResolved, fmt.Sprintf("%s is back under %s",
+					rule.Metric, formatMetric(rule.Metric, rule.Threshold)))
+			}
+		}
+		// Rules removed while firing resolve silently
+	}
+	c.since = since
+	return nil
+}
+
+// publish records an alert event in the activity feed and sends it to the
+// publisher, if any
+func (c *Collector) publish(ctx context.Context, app *core.Application, eventType, message string) {
+	log.InfoCtx(ctx, "Alert changed", "app", app.Name, "event", eventType, "message", message)
+
+	a := core.Activity{Actor: core.ActorSystem, Action: eventType, Message: message}
+	if err := c.store.WithContext(ctx).RecordResourceActivity(&a, app); err != nil {
+		log.WarnCtx(ctx, "Failed to record activity", "app", app.Name, "error", err)
+	}
+
+	if c.events == nil {
+		return
+	}
+	e := notify.Event{Type: eventType, Application: app.Name, Message: message}
+	team, err := c.store.WithContext(ctx).ApplicationTeam(app)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to find the team of an application", "app", app.Name, "error", err)
+	} else if team != nil {
+		e.TeamID, e.Team = team.ID, team.Name
+	}
+	c.events.Publish(ctx, e)
+}
+
+// formatMetric renders a value of an alert metric
+func formatMetric(metric string, v float64) string {
+	if metric == core.AlertMemory {
+		return fmt.Sprintf("%.0f MiB", v/(1<<20))
+	}
+	return fmt.Sprintf("%.1f%% CPU", v)
+}
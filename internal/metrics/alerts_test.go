@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/notify"
+	"github.com/AkMo3/simplify/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects published events
+type recorder struct {
+	events []notify.Event
+}
+
+func (r *recorder) Publish(ctx context.Context, e notify.Event) {
+	r.events = append(r.events, e)
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.CreateApplication(&core.Application{
+		ID: "web", Name: "web", Image: "nginx",
+		Alerts: []core.AlertRule{
+			{Metric: core.AlertCPU, Threshold: 150, Duration: 60},
+			{Metric: core.AlertMemory, Threshold: 512 << 20},
+		},
+	}))
+
+	c := New(s, &fakeEngine{}, 30*time.Second, time.Hour)
+	events := &recorder{}
+	c.SetPublisher(events)
+	ctx := context.Background()
+	alerting := func() []string {
+		app, err := s.GetApplication("web")
+		require.NoError(t, err)
+		return app.Alerting
+	}
+
+	t0 := time.Now().UTC()
+	busy := map[string]core.MetricSample{"web": {CPUPercent: 180, MemoryBytes: 100 << 20}}
+	require.NoError(t, c.evaluateAlerts(ctx, busy, t0))
+	assert.Empty(t, alerting(), "cpu has not been high for its duration yet")
+
+	require.NoError(t, c.evaluateAlerts(ctx, busy, t0.Add(30*time.Second)))
+	assert.Empty(t, alerting())
+
+	require.NoError(t, c.evaluateAlerts(ctx, busy, t0.Add(time.Minute)))
+	assert.Equal(t, []string{core.AlertCPU}, alerting())
+	require.Len(t, events.events, 1)
+	assert.Equal(t, notify.EventAlertFiring, events.events[0].Type)
+	assert.Equal(t, "web", events.events[0].Application)
+	assert.Contains(t, events.events[0].Message, "180.0% CPU")
+
+	// Memory fires on the first sample; cpu keeps firing without a new event
+	busy["web"] = core.MetricSample{CPUPercent: 170, MemoryBytes: 600 << 20}
+	require.NoError(t, c.evaluateAlerts(ctx, busy, t0.Add(90*time.Second)))
+	assert.Equal(t, []string{core.AlertCPU, core.AlertMemory}, alerting())
+	require.Len(t, events.events, 2)
+	assert.Equal(t, notify.EventAlertFiring, events.events[1].Type)
+
+	// A collector started afresh keeps alerts recorded in the store firing
+	c = New(s, &fakeEngine{}, 30*time.Second, time.Hour)
+	c.SetPublisher(events)
+	require.NoError(t, c.evaluateAlerts(ctx, busy, t0.Add(2*time.Minute)))
+	assert.Equal(t, []string{core.AlertCPU, core.AlertMemory}, alerting())
+	assert.Len(t, events.events, 2)
+
+	// Stopped replicas have no sample, which resolves both
+	require.NoError(t, c.evaluateAlerts(ctx, map[string]core.MetricSample{}, t0.Add(150*time.Second)))
+	assert.Empty(t, alerting())
+	require.Len(t, events.events, 4)
+	assert.Equal(t, notify.EventAlertResolved, events.events[2].Type)
+	assert.Equal(t, notify.EventAlertResolved, events.events[3].Type)
+
+	activity, err := s.ListActivity(core.ActivityFilter{ApplicationID: "web"})
+	require.NoError(t, err)
+	assert.Len(t, activity, 4)
+}
@@ -0,0 +1,213 @@
+// Package metrics samples the resource usage of application containers and
+// keeps a history of it for graphs.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/notify"
+	"github.com/AkMo3/simplify/internal/store"
+)
+
+// log is the collector's component logger, see logging.levels
+var log = logger.NewComponent("metrics")
+
+// pruneInterval is how often samples older than the retention are removed
+const pruneInterval = 10 * time.Minute
+
+// Engine is the part of the container engine the collector samples
+type Engine interface {
+	List(ctx context.Context, all bool) ([]container.ContainerInfo, error)
+	Stats(ctx context.Context, names []string) ([]container.ContainerStats, error)
+}
+
+// Collector periodically records the CPU, memory and network usage of
+// each application, summed over its running replicas, and evaluates the
+// applications' alert rules against it
+type Collector struct {
+	store     *store.Store
+	engine    Engine
+	events    notify.Publisher                    // nil sends no notifications
+	previous  map[string]container.ContainerStats // container name -> last sample, for rates
+	since     map[string]time.Time                // app ID/metric -> first sample of a streak over an alert threshold
+	lastPrune time.Time
+	interval  time.Duration
+	retention time.Duration
+}
+
+// New creates a collector that samples every interval and keeps samples
+// for retention
+func New(s *store.Store, engine Engine, interval, retention time.Duration) *Collector {
+	return &Collector{
+		store:     s,
+		engine:    engine,
+		previous:  make(map[string]container.ContainerStats),
+		since:     make(map[string]time.Time),
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Start runs the collection loop until ctx is canceled
+func (c *Collector) Start(ctx context.Context) {
+	log.Info("Starting metrics collection", "interval", c.interval.String(), "retention", c.retention.String())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Stopping metrics collection")
+			return
+		case <-ticker.C:
+			if err := c.collect(ctx); err != nil {
+				log.Warn("Metrics collection failed", "error", err)
+			}
+		}
+	}
+}
+
+// collect samples the running application containers and records one
+// sample per application
+func (c *Collector) collect(ctx context.Context) error {
+	containers, err := c.engine.List(ctx, false)
+	if err != nil {
+		return fmt.Errorf("listing containers: %w", err)
+	}
+
+	appOf := make(map[string]string) // container name -> app ID
+	names := make([]string, 0, len(containers))
+	for i := range containers {
+		appID := containers[i].Labels["simplify.app.id"]
+		if appID == "" || containers[i].Status != "running" {
+			continue
+		}
+		appOf[containers[i].Name] = appID
+		names = append(names, containers[i].Name)
+	}
+
+	stats, err := c.engine.Stats(ctx, names)
+	if err != nil {
+		return fmt.Errorf("sampling containers: %w", err)
+	}
+
+	samples := make(map[string]core.MetricSample)
+	current := make(map[string]container.ContainerStats, len(stats))
+	now := time.Now().UTC()
+	for i := range stats {
+		st := &stats[i]
+		appID, ok := appOf[st.Name]
+		if !ok {
+			continue
+		}
+		current[st.Name] = *st
+
+		sample := samples[appID]
+		sample.Time = now
+		sample.Replicas++
+		sample.MemoryBytes += st.MemoryBytes
+		if prev, ok := c.previous[st.Name]; ok {
+			cpu, rx, tx := rates(&prev, st)
+			sample.CPUPercent += cpu
+			sample.NetRxRate += rx
+			sample.NetTxRate += tx
+		}
+		samples[appID] = sample
+	}
+	// Containers that stopped are forgotten, so a restart starts afresh
+	c.previous = current
+
+	if len(samples) > 0 {
+		if err := c.store.WithContext(ctx).RecordMetrics(samples); err != nil {
+			return fmt.Errorf("recording metrics: %w", err)
+		}
+	}
+	log.DebugCtx(ctx, "Metrics collected", "applications", len(samples), "containers", len(current))
+
+	if err := c.evaluateAlerts(ctx, samples, now); err != nil {
+		return fmt.Errorf("evaluating alerts: %w", err)
+	}
+
+	if now.Sub(c.lastPrune) >= pruneInterval {
+		c.lastPrune = now
+		pruned, err := c.store.WithContext(ctx).PruneMetrics(now.Add(-c.retention))
+		if err != nil {
+			return fmt.Errorf("pruning metrics: %w", err)
+		}
+		if pruned > 0 {
+			log.DebugCtx(ctx, "Pruned metric samples", "count", pruned)
+		}
+	}
+	return nil
+}
+
+// rates returns the CPU percentage and the bytes received and sent per
+// second between two samples of a container. Counters that went backwards,
+// as when the container restarted in between, give zero.
+func rates(prev, cur *container.ContainerStats) (cpu, rx, tx float64) {
+	elapsed := cur.Time.Sub(prev.Time)
+	if elapsed <= 0 {
+		return 0, 0, 0
+	}
+	delta := func(before, after uint64) float64 {
+		if after < before {
+			return 0
+		}
+		return float64(after - before)
+	}
+	cpu = delta(prev.CPUNano, cur.CPUNano) / float64(elapsed.Nanoseconds()) * 100
+	rx = delta(prev.NetRxBytes, cur.NetRxBytes) / elapsed.Seconds()
+	tx = delta(prev.NetTxBytes, cur.NetTxBytes) / elapsed.Seconds()
+	return cpu, rx, tx
+}
+
+// Downsample averages samples over consecutive periods of step, so that a
+// long range fits a graph. Each result is timed at the start of its period
+// and reports the most replicas seen in it.
+func Downsample(samples []core.MetricSample, step time.Duration) []core.MetricSample {
+	if step <= 0 || len(samples) == 0 {
+		return samples
+	}
+
+	result := []core.MetricSample{}
+	var sum core.MetricSample
+	var memory float64
+	n := 0
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		sum.CPUPercent /= float64(n)
+		sum.NetRxRate /= float64(n)
+		sum.NetTxRate /= float64(n)
+		sum.MemoryBytes = uint64(memory / float64(n))
+		result = append(result, sum)
+	}
+
+	for i := range samples {
+		s := &samples[i]
+		period := s.Time.Truncate(step)
+		if n > 0 && !period.Equal(sum.Time) {
+			flush()
+			n = 0
+		}
+		if n == 0 {
+			sum = core.MetricSample{Time: period}
+			memory = 0
+		}
+		sum.CPUPercent += s.CPUPercent
+		sum.NetRxRate += s.NetRxRate
+		sum.NetTxRate += s.NetTxRate
+		memory += float64(s.MemoryBytes)
+		sum.Replicas = max(sum.Replicas, s.Replicas)
+		n++
+	}
+	flush()
+	return result
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine returns fixed containers and the next round of stats per call
+type fakeEngine struct {
+	containers []container.ContainerInfo
+	rounds     [][]container.ContainerStats
+	requested  [][]string
+}
+
+func (f *fakeEngine) List(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+	return f.containers, nil
+}
+
+func (f *fakeEngine) Stats(ctx context.Context, names []string) ([]container.ContainerStats, error) {
+	f.requested = append(f.requested, names)
+	stats := f.rounds[0]
+	f.rounds = f.rounds[1:]
+	return stats, nil
+}
+
+func TestCollect(t *testing.T) {
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	t0 := time.Now().UTC()
+	t1 := t0.Add(10 * time.Second)
+	engine := &fakeEngine{
+		containers: []container.ContainerInfo{
+			{Name: "web-1", Status: "running", Labels: map[string]string{"simplify.app.id": "web"}},
+			{Name: "web-2", Status: "running", Labels: map[string]string{"simplify.app.id": "web"}},
+			{Name: "web-3", Status: "exited", Labels: map[string]string{"simplify.app.id": "web"}},
+			{Name: "postgres", Status: "running"},
+		},
+		rounds: [][]container.ContainerStats{
+			{
+				{Name: "web-1", Time: t0, CPUNano: 1e9, MemoryBytes: 100, NetRxBytes: 1000, NetTxBytes: 50},
+				{Name: "web-2", Time: t0, CPUNano: 3e9, MemoryBytes: 200},
+			},
+			{
+				// 0.5s of CPU in 10s is 5%; web-2 restarted, so its counters went back
+				{Name: "web-1", Time: t1, CPUNano: 1.5e9, MemoryBytes: 150, NetRxBytes: 6000, NetTxBytes: 50},
+				{Name: "web-2", Time: t1, CPUNano: 1e8, MemoryBytes: 250},
+			},
+		},
+	}
+	c := New(s, engine, 10*time.Second, time.Hour)
+
+	require.NoError(t, c.collect(context.Background()))
+	require.NoError(t, c.collect(context.Background()))
+	assert.Equal(t, []string{"web-1", "web-2"}, engine.requested[0], "running app containers only")
+
+	samples, err := s.ListMetrics("web", t0.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+
+	first := samples[0]
+	assert.Equal(t, 2, first.Replicas)
+	assert.Equal(t, uint64(300), first.MemoryBytes)
+	assert.Zero(t, first.CPUPercent, "no rate without a previous sample")
+
+	second := samples[1]
+	assert.Equal(t, uint64(400), second.MemoryBytes)
+	assert.InDelta(t, 5.0, second.CPUPercent, 0.001)
+	assert.InDelta(t, 500.0, second.NetRxRate, 0.001)
+	assert.Zero(t, second.NetTxRate)
+}
+
+func TestDownsample(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var samples []core.MetricSample
+	for i := range 6 {
+		samples = append(samples, core.MetricSample{
+			Time:        start.Add(time.Duration(i) * 20 * time.Second),
+			CPUPercent:  float64(i),
+			MemoryBytes: uint64(i) * 100,
+			Replicas:    1 + i%3,
+		})
+	}
+
+	got := Downsample(samples, time.Minute)
+	require.Len(t, got, 2)
+	assert.True(t, got[0].Time.Equal(start))
+	assert.InDelta(t, 1.0, got[0].CPUPercent, 0.001)
+	assert.Equal(t, uint64(100), got[0].MemoryBytes)
+	assert.Equal(t, 3, got[0].Replicas, "most replicas seen")
+	assert.True(t, got[1].Time.Equal(start.Add(time.Minute)))
+	assert.InDelta(t, 4.0, got[1].CPUPercent, 0.001)
+
+	assert.Equal(t, samples, Downsample(samples, 0))
+	assert.Empty(t, Downsample(nil, time.Minute))
+}
@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, keyed with
+// the notifier's secret, as sha256=HEX
+const SignatureHeader = "X-Simplify-Signature"
+
+// defaultSMTPPort is the mail submission port
+const defaultSMTPPort = 587
+
+// httpClient sends Slack and webhook notifications
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// Slack posts events to a Slack incoming webhook
+type Slack struct {
+	URL string
+}
+
+// Notify posts the event as a Slack message
+func (s *Slack) Notify(ctx context.Context, e *Event) error {
+	text := fmt.Sprintf("*%s*\n%s", e.Title(), e.Message)
+	if e.Team != "" {
+		text += "\nTeam: " + e.Team
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return post(ctx, s.URL, body, nil)
+}
+
+// Webhook posts events as JSON to an HTTP endpoint
+type Webhook struct {
+	URL    string
+	Secret string // signs the body in SignatureHeader when set
+}
+
+// Notify posts the event as JSON
+func (w *Webhook) Notify(ctx context.Context, e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	headers := map[string]string{}
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		headers[SignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	return post(ctx, w.URL, body, headers)
+}
+
+// post sends a JSON body and fails unless the response is a 2xx
+func post(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "simplify-notify")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512)) //nolint:errcheck // best effort detail
+		return fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// SMTP emails events
+type SMTP struct {
+	Host     string
+	Username string // empty sends without authentication
+	Password string
+	From     string
+	To       []string
+	Port     int // defaultSMTPPort when 0
+}
+
+// Notify sends the event as a plain text email. The server must offer
+// STARTTLS before credentials are sent, except on localhost.
+func (s *SMTP) Notify(ctx context.Context, e *Event) error {
+	port := s.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+
+	// net/smtp takes no context, so the connection is dialed here and given
+	// the deadline of ctx, and closed if ctx is canceled sooner
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() }) //nolint:errcheck // unblocks the exchange below
+	defer stop()
+
+	if err := s.send(conn, s.message(e)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send delivers the email over conn, as smtp.SendMail does over a
+// connection of its own
+func (s *SMTP) send(conn net.Conn, msg []byte) error {
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		// PlainAuth refuses to send credentials without TLS, except to localhost
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message formats the email for an event
+func (s *SMTP) message(e *Event) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&b, "Subject: [Simplify] %s\r\n", e.Title())
+	fmt.Fprintf(&b, "Date: %s\r\n", e.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(e.Message)
+	b.WriteString("\r\n")
+	if e.Team != "" {
+		fmt.Fprintf(&b, "\r\nTeam: %s\r\n", e.Team)
+	}
+	fmt.Fprintf(&b, "Time: %s\r\n", e.Time.Format(time.RFC3339))
+	return []byte(b.String())
+}
@@ -0,0 +1,193 @@
+// Package notify sends events such as failed deploys to Slack, webhooks
+// and email, routed by the team that owns the application.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/AkMo3/simplify/internal/logger"
+)
+
+// log is the notifier's component logger, see logging.levels
+var log = logger.NewComponent("notify")
+
+// Event types
+const (
+	EventDeploySucceeded   = "deploy.succeeded"   // a new revision of an application is running
+	EventDeployFailed      = "deploy.failed"      // a revision or build could not be deployed
+	EventCrashLoop         = "app.crash_loop"     // an application keeps exiting and being restarted
+	EventHealthChanged     = "app.health_changed" // an application's healthcheck turned healthy or unhealthy
+	EventCertificateFailed = "certificate.failed" // a TLS certificate could not be issued or renewed
+	EventAlertFiring       = "app.alert"          // a metric of an application stayed above its alert threshold
+	EventAlertResolved     = "app.alert_resolved" // a metric of an application is back under its alert threshold
+)
+
+// EventTypes lists every event type, in the order they are documented
+var EventTypes = []string{EventDeploySucceeded, EventDeployFailed, EventCrashLoop, EventHealthChanged, EventCertificateFailed, EventAlertFiring, EventAlertResolved}
+
+// sendTimeout bounds how long one notifier may take to deliver an event
+const sendTimeout = 30 * time.Second
+
+// Event is something that happened to a resource
+type Event struct {
+	Time        time.Time `json:"time"`
+	Type        string    `json:"type"`
+	TeamID      string    `json:"team_id,omitempty"`
+	Team        string    `json:"team,omitempty"` // name of the team
+	Application string    `json:"application,omitempty"`
+	Message     string    `json:"message"`
+}
+
+// Title is a one-line summary of the event
+func (e *Event) Title() string {
+	if e.Application == "" {
+		return e.Type
+	}
+	return e.Type + ": " + e.Application
+}
+
+// Publisher accepts events for delivery
+type Publisher interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// Notifier delivers an event to one destination
+type Notifier interface {
+	Notify(ctx context.Context, e *Event) error
+}
+
+// Dispatcher routes events to the notifiers configured for them. Its
+// configuration can be replaced while it runs.
+type Dispatcher struct {
+	routes atomic.Pointer[[]route]
+	wg     sync.WaitGroup
+}
+
+// route is a NotificationRoute with its notifiers resolved
+type route struct {
+	teams     []string
+	events    []string
+	notifiers []namedNotifier
+}
+
+type namedNotifier struct {
+	Notifier
+	name string
+}
+
+// New returns a Dispatcher for the given configuration
+func New(cfg *config.NotificationsConfig) (*Dispatcher, error) {
+	d := &Dispatcher{}
+	if err := d.Configure(cfg); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Configure replaces the notifiers and routes. On error the previous
+// configuration stays in place.
+func (d *Dispatcher) Configure(cfg *config.NotificationsConfig) error {
+	notifiers := make(map[string]Notifier, len(cfg.Notifiers))
+	for name, nc := range cfg.Notifiers {
+		n, err := newNotifier(&nc)
+		if err != nil {
+			return fmt.Errorf("notifier %s: %w", name, err)
+		}
+		notifiers[strings.ToLower(name)] = n
+	}
+
+	routes := make([]route, 0, len(cfg.Routes))
+	for i, rc := range cfg.Routes {
+		for _, ev := range rc.Events {
+			if !slices.Contains(EventTypes, ev) {
+				return fmt.Errorf("route %d: unknown event %q (must be one of %s)", i+1, ev, strings.Join(EventTypes, ", "))
+			}
+		}
+		r := route{teams: rc.Teams, events: rc.Events}
+		for _, name := range rc.Notifiers {
+			n, ok := notifiers[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("route %d: unknown notifier %q", i+1, name)
+			}
+			r.notifiers = append(r.notifiers, namedNotifier{Notifier: n, name: name})
+		}
+		routes = append(routes, r)
+	}
+
+	d.routes.Store(&routes)
+	return nil
+}
+
+// newNotifier creates the notifier for a configured type
+func newNotifier(nc *config.NotifierConfig) (Notifier, error) {
+	switch nc.Type {
+	case config.NotifierSlack:
+		return &Slack{URL: nc.URL}, nil
+	case config.NotifierWebhook:
+		return &Webhook{URL: nc.URL, Secret: nc.Secret}, nil
+	case config.NotifierSMTP:
+		return &SMTP{Host: nc.Host, Port: nc.Port, Username: nc.Username, Password: nc.Password, From: nc.From, To: nc.To}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", nc.Type)
+	}
+}
+
+// matches reports whether the route applies to an event
+func (r *route) matches(e *Event) bool {
+	if len(r.events) > 0 && !slices.Contains(r.events, e.Type) {
+		return false
+	}
+	if len(r.teams) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.teams, func(t string) bool {
+		return (e.TeamID != "" && t == e.TeamID) || (e.Team != "" && strings.EqualFold(t, e.Team))
+	})
+}
+
+// Publish delivers an event in the background to the notifiers of every
+// route it matches. A notifier on several matching routes gets it once.
+func (d *Dispatcher) Publish(ctx context.Context, e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	sent := make(map[Notifier]bool)
+	for _, r := range *d.routes.Load() {
+		if !r.matches(&e) {
+			continue
+		}
+		for _, n := range r.notifiers {
+			if sent[n.Notifier] {
+				continue
+			}
+			sent[n.Notifier] = true
+
+			d.wg.Add(1)
+			go func() {
+				defer d.wg.Done()
+				// Delivery outlives the operation that raised the event
+				sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+				defer cancel()
+				if err := n.Notify(sendCtx, &e); err != nil {
+					log.WarnCtx(sendCtx, "Failed to send notification", "notifier", n.name, "event", e.Type, "error", err)
+					return
+				}
+				log.DebugCtx(sendCtx, "Notification sent", "notifier", n.name, "event", e.Type)
+			}()
+		}
+	}
+}
+
+// Wait blocks until the events published so far have been delivered or
+// have failed
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
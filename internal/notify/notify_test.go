@@ -0,0 +1,231 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is an HTTP endpoint that keeps the requests it receives
+type recorder struct {
+	*httptest.Server
+	bodies  []string
+	headers []http.Header
+	mu      sync.Mutex
+}
+
+func newRecorder(t *testing.T) *recorder {
+	rec := &recorder{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.bodies = append(rec.bodies, string(body))
+		rec.headers = append(rec.headers, r.Header.Clone())
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *recorder) received() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string(nil), rec.bodies...)
+}
+
+func TestDispatcherRouting(t *testing.T) {
+	slack, hook := newRecorder(t), newRecorder(t)
+
+	d, err := New(&config.NotificationsConfig{
+		Notifiers: map[string]config.NotifierConfig{
+			"ops":   {Type: config.NotifierSlack, URL: slack.URL},
+			"audit": {Type: config.NotifierWebhook, URL: hook.URL, Secret: "k"},
+		},
+		Routes: []config.NotificationRoute{
+			{Teams: []string{"Payments"}, Events: []string{EventDeployFailed, EventCrashLoop}, Notifiers: []string{"OPS"}},
+			{Notifiers: []string{"audit"}},
+			{Events: []string{EventDeployFailed}, Notifiers: []string{"audit", "ops"}},
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	d.Publish(ctx, Event{Type: EventDeployFailed, TeamID: "t1", Team: "payments", Application: "web", Message: "image not found"})
+	d.Publish(ctx, Event{Type: EventDeploySucceeded, TeamID: "t2", Team: "search", Application: "api", Message: "running"})
+	d.Wait()
+
+	// The failure matches every route but reaches each notifier once
+	require.Len(t, slack.received(), 1)
+	assert.Contains(t, slack.received()[0], "deploy.failed: web")
+	assert.Contains(t, slack.received()[0], "image not found")
+
+	bodies := hook.received()
+	require.Len(t, bodies, 2)
+	var types []string
+	for _, body := range bodies {
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(body), &e))
+		assert.False(t, e.Time.IsZero())
+		types = append(types, e.Type)
+	}
+	assert.ElementsMatch(t, []string{EventDeployFailed, EventDeploySucceeded}, types)
+
+	mac := hmac.New(sha256.New, []byte("k"))
+	mac.Write([]byte(bodies[0]))
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), hook.headers[0].Get(SignatureHeader))
+
+	// Reconfiguring replaces the routes
+	require.NoError(t, d.Configure(&config.NotificationsConfig{}))
+	d.Publish(ctx, Event{Type: EventDeployFailed, Application: "web"})
+	d.Wait()
+	assert.Len(t, slack.received(), 1)
+}
+
+func TestConfigureErrors(t *testing.T) {
+	notifiers := map[string]config.NotifierConfig{"ops": {Type: config.NotifierSlack, URL: "https://hooks.example.com"}}
+
+	_, err := New(&config.NotificationsConfig{
+		Notifiers: notifiers,
+		Routes:    []config.NotificationRoute{{Events: []string{"deploy.exploded"}, Notifiers: []string{"ops"}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown event")
+
+	d, err := New(&config.NotificationsConfig{Notifiers: notifiers, Routes: []config.NotificationRoute{{Notifiers: []string{"ops"}}}})
+	require.NoError(t, err)
+	err = d.Configure(&config.NotificationsConfig{Routes: []config.NotificationRoute{{Notifiers: []string{"missing"}}}})
+	require.Error(t, err)
+	assert.Len(t, *d.routes.Load(), 1, "previous routes kept")
+}
+
+func TestNotifierErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := (&Slack{URL: srv.URL}).Notify(context.Background(), &Event{Type: EventCrashLoop})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_token")
+}
+
+func TestSMTPMessage(t *testing.T) {
+	s := &SMTP{From: "simplify@example.com", To: []string{"a@example.com", "b@example.com"}}
+	e := &Event{
+		Time:        time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Type:        EventHealthChanged,
+		Application: "web",
+		Team:        "payments",
+		Message:     "replica 1 (web) is now unhealthy",
+	}
+
+	msg := string(s.message(e))
+	assert.Contains(t, msg, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, msg, "Subject: [Simplify] app.health_changed: web\r\n")
+	assert.Contains(t, msg, "Date: Fri, 16 Oct 2026 09:30:00 +0000\r\n")
+	_, body, ok := strings.Cut(msg, "\r\n\r\n")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(body, "replica 1 (web) is now unhealthy\r\n"))
+	assert.Contains(t, body, "Team: payments")
+}
+
+// serveSMTP answers one SMTP session on ln, recording the commands and the
+// message it receives
+func serveSMTP(t *testing.T, ln net.Listener) <-chan []string {
+	t.Helper()
+	got := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(got)
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		r := textproto.NewConn(conn)
+		_ = r.PrintfLine("220 localhost ESMTP") //nolint:errcheck // the client reports failures
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				break
+			}
+			lines = append(lines, line)
+			verb, _, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				_ = r.PrintfLine("250 localhost") //nolint:errcheck // the client reports failures
+			case "DATA":
+				_ = r.PrintfLine("354 go ahead") //nolint:errcheck // the client reports failures
+				body, _ := r.ReadDotLines()      //nolint:errcheck // the client reports failures
+				lines = append(lines, body...)
+				_ = r.PrintfLine("250 queued") //nolint:errcheck // the client reports failures
+			case "QUIT":
+				_ = r.PrintfLine("221 bye") //nolint:errcheck // the client reports failures
+				got <- lines
+				return
+			default:
+				_ = r.PrintfLine("250 ok") //nolint:errcheck // the client reports failures
+			}
+		}
+		got <- lines
+	}()
+	return got
+}
+
+func TestSMTPNotify(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	got := serveSMTP(t, ln)
+
+	s := &SMTP{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "simplify@example.com", To: []string{"a@example.com", "b@example.com"}}
+	e := &Event{Time: time.Now(), Type: EventCrashLoop, Application: "web", Message: "web keeps crashing"}
+	require.NoError(t, s.Notify(context.Background(), e))
+
+	lines := <-got
+	assert.Contains(t, lines, "MAIL FROM:<simplify@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<a@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<b@example.com>")
+	assert.Contains(t, lines, "web keeps crashing")
+}
+
+func TestSMTPNotifyHungServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// The server accepts the connection but never greets
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn) //nolint:errcheck // ends when the client hangs up
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s := &SMTP{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "simplify@example.com", To: []string{"a@example.com"}}
+
+	start := time.Now()
+	err = s.Notify(ctx, &Event{Time: time.Now(), Type: EventCrashLoop})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,90 @@
+package permissions
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// rootfulSocketDir holds the socket of the system-wide (rootful) Podman service
+const rootfulSocketDir = "/run/podman/"
+
+// accessReadWrite is the access(2) mode for read and write permission
+const accessReadWrite = 0o6
+
+// CheckPodmanSocket verifies that the Podman API socket at path exists, is a
+// socket, and can be read and written by the current user. It also detects
+// rootless/rootful mismatches, such as root using a user's rootless socket.
+// Errors carry remediation steps in the style of the directory checks.
+func CheckPodmanSocket(path string) error {
+	rootless := os.Geteuid() != 0
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.NewUnavailableErrorWithCause("podman",
+				formatSocketHelp("Podman socket not found", path, rootless), err)
+		}
+		if os.IsPermission(err) {
+			return errors.NewPermissionErrorFull(path,
+				formatSocketHelp("cannot access Podman socket", path, rootless), err)
+		}
+		return errors.NewInternalErrorWithCause(
+			fmt.Sprintf("failed to access Podman socket %s", path), err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return errors.NewPermissionErrorWithPath(path,
+			fmt.Sprintf("path exists but is not a socket: %s. Check PODMAN_SOCK or the context's socket", path))
+	}
+
+	owner := -1
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		owner = int(st.Uid)
+	}
+
+	// Root talking to a user's socket creates containers in that user's
+	// rootless storage, which is almost never intended
+	if !rootless && owner > 0 {
+		return errors.NewPermissionErrorWithPath(path, fmt.Sprintf(
+			"running as root but %s is the rootless socket of uid %d. "+
+				"You can either:\n"+
+				"  1. Run simplify as that user, without sudo\n"+
+				"  2. Use the rootful socket: sudo systemctl enable --now podman.socket "+
+				"and unset PODMAN_SOCK", path, owner))
+	}
+
+	if err := syscall.Access(path, accessReadWrite); err != nil {
+		if rootless && (owner == 0 || strings.HasPrefix(path, rootfulSocketDir)) {
+			return errors.NewPermissionErrorFull(path, fmt.Sprintf(
+				"cannot use %s: it is the rootful Podman socket and you are not root. "+
+					"You can either:\n"+
+					"  1. Use rootless Podman: systemctl --user enable --now podman.socket "+
+					"and unset PODMAN_SOCK\n"+
+					"  2. Run with elevated privileges: sudo simplify ...", path), err)
+		}
+		return errors.NewPermissionErrorFull(path,
+			formatSocketHelp("cannot read and write Podman socket", path, rootless), err)
+	}
+
+	return nil
+}
+
+// formatSocketHelp creates an error message with steps to start or reach
+// the Podman socket for the current user
+func formatSocketHelp(problem, path string, rootless bool) string {
+	start := "sudo systemctl enable --now podman.socket"
+	if rootless {
+		start = "systemctl --user enable --now podman.socket"
+	}
+	return fmt.Sprintf(
+		"%s: %s. "+
+			"You can either:\n"+
+			"  1. Start the Podman API socket: %s\n"+
+			"  2. Point PODMAN_SOCK at a running socket\n"+
+			"  3. Check the socket's ownership: ls -l %s",
+		problem, path, start, path)
+}
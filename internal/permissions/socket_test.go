@@ -0,0 +1,57 @@
+package permissions
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPodmanSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("listening socket", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "podman.sock")
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer l.Close()
+
+		assert.NoError(t, CheckPodmanSocket(path))
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		err := CheckPodmanSocket(filepath.Join(tmpDir, "missing.sock"))
+		require.Error(t, err)
+		assert.True(t, errors.IsUnavailable(err))
+		assert.Contains(t, err.Error(), "enable --now podman.socket")
+	})
+
+	t.Run("not a socket", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "file.sock")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		err := CheckPodmanSocket(path)
+		require.Error(t, err)
+		assert.True(t, errors.IsPermissionError(err))
+		assert.Contains(t, err.Error(), "not a socket")
+	})
+
+	t.Run("no access", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can access any socket")
+		}
+		path := filepath.Join(tmpDir, "locked.sock")
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer l.Close()
+		require.NoError(t, os.Chmod(path, 0o000))
+
+		err = CheckPodmanSocket(path)
+		require.Error(t, err)
+		assert.True(t, errors.IsPermissionError(err))
+	})
+}
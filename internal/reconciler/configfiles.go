@@ -0,0 +1,130 @@
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+)
+
+// SetConfigDir sets the directory config files of applications are written
+// to before they are mounted into containers. It must be called before
+// Start; applications with config files fail to deploy without one.
+func (w *Worker) SetConfigDir(dir string) {
+	w.configDir = dir
+}
+
+// writeConfigFiles writes the config files of an application and returns
+// their read-only mounts. Each version is written once to a directory named
+// by its checksum, so containers of the previous version keep their files
+// until they are replaced.
+func (w *Worker) writeConfigFiles(app *core.Application) ([]container.VolumeMount, error) {
+	if len(app.ConfigFiles) == 0 {
+		return nil, nil
+	}
+	if w.configDir == "" {
+		return nil, fmt.Errorf("no directory is configured for config files")
+	}
+	// The ID names a directory under configDir; never let it point elsewhere
+	if app.ID == "" || app.ID != filepath.Base(app.ID) || app.ID == "." || app.ID == ".." {
+		return nil, fmt.Errorf("application ID %q cannot name a config directory", app.ID)
+	}
+
+	dir := filepath.Join(w.configDir, app.ID, core.ConfigChecksum(app.ConfigFiles))
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		if err := writeConfigDir(dir, app.ConfigFiles); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to check config files: %w", err)
+	}
+
+	mounts := make([]container.VolumeMount, 0, len(app.ConfigFiles))
+	for i := range app.ConfigFiles {
+		f := &app.ConfigFiles[i]
+		mounts = append(mounts, container.VolumeMount{
+			Source:   filepath.Join(dir, configFileName(i, f)),
+			Target:   f.Path,
+			ReadOnly: true,
+		})
+	}
+	return mounts, nil
+}
+
+// writeConfigDir writes files to a temporary directory and renames it to
+// dir, so a failed write never leaves a partial version behind
+func writeConfigDir(dir string, files []core.ConfigFile) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), ".write-")
+	if err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for i := range files {
+		f := &files[i]
+		name := filepath.Join(tmp, configFileName(i, f))
+		if err := os.WriteFile(name, []byte(f.Content), fs.FileMode(f.FileMode())); err != nil {
+			return fmt.Errorf("failed to write config file %s: %w", f.Path, err)
+		}
+		// WriteFile applies the umask, the container should get the mode asked for
+		if err := os.Chmod(name, fs.FileMode(f.FileMode())); err != nil {
+			return fmt.Errorf("failed to write config file %s: %w", f.Path, err)
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return fmt.Errorf("failed to write config files: %w", err)
+	}
+	return nil
+}
+
+// configFileName names the host file of a config file. The index keeps
+// files with the same base name apart.
+func configFileName(i int, f *core.ConfigFile) string {
+	return strconv.Itoa(i) + "-" + path.Base(f.Path)
+}
+
+// pruneConfigFiles removes the versions of config files that are neither
+// current nor mounted by an existing container. keep holds the versions in
+// use as "appID/checksum".
+func (w *Worker) pruneConfigFiles(keep map[string]bool) {
+	if w.configDir == "" {
+		return
+	}
+	apps, err := os.ReadDir(w.configDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("Failed to read config directory", "dir", w.configDir, "error", err)
+		}
+		return
+	}
+
+	for _, a := range apps {
+		appDir := filepath.Join(w.configDir, a.Name())
+		versions, err := os.ReadDir(appDir)
+		if err != nil {
+			continue
+		}
+		kept := 0
+		for _, v := range versions {
+			if keep[a.Name()+"/"+v.Name()] {
+				kept++
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(appDir, v.Name())); err != nil {
+				log.Warn("Failed to remove config files", "dir", appDir, "error", err)
+			}
+		}
+		if kept == 0 {
+			os.Remove(appDir) //nolint:errcheck // fails while another write is in progress
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/notify"
+)
+
+// An application whose containers exit crashLoopRestarts times within
+// crashLoopWindow is reported as crash looping
+const (
+	crashLoopRestarts = 3
+	crashLoopWindow   = 5 * time.Minute
+)
+
+// notices remembers what was last reported, so that each deploy, crash
+// loop and health change is notified once
+type notices struct {
+	revisions map[string]string      // app ID -> revision and outcome last reported, see outcome
+	restarts  map[string][]time.Time // app ID -> recent restarts of exited containers
+	health    map[string]string      // container name -> last healthcheck status
+}
+
+func newNotices() *notices {
+	return &notices{
+		revisions: make(map[string]string),
+		restarts:  make(map[string][]time.Time),
+		health:    make(map[string]string),
+	}
+}
+
+// forget drops the health of containers that no longer exist
+func (n *notices) forget(existing map[string]bool) {
+	for name := range n.health {
+		if !existing[name] {
+			delete(n.health, name)
+		}
+	}
+}
+
+// SetPublisher sends notifications for deploys, crash loops and health
+// changes to p. It must be called before Start.
+func (w *Worker) SetPublisher(p notify.Publisher) {
+	w.events = p
+}
+
+// observe records the state of an existing container, reporting a crash
+// loop or a change of its health, which it returns
+func (w *Worker) observe(ctx context.Context, app *core.Application, info *container.ContainerInfo, replica int) string {
+	current := info.Labels["simplify.app.revision"] == app.Revision()
+	running := info.Status == "running" || strings.HasPrefix(info.Status, "Up")
+
+	if current && running {
+		// Already running this revision, e.g. since before a restart
+		w.notices.revisions[app.ID] = outcome(app, nil)
+	}
+
+	if current && !running {
+		// The container will be restarted below
+		now := time.Now()
+		recent := []time.Time{now}
+		for _, t := range w.notices.restarts[app.ID] {
+			if now.Sub(t) < crashLoopWindow {
+				recent = append(recent, t)
+			}
+		}
+		w.notices.restarts[app.ID] = recent
+		if len(recent) >= crashLoopRestarts {
+			delete(w.notices.restarts, app.ID)
+			w.publish(ctx, app, notify.EventCrashLoop, fmt.Sprintf(
+				"%s exited %d times in the last %s and is being restarted (last status: %s)",
+				info.Name, len(recent), crashLoopWindow, info.Status))
+		}
+	}
+
+	health := w.containerHealth(app, info)
+	if health != core.HealthHealthy && health != core.HealthUnhealthy {
+		return health
+	}
+	previous := w.notices.health[info.Name]
+	w.notices.health[info.Name] = health
+	if previous != "" && previous != health {
+		w.publish(ctx, app, notify.EventHealthChanged, fmt.Sprintf(
+			"replica %d (%s) is now %s", replica+1, info.Name, health))
+	}
+	return health
+}
+
+// deployed reports the outcome of deploying a replica, once per revision
+// unless a failed revision later deploys
+func (w *Worker) deployed(ctx context.Context, app *core.Application, err error) {
+	if w.notices.revisions[app.ID] == outcome(app, err) {
+		return
+	}
+	w.notices.revisions[app.ID] = outcome(app, err)
+
+	if err != nil {
+		w.publish(ctx, app, notify.EventDeployFailed, fmt.Sprintf(
+			"revision %s with image %s failed to deploy: %v", app.Revision(), app.Image, err))
+		return
+	}
+	w.publish(ctx, app, notify.EventDeploySucceeded, fmt.Sprintf(
+		"revision %s with image %s is running", app.Revision(), app.Image))
+}
+
+// outcome identifies the result of deploying the current revision of app
+func outcome(app *core.Application, err error) string {
+	if err != nil {
+		return app.Revision() + " failed"
+	}
+	return app.Revision()
+}
+
+// publish records an event about an application in the activity feed and
+// sends it to the publisher, if any. An agent's store is a copy of the
+// server's, so activity is only recorded on the server.
+func (w *Worker) publish(ctx context.Context, app *core.Application, eventType, message string) {
+	if w.nodeID == "" {
+		a := core.Activity{Actor: core.ActorSystem, Action: eventType, Message: message}
+		if err := w.store.WithContext(ctx).RecordResourceActivity(&a, app); err != nil {
+			log.WarnCtx(ctx, "Failed to record activity", "app", app.Name, "error", err)
+		}
+	}
+
+	if w.events == nil {
+		return
+	}
+	e := notify.Event{Type: eventType, Application: app.Name, Message: message}
+	team, err := w.store.WithContext(ctx).ApplicationTeam(app)
+	if err != nil {
+		log.WarnCtx(ctx, "Failed to find the team of an application", "app", app.Name, "error", err)
+	} else if team != nil {
+		e.TeamID, e.Team = team.ID, team.Name
+	}
+	w.events.Publish(ctx, e)
+}
@@ -0,0 +1,211 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+)
+
+// probeClient sends the requests of http health checks. Redirects are not
+// followed: a 3xx answer already counts as healthy.
+var probeClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+// prober runs the http and tcp health checks of running containers in the
+// background, so a slow check never holds up reconciliation
+type prober struct {
+	probes map[string]*probe // container ID -> state
+	mu     sync.Mutex
+}
+
+// probe is the health check state of one container
+type probe struct {
+	firstSeen time.Time // approximates the container's start for the start period
+	lastRun   time.Time
+	status    string
+	failures  int
+	running   bool
+}
+
+func newProber() *prober {
+	return &prober{probes: make(map[string]*probe)}
+}
+
+// status returns the health of a container, starting a check when one is
+// due. Until the first check completes the container is starting.
+func (p *prober) status(hc *core.HealthCheck, info *container.ContainerInfo, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pr := p.probes[info.ID]
+	if pr == nil {
+		pr = &probe{firstSeen: now, status: core.HealthStarting}
+		p.probes[info.ID] = pr
+	}
+	if !pr.running && now.Sub(pr.lastRun) >= hc.IntervalDuration() {
+		pr.running = true
+		pr.lastRun = now
+		go p.run(*hc, info.ID, probeAddress(hc, info), now)
+	}
+	return pr.status
+}
+
+// run performs one check and records its result
+func (p *prober) run(hc core.HealthCheck, id, address string, now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), hc.TimeoutDuration())
+	defer cancel()
+	err := check(ctx, &hc, address)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pr := p.probes[id]
+	if pr == nil {
+		return // the container is gone
+	}
+	pr.running = false
+
+	switch {
+	case err == nil:
+		pr.status = core.HealthHealthy
+		pr.failures = 0
+	case pr.status == core.HealthStarting && now.Sub(pr.firstSeen) < hc.StartPeriodDuration():
+		// Failures while the application starts up are not counted
+		log.Debug("Health check failed during start period", "container", id, "error", err)
+	default:
+		pr.failures++
+		log.Debug("Health check failed", "container", id, "failures", pr.failures, "error", err)
+		if pr.failures >= hc.RetryCount() {
+			pr.status = core.HealthUnhealthy
+		}
+	}
+}
+
+// forget drops the state of containers that no longer exist
+func (p *prober) forget(existing map[string]bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id := range p.probes {
+		if !existing[id] {
+			delete(p.probes, id)
+		}
+	}
+}
+
+// probeAddress returns where the checked port of a container is reached
+// from this host: its published host port if it has one, its address on
+// the container network otherwise
+func probeAddress(hc *core.HealthCheck, info *container.ContainerInfo) string {
+	port := strconv.Itoa(hc.Port)
+	if binding := info.Ports[port+"/tcp"]; binding != "" {
+		host, hostPort, err := net.SplitHostPort(binding)
+		if err != nil {
+			host, hostPort = "", binding
+		}
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "127.0.0.1"
+		}
+		return net.JoinHostPort(host, hostPort)
+	}
+	return net.JoinHostPort(info.IPAddress, port)
+}
+
+// check runs an http or tcp health check against address
+func check(ctx context.Context, hc *core.HealthCheck, address string) error {
+	if strings.HasPrefix(address, ":") {
+		return fmt.Errorf("container has no address")
+	}
+	if hc.Type == core.HealthCheckTCP {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	path := hc.Path
+	if path == "" {
+		path = "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+path, http.NoBody)
+	if err != nil {
+		return err
+	}
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 399 {
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return nil
+}
+
+// containerHealth returns the health of one of an application's
+// containers, empty when the application has no health check. Exec checks
+// are run by podman, which reports their result.
+func (w *Worker) containerHealth(app *core.Application, info *container.ContainerInfo) string {
+	hc := app.HealthCheck
+	switch {
+	case hc == nil:
+		return ""
+	case info.Status != "running" && !strings.HasPrefix(info.Status, "Up"):
+		return core.HealthStarting // about to be restarted
+	case hc.Type == core.HealthCheckExec:
+		if info.Health == "" {
+			return core.HealthStarting
+		}
+		return info.Health
+	default:
+		return w.prober.status(hc, info, time.Now())
+	}
+}
+
+// appHealth combines the health of an application's replicas: unhealthy
+// if any is, healthy once all are, and empty without a health check
+func appHealth(app *core.Application, replicas []string) string {
+	if app.HealthCheck == nil {
+		return ""
+	}
+	status := core.HealthHealthy
+	for _, h := range replicas {
+		if h == core.HealthUnhealthy {
+			return core.HealthUnhealthy
+		}
+		if h != core.HealthHealthy {
+			status = core.HealthStarting
+		}
+	}
+	return status
+}
+
+// recordHealth stores the health of an application when it changed
+func (w *Worker) recordHealth(ctx context.Context, app *core.Application, status string) {
+	if status == app.HealthStatus {
+		return
+	}
+	if err := w.store.WithContext(ctx).SetApplicationHealth(app.ID, status); err != nil {
+		log.Warn("Failed to record application health", "app", app.Name, "error", err)
+		return
+	}
+	app.HealthStatus = status
+}
+
+// Health returns the health status of each application run here, by
+// application ID, as of the last reconciliation. Applications without a
+// health check are left out.
+func (w *Worker) Health() map[string]string {
+	w.healthMu.Lock()
+	defer w.healthMu.Unlock()
+	return maps.Clone(w.health)
+}
@@ -4,67 +4,180 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/core"
 	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/notify"
+	"github.com/AkMo3/simplify/internal/reporting"
 	"github.com/AkMo3/simplify/internal/store"
+	"github.com/AkMo3/simplify/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// log is the reconciler's component logger, see logging.levels
+var log = logger.NewComponent("reconciler")
+
+// defaultInterval is how often reconciliation runs unless SetInterval is called
+const defaultInterval = 10 * time.Second
+
 // Worker is responsible for reconciling desired state (DB) with actual state (Podman)
 type Worker struct {
-	store     *store.Store
-	container container.ContainerManager
+	store      *store.Store
+	container  container.ContainerManager
+	events     notify.Publisher // nil sends no notifications
+	intervalCh chan time.Duration
+	triggerCh  chan struct{}
+	notices    *notices
+	prober     *prober
+	health     map[string]string // app ID -> health as of the last reconciliation, see Health
+	nodeID     string            // node whose applications are run, "" on the server
+	configDir  string            // where config files are written, see SetConfigDir
+	healthMu   sync.Mutex
 }
 
 // New creates a new reconciler worker
 func New(storeObj *store.Store, containerClient container.ContainerManager) *Worker {
 	return &Worker{
-		store:     storeObj,
-		container: containerClient,
+		store:      storeObj,
+		container:  containerClient,
+		intervalCh: make(chan time.Duration, 1),
+		triggerCh:  make(chan struct{}, 1),
+		notices:    newNotices(),
+		prober:     newProber(),
+	}
+}
+
+// SetNodeID makes the worker run the applications scheduled to a node, as
+// the agent does, instead of those the server runs. It must be called
+// before Start.
+func (w *Worker) SetNodeID(id string) {
+	w.nodeID = id
+}
+
+// SetInterval changes how often reconciliation runs. It can be called before
+// or while the loop is running; the latest value wins.
+func (w *Worker) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	select {
+	case <-w.intervalCh:
+	default:
+	}
+	w.intervalCh <- d
+}
+
+// Trigger requests a reconciliation without waiting for the next tick.
+// Requests made while one is pending are merged.
+func (w *Worker) Trigger() {
+	select {
+	case w.triggerCh <- struct{}{}:
+	default:
 	}
 }
 
 // Start runs the reconciliation loop in a blocking manner
 func (w *Worker) Start(ctx context.Context) {
-	logger.Info("Starting reconciliation loop")
+	log.Info("Starting reconciliation loop")
 
-	ticker := time.NewTicker(10 * time.Second)
+	ticker := time.NewTicker(defaultInterval)
 	defer ticker.Stop()
 
 	// Run once immediately
 	if err := w.reconcile(ctx); err != nil {
-		logger.Error("Reconciliation failed", "error", err)
+		log.Error("Reconciliation failed", "error", err)
+		reporting.CaptureError(ctx, err, "component", "reconciler")
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			logger.Info("Stopping reconciliation loop")
+			log.Info("Stopping reconciliation loop")
 			return
+		case d := <-w.intervalCh:
+			log.Info("Reconcile interval changed", "interval", d.String())
+			ticker.Reset(d)
+		case <-w.triggerCh:
+			log.Debug("Reconciliation triggered")
+			if err := w.reconcile(ctx); err != nil {
+				log.Error("Reconciliation failed", "error", err)
+				reporting.CaptureError(ctx, err, "component", "reconciler")
+			}
 		case <-ticker.C:
 			if err := w.reconcile(ctx); err != nil {
-				logger.Error("Reconciliation failed", "error", err)
+				log.Error("Reconciliation failed", "error", err)
+				reporting.CaptureError(ctx, err, "component", "reconciler")
 			}
 		}
 	}
 }
 
-func (w *Worker) reconcile(ctx context.Context) error {
-	// 1. Reconcile Pods
+func (w *Worker) reconcile(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "reconcile")
+	defer func() { tracing.End(span, err) }()
+
+	// 1. Reconcile Volumes, which pods may mount
+	if err := w.reconcileVolumes(ctx); err != nil {
+		return fmt.Errorf("failed to reconcile volumes: %w", err)
+	}
+
+	// 2. Reconcile Pods
 	if err := w.reconcilePods(ctx); err != nil {
 		return fmt.Errorf("failed to reconcile pods: %w", err)
 	}
 
-	// 2. Reconcile Applications
+	// 3. Reconcile Applications
 	return w.reconcileApps(ctx)
 }
 
+// reconcileVolumes creates engine volumes for stored volumes that are
+// missing. Volumes are never removed here: that would delete their data, so
+// only an explicit delete through the API removes them.
+func (w *Worker) reconcileVolumes(ctx context.Context) error {
+	volumes, err := w.store.WithContext(ctx).ListVolumes()
+	if err != nil {
+		return fmt.Errorf("listing db volumes: %w", err)
+	}
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	existing, err := w.container.ListVolumes(ctx)
+	if err != nil {
+		return fmt.Errorf("listing engine volumes: %w", err)
+	}
+	names := make(map[string]bool, len(existing))
+	for i := range existing {
+		names[existing[i].Name] = true
+	}
+
+	for i := range volumes {
+		v := &volumes[i]
+		if names[v.Name] {
+			continue
+		}
+		log.InfoCtx(ctx, "Creating missing volume", "volume", v.Name)
+		labels := map[string]string{
+			"simplify.volume.id":     v.ID,
+			"simplify.volume.size":   v.Size,
+			"simplify.volume.backup": v.Backup.Schedule,
+		}
+		if _, err := w.container.CreateVolume(ctx, v.Name, labels); err != nil {
+			log.Error("Failed to create volume", "volume", v.Name, "error", err)
+		}
+	}
+	return nil
+}
+
 func (w *Worker) reconcilePods(ctx context.Context) error {
-	pods, err := w.store.ListPods()
+	pods, err := w.store.WithContext(ctx).ListPods()
 	if err != nil {
 		return fmt.Errorf("listing db pods: %w", err)
 	}
@@ -79,21 +192,46 @@ func (w *Worker) reconcilePods(ctx context.Context) error {
 		podName := sanitizeName(pod.Name)
 		exists, err := w.container.PodExists(ctx, podName)
 		if err != nil {
-			logger.Error("Failed to check pod existence", "pod", podName, "error", err)
+			log.Error("Failed to check pod existence", "pod", podName, "error", err)
 			continue
 		}
 
+		if exists {
+			// Published ports and the rest of the pod's spec cannot be changed
+			// on a live pod, so recreate it. Removing it also removes its
+			// containers, which reconcileApps redeploys.
+			info, err := w.container.InspectPod(ctx, podName)
+			if err != nil {
+				log.Error("Failed to inspect pod", "pod", podName, "error", err)
+				continue
+			}
+			changed := ""
+			switch {
+			case !checkPortsMatch(pod.Ports, info.Ports):
+				changed = "ports"
+			case info.Labels["simplify.pod.spec"] != pod.SpecChecksum():
+				changed = "spec"
+			}
+			if changed != "" {
+				log.InfoCtx(ctx, "Pod changed, recreating pod", "pod", podName, "changed", changed)
+				if err := w.container.RemovePod(ctx, podName, true); err != nil {
+					log.Error("Failed to remove pod for update", "pod", podName, "error", err)
+					continue
+				}
+				exists = false
+			}
+		}
+
 		if !exists {
-			logger.InfoCtx(ctx, "Creating missing pod", "pod", podName)
-			// Convert ports map[string]string -> map[uint16]uint16
-			ports, err := parsePorts(pod.Ports)
+			log.InfoCtx(ctx, "Creating missing pod", "pod", podName)
+			opts, err := w.podOptions(ctx, &pod)
 			if err != nil {
-				logger.Error("Invalid ports for pod", "pod", podName, "error", err)
+				log.Error("Invalid pod", "pod", podName, "error", err)
 				continue
 			}
 
-			if _, err := w.container.CreatePod(ctx, podName, ports); err != nil {
-				logger.Error("Failed to create pod", "pod", podName, "error", err)
+			if _, err := w.container.CreatePod(ctx, podName, opts); err != nil {
+				log.Error("Failed to create pod", "pod", podName, "error", err)
 			}
 		}
 	}
@@ -101,8 +239,72 @@ func (w *Worker) reconcilePods(ctx context.Context) error {
 	return nil
 }
 
+// podOptions converts a stored pod into the options the engine creates it
+// with
+func (w *Worker) podOptions(ctx context.Context, pod *core.Pod) (container.PodOptions, error) {
+	// Convert ports map[string]string -> map[uint16]uint16
+	ports, err := parsePorts(pod.Ports)
+	if err != nil {
+		return container.PodOptions{}, fmt.Errorf("invalid ports: %w", err)
+	}
+
+	opts := container.PodOptions{
+		Ports:      ports,
+		Labels:     map[string]string{"simplify.managed": "true", "simplify.pod.id": pod.ID},
+		InfraImage: pod.InfraImage,
+		Hostname:   pod.Hostname,
+	}
+	if checksum := pod.SpecChecksum(); checksum != "" {
+		opts.Labels["simplify.pod.spec"] = checksum
+	}
+	if r := pod.Resources; r != nil {
+		opts.Resources = container.Resources{CPUs: r.CPUs, MemoryBytes: r.MemoryBytes}
+	}
+
+	if pod.NetworkID != "" {
+		net, err := w.store.WithContext(ctx).GetNetwork(pod.NetworkID)
+		if err != nil {
+			return container.PodOptions{}, fmt.Errorf("network %s does not exist", pod.NetworkID)
+		}
+		opts.Network = net.Name
+	}
+
+	volumes, err := w.volumeMounts(ctx, pod.Volumes)
+	if err != nil {
+		return container.PodOptions{}, err
+	}
+	opts.Volumes = volumes
+	return opts, nil
+}
+
+// volumeMounts converts the volume attachments of an application or pod.
+// Volumes must be known to the store so that reconcileVolumes has created
+// them; bind mounts are passed on as they are.
+func (w *Worker) volumeMounts(ctx context.Context, mounts []core.VolumeMount) ([]container.VolumeMount, error) {
+	if len(mounts) == 0 {
+		return nil, nil
+	}
+	stored, err := w.store.WithContext(ctx).ListVolumes()
+	if err != nil {
+		return nil, fmt.Errorf("listing volumes: %w", err)
+	}
+
+	result := make([]container.VolumeMount, 0, len(mounts))
+	for _, m := range mounts {
+		if m.Source != "" {
+			result = append(result, container.VolumeMount{Source: m.Source, Target: m.Path, ReadOnly: m.ReadOnly})
+			continue
+		}
+		if !slices.ContainsFunc(stored, func(v core.Volume) bool { return v.Name == m.Name }) {
+			return nil, fmt.Errorf("volume %s does not exist", m.Name)
+		}
+		result = append(result, container.VolumeMount{Volume: m.Name, Target: m.Path, ReadOnly: m.ReadOnly})
+	}
+	return result, nil
+}
+
 func (w *Worker) reconcileApps(ctx context.Context) error {
-	apps, err := w.store.ListApplications()
+	apps, err := w.store.WithContext(ctx).ListApplications()
 	if err != nil {
 		return fmt.Errorf("failed to list applications: %w", err)
 	}
@@ -112,13 +314,19 @@ func (w *Worker) reconcileApps(ctx context.Context) error {
 		return fmt.Errorf("failed to list containers: %w", err)
 	}
 
-	// Map AppID -> ContainerInfo for reconciliation
-	existingApps := make(map[string]container.ContainerInfo)
+	// Map AppID -> replica -> ContainerInfo for reconciliation
+	existingApps := make(map[string]map[int]container.ContainerInfo)
 	// Map ContainerName -> bool for orphan tracking
 	managedContainers := make(map[string]bool)
+	containerIDs := make(map[string]bool, len(containers))
+	configsInUse := make(map[string]bool)
 
 	for i := range containers {
 		c := &containers[i]
+		containerIDs[c.ID] = true
+		if checksum := c.Labels["simplify.app.config"]; checksum != "" {
+			configsInUse[c.Labels["simplify.app.id"]+"/"+checksum] = true
+		}
 		// Check for managed label
 		isManaged := false
 		appID := ""
@@ -134,141 +342,137 @@ func (w *Worker) reconcileApps(ctx context.Context) error {
 
 		if isManaged {
 			if appID != "" {
-				existingApps[appID] = *c
+				if existingApps[appID] == nil {
+					existingApps[appID] = make(map[int]container.ContainerInfo)
+				}
+				existingApps[appID][replicaIndex(c.Labels)] = *c
 			}
 			managedContainers[c.Name] = true
 		}
 	}
 
+	if err := w.orderByStack(ctx, apps); err != nil {
+		return err
+	}
+	if err := w.orderByDependencies(ctx, apps); err != nil {
+		return err
+	}
+
+	envs, err := w.store.WithContext(ctx).ListEnvironments()
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+	envMap := make(map[string]*core.Environment, len(envs))
+	for i := range envs {
+		envMap[envs[i].ID] = &envs[i]
+	}
+
 	desiredContainerNames := make(map[string]bool)
+	health := make(map[string]string)
 
 	for i := range apps {
 		app := &apps[i]
 
-		// Construct the expected container name
-		containerName := sanitizeName(app.Name)
-		if containerName == "" {
-			containerName = fmt.Sprintf("simplify-%s", app.ID)
+		// Stopped applications and those scheduled elsewhere run no
+		// containers here; any left are orphans
+		if !app.ScheduledOn(w.nodeID) {
+			continue
+		}
+		if app.Stopped {
+			w.recordHealth(ctx, app, "")
+			continue
+		}
+		if checksum := core.ConfigChecksum(app.ConfigFiles); checksum != "" {
+			configsInUse[app.ID+"/"+checksum] = true
 		}
-		desiredContainerNames[containerName] = true
 
-		// Check if it exists by AppID
-		info, exists := existingApps[app.ID]
-		if exists {
-			desiredContainerNames[info.Name] = true
+		// An environment whose limits were lowered keeps the containers
+		// already running, but none are created over its budget
+		env := envMap[app.EnvironmentID]
+		if env != nil {
+			if err := env.CheckLimits(app); err != nil {
+				log.Warn("Application exceeds its environment limits", "app", app.Name, "error", err)
+				for _, info := range existingApps[app.ID] {
+					desiredContainerNames[info.Name] = true
+				}
+				w.deployed(ctx, app, err)
+				continue
+			}
+		}
+		resources := app.EffectiveResources(env)
 
-			// Check if we need to recreate
-			needsRecreate := false
-			switch {
-			case info.Status != "running" && !strings.HasPrefix(info.Status, "Up"):
-				needsRecreate = true
-			case app.PodID != "":
-				// App should be in a Pod.
-				// app.PodID is the DB ID. We need to check if the container is in the CORRECT physical pod.
-				// Podman container info gives us the PHYSICAL Pod ID.
-				// Issues arise if DB Pod ID != Physical Pod ID (e.g. manual recreation).
-				// Strict check: info.PodID should match app.PodID.
-				// Robust check: Resolve app.PodID -> Pod Name -> Current Physical Pod ID.
-
-				pod, err := w.store.GetPod(app.PodID)
-				if err != nil {
-					// DB Pod missing? If strict, we might want to fail or detach.
-					// For now, let's assume if DB pod is missing, we can't enforce pod constraints.
-					logger.Warn("App assigned to non-existent pod in DB", "app", app.Name, "pod_id", app.PodID)
-				} else {
-					// We have the expected Pod Name.
-					// Let's get the CURRENT Physical Pod ID for this name.
-					// We can InspectPod or ListPods. Inspect is cheaper if singular.
-					physicalPod, err := w.container.InspectPod(ctx, sanitizeName(pod.Name))
-					if err != nil {
-						// Physical Pod missing?
-						// reconcilePods should have created it, but maybe it failed or race condition.
-						// If physical pod missing, we definitely need recreation logic to trigger correct deployment path?
-						// Actually, if physical pod is missing, deployApp will fail anyway.
-						// But here we are checking if CURRENT container is valid.
-						// If physical pod missing, current container CANNOT be in it (unless stale info).
-						needsRecreate = true
-						logger.Info("Physical pod missing", "app", app.Name, "pod_name", pod.Name)
-					} else {
-						// We have physical ID. Compare with info.PodID.
-						// Note: IDs might be short (12 chars) or full (64 chars). compare prefix.
-						match := false
-						switch {
-						case info.PodID == physicalPod.ID:
-							match = true
-						case len(info.PodID) > len(physicalPod.ID) && strings.HasPrefix(info.PodID, physicalPod.ID):
-							match = true
-						case len(physicalPod.ID) > len(info.PodID) && strings.HasPrefix(physicalPod.ID, info.PodID):
-							match = true
-						}
-
-						if !match {
-							needsRecreate = true
-							logger.Info("Pod mismatch", "app", app.Name, "expected_pod_name", pod.Name, "expected_pod_id", physicalPod.ID, "actual_pod_id", info.PodID)
-
-						}
+		// Construct the expected container name
+		baseName := sanitizeName(app.Name)
+		if baseName == "" {
+			baseName = fmt.Sprintf("simplify-%s", app.ID)
+		}
+
+		// Replicas beyond the desired count are left out of desiredContainerNames
+		// and removed as orphans below
+		var deployErr error
+		deploying := false
+		replicaHealth := make([]string, 0, app.DesiredReplicas())
+		for replica := 0; replica < app.DesiredReplicas(); replica++ {
+			containerName := replicaName(baseName, replica)
+			desiredContainerNames[containerName] = true
+
+			// Check if it exists by AppID
+			info, exists := existingApps[app.ID][replica]
+			if exists {
+				desiredContainerNames[info.Name] = true
+				h := w.observe(ctx, app, &info, replica)
+
+				if w.needsRecreate(ctx, app, resources, &info) {
+					log.Info("Recreating container", "container", info.Name)
+					if err := w.container.Remove(ctx, info.Name, true); err != nil {
+						log.Error("Failed to remove container for update", "container", info.Name, "error", err)
+						continue
 					}
+					// Mark as missing so we fall through to deploy logic
+					exists = false
+				} else {
+					replicaHealth = append(replicaHealth, h)
 				}
-			case app.PodID == "" && info.PodID != "":
-				// App should NOT be in a Pod, but IS
-				needsRecreate = true
-				logger.Info("Pod mismatch (should be standalone)", "app", app.Name, "actual_pod", info.PodID)
-			case app.NetworkID != "":
-				// App should be in a Network
-				// We need to look up network name from DB ID to check against info.Networks names
-				// This requires unnecessary DB lookup inside the loop?
-				// Optimization: Pre-fetch networks map?
-				// Or... simpler: Just assume if networks list is empty, it's wrong (unless default bridge is implied but usually we want explicit)
-				// Actually, we can just check if we need to reconcile networks.
-				// For now, let's skip complex name resolution and rely on "if not in correct pod" and "should be in network".
-				// If we implement network check properly later.
-				// BUT checking if existing network (like 'bridge') matches is good.
-				// Note: info.Networks contains names.
-				// We can just trigger recreate if we know the network name.
-				// Let's defer strict network name check to avoid N+1 DB lookup here,
-				// UNLESS we pre-load networks.
-				// Given the user issue, the main problem was Pod vs Network switch.
-				// The above "app.PodID == "" && info.PodID != """ check COVERS the specific user case!
-				// User switched from Pod -> Network. So App.PodID is empty, but Info.PodID is set.
-				// So that case is handled.
-
-				// Port check only if standalone
-				if !checkPortsMatch(app.Ports, info.Ports) {
-					needsRecreate = true
-					logger.Info("Ports mismatch", "app", app.Name, "info_ports", info.Ports)
-				}
-			case app.PodID == "" && !checkPortsMatch(app.Ports, info.Ports):
-				// Standalone default bridge
-				needsRecreate = true
-				logger.Info("Ports mismatch", "app", app.Name, "info_ports", info.Ports)
 			}
 
-			if needsRecreate {
-				logger.Info("Recreating container", "container", info.Name)
-				if err := w.container.Remove(ctx, info.Name, true); err != nil {
-					logger.Error("Failed to remove container for update", "container", info.Name, "error", err)
-					continue
+			if !exists {
+				replicaHealth = append(replicaHealth, core.HealthStarting)
+				// Missing or just removed, deploy
+				log.Info("Deploying missing application", "app", app.Name, "replica", replica)
+				deploying = true
+				if err := w.deployApp(ctx, app, resources, containerName, replica); err != nil {
+					log.Error("Failed to deploy app", "app", app.Name, "error", err)
+					reporting.CaptureError(ctx, err, "component", "reconciler", "app", app.Name, "replica", replica)
+					if deployErr == nil {
+						deployErr = err
+					}
 				}
-				// Mark as missing so we fall through to deploy logic
-				exists = false
 			}
 		}
+		if deploying {
+			w.deployed(ctx, app, deployErr)
+		}
 
-		if !exists {
-			// Missing or just removed, deploy
-			logger.Info("Deploying missing application", "app", app.Name)
-			if err := w.deployApp(ctx, app, containerName); err != nil {
-				logger.Error("Failed to deploy app", "app", app.Name, "error", err)
-			}
+		status := appHealth(app, replicaHealth)
+		w.recordHealth(ctx, app, status)
+		if status != "" {
+			health[app.ID] = status
 		}
 	}
 
+	w.healthMu.Lock()
+	w.health = health
+	w.healthMu.Unlock()
+	w.notices.forget(managedContainers)
+	w.prober.forget(containerIDs)
+	w.pruneConfigFiles(configsInUse)
+
 	// Cleanup Orphans
 	for name := range managedContainers {
 		if !desiredContainerNames[name] {
-			logger.Info("Removing orphaned container", "container", name)
+			log.Info("Removing orphaned container", "container", name)
 			if err := w.container.Remove(ctx, name, true); err != nil {
-				logger.Error("Failed to remove orphan", "container", name, "error", err)
+				log.Error("Failed to remove orphan", "container", name, "error", err)
 			}
 		}
 	}
@@ -276,8 +480,167 @@ func (w *Worker) reconcileApps(ctx context.Context) error {
 	return nil
 }
 
+// orderByStack sorts apps so that the members of each stack are deployed
+// in the stack's start order
+func (w *Worker) orderByStack(ctx context.Context, apps []core.Application) error {
+	stacks, err := w.store.WithContext(ctx).ListStacks()
+	if err != nil {
+		return fmt.Errorf("failed to list stacks: %w", err)
+	}
+
+	position := make(map[string]int)
+	for i := range stacks {
+		for pos, appID := range stacks[i].ApplicationIDs {
+			position[appID] = pos
+		}
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return position[apps[i].ID] < position[apps[j].ID]
+	})
+	return nil
+}
+
+// orderByDependencies sorts apps so that each is deployed after the
+// applications it depends on. Other applications keep their order.
+func (w *Worker) orderByDependencies(ctx context.Context, apps []core.Application) error {
+	links, err := w.store.WithContext(ctx).ListLinks()
+	if err != nil {
+		return fmt.Errorf("failed to list links: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(apps))
+	for i := range apps {
+		ids[i] = apps[i].ID
+	}
+	order, err := core.OrderByDependencies(ids, links)
+	if err != nil {
+		log.Warn("Ignoring application dependencies", "error", err)
+		return nil
+	}
+	position := make(map[string]int, len(order))
+	for pos, id := range order {
+		position[id] = pos
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return position[apps[i].ID] < position[apps[j].ID]
+	})
+	return nil
+}
+
+// needsRecreate reports whether an existing container no longer matches the
+// application spec, or is not running, and must be replaced
+func (w *Worker) needsRecreate(ctx context.Context, app *core.Application, resources core.Resources, info *container.ContainerInfo) bool {
+	needsRecreate := false
+	switch {
+	case info.Status != "running" && !strings.HasPrefix(info.Status, "Up"):
+		needsRecreate = true
+	case info.Labels["simplify.app.revision"] != "" && info.Labels["simplify.app.revision"] != app.Revision():
+		// Application spec was updated after this container was created
+		needsRecreate = true
+		log.Info("Application spec changed", "app", app.Name, "revision", app.Revision())
+	case info.Labels["simplify.app.resources"] != resourcesLabel(resources):
+		// The environment's default limits changed
+		needsRecreate = true
+		log.Info("Resource limits changed", "app", app.Name, "resources", resourcesLabel(resources))
+	case info.Labels["simplify.app.config"] != core.ConfigChecksum(app.ConfigFiles):
+		// Config files changed, or the container was created before the label
+		needsRecreate = true
+		log.Info("Config files changed", "app", app.Name)
+	case app.PodID != "":
+		// App should be in a Pod.
+		// app.PodID is the DB ID. We need to check if the container is in the CORRECT physical pod.
+		// Podman container info gives us the PHYSICAL Pod ID.
+		// Issues arise if DB Pod ID != Physical Pod ID (e.g. manual recreation).
+		// Strict check: info.PodID should match app.PodID.
+		// Robust check: Resolve app.PodID -> Pod Name -> Current Physical Pod ID.
+
+		pod, err := w.store.WithContext(ctx).GetPod(app.PodID)
+		if err != nil {
+			// DB Pod missing? If strict, we might want to fail or detach.
+			// For now, let's assume if DB pod is missing, we can't enforce pod constraints.
+			log.Warn("App assigned to non-existent pod in DB", "app", app.Name, "pod_id", app.PodID)
+		} else {
+			// We have the expected Pod Name.
+			// Let's get the CURRENT Physical Pod ID for this name.
+			// We can InspectPod or ListPods. Inspect is cheaper if singular.
+			physicalPod, err := w.container.InspectPod(ctx, sanitizeName(pod.Name))
+			if err != nil {
+				// Physical Pod missing?
+				// reconcilePods should have created it, but maybe it failed or race condition.
+				// If physical pod missing, we definitely need recreation logic to trigger correct deployment path?
+				// Actually, if physical pod is missing, deployApp will fail anyway.
+				// But here we are checking if CURRENT container is valid.
+				// If physical pod missing, current container CANNOT be in it (unless stale info).
+				needsRecreate = true
+				log.Info("Physical pod missing", "app", app.Name, "pod_name", pod.Name)
+			} else {
+				// We have physical ID. Compare with info.PodID.
+				// Note: IDs might be short (12 chars) or full (64 chars). compare prefix.
+				match := false
+				switch {
+				case info.PodID == physicalPod.ID:
+					match = true
+				case len(info.PodID) > len(physicalPod.ID) && strings.HasPrefix(info.PodID, physicalPod.ID):
+					match = true
+				case len(physicalPod.ID) > len(info.PodID) && strings.HasPrefix(physicalPod.ID, info.PodID):
+					match = true
+				}
+
+				if !match {
+					needsRecreate = true
+					log.Info("Pod mismatch", "app", app.Name, "expected_pod_name", pod.Name, "expected_pod_id", physicalPod.ID, "actual_pod_id", info.PodID)
+
+				}
+			}
+		}
+	case app.PodID == "" && info.PodID != "":
+		// App should NOT be in a Pod, but IS
+		needsRecreate = true
+		log.Info("Pod mismatch (should be standalone)", "app", app.Name, "actual_pod", info.PodID)
+	case app.NetworkID != "":
+		// App should be in a Network
+		// We need to look up network name from DB ID to check against info.Networks names
+		// This requires unnecessary DB lookup inside the loop?
+		// Optimization: Pre-fetch networks map?
+		// Or... simpler: Just assume if networks list is empty, it's wrong (unless default bridge is implied but usually we want explicit)
+		// Actually, we can just check if we need to reconcile networks.
+		// For now, let's skip complex name resolution and rely on "if not in correct pod" and "should be in network".
+		// If we implement network check properly later.
+		// BUT checking if existing network (like 'bridge') matches is good.
+		// Note: info.Networks contains names.
+		// We can just trigger recreate if we know the network name.
+		// Let's defer strict network name check to avoid N+1 DB lookup here,
+		// UNLESS we pre-load networks.
+		// Given the user issue, the main problem was Pod vs Network switch.
+		// The above "app.PodID == "" && info.PodID != """ check COVERS the specific user case!
+		// User switched from Pod -> Network. So App.PodID is empty, but Info.PodID is set.
+		// So that case is handled.
+
+		// Port check only if standalone
+		if !checkPortsMatch(app.Ports, info.Ports) {
+			needsRecreate = true
+			log.Info("Ports mismatch", "app", app.Name, "info_ports", info.Ports)
+		}
+	case app.PodID == "" && !checkPortsMatch(app.Ports, info.Ports):
+		// Standalone default bridge
+		needsRecreate = true
+		log.Info("Ports mismatch", "app", app.Name, "info_ports", info.Ports)
+	}
+	return needsRecreate
+}
+
 // deployApp handles the specific logic of converting App struct to Container args
-func (w *Worker) deployApp(ctx context.Context, app *core.Application, containerName string) error {
+func (w *Worker) deployApp(ctx context.Context, app *core.Application, resources core.Resources, containerName string, replica int) (err error) {
+	ctx, span := tracing.Start(ctx, "reconcile.deployApp",
+		attribute.String("app.id", app.ID),
+		attribute.String("app.name", app.Name),
+		attribute.Int("app.replica", replica),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Convert Ports map[string]string -> map[uint16]uint16
 	// Format "8080:80" -> Host:Container
 	ports, err := parsePorts(app.Ports)
@@ -291,19 +654,53 @@ func (w *Worker) deployApp(ctx context.Context, app *core.Application, container
 		env = append(env, fmt.Sprintf("%s=%s", k, v))
 	}
 
+	// Copy referenced secrets into the engine, which injects them without
+	// exposing the values in the container's configuration
+	secrets := make([]container.SecretMount, 0, len(app.Secrets))
+	for _, ref := range app.Secrets {
+		value, err := w.store.WithContext(ctx).SecretValue(ref.Name)
+		if err != nil {
+			return fmt.Errorf("secret %s: %w", ref.Name, err)
+		}
+		engineName := container.ManagedSecretName(ref.Name)
+		if _, err := w.container.CreateSecret(ctx, engineName, strings.NewReader(value), true); err != nil {
+			return fmt.Errorf("syncing secret %s: %w", ref.Name, err)
+		}
+		secrets = append(secrets, container.SecretMount{Secret: engineName, Env: ref.Env, Target: ref.File})
+	}
+
+	volumes, err := w.volumeMounts(ctx, app.Volumes)
+	if err != nil {
+		return err
+	}
+
+	configs, err := w.writeConfigFiles(app)
+	if err != nil {
+		return err
+	}
+	volumes = append(volumes, configs...)
+
 	// Define Labels
 	labels := map[string]string{
-		"simplify.managed":  "true",
-		"simplify.app.id":   app.ID,
-		"simplify.app.name": app.Name,
+		"simplify.managed":      "true",
+		"simplify.app.id":       app.ID,
+		"simplify.app.name":     app.Name,
+		"simplify.app.revision": app.Revision(),
+		"simplify.app.replica":  strconv.Itoa(replica),
+	}
+	if label := resourcesLabel(resources); label != "" {
+		labels["simplify.app.resources"] = label
+	}
+	if checksum := core.ConfigChecksum(app.ConfigFiles); checksum != "" {
+		labels["simplify.app.config"] = checksum
 	}
 
 	// Determine Pod Name if valid
 	podName := ""
 	if app.PodID != "" {
-		pod, err := w.store.GetPod(app.PodID)
+		pod, err := w.store.WithContext(ctx).GetPod(app.PodID)
 		if err != nil {
-			logger.WarnCtx(ctx, "App assigned to non-existent pod", "app", app.Name, "pod_id", app.PodID)
+			log.WarnCtx(ctx, "App assigned to non-existent pod", "app", app.Name, "pod_id", app.PodID)
 			// Decide: Fail or run standalone?
 			// Let's run standalone but log warning, OR better: fail to deploy until Pod is ready.
 			return fmt.Errorf("pod %s does not exist", app.PodID)
@@ -315,9 +712,9 @@ func (w *Worker) deployApp(ctx context.Context, app *core.Application, container
 	// Determine Network Name if valid
 	networkName := ""
 	if app.NetworkID != "" {
-		net, err := w.store.GetNetwork(app.NetworkID)
+		net, err := w.store.WithContext(ctx).GetNetwork(app.NetworkID)
 		if err != nil {
-			logger.WarnCtx(ctx, "App assigned to non-existent network", "app", app.Name, "network_id", app.NetworkID)
+			log.WarnCtx(ctx, "App assigned to non-existent network", "app", app.Name, "network_id", app.NetworkID)
 			// Decide: Fail or run with default?
 			// Let's fail because if user wants custom network, falling back to bridge might be confusing security-wise.
 			return fmt.Errorf("network %s does not exist", app.NetworkID)
@@ -326,10 +723,79 @@ func (w *Worker) deployApp(ctx context.Context, app *core.Application, container
 	}
 
 	// Call Container Client
-	_, err = w.container.Run(ctx, containerName, app.Image, ports, env, labels, podName, networkName)
+	_, err = w.container.Run(ctx, containerName, container.RunOptions{
+		Image:       app.Image,
+		Ports:       ports,
+		Env:         env,
+		Secrets:     secrets,
+		Volumes:     volumes,
+		Resources:   container.Resources{CPUs: resources.CPUs, MemoryBytes: resources.MemoryBytes},
+		Health:      podmanHealthCheck(app),
+		Labels:      labels,
+		Interactive: app.Interactive,
+		Pod:         podName,
+		Network:     networkName,
+		Aliases:     networkAliases(app, networkName),
+	})
 	return err
 }
 
+// networkAliases returns the DNS aliases of an application's containers.
+// They only apply on the application's own network, which containers in a
+// pod do not join.
+func networkAliases(app *core.Application, networkName string) []string {
+	if networkName == "" || app.PodID != "" {
+		return nil
+	}
+	return app.NetworkAliases
+}
+
+// podmanHealthCheck returns the exec health check podman runs in an
+// application's containers. Http and tcp checks are probed by the worker.
+func podmanHealthCheck(app *core.Application) *container.HealthCheck {
+	hc := app.HealthCheck
+	if hc == nil || hc.Type != core.HealthCheckExec {
+		return nil
+	}
+	return &container.HealthCheck{
+		Command:     hc.Command,
+		Interval:    hc.IntervalDuration(),
+		Timeout:     hc.TimeoutDuration(),
+		StartPeriod: hc.StartPeriodDuration(),
+		Retries:     hc.RetryCount(),
+	}
+}
+
+// resourcesLabel records the limits a container was created with, empty
+// when it has none
+func resourcesLabel(r core.Resources) string {
+	if r == (core.Resources{}) {
+		return ""
+	}
+	return fmt.Sprintf("cpus=%g,memory=%d", r.CPUs, r.MemoryBytes)
+}
+
+// replicaName returns the container name of a replica. The first replica
+// keeps the plain name so single-replica apps are named as before. Others
+// get a ".rN" suffix, which sanitizeName never produces, so they cannot
+// take the name of another application such as "web-2".
+func replicaName(base string, replica int) string {
+	if replica == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s.r%d", base, replica+1)
+}
+
+// replicaIndex reads the replica label of a container. Containers created
+// before replicas were supported have no label and count as the first replica.
+func replicaIndex(labels map[string]string) int {
+	n, err := strconv.Atoi(labels["simplify.app.replica"])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
 // parsePorts converts "80:80" strings into uint16 map
 func parsePorts(raw map[string]string) (map[uint16]uint16, error) {
 	result := make(map[uint16]uint16, len(raw))
@@ -0,0 +1,136 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEngine keeps containers in memory. Calls the reconciler does not make
+// for plain applications go to the nil embedded interface and panic.
+type fakeEngine struct {
+	container.ContainerManager
+	containers map[string]container.ContainerInfo
+	removed    []string
+}
+
+func newFakeEngine() *fakeEngine {
+	return &fakeEngine{containers: make(map[string]container.ContainerInfo)}
+}
+
+func (f *fakeEngine) List(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+	list := make([]container.ContainerInfo, 0, len(f.containers))
+	for _, c := range f.containers {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (f *fakeEngine) Run(ctx context.Context, name string, opts container.RunOptions) (string, error) {
+	if _, ok := f.containers[name]; ok {
+		return "", fmt.Errorf("the container name %q is already in use", name)
+	}
+	f.containers[name] = container.ContainerInfo{ID: "id-" + name, Name: name, Image: opts.Image, Status: "running", Labels: opts.Labels}
+	return "id-" + name, nil
+}
+
+func (f *fakeEngine) Remove(ctx context.Context, name string, force bool) error {
+	delete(f.containers, name)
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+// names returns the names of the engine's containers, sorted
+func (f *fakeEngine) names() []string {
+	names := make([]string, 0, len(f.containers))
+	for name := range f.containers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func setupWorker(t *testing.T) (*Worker, *store.Store, *fakeEngine) {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	engine := newFakeEngine()
+	return New(s, engine), s, engine
+}
+
+func TestReplicaName(t *testing.T) {
+	assert.Equal(t, "web", replicaName("web", 0))
+	assert.Equal(t, "web.r2", replicaName("web", 1))
+	assert.Equal(t, "web.r3", replicaName("web", 2))
+	assert.NotEqual(t, sanitizeName("web.r2"), "web.r2", "sanitized application names never end like a replica")
+}
+
+func TestNetworkAliases(t *testing.T) {
+	app := &core.Application{Name: "shop-db", NetworkAliases: []string{"db"}}
+	assert.Equal(t, []string{"db"}, networkAliases(app, "shop-default"))
+	assert.Nil(t, networkAliases(app, ""), "no network of its own")
+
+	app.PodID = "pod-1"
+	assert.Nil(t, networkAliases(app, "shop-default"), "containers in a pod use the pod's network")
+}
+
+func TestReconcileReplicas(t *testing.T) {
+	w, s, engine := setupWorker(t)
+	ctx := context.Background()
+
+	web := &core.Application{ID: "app-web", Name: "web", Image: "nginx", Replicas: 3}
+	require.NoError(t, s.CreateApplication(web))
+	// Named like a replica of web used to be
+	web2 := &core.Application{ID: "app-web-2", Name: "web-2", Image: "nginx"}
+	require.NoError(t, s.CreateApplication(web2))
+
+	require.NoError(t, w.reconcileApps(ctx))
+	assert.Equal(t, []string{"web", "web-2", "web.r2", "web.r3"}, engine.names())
+	assert.Equal(t, "app-web-2", engine.containers["web-2"].Labels["simplify.app.id"])
+	assert.Equal(t, "2", engine.containers["web.r3"].Labels["simplify.app.replica"])
+
+	// A second pass has nothing to do
+	require.NoError(t, w.reconcileApps(ctx))
+	assert.Empty(t, engine.removed)
+	assert.Len(t, engine.containers, 4)
+
+	// Scaling down removes the highest replicas
+	web.Replicas = 1
+	require.NoError(t, s.UpdateApplication(web))
+	require.NoError(t, w.reconcileApps(ctx))
+	assert.Equal(t, []string{"web", "web-2"}, engine.names())
+	assert.ElementsMatch(t, []string{"web.r2", "web.r3"}, engine.removed)
+}
+
+func TestReconcileOrphans(t *testing.T) {
+	w, s, engine := setupWorker(t)
+	ctx := context.Background()
+
+	web := &core.Application{ID: "app-web", Name: "web", Image: "nginx"}
+	require.NoError(t, s.CreateApplication(web))
+	engine.containers["gone"] = container.ContainerInfo{Name: "gone", Status: "running", Labels: map[string]string{
+		"simplify.managed": "true", "simplify.app.id": "deleted",
+	}}
+	engine.containers["simplify-legacy"] = container.ContainerInfo{Name: "simplify-legacy", Status: "running"}
+	engine.containers["postgres"] = container.ContainerInfo{Name: "postgres", Status: "running"}
+
+	require.NoError(t, w.reconcileApps(ctx))
+	assert.Equal(t, []string{"postgres", "web"}, engine.names(), "managed containers of no application are removed, others are left alone")
+
+	// Deleting the application removes its containers
+	require.NoError(t, s.DeleteApplication(web.ID))
+	require.NoError(t, w.reconcileApps(ctx))
+	assert.Equal(t, []string{"postgres"}, engine.names())
+}
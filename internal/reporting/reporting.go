@@ -0,0 +1,98 @@
+// Package reporting sends errors and panics to a Sentry-compatible error
+// tracker (Sentry, GlitchTip, ...).
+//
+// Until Init is called every capture is a no-op, so callers report
+// unconditionally and pay nothing when reporting is disabled.
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/getsentry/sentry-go"
+)
+
+// Options configure the reporting client
+type Options struct {
+	Transport   sentry.Transport // overrides the HTTP transport, for tests
+	DSN         string           // project DSN, e.g. https://key@sentry.example.com/1
+	Environment string           // development | production
+	Release     string           // simplify version
+	SampleRate  float64          // fraction of events to send, between 0 and 1
+}
+
+// tagFields are fields also set as tags, so events can be searched by them
+var tagFields = map[string]bool{"component": true, "request_id": true}
+
+// enabled is set once Init succeeds
+var enabled atomic.Bool
+
+// Init configures the global client. The returned function flushes queued
+// events and must be called on shutdown.
+func Init(opts Options) (func(time.Duration) bool, error) {
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:         opts.DSN,
+		Environment: opts.Environment,
+		Release:     opts.Release,
+		SampleRate:  opts.SampleRate,
+		Transport:   opts.Transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize error reporting: %w", err)
+	}
+	enabled.Store(true)
+	return sentry.Flush, nil
+}
+
+// Enabled reports whether Init has configured a client
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// CaptureError reports err. Fields are key-value pairs, as for the logger,
+// and are attached to the event along with the operation ID in ctx.
+func CaptureError(ctx context.Context, err error, fields ...any) {
+	if err == nil || !Enabled() {
+		return
+	}
+	sentry.CurrentHub().WithScope(func(scope *sentry.Scope) {
+		applyFields(ctx, scope, fields)
+		sentry.CurrentHub().CaptureException(err)
+	})
+}
+
+// CapturePanic reports a value recovered from a panic. Fields are handled
+// as in CaptureError.
+func CapturePanic(ctx context.Context, recovered any, fields ...any) {
+	if recovered == nil || !Enabled() {
+		return
+	}
+	sentry.CurrentHub().WithScope(func(scope *sentry.Scope) {
+		applyFields(ctx, scope, fields)
+		scope.SetLevel(sentry.LevelFatal)
+		sentry.CurrentHub().RecoverWithContext(ctx, recovered)
+	})
+}
+
+// applyFields attaches the fields and operation ID to scope
+func applyFields(ctx context.Context, scope *sentry.Scope, fields []any) {
+	extra := make(sentry.Context, len(fields)/2+1)
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
+		}
+		extra[key] = fields[i+1]
+		if tagFields[key] {
+			scope.SetTag(key, fmt.Sprint(fields[i+1]))
+		}
+	}
+	if id := logger.OperationIDFromContext(ctx); id != "" {
+		scope.SetTag("operation_id", id)
+		extra["operation_id"] = id
+	}
+	scope.SetContext("simplify", extra)
+}
@@ -0,0 +1,74 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport keeps events in memory instead of sending them
+type recordingTransport struct {
+	events []*sentry.Event
+	mu     sync.Mutex
+}
+
+func (t *recordingTransport) Configure(sentry.ClientOptions)        {}
+func (t *recordingTransport) Flush(time.Duration) bool              { return true }
+func (t *recordingTransport) FlushWithContext(context.Context) bool { return true }
+func (t *recordingTransport) Close()                                {}
+
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.events
+}
+
+func TestCapture(t *testing.T) {
+	// Captures before Init are dropped
+	CaptureError(context.Background(), errors.New("ignored"))
+	assert.False(t, Enabled())
+
+	transport := &recordingTransport{}
+	flush, err := Init(Options{
+		DSN:         "https://key@sentry.example.com/1",
+		Environment: "production",
+		Release:     "simplify@test",
+		SampleRate:  1,
+		Transport:   transport,
+	})
+	require.NoError(t, err)
+	defer flush(time.Second)
+
+	ctx := logger.WithOperationID(context.Background())
+	CaptureError(ctx, errors.New("podman unreachable"), "component", "reconciler", "app", "web")
+	CapturePanic(ctx, "boom", "request_id", "req-1")
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+
+	failure := events[0]
+	assert.Equal(t, "production", failure.Environment)
+	assert.Equal(t, "simplify@test", failure.Release)
+	require.NotEmpty(t, failure.Exception)
+	assert.Equal(t, "podman unreachable", failure.Exception[len(failure.Exception)-1].Value)
+	assert.Equal(t, "reconciler", failure.Tags["component"])
+	assert.Equal(t, logger.OperationIDFromContext(ctx), failure.Tags["operation_id"])
+	assert.Equal(t, "web", failure.Contexts["simplify"]["app"])
+
+	panicked := events[1]
+	assert.Equal(t, sentry.LevelFatal, panicked.Level)
+	assert.Equal(t, "req-1", panicked.Tags["request_id"])
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// maxActivityLimit is the most entries returned by one request
+const maxActivityLimit = 500
+
+// recordActivity adds a change made by a request to the activity feed. A
+// failure is logged rather than returned, as the change itself was stored.
+func (s *Server) recordActivity(r *http.Request, action string, resource any, message string) {
+	a := core.Activity{Actor: requestActor(r), Action: action, Message: message}
+	if err := s.store.WithContext(r.Context()).RecordResourceActivity(&a, resource); err != nil {
+		log.WarnCtx(r.Context(), "Failed to record activity", "action", action, "error", err)
+	}
+}
+
+// handleListActivity returns the activity feed, newest first, filtered by
+// ?team, ?project and ?application IDs. ?limit caps the entries returned
+// and ?before pages back from an entry's created_at.
+func (s *Server) handleListActivity(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	filter := core.ActivityFilter{
+		TeamID:        q.Get("team"),
+		ProjectID:     q.Get("project"),
+		ApplicationID: q.Get("application"),
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxActivityLimit {
+			return errors.NewInvalidInputErrorWithField("limit",
+				"limit must be between 1 and "+strconv.Itoa(maxActivityLimit))
+		}
+		filter.Limit = limit
+	}
+	if v := q.Get("before"); v != "" {
+		before, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return errors.NewInvalidInputErrorWithField("before", "before must be an RFC 3339 timestamp")
+		}
+		filter.Before = before
+	}
+
+	entries, err := s.store.WithContext(r.Context()).ListActivity(filter)
+	if err != nil {
+		return err
+	}
+	return writeSuccess(w, entries)
+}
+
+// recordTeamActivity records a change to a team's membership
+func (s *Server) recordTeamActivity(r *http.Request, teamID, message string) {
+	team, err := s.store.WithContext(r.Context()).GetTeam(teamID)
+	if err != nil {
+		team = &core.Team{ID: teamID}
+	}
+	s.recordActivity(r, core.ActionUpdated, team, message)
+}
+
+// applicationName returns the name of an application for a message, or its
+// ID when it cannot be read
+func (s *Server) applicationName(r *http.Request, id string) string {
+	if app, err := s.store.WithContext(r.Context()).GetApplication(id); err == nil {
+		return app.Name
+	}
+	return id
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// attachWriteWait bounds each write to an attached client
+	attachWriteWait = 10 * time.Second
+	// attachPingInterval keeps idle connections open through proxies
+	attachPingInterval = 30 * time.Second
+)
+
+// attachUpgrader upgrades attach requests to websockets. It refuses
+// cross-origin requests, so web pages cannot attach on behalf of a user.
+var attachUpgrader = websocket.Upgrader{HandshakeTimeout: 10 * time.Second}
+
+// handleAttach attaches a websocket to the main process of one replica of an
+// application. Output is sent as binary messages whose first byte is the
+// stream, client.AttachStdout or client.AttachStderr. Messages from the
+// client are written to stdin if the application is interactive and
+// discarded otherwise; an empty one closes stdin. The server closes the
+// connection when the process exits.
+func (s *Server) handleAttach(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+	replica, err := replicaParam(r)
+	if err != nil {
+		return err
+	}
+
+	// Errors before the upgrade get a JSON response like other endpoints
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+	if !app.ScheduledOn("") {
+		return errors.NewUnavailableError("attach", "applications run by agent nodes can only be attached to on the node")
+	}
+	name, err := s.replicaContainer(r, id, replica)
+	if err != nil {
+		return err
+	}
+
+	conn, err := attachUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already answered with an HTTP error
+		log.DebugCtx(r.Context(), "Attach upgrade failed", "app", app.Name, "error", err)
+		return nil
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var stdin io.Reader
+	var stdinWriter *io.PipeWriter
+	if app.Interactive {
+		pr, pw := io.Pipe()
+		defer pr.Close()
+		stdin, stdinWriter = pr, pw
+	}
+	go readAttachInput(conn, stdinWriter, cancel)
+	go pingAttached(ctx, conn, cancel)
+
+	out := &attachOutput{conn: conn}
+	err = s.container.Attach(ctx, name, stdin,
+		&attachStream{out: out, stream: client.AttachStdout},
+		&attachStream{out: out, stream: client.AttachStderr})
+
+	closing := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "process exited")
+	if err != nil && ctx.Err() == nil {
+		log.WarnCtx(r.Context(), "Attach failed", "app", app.Name, "error", err)
+		closing = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "attach failed")
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(attachWriteWait)) //nolint:errcheck // the client may already be gone
+	return nil
+}
+
+// readAttachInput copies the messages of an attached client to stdin, which
+// may be nil to discard them, until the client goes away. An empty message
+// closes stdin. Once the client is gone the attachment is canceled.
+func readAttachInput(conn *websocket.Conn, stdin *io.PipeWriter, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if stdin != nil {
+				stdin.Close()
+			}
+			return
+		}
+		switch {
+		case stdin == nil:
+		case len(data) == 0:
+			stdin.Close()
+			stdin = nil
+		default:
+			if _, err := stdin.Write(data); err != nil {
+				// The process stopped reading; keep serving control frames
+				stdin = nil
+			}
+		}
+	}
+}
+
+// pingAttached pings an attached client until ctx is done, and cancels the
+// attachment when a ping cannot be sent
+func pingAttached(ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc) {
+	ticker := time.NewTicker(attachPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(attachWriteWait)); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// attachOutput serializes the output streams of an attached process onto
+// one websocket
+type attachOutput struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// attachStream writes one output stream of an attached process
+type attachStream struct {
+	out    *attachOutput
+	stream byte
+}
+
+// Write sends p as one message prefixed with the stream
+func (s *attachStream) Write(p []byte) (int, error) {
+	s.out.mu.Lock()
+	defer s.out.mu.Unlock()
+
+	msg := make([]byte, 0, len(p)+1)
+	msg = append(msg, s.stream)
+	msg = append(msg, p...)
+	if err := s.out.conn.SetWriteDeadline(time.Now().Add(attachWriteWait)); err != nil {
+		return 0, err
+	}
+	if err := s.out.conn.WriteMessage(websocket.BinaryMessage, msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
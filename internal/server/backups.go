@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/backup"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// Backups creates and restores stored backups. *backup.Runner implements it.
+type Backups interface {
+	Run(ctx context.Context) (*backup.Backup, error)
+	List(ctx context.Context) ([]backup.Backup, error)
+	Restore(ctx context.Context, name string, volumes bool) (*backup.Backup, error)
+}
+
+// StoredRestoreRequest restores a stored backup
+type StoredRestoreRequest = client.StoredRestoreRequest
+
+// SetBackups enables the stored backups API
+func (s *Server) SetBackups(b Backups) {
+	s.backups = b
+}
+
+// requireBackups fails unless a backup target is configured
+func (s *Server) requireBackups() error {
+	if s.backups == nil {
+		return errors.NewUnavailableError("backup", "no backup target is configured, set backup.dir or backup.s3")
+	}
+	return nil
+}
+
+// handleListBackups lists the backups stored in the backup target, newest first
+func (s *Server) handleListBackups(w http.ResponseWriter, r *http.Request) error {
+	if err := s.requireBackups(); err != nil {
+		return err
+	}
+
+	backups, err := s.backups.List(r.Context())
+	if err != nil {
+		return errors.NewUnavailableErrorWithCause("backup", "failed to list backups", err)
+	}
+	return writeSuccess(w, backups)
+}
+
+// handleCreateBackup backs up the database and the configured volumes to
+// the backup target now
+func (s *Server) handleCreateBackup(w http.ResponseWriter, r *http.Request) error {
+	if err := s.requireBackups(); err != nil {
+		return err
+	}
+	// Volume archives can take longer than the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{}) //nolint:errcheck // unsupported writers keep the timeout
+
+	b, err := s.backups.Run(r.Context())
+	if err != nil {
+		return errors.NewInternalErrorWithCause("backup failed", err)
+	}
+	return writeCreated(w, b)
+}
+
+// handleRestoreBackup replaces the database, and optionally the volumes,
+// with a stored backup
+func (s *Server) handleRestoreBackup(w http.ResponseWriter, r *http.Request) error {
+	if err := s.requireBackups(); err != nil {
+		return err
+	}
+	name := chi.URLParam(r, "name")
+
+	var req StoredRestoreRequest
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{}) //nolint:errcheck // unsupported writers keep the timeout
+
+	b, err := s.backups.Restore(r.Context(), name, req.Volumes)
+	if backup.IsNotFound(err) {
+		return errors.NewNotFoundError("backup", name)
+	}
+	if err != nil {
+		return errors.NewInternalErrorWithCause("restore failed", err)
+	}
+
+	log.InfoCtx(r.Context(), "Stored backup restored", "name", b.Name, "volumes", req.Volumes)
+	s.triggerReconcile()
+	return writeSuccess(w, b)
+}
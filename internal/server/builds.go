@@ -0,0 +1,342 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/build"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/notify"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Builder builds images from source. *build.Builder implements it.
+type Builder interface {
+	Build(ctx context.Context, b *core.Build, out io.Writer) error
+}
+
+// BuildRequest starts a build of an application's project repository
+type BuildRequest = client.BuildRequest
+
+// maxBuildLog is how much of the end of a build's output is kept
+const maxBuildLog = 64 << 10
+
+// buildLogInterval is how often the log of a running build is saved
+const buildLogInterval = 2 * time.Second
+
+// SetBuilder enables builds. Builds left unfinished by a previous run of
+// the server are marked as failed.
+func (s *Server) SetBuilder(b Builder) {
+	s.builder = b
+	failed, err := s.store.FailUnfinishedBuilds("interrupted by a server restart")
+	if err != nil {
+		log.Warn("Failed to mark interrupted builds", "error", err)
+	} else if failed > 0 {
+		log.Info("Marked interrupted builds as failed", "count", failed)
+	}
+}
+
+// handleCreateBuild starts building an application's image from its
+// project repository. The build runs in the background; its status and
+// log are read with handleGetBuild.
+func (s *Server) handleCreateBuild(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req BuildRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	switch req.Strategy {
+	case core.BuildAuto, core.BuildContainerfile, core.BuildBuildpacks:
+	default:
+		return errors.NewInvalidInputErrorWithField("strategy", "strategy must be containerfile or buildpacks")
+	}
+	if req.Containerfile != "" && req.Strategy == core.BuildBuildpacks {
+		return errors.NewInvalidInputErrorWithField("containerfile", "containerfile cannot be used with the buildpacks strategy")
+	}
+
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+
+	b, err := s.startBuild(r, app, req)
+	if err != nil {
+		return err
+	}
+	return writeCreated(w, b)
+}
+
+// startBuild starts building an application's image in the background and
+// returns a copy of the new build
+func (s *Server) startBuild(r *http.Request, app *core.Application, req BuildRequest) (*core.Build, error) {
+	if s.builder == nil {
+		return nil, errors.NewUnavailableError("builder", "builds are not enabled on this server")
+	}
+
+	repoURL, err := s.applicationRepo(r, app)
+	if err != nil {
+		return nil, err
+	}
+
+	// The check and create must not interleave with another request
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	st := s.store.WithContext(r.Context())
+	builds, err := st.ListBuilds(app.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range builds {
+		if !builds[i].Finished() {
+			return nil, errors.NewConflictStateError("application", app.Name, "build "+builds[i].ID+" is still in progress")
+		}
+	}
+
+	b := &core.Build{
+		CreatedAt:     time.Now().UTC(),
+		ID:            uuid.New().String(),
+		ApplicationID: app.ID,
+		RepoURL:       repoURL,
+		Ref:           req.Ref,
+		Strategy:      req.Strategy,
+		Containerfile: req.Containerfile,
+		Context:       req.Context,
+		Status:        core.BuildQueued,
+	}
+	b.Image = build.ImageTag(app.Name, b.ID)
+	if err := st.CreateBuild(b); err != nil {
+		return nil, err
+	}
+
+	// The build outlives the request and owns b from here on
+	created := *b
+	go s.runBuild(r.WithContext(context.WithoutCancel(r.Context())), b)
+
+	message := "build of " + repoURL
+	if req.Ref != "" {
+		message += " at " + req.Ref
+	}
+	s.recordActivity(r, core.ActionCreated, &created, message)
+	log.InfoCtx(r.Context(), "Build started", "app", app.Name, "build", b.ID, "repo", repoURL, "ref", req.Ref)
+	return &created, nil
+}
+
+// applicationRepo returns the repository of the project an application
+// belongs to
+func (s *Server) applicationRepo(r *http.Request, app *core.Application) (string, error) {
+	noRepo := errors.NewInvalidInputError("application " + app.Name + " has no project repository to build from; set repo_url on its project")
+	if app.EnvironmentID == "" {
+		return "", noRepo
+	}
+
+	st := s.store.WithContext(r.Context())
+	env, err := st.GetEnvironment(app.EnvironmentID)
+	if err != nil {
+		return "", err
+	}
+	project, err := st.GetProject(env.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	if project.RepoURL == "" {
+		return "", noRepo
+	}
+	// Projects saved before repository URLs were checked may hold any string
+	if err := validateRepoURL(project.RepoURL); err != nil {
+		return "", err
+	}
+	return project.RepoURL, nil
+}
+
+// validateRepoURL checks that a project's repository is an https or ssh
+// URL, or the scp-like git@host:path form. Anything else, such as a local
+// path or a string starting with -, is refused before it reaches git.
+func validateRepoURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	invalid := errors.NewInvalidInputErrorWithField("repo_url", "repo_url must be an https:// or ssh:// URL, or git@host:path")
+	if strings.ContainsAny(raw, " \t\r\n") {
+		return invalid
+	}
+	if rest, ok := strings.CutPrefix(raw, "git@"); ok {
+		host, path, found := strings.Cut(rest, ":")
+		if !found || host == "" || path == "" || strings.Contains(host, "/") {
+			return invalid
+		}
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "ssh") || u.Host == "" {
+		return invalid
+	}
+	return nil
+}
+
+// runBuild runs a build to completion, saving its log as it goes, and
+// updates the application to the new image when it succeeds
+func (s *Server) runBuild(r *http.Request, b *core.Build) {
+	ctx := r.Context()
+	st := s.store.WithContext(ctx)
+
+	out := &tailBuffer{max: maxBuildLog}
+	save := func() {
+		b.Log = out.String()
+		if err := st.UpdateBuild(b); err != nil {
+			log.WarnCtx(ctx, "Failed to save build", "build", b.ID, "error", err)
+		}
+	}
+
+	b.Status = core.BuildRunning
+	save()
+
+	// The builder owns b while it runs, so progress is saved to the stored copy
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(buildLogInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				s.saveBuildLog(r, b.ID, out.String())
+			}
+		}
+	}()
+
+	err := s.builder.Build(ctx, b, out)
+	close(done)
+	<-stopped
+
+	now := time.Now().UTC()
+	b.FinishedAt = &now
+	if err == nil {
+		err = s.deployBuild(r, b)
+	}
+	if err != nil {
+		b.Status = core.BuildFailed
+		b.Error = err.Error()
+		log.WarnCtx(ctx, "Build failed", "build", b.ID, "error", err)
+		if app, getErr := st.GetApplication(b.ApplicationID); getErr == nil {
+			s.publish(r, app, notify.EventDeployFailed, fmt.Sprintf("build %s of %s failed: %v", b.ID, b.RepoURL, err))
+		}
+	} else {
+		b.Status = core.BuildSucceeded
+		log.InfoCtx(ctx, "Build succeeded", "build", b.ID, "image", b.Image, "commit", b.Commit)
+	}
+	save()
+}
+
+// saveBuildLog updates the log of a stored build
+func (s *Server) saveBuildLog(r *http.Request, buildID, output string) {
+	st := s.store.WithContext(r.Context())
+	stored, err := st.GetBuild(buildID)
+	if err == nil {
+		stored.Log = output
+		err = st.UpdateBuild(stored)
+	}
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to save build log", "build", buildID, "error", err)
+	}
+}
+
+// deployBuild points the build's application at the built image
+func (s *Server) deployBuild(r *http.Request, b *core.Build) error {
+	st := s.store.WithContext(r.Context())
+	app, err := st.GetApplication(b.ApplicationID)
+	if err != nil {
+		return err
+	}
+
+	app.Image = b.Image
+	app.UpdatedAt = time.Now().UTC()
+	if err := st.UpdateApplication(app); err != nil {
+		return err
+	}
+	s.recordDeployment(r, app, "")
+	s.triggerReconcile()
+	return nil
+}
+
+// handleListBuilds returns the builds of an application, newest first,
+// without their logs
+func (s *Server) handleListBuilds(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	if _, err := s.store.WithContext(r.Context()).GetApplication(id); err != nil {
+		return err
+	}
+
+	builds, err := s.store.WithContext(r.Context()).ListBuilds(id)
+	if err != nil {
+		return err
+	}
+	for i := range builds {
+		builds[i].Log = ""
+	}
+
+	return writeSuccess(w, builds)
+}
+
+// handleGetBuild returns a build with its log
+func (s *Server) handleGetBuild(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	buildID := chi.URLParam(r, "buildID")
+	if id == "" || buildID == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id and build id are required")
+	}
+
+	b, err := s.store.WithContext(r.Context()).GetBuild(buildID)
+	if err != nil {
+		return err
+	}
+	if b.ApplicationID != id {
+		return errors.NewNotFoundError("build", buildID)
+	}
+
+	return writeSuccess(w, b)
+}
+
+// tailBuffer keeps the last max bytes written to it
+type tailBuffer struct {
+	buf []byte
+	max int
+	mu  sync.Mutex
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.max; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/net/publicsuffix"
+)
+
+// CloneEnvironmentRequest is the body of POST /environments/{id}/clone
+type CloneEnvironmentRequest = client.CloneEnvironmentRequest
+
+// CloneEnvironmentResponse is the environment created by a clone
+type CloneEnvironmentResponse = client.CloneEnvironmentResponse
+
+// maxPort is the largest TCP port
+const maxPort = 65535
+
+// handleCloneEnvironment copies an environment and its applications into a
+// new environment, such as a staging copy of production. Copies get new
+// names, empty volumes of their own, pending copies of their domains and the
+// links between them. Pods and stacks are not copied, so the copies run
+// outside them. Every name is checked before anything is created, and what
+// was created is removed again if a later step fails.
+func (s *Server) handleCloneEnvironment(w http.ResponseWriter, r *http.Request) (err error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req CloneEnvironmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if req.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if req.NameSuffix == "" {
+		req.NameSuffix = "-" + req.Name
+	}
+	if !engineNamePattern.MatchString("x" + req.NameSuffix) {
+		return errors.NewInvalidInputErrorWithField("name_suffix", "name suffix may only contain letters, digits, '.', '-' and '_'")
+	}
+	if req.HostnameSuffix == "" {
+		req.HostnameSuffix = strings.ToLower(req.NameSuffix)
+	}
+	if req.PortOffset < 0 || req.PortOffset > maxPort {
+		return errors.NewInvalidInputErrorWithField("port_offset", fmt.Sprintf("port offset must be between 0 and %d", maxPort))
+	}
+
+	st := s.store.WithContext(r.Context())
+	source, err := st.GetEnvironment(id)
+	if err != nil {
+		return err
+	}
+	plan, err := s.planClone(r, source, &req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	resp := CloneEnvironmentResponse{
+		Environment: core.Environment{
+			CreatedAt: now,
+			Limits:    source.Limits,
+			Config:    maps.Clone(source.Config),
+			ID:        uuid.New().String(),
+			ProjectID: source.ProjectID,
+			Name:      req.Name,
+		},
+		Applications: make([]core.Application, 0, len(plan.apps)),
+	}
+	// Each object created is removed again, newest first, if a later one
+	// fails, so the clone can be retried under the same names
+	var undo []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if uerr := undo[i](); uerr != nil {
+				log.WarnCtx(r.Context(), "Failed to undo part of a clone", "environment", req.Name, "error", uerr)
+			}
+		}
+	}()
+
+	if err := st.CreateEnvironment(&resp.Environment); err != nil {
+		return err
+	}
+	undo = append(undo, func() error { return st.DeleteEnvironment(resp.Environment.ID) })
+
+	for i := range plan.volumes {
+		v := &plan.volumes[i]
+		if err := st.CreateVolume(v); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return st.DeleteVolume(v.ID) })
+	}
+	for i := range plan.apps {
+		app := &plan.apps[i]
+		app.EnvironmentID = resp.Environment.ID
+		if err := s.scheduleApplication(r, app); err != nil {
+			return err
+		}
+		if err := st.CreateApplication(app); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return st.DeleteApplication(app.ID) })
+	}
+	for i := range plan.links {
+		link := &plan.links[i]
+		if err := st.CreateLink(link); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return st.DeleteLink(link.ID) })
+	}
+	for i := range plan.domains {
+		domain := &plan.domains[i]
+		if err := st.CreateDomain(domain); err != nil {
+			return err
+		}
+		undo = append(undo, func() error { return st.DeleteDomain(domain.ID) })
+		resp.Domains = append(resp.Domains, *domain)
+	}
+
+	// Only a clone that went through is recorded
+	s.recordActivity(r, core.ActionCreated, &resp.Environment, "cloned from "+source.Name)
+	for i := range plan.apps {
+		s.recordDeployment(r, &plan.apps[i], "")
+	}
+
+	for i := range plan.apps {
+		app := plan.apps[i]
+		app.Redact()
+		resp.Applications = append(resp.Applications, app)
+	}
+
+	log.InfoCtx(r.Context(), "Environment cloned", "source", source.Name, "environment", req.Name, "applications", len(plan.apps))
+	return writeCreated(w, resp)
+}
+
+// clonePlan holds what a clone creates, before any of it is stored
+type clonePlan struct {
+	apps    []core.Application
+	volumes []core.Volume
+	links   []core.Link
+	domains []core.Domain
+}
+
+// planClone copies the applications of an environment with their volumes,
+// links and domains, and fails when a name the copies would use is taken
+func (s *Server) planClone(r *http.Request, source *core.Environment, req *CloneEnvironmentRequest) (*clonePlan, error) {
+	st := s.store.WithContext(r.Context())
+
+	apps, err := st.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := st.ListVolumes()
+	if err != nil {
+		return nil, err
+	}
+	domains, err := st.ListDomains()
+	if err != nil {
+		return nil, err
+	}
+	links, err := st.ListLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &clonePlan{}
+	now := time.Now().UTC()
+	ids := make(map[string]string) // source application ID -> ID of its copy
+	volumeNames := make(map[string]string)
+
+	for i := range apps {
+		src := &apps[i]
+		if src.EnvironmentID != source.ID {
+			continue
+		}
+		name := src.Name + req.NameSuffix
+		if slices.ContainsFunc(apps, func(a core.Application) bool { return a.Name == name }) {
+			return nil, errors.NewAlreadyExistsError("application", name)
+		}
+		ports, err := offsetPorts(src.Ports, req.PortOffset)
+		if err != nil {
+			return nil, errors.NewInvalidInputErrorWithField("port_offset", fmt.Sprintf("application %s: %v", src.Name, err))
+		}
+
+		app := core.Application{
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			AutoDeploy:   src.AutoDeploy,
+			Resources:    src.Resources,
+			HealthCheck:  src.HealthCheck,
+			EnvVars:      maps.Clone(src.EnvVars),
+			Ports:        ports,
+			NodeSelector: maps.Clone(src.NodeSelector),
+			Name:         name,
+			ID:           uuid.New().String(),
+			Image:        src.Image,
+			NetworkID:    src.NetworkID,
+			SensitiveEnv: slices.Clone(src.SensitiveEnv),
+			Secrets:      slices.Clone(src.Secrets),
+			ConfigFiles:  slices.Clone(src.ConfigFiles),
+			Alerts:       slices.Clone(src.Alerts),
+			Replicas:     src.Replicas,
+			Stopped:      src.Stopped,
+			Interactive:  src.Interactive,
+		}
+		ids[src.ID] = app.ID
+
+		// Copies get empty volumes of their own rather than sharing data
+		// with the source environment
+		for _, m := range src.Volumes {
+			if m.Name == "" {
+				// Bind mounts keep pointing at the same host path
+				app.Volumes = append(app.Volumes, m)
+				continue
+			}
+			if _, ok := volumeNames[m.Name]; !ok {
+				volumeNames[m.Name] = m.Name + req.NameSuffix
+				v := core.Volume{CreatedAt: now, Name: volumeNames[m.Name]}
+				if idx := slices.IndexFunc(volumes, func(v core.Volume) bool { return v.Name == m.Name }); idx >= 0 {
+					v.Size = volumes[idx].Size
+					v.Backup = volumes[idx].Backup
+				}
+				if slices.ContainsFunc(volumes, func(existing core.Volume) bool { return existing.Name == v.Name }) {
+					return nil, errors.NewAlreadyExistsError("volume", v.Name)
+				}
+				plan.volumes = append(plan.volumes, v)
+			}
+			m.Name = volumeNames[m.Name]
+			app.Volumes = append(app.Volumes, m)
+		}
+		plan.apps = append(plan.apps, app)
+	}
+
+	for i := range links {
+		from, to := ids[links[i].From], ids[links[i].To]
+		if from == "" || to == "" {
+			continue
+		}
+		plan.links = append(plan.links, core.Link{CreatedAt: now, From: from, To: to, Type: links[i].Type})
+	}
+
+	for i := range domains {
+		src := &domains[i]
+		appID, ok := ids[src.ApplicationID]
+		if !ok {
+			continue
+		}
+		hostname := cloneHostname(src.Hostname, req.HostnameSuffix)
+		if !domainPattern.MatchString(hostname) {
+			return nil, errors.NewInvalidInputErrorWithField("hostname_suffix",
+				fmt.Sprintf("domain %s would become %s, which is not a valid hostname", src.Hostname, hostname))
+		}
+		taken := func(d core.Domain) bool { return d.Hostname == hostname }
+		if slices.ContainsFunc(domains, taken) || slices.ContainsFunc(plan.domains, taken) {
+			return nil, errors.NewAlreadyExistsError("domain", hostname)
+		}
+		domain := core.Domain{Hostname: hostname, ApplicationID: appID, TLS: src.TLS, Port: src.Port}
+		if err := resetDomain(&domain); err != nil {
+			return nil, err
+		}
+		plan.domains = append(plan.domains, domain)
+	}
+	return plan, nil
+}
+
+// cloneHostname returns the hostname of a domain's copy. The suffix is
+// appended to the first label of subdomains, as in shop-staging.example.com.
+// Apex domains, such as example.com or example.co.uk, would become a
+// different registrable domain that way, so they get the suffix as a
+// subdomain instead, as in staging.example.co.uk.
+func cloneHostname(hostname, suffix string) string {
+	if apex, err := publicsuffix.EffectiveTLDPlusOne(hostname); err == nil && apex != hostname {
+		label, rest, _ := strings.Cut(hostname, ".")
+		return label + suffix + "." + rest
+	}
+	return strings.Trim(suffix, "-_.") + "." + hostname
+}
+
+// offsetPorts returns a copy of published ports with offset added to each
+// host port, keeping any host IP and protocol. An offset of 0 publishes
+// none, as the source's host ports are taken.
+func offsetPorts(ports map[string]string, offset int) (map[string]string, error) {
+	result := make(map[string]string, len(ports))
+	if offset == 0 {
+		return result, nil
+	}
+	for host, containerPort := range ports {
+		prefix, port := "", host
+		if idx := strings.LastIndex(port, ":"); idx >= 0 {
+			prefix, port = port[:idx+1], port[idx+1:]
+		}
+		port, proto, _ := strings.Cut(port, "/")
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host port %s", host)
+		}
+		if n+offset > maxPort {
+			return nil, fmt.Errorf("host port %d plus %d is above %d", n, offset, maxPort)
+		}
+		shifted := prefix + strconv.Itoa(n+offset)
+		if proto != "" {
+			shifted += "/" + proto
+		}
+		result[shifted] = containerPort
+	}
+	return result, nil
+}
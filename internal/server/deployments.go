@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// ActorHeader names who made a change, as recorded in deployment history.
+// The CLI sends user@host. Until requests are authenticated it is taken on
+// trust.
+const ActorHeader = client.ActorHeader
+
+// requestActor returns who made the request, "api" when the client did not say
+func requestActor(r *http.Request) string {
+	if actor := r.Header.Get(ActorHeader); actor != "" {
+		return actor
+	}
+	return "api"
+}
+
+// recordDeployment adds the current revision of app to its history and to
+// the activity feed. A failure is logged rather than returned, as the
+// change itself was stored.
+func (s *Server) recordDeployment(r *http.Request, app *core.Application, rollbackOf string) {
+	d := core.NewDeployment(app, requestActor(r))
+	d.RollbackOf = rollbackOf
+	if err := s.store.WithContext(r.Context()).RecordDeployment(d); err != nil {
+		log.ErrorCtx(r.Context(), "Failed to record deployment", "app", app.Name, "revision", d.Revision, "error", err)
+	}
+
+	action, message := core.ActionUpdated, "revision "+d.Revision+" with image "+d.Image
+	if app.CreatedAt.Equal(app.UpdatedAt) {
+		action = core.ActionCreated
+	}
+	if rollbackOf != "" {
+		message = "rolled back to revision " + rollbackOf + " with image " + d.Image
+	}
+	s.recordActivity(r, action, app, message)
+}
+
+// handleListDeployments returns the deployment history of an application,
+// newest first
+func (s *Server) handleListDeployments(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	if _, err := s.store.WithContext(r.Context()).GetApplication(id); err != nil {
+		return err
+	}
+
+	history, err := s.store.WithContext(r.Context()).ListDeployments(id)
+	if err != nil {
+		return err
+	}
+	for i := range history {
+		history[i].Redact()
+	}
+
+	return writeSuccess(w, history)
+}
+
+// handleRollbackApplication restores the spec recorded at a revision. The
+// restored spec gets a new revision, so the reconciler replaces the running
+// containers, and the rollback is itself recorded in the history.
+func (s *Server) handleRollbackApplication(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	revision := chi.URLParam(r, "revision")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+	if revision == "" {
+		return errors.NewInvalidInputErrorWithField("revision", "revision is required")
+	}
+
+	st := s.store.WithContext(r.Context())
+	app, err := st.GetApplication(id)
+	if err != nil {
+		return err
+	}
+	if app.Revision() == revision {
+		return errors.NewInvalidInputErrorWithField("revision", "application is already at revision "+revision)
+	}
+
+	d, err := st.GetDeployment(id, revision)
+	if err != nil {
+		return err
+	}
+
+	d.Restore(app)
+	app.UpdatedAt = time.Now().UTC()
+	if err := st.UpdateApplication(app); err != nil {
+		return err
+	}
+	s.recordDeployment(r, app, revision)
+
+	log.InfoCtx(r.Context(), "Application rolled back", "app", app.Name, "revision", revision, "image", app.Image)
+	app.Redact()
+	return writeSuccess(w, app)
+}
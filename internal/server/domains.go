@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// domainPattern matches fully qualified, lowercase host names with at least
+// two labels. Wildcards are not supported.
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// txtResolver looks up DNS TXT records. *net.Resolver implements it; tests
+// substitute a fake.
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// domainVerifyTimeout bounds the DNS lookup of a verification
+const domainVerifyTimeout = 10 * time.Second
+
+// handleCreateDomain registers a hostname for an application. The domain
+// starts pending until its TXT record is verified.
+func (s *Server) handleCreateDomain(w http.ResponseWriter, r *http.Request) error {
+	var domain core.Domain
+	if err := json.NewDecoder(r.Body).Decode(&domain); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	domain.Hostname = strings.TrimSuffix(strings.ToLower(domain.Hostname), ".")
+	if domain.Hostname == "" {
+		return errors.NewInvalidInputErrorWithField("hostname", "hostname is required")
+	}
+	if len(domain.Hostname) > 253 || !domainPattern.MatchString(domain.Hostname) {
+		return errors.NewInvalidInputErrorWithField("hostname", "hostname must be a fully qualified domain name such as app.example.com")
+	}
+	if domain.TLS == "" {
+		domain.TLS = core.TLSAuto
+	}
+	if err := s.validateDomainTarget(r, &domain); err != nil {
+		return err
+	}
+
+	if err := resetDomain(&domain); err != nil {
+		return err
+	}
+
+	if err := s.store.WithContext(r.Context()).CreateDomain(&domain); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionCreated, &domain, "")
+
+	return writeCreated(w, domain)
+}
+
+// resetDomain gives a new domain its ID and a fresh verification token, and
+// leaves it pending until the token is found in DNS
+func resetDomain(domain *core.Domain) error {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return errors.NewInternalErrorWithCause("failed to generate verification token", err)
+	}
+
+	domain.ID = uuid.New().String()
+	domain.CreatedAt = time.Now().UTC()
+	domain.Status = core.DomainPending
+	domain.StatusMessage = ""
+	domain.VerifiedAt = nil
+	domain.VerificationToken = "simplify-verify=" + hex.EncodeToString(token)
+	return nil
+}
+
+// handleListDomains returns all domains
+func (s *Server) handleListDomains(w http.ResponseWriter, r *http.Request) error {
+	domains, err := s.store.WithContext(r.Context()).ListDomains()
+	if err != nil {
+		return err
+	}
+	if err := s.setDomainsReady(r, domains); err != nil {
+		return err
+	}
+
+	return writeSuccess(w, domains)
+}
+
+// handleGetDomain returns a single domain by ID
+func (s *Server) handleGetDomain(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	domain, err := s.store.WithContext(r.Context()).GetDomain(id)
+	if err != nil {
+		return err
+	}
+	domains := []core.Domain{*domain}
+	if err := s.setDomainsReady(r, domains); err != nil {
+		return err
+	}
+
+	return writeSuccess(w, domains[0])
+}
+
+// handleUpdateDomain changes the target application, port and TLS mode of
+// a domain. The hostname cannot be changed, as it is what was verified.
+func (s *Server) handleUpdateDomain(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.Domain
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	st := s.store.WithContext(r.Context())
+	domain, err := st.GetDomain(id)
+	if err != nil {
+		return err
+	}
+
+	if req.ApplicationID != "" {
+		domain.ApplicationID = req.ApplicationID
+	}
+	if req.TLS != "" {
+		domain.TLS = req.TLS
+	}
+	domain.Port = req.Port
+	if err := s.validateDomainTarget(r, domain); err != nil {
+		return err
+	}
+
+	if err := st.UpdateDomain(domain); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionUpdated, domain, "")
+
+	return writeSuccess(w, domain)
+}
+
+// handleDeleteDomain removes a domain
+func (s *Server) handleDeleteDomain(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	// Read before deleting, to name it in the activity feed
+	domain, getErr := s.store.WithContext(r.Context()).GetDomain(id)
+	if err := s.store.WithContext(r.Context()).DeleteDomain(id); err != nil {
+		return err
+	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, domain, "")
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// handleVerifyDomain checks the domain's TXT record and records the result.
+// A failed check is not an API error: the domain is returned with status
+// failed and a message saying what was found.
+func (s *Server) handleVerifyDomain(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	st := s.store.WithContext(r.Context())
+	domain, err := st.GetDomain(id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), domainVerifyTimeout)
+	defer cancel()
+
+	record := domain.VerificationRecord()
+	values, err := s.resolver.LookupTXT(ctx, record)
+	switch {
+	case err != nil:
+		domain.Status = core.DomainFailed
+		domain.StatusMessage = fmt.Sprintf("TXT lookup of %s failed: %v", record, err)
+	case !slices.Contains(values, domain.VerificationToken):
+		domain.Status = core.DomainFailed
+		domain.StatusMessage = fmt.Sprintf("%s has no TXT record %q", record, domain.VerificationToken)
+	default:
+		now := time.Now().UTC()
+		domain.Status = core.DomainVerified
+		domain.StatusMessage = ""
+		domain.VerifiedAt = &now
+	}
+
+	if err := st.UpdateDomain(domain); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionUpdated, domain, "verification "+domain.Status)
+
+	log.InfoCtx(r.Context(), "Domain verification checked", "hostname", domain.Hostname, "status", domain.Status)
+	return writeSuccess(w, domain)
+}
+
+// setDomainsReady marks the domains that are verified and whose application
+// passes its health check, or runs without one. A route to an application
+// that is starting or unhealthy is not ready to receive traffic.
+func (s *Server) setDomainsReady(r *http.Request, domains []core.Domain) error {
+	if len(domains) == 0 {
+		return nil
+	}
+	st := s.store.WithContext(r.Context())
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+	nodes, err := st.ListNodes()
+	if err != nil {
+		return err
+	}
+	nodeMap := make(map[string]*core.Node, len(nodes))
+	for i := range nodes {
+		nodeMap[nodes[i].ID] = &nodes[i]
+	}
+	now := time.Now()
+	appMap := make(map[string]*core.Application, len(apps))
+	for i := range apps {
+		applyNodeStatus(&apps[i], nodeMap, now)
+		appMap[apps[i].ID] = &apps[i]
+	}
+
+	for i := range domains {
+		d := &domains[i]
+		app := appMap[d.ApplicationID]
+		d.Ready = d.Status == core.DomainVerified && app != nil && !app.Stopped &&
+			(app.HealthCheck == nil || app.HealthStatus == core.HealthHealthy)
+	}
+	return nil
+}
+
+// validateDomainTarget checks the TLS mode, port and target application
+func (s *Server) validateDomainTarget(r *http.Request, domain *core.Domain) error {
+	switch domain.TLS {
+	case core.TLSAuto, core.TLSInternal, core.TLSOff:
+	default:
+		return errors.NewInvalidInputErrorWithField("tls",
+			"tls must be one of "+core.TLSAuto+", "+core.TLSInternal+" or "+core.TLSOff)
+	}
+
+	if domain.ApplicationID == "" {
+		return errors.NewInvalidInputErrorWithField("application_id", "application_id is required")
+	}
+	if _, err := s.store.WithContext(r.Context()).GetApplication(domain.ApplicationID); err != nil {
+		return err
+	}
+
+	if domain.Port < 0 || domain.Port > 65535 {
+		return errors.NewInvalidInputErrorWithField("port", "port must be between 1 and 65535")
+	}
+	return nil
+}
+
+// deleteDomainsOf removes the domains routing to a deleted application
+func (s *Server) deleteDomainsOf(r *http.Request, appID string) {
+	st := s.store.WithContext(r.Context())
+	domains, err := st.ListDomains()
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to list domains", "app", appID, "error", err)
+		return
+	}
+	for i := range domains {
+		if domains[i].ApplicationID != appID {
+			continue
+		}
+		if err := st.DeleteDomain(domains[i].ID); err != nil {
+			log.WarnCtx(r.Context(), "Failed to delete domain", "hostname", domains[i].Hostname, "error", err)
+		}
+	}
+}
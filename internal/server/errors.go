@@ -5,7 +5,8 @@ import (
 	"net/http"
 
 	"github.com/AkMo3/simplify/internal/errors"
-	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/reporting"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 // ErrorResponse represents a structured error response
@@ -20,6 +21,10 @@ type ErrorDetail struct {
 	Resource string `json:"resource,omitempty"`
 	ID       string `json:"id,omitempty"`
 	Field    string `json:"field,omitempty"`
+	Stack    string `json:"stack,omitempty"` // development only
+
+	RequestID string `json:"request_id,omitempty"` // matches the request_id of the server's log entries
+	DocURL    string `json:"doc_url,omitempty"`    // documentation for Code, when there is any
 }
 
 // AppHandler is a handler function that returns an error
@@ -34,21 +39,28 @@ func WrapHandler(h AppHandler) http.HandlerFunc {
 			return
 		}
 
-		// Log the error with request context
-		logger.Error("Request failed",
-			"method", r.Method,
-			"path", r.URL.Path,
-			"error", err,
-		)
-
-		// Map error to HTTP response
-		statusCode, response := mapErrorToResponse(err)
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(statusCode)
+		// Log the error with request context, and its stack when one was captured
+		requestID := middleware.GetReqID(r.Context())
+		fields := []any{"method", r.Method, "path", r.URL.Path, "request_id", requestID, "error", err}
+		if stack := errors.StackTrace(err); stack != "" {
+			fields = append(fields, "stack", stack)
+		}
+		log.Error("Request failed", fields...)
+
+		// Map error to HTTP response, reporting engine timeouts and outages by kind
+		statusCode, response := mapErrorToResponse(errors.Classify(err))
+
+		// Internal errors are bugs or outages worth tracking, unlike client errors
+		if statusCode == http.StatusInternalServerError {
+			reporting.CaptureError(r.Context(), err,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", requestID,
+			)
+		}
 
-		if encodeErr := json.NewEncoder(w).Encode(response); encodeErr != nil {
-			logger.Error("Failed to encode error response", "error", encodeErr)
+		if encodeErr := writeError(w, r, statusCode, response.Error); encodeErr != nil {
+			log.Error("Failed to encode error response", "error", encodeErr)
 		}
 	}
 }
@@ -118,12 +130,49 @@ func mapErrorToResponse(err error) (int, ErrorResponse) {
 		return http.StatusForbidden, response
 	}
 
+	// Check for TimeoutError
+	if errors.IsTimeout(err) {
+		if base := errors.GetBaseError(err); base != nil {
+			response.Error = ErrorDetail{
+				Code:    base.Code,
+				Message: base.Message,
+			}
+		}
+		return http.StatusGatewayTimeout, response
+	}
+
+	// Check for UnavailableError
+	if errors.IsUnavailable(err) {
+		if base := errors.GetBaseError(err); base != nil {
+			response.Error = ErrorDetail{
+				Code:     base.Code,
+				Message:  base.Message,
+				Resource: base.Resource,
+			}
+		}
+		return http.StatusServiceUnavailable, response
+	}
+
+	// Check for ConflictStateError
+	if errors.IsConflictState(err) {
+		if base := errors.GetBaseError(err); base != nil {
+			response.Error = ErrorDetail{
+				Code:     base.Code,
+				Message:  base.Message,
+				Resource: base.Resource,
+				ID:       base.ID,
+			}
+		}
+		return http.StatusConflict, response
+	}
+
 	// Check for InternalError or unknown errors
 	if errors.IsInternal(err) {
 		if base := errors.GetBaseError(err); base != nil {
 			response.Error = ErrorDetail{
 				Code:    base.Code,
 				Message: base.Message,
+				Stack:   errors.StackTrace(err),
 			}
 		}
 		return http.StatusInternalServerError, response
@@ -141,6 +190,14 @@ func writeJSON(w http.ResponseWriter, statusCode int, data any) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
+// writeError writes an error body, tagged with the request ID and the
+// documentation link for its code
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, detail ErrorDetail) error {
+	detail.RequestID = middleware.GetReqID(r.Context())
+	detail.DocURL = errors.DocURL(detail.Code)
+	return writeJSON(w, statusCode, ErrorResponse{Error: detail})
+}
+
 // writeSuccess writes a successful JSON response with status 200
 func writeSuccess(w http.ResponseWriter, data any) error {
 	return writeJSON(w, http.StatusOK, data)
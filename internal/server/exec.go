@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// ExecRequest is the body of POST /applications/{id}/exec
+type ExecRequest = client.ExecRequest
+
+// ExecOutput is one line of the answer of POST /applications/{id}/exec
+type ExecOutput = client.ExecOutput
+
+// handleExec runs a command in one replica of an application and streams
+// its output as newline delimited JSON, ending with its exit code. The
+// command gets no input; use attach for interactive processes.
+func (s *Server) handleExec(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req ExecRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if len(req.Command) == 0 {
+		return errors.NewInvalidInputErrorWithField("command", "command is required")
+	}
+	if req.Replica < 0 {
+		return errors.NewInvalidInputErrorWithField("replica", "replica must be a non-negative number")
+	}
+	for _, kv := range req.Env {
+		if k, _, ok := strings.Cut(kv, "="); !ok || k == "" {
+			return errors.NewInvalidInputErrorWithField("env", "environment variables must be KEY=VALUE: "+kv)
+		}
+	}
+
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+	if !app.ScheduledOn("") {
+		return errors.NewUnavailableError("exec", "commands in applications run by agent nodes can only be run on the node")
+	}
+	name, err := s.replicaContainer(r, id, req.Replica)
+	if err != nil {
+		return err
+	}
+
+	// Commands may run longer than the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{}) //nolint:errcheck // unsupported writers keep the timeout
+
+	s.recordActivity(r, core.ActionExecuted, app, strings.Join(req.Command, " "))
+	out := &execOutput{w: w, enc: json.NewEncoder(w)}
+	code, err := s.container.Exec(r.Context(), name, container.ExecOptions{
+		Stdout:  &execStream{out: out, stream: "stdout"},
+		Stderr:  &execStream{out: out, stream: "stderr"},
+		Cmd:     req.Command,
+		Env:     req.Env,
+		User:    req.User,
+		WorkDir: req.WorkDir,
+	})
+	if err != nil && !out.started {
+		// Nothing is sent yet, so the client gets an error response
+		return err
+	}
+	if err != nil {
+		if r.Context().Err() == nil {
+			log.WarnCtx(r.Context(), "Exec failed", "app", app.Name, "error", err)
+			_ = out.send(ExecOutput{Error: err.Error()}) //nolint:errcheck // the client may already be gone
+		}
+		return nil
+	}
+
+	log.InfoCtx(r.Context(), "Command executed", "app", app.Name, "container", name, "exit_code", code)
+	_ = out.send(ExecOutput{ExitCode: &code}) //nolint:errcheck // the client may already be gone
+	return nil
+}
+
+// execOutput serializes the output streams of a command onto one response.
+// Headers are sent with the first message, so a command that fails to start
+// still gets an error response.
+type execOutput struct {
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	mu      sync.Mutex
+	started bool
+}
+
+// send writes msg as one line and flushes it to the client
+func (o *execOutput) send(msg ExecOutput) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.started {
+		o.w.Header().Set("Content-Type", "application/x-ndjson")
+		o.w.WriteHeader(http.StatusOK)
+		o.started = true
+	}
+	if err := o.enc.Encode(msg); err != nil {
+		return err
+	}
+	return http.NewResponseController(o.w).Flush()
+}
+
+// execStream writes one output stream of a command
+type execStream struct {
+	out    *execOutput
+	stream string
+}
+
+// Write sends p as one message of the stream
+func (s *execStream) Write(p []byte) (int, error) {
+	if err := s.out.send(ExecOutput{Stream: s.stream, Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,18 +2,32 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/core"
 	"github.com/AkMo3/simplify/internal/errors"
-	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/manifest"
+	"github.com/AkMo3/simplify/pkg/client"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
-const statusStopped = "stopped"
+const (
+	statusStopped  = "stopped"
+	statusUpdating = "updating"
+)
+
+// hostnamePattern matches a DNS label, as the hostname of a pod must be
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
 
 // =============================================================================
 // Application Handlers
@@ -26,10 +40,18 @@ func (s *Server) handleCreateApplication(w http.ResponseWriter, r *http.Request)
 		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
 	}
 
-	// Generate ID if not provided
+	// Generate ID if not provided. IDs name the application's directory of
+	// config files, so they must be a single path element.
 	if app.ID == "" {
 		app.ID = uuid.New().String()
 	}
+	if !engineNamePattern.MatchString(app.ID) {
+		return errors.NewInvalidInputErrorWithField("id", "id may only contain letters, digits, '.', '-' and '_'")
+	}
+	app.StackID = ""      // set by attaching the application to a stack
+	app.NodeID = ""       // set by the scheduler
+	app.HealthStatus = "" // set by the reconciler
+	app.Alerting = nil    // set by the metrics collector
 
 	// Set timestamps
 	now := time.Now().UTC()
@@ -43,48 +65,109 @@ func (s *Server) handleCreateApplication(w http.ResponseWriter, r *http.Request)
 	if app.Image == "" {
 		return errors.NewInvalidInputErrorWithField("image", "image is required")
 	}
+	if err := validateSecretRefs(app.Secrets); err != nil {
+		return err
+	}
+	if err := validateSensitiveEnv(app.SensitiveEnv); err != nil {
+		return err
+	}
+	if err := validateNetworkAliases(app.NetworkAliases); err != nil {
+		return err
+	}
+	if err := validateVolumeMounts(app.Volumes); err != nil {
+		return err
+	}
+	if err := validateConfigFiles(app.ConfigFiles, app.Volumes); err != nil {
+		return err
+	}
+	if err := validateHealthCheck(app.HealthCheck); err != nil {
+		return err
+	}
+	if err := validateAlerts(app.Alerts); err != nil {
+		return err
+	}
+	if err := s.checkEnvironmentLimits(r, &app); err != nil {
+		return err
+	}
+	if err := s.scheduleApplication(r, &app); err != nil {
+		return err
+	}
 
-	if err := s.store.CreateApplication(&app); err != nil {
+	if err := s.store.WithContext(r.Context()).CreateApplication(&app); err != nil {
 		return err
 	}
+	s.recordDeployment(r, &app, "")
 
+	app.Redact()
 	return writeCreated(w, app)
 }
 
 // handleListApplications returns all applications
 func (s *Server) handleListApplications(w http.ResponseWriter, r *http.Request) error {
-	apps, err := s.store.ListApplications()
+	apps, err := s.store.WithContext(r.Context()).ListApplications()
 	if err != nil {
 		return err
 	}
 
-	// Fetch from podman the status of containers
+	// Fetch from podman the status of containers. Without the engine, such
+	// as while it is not connected yet, apps keep their recorded status.
 	containers, err := s.container.List(r.Context(), true)
+	engineKnown := err == nil
+	if err != nil {
+		log.ErrorCtx(r.Context(), "Error listing containers from engine", "error", err)
+	}
+
+	// Applications scheduled to nodes report their status through heartbeats
+	nodes, err := s.store.WithContext(r.Context()).ListNodes()
 	if err != nil {
-		// Log error but return apps with unknown status is better than failing?
-		// For now, let's return error as before but we could improve this.
 		return err
 	}
+	nodeMap := make(map[string]*core.Node, len(nodes))
+	for i := range nodes {
+		nodeMap[nodes[i].ID] = &nodes[i]
+	}
+	now := time.Now()
 
-	// Create a map of AppID -> ContainerInfo
+	// Map AppID -> ContainerInfo of the first replica, and AppID -> every replica
 	containerMap := make(map[string]container.ContainerInfo)
+	instances := make(map[string][]core.Instance)
 	for i := range containers {
 		c := containers[i]
 		if appID, ok := c.Labels["simplify.app.id"]; ok {
-			containerMap[appID] = c
+			replica, _ := strconv.Atoi(c.Labels["simplify.app.replica"]) //nolint:errcheck // unlabeled containers are the first replica
+			if replica == 0 {
+				containerMap[appID] = c
+			}
+			instances[appID] = append(instances[appID], core.Instance{
+				Name:     c.Name,
+				Status:   c.Status,
+				Revision: c.Labels["simplify.app.revision"],
+				Replica:  replica,
+			})
 		}
 	}
 
 	for i := range apps {
+		apps[i].Instances = instances[apps[i].ID]
+		sort.Slice(apps[i].Instances, func(a, b int) bool {
+			return apps[i].Instances[a].Replica < apps[i].Instances[b].Replica
+		})
+
 		if info, ok := containerMap[apps[i].ID]; ok {
 			apps[i].Status = info.Status
+			if rev := info.Labels["simplify.app.revision"]; rev != "" && rev != apps[i].Revision() {
+				// Container still runs a previous spec until the reconciler replaces it
+				apps[i].Status = statusUpdating
+			}
 			apps[i].Ports = info.Ports
 			apps[i].IPAddress = info.IPAddress
 			apps[i].ExposedPorts = info.ExposedPorts
 			apps[i].ConnectedNetworks = info.Networks
-		} else {
-			apps[i].Status = statusStopped // Or "unknown" or empty
+		} else if engineKnown {
+			apps[i].Status = statusStopped
 		}
+		applyNodeStatus(&apps[i], nodeMap, now)
+		apps[i].Redact()
 	}
 
 	// Return empty array instead of null
@@ -102,7 +185,7 @@ func (s *Server) handleGetApplication(w http.ResponseWriter, r *http.Request) er
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	app, err := s.store.GetApplication(id)
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
 	if err != nil {
 		return err
 	}
@@ -111,7 +194,7 @@ func (s *Server) handleGetApplication(w http.ResponseWriter, r *http.Request) er
 	info, err := s.container.GetContainer(r.Context(), app.ID)
 	if err != nil {
 		// Log error but return DB state (likely stopped or previous state)
-		logger.ErrorCtx(r.Context(), "Error inspecting container", "id", app.ID, "error", err)
+		log.ErrorCtx(r.Context(), "Error inspecting container", "id", app.ID, "error", err)
 		if app.Status == "" {
 			app.Status = statusStopped
 		}
@@ -123,9 +206,45 @@ func (s *Server) handleGetApplication(w http.ResponseWriter, r *http.Request) er
 		app.ConnectedNetworks = info.Networks
 	}
 
+	nodes := make(map[string]*core.Node, 1)
+	if app.NodeID != "" {
+		if node, err := s.store.WithContext(r.Context()).GetNode(app.NodeID); err == nil {
+			nodes[node.ID] = node
+		}
+	}
+	applyNodeStatus(app, nodes, time.Now())
+
+	app.Redact()
 	return writeSuccess(w, app)
 }
 
+// handleExportApplication returns an application as a YAML manifest that
+// can be applied on another host, without IDs, runtime state or the values
+// of sensitive environment variables
+func (s *Server) handleExportApplication(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := manifest.ExportApplication(app)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", app.Name+".yaml"))
+	if _, err := w.Write(data); err != nil {
+		log.WarnCtx(r.Context(), "Failed to write export", "app", app.Name, "error", err)
+	}
+	return nil
+}
+
 // handleUpdateApplication updates an existing application
 func (s *Server) handleUpdateApplication(w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
@@ -142,6 +261,17 @@ func (s *Server) handleUpdateApplication(w http.ResponseWriter, r *http.Request)
 	app.ID = id
 	app.UpdatedAt = time.Now().UTC()
 
+	// Stack membership is managed through the stack, placement by the scheduler
+	existing, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+	app.StackID = existing.StackID
+	app.NodeID = existing.NodeID
+	app.HealthStatus = existing.HealthStatus
+	app.Alerting = existing.Alerting
+	app.KeepRedactedEnv(existing)
+
 	// Validate required fields
 	if app.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
@@ -149,11 +279,84 @@ func (s *Server) handleUpdateApplication(w http.ResponseWriter, r *http.Request)
 	if app.Image == "" {
 		return errors.NewInvalidInputErrorWithField("image", "image is required")
 	}
+	if err := validateSecretRefs(app.Secrets); err != nil {
+		return err
+	}
+	if err := validateSensitiveEnv(app.SensitiveEnv); err != nil {
+		return err
+	}
+	if err := validateNetworkAliases(app.NetworkAliases); err != nil {
+		return err
+	}
+	if err := validateVolumeMounts(app.Volumes); err != nil {
+		return err
+	}
+	if err := validateConfigFiles(app.ConfigFiles, app.Volumes); err != nil {
+		return err
+	}
+	if err := validateHealthCheck(app.HealthCheck); err != nil {
+		return err
+	}
+	if err := validateAlerts(app.Alerts); err != nil {
+		return err
+	}
+	if err := s.checkEnvironmentLimits(r, &app); err != nil {
+		return err
+	}
+	if err := s.scheduleApplication(r, &app); err != nil {
+		return err
+	}
+
+	if err := s.store.WithContext(r.Context()).UpdateApplication(&app); err != nil {
+		return err
+	}
+	s.recordDeployment(r, &app, "")
+
+	app.Redact()
+	return writeSuccess(w, app)
+}
+
+// scaleRequest is the body of a scale request
+type scaleRequest struct {
+	Replicas *int `json:"replicas"`
+}
+
+// handleScaleApplication sets the replica count of an application. The
+// revision is left unchanged so running replicas are kept, and the
+// reconciler only starts or removes the difference.
+func (s *Server) handleScaleApplication(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req scaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if req.Replicas == nil {
+		return errors.NewInvalidInputErrorWithField("replicas", "replicas is required")
+	}
+	if *req.Replicas < 1 {
+		return errors.NewInvalidInputErrorWithField("replicas", "replicas must be at least 1")
+	}
+
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+	if *req.Replicas > 1 && !app.Scalable() {
+		return errors.NewInvalidInputErrorWithField("replicas",
+			"application publishes host ports or runs in a pod, so it can only run one replica")
+	}
 
-	if err := s.store.UpdateApplication(&app); err != nil {
+	app.Replicas = *req.Replicas
+	if err := s.store.WithContext(r.Context()).UpdateApplication(app); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionDeployed, app, fmt.Sprintf("scaled to %d replicas", app.Replicas))
 
+	app.Redact()
 	return writeSuccess(w, app)
 }
 
@@ -164,9 +367,27 @@ func (s *Server) handleDeleteApplication(w http.ResponseWriter, r *http.Request)
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	if err := s.store.DeleteApplication(id); err != nil {
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.WithContext(r.Context()).DeleteApplication(id); err != nil {
 		return err
 	}
+	s.removeFromStack(r, app)
+	if err := s.store.WithContext(r.Context()).DeleteDeployments(id); err != nil {
+		log.WarnCtx(r.Context(), "Failed to delete deployment history", "app", id, "error", err)
+	}
+	s.deleteDomainsOf(r, id)
+	s.deleteLinksOf(r, id)
+	if err := s.store.WithContext(r.Context()).DeleteBuilds(id); err != nil {
+		log.WarnCtx(r.Context(), "Failed to delete build history", "app", id, "error", err)
+	}
+	if err := s.store.WithContext(r.Context()).DeleteMetrics(id); err != nil {
+		log.WarnCtx(r.Context(), "Failed to delete metrics", "app", id, "error", err)
+	}
+	s.recordActivity(r, core.ActionDeleted, app, "")
 
 	writeNoContent(w)
 	return nil
@@ -192,16 +413,17 @@ func (s *Server) handleCreateTeam(w http.ResponseWriter, r *http.Request) error
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
 
-	if err := s.store.CreateTeam(&team); err != nil {
+	if err := s.store.WithContext(r.Context()).CreateTeam(&team); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionCreated, &team, "")
 
 	return writeCreated(w, team)
 }
 
 // handleListTeams returns all teams
 func (s *Server) handleListTeams(w http.ResponseWriter, r *http.Request) error {
-	teams, err := s.store.ListTeams()
+	teams, err := s.store.WithContext(r.Context()).ListTeams()
 	if err != nil {
 		return err
 	}
@@ -220,7 +442,7 @@ func (s *Server) handleGetTeam(w http.ResponseWriter, r *http.Request) error {
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	team, err := s.store.GetTeam(id)
+	team, err := s.store.WithContext(r.Context()).GetTeam(id)
 	if err != nil {
 		return err
 	}
@@ -246,9 +468,10 @@ func (s *Server) handleUpdateTeam(w http.ResponseWriter, r *http.Request) error
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
 
-	if err := s.store.UpdateTeam(&team); err != nil {
+	if err := s.store.WithContext(r.Context()).UpdateTeam(&team); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionUpdated, &team, "")
 
 	return writeSuccess(w, team)
 }
@@ -260,9 +483,14 @@ func (s *Server) handleDeleteTeam(w http.ResponseWriter, r *http.Request) error
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	if err := s.store.DeleteTeam(id); err != nil {
+	// Read before deleting, to name it in the activity feed
+	team, getErr := s.store.WithContext(r.Context()).GetTeam(id)
+	if err := s.store.WithContext(r.Context()).DeleteTeam(id); err != nil {
 		return err
 	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, team, "")
+	}
 
 	writeNoContent(w)
 	return nil
@@ -287,17 +515,21 @@ func (s *Server) handleCreateProject(w http.ResponseWriter, r *http.Request) err
 	if project.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
+	if err := validateRepoURL(project.RepoURL); err != nil {
+		return err
+	}
 
-	if err := s.store.CreateProject(&project); err != nil {
+	if err := s.store.WithContext(r.Context()).CreateProject(&project); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionCreated, &project, "")
 
 	return writeCreated(w, project)
 }
 
 // handleListProjects returns all projects
 func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) error {
-	projects, err := s.store.ListProjects()
+	projects, err := s.store.WithContext(r.Context()).ListProjects()
 	if err != nil {
 		return err
 	}
@@ -316,7 +548,7 @@ func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) error
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	project, err := s.store.GetProject(id)
+	project, err := s.store.WithContext(r.Context()).GetProject(id)
 	if err != nil {
 		return err
 	}
@@ -341,10 +573,14 @@ func (s *Server) handleUpdateProject(w http.ResponseWriter, r *http.Request) err
 	if project.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
+	if err := validateRepoURL(project.RepoURL); err != nil {
+		return err
+	}
 
-	if err := s.store.UpdateProject(&project); err != nil {
+	if err := s.store.WithContext(r.Context()).UpdateProject(&project); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionUpdated, &project, "")
 
 	return writeSuccess(w, project)
 }
@@ -356,9 +592,14 @@ func (s *Server) handleDeleteProject(w http.ResponseWriter, r *http.Request) err
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	if err := s.store.DeleteProject(id); err != nil {
+	// Read before deleting, to name it in the activity feed
+	project, getErr := s.store.WithContext(r.Context()).GetProject(id)
+	if err := s.store.WithContext(r.Context()).DeleteProject(id); err != nil {
 		return err
 	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, project, "")
+	}
 
 	writeNoContent(w)
 	return nil
@@ -383,17 +624,21 @@ func (s *Server) handleCreateEnvironment(w http.ResponseWriter, r *http.Request)
 	if env.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
+	if err := validateEnvironmentLimits(env.Limits); err != nil {
+		return err
+	}
 
-	if err := s.store.CreateEnvironment(&env); err != nil {
+	if err := s.store.WithContext(r.Context()).CreateEnvironment(&env); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionCreated, &env, "")
 
 	return writeCreated(w, env)
 }
 
 // handleListEnvironments returns all environments
 func (s *Server) handleListEnvironments(w http.ResponseWriter, r *http.Request) error {
-	envs, err := s.store.ListEnvironments()
+	envs, err := s.store.WithContext(r.Context()).ListEnvironments()
 	if err != nil {
 		return err
 	}
@@ -412,7 +657,7 @@ func (s *Server) handleGetEnvironment(w http.ResponseWriter, r *http.Request) er
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	env, err := s.store.GetEnvironment(id)
+	env, err := s.store.WithContext(r.Context()).GetEnvironment(id)
 	if err != nil {
 		return err
 	}
@@ -437,10 +682,14 @@ func (s *Server) handleUpdateEnvironment(w http.ResponseWriter, r *http.Request)
 	if env.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
+	if err := validateEnvironmentLimits(env.Limits); err != nil {
+		return err
+	}
 
-	if err := s.store.UpdateEnvironment(&env); err != nil {
+	if err := s.store.WithContext(r.Context()).UpdateEnvironment(&env); err != nil {
 		return err
 	}
+	s.recordActivity(r, core.ActionUpdated, &env, "")
 
 	return writeSuccess(w, env)
 }
@@ -452,14 +701,182 @@ func (s *Server) handleDeleteEnvironment(w http.ResponseWriter, r *http.Request)
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	if err := s.store.DeleteEnvironment(id); err != nil {
+	// Read before deleting, to name it in the activity feed
+	env, getErr := s.store.WithContext(r.Context()).GetEnvironment(id)
+	if err := s.store.WithContext(r.Context()).DeleteEnvironment(id); err != nil {
 		return err
 	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, env, "")
+	}
 
 	writeNoContent(w)
 	return nil
 }
 
+// validateEnvironmentLimits checks that an environment's limits are not
+// negative and that its defaults fit its maximums
+func validateEnvironmentLimits(l *core.EnvironmentLimits) error {
+	if l == nil {
+		return nil
+	}
+	if err := validateResources("limits.default", &l.Default); err != nil {
+		return err
+	}
+	if err := validateResources("limits.max", &l.Max); err != nil {
+		return err
+	}
+	if l.MaxPorts < 0 {
+		return errors.NewInvalidInputErrorWithField("limits.max_ports", "max_ports must not be negative")
+	}
+	if l.Max.CPUs > 0 && l.Default.CPUs > l.Max.CPUs {
+		return errors.NewInvalidInputErrorWithField("limits.default.cpus", "default cpus must not exceed the maximum")
+	}
+	if l.Max.MemoryBytes > 0 && l.Default.MemoryBytes > l.Max.MemoryBytes {
+		return errors.NewInvalidInputErrorWithField("limits.default.memory_bytes", "default memory must not exceed the maximum")
+	}
+	return nil
+}
+
+// validateResources checks that resource limits are not negative
+func validateResources(field string, r *core.Resources) error {
+	if r.CPUs < 0 {
+		return errors.NewInvalidInputErrorWithField(field+".cpus", "cpus must not be negative")
+	}
+	if r.MemoryBytes < 0 {
+		return errors.NewInvalidInputErrorWithField(field+".memory_bytes", "memory must not be negative")
+	}
+	return nil
+}
+
+// validateSensitiveEnv checks the names of the environment variables
+// marked sensitive
+func validateSensitiveEnv(names []string) error {
+	for _, name := range names {
+		if !envNamePattern.MatchString(name) {
+			return errors.NewInvalidInputErrorWithField("sensitive_env",
+				fmt.Sprintf("invalid environment variable name %q", name))
+		}
+	}
+	return nil
+}
+
+// validateNetworkAliases checks that each network alias is a name the
+// engine accepts
+func validateNetworkAliases(aliases []string) error {
+	for _, alias := range aliases {
+		if !engineNamePattern.MatchString(alias) {
+			return errors.NewInvalidInputErrorWithField("network_aliases",
+				fmt.Sprintf("invalid network alias %q", alias))
+		}
+	}
+	return nil
+}
+
+// validateConfigFiles checks that each config file has a distinct clean
+// absolute path, not taken by a volume, and fits the size limit
+func validateConfigFiles(files []core.ConfigFile, volumes []core.VolumeMount) error {
+	paths := make(map[string]bool, len(files)+len(volumes))
+	for _, v := range volumes {
+		paths[v.Path] = true
+	}
+	for i := range files {
+		f := &files[i]
+		if !path.IsAbs(f.Path) || path.Clean(f.Path) != f.Path || f.Path == "/" {
+			return errors.NewInvalidInputErrorWithField("config_files",
+				fmt.Sprintf("config file %q: path must be a clean absolute file path", f.Path))
+		}
+		if paths[f.Path] {
+			return errors.NewInvalidInputErrorWithField("config_files",
+				fmt.Sprintf("config file %s: path is already mounted", f.Path))
+		}
+		paths[f.Path] = true
+		if len(f.Content) > core.MaxConfigFileSize {
+			return errors.NewInvalidInputErrorWithField("config_files",
+				fmt.Sprintf("config file %s: content exceeds %d bytes", f.Path, core.MaxConfigFileSize))
+		}
+		if f.Mode > 0o777 {
+			return errors.NewInvalidInputErrorWithField("config_files",
+				fmt.Sprintf("config file %s: mode must be permission bits, e.g. 0644", f.Path))
+		}
+	}
+	return nil
+}
+
+// validateHealthCheck checks that a health check has a known type and
+// what that type needs to run
+func validateHealthCheck(hc *core.HealthCheck) error {
+	if hc == nil {
+		return nil
+	}
+	switch hc.Type {
+	case core.HealthCheckHTTP, core.HealthCheckTCP:
+		if hc.Port < 1 || hc.Port > 65535 {
+			return errors.NewInvalidInputErrorWithField("health_check.port", "port must be between 1 and 65535")
+		}
+		if hc.Type == core.HealthCheckHTTP && hc.Path != "" && !strings.HasPrefix(hc.Path, "/") {
+			return errors.NewInvalidInputErrorWithField("health_check.path", "path must start with /")
+		}
+	case core.HealthCheckExec:
+		if len(hc.Command) == 0 {
+			return errors.NewInvalidInputErrorWithField("health_check.command", "command is required")
+		}
+	default:
+		return errors.NewInvalidInputErrorWithField("health_check.type", "type must be http, tcp or exec")
+	}
+	if hc.Interval < 0 || hc.Timeout < 0 || hc.Retries < 0 || hc.StartPeriod < 0 {
+		return errors.NewInvalidInputErrorWithField("health_check", "interval, timeout, retries and start_period must not be negative")
+	}
+	return nil
+}
+
+// validateAlerts checks an application's alert rules, at most one per metric
+func validateAlerts(rules []core.AlertRule) error {
+	seen := make(map[string]bool, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Metric != core.AlertCPU && rule.Metric != core.AlertMemory {
+			return errors.NewInvalidInputErrorWithField("alerts.metric", "metric must be cpu or memory")
+		}
+		if seen[rule.Metric] {
+			return errors.NewInvalidInputErrorWithField("alerts.metric", fmt.Sprintf("more than one %s alert", rule.Metric))
+		}
+		seen[rule.Metric] = true
+		if rule.Threshold <= 0 {
+			return errors.NewInvalidInputErrorWithField("alerts.threshold", "threshold must be positive")
+		}
+		if rule.Duration < 0 {
+			return errors.NewInvalidInputErrorWithField("alerts.duration", "duration must not be negative")
+		}
+	}
+	return nil
+}
+
+// checkEnvironmentLimits fails when an application asks for more than its
+// environment allows. Applications of unknown environments are not checked.
+func (s *Server) checkEnvironmentLimits(r *http.Request, app *core.Application) error {
+	if app.Resources != nil {
+		if err := validateResources("resources", app.Resources); err != nil {
+			return err
+		}
+	}
+	if app.EnvironmentID == "" {
+		return nil
+	}
+
+	env, err := s.store.WithContext(r.Context()).GetEnvironment(app.EnvironmentID)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := env.CheckLimits(app); err != nil {
+		return errors.NewInvalidInputErrorWithField("resources", err.Error())
+	}
+	return nil
+}
+
 // =============================================================================
 // Pod Handlers
 // =============================================================================
@@ -479,8 +896,11 @@ func (s *Server) handleCreatePod(w http.ResponseWriter, r *http.Request) error {
 	if pod.Name == "" {
 		return errors.NewInvalidInputErrorWithField("name", "name is required")
 	}
+	if err := s.validatePod(r, &pod); err != nil {
+		return err
+	}
 
-	if err := s.store.CreatePod(&pod); err != nil {
+	if err := s.store.WithContext(r.Context()).CreatePod(&pod); err != nil {
 		return err
 	}
 
@@ -489,7 +909,7 @@ func (s *Server) handleCreatePod(w http.ResponseWriter, r *http.Request) error {
 
 // handleListPods returns all pods
 func (s *Server) handleListPods(w http.ResponseWriter, r *http.Request) error {
-	pods, err := s.store.ListPods()
+	pods, err := s.store.WithContext(r.Context()).ListPods()
 	if err != nil {
 		return err
 	}
@@ -502,7 +922,7 @@ func (s *Server) handleListPods(w http.ResponseWriter, r *http.Request) error {
 	podInfos, err := s.container.ListPods(r.Context())
 	if err != nil {
 		// Log error but continue with DB data
-		logger.ErrorCtx(r.Context(), "Error listing pods from engine", "error", err)
+		log.ErrorCtx(r.Context(), "Error listing pods from engine", "error", err)
 	} else {
 		// Map by Name since DB ID != Podman ID
 		statusMap := make(map[string]string)
@@ -530,7 +950,7 @@ func (s *Server) handleGetPod(w http.ResponseWriter, r *http.Request) error {
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	pod, err := s.store.GetPod(id)
+	pod, err := s.store.WithContext(r.Context()).GetPod(id)
 	if err != nil {
 		return err
 	}
@@ -539,7 +959,7 @@ func (s *Server) handleGetPod(w http.ResponseWriter, r *http.Request) error {
 	info, err := s.container.InspectPod(r.Context(), pod.Name)
 	if err != nil {
 		// Log error but return DB state (likely stopped or previous state)
-		logger.ErrorCtx(r.Context(), "Error inspecting pod", "name", pod.Name, "error", err)
+		log.ErrorCtx(r.Context(), "Error inspecting pod", "name", pod.Name, "error", err)
 		if pod.Status == "" {
 			pod.Status = statusStopped
 		}
@@ -550,6 +970,69 @@ func (s *Server) handleGetPod(w http.ResponseWriter, r *http.Request) error {
 	return writeSuccess(w, pod)
 }
 
+// handleUpdatePod updates a pod's ports and spec. The reconciler recreates
+// the pod and its member containers when either changes.
+func (s *Server) handleUpdatePod(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var pod core.Pod
+	if err := json.NewDecoder(r.Body).Decode(&pod); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	existing, err := s.store.WithContext(r.Context()).GetPod(id)
+	if err != nil {
+		return err
+	}
+
+	// Pods are matched to engine pods by name, so renaming would orphan the running pod
+	if pod.Name != "" && pod.Name != existing.Name {
+		return errors.NewInvalidInputErrorWithField("name", "pod name cannot be changed")
+	}
+
+	existing.Ports = pod.Ports
+	existing.Resources = pod.Resources
+	existing.InfraImage = pod.InfraImage
+	existing.Hostname = pod.Hostname
+	existing.NetworkID = pod.NetworkID
+	existing.Volumes = pod.Volumes
+	if err := s.validatePod(r, existing); err != nil {
+		return err
+	}
+	if err := s.store.WithContext(r.Context()).UpdatePod(existing); err != nil {
+		return err
+	}
+
+	return writeSuccess(w, existing)
+}
+
+// validatePod checks the spec of a pod and that the network it joins exists
+func (s *Server) validatePod(r *http.Request, pod *core.Pod) error {
+	if pod.Hostname != "" && !hostnamePattern.MatchString(pod.Hostname) {
+		return errors.NewInvalidInputErrorWithField("hostname", "hostname must be a valid DNS label")
+	}
+	if err := validateVolumeMounts(pod.Volumes); err != nil {
+		return err
+	}
+	if pod.Resources != nil {
+		if err := validateResources("resources", pod.Resources); err != nil {
+			return err
+		}
+	}
+	if pod.NetworkID != "" {
+		if _, err := s.store.WithContext(r.Context()).GetNetwork(pod.NetworkID); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewInvalidInputErrorWithField("network_id", fmt.Sprintf("network %s does not exist", pod.NetworkID))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // handleDeletePod removes a pod
 func (s *Server) handleDeletePod(w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
@@ -557,7 +1040,21 @@ func (s *Server) handleDeletePod(w http.ResponseWriter, r *http.Request) error {
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	if err := s.store.DeletePod(id); err != nil {
+	st := s.store.WithContext(r.Context())
+
+	// Removing a pod from under its applications would leave them undeployable
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		if apps[i].PodID == id {
+			return errors.NewConflictStateError("pod", id,
+				fmt.Sprintf("pod is still used by application %s; move or delete it first", apps[i].Name))
+		}
+	}
+
+	if err := st.DeletePod(id); err != nil {
 		return err
 	}
 
@@ -586,7 +1083,7 @@ func (s *Server) handleCreateNetwork(w http.ResponseWriter, r *http.Request) err
 	}
 
 	// Create in DB
-	if err := s.store.CreateNetwork(&network); err != nil {
+	if err := s.store.WithContext(r.Context()).CreateNetwork(&network); err != nil {
 		return err
 	}
 
@@ -595,14 +1092,14 @@ func (s *Server) handleCreateNetwork(w http.ResponseWriter, r *http.Request) err
 	if err != nil {
 		return errors.NewInternalErrorWithCause("failed to create network in backend", err)
 	}
-	logger.InfoCtx(r.Context(), "Network created in engine", "name", network.Name, "id", id)
+	log.InfoCtx(r.Context(), "Network created in engine", "name", network.Name, "id", id)
 
 	return writeCreated(w, network)
 }
 
 // handleListNetworks returns all networks
 func (s *Server) handleListNetworks(w http.ResponseWriter, r *http.Request) error {
-	networks, err := s.store.ListNetworks()
+	networks, err := s.store.WithContext(r.Context()).ListNetworks()
 	if err != nil {
 		return err
 	}
@@ -614,7 +1111,7 @@ func (s *Server) handleListNetworks(w http.ResponseWriter, r *http.Request) erro
 	// Fetch runtime info
 	netInfos, err := s.container.ListNetworks(r.Context())
 	if err != nil {
-		logger.ErrorCtx(r.Context(), "Error listing networks from engine", "error", err)
+		log.ErrorCtx(r.Context(), "Error listing networks from engine", "error", err)
 	} else {
 		// Map by Name
 		infoMap := make(map[string]container.NetworkInfo)
@@ -640,20 +1137,80 @@ func (s *Server) handleDeleteNetwork(w http.ResponseWriter, r *http.Request) err
 		return errors.NewInvalidInputErrorWithField("id", "id is required")
 	}
 
-	network, err := s.store.GetNetwork(id)
+	network, err := s.store.WithContext(r.Context()).GetNetwork(id)
 	if err != nil {
 		return err // NotFound or other
 	}
 
 	// Remove from engine first
 	if err := s.container.RemoveNetwork(r.Context(), network.Name); err != nil {
-		logger.WarnCtx(r.Context(), "Failed to remove network from engine", "name", network.Name, "error", err)
+		log.WarnCtx(r.Context(), "Failed to remove network from engine", "name", network.Name, "error", err)
 	}
 
-	if err := s.store.DeleteNetwork(id); err != nil {
+	if err := s.store.WithContext(r.Context()).DeleteNetwork(id); err != nil {
 		return err
 	}
 
 	writeNoContent(w)
 	return nil
 }
+
+// =============================================================================
+// Backup Handlers
+// =============================================================================
+
+// maxRestoreBodySize bounds the size of an uploaded snapshot
+const maxRestoreBodySize = 64 << 20
+
+// BackupRestoreRequest carries a snapshot produced by the backup endpoint
+type BackupRestoreRequest = client.BackupRestoreRequest
+
+// handleBackup streams a consistent snapshot of the database
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) error {
+	name := fmt.Sprintf("simplify-%s.db", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+
+	n, err := s.store.WithContext(r.Context()).Backup(w)
+	if err != nil {
+		// Headers are already sent, so the client sees a truncated body
+		log.ErrorCtx(r.Context(), "Backup failed", "written", n, "error", err)
+		return nil
+	}
+
+	log.InfoCtx(r.Context(), "Backup written", "bytes", n)
+	return nil
+}
+
+// handleRestore verifies an uploaded snapshot and replaces the database contents with it
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) error {
+	var req BackupRestoreRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRestoreBodySize)).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if len(req.Snapshot) == 0 {
+		return errors.NewInvalidInputErrorWithField("snapshot", "snapshot is required")
+	}
+
+	tmp, err := os.CreateTemp("", "simplify-restore-*.db")
+	if err != nil {
+		return errors.NewInternalErrorWithCause("failed to stage snapshot", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(req.Snapshot); err != nil {
+		tmp.Close()
+		return errors.NewInternalErrorWithCause("failed to stage snapshot", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.NewInternalErrorWithCause("failed to stage snapshot", err)
+	}
+
+	summary, err := s.store.WithContext(r.Context()).Restore(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	log.InfoCtx(r.Context(), "Backup restored", "records", summary.Records)
+	return writeSuccess(w, summary)
+}
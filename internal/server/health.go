@@ -5,21 +5,15 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/pkg/client"
 	"go.uber.org/zap"
 )
 
 // HealthStatus represents the health check response
-type HealthStatus struct {
-	Checks map[string]ComponentHealth `json:"checks,omitempty"`
-	Status string                     `json:"status"`
-}
+type HealthStatus = client.HealthStatus
 
 // ComponentHealth represents the health of a single component
-type ComponentHealth struct {
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
-}
+type ComponentHealth = client.ComponentHealth
 
 const (
 	statusHealthy   = "healthy"
@@ -34,7 +28,7 @@ func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	}
 	err := writeJSON(w, http.StatusOK, status)
 	if err != nil {
-		logger.Error("failed to write json", zap.Error(err))
+		log.Error("failed to write json", zap.Error(err))
 	}
 }
 
@@ -71,7 +65,7 @@ func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
 
 	err := writeJSON(w, httpStatus, status)
 	if err != nil {
-		logger.Error("failed to write json", zap.Error(err))
+		log.Error("failed to write json", zap.Error(err))
 	}
 }
 
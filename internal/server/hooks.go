@@ -0,0 +1,304 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// maxHookBody is the largest webhook payload accepted
+const maxHookBody = 10 << 20
+
+// HookResult reports what a push webhook changed
+type HookResult struct {
+	Ignored string   `json:"ignored,omitempty"` // why the event caused no change
+	Builds  []string `json:"builds,omitempty"`  // IDs of the builds started
+	Updated []string `json:"updated,omitempty"` // applications whose image tag was bumped
+	Skipped []string `json:"skipped,omitempty"` // applications that matched but could not be redeployed, with the reason
+}
+
+// pushEvent is the part of a provider's push webhook used to redeploy
+type pushEvent struct {
+	Ref   string   // refs/heads/BRANCH or refs/tags/TAG
+	Repos []string // URLs of the repository, any of which may be a project's RepoURL
+	Push  bool     // false for other events, which are acknowledged only
+}
+
+// githubPush is the subset of GitHub's push event payload that is used
+type githubPush struct {
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+	Ref     string `json:"ref"`
+	Deleted bool   `json:"deleted"`
+}
+
+// gitlabPush is the subset of GitLab's push and tag push event payloads
+// that is used
+type gitlabPush struct {
+	Project struct {
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		WebURL     string `json:"web_url"`
+	} `json:"project"`
+	Ref         string `json:"ref"`
+	CheckoutSHA string `json:"checkout_sha"` // empty when the ref was deleted
+}
+
+// handleGitHubHook receives GitHub push webhooks, signed with the project's
+// webhook secret in X-Hub-Signature-256
+func (s *Server) handleGitHubHook(w http.ResponseWriter, r *http.Request) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookBody))
+	if err != nil {
+		return errors.NewInvalidInputErrorWithCause("failed to read webhook body", err)
+	}
+
+	var payload githubPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid webhook payload", err)
+	}
+	event := pushEvent{
+		Ref:   payload.Ref,
+		Repos: []string{payload.Repository.CloneURL, payload.Repository.SSHURL, payload.Repository.HTMLURL},
+		Push:  r.Header.Get("X-GitHub-Event") == "push" && !payload.Deleted,
+	}
+
+	signature := r.Header.Get("X-Hub-Signature-256")
+	verify := func(secret string) bool {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(signature), []byte(want))
+	}
+
+	r.Header.Set(ActorHeader, "github")
+	return s.handlePush(w, r, &event, verify)
+}
+
+// handleGitLabHook receives GitLab push and tag push webhooks, carrying the
+// project's webhook secret in X-Gitlab-Token
+func (s *Server) handleGitLabHook(w http.ResponseWriter, r *http.Request) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookBody))
+	if err != nil {
+		return errors.NewInvalidInputErrorWithCause("failed to read webhook body", err)
+	}
+
+	var payload gitlabPush
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid webhook payload", err)
+	}
+	kind := r.Header.Get("X-Gitlab-Event")
+	event := pushEvent{
+		Ref:   payload.Ref,
+		Repos: []string{payload.Project.GitHTTPURL, payload.Project.GitSSHURL, payload.Project.WebURL},
+		Push:  (kind == "Push Hook" || kind == "Tag Push Hook") && payload.CheckoutSHA != "",
+	}
+
+	token := r.Header.Get("X-Gitlab-Token")
+	verify := func(secret string) bool {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+
+	r.Header.Set(ActorHeader, "gitlab")
+	return s.handlePush(w, r, &event, verify)
+}
+
+// handlePush authenticates a push event against the projects using its
+// repository and redeploys their applications: a push to an application's
+// auto-deploy branch starts a build, and a pushed tag becomes the tag of
+// the images of applications that follow tags
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request, event *pushEvent, verify func(secret string) bool) error {
+	projects, err := s.pushProjects(r, event.Repos, verify)
+	if err != nil {
+		return err
+	}
+
+	result := HookResult{}
+	branch, isBranch := strings.CutPrefix(event.Ref, "refs/heads/")
+	tag, isTag := strings.CutPrefix(event.Ref, "refs/tags/")
+	switch {
+	case !event.Push:
+		result.Ignored = "not a push event"
+		return writeSuccess(w, result)
+	case !isBranch && !isTag:
+		result.Ignored = "unsupported ref " + event.Ref
+		return writeSuccess(w, result)
+	}
+
+	apps, err := s.projectApplications(r, projects)
+	if err != nil {
+		return err
+	}
+
+	for i := range apps {
+		app := &apps[i]
+		if app.AutoDeploy == nil {
+			continue
+		}
+		switch {
+		case isBranch && app.AutoDeploy.Branch == branch:
+			b, err := s.startBuild(r, app, s.lastBuildRequest(r, app.ID, branch))
+			if err != nil {
+				result.Skipped = append(result.Skipped, app.Name+": "+err.Error())
+				continue
+			}
+			result.Builds = append(result.Builds, b.ID)
+		case isTag && app.AutoDeploy.Tags:
+			image := withTag(app.Image, tag)
+			if image == app.Image {
+				continue
+			}
+			app.Image = image
+			app.UpdatedAt = time.Now().UTC()
+			if err := s.store.WithContext(r.Context()).UpdateApplication(app); err != nil {
+				result.Skipped = append(result.Skipped, app.Name+": "+err.Error())
+				continue
+			}
+			s.recordDeployment(r, app, "")
+			result.Updated = append(result.Updated, app.Name)
+		}
+	}
+	if len(result.Updated) > 0 {
+		s.triggerReconcile()
+	}
+	if len(result.Builds) == 0 && len(result.Updated) == 0 && len(result.Skipped) == 0 {
+		result.Ignored = "no application deploys " + event.Ref
+	}
+
+	log.InfoCtx(r.Context(), "Push webhook handled", "ref", event.Ref,
+		"builds", len(result.Builds), "updated", len(result.Updated), "skipped", len(result.Skipped))
+	return writeSuccess(w, result)
+}
+
+// pushProjects returns the projects whose repository is one of repos and
+// whose webhook secret verifies the request
+func (s *Server) pushProjects(r *http.Request, repos []string, verify func(secret string) bool) ([]core.Project, error) {
+	st := s.store.WithContext(r.Context())
+	projects, err := st.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if repo != "" {
+			wanted = append(wanted, normalizeRepoURL(repo))
+		}
+	}
+
+	var matched, verified []core.Project
+	for i := range projects {
+		p := &projects[i]
+		if p.RepoURL == "" || !slices.Contains(wanted, normalizeRepoURL(p.RepoURL)) {
+			continue
+		}
+		matched = append(matched, *p)
+		if p.WebhookSecret == "" {
+			continue
+		}
+		secret, err := st.SecretValue(p.WebhookSecret)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Failed to read webhook secret", "project", p.Name, "secret", p.WebhookSecret, "error", err)
+			continue
+		}
+		if verify(secret) {
+			verified = append(verified, *p)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil, errors.NewNotFoundError("project", strings.Join(wanted, ", "))
+	}
+	if len(verified) == 0 {
+		return nil, errors.NewPermissionError("webhook signature does not match the webhook secret of any project using this repository")
+	}
+	return verified, nil
+}
+
+// projectApplications returns the applications in the environments of the
+// given projects
+func (s *Server) projectApplications(r *http.Request, projects []core.Project) ([]core.Application, error) {
+	st := s.store.WithContext(r.Context())
+	envs, err := st.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	apps, err := st.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	inProjects := make(map[string]bool)
+	for i := range envs {
+		if slices.ContainsFunc(projects, func(p core.Project) bool { return p.ID == envs[i].ProjectID }) {
+			inProjects[envs[i].ID] = true
+		}
+	}
+
+	var result []core.Application
+	for i := range apps {
+		if apps[i].EnvironmentID != "" && inProjects[apps[i].EnvironmentID] {
+			result = append(result, apps[i])
+		}
+	}
+	return result, nil
+}
+
+// lastBuildRequest builds ref the way the application's latest build was
+// built, so pushes keep the strategy and paths chosen for a manual build
+func (s *Server) lastBuildRequest(r *http.Request, appID, ref string) BuildRequest {
+	req := BuildRequest{Ref: ref}
+	builds, err := s.store.WithContext(r.Context()).ListBuilds(appID)
+	if err != nil || len(builds) == 0 {
+		return req
+	}
+	req.Strategy = builds[0].Strategy
+	req.Containerfile = builds[0].Containerfile
+	req.Context = builds[0].Context
+	return req
+}
+
+// normalizeRepoURL reduces the HTTPS, SSH and scp-like forms of a
+// repository URL to host/path, so that any of them matches the others
+func normalizeRepoURL(raw string) string {
+	u := strings.ToLower(strings.TrimSpace(raw))
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+
+	if _, rest, ok := strings.Cut(u, "://"); ok {
+		u = rest
+	} else if colon := strings.IndexByte(u, ':'); colon >= 0 && !strings.Contains(u[:colon], "/") {
+		// git@host:owner/repo
+		u = u[:colon] + "/" + u[colon+1:]
+	}
+
+	host, path, _ := strings.Cut(u, "/")
+	if _, h, ok := strings.Cut(host, "@"); ok {
+		host = h
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	return host + "/" + path
+}
+
+// withTag replaces the tag or digest of an image reference
+func withTag(image, tag string) string {
+	name, _, _ := strings.Cut(image, "@")
+	// A colon after the last slash starts the tag; one before it is a registry port
+	if colon := strings.LastIndexByte(name, ':'); colon > strings.LastIndexByte(name, '/') {
+		name = name[:colon]
+	}
+	return name + ":" + tag
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// handleCreateLink links two applications. A depends_on link orders their
+// deployment and may not close a cycle.
+func (s *Server) handleCreateLink(w http.ResponseWriter, r *http.Request) error {
+	var link core.Link
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	switch link.Type {
+	case core.LinkDependsOn, core.LinkConnectsTo:
+	case "":
+		return errors.NewInvalidInputErrorWithField("type", "type is required")
+	default:
+		return errors.NewInvalidInputErrorWithField("type",
+			"type must be "+core.LinkDependsOn+" or "+core.LinkConnectsTo)
+	}
+	if link.From == "" {
+		return errors.NewInvalidInputErrorWithField("from", "from is required")
+	}
+	if link.To == "" {
+		return errors.NewInvalidInputErrorWithField("to", "to is required")
+	}
+	if link.From == link.To {
+		return errors.NewInvalidInputErrorWithField("to", "an application cannot link to itself")
+	}
+
+	st := s.store.WithContext(r.Context())
+	for _, id := range []string{link.From, link.To} {
+		if _, err := st.GetApplication(id); err != nil {
+			return err
+		}
+	}
+
+	link.ID = uuid.New().String()
+	link.CreatedAt = time.Now().UTC()
+	if err := st.CreateLink(&link); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionCreated, &link, s.applicationName(r, link.From)+" "+link.Type+" "+s.applicationName(r, link.To))
+
+	log.InfoCtx(r.Context(), "Applications linked", "from", link.From, "to", link.To, "type", link.Type)
+	return writeCreated(w, link)
+}
+
+// handleListLinks returns all links, or with ?application= those from and
+// to one application
+func (s *Server) handleListLinks(w http.ResponseWriter, r *http.Request) error {
+	links, err := s.store.WithContext(r.Context()).ListLinks()
+	if err != nil {
+		return err
+	}
+
+	filtered := []core.Link{}
+	appID := r.URL.Query().Get("application")
+	for i := range links {
+		if appID == "" || links[i].From == appID || links[i].To == appID {
+			filtered = append(filtered, links[i])
+		}
+	}
+	return writeSuccess(w, filtered)
+}
+
+// handleDeleteLink removes a link
+func (s *Server) handleDeleteLink(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	// Read before deleting, to describe it in the activity feed
+	link, getErr := s.store.WithContext(r.Context()).GetLink(id)
+	if err := s.store.WithContext(r.Context()).DeleteLink(id); err != nil {
+		return err
+	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, link, s.applicationName(r, link.From)+" "+link.Type+" "+s.applicationName(r, link.To))
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// handleGetGraph returns the service map: every application, in dependency
+// order, and the links between them
+func (s *Server) handleGetGraph(w http.ResponseWriter, r *http.Request) error {
+	st := s.store.WithContext(r.Context())
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+	links, err := st.ListLinks()
+	if err != nil {
+		return err
+	}
+	nodes, err := st.ListNodes()
+	if err != nil {
+		return err
+	}
+	nodeMap := make(map[string]*core.Node, len(nodes))
+	for i := range nodes {
+		nodeMap[nodes[i].ID] = &nodes[i]
+	}
+
+	ids := make([]string, len(apps))
+	appMap := make(map[string]*core.Application, len(apps))
+	for i := range apps {
+		ids[i] = apps[i].ID
+		appMap[apps[i].ID] = &apps[i]
+	}
+	ids, err = core.OrderByDependencies(ids, links)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	graph := core.Graph{Nodes: make([]core.GraphNode, 0, len(ids)), Links: links}
+	if graph.Links == nil {
+		graph.Links = []core.Link{}
+	}
+	for _, id := range ids {
+		app := appMap[id]
+		applyNodeStatus(app, nodeMap, now)
+		graph.Nodes = append(graph.Nodes, core.GraphNode{
+			ID:            app.ID,
+			Name:          app.Name,
+			Image:         app.Image,
+			StackID:       app.StackID,
+			EnvironmentID: app.EnvironmentID,
+			HealthStatus:  app.HealthStatus,
+			Stopped:       app.Stopped,
+		})
+	}
+	return writeSuccess(w, graph)
+}
+
+// deleteLinksOf removes the links of a deleted application. Failures are
+// logged: the application is already gone.
+func (s *Server) deleteLinksOf(r *http.Request, appID string) {
+	if err := s.store.WithContext(r.Context()).DeleteLinksOf(appID); err != nil {
+		log.WarnCtx(r.Context(), "Failed to delete links", "app", appID, "error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/pkg/client"
+)
+
+// logLevel is the body of the log level endpoints
+type logLevel = client.LogLevel
+
+// handleGetLogLevel returns the current log level of the server
+func (s *Server) handleGetLogLevel(w http.ResponseWriter, r *http.Request) error {
+	return writeSuccess(w, logLevel{Level: logger.Level()})
+}
+
+// handleSetLogLevel changes the log level of the server without a restart.
+// The change lasts until the next restart or config reload.
+func (s *Server) handleSetLogLevel(w http.ResponseWriter, r *http.Request) error {
+	var req logLevel
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if req.Level == "" {
+		return errors.NewInvalidInputErrorWithField("level", "level is required")
+	}
+
+	previous := logger.Level()
+	if err := logger.SetLevel(req.Level); err != nil {
+		return errors.NewInvalidInputErrorWithField("level", err.Error())
+	}
+
+	log.InfoCtx(r.Context(), "Log level changed", "from", previous, "to", logger.Level())
+	return writeSuccess(w, logLevel{Level: logger.Level()})
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// handleStreamLogs streams the output of one replica of an application as
+// newline delimited JSON log lines, or as server-sent events when the client
+// accepts text/event-stream, as browsers' EventSource does. With follow=true
+// the response stays open until the client goes away; timestamps=true adds
+// the time of each line.
+func (s *Server) handleStreamLogs(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	replica, err := replicaParam(r)
+	if err != nil {
+		return err
+	}
+	q := r.URL.Query()
+	opts := container.LogOptions{Since: q.Get("since")}
+	if v := q.Get("tail"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 0 {
+			return errors.NewInvalidInputErrorWithField("tail", "tail must be a non-negative number")
+		}
+		opts.Tail = v
+	}
+	if v := q.Get("follow"); v != "" {
+		follow, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.NewInvalidInputErrorWithField("follow", "follow must be true or false")
+		}
+		opts.Follow = follow
+	}
+	if v := q.Get("timestamps"); v != "" {
+		timestamps, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.NewInvalidInputErrorWithField("timestamps", "timestamps must be true or false")
+		}
+		opts.Timestamps = timestamps
+	}
+
+	app, err := s.store.WithContext(r.Context()).GetApplication(id)
+	if err != nil {
+		return err
+	}
+	if !app.ScheduledOn("") {
+		return errors.NewUnavailableError("logs", "logs of applications run by agent nodes are only available on the node")
+	}
+	name, err := s.replicaContainer(r, id, replica)
+	if err != nil {
+		return err
+	}
+
+	if opts.Follow {
+		// A followed stream outlives the server's write timeout
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{}) //nolint:errcheck // unsupported writers keep the timeout
+	}
+
+	out := &logStream{w: w, events: strings.Contains(r.Header.Get("Accept"), "text/event-stream")}
+	err = s.container.StreamLogs(r.Context(), name, opts, func(line container.LogLine) error {
+		return out.send(client.LogLine(line))
+	})
+	if !out.started {
+		if err != nil {
+			return err
+		}
+		out.start()
+	}
+	if err != nil && r.Context().Err() != nil {
+		return nil
+	}
+	if err != nil {
+		// Headers are already sent, so the client sees the stream end early
+		log.WarnCtx(r.Context(), "Log stream failed", "app", app.Name, "error", err)
+	}
+	out.end(err)
+	return nil
+}
+
+// logStream writes log lines as newline delimited JSON, or as server-sent
+// events. Headers are sent with the first line, so a stream that fails to
+// start still gets an error response.
+type logStream struct {
+	w       http.ResponseWriter
+	events  bool
+	started bool
+}
+
+func (s *logStream) start() {
+	if s.events {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		s.w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+}
+
+// send writes one line and flushes it to the client. Events carry the line
+// as JSON in their data.
+func (s *logStream) send(line client.LogLine) error {
+	if !s.started {
+		s.start()
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	if s.events {
+		_, err = fmt.Fprintf(s.w, "data: %s\n\n", data)
+	} else {
+		_, err = fmt.Fprintf(s.w, "%s\n", data)
+	}
+	if err != nil {
+		return err
+	}
+	return http.NewResponseController(s.w).Flush()
+}
+
+// end tells event clients that the stream is over, with an "end" event or an
+// "error" event carrying the message of err, as EventSource would otherwise
+// reconnect. Newline delimited JSON just ends.
+func (s *logStream) end(err error) {
+	if !s.events {
+		return
+	}
+	event, data := "end", []byte("{}")
+	if err != nil {
+		event = "error"
+		data, _ = json.Marshal(map[string]string{"message": err.Error()}) //nolint:errcheck // strings always encode
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, data); err == nil {
+		_ = http.NewResponseController(s.w).Flush() //nolint:errcheck // the client may already be gone
+	}
+}
+
+// replicaParam returns the 0-based replica index of the replica query
+// parameter, the first replica when it is unset
+func replicaParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("replica")
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, errors.NewInvalidInputErrorWithField("replica", "replica must be a non-negative number")
+	}
+	return n, nil
+}
+
+// replicaContainer returns the name of the container running a replica of
+// an application
+func (s *Server) replicaContainer(r *http.Request, appID string, replica int) (string, error) {
+	containers, err := s.container.List(r.Context(), true)
+	if err != nil {
+		return "", err
+	}
+	for i := range containers {
+		c := &containers[i]
+		if c.Labels["simplify.app.id"] != appID {
+			continue
+		}
+		n, _ := strconv.Atoi(c.Labels["simplify.app.replica"]) //nolint:errcheck // unlabeled containers are the first replica
+		if n == replica {
+			return c.Name, nil
+		}
+	}
+	return "", errors.NewNotFoundError("container", appID+" replica "+strconv.Itoa(replica))
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/metrics"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxMetricSamples is the most samples returned for a range. Longer ranges
+// are averaged into fewer, wider samples.
+const maxMetricSamples = 360
+
+// defaultMetricsRange is the range returned when none is requested
+const defaultMetricsRange = time.Hour
+
+// MetricsResponse is the resource usage history of an application
+type MetricsResponse = client.MetricsResponse
+
+// handleGetMetrics returns the resource usage of an application over the
+// last ?range (default 1h), averaged over ?step when given or when the
+// range holds more than maxMetricSamples samples
+func (s *Server) handleGetMetrics(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	if _, err := s.store.WithContext(r.Context()).GetApplication(id); err != nil {
+		return err
+	}
+	if s.config.Metrics.Interval <= 0 {
+		return errors.NewUnavailableError("metrics", "metrics collection is disabled, set metrics.interval to enable it")
+	}
+	interval := time.Duration(s.config.Metrics.Interval) * time.Second
+	retention := s.config.MetricsRetention()
+
+	rng := defaultMetricsRange
+	if q := r.URL.Query().Get("range"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d <= 0 {
+			return errors.NewInvalidInputErrorWithField("range", "range must be a positive duration such as 15m, 1h or 24h")
+		}
+		if d > retention {
+			return errors.NewInvalidInputErrorWithField("range", "range cannot exceed the metrics retention of "+retention.String())
+		}
+		rng = d
+	}
+
+	step := interval
+	if q := r.URL.Query().Get("step"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d <= 0 {
+			return errors.NewInvalidInputErrorWithField("step", "step must be a positive duration such as 1m")
+		}
+		step = max(d, interval)
+	}
+	// Round up to whole intervals, so each step holds the same number of samples
+	if minStep := rng / maxMetricSamples; step < minStep {
+		step = (minStep + interval - 1) / interval * interval
+	}
+
+	samples, err := s.store.WithContext(r.Context()).ListMetrics(id, time.Now().Add(-rng))
+	if err != nil {
+		return err
+	}
+	if step > interval {
+		samples = metrics.Downsample(samples, step)
+	}
+
+	return writeSuccess(w, MetricsResponse{Samples: samples, Range: rng.String(), Step: step.String()})
+}
@@ -2,12 +2,110 @@ package server
 
 import (
 	"net/http"
+	"net/netip"
+	"runtime/debug"
 	"strings"
+	"time"
 
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
 	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/reporting"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// httpLog logs API requests. Set logging.levels.http to quieten it.
+var httpLog = logger.NewComponent("http")
+
+// RequestLogger logs each request with its status and duration
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+		httpLog.Info("HTTP request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", ww.Status(),
+			"bytes", ww.BytesWritten(),
+			"duration", time.Since(start).String(),
+			"remote", r.RemoteAddr,
+			"request_id", middleware.GetReqID(r.Context()),
+		)
+	})
+}
+
+// RealIP replaces r.RemoteAddr with the client address from the
+// X-Forwarded-For, X-Real-IP or True-Client-IP headers, but only for
+// requests whose peer is one of the trusted proxies. Other peers keep their
+// socket address, so clients cannot spoof it.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		for _, p := range trusted {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := parseAddr(r.RemoteAddr); ok && isTrusted(peer) {
+				if client, ok := forwardedClient(r, isTrusted); ok {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient returns the client address from the forwarding headers.
+// X-Forwarded-For is read right to left, skipping trusted proxies, since
+// only the entries they appended can be believed.
+func forwardedClient(r *http.Request, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, ok := parseAddr(strings.TrimSpace(hops[i]))
+			if !ok {
+				break
+			}
+			client = addr
+			if !isTrusted(addr) {
+				break
+			}
+		}
+		if client.IsValid() {
+			return client, true
+		}
+	}
+
+	for _, header := range []string{"X-Real-IP", "True-Client-IP"} {
+		if addr, ok := parseAddr(strings.TrimSpace(r.Header.Get(header))); ok {
+			return addr, true
+		}
+	}
+	return netip.Addr{}, false
+}
+
+// parseAddr parses an address with or without a port
+func parseAddr(s string) (netip.Addr, bool) {
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr().Unmap(), true
+	}
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return addr.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
+
 // JSONContentType sets the Content-Type header to application/json for responses
 func JSONContentType(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -16,37 +114,36 @@ func JSONContentType(next http.Handler) http.Handler {
 	})
 }
 
-// RequireJSONContentType validates that POST/PUT/PATCH requests have JSON content type
+// RequireJSONContentType validates that POST/PUT/PATCH requests have JSON
+// content type. Requests without a body, such as actions like
+// POST /stacks/{id}/stop, need no content type.
 func RequireJSONContentType(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Only check methods that typically have request bodies
-		if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+		hasBody := r.ContentLength != 0
+		if hasBody && (r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch) {
 			contentType := r.Header.Get("Content-Type")
 			if contentType == "" {
-				err := writeJSON(w, http.StatusBadRequest, ErrorResponse{
-					Error: ErrorDetail{
-						Code:    "INVALID_INPUT",
-						Message: "Content-Type header is required",
-						Field:   "Content-Type",
-					},
+				err := writeError(w, r, http.StatusBadRequest, ErrorDetail{
+					Code:    "INVALID_INPUT",
+					Message: "Content-Type header is required",
+					Field:   "Content-Type",
 				})
 				if err != nil {
-					logger.Error("failed to write json", zap.Error(err))
+					log.Error("failed to write json", zap.Error(err))
 				}
 				return
 			}
 
 			// Check if content type is JSON (handle charset and other params)
 			if !strings.HasPrefix(contentType, "application/json") {
-				err := writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{
-					Error: ErrorDetail{
-						Code:    "INVALID_INPUT",
-						Message: "Content-Type must be application/json",
-						Field:   "Content-Type",
-					},
+				err := writeError(w, r, http.StatusUnsupportedMediaType, ErrorDetail{
+					Code:    "INVALID_INPUT",
+					Message: "Content-Type must be application/json",
+					Field:   "Content-Type",
 				})
 				if err != nil {
-					logger.Error("failed to write json", zap.Error(err))
+					log.Error("failed to write json", zap.Error(err))
 				}
 				return
 			}
@@ -56,6 +153,120 @@ func RequireJSONContentType(next http.Handler) http.Handler {
 	})
 }
 
+// readOnlyWithoutEngine refuses changes while the container engine is not
+// connected, so nothing is accepted that cannot be applied. Reads are served
+// from the store, and agents and the log level are still handled since they
+// do not involve this host's engine.
+func (s *Server) readOnlyWithoutEngine(next http.Handler) http.Handler {
+	unavailable := WrapHandler(func(http.ResponseWriter, *http.Request) error {
+		return errors.NewUnavailableError("podman", "the container engine is not connected yet, so the API is read-only")
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read := r.Method == http.MethodGet || r.Method == http.MethodHead
+		exempt := strings.HasPrefix(r.URL.Path, "/api/v1/nodes") || r.URL.Path == "/api/v1/logging/level"
+		if read || exempt || s.engineUp == nil || s.engineUp() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		unavailable(w, r)
+	})
+}
+
+// requireAPIKey guards routes that run commands in containers or manage
+// users and their API keys. Requests
+// must carry a valid API key as their bearer token unless they come from
+// this host, such as the CLI; the address is the one RealIP settled on, so
+// a reverse proxy on this host must forward the client's address.
+func (s *Server) requireAPIKey(next http.Handler) http.Handler {
+	return WrapHandler(func(w http.ResponseWriter, r *http.Request) error {
+		if addr, ok := parseAddr(r.RemoteAddr); ok && addr.IsLoopback() {
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		if _, _, err := s.authenticate(r); err != nil {
+			return err
+		}
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// authenticate returns the user and API key of the bearer token of r. A
+// missing, unknown or expired key is a PermissionError.
+func (s *Server) authenticate(r *http.Request) (*core.User, *core.APIKey, error) {
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || key == "" {
+		return nil, nil, errors.NewPermissionError("an API key is required")
+	}
+	user, apiKey, err := s.store.WithContext(r.Context()).AuthenticateAPIKey(key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, errors.NewPermissionError("invalid API key")
+		}
+		return nil, nil, err
+	}
+	return user, apiKey, nil
+}
+
+// Tracing starts a span for each API request, named after the matched route
+// once routing is done. Health checks are not traced.
+func Tracing(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			trace.SpanFromContext(r.Context()).SetName(r.Method + " " + rctx.RoutePattern())
+		}
+	})
+	return otelhttp.NewHandler(named, "http.request",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/healthz" && r.URL.Path != "/readyz"
+		}),
+	)
+}
+
+// Recoverer recovers from panics in handlers, logs them with their stack,
+// reports them to the error tracker and responds with an internal error
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rvr := recover()
+			if rvr == nil {
+				return
+			}
+			if rvr == http.ErrAbortHandler { //nolint:errorlint // sentinel used by net/http to abort a response
+				panic(rvr)
+			}
+
+			requestID := middleware.GetReqID(r.Context())
+			log.Error("Panic serving request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", requestID,
+				"panic", rvr,
+				"stack", string(debug.Stack()),
+			)
+			reporting.CapturePanic(r.Context(), rvr,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"request_id", requestID,
+			)
+
+			if r.Header.Get("Connection") == "Upgrade" {
+				return
+			}
+			err := writeError(w, r, http.StatusInternalServerError, ErrorDetail{
+				Code:    errors.CodeInternal,
+				Message: "An internal error occurred",
+			})
+			if err != nil {
+				log.Error("failed to write json", zap.Error(err))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
 // NoCacheHeaders adds headers to prevent caching of API responses
 func NoCacheHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
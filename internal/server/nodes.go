@@ -0,0 +1,301 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterNodeRequest is the body of POST /nodes
+type RegisterNodeRequest = client.RegisterNodeRequest
+
+// HeartbeatRequest is the body of POST /nodes/{id}/heartbeat
+type HeartbeatRequest = client.HeartbeatRequest
+
+// handleRegisterNode registers an agent's node, or re-registers it under
+// the same name, and returns the token the agent authenticates with. The
+// agent must present the server's join token, and to re-register, the
+// node's current token as its bearer token.
+func (s *Server) handleRegisterNode(w http.ResponseWriter, r *http.Request) error {
+	var req RegisterNodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	joinToken := s.config.Server.JoinToken
+	if joinToken == "" {
+		return errors.NewPermissionError("node registration is disabled; set server.join_token")
+	}
+	if subtle.ConstantTimeCompare([]byte(req.JoinToken), []byte(joinToken)) != 1 {
+		return errors.NewPermissionError("invalid join token")
+	}
+
+	if req.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if !engineNamePattern.MatchString(req.Name) {
+		return errors.NewInvalidInputErrorWithField("name", "name may only contain letters, digits, '.', '-' and '_'")
+	}
+
+	now := time.Now().UTC()
+	node := core.Node{
+		CreatedAt:     now,
+		LastHeartbeat: now,
+		Labels:        req.Labels,
+		Name:          req.Name,
+		Capacity:      req.Capacity,
+	}
+	current, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if err := s.store.WithContext(r.Context()).RegisterNode(&node, current); err != nil {
+		return err
+	}
+	node.Status = core.NodeReady
+
+	log.InfoCtx(r.Context(), "Node registered", "node", node.Name, "id", node.ID, "labels", node.Labels)
+	s.schedulePending(r)
+
+	return writeCreated(w, node)
+}
+
+// handleListNodes returns all nodes with their current state
+func (s *Server) handleListNodes(w http.ResponseWriter, r *http.Request) error {
+	nodes, err := s.store.WithContext(r.Context()).ListNodes()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range nodes {
+		nodes[i].Status = nodeStatus(&nodes[i], now)
+	}
+	return writeSuccess(w, nodes)
+}
+
+// handleGetNode returns a single node by ID
+func (s *Server) handleGetNode(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	node, err := s.store.WithContext(r.Context()).GetNode(id)
+	if err != nil {
+		return err
+	}
+	node.Status = nodeStatus(node, time.Now())
+
+	return writeSuccess(w, node)
+}
+
+// handleDeleteNode removes a node. Its applications are scheduled to other
+// matching nodes, or wait for one to register.
+func (s *Server) handleDeleteNode(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	if err := s.store.WithContext(r.Context()).DeleteNode(id); err != nil {
+		return err
+	}
+	s.schedulePending(r)
+
+	writeNoContent(w)
+	return nil
+}
+
+// handleNodeHeartbeat records that a node's agent is alive, with its
+// capacity and the status of its applications
+func (s *Server) handleNodeHeartbeat(w http.ResponseWriter, r *http.Request) error {
+	node, err := s.authenticateNode(r)
+	if err != nil {
+		return err
+	}
+
+	var req HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	now := time.Now().UTC()
+	wasReady := node.Ready(now)
+	node, err = s.store.WithContext(r.Context()).RecordHeartbeat(node.ID, req.Capacity, req.AppStatus, req.AppHealth, now)
+	if err != nil {
+		return err
+	}
+	node.Status = core.NodeReady
+
+	// A node coming back may take applications waiting for one
+	if !wasReady {
+		log.InfoCtx(r.Context(), "Node is ready", "node", node.Name)
+		s.schedulePending(r)
+	}
+
+	return writeSuccess(w, node)
+}
+
+// handleNodeState returns the applications scheduled to a node and the
+// resources they reference, including secret values. Only the node's
+// agent may read it.
+func (s *Server) handleNodeState(w http.ResponseWriter, r *http.Request) error {
+	node, err := s.authenticateNode(r)
+	if err != nil {
+		return err
+	}
+
+	st := s.store.WithContext(r.Context())
+	state := core.NodeState{
+		Applications: []core.Application{},
+		Environments: []core.Environment{},
+		Pods:         []core.Pod{},
+		Networks:     []core.Network{},
+		Volumes:      []core.Volume{},
+		Secrets:      []core.Secret{},
+		Stacks:       []core.Stack{},
+		Links:        []core.Link{},
+	}
+
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+	var (
+		podIDs, networkIDs, stackIDs, envIDs []string
+		volumeNames, secretNames             []string
+	)
+	for i := range apps {
+		app := &apps[i]
+		if !app.ScheduledOn(node.ID) {
+			continue
+		}
+		state.Applications = append(state.Applications, *app)
+		podIDs = appendMissing(podIDs, app.PodID)
+		networkIDs = appendMissing(networkIDs, app.NetworkID)
+		stackIDs = appendMissing(stackIDs, app.StackID)
+		envIDs = appendMissing(envIDs, app.EnvironmentID)
+		for _, m := range app.Volumes {
+			volumeNames = appendMissing(volumeNames, m.Name)
+		}
+		for _, ref := range app.Secrets {
+			secretNames = appendMissing(secretNames, ref.Name)
+		}
+	}
+
+	for _, id := range envIDs {
+		env, err := st.GetEnvironment(id)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Environment of scheduled application not found", "environment_id", id, "error", err)
+			continue
+		}
+		state.Environments = append(state.Environments, *env)
+	}
+	for _, id := range podIDs {
+		pod, err := st.GetPod(id)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Pod of scheduled application not found", "pod_id", id, "error", err)
+			continue
+		}
+		state.Pods = append(state.Pods, *pod)
+	}
+	for _, id := range networkIDs {
+		network, err := st.GetNetwork(id)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Network of scheduled application not found", "network_id", id, "error", err)
+			continue
+		}
+		state.Networks = append(state.Networks, *network)
+	}
+	for _, id := range stackIDs {
+		stack, err := st.GetStack(id)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Stack of scheduled application not found", "stack_id", id, "error", err)
+			continue
+		}
+		// Keep the start order of the members on this node only
+		stack.ApplicationIDs = slices.DeleteFunc(stack.ApplicationIDs, func(appID string) bool {
+			return !slices.ContainsFunc(state.Applications, func(a core.Application) bool { return a.ID == appID })
+		})
+		state.Stacks = append(state.Stacks, *stack)
+	}
+
+	// Dependencies order deployment among the applications on this node
+	links, err := st.ListLinks()
+	if err != nil {
+		return err
+	}
+	for i := range links {
+		onNode := func(appID string) bool {
+			return slices.ContainsFunc(state.Applications, func(a core.Application) bool { return a.ID == appID })
+		}
+		if links[i].Type == core.LinkDependsOn && onNode(links[i].From) && onNode(links[i].To) {
+			state.Links = append(state.Links, links[i])
+		}
+	}
+
+	volumes, err := st.ListVolumes()
+	if err != nil {
+		return err
+	}
+	for i := range volumes {
+		if slices.Contains(volumeNames, volumes[i].Name) {
+			state.Volumes = append(state.Volumes, volumes[i])
+		}
+	}
+
+	secrets, err := st.ListSecrets()
+	if err != nil {
+		return err
+	}
+	for i := range secrets {
+		if !slices.Contains(secretNames, secrets[i].Name) {
+			continue
+		}
+		value, err := st.SecretValue(secrets[i].Name)
+		if err != nil {
+			return err
+		}
+		secrets[i].Value = value
+		state.Secrets = append(state.Secrets, secrets[i])
+	}
+
+	return writeSuccess(w, state)
+}
+
+// authenticateNode returns the node named in the URL when the request
+// carries its token as a bearer token
+func (s *Server) authenticateNode(r *http.Request) (*core.Node, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return nil, errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, errors.NewPermissionError("node token required")
+	}
+	return s.store.WithContext(r.Context()).AuthenticateNode(id, token)
+}
+
+// nodeStatus returns NodeReady or NodeNotReady for a node at the given time
+func nodeStatus(node *core.Node, now time.Time) string {
+	if node.Ready(now) {
+		return core.NodeReady
+	}
+	return core.NodeNotReady
+}
+
+// appendMissing appends s to list unless it is empty or already listed
+func appendMissing(list []string, s string) []string {
+	if s == "" || slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
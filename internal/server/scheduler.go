@@ -0,0 +1,142 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+)
+
+// Application states for applications that do not run on the server
+const (
+	statusPending = "pending" // no node matches the node selector
+	statusUnknown = "unknown" // the node stopped sending heartbeats
+)
+
+// scheduleApplication sets app.NodeID from its node selector. Applications
+// without a selector run on the server. An application stays on its node as
+// long as the node exists and matches, so that updates do not move it;
+// otherwise the least loaded ready node that matches is picked. When none
+// matches, the application waits for one, see schedulePending.
+func (s *Server) scheduleApplication(r *http.Request, app *core.Application) error {
+	if len(app.NodeSelector) == 0 {
+		app.NodeID = ""
+		return nil
+	}
+
+	st := s.store.WithContext(r.Context())
+	if app.NodeID != "" {
+		if node, err := st.GetNode(app.NodeID); err == nil && node.Matches(app.NodeSelector) {
+			return nil
+		}
+	}
+
+	nodes, err := st.ListNodes()
+	if err != nil {
+		return err
+	}
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+
+	app.NodeID = ""
+	if node := pickNode(nodes, apps, app, time.Now()); node != nil {
+		app.NodeID = node.ID
+		log.InfoCtx(r.Context(), "Application scheduled", "app", app.Name, "node", node.Name)
+	} else {
+		log.InfoCtx(r.Context(), "No node matches application, waiting for one", "app", app.Name, "selector", app.NodeSelector)
+	}
+	return nil
+}
+
+// schedulePending places the applications waiting for a node. It runs
+// when nodes register, come back or are removed.
+func (s *Server) schedulePending(r *http.Request) {
+	st := s.store.WithContext(r.Context())
+
+	nodes, err := st.ListNodes()
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to list nodes for scheduling", "error", err)
+		return
+	}
+	apps, err := st.ListApplications()
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to list applications for scheduling", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range apps {
+		app := &apps[i]
+		if len(app.NodeSelector) == 0 || app.NodeID != "" {
+			continue
+		}
+		node := pickNode(nodes, apps, app, now)
+		if node == nil {
+			continue
+		}
+		// Setting it in apps counts it towards the node's load for the
+		// applications after it
+		app.NodeID = node.ID
+		if err := st.UpdateApplication(app); err != nil {
+			log.WarnCtx(r.Context(), "Failed to schedule application", "app", app.Name, "error", err)
+			continue
+		}
+		log.InfoCtx(r.Context(), "Application scheduled", "app", app.Name, "node", node.Name)
+	}
+}
+
+// pickNode returns the ready node matching app's selector that runs the
+// fewest applications per CPU, or nil. Ties go to the node whose name
+// sorts first, so placement is predictable.
+func pickNode(nodes []core.Node, apps []core.Application, app *core.Application, now time.Time) *core.Node {
+	load := make(map[string]int, len(nodes))
+	for i := range apps {
+		if apps[i].NodeID != "" && apps[i].ID != app.ID {
+			load[apps[i].NodeID]++
+		}
+	}
+
+	var best *core.Node
+	for i := range nodes {
+		n := &nodes[i]
+		if !n.Ready(now) || !n.Matches(app.NodeSelector) {
+			continue
+		}
+		if best == nil {
+			best = n
+			continue
+		}
+		// Compare load/cpus without dividing
+		a, b := load[n.ID]*max(best.Capacity.CPUs, 1), load[best.ID]*max(n.Capacity.CPUs, 1)
+		if a < b || (a == b && n.Name < best.Name) {
+			best = n
+		}
+	}
+	return best
+}
+
+// applyNodeStatus sets the status and health of an application that runs
+// on a node from the node's last heartbeat. Applications run by the server
+// are left unchanged.
+func applyNodeStatus(app *core.Application, nodes map[string]*core.Node, now time.Time) {
+	if app.ScheduledOn("") {
+		return
+	}
+	node := nodes[app.NodeID]
+	app.HealthStatus = ""
+	if node != nil && node.Ready(now) && app.HealthCheck != nil {
+		app.HealthStatus = node.AppHealth[app.ID]
+	}
+	switch {
+	case app.NodeID == "" || node == nil:
+		app.Status = statusPending
+	case !node.Ready(now):
+		app.Status = statusUnknown
+	case node.AppStatus[app.ID] != "":
+		app.Status = node.AppStatus[app.ID]
+	default:
+		app.Status = statusStopped
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxSecretSize is the largest value the container engine accepts for a secret
+const maxSecretSize = 512 * 1024
+
+var (
+	// engineNamePattern restricts secret and volume names to those valid in the engine
+	engineNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	// envNamePattern matches valid environment variable names
+	envNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// handleCreateSecret stores a new secret. The value is write-only and is
+// left out of the response.
+func (s *Server) handleCreateSecret(w http.ResponseWriter, r *http.Request) error {
+	var secret core.Secret
+	if err := json.NewDecoder(r.Body).Decode(&secret); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	if secret.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if !engineNamePattern.MatchString(secret.Name) {
+		return errors.NewInvalidInputErrorWithField("name", "name may only contain letters, digits, '.', '-' and '_'")
+	}
+	if err := validateSecretValue(secret.Value); err != nil {
+		return err
+	}
+
+	secret.ID = uuid.New().String()
+	now := time.Now().UTC()
+	secret.CreatedAt = now
+	secret.UpdatedAt = now
+
+	if err := s.store.WithContext(r.Context()).CreateSecret(&secret); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionCreated, &secret, "")
+
+	secret.Value = ""
+	return writeCreated(w, secret)
+}
+
+// handleListSecrets returns all secrets without their values
+func (s *Server) handleListSecrets(w http.ResponseWriter, r *http.Request) error {
+	secrets, err := s.store.WithContext(r.Context()).ListSecrets()
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, secrets)
+}
+
+// handleGetSecret returns a secret without its value
+func (s *Server) handleGetSecret(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	secret, err := s.store.WithContext(r.Context()).GetSecret(id)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, secret)
+}
+
+// handleUpdateSecret replaces the value of a secret. Applications using it
+// get a new revision, so the reconciler recreates their containers with the
+// new value.
+func (s *Server) handleUpdateSecret(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.Secret
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if err := validateSecretValue(req.Value); err != nil {
+		return err
+	}
+
+	st := s.store.WithContext(r.Context())
+	secret, err := st.GetSecret(id)
+	if err != nil {
+		return err
+	}
+
+	secret.Value = req.Value
+	secret.UpdatedAt = time.Now().UTC()
+	if err := st.UpdateSecret(secret); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionUpdated, secret, "value changed")
+
+	apps, err := s.appsUsingSecret(r, secret.Name)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		apps[i].UpdatedAt = secret.UpdatedAt
+		if err := st.UpdateApplication(&apps[i]); err != nil {
+			return err
+		}
+		s.recordDeployment(r, &apps[i], "")
+		log.InfoCtx(r.Context(), "Redeploying application for updated secret", "app", apps[i].Name, "secret", secret.Name)
+	}
+
+	secret.Value = ""
+	return writeSuccess(w, secret)
+}
+
+// handleDeleteSecret removes a secret that no application uses
+func (s *Server) handleDeleteSecret(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	secret, err := s.store.WithContext(r.Context()).GetSecret(id)
+	if err != nil {
+		return err
+	}
+
+	apps, err := s.appsUsingSecret(r, secret.Name)
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 {
+		return errors.NewConflictStateError("secret", secret.Name,
+			fmt.Sprintf("secret is used by application %s", apps[0].Name))
+	}
+
+	if err := s.store.WithContext(r.Context()).DeleteSecret(id); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionDeleted, secret, "")
+
+	// The engine copy only exists once an application has been deployed with it
+	if err := s.container.RemoveSecret(r.Context(), container.ManagedSecretName(secret.Name)); err != nil {
+		log.DebugCtx(r.Context(), "Secret not removed from engine", "name", secret.Name, "error", err)
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// appsUsingSecret returns the applications that reference the secret name
+func (s *Server) appsUsingSecret(r *http.Request, name string) ([]core.Application, error) {
+	apps, err := s.store.WithContext(r.Context()).ListApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	var using []core.Application
+	for i := range apps {
+		if slices.ContainsFunc(apps[i].Secrets, func(ref core.SecretRef) bool { return ref.Name == name }) {
+			using = append(using, apps[i])
+		}
+	}
+	return using, nil
+}
+
+// validateSecretValue checks a value is present and fits in the engine
+func validateSecretValue(value string) error {
+	if value == "" {
+		return errors.NewInvalidInputErrorWithField("value", "value is required")
+	}
+	if len(value) > maxSecretSize {
+		return errors.NewInvalidInputErrorWithField("value", fmt.Sprintf("value must not exceed %d bytes", maxSecretSize))
+	}
+	return nil
+}
+
+// validateSecretRefs checks an application's secret references. Each names a
+// secret and injects it as an environment variable, a file, or both.
+func validateSecretRefs(refs []core.SecretRef) error {
+	for _, ref := range refs {
+		if ref.Name == "" {
+			return errors.NewInvalidInputErrorWithField("secrets", "secret name is required")
+		}
+		if ref.Env == "" && ref.File == "" {
+			return errors.NewInvalidInputErrorWithField("secrets",
+				fmt.Sprintf("secret %s must set env, file or both", ref.Name))
+		}
+		if ref.Env != "" && !envNamePattern.MatchString(ref.Env) {
+			return errors.NewInvalidInputErrorWithField("secrets",
+				fmt.Sprintf("secret %s: invalid environment variable name %q", ref.Name, ref.Env))
+		}
+		if ref.File != "" && (!path.IsAbs(ref.File) || path.Clean(ref.File) != ref.File) {
+			return errors.NewInvalidInputErrorWithField("secrets",
+				fmt.Sprintf("secret %s: file must be a clean absolute path", ref.Name))
+		}
+	}
+	return nil
+}
@@ -4,17 +4,25 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/config"
 	"github.com/AkMo3/simplify/internal/container"
+	"github.com/AkMo3/simplify/internal/core"
 	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/notify"
 	"github.com/AkMo3/simplify/internal/store"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
+// log is the server's component logger, see logging.levels. API request
+// logs use the separate "http" component.
+var log = logger.NewComponent("server")
+
 // Server represents the HTTP API server
 type Server struct {
 	router    *chi.Mux
@@ -22,6 +30,13 @@ type Server struct {
 	store     *store.Store
 	container container.ContainerManager
 	config    *config.Config
+	resolver  txtResolver      // DNS lookups for domain verification
+	builder   Builder          // nil when builds are disabled
+	backups   Backups          // nil when no backup target is configured
+	reconcile func()           // requests a reconcile, nil when there is no reconciler
+	events    notify.Publisher // nil sends no notifications
+	engineUp  func() bool      // reports whether the engine is connected, nil when it always is
+	buildMu   sync.Mutex       // serializes starting builds
 }
 
 // New creates a new Server with the provided dependencies
@@ -31,25 +46,75 @@ func New(cfg *config.Config, storeImpl *store.Store, containerClient container.C
 		store:     storeImpl,
 		container: containerClient,
 		config:    cfg,
+		resolver:  net.DefaultResolver,
 	}
 	s.setupMiddleware()
 	s.setupRoutes()
 	return s
 }
 
+// SetReconcileTrigger sets the function that asks the reconciler to apply
+// changes right away, such as an image updated by a push
+func (s *Server) SetReconcileTrigger(fn func()) {
+	s.reconcile = fn
+}
+
+// SetEngineCheck makes the API read-only while up reports that the
+// container engine is not connected, such as while the server waits for the
+// Podman socket at boot
+func (s *Server) SetEngineCheck(up func() bool) {
+	s.engineUp = up
+}
+
+// SetPublisher sends notifications, such as failed builds, to p
+func (s *Server) SetPublisher(p notify.Publisher) {
+	s.events = p
+}
+
+// publish records an event about an application in the activity feed and
+// sends it to the publisher, if one is set
+func (s *Server) publish(r *http.Request, app *core.Application, eventType, message string) {
+	a := core.Activity{Actor: core.ActorSystem, Action: eventType, Message: message}
+	if err := s.store.WithContext(r.Context()).RecordResourceActivity(&a, app); err != nil {
+		log.WarnCtx(r.Context(), "Failed to record activity", "action", eventType, "error", err)
+	}
+
+	if s.events == nil {
+		return
+	}
+	e := notify.Event{Type: eventType, Application: app.Name, Message: message}
+	team, err := s.store.WithContext(r.Context()).ApplicationTeam(app)
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to find the team of an application", "app", app.Name, "error", err)
+	} else if team != nil {
+		e.TeamID, e.Team = team.ID, team.Name
+	}
+	s.events.Publish(r.Context(), e)
+}
+
+// triggerReconcile asks the reconciler to run, if there is one
+func (s *Server) triggerReconcile() {
+	if s.reconcile != nil {
+		s.reconcile()
+	}
+}
+
 // setupMiddleware configures the middleware stack
 func (s *Server) setupMiddleware() {
 	// Request ID for tracing
 	s.router.Use(middleware.RequestID)
 
-	// Real IP detection (for proxies)
-	s.router.Use(middleware.RealIP)
+	// Client IP from forwarded headers, trusted only from configured proxies
+	s.router.Use(RealIP(s.config.TrustedProxies()))
 
 	// Request logging
-	s.router.Use(middleware.Logger)
+	s.router.Use(RequestLogger)
 
-	// Panic recovery
-	s.router.Use(middleware.Recoverer)
+	// OpenTelemetry spans, a no-op unless tracing is enabled
+	s.router.Use(Tracing)
+
+	// Panic recovery, reported to the error tracker when configured
+	s.router.Use(Recoverer)
 
 	// Security headers
 	s.router.Use(SecurityHeaders)
@@ -69,12 +134,30 @@ func (s *Server) setupRoutes() {
 
 	// API routes
 	s.router.Route("/api/v1", func(r chi.Router) {
+		r.Use(s.readOnlyWithoutEngine)
+
 		// Applications
 		r.Post("/applications", WrapHandler(s.handleCreateApplication))
 		r.Get("/applications", WrapHandler(s.handleListApplications))
 		r.Get("/applications/{id}", WrapHandler(s.handleGetApplication))
 		r.Put("/applications/{id}", WrapHandler(s.handleUpdateApplication))
+		r.Get("/applications/{id}/export", WrapHandler(s.handleExportApplication))
+		r.Post("/applications/{id}/scale", WrapHandler(s.handleScaleApplication))
+		r.Get("/applications/{id}/deployments", WrapHandler(s.handleListDeployments))
+		r.Post("/applications/{id}/rollback/{revision}", WrapHandler(s.handleRollbackApplication))
 		r.Delete("/applications/{id}", WrapHandler(s.handleDeleteApplication))
+		r.Post("/applications/{id}/builds", WrapHandler(s.handleCreateBuild))
+		r.Get("/applications/{id}/builds", WrapHandler(s.handleListBuilds))
+		r.Get("/applications/{id}/builds/{buildID}", WrapHandler(s.handleGetBuild))
+		r.Get("/applications/{id}/metrics", WrapHandler(s.handleGetMetrics))
+		r.Get("/applications/{id}/logs", WrapHandler(s.handleStreamLogs))
+		// Running commands in containers needs an API key from other hosts
+		r.With(s.requireAPIKey).Get("/applications/{id}/attach", WrapHandler(s.handleAttach))
+		r.With(s.requireAPIKey).Post("/applications/{id}/exec", WrapHandler(s.handleExec))
+
+		// Push webhooks from git hosts, authenticated by the project's webhook secret
+		r.Post("/hooks/github", WrapHandler(s.handleGitHubHook))
+		r.Post("/hooks/gitlab", WrapHandler(s.handleGitLabHook))
 
 		// Teams
 		r.Post("/teams", WrapHandler(s.handleCreateTeam))
@@ -82,6 +165,24 @@ func (s *Server) setupRoutes() {
 		r.Get("/teams/{id}", WrapHandler(s.handleGetTeam))
 		r.Put("/teams/{id}", WrapHandler(s.handleUpdateTeam))
 		r.Delete("/teams/{id}", WrapHandler(s.handleDeleteTeam))
+		r.Get("/teams/{id}/members", WrapHandler(s.handleListTeamMembers))
+		r.Put("/teams/{id}/members/{userID}", WrapHandler(s.handleSetTeamMember))
+		r.Delete("/teams/{id}/members/{userID}", WrapHandler(s.handleRemoveTeamMember))
+
+		// Users and their API keys. Changing them needs an API key from other
+		// hosts, or anyone could mint a key for the routes guarded by one.
+		r.Get("/whoami", WrapHandler(s.handleWhoAmI))
+		r.Get("/users", WrapHandler(s.handleListUsers))
+		r.Get("/users/{id}", WrapHandler(s.handleGetUser))
+		r.Get("/users/{id}/apikeys", WrapHandler(s.handleListAPIKeys))
+		r.Group(func(r chi.Router) {
+			r.Use(s.requireAPIKey)
+			r.Post("/users", WrapHandler(s.handleCreateUser))
+			r.Put("/users/{id}", WrapHandler(s.handleUpdateUser))
+			r.Delete("/users/{id}", WrapHandler(s.handleDeleteUser))
+			r.Post("/users/{id}/apikeys", WrapHandler(s.handleCreateAPIKey))
+			r.Delete("/users/{id}/apikeys/{keyID}", WrapHandler(s.handleDeleteAPIKey))
+		})
 
 		// Projects
 		r.Post("/projects", WrapHandler(s.handleCreateProject))
@@ -96,27 +197,97 @@ func (s *Server) setupRoutes() {
 		r.Get("/environments/{id}", WrapHandler(s.handleGetEnvironment))
 		r.Put("/environments/{id}", WrapHandler(s.handleUpdateEnvironment))
 		r.Delete("/environments/{id}", WrapHandler(s.handleDeleteEnvironment))
+		r.Post("/environments/{id}/clone", WrapHandler(s.handleCloneEnvironment))
 
 		// Images
 		r.Get("/images/inspect", WrapHandler(s.handleInspectImage))
 
+		// Host
+		r.Get("/system/df", WrapHandler(s.handleDiskUsage))
+		r.Get("/system/info", WrapHandler(s.handleSystemInfo))
+
 		// Pods
 		r.Post("/pods", WrapHandler(s.handleCreatePod))
 		r.Get("/pods", WrapHandler(s.handleListPods))
 		r.Get("/pods/{id}", WrapHandler(s.handleGetPod))
+		r.Put("/pods/{id}", WrapHandler(s.handleUpdatePod))
 		r.Delete("/pods/{id}", WrapHandler(s.handleDeletePod))
 
 		// Networks
 		r.Post("/networks", WrapHandler(s.handleCreateNetwork))
 		r.Get("/networks", WrapHandler(s.handleListNetworks))
 		r.Delete("/networks/{id}", WrapHandler(s.handleDeleteNetwork))
+
+		// Secrets (values are write-only)
+		r.Post("/secrets", WrapHandler(s.handleCreateSecret))
+		r.Get("/secrets", WrapHandler(s.handleListSecrets))
+		r.Get("/secrets/{id}", WrapHandler(s.handleGetSecret))
+		r.Put("/secrets/{id}", WrapHandler(s.handleUpdateSecret))
+		r.Delete("/secrets/{id}", WrapHandler(s.handleDeleteSecret))
+
+		// Volumes
+		r.Post("/volumes", WrapHandler(s.handleCreateVolume))
+		r.Get("/volumes", WrapHandler(s.handleListVolumes))
+		r.Get("/volumes/{id}", WrapHandler(s.handleGetVolume))
+		r.Put("/volumes/{id}", WrapHandler(s.handleUpdateVolume))
+		r.Delete("/volumes/{id}", WrapHandler(s.handleDeleteVolume))
+
+		// Domains
+		r.Post("/domains", WrapHandler(s.handleCreateDomain))
+		r.Get("/domains", WrapHandler(s.handleListDomains))
+		r.Get("/domains/{id}", WrapHandler(s.handleGetDomain))
+		r.Put("/domains/{id}", WrapHandler(s.handleUpdateDomain))
+		r.Delete("/domains/{id}", WrapHandler(s.handleDeleteDomain))
+		r.Post("/domains/{id}/verify", WrapHandler(s.handleVerifyDomain))
+
+		// Stacks
+		r.Post("/stacks", WrapHandler(s.handleCreateStack))
+		r.Get("/stacks", WrapHandler(s.handleListStacks))
+		r.Get("/stacks/{id}", WrapHandler(s.handleGetStack))
+		r.Put("/stacks/{id}", WrapHandler(s.handleUpdateStack))
+		r.Delete("/stacks/{id}", WrapHandler(s.handleDeleteStack))
+		r.Post("/stacks/{id}/deploy", WrapHandler(s.handleDeployStack))
+		r.Post("/stacks/{id}/stop", WrapHandler(s.handleStopStack))
+
+		// Links between applications and the service map they form
+		r.Post("/links", WrapHandler(s.handleCreateLink))
+		r.Get("/links", WrapHandler(s.handleListLinks))
+		r.Delete("/links/{id}", WrapHandler(s.handleDeleteLink))
+		r.Get("/graph", WrapHandler(s.handleGetGraph))
+
+		// Activity feed of changes and events, per team, project or application
+		r.Get("/activity", WrapHandler(s.handleListActivity))
+
+		// Nodes run by agents; heartbeat and state take the node's token
+		r.Post("/nodes", WrapHandler(s.handleRegisterNode))
+		r.Get("/nodes", WrapHandler(s.handleListNodes))
+		r.Get("/nodes/{id}", WrapHandler(s.handleGetNode))
+		r.Delete("/nodes/{id}", WrapHandler(s.handleDeleteNode))
+		r.Post("/nodes/{id}/heartbeat", WrapHandler(s.handleNodeHeartbeat))
+		r.Get("/nodes/{id}/state", WrapHandler(s.handleNodeState))
+
+		// Application templates
+		r.Get("/templates", WrapHandler(s.handleListTemplates))
+		r.Get("/templates/{id}", WrapHandler(s.handleGetTemplate))
+		r.Post("/templates/{id}/instantiate", WrapHandler(s.handleInstantiateTemplate))
+
+		// Logging
+		r.Get("/logging/level", WrapHandler(s.handleGetLogLevel))
+		r.Put("/logging/level", WrapHandler(s.handleSetLogLevel))
+
+		// Backup
+		r.Get("/backup", WrapHandler(s.handleBackup))
+		r.Post("/backup/restore", WrapHandler(s.handleRestore))
+		r.Get("/backups", WrapHandler(s.handleListBackups))
+		r.Post("/backups", WrapHandler(s.handleCreateBackup))
+		r.Post("/backups/{name}/restore", WrapHandler(s.handleRestoreBackup))
 	})
 }
 
 // Start starts the HTTP server and blocks until the context is canceled.
 // It handles graceful shutdown when the context is canceled.
 func (s *Server) Start(ctx context.Context) error {
-	addr := fmt.Sprintf(":%d", s.config.Server.Port)
+	addr := s.config.ListenAddr()
 
 	s.server = &http.Server{
 		Addr:         addr,
@@ -131,7 +302,7 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Start server in goroutine
 	go func() {
-		logger.Info("Starting HTTP server",
+		log.Info("Starting HTTP server",
 			"addr", addr,
 			"read_timeout", s.config.Server.ReadTimeout,
 			"write_timeout", s.config.Server.WriteTimeout,
@@ -145,7 +316,7 @@ func (s *Server) Start(ctx context.Context) error {
 	// Wait for context cancellation or server error
 	select {
 	case <-ctx.Done():
-		logger.Info("Shutting down HTTP server...")
+		log.Info("Shutting down HTTP server...")
 		return s.shutdown()
 	case err := <-errCh:
 		return fmt.Errorf("server error: %w", err)
@@ -158,16 +329,16 @@ func (s *Server) shutdown() error {
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	logger.Info("Graceful shutdown initiated",
+	log.Info("Graceful shutdown initiated",
 		"timeout_seconds", s.config.Server.ShutdownTimeout,
 	)
 
 	if err := s.server.Shutdown(ctx); err != nil {
-		logger.Error("Graceful shutdown failed", "error", err)
+		log.Error("Graceful shutdown failed", "error", err)
 		return fmt.Errorf("shutdown error: %w", err)
 	}
 
-	logger.Info("HTTP server stopped gracefully")
+	log.Info("HTTP server stopped gracefully")
 	return nil
 }
 
@@ -1,20 +1,35 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/AkMo3/simplify/internal/backup"
 	"github.com/AkMo3/simplify/internal/config"
 	"github.com/AkMo3/simplify/internal/container"
 	"github.com/AkMo3/simplify/internal/core"
 	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/logger"
+	"github.com/AkMo3/simplify/internal/manifest"
 	"github.com/AkMo3/simplify/internal/store"
+	"github.com/AkMo3/simplify/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -25,9 +40,14 @@ type MockContainerManager struct {
 	InspectImageFunc func(ctx context.Context, image string) (*container.ImageInfo, error)
 	ListPodsFunc     func(ctx context.Context) ([]container.PodInfo, error)
 	InspectPodFunc   func(ctx context.Context, nameOrID string) (*container.PodInfo, error)
+	StreamLogsFunc   func(ctx context.Context, name string, opts container.LogOptions, fn func(container.LogLine) error) error
+	AttachFunc       func(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error
+	ExecFunc         func(ctx context.Context, name string, opts container.ExecOptions) (int, error)
+	DiskUsageFunc    func(ctx context.Context) (*container.DiskUsage, error)
+	SystemInfoFunc   func(ctx context.Context) (*container.SystemInfo, error)
 }
 
-func (m *MockContainerManager) Run(ctx context.Context, name, image string, ports map[uint16]uint16, env []string, labels map[string]string, podName, networkName string) (string, error) {
+func (m *MockContainerManager) Run(ctx context.Context, name string, opts container.RunOptions) (string, error) {
 	return "mock-id", nil
 }
 func (m *MockContainerManager) Stop(ctx context.Context, name string, timeout *uint) error {
@@ -42,9 +62,30 @@ func (m *MockContainerManager) List(ctx context.Context, all bool) ([]container.
 	}
 	return []container.ContainerInfo{}, nil
 }
+func (m *MockContainerManager) Restart(ctx context.Context, name string) error {
+	return nil
+}
 func (m *MockContainerManager) Logs(ctx context.Context, name string, follow bool, tail string) error {
 	return nil
 }
+func (m *MockContainerManager) StreamLogs(ctx context.Context, name string, opts container.LogOptions, fn func(container.LogLine) error) error {
+	if m.StreamLogsFunc != nil {
+		return m.StreamLogsFunc(ctx, name, opts, fn)
+	}
+	return nil
+}
+func (m *MockContainerManager) Attach(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if m.AttachFunc != nil {
+		return m.AttachFunc(ctx, name, stdin, stdout, stderr)
+	}
+	return nil
+}
+func (m *MockContainerManager) Exec(ctx context.Context, name string, opts container.ExecOptions) (int, error) {
+	if m.ExecFunc != nil {
+		return m.ExecFunc(ctx, name, opts)
+	}
+	return 0, nil
+}
 func (m *MockContainerManager) GetContainer(ctx context.Context, nameOrID string) (*container.ContainerInfo, error) {
 	return &container.ContainerInfo{}, nil
 }
@@ -54,7 +95,7 @@ func (m *MockContainerManager) InspectImage(ctx context.Context, image string) (
 	}
 	return &container.ImageInfo{ID: "mock-image-id", ExposedPorts: []string{"80/tcp"}}, nil
 }
-func (m *MockContainerManager) CreatePod(ctx context.Context, name string, ports map[uint16]uint16) (string, error) {
+func (m *MockContainerManager) CreatePod(ctx context.Context, name string, opts container.PodOptions) (string, error) {
 	return "mock-pod-id", nil
 }
 func (m *MockContainerManager) RemovePod(ctx context.Context, nameOrID string, force bool) error {
@@ -88,8 +129,39 @@ func (m *MockContainerManager) RemoveNetwork(ctx context.Context, nameOrID strin
 func (m *MockContainerManager) ListNetworks(ctx context.Context) ([]container.NetworkInfo, error) {
 	return []container.NetworkInfo{}, nil
 }
+func (m *MockContainerManager) CreateSecret(ctx context.Context, name string, data io.Reader, replace bool) (string, error) {
+	return "mock-secret-id", nil
+}
+func (m *MockContainerManager) RemoveSecret(ctx context.Context, nameOrID string) error {
+	return nil
+}
+func (m *MockContainerManager) CreateVolume(ctx context.Context, name string, labels map[string]string) (string, error) {
+	return name, nil
+}
+func (m *MockContainerManager) RemoveVolume(ctx context.Context, name string, force bool) error {
+	return nil
+}
+func (m *MockContainerManager) ListVolumes(ctx context.Context) ([]container.VolumeInfo, error) {
+	return []container.VolumeInfo{}, nil
+}
+func (m *MockContainerManager) DiskUsage(ctx context.Context) (*container.DiskUsage, error) {
+	if m.DiskUsageFunc != nil {
+		return m.DiskUsageFunc(ctx)
+	}
+	return &container.DiskUsage{}, nil
+}
+
+func (m *MockContainerManager) SystemInfo(ctx context.Context) (*container.SystemInfo, error) {
+	if m.SystemInfoFunc != nil {
+		return m.SystemInfoFunc(ctx)
+	}
+	return &container.SystemInfo{}, nil
+}
 
 // setupTestServer creates a test server with a temporary database
+// testJoinToken is the join token of test servers
+const testJoinToken = "test-join-token"
+
 func setupTestServer(t *testing.T) (srv *Server, mock *MockContainerManager, cleanup func()) {
 	t.Helper()
 
@@ -102,11 +174,15 @@ func setupTestServer(t *testing.T) (srv *Server, mock *MockContainerManager, cle
 	// Create store
 	s, err := store.New(dbPath)
 	require.NoError(t, err)
+	key, err := store.LoadSecretKey(filepath.Join(tmpDir, "secret.key"))
+	require.NoError(t, err)
+	require.NoError(t, s.SetSecretKey(key))
 
 	// Create config with defaults
 	cfg := &config.Config{
 		Env: config.EnvDevelopment,
 		Server: config.ServerConfig{
+			JoinToken:       testJoinToken,
 			Port:            8080,
 			ReadTimeout:     30,
 			WriteTimeout:    30,
@@ -189,6 +265,38 @@ func TestCreateApplication(t *testing.T) {
 				assert.Equal(t, "image", errResp.Error.Field)
 			},
 		},
+		{
+			name: "id with path separators",
+			body: map[string]any{
+				"id":    "../../etc",
+				"name":  "test-app",
+				"image": "nginx:latest",
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body []byte) {
+				var errResp ErrorResponse
+				err := json.Unmarshal(body, &errResp)
+				require.NoError(t, err)
+				assert.Equal(t, errors.CodeInvalidInput, errResp.Error.Code)
+				assert.Equal(t, "id", errResp.Error.Field)
+			},
+		},
+		{
+			name: "invalid network alias",
+			body: map[string]any{
+				"name":            "test-app",
+				"image":           "nginx:latest",
+				"network_aliases": []string{"db", "not an alias"},
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body []byte) {
+				var errResp ErrorResponse
+				err := json.Unmarshal(body, &errResp)
+				require.NoError(t, err)
+				assert.Equal(t, errors.CodeInvalidInput, errResp.Error.Code)
+				assert.Equal(t, "network_aliases", errResp.Error.Field)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -317,6 +425,47 @@ func TestListApplications(t *testing.T) {
 	}
 }
 
+func TestListApplicationsOutdatedRevision(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	app := &core.Application{
+		ID:        "app-rev",
+		Name:      "Revisioned",
+		Image:     "nginx:latest",
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, srv.store.CreateApplication(app))
+
+	revision := "2000-01-01T00:00:00Z"
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{
+			{
+				ID:     "c1",
+				Labels: map[string]string{"simplify.app.id": "app-rev", "simplify.app.revision": revision},
+				Status: "running",
+			},
+		}, nil
+	}
+
+	list := func() core.Application {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/applications", http.NoBody)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var apps []core.Application
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apps))
+		require.Len(t, apps, 1)
+		return apps[0]
+	}
+
+	assert.Equal(t, statusUpdating, list().Status)
+
+	revision = app.Revision()
+	assert.Equal(t, "running", list().Status)
+}
+
 func TestUpdateApplication(t *testing.T) {
 	srv, _, cleanup := setupTestServer(t)
 	defer cleanup()
@@ -374,6 +523,76 @@ func TestUpdateApplicationNotFound(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &errResp)
 	require.NoError(t, err)
 	assert.Equal(t, errors.CodeNotFound, errResp.Error.Code)
+	assert.NotEmpty(t, errResp.Error.RequestID, "errors should be traceable to the server logs")
+	assert.Equal(t, errors.DocsURL+"#not_found", errResp.Error.DocURL)
+}
+
+func TestScaleApplication(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	app := &core.Application{ID: "worker", Name: "worker", Image: "busybox", Replicas: 1}
+	require.NoError(t, srv.store.CreateApplication(app))
+	web := &core.Application{ID: "web", Name: "web", Image: "nginx", Ports: map[string]string{"8080": "80"}}
+	require.NoError(t, srv.store.CreateApplication(web))
+	revision := app.Revision()
+
+	scale := func(id string, body map[string]any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/applications/"+id+"/scale", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := scale("worker", map[string]any{"replicas": 3})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	stored, err := srv.store.GetApplication("worker")
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Replicas)
+	assert.Equal(t, revision, stored.Revision(), "scaling must not change the revision")
+
+	// Invalid counts
+	assert.Equal(t, http.StatusBadRequest, scale("worker", map[string]any{"replicas": 0}).Code)
+	assert.Equal(t, http.StatusBadRequest, scale("worker", map[string]any{}).Code)
+
+	// Host ports can only be bound by one replica
+	assert.Equal(t, http.StatusBadRequest, scale("web", map[string]any{"replicas": 2}).Code)
+	assert.Equal(t, http.StatusOK, scale("web", map[string]any{"replicas": 1}).Code)
+
+	assert.Equal(t, http.StatusNotFound, scale("missing", map[string]any{"replicas": 2}).Code)
+}
+
+func TestListApplicationsInstances(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	app := &core.Application{ID: "worker", Name: "worker", Image: "busybox", Replicas: 2}
+	require.NoError(t, srv.store.CreateApplication(app))
+
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{
+			{Name: "worker-2", Status: "exited", Labels: map[string]string{"simplify.app.id": "worker", "simplify.app.replica": "1"}},
+			{Name: "worker", Status: "running", Labels: map[string]string{"simplify.app.id": "worker"}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/applications", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var apps []core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apps))
+	require.Len(t, apps, 1)
+
+	assert.Equal(t, "running", apps[0].Status, "status comes from the first replica")
+	require.Len(t, apps[0].Instances, 2)
+	assert.Equal(t, core.Instance{Name: "worker", Status: "running", Replica: 0}, apps[0].Instances[0])
+	assert.Equal(t, "exited", apps[0].Instances[1].Status)
 }
 
 func TestDeleteApplication(t *testing.T) {
@@ -401,6 +620,55 @@ func TestDeleteApplication(t *testing.T) {
 	assert.True(t, errors.IsNotFound(err))
 }
 
+// =============================================================================
+// Backup Handler Tests
+// =============================================================================
+
+func TestBackupAndRestore(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	err := srv.store.CreateApplication(&core.Application{ID: "keep", Name: "Keep", Image: "nginx"})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/backup", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	snapshot := w.Body.Bytes()
+
+	err = srv.store.DeleteApplication("keep")
+	require.NoError(t, err)
+
+	body, err := json.Marshal(BackupRestoreRequest{Snapshot: snapshot})
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/backup/restore", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	app, err := srv.store.GetApplication("keep")
+	require.NoError(t, err)
+	assert.Equal(t, "Keep", app.Name)
+
+	// Garbage is rejected without touching the data
+	body, err = json.Marshal(BackupRestoreRequest{Snapshot: []byte("garbage")})
+	require.NoError(t, err)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/backup/restore", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	_, err = srv.store.GetApplication("keep")
+	assert.NoError(t, err)
+}
+
 // =============================================================================
 // Health Check Tests
 // =============================================================================
@@ -447,6 +715,38 @@ func TestReadyzEndpoint(t *testing.T) {
 	assert.Equal(t, "unhealthy", status.Checks["podman"].Status)
 }
 
+func TestReadOnlyWithoutEngine(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store.WithContext(context.Background())
+	require.NoError(t, st.CreateApplication(&core.Application{ID: "app-1", Name: "web", Image: "nginx", Status: "running"}))
+
+	up := false
+	srv.SetEngineCheck(func() bool { return up })
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return nil, errors.NewUnavailableError("podman", "container engine is not connected yet")
+	}
+
+	// Stored data is still served, with the recorded status
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/applications", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var apps []core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apps))
+	require.Len(t, apps, 1)
+	assert.Equal(t, "running", apps[0].Status)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/teams", map[string]string{"name": "payments"})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "changes are refused")
+
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/logging/level", map[string]string{"level": logger.Level()})
+	assert.Equal(t, http.StatusOK, w.Code, "the log level can still be changed")
+
+	up = true
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/teams", map[string]string{"name": "payments"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
 // =============================================================================
 // Middleware Tests
 // =============================================================================
@@ -495,6 +795,12 @@ func TestRequireJSONContentType(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 		})
 	}
+
+	// Actions without a body need no content type
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/domains/missing/verify", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
 func TestSecurityHeaders(t *testing.T) {
@@ -510,6 +816,58 @@ func TestSecurityHeaders(t *testing.T) {
 	assert.Equal(t, "1; mode=block", w.Header().Get("X-XSS-Protection"))
 }
 
+func TestRecoverer(t *testing.T) {
+	h := Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/apps", http.NoBody)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var resp ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, errors.CodeInternal, resp.Error.Code)
+}
+
+func TestRealIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8"), netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{name: "untrusted peer keeps socket address", remote: "203.0.113.7:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4"}, want: "203.0.113.7:5000"},
+		{name: "trusted peer without headers", remote: "127.0.0.1:5000", want: "127.0.0.1:5000"},
+		{name: "forwarded for", remote: "127.0.0.1:5000", headers: map[string]string{"X-Forwarded-For": "198.51.100.9"}, want: "198.51.100.9"},
+		{name: "spoofed entries left of the client are ignored", remote: "127.0.0.1:5000", headers: map[string]string{"X-Forwarded-For": "6.6.6.6, 198.51.100.9, 10.1.2.3"}, want: "198.51.100.9"},
+		{name: "real ip", remote: "[::ffff:127.0.0.1]:5000", headers: map[string]string{"X-Real-IP": "198.51.100.9"}, want: "198.51.100.9"},
+		{name: "invalid header", remote: "127.0.0.1:5000", headers: map[string]string{"X-Real-IP": "nonsense"}, want: "127.0.0.1:5000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestNoCacheHeaders(t *testing.T) {
 	srv, _, cleanup := setupTestServer(t)
 	defer cleanup()
@@ -564,6 +922,24 @@ func TestMapErrorToResponse(t *testing.T) {
 			expectedStatus: http.StatusForbidden,
 			expectedCode:   errors.CodePermissionDenied,
 		},
+		{
+			name:           "timeout error",
+			err:            errors.NewTimeoutError("engine call timed out"),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   errors.CodeTimeout,
+		},
+		{
+			name:           "unavailable error",
+			err:            errors.NewUnavailableError("podman", "socket unreachable"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   errors.CodeUnavailable,
+		},
+		{
+			name:           "conflict state error",
+			err:            errors.NewConflictStateError("pod", "pod-1", "pod has applications"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   errors.CodeConflictState,
+		},
 	}
 
 	for _, tt := range tests {
@@ -575,6 +951,18 @@ func TestMapErrorToResponse(t *testing.T) {
 	}
 }
 
+func TestMapErrorToResponseStack(t *testing.T) {
+	errors.SetStackCapture(true)
+	defer errors.SetStackCapture(false)
+
+	_, response := mapErrorToResponse(errors.NewInternalError("something went wrong"))
+	assert.Contains(t, response.Error.Stack, "TestMapErrorToResponseStack")
+
+	errors.SetStackCapture(false)
+	_, response = mapErrorToResponse(errors.NewInternalError("something went wrong"))
+	assert.Empty(t, response.Error.Stack)
+}
+
 // =============================================================================
 // Team Handler Tests (Basic coverage)
 // =============================================================================
@@ -799,3 +1187,1972 @@ func TestGetPod(t *testing.T) {
 	srv.Router().ServeHTTP(w, req)
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestUpdatePod(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	err := srv.store.CreatePod(&core.Pod{Name: "web-pod", ID: "pod-1", Ports: map[string]string{"8080": "80"}})
+	require.NoError(t, err)
+
+	update := func(id string, body map[string]any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/pods/"+id, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := update("pod-1", map[string]any{"ports": map[string]string{"9090": "80", "9443": "443"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	stored, err := srv.store.GetPod("pod-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"9090": "80", "9443": "443"}, stored.Ports)
+	assert.Equal(t, "web-pod", stored.Name)
+
+	// Renaming is rejected
+	w = update("pod-1", map[string]any{"name": "other", "ports": map[string]string{}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Unknown pod
+	w = update("missing", map[string]any{"ports": map[string]string{}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPodSpec(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	require.NoError(t, srv.store.CreateNetwork(&core.Network{ID: "net-1", Name: "backend"}))
+	require.NoError(t, srv.store.CreateVolume(&core.Volume{ID: "vol-1", Name: "shared"}))
+
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/pods", map[string]any{
+		"name":        "stack",
+		"hostname":    "stack",
+		"infra_image": "registry.k8s.io/pause:3.9",
+		"network_id":  "net-1",
+		"volumes":     []map[string]any{{"name": "shared", "path": "/shared"}},
+		"resources":   map[string]any{"cpus": 1.5, "memory_bytes": 256 << 20},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var pod core.Pod
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pod))
+	checksum := pod.SpecChecksum()
+	assert.NotEmpty(t, checksum)
+
+	stored, err := srv.store.GetPod(pod.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "net-1", stored.NetworkID)
+	assert.Equal(t, []core.VolumeMount{{Name: "shared", Path: "/shared"}}, stored.Volumes)
+
+	// The checksum follows the spec, so the reconciler recreates the pod
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/pods/"+pod.ID, map[string]any{"hostname": "other"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	stored, err = srv.store.GetPod(pod.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "other", stored.Hostname)
+	assert.NotEqual(t, checksum, stored.SpecChecksum())
+	assert.Empty(t, stored.Volumes, "updates replace the whole spec")
+
+	// The volume cannot be deleted while a pod mounts it
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/pods/"+pod.ID, map[string]any{
+		"volumes": []map[string]any{{"name": "shared", "path": "/shared"}},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusConflict, sendJSON(t, srv, http.MethodDelete, "/api/v1/volumes/vol-1", nil).Code)
+
+	for name, body := range map[string]map[string]any{
+		"unknown network":   {"name": "p", "network_id": "missing"},
+		"invalid hostname":  {"name": "p", "hostname": "not a host"},
+		"relative volume":   {"name": "p", "volumes": []map[string]any{{"name": "shared", "path": "shared"}}},
+		"negative resource": {"name": "p", "resources": map[string]any{"cpus": -1}},
+	} {
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/pods", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	mock.DiskUsageFunc = func(ctx context.Context) (*container.DiskUsage, error) {
+		return &container.DiskUsage{Volumes: container.DiskUsageSummary{Size: 1400, Reclaimable: 400, Total: 2, Active: 1}}, nil
+	}
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/system/df", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var usage client.DiskUsage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, client.DiskUsageSummary{Size: 1400, Reclaimable: 400, Total: 2, Active: 1}, usage.Volumes)
+
+	mock.DiskUsageFunc = func(ctx context.Context) (*container.DiskUsage, error) {
+		return nil, errors.NewUnavailableError("podman", "container engine is not connected yet")
+	}
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/system/df", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestSystemInfo(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	mock.SystemInfoFunc = func(ctx context.Context) (*container.SystemInfo, error) {
+		return &container.SystemInfo{Version: "5.2.0", StorageDriver: "overlay", OS: "linux", MemTotal: 8 << 30, MemFree: 1 << 30, CPUs: 4}, nil
+	}
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/system/info", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var info client.SystemInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, client.SystemInfo{Version: "5.2.0", StorageDriver: "overlay", OS: "linux", MemTotal: 8 << 30, MemFree: 1 << 30, CPUs: 4}, info)
+
+	mock.SystemInfoFunc = func(ctx context.Context) (*container.SystemInfo, error) {
+		return nil, errors.NewUnavailableError("podman", "container engine is not connected yet")
+	}
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/system/info", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestLogLevel(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	previous := logger.Level()
+	defer logger.SetLevel(previous) //nolint:errcheck // restoring a level read from the logger
+
+	setLevel := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/logging/level", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := setLevel(`{"level":"warn"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "warn", logger.Level())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/logging/level", http.NoBody)
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"warn"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, setLevel(`{"level":"loud"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, setLevel(`{}`).Code)
+	assert.Equal(t, "warn", logger.Level())
+}
+
+func TestDeletePodInUse(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	require.NoError(t, srv.store.CreatePod(&core.Pod{ID: "pod-1", Name: "stack"}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "web", Name: "web", Image: "nginx", PodID: "pod-1"}))
+
+	deletePod := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/pods/pod-1", http.NoBody)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	w := deletePod()
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), errors.CodeConflictState)
+
+	require.NoError(t, srv.store.DeleteApplication("web"))
+	assert.Equal(t, http.StatusNoContent, deletePod().Code)
+}
+
+// sendJSON serves a request with body encoded as JSON, or no body when nil
+func sendJSON(t *testing.T, srv *Server, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return sendJSONWithKey(t, srv, "", method, path, body)
+}
+
+// sendJSONWithKey is sendJSON with key as the bearer token, when set
+func sendJSONWithKey(t *testing.T, srv *Server, key, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+// createTestAPIKey creates a user with an API key and returns the key
+func createTestAPIKey(t *testing.T, srv *Server) string {
+	t.Helper()
+	st := srv.store.WithContext(context.Background())
+	user := &core.User{Username: "alice"}
+	require.NoError(t, st.CreateUser(user))
+	key := &core.APIKey{UserID: user.ID, Name: "test"}
+	require.NoError(t, st.CreateAPIKey(key))
+	return key.Key
+}
+
+func TestSecretCRUD(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+
+	w := send(http.MethodPost, "/api/v1/secrets", map[string]string{"name": "db-password"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "a value is required")
+
+	w = send(http.MethodPost, "/api/v1/secrets", map[string]string{"name": "db-password", "value": "hunter2"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "hunter2", "values are write-only")
+
+	var secret core.Secret
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &secret))
+	require.NotEmpty(t, secret.ID)
+
+	w = send(http.MethodGet, "/api/v1/secrets", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "db-password")
+	assert.NotContains(t, w.Body.String(), "hunter2")
+
+	// Applications reference secrets by name, as an env var or a file
+	app := map[string]any{
+		"name":    "api",
+		"image":   "myapp",
+		"secrets": []map[string]string{{"name": "db-password", "env": "DB PASSWORD"}},
+	}
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/applications", app).Code)
+	app["secrets"] = []map[string]string{{"name": "db-password", "env": "DB_PASSWORD", "file": "/run/secrets/db"}}
+	w = send(http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// Updating the value gives the application a new revision
+	time.Sleep(time.Millisecond)
+	w = send(http.MethodPut, "/api/v1/secrets/"+secret.ID, map[string]string{"value": "correct-horse"})
+	require.Equal(t, http.StatusOK, w.Code)
+	value, err := srv.store.SecretValue("db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "correct-horse", value)
+	updated, err := srv.store.GetApplication(created.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, created.Revision(), updated.Revision())
+
+	// Secrets in use cannot be deleted
+	w = send(http.MethodDelete, "/api/v1/secrets/"+secret.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	require.NoError(t, srv.store.DeleteApplication(created.ID))
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/secrets/"+secret.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/secrets/"+secret.ID, nil).Code)
+}
+
+func TestVolumeCRUD(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+
+	w := send(http.MethodPost, "/api/v1/volumes", map[string]any{"name": "pgdata", "size": "lots"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = send(http.MethodPost, "/api/v1/volumes", map[string]any{
+		"name":   "pgdata",
+		"size":   "10GiB",
+		"backup": map[string]any{"schedule": "daily", "retain": 7},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var volume core.Volume
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &volume))
+	assert.Equal(t, core.BackupDaily, volume.Backup.Schedule)
+
+	assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/api/v1/volumes", map[string]any{"name": "pgdata"}).Code)
+
+	w = send(http.MethodPut, "/api/v1/volumes/"+volume.ID, map[string]any{"size": "20GiB", "backup": map[string]any{"schedule": "hourly"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = send(http.MethodPut, "/api/v1/volumes/"+volume.ID, map[string]any{"size": "20GiB"})
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err := srv.store.GetVolume(volume.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "20GiB", got.Size)
+	assert.Equal(t, "pgdata", got.Name)
+
+	// Attachments need distinct absolute paths
+	app := map[string]any{
+		"name":    "db",
+		"image":   "postgres",
+		"volumes": []map[string]any{{"name": "pgdata", "path": "data"}},
+	}
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/applications", app).Code)
+
+	// Bind mounts take an absolute host path instead of a volume name
+	for _, m := range []map[string]any{
+		{"source": "initdb", "path": "/docker-entrypoint-initdb.d"},
+		{"name": "pgdata", "source": "/srv/initdb", "path": "/docker-entrypoint-initdb.d"},
+		{"path": "/docker-entrypoint-initdb.d"},
+	} {
+		app["volumes"] = []map[string]any{m}
+		assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/applications", app).Code, "%v", m)
+	}
+
+	app["volumes"] = []map[string]any{
+		{"name": "pgdata", "path": "/var/lib/postgresql/data"},
+		{"source": "/srv/initdb", "path": "/docker-entrypoint-initdb.d", "read_only": true},
+	}
+	w = send(http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	// Attached volumes cannot be deleted
+	assert.Equal(t, http.StatusConflict, send(http.MethodDelete, "/api/v1/volumes/"+volume.ID, nil).Code)
+	require.NoError(t, srv.store.DeleteApplication(created.ID))
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/volumes/"+volume.ID, nil).Code)
+}
+
+func TestDeploymentsAndRollback(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", map[string]any{"name": "web", "image": "nginx:1.26"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var app core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+	first := app.Revision()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/applications/"+app.ID,
+		strings.NewReader(`{"name": "web", "image": "nginx:1.27", "env_vars": {"MODE": "new"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice@laptop")
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/applications/"+app.ID+"/deployments", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var history []core.Deployment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, "nginx:1.27", history[0].Image)
+	assert.Equal(t, "alice@laptop", history[0].Actor)
+	assert.Equal(t, "api", history[1].Actor)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/applications/"+app.ID+"/rollback/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/applications/"+app.ID+"/rollback/"+first, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var restored core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "nginx:1.26", restored.Image)
+	assert.Empty(t, restored.EnvVars)
+	assert.NotEqual(t, first, restored.Revision(), "a rollback is a new revision")
+
+	history, err := srv.store.ListDeployments(app.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, first, history[0].RollbackOf)
+
+	// Deleting the application drops its history
+	assert.Equal(t, http.StatusNoContent, sendJSON(t, srv, http.MethodDelete, "/api/v1/applications/"+app.ID, nil).Code)
+	history, err = srv.store.ListDeployments(app.ID)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestUsersAndAPIKeys(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	// Users and keys are managed from the server's host, as the CLI does
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader io.Reader = http.NoBody
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "127.0.0.1:40000"
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	// Other hosts need an API key to create users or keys
+	assert.Equal(t, http.StatusForbidden, sendJSON(t, srv, http.MethodPost, "/api/v1/users", map[string]any{"username": "mallory"}).Code)
+	owner := &core.User{Username: "admin"}
+	require.NoError(t, srv.store.CreateUser(owner))
+	admin := &core.APIKey{UserID: owner.ID, Name: "admin"}
+	require.NoError(t, srv.store.CreateAPIKey(admin))
+	w := sendJSONWithKey(t, srv, admin.Key, http.MethodPost, "/api/v1/users", map[string]any{"username": "bob"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var bob core.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bob))
+	assert.Equal(t, http.StatusForbidden, sendJSON(t, srv, http.MethodPost, "/api/v1/users/"+bob.ID+"/apikeys", map[string]any{"name": "stolen"}).Code)
+	assert.Equal(t, http.StatusForbidden, sendJSONWithKey(t, srv, "smp_invalid", http.MethodDelete, "/api/v1/users/"+bob.ID, nil).Code)
+
+	// whoami checks the key even from this host
+	w = sendJSONWithKey(t, srv, admin.Key, http.MethodGet, "/api/v1/whoami", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var me WhoAmIResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &me))
+	assert.Equal(t, "admin", me.User.Username)
+	assert.Equal(t, admin.ID, me.APIKey.ID)
+	assert.Empty(t, me.APIKey.Key)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/whoami", nil).Code)
+	assert.Equal(t, http.StatusForbidden, sendJSONWithKey(t, srv, "smp_invalid", http.MethodGet, "/api/v1/whoami", nil).Code)
+
+	w = send(http.MethodPost, "/api/v1/teams", map[string]any{"name": "Platform"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var team core.Team
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &team))
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/users", map[string]any{"username": "Alice Smith"}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/users", map[string]any{
+		"username": "alice",
+		"teams":    []map[string]any{{"team_id": team.ID, "role": "root"}},
+	}).Code)
+
+	w = send(http.MethodPost, "/api/v1/users", map[string]any{"username": "alice", "email": "alice@example.com"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var user core.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+	assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/api/v1/users", map[string]any{"username": "alice"}).Code)
+
+	// Team membership
+	membersPath := "/api/v1/teams/" + team.ID + "/members"
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPut, "/api/v1/teams/missing/members/"+user.ID, map[string]any{}).Code)
+	w = send(http.MethodPut, membersPath+"/"+user.ID, map[string]any{"role": core.RoleOwner})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = send(http.MethodGet, membersPath, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var members []core.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &members))
+	require.Len(t, members, 1)
+	assert.Equal(t, core.RoleOwner, members[0].Role(team.ID))
+
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, membersPath+"/"+user.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, membersPath+"/"+user.ID, nil).Code)
+
+	// API keys are returned once, then only listed by prefix
+	keysPath := "/api/v1/users/" + user.ID + "/apikeys"
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, keysPath, map[string]any{}).Code)
+	w = send(http.MethodPost, keysPath, map[string]any{"name": "laptop"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var key core.APIKey
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+	assert.NotEmpty(t, key.Key)
+
+	w = send(http.MethodGet, keysPath, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), key.Key)
+	assert.Contains(t, w.Body.String(), key.Prefix)
+
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, keysPath+"/"+key.ID, nil).Code)
+	_, _, err := srv.store.AuthenticateAPIKey(key.Key)
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/users/"+user.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/users/"+user.ID, nil).Code)
+}
+
+// fakeResolver answers TXT lookups from a map
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	values, ok := f[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return values, nil
+}
+
+func TestDomains(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+	resolver := fakeResolver{}
+	srv.resolver = resolver
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+
+	w := send(http.MethodPost, "/api/v1/applications", map[string]any{"name": "web", "image": "nginx"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var app core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "localhost", "application_id": app.ID}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "*.example.com", "application_id": app.ID}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "app.example.com", "application_id": app.ID, "tls": "maybe"}).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "app.example.com", "application_id": "missing"}).Code)
+
+	w = send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "App.Example.com.", "application_id": app.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var domain core.Domain
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &domain))
+	assert.Equal(t, "app.example.com", domain.Hostname)
+	assert.Equal(t, core.TLSAuto, domain.TLS)
+	assert.Equal(t, core.DomainPending, domain.Status)
+	assert.NotEmpty(t, domain.VerificationToken)
+
+	assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/api/v1/domains", map[string]any{"hostname": "app.example.com", "application_id": app.ID}).Code)
+
+	// Without the TXT record verification fails, but the request succeeds
+	w = send(http.MethodPost, "/api/v1/domains/"+domain.ID+"/verify", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &domain))
+	assert.Equal(t, core.DomainFailed, domain.Status)
+	assert.NotEmpty(t, domain.StatusMessage)
+
+	resolver[domain.VerificationRecord()] = []string{"unrelated", domain.VerificationToken}
+	w = send(http.MethodPost, "/api/v1/domains/"+domain.ID+"/verify", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &domain))
+	assert.Equal(t, core.DomainVerified, domain.Status)
+	assert.NotNil(t, domain.VerifiedAt)
+
+	// Verified and routed to an application without a health check
+	w = send(http.MethodGet, "/api/v1/domains/"+domain.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &domain))
+	assert.True(t, domain.Ready)
+
+	w = send(http.MethodPut, "/api/v1/domains/"+domain.ID, map[string]any{"tls": core.TLSOff, "port": 8080})
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err := srv.store.GetDomain(domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, core.TLSOff, got.TLS)
+	assert.Equal(t, 8080, got.Port)
+	assert.Equal(t, core.DomainVerified, got.Status)
+
+	// Deleting the application removes its domains
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/applications/"+app.ID, nil).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/domains/"+domain.ID, nil).Code)
+}
+
+func TestTemplates(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+
+	w := send(http.MethodGet, "/api/v1/templates", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"postgres"`)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/templates/missing", nil).Code)
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/templates/postgres/instantiate", map[string]any{}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/templates/ghost/instantiate", map[string]any{"name": "blog"}).Code)
+
+	w = send(http.MethodPost, "/api/v1/templates/postgres/instantiate", map[string]any{
+		"name":   "db",
+		"values": map[string]string{"POSTGRES_DB": "shop"},
+		"ports":  map[string]string{"15432": "5432"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp InstantiateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	app := resp.Application
+	assert.Equal(t, "docker.io/library/postgres:17", app.Image)
+	assert.Equal(t, map[string]string{"15432": "5432"}, app.Ports)
+	assert.Equal(t, "shop", app.EnvVars["POSTGRES_DB"])
+	assert.NotContains(t, app.EnvVars, "POSTGRES_PASSWORD")
+	assert.Equal(t, []core.SecretRef{{Name: "db-postgres-password", Env: "POSTGRES_PASSWORD"}}, app.Secrets)
+	assert.Equal(t, []core.VolumeMount{{Name: "db-data", Path: "/var/lib/postgresql/data"}}, app.Volumes)
+	require.NotEmpty(t, resp.Generated["POSTGRES_PASSWORD"])
+
+	// The generated password is only stored as a secret
+	value, err := srv.store.SecretValue("db-postgres-password")
+	require.NoError(t, err)
+	assert.Equal(t, resp.Generated["POSTGRES_PASSWORD"], value)
+	_, err = srv.store.GetApplication(app.ID)
+	require.NoError(t, err)
+
+	// A second instance with the same name is rejected before creating anything
+	w = send(http.MethodPost, "/api/v1/templates/postgres/instantiate", map[string]any{"name": "db"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	volumes, err := srv.store.ListVolumes()
+	require.NoError(t, err)
+	assert.Len(t, volumes, 1)
+}
+
+func TestStacks(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+	createApp := func(name string) core.Application {
+		w := send(http.MethodPost, "/api/v1/applications", map[string]any{"name": name, "image": "nginx"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var app core.Application
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+		return app
+	}
+	db, web, worker := createApp("db"), createApp("web"), createApp("worker")
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/stacks", map[string]any{"name": "shop", "application_ids": []string{db.ID, db.ID}}).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/stacks", map[string]any{"name": "shop", "application_ids": []string{"missing"}}).Code)
+
+	w := send(http.MethodPost, "/api/v1/stacks", map[string]any{"name": "shop", "application_ids": []string{db.ID, web.ID}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var stack core.Stack
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stack))
+	network, err := srv.store.GetNetwork(stack.NetworkID)
+	require.NoError(t, err)
+	assert.Equal(t, "shop_default", network.Name)
+
+	got, err := srv.store.GetApplication(web.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stack.ID, got.StackID)
+	assert.Equal(t, stack.NetworkID, got.NetworkID)
+
+	// An application belongs to one stack at a time
+	w = send(http.MethodPost, "/api/v1/stacks", map[string]any{"name": "other", "application_ids": []string{db.ID}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Updating an application does not drop its membership
+	w = send(http.MethodPut, "/api/v1/applications/"+web.ID, map[string]any{"name": "web", "image": "nginx:1.27", "network_id": stack.NetworkID})
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err = srv.store.GetApplication(web.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stack.ID, got.StackID)
+
+	// Stop and start every member
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/stacks/"+stack.ID+"/stop", nil).Code)
+	got, err = srv.store.GetApplication(db.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Stopped)
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/stacks/"+stack.ID+"/deploy", nil).Code)
+	got, err = srv.store.GetApplication(db.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Stopped)
+
+	// Replace web with worker; web is detached and leaves the network
+	w = send(http.MethodPut, "/api/v1/stacks/"+stack.ID, map[string]any{"application_ids": []string{worker.ID, db.ID}})
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err = srv.store.GetApplication(web.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.StackID)
+	assert.Empty(t, got.NetworkID)
+
+	// Deleting a member removes it from the stack
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/applications/"+worker.ID, nil).Code)
+	current, err := srv.store.GetStack(stack.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{db.ID}, current.ApplicationIDs)
+
+	// Deleting the stack removes its applications, their links and the network
+	require.NoError(t, srv.store.CreateLink(&core.Link{From: web.ID, To: db.ID, Type: core.LinkDependsOn}))
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/stacks/"+stack.ID, nil).Code)
+	_, err = srv.store.GetApplication(db.ID)
+	assert.True(t, errors.IsNotFound(err))
+	links, err := srv.store.ListLinks()
+	require.NoError(t, err)
+	assert.Empty(t, links)
+	_, err = srv.store.GetNetwork(stack.NetworkID)
+	assert.True(t, errors.IsNotFound(err))
+	_, err = srv.store.GetApplication(web.ID)
+	assert.NoError(t, err)
+}
+
+func TestNodes(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+	sendAs := func(node core.Node, method, path string, body any) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+node.Token)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+	register := func(name string, labels map[string]string) core.Node {
+		w := send(http.MethodPost, "/api/v1/nodes", map[string]any{"name": name, "labels": labels, "join_token": testJoinToken, "capacity": map[string]any{"cpus": 2}})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var node core.Node
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &node))
+		require.NotEmpty(t, node.Token)
+		return node
+	}
+	getApp := func(id string) core.Application {
+		w := send(http.MethodGet, "/api/v1/applications/"+id, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var app core.Application
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+		return app
+	}
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/nodes", map[string]any{"name": "", "join_token": testJoinToken}).Code)
+
+	// Registering needs the join token
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/nodes", map[string]any{"name": "eu-1"}).Code)
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/nodes", map[string]any{"name": "eu-1", "join_token": "wrong"}).Code)
+
+	eu := register("eu-1", map[string]string{"region": "eu"})
+
+	// Re-registering a name needs the node's current token, which is replaced
+	rejoin := RegisterNodeRequest{Name: "eu-1", Labels: eu.Labels, JoinToken: testJoinToken}
+	assert.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/nodes", rejoin).Code)
+	assert.Equal(t, http.StatusForbidden, sendAs(core.Node{Token: "smn_wrong"}, http.MethodPost, "/api/v1/nodes", rejoin).Code)
+	w := sendAs(eu, http.MethodPost, "/api/v1/nodes", rejoin)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var rejoined core.Node
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rejoined))
+	assert.Equal(t, eu.ID, rejoined.ID)
+	assert.NotEqual(t, eu.Token, rejoined.Token)
+	eu.Token = rejoined.Token
+
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/secrets", map[string]any{"name": "api-key", "value": "s3cret"}).Code)
+
+	// An application without a selector runs on the server
+	w = send(http.MethodPost, "/api/v1/applications", map[string]any{"name": "local", "image": "nginx"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var local core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &local))
+	assert.Empty(t, local.NodeID)
+
+	w = send(http.MethodPost, "/api/v1/applications", map[string]any{
+		"name": "api", "image": "myapp", "node_selector": map[string]string{"region": "eu"},
+		"secrets": []map[string]string{{"name": "api-key", "env": "API_KEY"}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var api core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &api))
+	assert.Equal(t, eu.ID, api.NodeID)
+
+	// No node matches yet, so the application waits
+	w = send(http.MethodPost, "/api/v1/applications", map[string]any{"name": "batch", "image": "myapp", "node_selector": map[string]string{"region": "us"}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var batch core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &batch))
+	assert.Empty(t, batch.NodeID)
+	assert.Equal(t, statusPending, getApp(batch.ID).Status)
+
+	// Heartbeats and state need the node's token
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, "/api/v1/nodes/"+eu.ID+"/state", nil).Code)
+	assert.Equal(t, http.StatusNotFound, sendAs(core.Node{Token: "smn_wrong"}, http.MethodGet, "/api/v1/nodes/"+eu.ID+"/state", nil).Code)
+
+	w = sendAs(eu, http.MethodPost, "/api/v1/nodes/"+eu.ID+"/heartbeat", HeartbeatRequest{
+		AppStatus: map[string]string{api.ID: "running"},
+		Capacity:  core.NodeCapacity{CPUs: 2},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "running", getApp(api.ID).Status)
+
+	w = sendAs(eu, http.MethodGet, "/api/v1/nodes/"+eu.ID+"/state", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var state core.NodeState
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &state))
+	require.Len(t, state.Applications, 1)
+	assert.Equal(t, api.ID, state.Applications[0].ID)
+	require.Len(t, state.Secrets, 1)
+	assert.Equal(t, "s3cret", state.Secrets[0].Value)
+
+	// A matching node registering takes the waiting application
+	us := register("us-1", map[string]string{"region": "us"})
+	assert.Equal(t, us.ID, getApp(batch.ID).NodeID)
+
+	// Updates keep the placement
+	w = send(http.MethodPut, "/api/v1/applications/"+api.ID, map[string]any{"name": "api", "image": "myapp:v2", "node_selector": map[string]string{"region": "eu"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, eu.ID, getApp(api.ID).NodeID)
+
+	w = send(http.MethodGet, "/api/v1/nodes", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var nodes []core.Node
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &nodes))
+	require.Len(t, nodes, 2)
+	for _, n := range nodes {
+		assert.Equal(t, core.NodeReady, n.Status)
+		assert.Empty(t, n.Token)
+	}
+
+	// Removing a node leaves its applications waiting for another
+	assert.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/nodes/"+eu.ID, nil).Code)
+	assert.Empty(t, getApp(api.ID).NodeID)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/nodes/"+eu.ID, nil).Code)
+}
+
+func TestPickNode(t *testing.T) {
+	now := time.Now()
+	nodes := []core.Node{
+		{ID: "a", Name: "a", LastHeartbeat: now, Capacity: core.NodeCapacity{CPUs: 1}, Labels: map[string]string{"disk": "ssd"}},
+		{ID: "b", Name: "b", LastHeartbeat: now, Capacity: core.NodeCapacity{CPUs: 4}, Labels: map[string]string{"disk": "ssd"}},
+		{ID: "c", Name: "c", LastHeartbeat: now.Add(-2 * core.NodeHeartbeatTimeout), Capacity: core.NodeCapacity{CPUs: 8}, Labels: map[string]string{"disk": "ssd"}},
+	}
+	apps := []core.Application{{ID: "1", NodeID: "a"}, {ID: "2", NodeID: "b"}, {ID: "3", NodeID: "b"}}
+	app := &core.Application{ID: "new", NodeSelector: map[string]string{"disk": "ssd"}}
+
+	// b runs more applications but has more CPUs per application; c is not ready
+	assert.Equal(t, "b", pickNode(nodes, apps, app, now).ID)
+
+	// Ties go to the first name
+	assert.Equal(t, "a", pickNode(nodes, nil, app, now).ID)
+
+	app.NodeSelector = map[string]string{"disk": "hdd"}
+	assert.Nil(t, pickNode(nodes, apps, app, now))
+}
+
+// fakeBuilder blocks each build until a result is sent on results
+type fakeBuilder struct {
+	results chan error
+}
+
+func (f *fakeBuilder) Build(ctx context.Context, b *core.Build, out io.Writer) error {
+	fmt.Fprintln(out, "building", b.Image)
+	b.Commit = "abc123"
+	b.Strategy = core.BuildContainerfile
+	return <-f.results
+}
+
+func TestBuilds(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, method, path, body)
+	}
+	require.NoError(t, srv.store.CreateProject(&core.Project{ID: "shop", Name: "shop", RepoURL: "https://example.com/shop.git"}))
+	require.NoError(t, srv.store.CreateEnvironment(&core.Environment{ID: "prod", Name: "prod", ProjectID: "shop"}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "web", Name: "web", Image: "nginx", EnvironmentID: "prod"}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "loose", Name: "loose", Image: "nginx"}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, send(http.MethodPost, "/api/v1/applications/web/builds", nil).Code)
+
+	// Repositories that git could read as options are refused
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/projects", core.Project{Name: "evil", RepoURL: "--upload-pack=touch /tmp/pwned"}).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/api/v1/projects/shop", core.Project{Name: "shop", RepoURL: "-u"}).Code)
+
+	builder := &fakeBuilder{results: make(chan error)}
+	srv.SetBuilder(builder)
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/applications/loose/builds", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/applications/web/builds", map[string]any{"strategy": "nix"}).Code)
+	assert.Equal(t, http.StatusNotFound, send(http.MethodPost, "/api/v1/applications/missing/builds", nil).Code)
+
+	w := send(http.MethodPost, "/api/v1/applications/web/builds", map[string]any{"ref": "main"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var b core.Build
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &b))
+	assert.Equal(t, "https://example.com/shop.git", b.RepoURL)
+	assert.Equal(t, "main", b.Ref)
+	assert.True(t, strings.HasPrefix(b.Image, "localhost/simplify/web:"))
+
+	// One build at a time per application
+	assert.Equal(t, http.StatusConflict, send(http.MethodPost, "/api/v1/applications/web/builds", nil).Code)
+
+	builder.results <- nil
+	waitForBuild := func(id string) core.Build {
+		var got core.Build
+		require.Eventually(t, func() bool {
+			resp := send(http.MethodGet, "/api/v1/applications/web/builds/"+id, nil)
+			require.Equal(t, http.StatusOK, resp.Code)
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &got))
+			return got.Finished()
+		}, 5*time.Second, 10*time.Millisecond)
+		return got
+	}
+	got := waitForBuild(b.ID)
+	assert.Equal(t, core.BuildSucceeded, got.Status)
+	assert.Equal(t, "abc123", got.Commit)
+	assert.Contains(t, got.Log, "building "+b.Image)
+
+	app, err := srv.store.GetApplication("web")
+	require.NoError(t, err)
+	assert.Equal(t, b.Image, app.Image)
+	history, err := srv.store.ListDeployments("web")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	// A failed build leaves the application alone
+	w = send(http.MethodPost, "/api/v1/applications/web/builds", nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var failing core.Build
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &failing))
+	builder.results <- fmt.Errorf("exit code 1")
+	got = waitForBuild(failing.ID)
+	assert.Equal(t, core.BuildFailed, got.Status)
+	assert.Equal(t, "exit code 1", got.Error)
+	app, err = srv.store.GetApplication("web")
+	require.NoError(t, err)
+	assert.Equal(t, b.Image, app.Image)
+
+	w = send(http.MethodGet, "/api/v1/applications/web/builds", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list []core.Build
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, failing.ID, list[0].ID)
+	assert.Empty(t, list[0].Log)
+
+	assert.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/applications/loose/builds/"+b.ID, nil).Code)
+
+	// Deleting the application drops its builds
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/applications/web", nil).Code)
+	builds, err := srv.store.ListBuilds("web")
+	require.NoError(t, err)
+	assert.Empty(t, builds)
+}
+
+func TestPushHooks(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	builder := &fakeBuilder{results: make(chan error, 1)}
+	builder.results <- nil
+	srv.SetBuilder(builder)
+	var reconciles atomic.Int32
+	srv.SetReconcileTrigger(func() { reconciles.Add(1) })
+
+	require.NoError(t, srv.store.CreateSecret(&core.Secret{ID: "hook", Name: "shop-hook", Value: "s3cret"}))
+	require.NoError(t, srv.store.CreateProject(&core.Project{ID: "shop", Name: "shop", RepoURL: "git@github.com:acme/Shop.git", WebhookSecret: "shop-hook"}))
+	require.NoError(t, srv.store.CreateEnvironment(&core.Environment{ID: "prod", Name: "prod", ProjectID: "shop"}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "web", Name: "web", Image: "nginx", EnvironmentID: "prod", AutoDeploy: &core.AutoDeploy{Branch: "main"}}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "api", Name: "api", Image: "registry.example.com:5000/acme/api:v1", EnvironmentID: "prod", AutoDeploy: &core.AutoDeploy{Tags: true}}))
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "manual", Name: "manual", Image: "redis", EnvironmentID: "prod"}))
+
+	github := func(event, secret string, payload any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/hooks/github", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+	push := func(ref string) map[string]any {
+		return map[string]any{"ref": ref, "repository": map[string]any{"clone_url": "https://github.com/acme/shop.git"}}
+	}
+
+	assert.Equal(t, http.StatusForbidden, github("push", "wrong", push("refs/heads/main")).Code)
+	other := map[string]any{"ref": "refs/heads/main", "repository": map[string]any{"clone_url": "https://github.com/acme/other.git"}}
+	assert.Equal(t, http.StatusNotFound, github("push", "s3cret", other).Code)
+
+	w := github("ping", "s3cret", push(""))
+	require.Equal(t, http.StatusOK, w.Code)
+	var result HookResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.NotEmpty(t, result.Ignored)
+
+	// A push to the auto-deploy branch builds the application
+	w = github("push", "s3cret", push("refs/heads/main"))
+	require.Equal(t, http.StatusOK, w.Code)
+	result = HookResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Builds, 1)
+	require.Eventually(t, func() bool {
+		b, err := srv.store.GetBuild(result.Builds[0])
+		return err == nil && b.Finished()
+	}, 5*time.Second, 10*time.Millisecond)
+	b, err := srv.store.GetBuild(result.Builds[0])
+	require.NoError(t, err)
+	assert.Equal(t, "main", b.Ref)
+	app, err := srv.store.GetApplication("web")
+	require.NoError(t, err)
+	assert.Equal(t, b.Image, app.Image)
+	assert.Equal(t, int32(1), reconciles.Load())
+
+	// Other branches are ignored
+	w = github("push", "s3cret", push("refs/heads/feature"))
+	require.Equal(t, http.StatusOK, w.Code)
+	result = HookResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Empty(t, result.Builds)
+	assert.NotEmpty(t, result.Ignored)
+
+	// A GitLab tag push bumps the tag of applications that follow tags
+	body, err := json.Marshal(map[string]any{
+		"ref": "refs/tags/v2", "checkout_sha": "abc",
+		"project": map[string]any{"git_ssh_url": "git@github.com:acme/shop.git"},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/hooks/gitlab", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gitlab-Event", "Tag Push Hook")
+	req.Header.Set("X-Gitlab-Token", "s3cret")
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	result = HookResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"api"}, result.Updated)
+	app, err = srv.store.GetApplication("api")
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com:5000/acme/api:v2", app.Image)
+	assert.Equal(t, int32(2), reconciles.Load())
+	history, err := srv.store.ListDeployments("api")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "gitlab", history[0].Actor)
+
+	manual, err := srv.store.GetApplication("manual")
+	require.NoError(t, err)
+	assert.Equal(t, "redis", manual.Image)
+}
+
+func TestNormalizeRepoURL(t *testing.T) {
+	for _, raw := range []string{
+		"https://github.com/acme/shop.git",
+		"https://github.com/Acme/Shop",
+		"git@github.com:acme/shop.git",
+		"ssh://git@github.com:22/acme/shop.git",
+		"https://token@github.com/acme/shop/",
+	} {
+		assert.Equal(t, "github.com/acme/shop", normalizeRepoURL(raw), raw)
+		assert.NoError(t, validateRepoURL(raw), raw)
+	}
+	for _, raw := range []string{
+		"--upload-pack=touch /tmp/pwned",
+		"-u",
+		"file:///etc",
+		"http://github.com/acme/shop.git",
+		"/srv/git/shop.git",
+		"git@github.com",
+		"https:///acme/shop",
+		"https://github.com/acme/shop.git --bare",
+	} {
+		assert.Error(t, validateRepoURL(raw), raw)
+	}
+
+	assert.Equal(t, "nginx:v2", withTag("nginx", "v2"))
+	assert.Equal(t, "localhost:5000/web:v2", withTag("localhost:5000/web:v1", "v2"))
+	assert.Equal(t, "ghcr.io/acme/web:v2", withTag("ghcr.io/acme/web@sha256:abc", "v2"))
+}
+
+func TestMetrics(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	require.NoError(t, srv.store.CreateApplication(&core.Application{ID: "web", Name: "web", Image: "nginx"}))
+	get := func(path string) *httptest.ResponseRecorder {
+		return sendJSON(t, srv, http.MethodGet, path, nil)
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/applications/web/metrics").Code, "collection disabled")
+
+	srv.config.Metrics = config.MetricsConfig{Interval: 10, Retention: 24}
+	now := time.Now().UTC()
+	for i := range 180 {
+		at := now.Add(-time.Duration(i) * 10 * time.Second)
+		require.NoError(t, srv.store.RecordMetrics(map[string]core.MetricSample{
+			"web": {Time: at, CPUPercent: 10, MemoryBytes: 1 << 20, Replicas: 1},
+		}))
+	}
+
+	var result MetricsResponse
+	w := get("/api/v1/applications/web/metrics?range=5m")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result.Samples, 30)
+	assert.Equal(t, "5m0s", result.Range)
+	assert.Equal(t, "10s", result.Step)
+	assert.True(t, result.Samples[0].Time.Before(result.Samples[1].Time), "oldest first")
+
+	w = get("/api/v1/applications/web/metrics?step=5m")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "1h0m0s", result.Range, "default range")
+	assert.LessOrEqual(t, len(result.Samples), 7)
+	assert.InDelta(t, 10.0, result.Samples[0].CPUPercent, 0.001)
+
+	// A range too long for one sample per interval is averaged
+	w = get("/api/v1/applications/web/metrics?range=24h")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "4m0s", result.Step)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/applications/web/metrics?range=48h").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/applications/web/metrics?range=soon").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/applications/missing/metrics").Code)
+
+	w = sendJSON(t, srv, http.MethodDelete, "/api/v1/applications/web", nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	samples, err := srv.store.ListMetrics("web", now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+}
+
+// fakeBackups records calls to the stored backups API
+type fakeBackups struct {
+	restored string
+	backups  []backup.Backup
+	volumes  bool
+}
+
+func (f *fakeBackups) Run(ctx context.Context) (*backup.Backup, error) {
+	b := backup.Backup{Name: "simplify-20260101-030000", CreatedAt: time.Now()}
+	f.backups = append([]backup.Backup{b}, f.backups...)
+	return &b, nil
+}
+
+func (f *fakeBackups) List(ctx context.Context) ([]backup.Backup, error) {
+	return f.backups, nil
+}
+
+func (f *fakeBackups) Restore(ctx context.Context, name string, volumes bool) (*backup.Backup, error) {
+	for _, b := range f.backups {
+		if b.Name == name {
+			f.restored, f.volumes = name, volumes
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", backup.ErrNotFound, name)
+}
+
+func TestStoredBackups(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/backups", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "no target configured")
+
+	fake := &fakeBackups{}
+	srv.SetBackups(fake)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/backups", nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var list []backup.Backup
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/backups", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "simplify-20260101-030000", list[0].Name)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/backups/simplify-20260101-030000/restore", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, fake.volumes)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/backups/simplify-20260101-030000/restore", StoredRestoreRequest{Volumes: true})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "simplify-20260101-030000", fake.restored)
+	assert.True(t, fake.volumes)
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/backups/simplify-19700101-000000/restore", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestEnvironmentLimits(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	env := core.Environment{
+		ID:   "prod",
+		Name: "prod",
+		Limits: &core.EnvironmentLimits{
+			Default:  core.Resources{CPUs: 0.5, MemoryBytes: 256 << 20},
+			Max:      core.Resources{CPUs: 2, MemoryBytes: 1 << 30},
+			MaxPorts: 1,
+		},
+	}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/environments", env)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	invalid := env
+	invalid.Limits = &core.EnvironmentLimits{Default: core.Resources{CPUs: 4}, Max: core.Resources{CPUs: 2}}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/environments/prod", invalid)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "default above the maximum")
+	invalid.Limits = &core.EnvironmentLimits{MaxPorts: -1}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/environments/prod", invalid)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	app := core.Application{Name: "web", Image: "nginx", EnvironmentID: "prod"}
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code, "defaults apply")
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, core.Resources{CPUs: 0.5, MemoryBytes: 256 << 20}, created.EffectiveResources(&env))
+
+	created.Resources = &core.Resources{CPUs: 4}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "exceeds the maximum of 2")
+
+	created.Resources = &core.Resources{MemoryBytes: 1 << 30}
+	created.Ports = map[string]string{"8080": "80", "8443": "443"}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusBadRequest, w.Code, "too many ports")
+
+	created.Ports = map[string]string{"8080": "80"}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	created.Resources = &core.Resources{CPUs: -1}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Applications without an environment, or of an unknown one, are unbounded
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/applications",
+		core.Application{Name: "big", Image: "nginx", EnvironmentID: "missing", Resources: &core.Resources{CPUs: 16}})
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCloneEnvironment(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store
+	require.NoError(t, st.CreateEnvironment(&core.Environment{ID: "prod", Name: "prod", Config: map[string]string{"region": "eu"}}))
+	require.NoError(t, st.CreateVolume(&core.Volume{ID: "v1", Name: "pgdata", Size: "10GiB"}))
+	require.NoError(t, st.CreateApplication(&core.Application{
+		ID: "db", Name: "db", Image: "postgres", EnvironmentID: "prod", PodID: "pod-1",
+		Ports:   map[string]string{"127.0.0.1:5432": "5432"},
+		Volumes: []core.VolumeMount{{Name: "pgdata", Path: "/var/lib/postgresql/data"}},
+	}))
+	require.NoError(t, st.CreateApplication(&core.Application{
+		ID: "web", Name: "web", Image: "nginx", EnvironmentID: "prod",
+		Ports: map[string]string{"8080": "80"}, EnvVars: map[string]string{"API_TOKEN": "s3cret"},
+	}))
+	require.NoError(t, st.CreateApplication(&core.Application{ID: "other", Name: "other", Image: "nginx"}))
+	require.NoError(t, st.CreateLink(&core.Link{From: "web", To: "db", Type: core.LinkDependsOn}))
+	require.NoError(t, st.CreateDomain(&core.Domain{ID: "d1", Hostname: "shop.example.com", ApplicationID: "web", Status: core.DomainVerified}))
+	require.NoError(t, st.CreateDomain(&core.Domain{ID: "d2", Hostname: "example.com", ApplicationID: "web", Status: core.DomainVerified}))
+
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/environments/prod/clone", CloneEnvironmentRequest{Name: "staging", PortOffset: 1000})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp CloneEnvironmentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	assert.Equal(t, "staging", resp.Environment.Name)
+	assert.Equal(t, map[string]string{"region": "eu"}, resp.Environment.Config)
+	require.Len(t, resp.Applications, 2)
+	clones := make(map[string]core.Application)
+	for _, app := range resp.Applications {
+		clones[app.Name] = app
+		assert.Equal(t, resp.Environment.ID, app.EnvironmentID)
+	}
+	assert.Equal(t, map[string]string{"127.0.0.1:6432": "5432"}, clones["db-staging"].Ports)
+	assert.Empty(t, clones["db-staging"].PodID, "pods are not copied")
+	assert.Equal(t, "pgdata-staging", clones["db-staging"].Volumes[0].Name)
+	assert.Equal(t, map[string]string{"9080": "80"}, clones["web-staging"].Ports)
+	assert.Equal(t, core.RedactedValue, clones["web-staging"].EnvVars["API_TOKEN"])
+
+	volumes, err := st.ListVolumes()
+	require.NoError(t, err)
+	sizes := make(map[string]string)
+	for _, v := range volumes {
+		sizes[v.Name] = v.Size
+	}
+	assert.Equal(t, map[string]string{"pgdata": "10GiB", "pgdata-staging": "10GiB"}, sizes)
+
+	require.Len(t, resp.Domains, 2)
+	hostnames := make([]string, 0, len(resp.Domains))
+	for _, d := range resp.Domains {
+		hostnames = append(hostnames, d.Hostname)
+		assert.Equal(t, core.DomainPending, d.Status)
+		assert.Equal(t, clones["web-staging"].ID, d.ApplicationID)
+	}
+	// Apex domains get the suffix as a subdomain rather than a new domain
+	assert.ElementsMatch(t, []string{"shop-staging.example.com", "staging.example.com"}, hostnames)
+
+	links, err := st.ListLinks()
+	require.NoError(t, err)
+	pairs := make(map[string]string)
+	for _, l := range links {
+		pairs[l.From] = l.To
+	}
+	assert.Equal(t, clones["db-staging"].ID, pairs[clones["web-staging"].ID])
+
+	stored, err := st.GetApplication(clones["web-staging"].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", stored.EnvVars["API_TOKEN"], "only the response is redacted")
+
+	// Taken names fail before anything is created
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/environments/prod/clone", CloneEnvironmentRequest{Name: "staging"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	envs, err := st.ListEnvironments()
+	require.NoError(t, err)
+	assert.Len(t, envs, 2)
+
+	// Without an offset the copies publish no ports
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/environments/prod/clone", CloneEnvironmentRequest{Name: "preview", NameSuffix: "-pr1"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var preview CloneEnvironmentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	for _, app := range preview.Applications {
+		assert.Empty(t, app.Ports, app.Name)
+	}
+
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/environments/prod/clone", CloneEnvironmentRequest{Name: "x", PortOffset: 60000})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/environments/missing/clone", CloneEnvironmentRequest{Name: "x"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCloneHostname(t *testing.T) {
+	tests := []struct {
+		hostname, suffix, want string
+	}{
+		{"shop.example.com", "-staging", "shop-staging.example.com"},
+		{"api.eu.example.com", "-pr1", "api-pr1.eu.example.com"},
+		{"example.com", "-staging", "staging.example.com"},
+		{"example.co.uk", "-staging", "staging.example.co.uk"},
+		{"shop.example.co.uk", "-staging", "shop-staging.example.co.uk"},
+		{"example.com", ".preview", "preview.example.com"},
+		{"localhost", "-dev", "dev.localhost"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, cloneHostname(tt.hostname, tt.suffix), tt.hostname+" "+tt.suffix)
+	}
+}
+
+func TestApplicationAlerts(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	invalid := [][]core.AlertRule{
+		{{Metric: "disk", Threshold: 1}},
+		{{Metric: core.AlertCPU}},
+		{{Metric: core.AlertCPU, Threshold: 90, Duration: -1}},
+		{{Metric: core.AlertCPU, Threshold: 90}, {Metric: core.AlertCPU, Threshold: 150}},
+	}
+	for _, rules := range invalid {
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications",
+			core.Application{Name: "web", Image: "nginx", Alerts: rules})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%+v", rules)
+	}
+
+	app := core.Application{
+		Name:     "web",
+		Image:    "nginx",
+		Alerts:   []core.AlertRule{{Metric: core.AlertMemory, Threshold: 512 << 20, Duration: 300}},
+		Alerting: []string{core.AlertMemory},
+	}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Empty(t, created.Alerting, "set by the metrics collector only")
+
+	// Updates keep the state the collector recorded
+	require.NoError(t, srv.store.SetApplicationAlerting(created.ID, []string{core.AlertMemory}))
+	created.Image = "nginx:1.27"
+	created.Alerting = nil
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/applications/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"alerting":["memory"]`)
+}
+
+func TestApplicationHealthChecks(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	invalid := []*core.HealthCheck{
+		{Type: "grpc", Port: 80},
+		{Type: core.HealthCheckHTTP},
+		{Type: core.HealthCheckHTTP, Port: 80, Path: "healthz"},
+		{Type: core.HealthCheckTCP, Port: 70000},
+		{Type: core.HealthCheckExec},
+		{Type: core.HealthCheckTCP, Port: 5432, Retries: -1},
+	}
+	for _, hc := range invalid {
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications",
+			core.Application{Name: "web", Image: "nginx", HealthCheck: hc})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%+v", hc)
+	}
+
+	app := core.Application{
+		Name:         "web",
+		Image:        "nginx",
+		HealthCheck:  &core.HealthCheck{Type: core.HealthCheckHTTP, Port: 80, Path: "/healthz"},
+		HealthStatus: core.HealthHealthy, // set by the reconciler, not the client
+	}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Empty(t, created.HealthStatus)
+
+	require.NoError(t, srv.store.SetApplicationHealth(created.ID, core.HealthUnhealthy))
+	created.Image = "nginx:1.27"
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusOK, w.Code)
+	got, err := srv.store.GetApplication(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, core.HealthUnhealthy, got.HealthStatus, "updates keep the recorded health")
+
+	// A verified domain is ready only once its application is healthy
+	domain := core.Domain{ID: "web-domain", Hostname: "web.example.com", ApplicationID: created.ID, TLS: core.TLSAuto, Status: core.DomainVerified}
+	require.NoError(t, srv.store.CreateDomain(&domain))
+	ready := func() bool {
+		w := sendJSON(t, srv, http.MethodGet, "/api/v1/domains", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var list []core.Domain
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+		return list[0].Ready
+	}
+	assert.False(t, ready())
+	require.NoError(t, srv.store.SetApplicationHealth(created.ID, core.HealthHealthy))
+	assert.True(t, ready())
+}
+
+func TestApplyNodeHealth(t *testing.T) {
+	now := time.Now()
+	node := &core.Node{ID: "n1", LastHeartbeat: now, AppHealth: map[string]string{"web": core.HealthHealthy}}
+	nodes := map[string]*core.Node{"n1": node}
+
+	app := core.Application{ID: "web", NodeID: "n1", NodeSelector: map[string]string{"zone": "a"}, HealthCheck: &core.HealthCheck{Type: core.HealthCheckTCP, Port: 80}}
+	applyNodeStatus(&app, nodes, now)
+	assert.Equal(t, core.HealthHealthy, app.HealthStatus)
+
+	// The health of a node that stopped reporting is unknown
+	applyNodeStatus(&app, nodes, now.Add(2*core.NodeHeartbeatTimeout))
+	assert.Empty(t, app.HealthStatus)
+}
+
+func TestSensitiveEnv(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	app := core.Application{
+		Name:         "api",
+		Image:        "myapp:v1",
+		EnvVars:      map[string]string{"DB_HOST": "db", "DB_PASSWORD": "hunter2", "STRIPE_WEBHOOK": "whsec_123"},
+		SensitiveEnv: []string{"STRIPE_WEBHOOK"},
+	}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", app)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "hunter2")
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, map[string]string{"DB_HOST": "db", "DB_PASSWORD": core.RedactedValue, "STRIPE_WEBHOOK": core.RedactedValue}, created.EnvVars)
+
+	for _, path := range []string{"/api/v1/applications", "/api/v1/applications/" + created.ID, "/api/v1/applications/" + created.ID + "/deployments"} {
+		w = sendJSON(t, srv, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code, path)
+		assert.NotContains(t, w.Body.String(), "hunter2", path)
+		assert.NotContains(t, w.Body.String(), "whsec_123", path)
+	}
+
+	// Sending the redacted spec back keeps the stored values
+	created.Image = "myapp:v2"
+	created.EnvVars["DB_HOST"] = "db2"
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	require.Equal(t, http.StatusOK, w.Code)
+	stored, err := srv.store.GetApplication(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_HOST": "db2", "DB_PASSWORD": "hunter2", "STRIPE_WEBHOOK": "whsec_123"}, stored.EnvVars)
+
+	created.SensitiveEnv = []string{"not-a-name"}
+	w = sendJSON(t, srv, http.MethodPut, "/api/v1/applications/"+created.ID, created)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfigFiles(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	invalid := [][]core.ConfigFile{
+		{{Path: "etc/app.conf"}},
+		{{Path: "/etc/../app.conf"}},
+		{{Path: "/"}},
+		{{Path: "/etc/app.conf"}, {Path: "/etc/app.conf"}},
+		{{Path: "/data"}}, // taken by the volume
+		{{Path: "/etc/app.conf", Content: strings.Repeat("x", core.MaxConfigFileSize+1)}},
+		{{Path: "/etc/app.conf", Mode: 0o4755}},
+	}
+	for _, files := range invalid {
+		app := core.Application{Name: "web", Image: "nginx", ConfigFiles: files,
+			Volumes: []core.VolumeMount{{Name: "data", Path: "/data"}}}
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", app)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%+v", files)
+	}
+
+	files := []core.ConfigFile{{Path: "/etc/nginx/conf.d/default.conf", Content: "server {}"}}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications",
+		core.Application{Name: "web", Image: "nginx", ConfigFiles: files})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, files, created.ConfigFiles)
+
+	history, err := srv.store.ListDeployments(created.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, files, history[0].ConfigFiles)
+
+	checksum := core.ConfigChecksum(files)
+	assert.Len(t, checksum, 16)
+	assert.Empty(t, core.ConfigChecksum(nil))
+	changed := []core.ConfigFile{{Path: files[0].Path, Content: files[0].Content, Mode: 0o600}}
+	assert.NotEqual(t, checksum, core.ConfigChecksum(changed))
+	assert.Equal(t, uint32(0o644), files[0].FileMode())
+}
+
+func TestLinksAndGraph(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ids := make(map[string]string)
+	for _, name := range []string{"web", "api", "db"} {
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", core.Application{Name: name, Image: "nginx"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var app core.Application
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+		ids[name] = app.ID
+	}
+
+	invalid := []core.Link{
+		{From: ids["web"], To: ids["api"]},
+		{From: ids["web"], To: ids["api"], Type: "calls"},
+		{To: ids["api"], Type: core.LinkDependsOn},
+		{From: ids["web"], To: ids["web"], Type: core.LinkDependsOn},
+	}
+	for _, link := range invalid {
+		w := sendJSON(t, srv, http.MethodPost, "/api/v1/links", link)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%+v", link)
+	}
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/links",
+		core.Link{From: ids["web"], To: "missing", Type: core.LinkDependsOn})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	for _, link := range []core.Link{
+		{From: ids["web"], To: ids["api"], Type: core.LinkDependsOn},
+		{From: ids["api"], To: ids["db"], Type: core.LinkDependsOn},
+		{From: ids["db"], To: ids["web"], Type: core.LinkConnectsTo},
+	} {
+		w = sendJSON(t, srv, http.MethodPost, "/api/v1/links", link)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/links",
+		core.Link{From: ids["db"], To: ids["web"], Type: core.LinkDependsOn})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "cycle")
+	w = sendJSON(t, srv, http.MethodPost, "/api/v1/links",
+		core.Link{From: ids["web"], To: ids["api"], Type: core.LinkDependsOn})
+	assert.Equal(t, http.StatusConflict, w.Code, "duplicate")
+
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/graph", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var graph core.Graph
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &graph))
+	require.Len(t, graph.Nodes, 3)
+	assert.Equal(t, []string{"db", "api", "web"},
+		[]string{graph.Nodes[0].Name, graph.Nodes[1].Name, graph.Nodes[2].Name})
+	assert.Len(t, graph.Links, 3)
+
+	w = sendJSON(t, srv, http.MethodDelete, "/api/v1/applications/"+ids["db"], nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/links?application="+ids["api"], nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var links []core.Link
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &links))
+	require.Len(t, links, 1)
+	assert.Equal(t, ids["web"], links[0].From)
+
+	w = sendJSON(t, srv, http.MethodDelete, "/api/v1/links/"+links[0].ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = sendJSON(t, srv, http.MethodDelete, "/api/v1/links/"+links[0].ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestActivityFeed(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(ActorHeader, "alice@laptop")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+	list := func(query string) []core.Activity {
+		t.Helper()
+		w := sendJSON(t, srv, http.MethodGet, "/api/v1/activity"+query, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var entries []core.Activity
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		return entries
+	}
+
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/teams", core.Team{ID: "team-1", Name: "Platform"}).Code)
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/projects",
+		core.Project{ID: "project-1", TeamID: "team-1", Name: "shop"}).Code)
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/environments",
+		core.Environment{ID: "env-1", ProjectID: "project-1", Name: "prod"}).Code)
+	w := send(http.MethodPost, "/api/v1/applications", core.Application{Name: "web", Image: "nginx", EnvironmentID: "env-1"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var app core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &app))
+	require.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/applications/"+app.ID+"/scale", map[string]int{"replicas": 2}).Code)
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/api/v1/applications/"+app.ID, nil).Code)
+	require.Equal(t, http.StatusCreated, send(http.MethodPost, "/api/v1/volumes", core.Volume{Name: "data"}).Code)
+
+	entries := list("")
+	require.Len(t, entries, 7)
+	assert.Equal(t, "volume", entries[0].ResourceType, "newest first")
+	for i := range entries {
+		assert.Equal(t, "alice@laptop", entries[i].Actor)
+	}
+
+	appEntries := list("?application=" + app.ID)
+	require.Len(t, appEntries, 3)
+	assert.Equal(t, []string{core.ActionDeleted, core.ActionDeployed, core.ActionCreated},
+		[]string{appEntries[0].Action, appEntries[1].Action, appEntries[2].Action})
+	assert.Equal(t, "web", appEntries[0].ResourceName)
+	assert.Equal(t, "scaled to 2 replicas", appEntries[1].Message)
+
+	assert.Len(t, list("?team=team-1"), 6)
+	assert.Len(t, list("?project=project-1"), 5)
+	assert.Len(t, list("?team=team-1&limit=2"), 2)
+	assert.Len(t, list("?before="+entries[4].CreatedAt.Format(time.RFC3339Nano)), 2)
+
+	for _, query := range []string{"?limit=0", "?limit=many", "?before=yesterday"} {
+		w := sendJSON(t, srv, http.MethodGet, "/api/v1/activity"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestApplicationLogs(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store.WithContext(context.Background())
+	app := &core.Application{ID: "app-1", Name: "web", Image: "nginx", Replicas: 2}
+	require.NoError(t, st.CreateApplication(app))
+	remote := &core.Application{ID: "app-2", Name: "worker", Image: "busybox", NodeID: "node-1"}
+	require.NoError(t, st.CreateApplication(remote))
+
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{
+			{Name: "web", Labels: map[string]string{"simplify.app.id": "app-1", "simplify.app.replica": "0"}},
+			{Name: "web-2", Labels: map[string]string{"simplify.app.id": "app-1", "simplify.app.replica": "1"}},
+		}, nil
+	}
+	var streamed container.LogOptions
+	mock.StreamLogsFunc = func(ctx context.Context, name string, opts container.LogOptions, fn func(container.LogLine) error) error {
+		streamed = opts
+		if name != "web-2" {
+			return errors.NewNotFoundError("container", name)
+		}
+		for _, text := range []string{"listening on :80", "GET / 200"} {
+			if err := fn(container.LogLine{Stream: "stdout", Text: text}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	c := client.New(ts.URL, client.Options{MaxRetries: -1})
+	ctx := context.Background()
+
+	var lines []string
+	err := c.StreamLogs(ctx, app.ID, client.LogOptions{Replica: 1, Tail: 20, Since: "10m"}, func(line client.LogLine) error {
+		lines = append(lines, line.Text)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"listening on :80", "GET / 200"}, lines)
+	assert.Equal(t, container.LogOptions{Tail: "20", Since: "10m"}, streamed)
+
+	err = c.StreamLogs(ctx, app.ID, client.LogOptions{}, func(client.LogLine) error { return nil })
+	assert.True(t, client.IsNotFound(err), "errors before the first line are returned")
+
+	err = c.StreamLogs(ctx, app.ID, client.LogOptions{Replica: 5}, func(client.LogLine) error { return nil })
+	assert.True(t, client.IsNotFound(err), "no such replica")
+
+	err = c.StreamLogs(ctx, remote.ID, client.LogOptions{}, func(client.LogLine) error { return nil })
+	assert.True(t, client.IsUnavailable(err), "logs of node applications stay on the node")
+
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/applications/"+app.ID+"/logs?tail=-1", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestApplicationLogEvents(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store.WithContext(context.Background())
+	require.NoError(t, st.CreateApplication(&core.Application{ID: "app-1", Name: "web", Image: "nginx"}))
+
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{{Name: "web", Labels: map[string]string{"simplify.app.id": "app-1"}}}, nil
+	}
+	var streamed container.LogOptions
+	mock.StreamLogsFunc = func(ctx context.Context, name string, opts container.LogOptions, fn func(container.LogLine) error) error {
+		streamed = opts
+		written := time.Date(2026, 10, 16, 9, 30, 0, 5e8, time.UTC)
+		if err := fn(container.LogLine{Time: written, Stream: "stdout", Text: "listening on :80"}); err != nil {
+			return err
+		}
+		return fn(container.LogLine{Stream: "stderr", Text: "no timestamp"})
+	}
+
+	// Server-sent events for EventSource, ending with an end event
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/applications/app-1/logs?timestamps=true&tail=10", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, container.LogOptions{Tail: "10", Timestamps: true}, streamed)
+	assert.Equal(t, `data: {"time":"2026-10-16T09:30:00.5Z","stream":"stdout","text":"listening on :80"}
+
+data: {"stream":"stderr","text":"no timestamp"}
+
+event: end
+data: {}
+
+`, w.Body.String())
+
+	// The client reads the timestamps from newline delimited JSON
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	c := client.New(ts.URL, client.Options{MaxRetries: -1})
+	var lines []client.LogLine
+	err := c.StreamLogs(context.Background(), "app-1", client.LogOptions{Timestamps: true}, func(line client.LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 5e8, time.UTC), lines[0].Time)
+	assert.Equal(t, "listening on :80", lines[0].Text)
+	assert.True(t, lines[1].Time.IsZero())
+
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/applications/app-1/logs?timestamps=maybe", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestApplicationAttach(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store.WithContext(context.Background())
+	app := &core.Application{ID: "app-1", Name: "repl", Image: "python", Interactive: true}
+	require.NoError(t, st.CreateApplication(app))
+	quiet := &core.Application{ID: "app-2", Name: "web", Image: "nginx"}
+	require.NoError(t, st.CreateApplication(quiet))
+	remote := &core.Application{ID: "app-3", Name: "worker", Image: "busybox", NodeID: "node-1"}
+	require.NoError(t, st.CreateApplication(remote))
+
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{
+			{Name: "repl", Labels: map[string]string{"simplify.app.id": "app-1", "simplify.app.replica": "0"}},
+			{Name: "web", Labels: map[string]string{"simplify.app.id": "app-2", "simplify.app.replica": "0"}},
+		}, nil
+	}
+	// The process echoes each line of input, and fails on "fail"
+	mock.AttachFunc = func(ctx context.Context, name string, stdin io.Reader, stdout, stderr io.Writer) error {
+		if stdin == nil {
+			_, err := fmt.Fprintln(stdout, name, "has no input")
+			return err
+		}
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			switch scanner.Text() {
+			case "fail":
+				return fmt.Errorf("engine went away")
+			case "warn":
+				fmt.Fprintln(stderr, "warning")
+			default:
+				fmt.Fprintln(stdout, ">>>", scanner.Text())
+			}
+		}
+		return nil
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	c := client.New(ts.URL, client.Options{})
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("1+1\nwarn\n")
+	require.NoError(t, c.Attach(ctx, app.ID, 0, stdin, &stdout, &stderr))
+	assert.Equal(t, ">>> 1+1\n", stdout.String())
+	assert.Equal(t, "warning\n", stderr.String())
+
+	err := c.Attach(ctx, app.ID, 0, strings.NewReader("fail\n"), &stdout, &stderr)
+	require.Error(t, err, "failures after the upgrade close the connection with an error")
+
+	stdout.Reset()
+	require.NoError(t, c.Attach(ctx, quiet.ID, 0, strings.NewReader("ignored\n"), &stdout, nil))
+	assert.Equal(t, "web has no input\n", stdout.String(), "input to applications that are not interactive is discarded")
+
+	err = c.Attach(ctx, app.ID, 1, nil, &stdout, nil)
+	assert.True(t, client.IsNotFound(err), "no such replica")
+
+	err = c.Attach(ctx, remote.ID, 0, nil, &stdout, nil)
+	assert.True(t, client.IsUnavailable(err), "applications run by nodes are attached to on the node")
+
+	// Other hosts need an API key
+	w := sendJSON(t, srv, http.MethodGet, "/api/v1/applications/"+app.ID+"/attach?replica=x", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = sendJSONWithKey(t, srv, "smp_wrong", http.MethodGet, "/api/v1/applications/"+app.ID+"/attach?replica=x", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	key := createTestAPIKey(t, srv)
+	w = sendJSONWithKey(t, srv, key, http.MethodGet, "/api/v1/applications/"+app.ID+"/attach?replica=x", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// TestClientTypes checks that the client's copies of types it cannot
+// import without the container engine match the server's
+func TestClientTypes(t *testing.T) {
+	roundTrip := func(in, out any) {
+		t.Helper()
+		data, err := json.Marshal(in)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, out))
+	}
+
+	b := backup.Backup{CreatedAt: time.Now().UTC(), Records: map[string]int{"apps": 2}, Name: "b1", Volumes: []string{"data"}, Size: 42}
+	var gotBackup client.Backup
+	roundTrip(b, &gotBackup)
+	assert.Equal(t, client.Backup(b), gotBackup)
+
+	summary := store.BackupSummary{Records: map[string]int{"apps": 2}, Size: 42}
+	var gotSummary client.BackupSummary
+	roundTrip(summary, &gotSummary)
+	assert.Equal(t, client.BackupSummary(summary), gotSummary)
+
+	image := container.ImageInfo{ID: "sha256:1", ExposedPorts: []string{"80/tcp"}}
+	var gotImage client.ImageInfo
+	roundTrip(image, &gotImage)
+	assert.Equal(t, client.ImageInfo(image), gotImage)
+
+	line := container.LogLine{Stream: "stderr", Text: "oops"}
+	var gotLine client.LogLine
+	roundTrip(line, &gotLine)
+	assert.Equal(t, client.LogLine(line), gotLine)
+}
+
+func TestExportApplication(t *testing.T) {
+	srv, _, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	w := sendJSON(t, srv, http.MethodPost, "/api/v1/applications", core.Application{
+		Name:     "web",
+		Image:    "nginx",
+		Replicas: 1,
+		EnvVars:  map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": "hunter2"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created core.Application
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/applications/"+created.ID+"/export", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), "hunter2")
+	assert.NotContains(t, w.Body.String(), created.ID)
+
+	m, err := manifest.Parse(w.Body.Bytes())
+	require.NoError(t, err)
+	require.Len(t, m.Applications, 1)
+	assert.Equal(t, "nginx", m.Applications[0].Image)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "debug", "DB_PASSWORD": ""}, m.Applications[0].EnvVars)
+
+	w = sendJSON(t, srv, http.MethodGet, "/api/v1/applications/missing/export", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestApplicationExec(t *testing.T) {
+	srv, mock, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	st := srv.store.WithContext(context.Background())
+	app := &core.Application{ID: "app-1", Name: "web", Image: "nginx"}
+	require.NoError(t, st.CreateApplication(app))
+	remote := &core.Application{ID: "app-2", Name: "worker", Image: "busybox", NodeID: "node-1"}
+	require.NoError(t, st.CreateApplication(remote))
+
+	mock.ListFunc = func(ctx context.Context, all bool) ([]container.ContainerInfo, error) {
+		return []container.ContainerInfo{
+			{Name: "web", Labels: map[string]string{"simplify.app.id": "app-1", "simplify.app.replica": "0"}},
+		}, nil
+	}
+	var ran container.ExecOptions
+	mock.ExecFunc = func(ctx context.Context, name string, opts container.ExecOptions) (int, error) {
+		ran = opts
+		if opts.Cmd[0] == "missing" {
+			return -1, errors.NewNotFoundError("command", "missing")
+		}
+		fmt.Fprintln(opts.Stdout, "index.html")
+		fmt.Fprintln(opts.Stderr, "ls: cannot access 'extra'")
+		return 2, nil
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	c := client.New(ts.URL, client.Options{MaxRetries: -1})
+	ctx := context.Background()
+
+	var stdout, stderr bytes.Buffer
+	code, err := c.Exec(ctx, app.ID, &client.ExecRequest{Command: []string{"ls", "extra"}, Env: []string{"LC_ALL=C"}}, &stdout, &stderr)
+	require.NoError(t, err)
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "index.html\n", stdout.String())
+	assert.Equal(t, "ls: cannot access 'extra'\n", stderr.String())
+	assert.Equal(t, []string{"LC_ALL=C"}, ran.Env)
+	assert.Nil(t, ran.Stdin, "commands run through the API get no input")
+
+	_, err = c.Exec(ctx, app.ID, &client.ExecRequest{Command: []string{"missing"}}, &stdout, &stderr)
+	assert.True(t, client.IsNotFound(err), "errors before any output are returned")
+
+	_, err = c.Exec(ctx, app.ID, &client.ExecRequest{Command: []string{"ls"}, Replica: 1}, &stdout, &stderr)
+	assert.True(t, client.IsNotFound(err), "no such replica")
+
+	_, err = c.Exec(ctx, remote.ID, &client.ExecRequest{Command: []string{"ls"}}, &stdout, &stderr)
+	assert.True(t, client.IsUnavailable(err), "node applications run commands on the node")
+
+	// Other hosts need an API key
+	ls := client.ExecRequest{Command: []string{"ls"}}
+	assert.Equal(t, http.StatusForbidden, sendJSON(t, srv, http.MethodPost, "/api/v1/applications/"+app.ID+"/exec", ls).Code)
+	assert.Equal(t, http.StatusForbidden, sendJSONWithKey(t, srv, "smp_wrong", http.MethodPost, "/api/v1/applications/"+app.ID+"/exec", ls).Code)
+	key := createTestAPIKey(t, srv)
+	w := sendJSONWithKey(t, srv, key, http.MethodPost, "/api/v1/applications/"+app.ID+"/exec", ls)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	for _, req := range []client.ExecRequest{{}, {Command: []string{"ls"}, Env: []string{"=x"}}} {
+		w := sendJSONWithKey(t, srv, key, http.MethodPost, "/api/v1/applications/"+app.ID+"/exec", req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "%+v", req)
+	}
+
+	activity, err := st.ListActivity(core.ActivityFilter{ApplicationID: app.ID})
+	require.NoError(t, err)
+	require.NotEmpty(t, activity)
+	assert.Equal(t, core.ActionExecuted, activity[0].Action)
+}
@@ -0,0 +1,345 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// handleCreateStack creates a stack and its shared network, and attaches
+// the listed applications to it
+func (s *Server) handleCreateStack(w http.ResponseWriter, r *http.Request) error {
+	var stack core.Stack
+	if err := json.NewDecoder(r.Body).Decode(&stack); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	if stack.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if !engineNamePattern.MatchString(stack.Name) {
+		return errors.NewInvalidInputErrorWithField("name", "name may only contain letters, digits, '.', '-' and '_'")
+	}
+
+	stack.ID = uuid.New().String()
+	now := time.Now().UTC()
+	stack.CreatedAt = now
+	stack.UpdatedAt = now
+	stack.Stopped = false
+
+	apps, err := s.stackMembers(r, &stack)
+	if err != nil {
+		return err
+	}
+
+	st := s.store.WithContext(r.Context())
+	network := core.Network{ID: uuid.New().String(), CreatedAt: now, Name: stack.NetworkName()}
+	if err := st.CreateNetwork(&network); err != nil {
+		return err
+	}
+	if _, err := s.container.CreateNetwork(r.Context(), network.Name); err != nil {
+		return errors.NewInternalErrorWithCause("failed to create stack network in backend", err)
+	}
+	stack.NetworkID = network.ID
+
+	if err := st.CreateStack(&stack); err != nil {
+		return err
+	}
+
+	for i := range apps {
+		if err := s.attachToStack(r, &apps[i], &stack); err != nil {
+			return err
+		}
+	}
+	s.recordActivity(r, core.ActionCreated, &stack, "")
+
+	return writeCreated(w, stack)
+}
+
+// handleListStacks returns all stacks
+func (s *Server) handleListStacks(w http.ResponseWriter, r *http.Request) error {
+	stacks, err := s.store.WithContext(r.Context()).ListStacks()
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, stacks)
+}
+
+// handleGetStack returns a single stack by ID
+func (s *Server) handleGetStack(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	stack, err := s.store.WithContext(r.Context()).GetStack(id)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, stack)
+}
+
+// handleUpdateStack replaces the stack's applications and their start
+// order. Applications left out are detached but keep running.
+func (s *Server) handleUpdateStack(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.Stack
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	st := s.store.WithContext(r.Context())
+	stack, err := st.GetStack(id)
+	if err != nil {
+		return err
+	}
+
+	removed := slices.DeleteFunc(slices.Clone(stack.ApplicationIDs), func(appID string) bool {
+		return slices.Contains(req.ApplicationIDs, appID)
+	})
+	stack.ApplicationIDs = req.ApplicationIDs
+	apps, err := s.stackMembers(r, stack)
+	if err != nil {
+		return err
+	}
+
+	stack.UpdatedAt = time.Now().UTC()
+	if err := st.UpdateStack(stack); err != nil {
+		return err
+	}
+
+	for _, appID := range removed {
+		app, err := st.GetApplication(appID)
+		if err != nil {
+			log.WarnCtx(r.Context(), "Stack member not found", "stack", stack.Name, "app", appID, "error", err)
+			continue
+		}
+		if err := s.detachFromStack(r, app, stack); err != nil {
+			return err
+		}
+	}
+	for i := range apps {
+		if apps[i].StackID != stack.ID {
+			if err := s.attachToStack(r, &apps[i], stack); err != nil {
+				return err
+			}
+		}
+	}
+	s.recordActivity(r, core.ActionUpdated, stack, "")
+
+	return writeSuccess(w, stack)
+}
+
+// handleDeployStack starts every application of a stopped stack. Running
+// applications are left as they are.
+func (s *Server) handleDeployStack(w http.ResponseWriter, r *http.Request) error {
+	return s.setStackStopped(w, r, false)
+}
+
+// handleStopStack stops every application of a stack. Their containers are
+// removed by the reconciler; volumes and configuration are kept.
+func (s *Server) handleStopStack(w http.ResponseWriter, r *http.Request) error {
+	return s.setStackStopped(w, r, true)
+}
+
+// setStackStopped sets the desired state of a stack and its applications
+func (s *Server) setStackStopped(w http.ResponseWriter, r *http.Request, stopped bool) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	st := s.store.WithContext(r.Context())
+	stack, err := st.GetStack(id)
+	if err != nil {
+		return err
+	}
+
+	for _, appID := range stack.ApplicationIDs {
+		app, err := st.GetApplication(appID)
+		if err != nil {
+			return err
+		}
+		if app.Stopped == stopped {
+			continue
+		}
+		app.Stopped = stopped
+		if err := st.UpdateApplication(app); err != nil {
+			return err
+		}
+	}
+
+	stack.Stopped = stopped
+	stack.UpdatedAt = time.Now().UTC()
+	if err := st.UpdateStack(stack); err != nil {
+		return err
+	}
+
+	action := core.ActionDeployed
+	if stopped {
+		action = core.ActionStopped
+	}
+	s.recordActivity(r, action, stack, "")
+
+	log.InfoCtx(r.Context(), "Stack state changed", "stack", stack.Name, "stopped", stopped)
+	return writeSuccess(w, stack)
+}
+
+// handleDeleteStack removes a stack together with its applications and
+// network. Volumes and secrets are kept, as they may hold data.
+func (s *Server) handleDeleteStack(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	st := s.store.WithContext(r.Context())
+	stack, err := st.GetStack(id)
+	if err != nil {
+		return err
+	}
+
+	for _, appID := range stack.ApplicationIDs {
+		if err := st.DeleteApplication(appID); err != nil {
+			return err
+		}
+		if err := st.DeleteDeployments(appID); err != nil {
+			log.WarnCtx(r.Context(), "Failed to delete deployment history", "app", appID, "error", err)
+		}
+		s.deleteDomainsOf(r, appID)
+		s.deleteLinksOf(r, appID)
+		if err := st.DeleteBuilds(appID); err != nil {
+			log.WarnCtx(r.Context(), "Failed to delete build history", "app", appID, "error", err)
+		}
+		if err := st.DeleteMetrics(appID); err != nil {
+			log.WarnCtx(r.Context(), "Failed to delete metrics", "app", appID, "error", err)
+		}
+	}
+
+	if err := st.DeleteStack(id); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionDeleted, stack, fmt.Sprintf("with %d applications", len(stack.ApplicationIDs)))
+
+	// Remove the containers now rather than waiting for the reconciler, as
+	// the engine refuses to remove a network that containers are attached to
+	s.removeAppContainers(r, stack.ApplicationIDs)
+
+	if network, err := st.GetNetwork(stack.NetworkID); err == nil {
+		if err := s.container.RemoveNetwork(r.Context(), network.Name); err != nil {
+			log.WarnCtx(r.Context(), "Failed to remove stack network from engine", "name", network.Name, "error", err)
+		}
+		if err := st.DeleteNetwork(network.ID); err != nil {
+			log.WarnCtx(r.Context(), "Failed to delete stack network", "name", network.Name, "error", err)
+		}
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// stackMembers loads the stack's applications in order, checking that each
+// exists, is listed once and belongs to no other stack
+func (s *Server) stackMembers(r *http.Request, stack *core.Stack) ([]core.Application, error) {
+	st := s.store.WithContext(r.Context())
+
+	apps := make([]core.Application, 0, len(stack.ApplicationIDs))
+	for i, appID := range stack.ApplicationIDs {
+		if slices.Contains(stack.ApplicationIDs[:i], appID) {
+			return nil, errors.NewInvalidInputErrorWithField("application_ids",
+				fmt.Sprintf("application %s is listed more than once", appID))
+		}
+		app, err := st.GetApplication(appID)
+		if err != nil {
+			return nil, err
+		}
+		if app.StackID != "" && app.StackID != stack.ID {
+			return nil, errors.NewConflictStateError("application", app.Name, "application already belongs to another stack")
+		}
+		apps = append(apps, *app)
+	}
+	return apps, nil
+}
+
+// attachToStack makes app a member of stack. Applications outside a pod
+// move to the stack's network, which redeploys them.
+func (s *Server) attachToStack(r *http.Request, app *core.Application, stack *core.Stack) error {
+	app.StackID = stack.ID
+	app.Stopped = stack.Stopped
+	moved := app.PodID == "" && app.NetworkID != stack.NetworkID
+	if moved {
+		app.NetworkID = stack.NetworkID
+		app.UpdatedAt = time.Now().UTC()
+	}
+	return s.saveStackMember(r, app, moved)
+}
+
+// detachFromStack removes app from stack, taking it off the stack's network
+func (s *Server) detachFromStack(r *http.Request, app *core.Application, stack *core.Stack) error {
+	app.StackID = ""
+	moved := app.NetworkID == stack.NetworkID
+	if moved {
+		app.NetworkID = ""
+		app.UpdatedAt = time.Now().UTC()
+	}
+	return s.saveStackMember(r, app, moved)
+}
+
+// saveStackMember stores an application whose membership changed,
+// recording a deployment when its spec changed too
+func (s *Server) saveStackMember(r *http.Request, app *core.Application, redeploy bool) error {
+	if err := s.store.WithContext(r.Context()).UpdateApplication(app); err != nil {
+		return err
+	}
+	if redeploy {
+		s.recordDeployment(r, app, "")
+	}
+	return nil
+}
+
+// removeFromStack drops a deleted application from its stack's list
+func (s *Server) removeFromStack(r *http.Request, app *core.Application) {
+	if app.StackID == "" {
+		return
+	}
+	st := s.store.WithContext(r.Context())
+	stack, err := st.GetStack(app.StackID)
+	if err != nil {
+		log.WarnCtx(r.Context(), "Stack of deleted application not found", "app", app.Name, "stack_id", app.StackID)
+		return
+	}
+	stack.ApplicationIDs = slices.DeleteFunc(stack.ApplicationIDs, func(id string) bool { return id == app.ID })
+	if err := st.UpdateStack(stack); err != nil {
+		log.WarnCtx(r.Context(), "Failed to update stack", "stack", stack.Name, "error", err)
+	}
+}
+
+// removeAppContainers force-removes the containers of the given applications
+func (s *Server) removeAppContainers(r *http.Request, appIDs []string) {
+	containers, err := s.container.List(r.Context(), true)
+	if err != nil {
+		log.WarnCtx(r.Context(), "Failed to list containers", "error", err)
+		return
+	}
+	for i := range containers {
+		if !slices.Contains(appIDs, containers[i].Labels["simplify.app.id"]) {
+			continue
+		}
+		if err := s.container.Remove(r.Context(), containers[i].Name, true); err != nil {
+			log.WarnCtx(r.Context(), "Failed to remove container", "container", containers[i].Name, "error", err)
+		}
+	}
+}
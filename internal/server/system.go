@@ -0,0 +1,26 @@
+package server
+
+import (
+	"net/http"
+)
+
+// handleDiskUsage returns the space used by the engine's images, containers
+// and volumes
+func (s *Server) handleDiskUsage(w http.ResponseWriter, r *http.Request) error {
+	usage, err := s.container.DiskUsage(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, usage)
+}
+
+// handleSystemInfo returns the engine version and the capacity of its host
+func (s *Server) handleSystemInfo(w http.ResponseWriter, r *http.Request) error {
+	info, err := s.container.SystemInfo(r.Context())
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, info)
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/internal/templates"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// InstantiateRequest is the body of POST /templates/{id}/instantiate
+type InstantiateRequest = client.InstantiateRequest
+
+// InstantiateResponse is the application created from a template
+type InstantiateResponse = client.InstantiateResponse
+
+// handleListTemplates returns the bundled application templates
+func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) error {
+	list, err := templates.List()
+	if err != nil {
+		return errors.NewInternalErrorWithCause("failed to load templates", err)
+	}
+
+	return writeSuccess(w, list)
+}
+
+// handleGetTemplate returns a single template by ID
+func (s *Server) handleGetTemplate(w http.ResponseWriter, r *http.Request) error {
+	t, err := lookupTemplate(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, t)
+}
+
+// handleInstantiateTemplate creates an application from a template, along
+// with the volumes it attaches and secrets for its secret variables. Every
+// name is checked before anything is created.
+func (s *Server) handleInstantiateTemplate(w http.ResponseWriter, r *http.Request) error {
+	t, err := lookupTemplate(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	var req InstantiateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if req.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if !engineNamePattern.MatchString(req.Name) {
+		return errors.NewInvalidInputErrorWithField("name", "name may only contain letters, digits, '.', '-' and '_'")
+	}
+
+	values, err := t.Resolve(req.Values)
+	if err != nil {
+		return errors.NewInvalidInputErrorWithField("values", err.Error())
+	}
+	if err := s.checkTemplateNames(r, t, req.Name); err != nil {
+		return err
+	}
+
+	st := s.store.WithContext(r.Context())
+	now := time.Now().UTC()
+
+	app := core.Application{
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		EnvVars:       values.Env,
+		Ports:         maps.Clone(t.Ports),
+		ID:            uuid.New().String(),
+		Name:          req.Name,
+		EnvironmentID: req.EnvironmentID,
+		Image:         t.Image,
+		Replicas:      1,
+	}
+	if req.Ports != nil {
+		app.Ports = req.Ports
+	}
+	if err := s.checkEnvironmentLimits(r, &app); err != nil {
+		return err
+	}
+
+	for i := range t.Env {
+		v := &t.Env[i]
+		value, ok := values.Secrets[v.Name]
+		if !ok {
+			continue
+		}
+		secret := core.Secret{
+			CreatedAt: now,
+			UpdatedAt: now,
+			Name:      v.SecretName(req.Name),
+			Value:     value,
+		}
+		if err := st.CreateSecret(&secret); err != nil {
+			return err
+		}
+		app.Secrets = append(app.Secrets, core.SecretRef{Name: secret.Name, Env: v.Name})
+	}
+
+	for i := range t.Volumes {
+		v := &t.Volumes[i]
+		volume := core.Volume{CreatedAt: now, Name: v.VolumeName(req.Name)}
+		if err := st.CreateVolume(&volume); err != nil {
+			return err
+		}
+		app.Volumes = append(app.Volumes, core.VolumeMount{Name: volume.Name, Path: v.Path})
+	}
+
+	if err := st.CreateApplication(&app); err != nil {
+		return err
+	}
+	s.recordDeployment(r, &app, "")
+
+	log.InfoCtx(r.Context(), "Application created from template", "app", app.Name, "template", t.ID)
+	app.Redact()
+	return writeCreated(w, InstantiateResponse{Generated: values.Generated, Application: app})
+}
+
+// checkTemplateNames fails when the application, volume or secret names an
+// instantiation would use are taken
+func (s *Server) checkTemplateNames(r *http.Request, t *templates.Template, name string) error {
+	st := s.store.WithContext(r.Context())
+
+	apps, err := st.ListApplications()
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(apps, func(a core.Application) bool { return a.Name == name }) {
+		return errors.NewAlreadyExistsError("application", name)
+	}
+
+	volumes, err := st.ListVolumes()
+	if err != nil {
+		return err
+	}
+	for i := range t.Volumes {
+		want := t.Volumes[i].VolumeName(name)
+		if slices.ContainsFunc(volumes, func(v core.Volume) bool { return v.Name == want }) {
+			return errors.NewAlreadyExistsError("volume", want)
+		}
+	}
+
+	secrets, err := st.ListSecrets()
+	if err != nil {
+		return err
+	}
+	for i := range t.Env {
+		if !t.Env[i].Secret {
+			continue
+		}
+		want := t.Env[i].SecretName(name)
+		if slices.ContainsFunc(secrets, func(s core.Secret) bool { return s.Name == want }) {
+			return errors.NewAlreadyExistsError("secret", want)
+		}
+	}
+	return nil
+}
+
+// lookupTemplate returns a bundled template, or NotFoundError
+func lookupTemplate(id string) (*templates.Template, error) {
+	t, err := templates.Get(id)
+	if err != nil {
+		return nil, errors.NewInternalErrorWithCause("failed to load templates", err)
+	}
+	if t == nil {
+		return nil, errors.NewNotFoundError("template", id)
+	}
+	return t, nil
+}
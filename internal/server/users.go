@@ -0,0 +1,311 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/AkMo3/simplify/pkg/client"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// usernamePattern restricts usernames to a login-style form
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// handleCreateUser creates a new user
+func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) error {
+	var user core.User
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if err := validateUser(&user); err != nil {
+		return err
+	}
+
+	user.ID = uuid.New().String()
+	user.CreatedAt = time.Now().UTC()
+
+	if err := s.store.WithContext(r.Context()).CreateUser(&user); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionCreated, &user, "")
+
+	return writeCreated(w, user)
+}
+
+// WhoAmIResponse is the answer of GET /whoami
+type WhoAmIResponse = client.WhoAmIResponse
+
+// handleWhoAmI returns the user and API key of the request's bearer token.
+// Unlike other routes it always needs a key, also from this host.
+func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) error {
+	user, key, err := s.authenticate(r)
+	if err != nil {
+		return err
+	}
+	return writeSuccess(w, WhoAmIResponse{User: *user, APIKey: *key})
+}
+
+// handleListUsers returns all users
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) error {
+	users, err := s.store.WithContext(r.Context()).ListUsers()
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, users)
+}
+
+// handleGetUser returns a single user by ID
+func (s *Server) handleGetUser(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	user, err := s.store.WithContext(r.Context()).GetUser(id)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, user)
+}
+
+// handleUpdateUser replaces a user's username, email and team memberships
+func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.User
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if err := validateUser(&req); err != nil {
+		return err
+	}
+
+	st := s.store.WithContext(r.Context())
+	user, err := st.GetUser(id)
+	if err != nil {
+		return err
+	}
+
+	user.Username = req.Username
+	user.Email = req.Email
+	user.Teams = req.Teams
+	if err := st.UpdateUser(user); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionUpdated, user, "")
+
+	return writeSuccess(w, user)
+}
+
+// handleDeleteUser removes a user and revokes its API keys
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	// Read before deleting, to name it in the activity feed
+	user, getErr := s.store.WithContext(r.Context()).GetUser(id)
+	if err := s.store.WithContext(r.Context()).DeleteUser(id); err != nil {
+		return err
+	}
+	if getErr == nil {
+		s.recordActivity(r, core.ActionDeleted, user, "")
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// =============================================================================
+// API Key Handlers
+// =============================================================================
+
+// handleCreateAPIKey generates an API key for a user. The response is the
+// only time the key is returned.
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) error {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.APIKey
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	if req.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return errors.NewInvalidInputErrorWithField("expires_at", "expires_at must be in the future")
+	}
+
+	key := core.APIKey{
+		CreatedAt: now,
+		ExpiresAt: req.ExpiresAt,
+		UserID:    userID,
+		Name:      req.Name,
+	}
+	if err := s.store.WithContext(r.Context()).CreateAPIKey(&key); err != nil {
+		return err
+	}
+
+	return writeCreated(w, key)
+}
+
+// handleListAPIKeys returns a user's API keys, without the keys themselves
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) error {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	st := s.store.WithContext(r.Context())
+	if _, err := st.GetUser(userID); err != nil {
+		return err
+	}
+
+	keys, err := st.ListAPIKeys(userID)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, keys)
+}
+
+// handleDeleteAPIKey revokes one of a user's API keys
+func (s *Server) handleDeleteAPIKey(w http.ResponseWriter, r *http.Request) error {
+	userID := chi.URLParam(r, "id")
+	keyID := chi.URLParam(r, "keyID")
+
+	st := s.store.WithContext(r.Context())
+	key, err := st.GetAPIKey(keyID)
+	if err != nil {
+		return err
+	}
+	if key.UserID != userID {
+		return errors.NewNotFoundError("API key", keyID)
+	}
+
+	if err := st.DeleteAPIKey(keyID); err != nil {
+		return err
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// =============================================================================
+// Team Member Handlers
+// =============================================================================
+
+// handleListTeamMembers returns the users that belong to a team
+func (s *Server) handleListTeamMembers(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "id")
+
+	st := s.store.WithContext(r.Context())
+	if _, err := st.GetTeam(teamID); err != nil {
+		return err
+	}
+
+	members, err := st.ListTeamMembers(teamID)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, members)
+}
+
+// handleSetTeamMember adds a user to a team, or changes its role there
+func (s *Server) handleSetTeamMember(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+
+	var req core.TeamMembership
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if req.Role == "" {
+		req.Role = core.RoleMember
+	}
+	if err := validateRole(req.Role); err != nil {
+		return err
+	}
+
+	user, err := s.store.WithContext(r.Context()).SetTeamMember(teamID, userID, req.Role)
+	if err != nil {
+		return err
+	}
+	s.recordTeamActivity(r, teamID, "set "+user.Username+" as "+req.Role)
+
+	return writeSuccess(w, user)
+}
+
+// handleRemoveTeamMember removes a user from a team
+func (s *Server) handleRemoveTeamMember(w http.ResponseWriter, r *http.Request) error {
+	teamID := chi.URLParam(r, "id")
+	userID := chi.URLParam(r, "userID")
+
+	if err := s.store.WithContext(r.Context()).RemoveTeamMember(teamID, userID); err != nil {
+		return err
+	}
+	if user, err := s.store.WithContext(r.Context()).GetUser(userID); err == nil {
+		s.recordTeamActivity(r, teamID, "removed "+user.Username)
+	}
+
+	writeNoContent(w)
+	return nil
+}
+
+// validateUser checks the fields a client may set on a user
+func validateUser(user *core.User) error {
+	if user.Username == "" {
+		return errors.NewInvalidInputErrorWithField("username", "username is required")
+	}
+	if !usernamePattern.MatchString(user.Username) {
+		return errors.NewInvalidInputErrorWithField("username",
+			"username must be lowercase letters, digits, '.', '-' and '_', up to 64 characters")
+	}
+	if user.Email != "" && !strings.Contains(user.Email, "@") {
+		return errors.NewInvalidInputErrorWithField("email", "email is not a valid address")
+	}
+
+	seen := make(map[string]bool, len(user.Teams))
+	for _, m := range user.Teams {
+		if m.TeamID == "" {
+			return errors.NewInvalidInputErrorWithField("teams", "team_id is required")
+		}
+		if seen[m.TeamID] {
+			return errors.NewInvalidInputErrorWithField("teams", "team "+m.TeamID+" is listed more than once")
+		}
+		seen[m.TeamID] = true
+		if err := validateRole(m.Role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRole checks a team role is one of the known roles
+func validateRole(role string) error {
+	switch role {
+	case core.RoleOwner, core.RoleMember, core.RoleViewer:
+		return nil
+	default:
+		return errors.NewInvalidInputErrorWithField("role",
+			"role must be one of "+core.RoleOwner+", "+core.RoleMember+" or "+core.RoleViewer)
+	}
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// volumeSizePattern matches size hints such as 512MiB, 10G or 1.5TB
+var volumeSizePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?\s?([kKmMgGtTpP]i?)?[bB]?$`)
+
+// handleCreateVolume stores a new volume. The reconciler creates it in the
+// engine.
+func (s *Server) handleCreateVolume(w http.ResponseWriter, r *http.Request) error {
+	var volume core.Volume
+	if err := json.NewDecoder(r.Body).Decode(&volume); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+
+	if volume.Name == "" {
+		return errors.NewInvalidInputErrorWithField("name", "name is required")
+	}
+	if !engineNamePattern.MatchString(volume.Name) {
+		return errors.NewInvalidInputErrorWithField("name", "name may only contain letters, digits, '.', '-' and '_'")
+	}
+	if err := validateVolume(&volume); err != nil {
+		return err
+	}
+
+	volume.ID = uuid.New().String()
+	volume.CreatedAt = time.Now().UTC()
+
+	if err := s.store.WithContext(r.Context()).CreateVolume(&volume); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionCreated, &volume, "")
+
+	return writeCreated(w, volume)
+}
+
+// handleListVolumes returns all volumes
+func (s *Server) handleListVolumes(w http.ResponseWriter, r *http.Request) error {
+	volumes, err := s.store.WithContext(r.Context()).ListVolumes()
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, volumes)
+}
+
+// handleGetVolume returns a single volume
+func (s *Server) handleGetVolume(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	volume, err := s.store.WithContext(r.Context()).GetVolume(id)
+	if err != nil {
+		return err
+	}
+
+	return writeSuccess(w, volume)
+}
+
+// handleUpdateVolume changes a volume's size hint and backup policy. The
+// name is fixed, as it names the volume in the engine.
+func (s *Server) handleUpdateVolume(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	var req core.Volume
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return errors.NewInvalidInputErrorWithCause("invalid request body", err)
+	}
+	if err := validateVolume(&req); err != nil {
+		return err
+	}
+
+	volume, err := s.store.WithContext(r.Context()).GetVolume(id)
+	if err != nil {
+		return err
+	}
+	if req.Name != "" && req.Name != volume.Name {
+		return errors.NewInvalidInputErrorWithField("name", "volumes cannot be renamed")
+	}
+
+	volume.Size = req.Size
+	volume.Backup = req.Backup
+	if err := s.store.WithContext(r.Context()).UpdateVolume(volume); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionUpdated, volume, "")
+
+	return writeSuccess(w, volume)
+}
+
+// handleDeleteVolume removes a volume and its data. Volumes attached to an
+// application or pod cannot be deleted.
+func (s *Server) handleDeleteVolume(w http.ResponseWriter, r *http.Request) error {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return errors.NewInvalidInputErrorWithField("id", "id is required")
+	}
+
+	volume, err := s.store.WithContext(r.Context()).GetVolume(id)
+	if err != nil {
+		return err
+	}
+
+	apps, err := s.store.WithContext(r.Context()).ListApplications()
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		if slices.ContainsFunc(apps[i].Volumes, func(m core.VolumeMount) bool { return m.Name == volume.Name }) {
+			return errors.NewConflictStateError("volume", volume.Name,
+				fmt.Sprintf("volume is attached to application %s", apps[i].Name))
+		}
+	}
+	pods, err := s.store.WithContext(r.Context()).ListPods()
+	if err != nil {
+		return err
+	}
+	for i := range pods {
+		if slices.ContainsFunc(pods[i].Volumes, func(m core.VolumeMount) bool { return m.Name == volume.Name }) {
+			return errors.NewConflictStateError("volume", volume.Name,
+				fmt.Sprintf("volume is attached to pod %s", pods[i].Name))
+		}
+	}
+
+	// Remove from engine first. The reconciler may not have created it yet.
+	if err := s.container.RemoveVolume(r.Context(), volume.Name, false); err != nil {
+		log.WarnCtx(r.Context(), "Failed to remove volume from engine", "name", volume.Name, "error", err)
+	}
+
+	if err := s.store.WithContext(r.Context()).DeleteVolume(id); err != nil {
+		return err
+	}
+	s.recordActivity(r, core.ActionDeleted, volume, "")
+
+	writeNoContent(w)
+	return nil
+}
+
+// validateVolume checks a volume's size hint and backup policy
+func validateVolume(volume *core.Volume) error {
+	if volume.Size != "" && !volumeSizePattern.MatchString(volume.Size) {
+		return errors.NewInvalidInputErrorWithField("size", "size must be a number with an optional unit, e.g. 10GiB")
+	}
+	switch volume.Backup.Schedule {
+	case core.BackupNone, core.BackupDaily, core.BackupWeekly:
+	default:
+		return errors.NewInvalidInputErrorWithField("backup.schedule",
+			fmt.Sprintf("backup schedule must be %q or %q", core.BackupDaily, core.BackupWeekly))
+	}
+	if volume.Backup.Retain < 0 {
+		return errors.NewInvalidInputErrorWithField("backup.retain", "backup retain must not be negative")
+	}
+	return nil
+}
+
+// validateVolumeMounts checks an application's volume attachments. Each
+// mounts a volume, or bind-mounts an absolute host path, at a distinct
+// absolute path.
+func validateVolumeMounts(mounts []core.VolumeMount) error {
+	paths := make(map[string]bool, len(mounts))
+	for _, m := range mounts {
+		name := m.Name
+		switch {
+		case m.Name == "" && m.Source == "":
+			return errors.NewInvalidInputErrorWithField("volumes", "volume name or source is required")
+		case m.Name != "" && m.Source != "":
+			return errors.NewInvalidInputErrorWithField("volumes",
+				fmt.Sprintf("volume %s: set either a volume name or a source, not both", m.Name))
+		case m.Source != "":
+			name = m.Source
+			if !path.IsAbs(m.Source) || path.Clean(m.Source) != m.Source {
+				return errors.NewInvalidInputErrorWithField("volumes",
+					fmt.Sprintf("bind mount %s: source must be a clean absolute path", m.Source))
+			}
+		}
+		if !path.IsAbs(m.Path) || path.Clean(m.Path) != m.Path {
+			return errors.NewInvalidInputErrorWithField("volumes",
+				fmt.Sprintf("volume %s: path must be a clean absolute path", name))
+		}
+		if paths[m.Path] {
+			return errors.NewInvalidInputErrorWithField("volumes",
+				fmt.Sprintf("volume %s: %s is already mounted", name, m.Path))
+		}
+		paths[m.Path] = true
+	}
+	return nil
+}
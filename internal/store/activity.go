@@ -0,0 +1,160 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// MaxActivity is the number of activity entries kept. The oldest are
+// pruned as new ones are recorded.
+const MaxActivity = 5000
+
+// activityKey keys entries by time, so the feed is read newest first by
+// walking the bucket backwards
+func activityKey(a *core.Activity) []byte {
+	return fmt.Appendf(nil, "%020d/%s", a.CreatedAt.UnixNano(), a.ID)
+}
+
+// RecordActivity adds an entry to the activity feed, pruning the oldest
+// entries beyond MaxActivity
+func (s *Store) RecordActivity(a *core.Activity) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+
+	return s.update("create", BucketActivity, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketActivity))
+
+		data, err := json.Marshal(a)
+		if err != nil {
+			return errors.NewInternalErrorWithCause("failed to marshal activity", err)
+		}
+		if err := b.Put(activityKey(a), data); err != nil {
+			return errors.NewInternalErrorWithCause("failed to save activity", err)
+		}
+
+		excess := b.Stats().KeyN - MaxActivity
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && excess > 0; k, _ = c.First() {
+			if err := b.Delete(k); err != nil {
+				return errors.NewInternalErrorWithCause("failed to prune activity", err)
+			}
+			excess--
+		}
+		return nil
+	})
+}
+
+// ListActivity returns the entries matching a filter, newest first
+func (s *Store) ListActivity(filter core.ActivityFilter) ([]core.Activity, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = core.DefaultActivityLimit
+	}
+
+	entries := []core.Activity{}
+	err := s.view("list", BucketActivity, func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(BucketActivity)).Cursor()
+		for k, v := c.Last(); k != nil && len(entries) < limit; k, v = c.Prev() {
+			var a core.Activity
+			if err := json.Unmarshal(v, &a); err != nil {
+				return errors.NewInternalErrorWithCause("failed to unmarshal activity", err)
+			}
+			if filter.Matches(&a) {
+				entries = append(entries, a)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RecordResourceActivity adds an entry about a resource to the activity
+// feed. The caller sets the actor, action and message; the resource's type
+// and name and the team, project and application it belongs to are filled
+// in from resource.
+func (s *Store) RecordResourceActivity(a *core.Activity, resource any) error {
+	switch r := resource.(type) {
+	case *core.Application:
+		a.ResourceType, a.ResourceID, a.ResourceName = "application", r.ID, r.Name
+		a.ApplicationID = r.ID
+		a.ProjectID, a.TeamID = s.environmentScope(r.EnvironmentID)
+	case *core.Environment:
+		a.ResourceType, a.ResourceID, a.ResourceName = "environment", r.ID, r.Name
+		a.ProjectID, a.TeamID = s.projectScope(r.ProjectID)
+	case *core.Project:
+		a.ResourceType, a.ResourceID, a.ResourceName = "project", r.ID, r.Name
+		a.ProjectID, a.TeamID = r.ID, r.TeamID
+	case *core.Team:
+		a.ResourceType, a.ResourceID, a.ResourceName = "team", r.ID, r.Name
+		a.TeamID = r.ID
+	case *core.Domain:
+		a.ResourceType, a.ResourceID, a.ResourceName = "domain", r.ID, r.Hostname
+		s.applicationScope(a, r.ApplicationID)
+	case *core.Link:
+		a.ResourceType, a.ResourceID = "link", r.ID
+		s.applicationScope(a, r.From)
+	case *core.Build:
+		a.ResourceType, a.ResourceID = "build", r.ID
+		s.applicationScope(a, r.ApplicationID)
+	case *core.Stack:
+		a.ResourceType, a.ResourceID, a.ResourceName = "stack", r.ID, r.Name
+	case *core.Secret:
+		a.ResourceType, a.ResourceID, a.ResourceName = "secret", r.ID, r.Name
+	case *core.Volume:
+		a.ResourceType, a.ResourceID, a.ResourceName = "volume", r.ID, r.Name
+	case *core.User:
+		a.ResourceType, a.ResourceID, a.ResourceName = "user", r.ID, r.Username
+	default:
+		return errors.NewInternalError(fmt.Sprintf("no activity for resources of type %T", resource))
+	}
+	return s.RecordActivity(a)
+}
+
+// The scope lookups below are best effort: an entry about a resource whose
+// environment or project is gone is still recorded, without that scope.
+
+// applicationScope sets the application, project and team of an entry from
+// the application with the given ID
+func (s *Store) applicationScope(a *core.Activity, appID string) {
+	a.ApplicationID = appID
+	if app, err := s.GetApplication(appID); err == nil {
+		a.ProjectID, a.TeamID = s.environmentScope(app.EnvironmentID)
+	}
+}
+
+// environmentScope returns the project and team of an environment
+func (s *Store) environmentScope(envID string) (projectID, teamID string) {
+	if envID == "" {
+		return "", ""
+	}
+	env, err := s.GetEnvironment(envID)
+	if err != nil {
+		return "", ""
+	}
+	return s.projectScope(env.ProjectID)
+}
+
+// projectScope returns a project's ID and the team owning it
+func (s *Store) projectScope(projectID string) (string, string) {
+	if projectID == "" {
+		return "", ""
+	}
+	project, err := s.GetProject(projectID)
+	if err != nil {
+		return projectID, ""
+	}
+	return project.ID, project.TeamID
+}
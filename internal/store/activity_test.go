@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivity(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.CreateTeam(&core.Team{ID: "team-1", Name: "Platform"}))
+	require.NoError(t, s.CreateProject(&core.Project{ID: "project-1", TeamID: "team-1", Name: "shop"}))
+	require.NoError(t, s.CreateEnvironment(&core.Environment{ID: "env-1", ProjectID: "project-1", Name: "prod"}))
+	web := &core.Application{ID: "app-1", Name: "web", EnvironmentID: "env-1"}
+	require.NoError(t, s.CreateApplication(web))
+
+	start := time.Now().UTC()
+	record := func(action string, resource any, at time.Duration) {
+		t.Helper()
+		a := core.Activity{CreatedAt: start.Add(at), Actor: "alice@laptop", Action: action}
+		require.NoError(t, s.RecordResourceActivity(&a, resource))
+	}
+	record(core.ActionCreated, &core.Team{ID: "team-1", Name: "Platform"}, 0)
+	record(core.ActionCreated, web, time.Second)
+	record(core.ActionCreated, &core.Domain{ID: "domain-1", Hostname: "shop.example.com", ApplicationID: web.ID}, 2*time.Second)
+	record(core.ActionCreated, &core.Volume{ID: "volume-1", Name: "data"}, 3*time.Second)
+
+	all, err := s.ListActivity(core.ActivityFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, "volume", all[0].ResourceType, "newest first")
+	assert.Equal(t, "domain", all[1].ResourceType)
+	assert.Equal(t, "shop.example.com", all[1].ResourceName)
+	assert.Equal(t, web.ID, all[1].ApplicationID)
+	assert.Equal(t, "project-1", all[1].ProjectID)
+	assert.Equal(t, "team-1", all[1].TeamID)
+	assert.NotEmpty(t, all[1].ID)
+
+	team, err := s.ListActivity(core.ActivityFilter{TeamID: "team-1"})
+	require.NoError(t, err)
+	assert.Len(t, team, 3)
+
+	app, err := s.ListActivity(core.ActivityFilter{ApplicationID: web.ID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, app, 1)
+	assert.Equal(t, "domain", app[0].ResourceType)
+
+	older, err := s.ListActivity(core.ActivityFilter{Before: all[1].CreatedAt})
+	require.NoError(t, err)
+	assert.Len(t, older, 2)
+
+	assert.Error(t, s.RecordResourceActivity(&core.Activity{}, "not a resource"))
+}
@@ -0,0 +1,237 @@
+package store
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// APIKeyPrefix starts every API key, so leaked keys are easy to spot
+	APIKeyPrefix = "smp_"
+
+	// apiKeyBytes is the amount of randomness in a key
+	apiKeyBytes = 32
+
+	// apiKeyDisplayLen is how many leading characters of a key are kept
+	// in the clear to tell keys apart
+	apiKeyDisplayLen = len(APIKeyPrefix) + 8
+
+	// lastUsedResolution limits how often LastUsedAt is written, so that
+	// authenticating does not cost a write transaction per request
+	lastUsedResolution = time.Minute
+)
+
+// storedAPIKey is an API key as written to the database. Only the SHA-256
+// hash of the key is kept; the keys are random, so no salt is needed.
+type storedAPIKey struct {
+	core.APIKey
+	Hash []byte `json:"hash"`
+}
+
+// hashAPIKey returns the stored form of a key
+func hashAPIKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+// CreateAPIKey generates a new key for the user in apiKey.UserID. The
+// generated key is set in apiKey.Key; it cannot be read back later.
+func (s *Store) CreateAPIKey(apiKey *core.APIKey) error {
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return errors.NewInternalErrorWithCause("failed to generate API key", err)
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	if apiKey.ID == "" {
+		apiKey.ID = uuid.New().String()
+	}
+	apiKey.Prefix = key[:apiKeyDisplayLen]
+
+	record := storedAPIKey{APIKey: *apiKey, Hash: hashAPIKey(key)}
+	record.Key = ""
+
+	err := s.update("create", BucketAPIKeys, func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(BucketUsers)).Get([]byte(apiKey.UserID)) == nil {
+			return errors.NewNotFoundError("user", apiKey.UserID)
+		}
+		if err := putJSON(tx.Bucket([]byte(BucketAPIKeys)), "API key", record.ID, &record); err != nil {
+			return err
+		}
+		if err := tx.Bucket([]byte(bucketAPIKeyHashes)).Put(record.Hash, []byte(record.ID)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to index API key", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	apiKey.Key = key
+	return nil
+}
+
+// ListAPIKeys returns the API keys of a user, or of every user when userID
+// is empty. Keys are never included.
+func (s *Store) ListAPIKeys(userID string) ([]core.APIKey, error) {
+	records, err := genericList[storedAPIKey](s, BucketAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]core.APIKey, 0, len(records))
+	for i := range records {
+		if userID == "" || records[i].UserID == userID {
+			keys = append(keys, records[i].APIKey)
+		}
+	}
+	return keys, nil
+}
+
+// GetAPIKey retrieves an API key by ID, without the key itself
+func (s *Store) GetAPIKey(id string) (*core.APIKey, error) {
+	record, err := genericGet[storedAPIKey](s, BucketAPIKeys, id)
+	if err != nil {
+		return nil, err
+	}
+	return &record.APIKey, nil
+}
+
+// DeleteAPIKey revokes an API key by ID
+func (s *Store) DeleteAPIKey(id string) error {
+	return s.update("delete", BucketAPIKeys, func(tx *bbolt.Tx) error {
+		var record storedAPIKey
+		if err := getJSON(tx.Bucket([]byte(BucketAPIKeys)), "API key", id, &record); err != nil {
+			return err
+		}
+		return deleteAPIKey(tx, &record)
+	})
+}
+
+// AuthenticateAPIKey returns the user and key record for a presented key.
+// Unknown and expired keys both return NotFoundError, so callers cannot
+// tell them apart.
+func (s *Store) AuthenticateAPIKey(key string) (*core.User, *core.APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, nil, errors.NewNotFoundError("API key", "")
+	}
+	hash := hashAPIKey(key)
+	now := time.Now().UTC()
+
+	var (
+		record storedAPIKey
+		user   core.User
+	)
+	err := s.view("authenticate", BucketAPIKeys, func(tx *bbolt.Tx) error {
+		id := tx.Bucket([]byte(bucketAPIKeyHashes)).Get(hash)
+		if id == nil {
+			return errors.NewNotFoundError("API key", "")
+		}
+		if err := getJSON(tx.Bucket([]byte(BucketAPIKeys)), "API key", string(id), &record); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewNotFoundError("API key", "")
+			}
+			return err
+		}
+		if record.Expired(now) {
+			return errors.NewNotFoundError("API key", "")
+		}
+		return getJSON(tx.Bucket([]byte(BucketUsers)), "user", record.UserID, &user)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if record.LastUsedAt != nil && now.Sub(*record.LastUsedAt) < lastUsedResolution {
+		return &user, &record.APIKey, nil
+	}
+	err = s.update("touch", BucketAPIKeys, func(tx *bbolt.Tx) error {
+		// Reread the key, which may have been changed or revoked meanwhile
+		b := tx.Bucket([]byte(BucketAPIKeys))
+		if err := getJSON(b, "API key", record.ID, &record); err != nil {
+			if errors.IsNotFound(err) {
+				return errors.NewNotFoundError("API key", "")
+			}
+			return err
+		}
+		record.LastUsedAt = &now
+		return putJSON(b, "API key", record.ID, &record)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &user, &record.APIKey, nil
+}
+
+// deleteAPIKey removes a key and its index entry
+func deleteAPIKey(tx *bbolt.Tx, record *storedAPIKey) error {
+	if err := tx.Bucket([]byte(bucketAPIKeyHashes)).Delete(record.Hash); err != nil {
+		return errors.NewInternalErrorWithCause("failed to delete API key", err)
+	}
+	if err := tx.Bucket([]byte(BucketAPIKeys)).Delete([]byte(record.ID)); err != nil {
+		return errors.NewInternalErrorWithCause("failed to delete API key", err)
+	}
+	return nil
+}
+
+// deleteAPIKeysOf revokes every API key of a user
+func deleteAPIKeysOf(tx *bbolt.Tx, userID string) error {
+	b := tx.Bucket([]byte(BucketAPIKeys))
+
+	var records []storedAPIKey
+	err := b.ForEach(func(k, v []byte) error {
+		var record storedAPIKey
+		if err := json.Unmarshal(v, &record); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal API key", err)
+		}
+		if record.UserID == userID {
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		if err := deleteAPIKey(tx, &records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexAPIKeys rebuilds the index of API keys by hash from the stored keys
+func indexAPIKeys(tx *bbolt.Tx) error {
+	name := []byte(bucketAPIKeyHashes)
+	if tx.Bucket(name) != nil {
+		if err := tx.DeleteBucket(name); err != nil {
+			return errors.NewInternalErrorWithCause("failed to clear API key index", err)
+		}
+	}
+	index, err := tx.CreateBucket(name)
+	if err != nil {
+		return errors.NewInternalErrorWithCause("failed to create API key index", err)
+	}
+
+	return tx.Bucket([]byte(BucketAPIKeys)).ForEach(func(k, v []byte) error {
+		var record storedAPIKey
+		if err := json.Unmarshal(v, &record); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal API key", err)
+		}
+		if err := index.Put(record.Hash, bytes.Clone(k)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to index API key", err)
+		}
+		return nil
+	})
+}
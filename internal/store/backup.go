@@ -0,0 +1,148 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"go.etcd.io/bbolt"
+)
+
+// BackupSummary describes the contents of a snapshot
+type BackupSummary struct {
+	Records map[string]int `json:"records"` // record count per bucket
+	Size    int64          `json:"size"`
+}
+
+// Backup writes a consistent snapshot of the database to w.
+// It runs in a read transaction, so the store stays usable meanwhile.
+func (s *Store) Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := s.view("backup", "", func(tx *bbolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return n, errors.NewInternalErrorWithCause("failed to write backup", err)
+	}
+	return n, nil
+}
+
+// VerifyBackup opens the snapshot at path read-only and checks that it is a
+// consistent Simplify database whose records all decode as JSON.
+func VerifyBackup(path string) (*BackupSummary, error) {
+	db, err := openSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	summary := &BackupSummary{Records: make(map[string]int, len(allBuckets))}
+	err = db.View(func(tx *bbolt.Tx) error {
+		summary.Size = tx.Size()
+
+		// Drain the channel fully so the checker goroutine can exit
+		var corrupt error
+		for checkErr := range tx.Check() {
+			if corrupt == nil {
+				corrupt = checkErr
+			}
+		}
+		if corrupt != nil {
+			return errors.NewInvalidInputErrorWithCause("backup is corrupted", corrupt)
+		}
+
+		found := 0
+		for _, bucket := range allBuckets {
+			b := tx.Bucket([]byte(bucket))
+			if b == nil {
+				continue // snapshots from older versions may lack newer buckets
+			}
+			found++
+
+			err := b.ForEach(func(k, v []byte) error {
+				if !json.Valid(v) {
+					return errors.NewInvalidInputError(
+						fmt.Sprintf("backup record %s/%s is not valid JSON", bucket, k))
+				}
+				summary.Records[bucket]++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if found == 0 {
+			return errors.NewInvalidInputError("backup does not contain any Simplify data")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Restore replaces all data in the store with the snapshot at path.
+// The snapshot is verified first and copied in a single transaction,
+// so a failed restore leaves the current data untouched.
+func (s *Store) Restore(path string) (*BackupSummary, error) {
+	summary, err := VerifyBackup(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := openSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	err = src.View(func(srcTx *bbolt.Tx) error {
+		return s.update("restore", "", func(tx *bbolt.Tx) error {
+			for _, bucket := range allBuckets {
+				name := []byte(bucket)
+				if tx.Bucket(name) != nil {
+					if err := tx.DeleteBucket(name); err != nil {
+						return fmt.Errorf("clearing bucket %s: %w", bucket, err)
+					}
+				}
+
+				dst, err := tx.CreateBucket(name)
+				if err != nil {
+					return fmt.Errorf("creating bucket %s: %w", bucket, err)
+				}
+
+				b := srcTx.Bucket(name)
+				if b == nil {
+					continue
+				}
+				if err := b.ForEach(dst.Put); err != nil {
+					return fmt.Errorf("copying bucket %s: %w", bucket, err)
+				}
+			}
+			return indexAPIKeys(tx)
+		})
+	})
+	if err != nil {
+		return nil, errors.NewInternalErrorWithCause("failed to restore backup", err)
+	}
+	return summary, nil
+}
+
+// openSnapshot opens a backup file read-only
+func openSnapshot(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  1 * time.Second,
+	})
+	if err != nil {
+		return nil, errors.NewInvalidInputErrorWithCause(
+			fmt.Sprintf("failed to open backup %s", path), err)
+	}
+	return db, nil
+}
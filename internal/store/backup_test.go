@@ -0,0 +1,69 @@
+package store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.CreateTeam(&core.Team{ID: "t1", Name: "Platform"}))
+	require.NoError(t, s.CreateApplication(&core.Application{ID: "a1", Name: "web", Image: "nginx"}))
+	require.NoError(t, s.CreateUser(&core.User{ID: "u1", Username: "ci-bot"}))
+	key := &core.APIKey{UserID: "u1", Name: "ci"}
+	require.NoError(t, s.CreateAPIKey(key))
+
+	var buf bytes.Buffer
+	n, err := s.Backup(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	snapshot := filepath.Join(t.TempDir(), "backup.db")
+	require.NoError(t, os.WriteFile(snapshot, buf.Bytes(), 0o600))
+
+	summary, err := VerifyBackup(snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Records[BucketTeams])
+	assert.Equal(t, 1, summary.Records[BucketApplications])
+
+	// Changes made after the backup are discarded by the restore
+	require.NoError(t, s.CreateApplication(&core.Application{ID: "a2", Name: "api", Image: "api"}))
+	require.NoError(t, s.DeleteTeam("t1"))
+	later := &core.APIKey{UserID: "u1", Name: "later"}
+	require.NoError(t, s.CreateAPIKey(later))
+
+	_, err = s.Restore(snapshot)
+	require.NoError(t, err)
+
+	apps, err := s.ListApplications()
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "web", apps[0].Name)
+
+	team, err := s.GetTeam("t1")
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", team.Name)
+
+	// The index of API keys is rebuilt from the restored keys
+	_, _, err = s.AuthenticateAPIKey(key.Key)
+	require.NoError(t, err)
+	_, _, err = s.AuthenticateAPIKey(later.Key)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestVerifyBackupRejectsInvalidFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-db")
+	require.NoError(t, os.WriteFile(path, []byte("definitely not bolt"), 0o600))
+
+	_, err := VerifyBackup(path)
+	assert.True(t, errors.IsInvalidInput(err))
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// MaxBuildHistory is the number of builds kept per application. Older
+// finished builds are pruned as new ones are created.
+const MaxBuildHistory = 20
+
+// CreateBuild stores a new build, pruning the application's oldest
+// finished builds beyond MaxBuildHistory
+func (s *Store) CreateBuild(build *core.Build) error {
+	if build.ID == "" {
+		build.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketBuilds, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketBuilds))
+		if err := putJSON(b, "build", build.ID, build); err != nil {
+			return err
+		}
+
+		history, err := listBuilds(b, build.ApplicationID)
+		if err != nil {
+			return err
+		}
+		for i := MaxBuildHistory; i < len(history); i++ {
+			if !history[i].Finished() {
+				continue
+			}
+			if err := b.Delete([]byte(history[i].ID)); err != nil {
+				return errors.NewInternalErrorWithCause("failed to prune build", err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateBuild replaces a stored build
+func (s *Store) UpdateBuild(build *core.Build) error {
+	return s.genericUpdate(BucketBuilds, build.ID, build)
+}
+
+// GetBuild retrieves a build by ID
+func (s *Store) GetBuild(id string) (*core.Build, error) {
+	return genericGet[core.Build](s, BucketBuilds, id)
+}
+
+// ListBuilds returns the builds of an application, newest first
+func (s *Store) ListBuilds(appID string) ([]core.Build, error) {
+	var builds []core.Build
+	err := s.view("list", BucketBuilds, func(tx *bbolt.Tx) error {
+		var err error
+		builds, err = listBuilds(tx.Bucket([]byte(BucketBuilds)), appID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return builds, nil
+}
+
+// DeleteBuilds removes every build of an application
+func (s *Store) DeleteBuilds(appID string) error {
+	return s.update("delete", BucketBuilds, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketBuilds))
+		builds, err := listBuilds(b, appID)
+		if err != nil {
+			return err
+		}
+		for i := range builds {
+			if err := b.Delete([]byte(builds[i].ID)); err != nil {
+				return errors.NewInternalErrorWithCause("failed to delete build", err)
+			}
+		}
+		return nil
+	})
+}
+
+// FailUnfinishedBuilds marks builds that are queued or running as failed
+// with the given message. Builds run in the server process, so after a
+// restart none of them can still be running.
+func (s *Store) FailUnfinishedBuilds(message string) (int, error) {
+	failed := 0
+	err := s.update("update", BucketBuilds, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketBuilds))
+
+		var unfinished []core.Build
+		err := b.ForEach(func(k, v []byte) error {
+			var build core.Build
+			if err := json.Unmarshal(v, &build); err != nil {
+				return errors.NewInternalErrorWithCause("failed to unmarshal build", err)
+			}
+			if !build.Finished() {
+				unfinished = append(unfinished, build)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for i := range unfinished {
+			unfinished[i].Status = core.BuildFailed
+			unfinished[i].Error = message
+			unfinished[i].FinishedAt = &now
+			if err := putJSON(b, "build", unfinished[i].ID, &unfinished[i]); err != nil {
+				return err
+			}
+		}
+		failed = len(unfinished)
+		return nil
+	})
+	return failed, err
+}
+
+// listBuilds reads the builds of an application, newest first
+func listBuilds(b *bbolt.Bucket, appID string) ([]core.Build, error) {
+	var builds []core.Build
+	err := b.ForEach(func(k, v []byte) error {
+		// Skip decoding builds of other applications
+		if !bytes.Contains(v, []byte(`"application_id":"`+appID+`"`)) {
+			return nil
+		}
+		var build core.Build
+		if err := json.Unmarshal(v, &build); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal build", err)
+		}
+		if build.ApplicationID == appID {
+			builds = append(builds, build)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(builds, func(i, j int) bool { return builds[i].CreatedAt.After(builds[j].CreatedAt) })
+	return builds, nil
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilds(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	finished := start
+	for i := range MaxBuildHistory + 3 {
+		b := &core.Build{CreatedAt: start.Add(time.Duration(i) * time.Second), ApplicationID: "web", Status: core.BuildSucceeded, FinishedAt: &finished}
+		require.NoError(t, s.CreateBuild(b))
+		require.NotEmpty(t, b.ID)
+	}
+	other := &core.Build{CreatedAt: start, ApplicationID: "api", Status: core.BuildRunning}
+	require.NoError(t, s.CreateBuild(other))
+
+	builds, err := s.ListBuilds("web")
+	require.NoError(t, err)
+	require.Len(t, builds, MaxBuildHistory)
+	assert.True(t, builds[0].CreatedAt.After(builds[1].CreatedAt), "newest first")
+	assert.Equal(t, start.Add(3*time.Second), builds[MaxBuildHistory-1].CreatedAt)
+
+	// Builds that have not finished are failed on restart
+	failed, err := s.FailUnfinishedBuilds("interrupted")
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	got, err := s.GetBuild(other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, core.BuildFailed, got.Status)
+	assert.Equal(t, "interrupted", got.Error)
+	assert.NotNil(t, got.FinishedAt)
+
+	got.Log = "done"
+	require.NoError(t, s.UpdateBuild(got))
+
+	require.NoError(t, s.DeleteBuilds("web"))
+	builds, err = s.ListBuilds("web")
+	require.NoError(t, err)
+	assert.Empty(t, builds)
+	_, err = s.GetBuild(other.ID)
+	require.NoError(t, err)
+
+	_, err = s.GetBuild("missing")
+	assert.True(t, errors.IsNotFound(err))
+}
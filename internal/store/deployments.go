@@ -0,0 +1,122 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"go.etcd.io/bbolt"
+)
+
+// MaxDeploymentHistory is the number of deployments kept per application.
+// Older ones are pruned as new ones are recorded.
+const MaxDeploymentHistory = 20
+
+// deploymentKey keys deployments by application, so an application's
+// history is a contiguous key range
+func deploymentKey(appID, revision string) []byte {
+	return []byte(appID + "/" + revision)
+}
+
+// RecordDeployment stores a deployment, pruning the application's oldest
+// deployments beyond MaxDeploymentHistory
+func (s *Store) RecordDeployment(d *core.Deployment) error {
+	return s.update("create", BucketDeployments, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketDeployments))
+
+		data, err := json.Marshal(d)
+		if err != nil {
+			return errors.NewInternalErrorWithCause("failed to marshal deployment", err)
+		}
+		if err := b.Put(deploymentKey(d.ApplicationID, d.Revision), data); err != nil {
+			return errors.NewInternalErrorWithCause("failed to save deployment", err)
+		}
+
+		history, err := listDeployments(b, d.ApplicationID)
+		if err != nil {
+			return err
+		}
+		for i := MaxDeploymentHistory; i < len(history); i++ {
+			if err := b.Delete(deploymentKey(d.ApplicationID, history[i].Revision)); err != nil {
+				return errors.NewInternalErrorWithCause("failed to prune deployment", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListDeployments returns the deployments of an application, newest first
+func (s *Store) ListDeployments(appID string) ([]core.Deployment, error) {
+	var history []core.Deployment
+	err := s.view("list", BucketDeployments, func(tx *bbolt.Tx) error {
+		var err error
+		history, err = listDeployments(tx.Bucket([]byte(BucketDeployments)), appID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetDeployment retrieves the deployment of an application at revision
+func (s *Store) GetDeployment(appID, revision string) (*core.Deployment, error) {
+	var d core.Deployment
+	err := s.view("get", BucketDeployments, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(BucketDeployments)).Get(deploymentKey(appID, revision))
+		if data == nil {
+			return errors.NewNotFoundError("deployment", revision)
+		}
+		if err := json.Unmarshal(data, &d); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal deployment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// DeleteDeployments removes the history of an application
+func (s *Store) DeleteDeployments(appID string) error {
+	return s.update("delete", BucketDeployments, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketDeployments))
+		prefix := deploymentKey(appID, "")
+
+		var keys [][]byte
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return errors.NewInternalErrorWithCause("failed to delete deployment", err)
+			}
+		}
+		return nil
+	})
+}
+
+// listDeployments reads an application's deployments, newest first
+func listDeployments(b *bbolt.Bucket, appID string) ([]core.Deployment, error) {
+	prefix := deploymentKey(appID, "")
+	history := []core.Deployment{}
+
+	c := b.Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		var d core.Deployment
+		if err := json.Unmarshal(v, &d); err != nil {
+			return nil, errors.NewInternalErrorWithCause("failed to unmarshal deployment", err)
+		}
+		history = append(history, d)
+	}
+
+	// Revisions are timestamps without fixed width, so sort by time
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.After(history[j].CreatedAt)
+	})
+	return history, nil
+}
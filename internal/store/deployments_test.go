@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentHistory(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	app := &core.Application{ID: "web", Name: "web"}
+	for i := range MaxDeploymentHistory + 5 {
+		app.UpdatedAt = start.Add(time.Duration(i) * time.Second)
+		app.Image = "nginx:" + app.UpdatedAt.Format("150405")
+		require.NoError(t, s.RecordDeployment(core.NewDeployment(app, "alice@host")))
+	}
+	// Another application's history is separate
+	other := &core.Application{ID: "web-2", Name: "web-2", Image: "redis", UpdatedAt: start}
+	require.NoError(t, s.RecordDeployment(core.NewDeployment(other, "bob@host")))
+
+	history, err := s.ListDeployments("web")
+	require.NoError(t, err)
+	require.Len(t, history, MaxDeploymentHistory, "older deployments are pruned")
+	assert.Equal(t, app.Revision(), history[0].Revision, "newest first")
+	assert.Equal(t, "alice@host", history[0].Actor)
+
+	d, err := s.GetDeployment("web", history[1].Revision)
+	require.NoError(t, err)
+	assert.Equal(t, history[1].Image, d.Image)
+
+	_, err = s.GetDeployment("web", start.Format(time.RFC3339Nano))
+	assert.True(t, errors.IsNotFound(err), "pruned deployments are gone")
+
+	require.NoError(t, s.DeleteDeployments("web"))
+	history, err = s.ListDeployments("web")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	history, err = s.ListDeployments("web-2")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// ListDomains retrieves all domains from the database
+func (s *Store) ListDomains() ([]core.Domain, error) {
+	return genericList[core.Domain](s, BucketDomains)
+}
+
+// GetDomain retrieves a single domain by ID
+func (s *Store) GetDomain(id string) (*core.Domain, error) {
+	return genericGet[core.Domain](s, BucketDomains, id)
+}
+
+// CreateDomain stores a new domain. Hostnames must be unique, as each can
+// only route to one application.
+func (s *Store) CreateDomain(domain *core.Domain) error {
+	if domain.ID == "" {
+		domain.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketDomains, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketDomains))
+
+		// Check for duplicate hostname
+		err := b.ForEach(func(k, v []byte) error {
+			var existing core.Domain
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return nil
+			}
+			if existing.Hostname == domain.Hostname {
+				return errors.NewAlreadyExistsError("domain", domain.Hostname)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return putJSON(b, "domain", domain.ID, domain)
+	})
+}
+
+// UpdateDomain updates an existing domain.
+// Returns NotFoundError if the domain doesn't exist.
+func (s *Store) UpdateDomain(domain *core.Domain) error {
+	return s.genericUpdate(BucketDomains, domain.ID, domain)
+}
+
+// DeleteDomain removes a domain from the database
+func (s *Store) DeleteDomain(id string) error {
+	return s.update("delete", BucketDomains, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketDomains))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("domain", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete domain", err)
+		}
+		return nil
+	})
+}
@@ -9,7 +9,7 @@ import (
 
 // genericCreate stores an item in the specified bucket using the provided ID key.
 func (s *Store) genericCreate(bucketName, id string, item any) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("create", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -32,7 +32,7 @@ func (s *Store) genericCreate(bucketName, id string, item any) error {
 // Returns NotFoundError if the item doesn't exist.
 func genericGet[T any](s *Store, bucketName, id string) (*T, error) {
 	var item T
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.view("get", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -60,7 +60,7 @@ func genericGet[T any](s *Store, bucketName, id string) (*T, error) {
 func genericList[T any](s *Store, bucketName string) ([]T, error) {
 	var items []T
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.view("list", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -90,7 +90,7 @@ func genericList[T any](s *Store, bucketName string) ([]T, error) {
 // genericDelete removes an item by ID from the specified bucket.
 // Note: BoltDB Delete is idempotent - it doesn't error if the key doesn't exist.
 func (s *Store) genericDelete(bucketName, id string) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("delete", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -103,7 +103,7 @@ func (s *Store) genericDelete(bucketName, id string) error {
 // genericExists checks if an item exists in the specified bucket.
 func (s *Store) genericExists(bucketName, id string) (bool, error) {
 	var exists bool
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.view("exists", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -118,7 +118,7 @@ func (s *Store) genericExists(bucketName, id string) (bool, error) {
 // genericUpdate updates an existing item in the specified bucket.
 // Returns NotFoundError if the item doesn't exist.
 func (s *Store) genericUpdate(bucketName, id string, item any) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("update", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -145,7 +145,7 @@ func (s *Store) genericUpdate(bucketName, id string, item any) error {
 // genericCreateIfNotExists creates an item only if it doesn't already exist.
 // Returns AlreadyExistsError if the item exists.
 func (s *Store) genericCreateIfNotExists(bucketName, id string, item any) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("create", bucketName, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(bucketName))
 		if b == nil {
 			return errors.NewInternalError("bucket " + bucketName + " not found")
@@ -168,3 +168,27 @@ func (s *Store) genericCreateIfNotExists(bucketName, id string, item any) error
 		return nil
 	})
 }
+
+// getJSON reads and decodes the record stored under id
+func getJSON(b *bbolt.Bucket, resource, id string, v any) error {
+	data := b.Get([]byte(id))
+	if data == nil {
+		return errors.NewNotFoundError(resource, id)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.NewInternalErrorWithCause("failed to unmarshal "+resource, err)
+	}
+	return nil
+}
+
+// putJSON encodes and writes a record under id
+func putJSON(b *bbolt.Bucket, resource, id string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.NewInternalErrorWithCause("failed to marshal "+resource, err)
+	}
+	if err := b.Put([]byte(id), data); err != nil {
+		return errors.NewInternalErrorWithCause("failed to save "+resource, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// ListLinks retrieves all links between applications
+func (s *Store) ListLinks() ([]core.Link, error) {
+	return genericList[core.Link](s, BucketLinks)
+}
+
+// GetLink retrieves a single link by ID
+func (s *Store) GetLink(id string) (*core.Link, error) {
+	return genericGet[core.Link](s, BucketLinks, id)
+}
+
+// CreateLink stores a new link. A second link of the same type between the
+// same applications is a conflict, and a depends_on link that would close a
+// dependency cycle is invalid.
+func (s *Store) CreateLink(link *core.Link) error {
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketLinks, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketLinks))
+
+		var links []core.Link
+		err := b.ForEach(func(k, v []byte) error {
+			var existing core.Link
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return nil
+			}
+			if existing.From == link.From && existing.To == link.To && existing.Type == link.Type {
+				return errors.NewAlreadyExistsError("link", fmt.Sprintf("%s %s %s", link.From, link.Type, link.To))
+			}
+			links = append(links, existing)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if link.Type == core.LinkDependsOn {
+			links = append(links, *link)
+			ids := make([]string, 0, 2*len(links))
+			for i := range links {
+				ids = append(ids, links[i].From, links[i].To)
+			}
+			if _, err := core.OrderByDependencies(ids, links); err != nil {
+				return errors.NewInvalidInputErrorWithField("to", err.Error())
+			}
+		}
+
+		return putJSON(b, "link", link.ID, link)
+	})
+}
+
+// DeleteLink removes a link
+func (s *Store) DeleteLink(id string) error {
+	return s.update("delete", BucketLinks, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketLinks))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("link", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete link", err)
+		}
+		return nil
+	})
+}
+
+// DeleteLinksOf removes the links from and to an application
+func (s *Store) DeleteLinksOf(appID string) error {
+	return s.update("delete", BucketLinks, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketLinks))
+
+		var ids [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var link core.Link
+			if err := json.Unmarshal(v, &link); err != nil {
+				return nil
+			}
+			if link.From == appID || link.To == appID {
+				ids = append(ids, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := b.Delete(id); err != nil {
+				return errors.NewInternalErrorWithCause("failed to delete link", err)
+			}
+		}
+		return nil
+	})
+}
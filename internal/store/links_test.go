@@ -0,0 +1,47 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	webAPI := &core.Link{From: "web", To: "api", Type: core.LinkDependsOn}
+	require.NoError(t, s.CreateLink(webAPI))
+	require.NotEmpty(t, webAPI.ID)
+	require.NoError(t, s.CreateLink(&core.Link{From: "api", To: "db", Type: core.LinkDependsOn}))
+	require.NoError(t, s.CreateLink(&core.Link{From: "web", To: "api", Type: core.LinkConnectsTo}))
+
+	err := s.CreateLink(&core.Link{From: "web", To: "api", Type: core.LinkDependsOn})
+	assert.True(t, errors.IsAlreadyExists(err), "duplicate link")
+
+	err = s.CreateLink(&core.Link{From: "db", To: "web", Type: core.LinkDependsOn})
+	require.Error(t, err, "cycle")
+	assert.Contains(t, err.Error(), "dependency cycle")
+	// connects_to links may point either way
+	require.NoError(t, s.CreateLink(&core.Link{From: "db", To: "web", Type: core.LinkConnectsTo}))
+
+	links, err := s.ListLinks()
+	require.NoError(t, err)
+	assert.Len(t, links, 4)
+
+	order, err := core.OrderByDependencies([]string{"web", "cache", "api", "db"}, links)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "api", "web", "cache"}, order)
+
+	require.NoError(t, s.DeleteLink(webAPI.ID))
+	assert.True(t, errors.IsNotFound(s.DeleteLink(webAPI.ID)))
+
+	require.NoError(t, s.DeleteLinksOf("db"))
+	links, err = s.ListLinks()
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, core.LinkConnectsTo, links[0].Type)
+}
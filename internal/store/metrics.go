@@ -0,0 +1,119 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"go.etcd.io/bbolt"
+)
+
+// metricKey keys samples by application and then time, so an application's
+// samples are a contiguous key range in time order
+func metricKey(appID string, t time.Time) []byte {
+	return fmt.Appendf(nil, "%s/%020d", appID, t.UnixNano())
+}
+
+// metricTime reads the time of a sample back from its key
+func metricTime(key []byte) (time.Time, bool) {
+	i := bytes.LastIndexByte(key, '/')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(string(key[i+1:]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos).UTC(), true
+}
+
+// RecordMetrics stores one sample per application, keyed by application ID
+func (s *Store) RecordMetrics(samples map[string]core.MetricSample) error {
+	return s.update("create", BucketMetrics, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketMetrics))
+		for appID, sample := range samples {
+			data, err := json.Marshal(sample)
+			if err != nil {
+				return errors.NewInternalErrorWithCause("failed to marshal metric sample", err)
+			}
+			if err := b.Put(metricKey(appID, sample.Time), data); err != nil {
+				return errors.NewInternalErrorWithCause("failed to save metric sample", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListMetrics returns the samples of an application taken since the given
+// time, oldest first
+func (s *Store) ListMetrics(appID string, since time.Time) ([]core.MetricSample, error) {
+	samples := []core.MetricSample{}
+	err := s.view("list", BucketMetrics, func(tx *bbolt.Tx) error {
+		prefix := []byte(appID + "/")
+		c := tx.Bucket([]byte(BucketMetrics)).Cursor()
+		for k, v := c.Seek(metricKey(appID, since)); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var sample core.MetricSample
+			if err := json.Unmarshal(v, &sample); err != nil {
+				return errors.NewInternalErrorWithCause("failed to unmarshal metric sample", err)
+			}
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// PruneMetrics removes samples taken before the given time and returns how
+// many were removed
+func (s *Store) PruneMetrics(before time.Time) (int, error) {
+	pruned := 0
+	err := s.update("delete", BucketMetrics, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketMetrics))
+
+		var keys [][]byte
+		err := b.ForEach(func(k, _ []byte) error {
+			if t, ok := metricTime(k); !ok || t.Before(before) {
+				keys = append(keys, bytes.Clone(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return errors.NewInternalErrorWithCause("failed to prune metric sample", err)
+			}
+		}
+		pruned = len(keys)
+		return nil
+	})
+	return pruned, err
+}
+
+// DeleteMetrics removes every sample of an application
+func (s *Store) DeleteMetrics(appID string) error {
+	return s.update("delete", BucketMetrics, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketMetrics))
+		prefix := []byte(appID + "/")
+
+		var keys [][]byte
+		c := b.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, bytes.Clone(k))
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return errors.NewInternalErrorWithCause("failed to delete metric sample", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 10 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, s.RecordMetrics(map[string]core.MetricSample{
+			"web":   {Time: at, CPUPercent: float64(i), Replicas: 2},
+			"web-2": {Time: at, MemoryBytes: 1 << 20, Replicas: 1},
+		}))
+	}
+
+	samples, err := s.ListMetrics("web", start.Add(7*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 3, "samples since the given time")
+	assert.Equal(t, 7.0, samples[0].CPUPercent, "oldest first")
+	assert.Equal(t, 9.0, samples[2].CPUPercent)
+	assert.Equal(t, 2, samples[0].Replicas)
+
+	pruned, err := s.PruneMetrics(start.Add(5 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 10, pruned, "five samples of each application")
+
+	samples, err = s.ListMetrics("web", start)
+	require.NoError(t, err)
+	require.Len(t, samples, 5)
+	assert.True(t, samples[0].Time.Equal(start.Add(5*time.Minute)))
+
+	require.NoError(t, s.DeleteMetrics("web"))
+	samples, err = s.ListMetrics("web", start)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+
+	samples, err = s.ListMetrics("web-2", start)
+	require.NoError(t, err)
+	assert.Len(t, samples, 5, "other applications keep their samples")
+}
@@ -13,7 +13,7 @@ import (
 func (s *Store) ListNetworks() ([]core.Network, error) {
 	var networks []core.Network
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.view("list", BucketNetworks, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(BucketNetworks))
 		if b == nil {
 			return nil // Bucket might not exist yet if freshly migrated
@@ -40,7 +40,7 @@ func (s *Store) ListNetworks() ([]core.Network, error) {
 func (s *Store) GetNetwork(id string) (*core.Network, error) {
 	var network core.Network
 
-	err := s.db.View(func(tx *bbolt.Tx) error {
+	err := s.view("get", BucketNetworks, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(BucketNetworks))
 		v := b.Get([]byte(id))
 		if v == nil {
@@ -66,7 +66,7 @@ func (s *Store) CreateNetwork(network *core.Network) error {
 		network.ID = uuid.New().String()
 	}
 
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("create", BucketNetworks, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(BucketNetworks))
 
 		// Check for duplicate name
@@ -99,7 +99,7 @@ func (s *Store) CreateNetwork(network *core.Network) error {
 
 // DeleteNetwork removes a network from the database
 func (s *Store) DeleteNetwork(id string) error {
-	return s.db.Update(func(tx *bbolt.Tx) error {
+	return s.update("delete", BucketNetworks, func(tx *bbolt.Tx) error {
 		b := tx.Bucket([]byte(BucketNetworks))
 		if b.Get([]byte(id)) == nil {
 			return errors.NewNotFoundError("network", id)
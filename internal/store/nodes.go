@@ -0,0 +1,236 @@
+package store
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// NodeTokenPrefix starts every node token
+const NodeTokenPrefix = "smn_"
+
+// storedNode is a node as written to the database, with the SHA-256 hash of
+// the token its agent authenticates with
+type storedNode struct {
+	Hash []byte `json:"hash"`
+	core.Node
+}
+
+// ListNodes returns all nodes
+func (s *Store) ListNodes() ([]core.Node, error) {
+	records, err := genericList[storedNode](s, BucketNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]core.Node, 0, len(records))
+	for i := range records {
+		nodes = append(nodes, records[i].Node)
+	}
+	return nodes, nil
+}
+
+// GetNode retrieves a node by ID
+func (s *Store) GetNode(id string) (*core.Node, error) {
+	record, err := genericGet[storedNode](s, BucketNodes, id)
+	if err != nil {
+		return nil, err
+	}
+	return &record.Node, nil
+}
+
+// RegisterNode stores a node and issues a new token for its agent, set in
+// node.Token. A node registering under an existing name replaces it and
+// keeps its ID, so applications scheduled to it stay there; this needs
+// current, the token issued to that node, so one agent cannot take over
+// another's node.
+func (s *Store) RegisterNode(node *core.Node, current string) error {
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return errors.NewInternalErrorWithCause("failed to generate node token", err)
+	}
+	token := NodeTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.update("register", BucketNodes, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketNodes))
+
+		err := b.ForEach(func(k, v []byte) error {
+			var existing storedNode
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return errors.NewInternalErrorWithCause("failed to unmarshal node", err)
+			}
+			if existing.Name == node.Name {
+				if subtle.ConstantTimeCompare(existing.Hash, hashAPIKey(current)) != 1 {
+					return errors.NewPermissionError("node " + node.Name + " is already registered; re-registering it needs its current token")
+				}
+				node.ID = existing.ID
+				node.CreatedAt = existing.CreatedAt
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if node.ID == "" {
+			node.ID = uuid.New().String()
+		}
+
+		record := storedNode{Node: *node, Hash: hashAPIKey(token)}
+		record.Token = ""
+		record.Status = ""
+		return putJSON(b, "node", record.ID, &record)
+	})
+	if err != nil {
+		return err
+	}
+
+	node.Token = token
+	return nil
+}
+
+// AuthenticateNode returns the node with the given ID if token is its
+// current token. A wrong token and an unknown node both return
+// NotFoundError, so the agent registers again.
+func (s *Store) AuthenticateNode(id, token string) (*core.Node, error) {
+	record, err := genericGet[storedNode](s, BucketNodes, id)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(record.Hash, hashAPIKey(token)) != 1 {
+		return nil, errors.NewNotFoundError("node", id)
+	}
+	return &record.Node, nil
+}
+
+// RecordHeartbeat updates a node's capacity and application statuses and
+// health and marks it alive at the given time
+func (s *Store) RecordHeartbeat(id string, capacity core.NodeCapacity, appStatus, appHealth map[string]string, at time.Time) (*core.Node, error) {
+	var record storedNode
+	err := s.update("heartbeat", BucketNodes, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketNodes))
+		if err := getJSON(b, "node", id, &record); err != nil {
+			return err
+		}
+		record.Capacity = capacity
+		record.AppStatus = appStatus
+		record.AppHealth = appHealth
+		record.LastHeartbeat = at
+		return putJSON(b, "node", id, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record.Node, nil
+}
+
+// DeleteNode removes a node. Applications scheduled to it are unscheduled
+// in the same transaction.
+func (s *Store) DeleteNode(id string) error {
+	return s.update("delete", BucketNodes, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketNodes))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("node", id)
+		}
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete node", err)
+		}
+
+		apps := tx.Bucket([]byte(BucketApplications))
+		var unscheduled []core.Application
+		err := apps.ForEach(func(k, v []byte) error {
+			var app core.Application
+			if err := json.Unmarshal(v, &app); err != nil {
+				return errors.NewInternalErrorWithCause("failed to unmarshal application", err)
+			}
+			if app.NodeID == id {
+				app.NodeID = ""
+				unscheduled = append(unscheduled, app)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range unscheduled {
+			if err := putJSON(apps, "application", unscheduled[i].ID, &unscheduled[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SyncNodeState replaces the applications, environments, pods, networks,
+// volumes, secrets and stacks of the store with state, in one transaction. The agent keeps
+// its copy of the server's state this way, so its reconciler runs from the
+// store as the server's does and keeps running when the server is away.
+func (s *Store) SyncNodeState(state *core.NodeState) error {
+	secrets := make([]*storedSecret, 0, len(state.Secrets))
+	for i := range state.Secrets {
+		record, err := s.seal(&state.Secrets[i])
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, record)
+	}
+
+	return s.update("sync", BucketApplications, func(tx *bbolt.Tx) error {
+		for _, name := range []string{BucketApplications, BucketEnvironments, BucketPods, BucketNetworks, BucketVolumes, BucketSecrets, BucketStacks, BucketLinks} {
+			if err := tx.DeleteBucket([]byte(name)); err != nil {
+				return errors.NewInternalErrorWithCause("failed to clear bucket "+name, err)
+			}
+			if _, err := tx.CreateBucket([]byte(name)); err != nil {
+				return errors.NewInternalErrorWithCause("failed to create bucket "+name, err)
+			}
+		}
+
+		for i := range state.Applications {
+			if err := putJSON(tx.Bucket([]byte(BucketApplications)), "application", state.Applications[i].ID, &state.Applications[i]); err != nil {
+				return err
+			}
+		}
+		for i := range state.Environments {
+			if err := putJSON(tx.Bucket([]byte(BucketEnvironments)), "environment", state.Environments[i].ID, &state.Environments[i]); err != nil {
+				return err
+			}
+		}
+		for i := range state.Pods {
+			if err := putJSON(tx.Bucket([]byte(BucketPods)), "pod", state.Pods[i].ID, &state.Pods[i]); err != nil {
+				return err
+			}
+		}
+		for i := range state.Networks {
+			if err := putJSON(tx.Bucket([]byte(BucketNetworks)), "network", state.Networks[i].ID, &state.Networks[i]); err != nil {
+				return err
+			}
+		}
+		for i := range state.Volumes {
+			if err := putJSON(tx.Bucket([]byte(BucketVolumes)), "volume", state.Volumes[i].ID, &state.Volumes[i]); err != nil {
+				return err
+			}
+		}
+		for _, record := range secrets {
+			if err := putSecret(tx.Bucket([]byte(BucketSecrets)), record); err != nil {
+				return err
+			}
+		}
+		for i := range state.Stacks {
+			if err := putJSON(tx.Bucket([]byte(BucketStacks)), "stack", state.Stacks[i].ID, &state.Stacks[i]); err != nil {
+				return err
+			}
+		}
+		for i := range state.Links {
+			if err := putJSON(tx.Bucket([]byte(BucketLinks)), "link", state.Links[i].ID, &state.Links[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
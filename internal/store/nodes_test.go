@@ -0,0 +1,109 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodes(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	node := &core.Node{Name: "worker-1", Labels: map[string]string{"region": "eu"}}
+	require.NoError(t, s.RegisterNode(node, ""))
+	require.NotEmpty(t, node.ID)
+	require.True(t, len(node.Token) > len(NodeTokenPrefix))
+	first := node.Token
+
+	_, err := s.AuthenticateNode(node.ID, first)
+	require.NoError(t, err)
+	_, err = s.AuthenticateNode(node.ID, "smn_wrong")
+	assert.True(t, errors.IsNotFound(err))
+
+	// Registering the same name needs the node's current token
+	intruder := &core.Node{Name: "worker-1"}
+	assert.True(t, errors.IsPermissionError(s.RegisterNode(intruder, "")))
+	assert.True(t, errors.IsPermissionError(s.RegisterNode(intruder, "smn_wrong")))
+	assert.Empty(t, intruder.Token)
+	_, err = s.AuthenticateNode(node.ID, first)
+	require.NoError(t, err)
+
+	// and keeps the ID and replaces the token
+	again := &core.Node{Name: "worker-1", Labels: map[string]string{"region": "us"}}
+	require.NoError(t, s.RegisterNode(again, first))
+	assert.Equal(t, node.ID, again.ID)
+	_, err = s.AuthenticateNode(node.ID, first)
+	assert.True(t, errors.IsNotFound(err))
+
+	stored, err := s.GetNode(node.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "us", stored.Labels["region"])
+	assert.Empty(t, stored.Token)
+
+	now := time.Now().UTC()
+	updated, err := s.RecordHeartbeat(node.ID, core.NodeCapacity{CPUs: 4}, map[string]string{"app": "running"}, map[string]string{"app": core.HealthHealthy}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 4, updated.Capacity.CPUs)
+	assert.True(t, updated.Ready(now))
+	assert.False(t, updated.Ready(now.Add(core.NodeHeartbeatTimeout)))
+
+	// Deleting a node unschedules its applications
+	app := &core.Application{ID: "app", Name: "app", NodeID: node.ID, NodeSelector: map[string]string{"region": "us"}}
+	require.NoError(t, s.CreateApplication(app))
+	require.NoError(t, s.DeleteNode(node.ID))
+	got, err := s.GetApplication("app")
+	require.NoError(t, err)
+	assert.Empty(t, got.NodeID)
+	assert.True(t, errors.IsNotFound(s.DeleteNode(node.ID)))
+}
+
+func TestSyncNodeState(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	key, err := LoadSecretKey(filepath.Join(t.TempDir(), "secret.key"))
+	require.NoError(t, err)
+	require.NoError(t, s.SetSecretKey(key))
+
+	require.NoError(t, s.CreateApplication(&core.Application{ID: "stale", Name: "stale"}))
+	require.NoError(t, s.CreateTeam(&core.Team{ID: "team", Name: "Team"}))
+
+	state := &core.NodeState{
+		Applications: []core.Application{{ID: "web", Name: "web", NetworkID: "net", EnvironmentID: "prod"}},
+		Environments: []core.Environment{{ID: "prod", Name: "prod", Limits: &core.EnvironmentLimits{MaxPorts: 2}}},
+		Networks:     []core.Network{{ID: "net", Name: "shop_default"}},
+		Volumes:      []core.Volume{{ID: "vol", Name: "data"}},
+		Secrets:      []core.Secret{{ID: "sec", Name: "db-password", Value: "hunter2"}},
+		Stacks:       []core.Stack{{ID: "shop", Name: "shop", ApplicationIDs: []string{"web"}}},
+	}
+	require.NoError(t, s.SyncNodeState(state))
+
+	apps, err := s.ListApplications()
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, "web", apps[0].ID)
+
+	_, err = s.GetNetwork("net")
+	require.NoError(t, err)
+	env, err := s.GetEnvironment("prod")
+	require.NoError(t, err)
+	assert.Equal(t, 2, env.Limits.MaxPorts)
+	value, err := s.SecretValue("db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	// Other buckets are left alone
+	_, err = s.GetTeam("team")
+	require.NoError(t, err)
+
+	require.NoError(t, s.SyncNodeState(&core.NodeState{}))
+	apps, err = s.ListApplications()
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+}
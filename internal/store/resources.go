@@ -1,6 +1,10 @@
 package store
 
-import "github.com/AkMo3/simplify/internal/core"
+import (
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"go.etcd.io/bbolt"
+)
 
 // =============================================================================
 // Pod Methods
@@ -64,6 +68,34 @@ func (s *Store) UpdateApplication(app *core.Application) error {
 	return s.genericUpdate(BucketApplications, app.ID, app)
 }
 
+// SetApplicationHealth records the health status of an application in one
+// transaction, leaving the rest of the stored spec as it is
+func (s *Store) SetApplicationHealth(id, status string) error {
+	return s.update("health", BucketApplications, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketApplications))
+		var app core.Application
+		if err := getJSON(b, "application", id, &app); err != nil {
+			return err
+		}
+		app.HealthStatus = status
+		return putJSON(b, "application", id, &app)
+	})
+}
+
+// SetApplicationAlerting records the metrics of an application whose alerts
+// fire, leaving the rest of the stored spec as it is
+func (s *Store) SetApplicationAlerting(id string, metrics []string) error {
+	return s.update("alerting", BucketApplications, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketApplications))
+		var app core.Application
+		if err := getJSON(b, "application", id, &app); err != nil {
+			return err
+		}
+		app.Alerting = metrics
+		return putJSON(b, "application", id, &app)
+	})
+}
+
 // DeleteApplication removes an application by ID.
 func (s *Store) DeleteApplication(id string) error {
 	return s.genericDelete(BucketApplications, id)
@@ -100,9 +132,14 @@ func (s *Store) UpdateTeam(team *core.Team) error {
 	return s.genericUpdate(BucketTeams, team.ID, team)
 }
 
-// DeleteTeam removes a team by ID.
+// DeleteTeam removes a team by ID, along with its users' memberships.
 func (s *Store) DeleteTeam(id string) error {
-	return s.genericDelete(BucketTeams, id)
+	return s.update("delete", BucketTeams, func(tx *bbolt.Tx) error {
+		if err := tx.Bucket([]byte(BucketTeams)).Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete team", err)
+		}
+		return removeTeamMemberships(tx, id)
+	})
 }
 
 // TeamExists checks if a team exists.
@@ -181,3 +218,24 @@ func (s *Store) DeleteEnvironment(id string) error {
 func (s *Store) EnvironmentExists(id string) (bool, error) {
 	return s.genericExists(BucketEnvironments, id)
 }
+
+// ApplicationTeam returns the team owning an application through its
+// environment and project, or nil when the application is not in a
+// project with a team
+func (s *Store) ApplicationTeam(app *core.Application) (*core.Team, error) {
+	if app.EnvironmentID == "" {
+		return nil, nil
+	}
+	env, err := s.GetEnvironment(app.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+	project, err := s.GetProject(env.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.TeamID == "" {
+		return nil, nil
+	}
+	return s.GetTeam(project.TeamID)
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// SecretKeySize is the size of the AES-256 key that encrypts secret values
+const SecretKeySize = 32
+
+// storedSecret is a secret as written to the database. The value is only
+// kept encrypted, sealed with the secret ID as additional data so that
+// ciphertexts cannot be swapped between records.
+type storedSecret struct {
+	core.Secret
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// LoadSecretKey reads the secret encryption key at path, generating and
+// saving a new random key (mode 0600) when the file does not exist
+func LoadSecretKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path) //nolint:gosec // key path comes from the operator's config
+	if err == nil {
+		if len(key) != SecretKeySize {
+			return nil, fmt.Errorf("secret key %s must be %d bytes, got %d", path, SecretKeySize, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read secret key: %w", err)
+	}
+
+	key = make([]byte, SecretKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate secret key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create secret key directory: %w", err)
+	}
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write secret key: %w", err)
+	}
+	return key, nil
+}
+
+// SetSecretKey sets the AES-256 key that encrypts secret values. Secrets
+// cannot be stored or read until a key is set.
+func (s *Store) SetSecretKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("invalid secret key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("invalid secret key: %w", err)
+	}
+	s.secrets = aead
+	return nil
+}
+
+// seal encrypts a secret's value into a record for the database
+func (s *Store) seal(secret *core.Secret) (*storedSecret, error) {
+	if s.secrets == nil {
+		return nil, errors.NewInternalError("secret encryption key is not configured")
+	}
+	nonce := make([]byte, s.secrets.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.NewInternalErrorWithCause("failed to generate nonce", err)
+	}
+
+	record := &storedSecret{Secret: *secret}
+	record.Value = ""
+	record.Ciphertext = s.secrets.Seal(nonce, nonce, []byte(secret.Value), []byte(secret.ID))
+	return record, nil
+}
+
+// open decrypts a stored secret's value
+func (s *Store) open(record *storedSecret) (string, error) {
+	if s.secrets == nil {
+		return "", errors.NewInternalError("secret encryption key is not configured")
+	}
+	n := s.secrets.NonceSize()
+	if len(record.Ciphertext) < n {
+		return "", errors.NewInternalError("secret " + record.Name + " is corrupted")
+	}
+	value, err := s.secrets.Open(nil, record.Ciphertext[:n], record.Ciphertext[n:], []byte(record.ID))
+	if err != nil {
+		return "", errors.NewInternalErrorWithCause("failed to decrypt secret "+record.Name+" (wrong key?)", err)
+	}
+	return string(value), nil
+}
+
+// ListSecrets returns all secrets without their values
+func (s *Store) ListSecrets() ([]core.Secret, error) {
+	records, err := genericList[storedSecret](s, BucketSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]core.Secret, 0, len(records))
+	for i := range records {
+		secrets = append(secrets, records[i].Secret)
+	}
+	return secrets, nil
+}
+
+// GetSecret retrieves a secret by ID, without its value
+func (s *Store) GetSecret(id string) (*core.Secret, error) {
+	record, err := genericGet[storedSecret](s, BucketSecrets, id)
+	if err != nil {
+		return nil, err
+	}
+	return &record.Secret, nil
+}
+
+// SecretValue returns the decrypted value of the secret with the given name
+func (s *Store) SecretValue(name string) (string, error) {
+	var record *storedSecret
+	err := s.view("get", BucketSecrets, func(tx *bbolt.Tx) error {
+		var err error
+		record, err = findSecretByName(tx.Bucket([]byte(BucketSecrets)), name)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if record == nil {
+		return "", errors.NewNotFoundError("secret", name)
+	}
+	return s.open(record)
+}
+
+// CreateSecret encrypts and stores a new secret. Names must be unique.
+func (s *Store) CreateSecret(secret *core.Secret) error {
+	if secret.ID == "" {
+		secret.ID = uuid.New().String()
+	}
+	record, err := s.seal(secret)
+	if err != nil {
+		return err
+	}
+
+	return s.update("create", BucketSecrets, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketSecrets))
+
+		existing, err := findSecretByName(b, secret.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return errors.NewAlreadyExistsError("secret", secret.Name)
+		}
+		return putSecret(b, record)
+	})
+}
+
+// UpdateSecret replaces the value of an existing secret. The name cannot
+// be changed.
+func (s *Store) UpdateSecret(secret *core.Secret) error {
+	record, err := s.seal(secret)
+	if err != nil {
+		return err
+	}
+
+	return s.update("update", BucketSecrets, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketSecrets))
+
+		data := b.Get([]byte(secret.ID))
+		if data == nil {
+			return errors.NewNotFoundError("secret", secret.ID)
+		}
+		var existing storedSecret
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal secret", err)
+		}
+		record.Name = existing.Name
+		record.CreatedAt = existing.CreatedAt
+		return putSecret(b, record)
+	})
+}
+
+// DeleteSecret removes a secret by ID
+func (s *Store) DeleteSecret(id string) error {
+	return s.update("delete", BucketSecrets, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketSecrets))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("secret", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete secret", err)
+		}
+		return nil
+	})
+}
+
+// findSecretByName returns the stored secret with the given name, or nil
+func findSecretByName(b *bbolt.Bucket, name string) (*storedSecret, error) {
+	var found *storedSecret
+	err := b.ForEach(func(k, v []byte) error {
+		if found != nil {
+			return nil
+		}
+		var record storedSecret
+		if err := json.Unmarshal(v, &record); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal secret", err)
+		}
+		if record.Name == name {
+			found = &record
+		}
+		return nil
+	})
+	return found, err
+}
+
+// putSecret writes a stored secret under its ID
+func putSecret(b *bbolt.Bucket, record *storedSecret) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.NewInternalErrorWithCause("failed to marshal secret", err)
+	}
+	if err := b.Put([]byte(record.ID), data); err != nil {
+		return errors.NewInternalErrorWithCause("failed to save secret", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestSecretCRUD(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	secret := &core.Secret{Name: "db-password", Value: "hunter2"}
+
+	// Without a key nothing can be stored
+	err := s.CreateSecret(secret)
+	assert.True(t, errors.IsInternal(err))
+
+	key, err := LoadSecretKey(filepath.Join(t.TempDir(), "secret.key"))
+	require.NoError(t, err)
+	require.NoError(t, s.SetSecretKey(key))
+
+	require.NoError(t, s.CreateSecret(secret))
+	require.NotEmpty(t, secret.ID)
+
+	// Names are unique
+	err = s.CreateSecret(&core.Secret{Name: "db-password", Value: "other"})
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	// The value is never read back, only decrypted on request
+	got, err := s.GetSecret(secret.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "db-password", got.Name)
+	assert.Empty(t, got.Value)
+
+	list, err := s.ListSecrets()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Empty(t, list[0].Value)
+
+	value, err := s.SecretValue("db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	// The database holds ciphertext only
+	require.NoError(t, s.DB().View(func(tx *bbolt.Tx) error {
+		raw := tx.Bucket([]byte(BucketSecrets)).Get([]byte(secret.ID))
+		assert.NotContains(t, string(raw), "hunter2")
+		return nil
+	}))
+
+	require.NoError(t, s.UpdateSecret(&core.Secret{ID: secret.ID, Name: "renamed", Value: "correct-horse"}))
+	value, err = s.SecretValue("db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "correct-horse", value)
+
+	// Another key cannot decrypt the value
+	other, err := LoadSecretKey(filepath.Join(t.TempDir(), "other.key"))
+	require.NoError(t, err)
+	require.NoError(t, s.SetSecretKey(other))
+	_, err = s.SecretValue("db-password")
+	assert.True(t, errors.IsInternal(err))
+
+	_, err = s.SecretValue("missing")
+	assert.True(t, errors.IsNotFound(err))
+
+	require.NoError(t, s.DeleteSecret(secret.ID))
+	_, err = s.GetSecret(secret.ID)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestLoadSecretKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "secret.key")
+
+	key, err := LoadSecretKey(path)
+	require.NoError(t, err)
+	assert.Len(t, key, SecretKeySize)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// The saved key is reused
+	again, err := LoadSecretKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	require.NoError(t, os.WriteFile(path, []byte("short"), 0o600))
+	_, err = LoadSecretKey(path)
+	assert.Error(t, err)
+}
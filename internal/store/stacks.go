@@ -0,0 +1,71 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// ListStacks retrieves all stacks from the database
+func (s *Store) ListStacks() ([]core.Stack, error) {
+	return genericList[core.Stack](s, BucketStacks)
+}
+
+// GetStack retrieves a single stack by ID
+func (s *Store) GetStack(id string) (*core.Stack, error) {
+	return genericGet[core.Stack](s, BucketStacks, id)
+}
+
+// CreateStack stores a new stack. Names must be unique, as they name the
+// stack's network.
+func (s *Store) CreateStack(stack *core.Stack) error {
+	if stack.ID == "" {
+		stack.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketStacks, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketStacks))
+
+		// Check for duplicate name
+		err := b.ForEach(func(k, v []byte) error {
+			var existing core.Stack
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return nil
+			}
+			if existing.Name == stack.Name {
+				return errors.NewAlreadyExistsError("stack", stack.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return putJSON(b, "stack", stack.ID, stack)
+	})
+}
+
+// UpdateStack updates an existing stack.
+// Returns NotFoundError if the stack doesn't exist.
+func (s *Store) UpdateStack(stack *core.Stack) error {
+	return s.genericUpdate(BucketStacks, stack.ID, stack)
+}
+
+// DeleteStack removes a stack from the database. Its applications are
+// left alone; the caller decides what happens to them.
+func (s *Store) DeleteStack(id string) error {
+	return s.update("delete", BucketStacks, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketStacks))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("stack", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete stack", err)
+		}
+		return nil
+	})
+}
@@ -2,12 +2,17 @@
 package store
 
 import (
+	"context"
+	"crypto/cipher"
 	"fmt"
 	"time"
 
 	"github.com/AkMo3/simplify/internal/errors"
 	"github.com/AkMo3/simplify/internal/permissions"
+	"github.com/AkMo3/simplify/internal/tracing"
 	"go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Bucket names constants to avoid typos
@@ -18,11 +23,51 @@ const (
 	BucketApplications = "applications"
 	BucketPods         = "pods"
 	BucketNetworks     = "networks"
+	BucketSecrets      = "secrets"
+	BucketVolumes      = "volumes"
+	BucketDeployments  = "deployments"
+	BucketUsers        = "users"
+	BucketAPIKeys      = "api_keys"
+	BucketDomains      = "domains"
+	BucketStacks       = "stacks"
+	BucketNodes        = "nodes"
+	BucketBuilds       = "builds"
+	BucketMetrics      = "metrics"
+	BucketLinks        = "links"
+	BucketActivity     = "activity"
+
+	// bucketAPIKeyHashes indexes API key IDs by key hash. It is derived from
+	// BucketAPIKeys, so it is rebuilt rather than backed up and restored.
+	bucketAPIKeyHashes = "api_key_hashes"
 )
 
+// allBuckets lists every bucket the store manages
+var allBuckets = []string{
+	BucketTeams,
+	BucketProjects,
+	BucketEnvironments,
+	BucketApplications,
+	BucketPods,
+	BucketNetworks,
+	BucketSecrets,
+	BucketVolumes,
+	BucketDeployments,
+	BucketUsers,
+	BucketAPIKeys,
+	BucketDomains,
+	BucketStacks,
+	BucketNodes,
+	BucketBuilds,
+	BucketMetrics,
+	BucketLinks,
+	BucketActivity,
+}
+
 // Store holds the database connection
 type Store struct {
-	db *bbolt.DB
+	ctx     context.Context // parent of transaction spans, see WithContext
+	secrets cipher.AEAD     // encrypts secret values, see SetSecretKey
+	db      *bbolt.DB
 }
 
 // New creates a new Store and initializes the database buckets.
@@ -56,26 +101,56 @@ func New(dbPath string) (*Store, error) {
 // initBuckets creates the necessary buckets if they don't exist
 func (s *Store) initBuckets() error {
 	return s.db.Update(func(tx *bbolt.Tx) error {
-		buckets := []string{
-			BucketTeams,
-			BucketProjects,
-			BucketEnvironments,
-			BucketApplications,
-			BucketPods,
-			BucketNetworks,
-		}
-
-		for _, bucket := range buckets {
+		for _, bucket := range allBuckets {
 			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
 				return errors.NewInternalErrorWithCause(
 					fmt.Sprintf("failed to create bucket %s", bucket), err)
 			}
 		}
 
+		// Databases from older versions have keys but no index yet
+		if tx.Bucket([]byte(bucketAPIKeyHashes)) == nil {
+			return indexAPIKeys(tx)
+		}
 		return nil
 	})
 }
 
+// WithContext returns a Store whose transactions are traced as children of
+// the span in ctx. It shares the database connection with s.
+func (s *Store) WithContext(ctx context.Context) *Store {
+	c := *s
+	c.ctx = ctx
+	return &c
+}
+
+// view runs a read-only transaction in a span named after op
+func (s *Store) view(op, bucket string, fn func(*bbolt.Tx) error) (err error) {
+	span := s.startSpan(op, bucket)
+	defer func() { tracing.End(span, err) }()
+	return s.db.View(fn)
+}
+
+// update runs a read-write transaction in a span named after op
+func (s *Store) update(op, bucket string, fn func(*bbolt.Tx) error) (err error) {
+	span := s.startSpan(op, bucket)
+	defer func() { tracing.End(span, err) }()
+	return s.db.Update(fn)
+}
+
+func (s *Store) startSpan(op, bucket string) trace.Span {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var attrs []attribute.KeyValue
+	if bucket != "" {
+		attrs = append(attrs, attribute.String("store.bucket", bucket))
+	}
+	_, span := tracing.Start(ctx, "store."+op, attrs...)
+	return span
+}
+
 // Close ensures the database file is released
 func (s *Store) Close() error {
 	if s.db != nil {
@@ -134,6 +134,16 @@ func TestApplicationCRUD(t *testing.T) {
 		assert.Equal(t, 5, updatedApp.Replicas)
 	})
 
+	t.Run("SetHealth", func(t *testing.T) {
+		require.NoError(t, s.SetApplicationHealth(app.ID, core.HealthUnhealthy))
+
+		updatedApp, err := s.GetApplication(app.ID)
+		require.NoError(t, err)
+		assert.Equal(t, core.HealthUnhealthy, updatedApp.HealthStatus)
+		assert.Equal(t, 5, updatedApp.Replicas, "spec is kept")
+		assert.True(t, errors.IsNotFound(s.SetApplicationHealth("non-existent", core.HealthHealthy)))
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		err := s.DeleteApplication(app.ID)
 		require.NoError(t, err, "failed to delete application")
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// ListUsers retrieves all users from the database
+func (s *Store) ListUsers() ([]core.User, error) {
+	return genericList[core.User](s, BucketUsers)
+}
+
+// GetUser retrieves a single user by ID
+func (s *Store) GetUser(id string) (*core.User, error) {
+	return genericGet[core.User](s, BucketUsers, id)
+}
+
+// CreateUser stores a new user. Usernames must be unique, and every team
+// the user is a member of must exist.
+func (s *Store) CreateUser(user *core.User) error {
+	if user.ID == "" {
+		user.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketUsers, func(tx *bbolt.Tx) error {
+		return putUser(tx, user, true)
+	})
+}
+
+// UpdateUser updates an existing user, including its team memberships.
+// Returns NotFoundError if the user doesn't exist.
+func (s *Store) UpdateUser(user *core.User) error {
+	return s.update("update", BucketUsers, func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(BucketUsers)).Get([]byte(user.ID)) == nil {
+			return errors.NewNotFoundError("user", user.ID)
+		}
+		return putUser(tx, user, false)
+	})
+}
+
+// DeleteUser removes a user and revokes all of its API keys
+func (s *Store) DeleteUser(id string) error {
+	return s.update("delete", BucketUsers, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketUsers))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("user", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete user", err)
+		}
+		return deleteAPIKeysOf(tx, id)
+	})
+}
+
+// ListTeamMembers returns the users that belong to a team
+func (s *Store) ListTeamMembers(teamID string) ([]core.User, error) {
+	users, err := s.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]core.User, 0, len(users))
+	for i := range users {
+		if users[i].Role(teamID) != "" {
+			members = append(members, users[i])
+		}
+	}
+	return members, nil
+}
+
+// SetTeamMember adds a user to a team with the given role, or changes the
+// role of an existing member
+func (s *Store) SetTeamMember(teamID, userID, role string) (*core.User, error) {
+	var user core.User
+	err := s.update("update", BucketUsers, func(tx *bbolt.Tx) error {
+		if err := getJSON(tx.Bucket([]byte(BucketUsers)), "user", userID, &user); err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(user.Teams, func(m core.TeamMembership) bool { return m.TeamID == teamID })
+		if i >= 0 {
+			user.Teams[i].Role = role
+		} else {
+			user.Teams = append(user.Teams, core.TeamMembership{TeamID: teamID, Role: role})
+		}
+		return putUser(tx, &user, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RemoveTeamMember removes a user from a team.
+// Returns NotFoundError if the user is not a member.
+func (s *Store) RemoveTeamMember(teamID, userID string) error {
+	return s.update("update", BucketUsers, func(tx *bbolt.Tx) error {
+		var user core.User
+		if err := getJSON(tx.Bucket([]byte(BucketUsers)), "user", userID, &user); err != nil {
+			return err
+		}
+
+		n := len(user.Teams)
+		user.Teams = slices.DeleteFunc(user.Teams, func(m core.TeamMembership) bool { return m.TeamID == teamID })
+		if len(user.Teams) == n {
+			return errors.NewNotFoundError("team member", userID)
+		}
+		return putUser(tx, &user, false)
+	})
+}
+
+// removeTeamMemberships drops every membership of a deleted team
+func removeTeamMemberships(tx *bbolt.Tx, teamID string) error {
+	b := tx.Bucket([]byte(BucketUsers))
+
+	var changed []core.User
+	err := b.ForEach(func(k, v []byte) error {
+		var user core.User
+		if err := json.Unmarshal(v, &user); err != nil {
+			return errors.NewInternalErrorWithCause("failed to unmarshal user", err)
+		}
+		if user.Role(teamID) != "" {
+			user.Teams = slices.DeleteFunc(user.Teams, func(m core.TeamMembership) bool { return m.TeamID == teamID })
+			changed = append(changed, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Buckets must not be modified while iterating over them
+	for i := range changed {
+		if err := putJSON(b, "user", changed[i].ID, &changed[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putUser validates and writes a user. The username must not be taken by
+// another user, and every team in its memberships must exist.
+func putUser(tx *bbolt.Tx, user *core.User, create bool) error {
+	b := tx.Bucket([]byte(BucketUsers))
+
+	err := b.ForEach(func(k, v []byte) error {
+		if !create && string(k) == user.ID {
+			return nil
+		}
+		var existing core.User
+		if err := json.Unmarshal(v, &existing); err != nil {
+			return nil
+		}
+		if existing.Username == user.Username {
+			return errors.NewAlreadyExistsError("user", user.Username)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	teams := tx.Bucket([]byte(BucketTeams))
+	for _, m := range user.Teams {
+		if teams.Get([]byte(m.TeamID)) == nil {
+			return errors.NewNotFoundError("team", m.TeamID)
+		}
+	}
+
+	return putJSON(b, "user", user.ID, user)
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+func TestUserCRUD(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.CreateTeam(&core.Team{ID: "platform", Name: "Platform"}))
+
+	// Memberships must name existing teams
+	err := s.CreateUser(&core.User{Username: "alice", Teams: []core.TeamMembership{{TeamID: "missing", Role: core.RoleMember}}})
+	assert.True(t, errors.IsNotFound(err))
+
+	alice := &core.User{Username: "alice", Teams: []core.TeamMembership{{TeamID: "platform", Role: core.RoleOwner}}}
+	require.NoError(t, s.CreateUser(alice))
+	require.NotEmpty(t, alice.ID)
+
+	err = s.CreateUser(&core.User{Username: "alice"})
+	assert.True(t, errors.IsAlreadyExists(err))
+
+	bob := &core.User{Username: "bob"}
+	require.NoError(t, s.CreateUser(bob))
+
+	// Renaming onto another user's name is rejected, keeping one's own is not
+	bob.Username = "alice"
+	assert.True(t, errors.IsAlreadyExists(s.UpdateUser(bob)))
+	bob.Username = "bob"
+	bob.Email = "bob@example.com"
+	require.NoError(t, s.UpdateUser(bob))
+
+	updated, err := s.SetTeamMember("platform", bob.ID, core.RoleViewer)
+	require.NoError(t, err)
+	assert.Equal(t, core.RoleViewer, updated.Role("platform"))
+
+	members, err := s.ListTeamMembers("platform")
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	require.NoError(t, s.RemoveTeamMember("platform", bob.ID))
+	assert.True(t, errors.IsNotFound(s.RemoveTeamMember("platform", bob.ID)))
+
+	// Deleting a team drops its memberships
+	require.NoError(t, s.DeleteTeam("platform"))
+	got, err := s.GetUser(alice.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Teams)
+
+	require.NoError(t, s.DeleteUser(bob.ID))
+	_, err = s.GetUser(bob.ID)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestAPIKeys(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	err := s.CreateAPIKey(&core.APIKey{UserID: "nobody", Name: "ci"})
+	assert.True(t, errors.IsNotFound(err))
+
+	user := &core.User{Username: "ci-bot"}
+	require.NoError(t, s.CreateUser(user))
+
+	key := &core.APIKey{UserID: user.ID, Name: "ci"}
+	require.NoError(t, s.CreateAPIKey(key))
+	assert.Contains(t, key.Key, APIKeyPrefix)
+	assert.Equal(t, key.Key[:len(key.Prefix)], key.Prefix)
+
+	// The key is only returned on creation
+	stored, err := s.GetAPIKey(key.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Key)
+
+	authed, record, err := s.AuthenticateAPIKey(key.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "ci-bot", authed.Username)
+	assert.Equal(t, key.ID, record.ID)
+	assert.NotNil(t, record.LastUsedAt)
+
+	// LastUsedAt is only written once per lastUsedResolution
+	_, again, err := s.AuthenticateAPIKey(key.Key)
+	require.NoError(t, err)
+	assert.Equal(t, record.LastUsedAt, again.LastUsedAt)
+
+	_, _, err = s.AuthenticateAPIKey(key.Key + "x")
+	assert.True(t, errors.IsNotFound(err))
+
+	past := time.Now().Add(-time.Hour)
+	expired := &core.APIKey{UserID: user.ID, Name: "old", ExpiresAt: &past}
+	require.NoError(t, s.CreateAPIKey(expired))
+	_, _, err = s.AuthenticateAPIKey(expired.Key)
+	assert.True(t, errors.IsNotFound(err))
+
+	keys, err := s.ListAPIKeys(user.ID)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	require.NoError(t, s.DeleteAPIKey(expired.ID))
+	assert.True(t, errors.IsNotFound(s.DeleteAPIKey(expired.ID)))
+
+	// Deleting the user revokes its keys
+	require.NoError(t, s.DeleteUser(user.ID))
+	_, _, err = s.AuthenticateAPIKey(key.Key)
+	assert.True(t, errors.IsNotFound(err))
+	keys, err = s.ListAPIKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	require.NoError(t, s.db.View(func(tx *bbolt.Tx) error {
+		assert.Equal(t, 0, tx.Bucket([]byte(bucketAPIKeyHashes)).Stats().KeyN, "index entries are removed with their keys")
+		return nil
+	}))
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/google/uuid"
+	"go.etcd.io/bbolt"
+)
+
+// ListVolumes retrieves all volumes from the database
+func (s *Store) ListVolumes() ([]core.Volume, error) {
+	return genericList[core.Volume](s, BucketVolumes)
+}
+
+// GetVolume retrieves a single volume by ID
+func (s *Store) GetVolume(id string) (*core.Volume, error) {
+	return genericGet[core.Volume](s, BucketVolumes, id)
+}
+
+// CreateVolume stores a new volume in the database. Names must be unique,
+// as they name the volume in the container engine.
+func (s *Store) CreateVolume(volume *core.Volume) error {
+	if volume.ID == "" {
+		volume.ID = uuid.New().String()
+	}
+
+	return s.update("create", BucketVolumes, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketVolumes))
+
+		// Check for duplicate name
+		err := b.ForEach(func(k, v []byte) error {
+			var existing core.Volume
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return nil
+			}
+			if existing.Name == volume.Name {
+				return errors.NewAlreadyExistsError("volume", volume.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(volume)
+		if err != nil {
+			return errors.NewInternalErrorWithCause("failed to marshal volume", err)
+		}
+
+		if err := b.Put([]byte(volume.ID), data); err != nil {
+			return errors.NewInternalErrorWithCause("failed to save volume", err)
+		}
+
+		return nil
+	})
+}
+
+// UpdateVolume updates an existing volume.
+// Returns NotFoundError if the volume doesn't exist.
+func (s *Store) UpdateVolume(volume *core.Volume) error {
+	return s.genericUpdate(BucketVolumes, volume.ID, volume)
+}
+
+// DeleteVolume removes a volume from the database
+func (s *Store) DeleteVolume(id string) error {
+	return s.update("delete", BucketVolumes, func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketVolumes))
+		if b.Get([]byte(id)) == nil {
+			return errors.NewNotFoundError("volume", id)
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.NewInternalErrorWithCause("failed to delete volume", err)
+		}
+		return nil
+	})
+}
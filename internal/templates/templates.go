@@ -0,0 +1,211 @@
+// Package templates provides the catalog of bundled application templates:
+// pre-configured images with their ports, volumes and environment prompts.
+package templates
+
+import (
+	"bytes"
+	"crypto/rand"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+)
+
+//go:embed catalog/*.yaml
+var catalogFS embed.FS
+
+// Template describes an application that can be created in one step.
+// Field names follow the JSON names used by the HTTP API.
+type Template struct {
+	Ports       map[string]string `json:"ports,omitempty"` // host port -> container port
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	Env         []EnvVar          `json:"env,omitempty"`
+	Volumes     []Volume          `json:"volumes,omitempty"`
+}
+
+// EnvVar is an environment variable the template sets, and how its value
+// is obtained when the caller does not provide one
+type EnvVar struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"` // the caller must provide a value
+	Generate    bool   `json:"generate,omitempty"` // a random value is generated
+	Secret      bool   `json:"secret,omitempty"`   // injected from a secret, not a plain env var
+}
+
+// Volume is persistent storage the template attaches. The volume is named
+// after the application, see VolumeName.
+type Volume struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// VolumeName returns the name of the volume created for an application
+func (v *Volume) VolumeName(app string) string {
+	return app + "-" + v.Name
+}
+
+// SecretName returns the name of the secret created for an application
+// when the variable is a secret
+func (e *EnvVar) SecretName(app string) string {
+	return app + "-" + strings.ReplaceAll(strings.ToLower(e.Name), "_", "-")
+}
+
+// Values are the resolved environment of an instantiated template
+type Values struct {
+	Env       map[string]string // plain environment variables
+	Secrets   map[string]string // secret variables, by variable name
+	Generated map[string]string // variables whose value was generated, by variable name
+}
+
+// Resolve fills in every variable of the template from values, defaults
+// and generated values. It fails when a required variable is missing or a
+// value names a variable the template does not have.
+func (t *Template) Resolve(values map[string]string) (*Values, error) {
+	known := make(map[string]bool, len(t.Env))
+	for i := range t.Env {
+		known[t.Env[i].Name] = true
+	}
+	for name := range values {
+		if !known[name] {
+			return nil, fmt.Errorf("template %s has no variable %s", t.ID, name)
+		}
+	}
+
+	resolved := &Values{
+		Env:       make(map[string]string),
+		Secrets:   make(map[string]string),
+		Generated: make(map[string]string),
+	}
+	var missing []string
+	for i := range t.Env {
+		v := &t.Env[i]
+		value, ok := values[v.Name]
+		switch {
+		case ok && value != "":
+		case v.Default != "":
+			value = v.Default
+		case v.Generate:
+			value = rand.Text()
+			resolved.Generated[v.Name] = value
+		case v.Required:
+			missing = append(missing, v.Name)
+			continue
+		default:
+			continue
+		}
+
+		if v.Secret {
+			resolved.Secrets[v.Name] = value
+		} else {
+			resolved.Env[v.Name] = value
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %s requires a value for %s", t.ID, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// loadCatalog parses the bundled templates once
+var loadCatalog = sync.OnceValues(func() ([]Template, error) {
+	return parseCatalog(catalogFS)
+})
+
+// List returns the bundled templates sorted by ID
+func List() ([]Template, error) {
+	return loadCatalog()
+}
+
+// Get returns the bundled template with the given ID, or nil
+func Get(id string) (*Template, error) {
+	list, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].ID == id {
+			t := list[i]
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+// parseCatalog reads every template in the catalog directory of fsys
+func parseCatalog(fsys fs.FS) ([]Template, error) {
+	files, err := fs.Glob(fsys, "catalog/*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("listing templates: %w", err)
+	}
+
+	list := make([]Template, 0, len(files))
+	seen := make(map[string]string, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("reading template %s: %w", file, err)
+		}
+		t, err := parseTemplate(data)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", path.Base(file), err)
+		}
+		if other, ok := seen[t.ID]; ok {
+			return nil, fmt.Errorf("template %s: id %s is already used by %s", path.Base(file), t.ID, other)
+		}
+		seen[t.ID] = path.Base(file)
+		list = append(list, *t)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// parseTemplate decodes and checks a single YAML template
+func parseTemplate(data []byte) (*Template, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing yaml: %w", err)
+	}
+
+	// Round-trip through JSON so the json tags apply, as manifests do
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("converting yaml: %w", err)
+	}
+	var t Template
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("decoding template: %w", err)
+	}
+
+	if t.ID == "" || t.Name == "" || t.Image == "" {
+		return nil, fmt.Errorf("id, name and image are required")
+	}
+	for i := range t.Env {
+		v := &t.Env[i]
+		if v.Name == "" {
+			return nil, fmt.Errorf("env %d has no name", i)
+		}
+		if v.Required && (v.Default != "" || v.Generate) {
+			return nil, fmt.Errorf("env %s cannot be required and have a default", v.Name)
+		}
+	}
+	for _, v := range t.Volumes {
+		if v.Name == "" || !path.IsAbs(v.Path) {
+			return nil, fmt.Errorf("volume %q needs a name and an absolute path", v.Name)
+		}
+	}
+	return &t, nil
+}
@@ -0,0 +1,85 @@
+package templates
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	list, err := List()
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(list))
+	for i := range list {
+		ids = append(ids, list[i].ID)
+	}
+	assert.Subset(t, ids, []string{"ghost", "mariadb", "postgres", "redis", "uptime-kuma"})
+	assert.IsNonDecreasing(t, ids)
+
+	pg, err := Get("postgres")
+	require.NoError(t, err)
+	require.NotNil(t, pg)
+	assert.Equal(t, "5432", pg.Ports["5432"])
+
+	missing, err := Get("missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestParseCatalogErrors(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":  "id: x\nname: X\nimage: x\ncommand: [sh]\n",
+		"no image":       "id: x\nname: X\n",
+		"relative path":  "id: x\nname: X\nimage: x\nvolumes:\n  - name: data\n    path: data\n",
+		"required+value": "id: x\nname: X\nimage: x\nenv:\n  - name: A\n    required: true\n    default: a\n",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCatalog(fstest.MapFS{"catalog/x.yaml": {Data: []byte(doc)}})
+			assert.Error(t, err)
+		})
+	}
+
+	dup := fstest.MapFS{
+		"catalog/a.yaml": {Data: []byte("id: x\nname: A\nimage: a\n")},
+		"catalog/b.yaml": {Data: []byte("id: x\nname: B\nimage: b\n")},
+	}
+	_, err := parseCatalog(dup)
+	assert.ErrorContains(t, err, "already used")
+}
+
+func TestResolve(t *testing.T) {
+	tmpl := &Template{
+		ID: "test",
+		Env: []EnvVar{
+			{Name: "USER", Default: "admin"},
+			{Name: "PASSWORD", Generate: true, Secret: true},
+			{Name: "URL", Required: true},
+			{Name: "OPTIONAL"},
+		},
+	}
+
+	_, err := tmpl.Resolve(nil)
+	assert.ErrorContains(t, err, "URL")
+
+	_, err = tmpl.Resolve(map[string]string{"URL": "https://x", "TYPO": "1"})
+	assert.ErrorContains(t, err, "TYPO")
+
+	values, err := tmpl.Resolve(map[string]string{"URL": "https://x", "USER": "root"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"USER": "root", "URL": "https://x"}, values.Env)
+	require.Contains(t, values.Secrets, "PASSWORD")
+	assert.NotEmpty(t, values.Secrets["PASSWORD"])
+	assert.Equal(t, values.Secrets["PASSWORD"], values.Generated["PASSWORD"])
+
+	// Provided values are not generated
+	values, err = tmpl.Resolve(map[string]string{"URL": "https://x", "PASSWORD": "hunter2"})
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", values.Secrets["PASSWORD"])
+	assert.Empty(t, values.Generated)
+
+	assert.Equal(t, "db-password", tmpl.Env[1].SecretName("db"))
+}
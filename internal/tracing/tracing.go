@@ -0,0 +1,89 @@
+// Package tracing provides OpenTelemetry tracing for Simplify.
+//
+// Until Init is called the global tracer provider is a no-op, so spans
+// started with Start cost next to nothing when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies Simplify's tracer
+const instrumentationName = "github.com/AkMo3/simplify"
+
+// Options configure the OTLP exporter
+type Options struct {
+	Endpoint       string  // host:port or URL of the OTLP/HTTP collector; empty uses OTEL_EXPORTER_OTLP_ENDPOINT
+	ServiceName    string  // service.name resource attribute
+	ServiceVersion string  // service.version resource attribute
+	SampleRatio    float64 // fraction of traces to record, between 0 and 1
+	Insecure       bool    // use plain HTTP instead of HTTPS
+}
+
+// Init installs a global tracer provider that exports spans over OTLP/HTTP.
+// The returned function flushes pending spans and must be called on shutdown.
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporterOpts []otlptracehttp.Option
+	switch {
+	case strings.Contains(opts.Endpoint, "://"):
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	case opts.Endpoint != "":
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// ListApplications returns all applications
+func (c *Client) ListApplications(ctx context.Context) ([]Application, error) {
+	return list[Application](ctx, c, "/applications")
+}
+
+// GetApplication returns the application with the given ID
+func (c *Client) GetApplication(ctx context.Context, id string) (*Application, error) {
+	return call[Application](ctx, c, http.MethodGet, "/applications/"+url.PathEscape(id), nil)
+}
+
+// ExportApplication writes the application as a YAML manifest to w, ready
+// to be applied on another server. IDs and runtime state are left out, and
+// sensitive environment variables are exported empty.
+func (c *Client) ExportApplication(ctx context.Context, id string, w io.Writer) (int64, error) {
+	return c.Download(ctx, "/applications/"+url.PathEscape(id)+"/export", w)
+}
+
+// CreateApplication creates an application and returns it as stored
+func (c *Client) CreateApplication(ctx context.Context, app *Application) (*Application, error) {
+	return call[Application](ctx, c, http.MethodPost, "/applications", app)
+}
+
+// UpdateApplication replaces the spec of the application with app.ID
+func (c *Client) UpdateApplication(ctx context.Context, app *Application) (*Application, error) {
+	return call[Application](ctx, c, http.MethodPut, "/applications/"+url.PathEscape(app.ID), app)
+}
+
+// DeleteApplication deletes an application. Its containers are removed by
+// the reconciler.
+func (c *Client) DeleteApplication(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/applications/"+url.PathEscape(id), nil, nil)
+}
+
+// ScaleApplication sets the replica count of an application
+func (c *Client) ScaleApplication(ctx context.Context, id string, replicas int) (*Application, error) {
+	body := map[string]int{"replicas": replicas}
+	return call[Application](ctx, c, http.MethodPost, "/applications/"+url.PathEscape(id)+"/scale", body)
+}
+
+// ListDeployments returns the deployment history of an application
+func (c *Client) ListDeployments(ctx context.Context, appID string) ([]Deployment, error) {
+	return list[Deployment](ctx, c, "/applications/"+url.PathEscape(appID)+"/deployments")
+}
+
+// RollbackApplication restores an application to the spec of an earlier
+// revision
+func (c *Client) RollbackApplication(ctx context.Context, id, revision string) (*Application, error) {
+	path := "/applications/" + url.PathEscape(id) + "/rollback/" + url.PathEscape(revision)
+	return call[Application](ctx, c, http.MethodPost, path, nil)
+}
+
+// CreateBuild starts a build of an application's project repository
+func (c *Client) CreateBuild(ctx context.Context, appID string, req *BuildRequest) (*Build, error) {
+	return call[Build](ctx, c, http.MethodPost, "/applications/"+url.PathEscape(appID)+"/builds", req)
+}
+
+// ListBuilds returns the builds of an application
+func (c *Client) ListBuilds(ctx context.Context, appID string) ([]Build, error) {
+	return list[Build](ctx, c, "/applications/"+url.PathEscape(appID)+"/builds")
+}
+
+// GetBuild returns a build of an application, with its log
+func (c *Client) GetBuild(ctx context.Context, appID, buildID string) (*Build, error) {
+	path := "/applications/" + url.PathEscape(appID) + "/builds/" + url.PathEscape(buildID)
+	return call[Build](ctx, c, http.MethodGet, path, nil)
+}
+
+// GetMetrics returns the resource usage of an application over the last
+// rng, averaged over step. Zero values leave the choice to the server.
+func (c *Client) GetMetrics(ctx context.Context, appID string, rng, step time.Duration) (*MetricsResponse, error) {
+	query := url.Values{}
+	if rng > 0 {
+		query.Set("range", rng.String())
+	}
+	if step > 0 {
+		query.Set("step", step.String())
+	}
+	return call[MetricsResponse](ctx, c, http.MethodGet, "/applications/"+url.PathEscape(appID)+"/metrics"+encode(query), nil)
+}
+
+// StreamLogs calls fn for each log line of an application's container until
+// the output ends, fn returns an error or ctx is canceled. With
+// opts.Follow the stream only ends with ctx, so the client's timeout does
+// not apply.
+func (c *Client) StreamLogs(ctx context.Context, appID string, opts LogOptions, fn func(LogLine) error) error {
+	query := url.Values{}
+	if opts.Replica > 0 {
+		query.Set("replica", strconv.Itoa(opts.Replica))
+	}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	if opts.Since != "" {
+		query.Set("since", opts.Since)
+	}
+	if opts.Follow {
+		query.Set("follow", "true")
+	}
+	if opts.Timestamps {
+		query.Set("timestamps", "true")
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "/applications/"+url.PathEscape(appID)+"/logs"+encode(query), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line LogLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("decoding log line: %w", err)
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("reading logs: %w", err)
+	}
+	return nil
+}
+
+// Exec runs a command in a replica of an application, writing its output
+// to stdout and stderr as it arrives, and returns its exit code. The
+// command gets no input.
+func (c *Client) Exec(ctx context.Context, appID string, req *ExecRequest, stdout, stderr io.Writer) (int, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/applications/"+url.PathEscape(appID)+"/exec", req)
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var msg ExecOutput
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return -1, fmt.Errorf("decoding exec output: %w", err)
+		}
+		switch {
+		case msg.ExitCode != nil:
+			return *msg.ExitCode, nil
+		case msg.Error != "":
+			return -1, errors.NewInternalError(msg.Error)
+		}
+		out := stdout
+		if msg.Stream == "stderr" {
+			out = stderr
+		}
+		if out == nil {
+			continue
+		}
+		if _, err := io.WriteString(out, msg.Data); err != nil {
+			return -1, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		return -1, fmt.Errorf("reading exec output: %w", err)
+	}
+	return -1, fmt.Errorf("exec output ended without an exit code")
+}
+
+// encode returns query as the query string of a path, empty without values
+func encode(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/AkMo3/simplify/internal/errors"
+	"github.com/gorilla/websocket"
+)
+
+// Stream bytes prefixing the output messages of an attach websocket. Input
+// messages from the client are written to stdin as they are, and an empty
+// one closes stdin.
+const (
+	AttachStdout byte = 1
+	AttachStderr byte = 2
+)
+
+// Attach connects stdin, stdout and stderr to the main process of a replica
+// of an application, 0-based as in Instance.Replica, until the process
+// exits or ctx is canceled. stdin may be nil to only receive output; the
+// server discards the input of applications that are not interactive.
+func (c *Client) Attach(ctx context.Context, appID string, replica int, stdin io.Reader, stdout, stderr io.Writer) error {
+	u := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/api/v1/applications/" + url.PathEscape(appID) +
+		"/attach?replica=" + strconv.Itoa(replica)
+	header := http.Header{}
+	c.setHeaders(header)
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: c.timeout}
+	if t, ok := c.http.Transport.(*http.Transport); ok {
+		dialer.TLSClientConfig = t.TLSClientConfig
+	}
+	conn, resp, err := dialer.DialContext(ctx, u, header)
+	if err != nil {
+		if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+			defer resp.Body.Close()
+			return decodeError(resp)
+		}
+		return fmt.Errorf("contacting Simplify server at %s: %w", c.baseURL, err)
+	}
+	defer conn.Close()
+
+	// Closing the connection ends the read loop below
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	if stdin != nil {
+		go sendInput(conn, stdin)
+	}
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			var closed *websocket.CloseError
+			switch {
+			case ctx.Err() != nil:
+				return ctx.Err()
+			case stderrors.As(err, &closed) && closed.Code == websocket.CloseNormalClosure:
+				return nil
+			case stderrors.As(err, &closed):
+				return errors.NewInternalError(closed.Text)
+			default:
+				return fmt.Errorf("reading attached output: %w", err)
+			}
+		}
+		if len(msg) == 0 {
+			continue
+		}
+		out := stdout
+		if msg[0] == AttachStderr {
+			out = stderr
+		}
+		if out == nil {
+			continue
+		}
+		if _, err := out.Write(msg[1:]); err != nil {
+			return err
+		}
+	}
+}
+
+// sendInput writes stdin to an attach websocket, one message per read,
+// until stdin ends or the connection fails. The end of stdin is passed on.
+func sendInput(conn *websocket.Conn, stdin io.Reader) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			if werr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err == io.EOF {
+			_ = conn.WriteMessage(websocket.BinaryMessage, nil) //nolint:errcheck // the process may have exited
+		}
+		if err != nil {
+			return
+		}
+	}
+}
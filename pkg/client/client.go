@@ -0,0 +1,324 @@
+// Package client is the Go client for the Simplify HTTP API. It has a typed
+// method for each endpoint, retries idempotent requests that fail on the
+// way to the server, and converts error responses back into the error types
+// the server returned. The simplify CLI talks to the server through it.
+//
+//	c := client.New("http://localhost:8080", client.Options{Token: token})
+//	apps, err := c.ListApplications(ctx)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AkMo3/simplify/internal/errors"
+)
+
+// ActorHeader names who made a change, as recorded in deployment history
+// and the activity feed. Until requests are authenticated it is taken on
+// trust.
+const ActorHeader = "X-Simplify-Actor"
+
+// Defaults of Options
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultRetryWait  = 250 * time.Millisecond
+)
+
+// maxRetryWait caps the backoff between two attempts
+const maxRetryWait = 5 * time.Second
+
+// Options configures a Client. The zero value uses the defaults.
+type Options struct {
+	HTTPClient *http.Client  // nil for http.DefaultTransport without a timeout of its own
+	Token      string        // sent as a bearer token when set
+	Actor      string        // sent in ActorHeader when set, such as user@host
+	Timeout    time.Duration // of each request except log streams, 0 for DefaultTimeout
+	MaxRetries int           // retries of idempotent requests, 0 for DefaultMaxRetries, negative for none
+	RetryWait  time.Duration // wait before the first retry, doubled for each further one; 0 for DefaultRetryWait
+}
+
+// Client calls the Simplify API of one server. It is safe for concurrent use.
+type Client struct {
+	http       *http.Client
+	baseURL    string
+	token      string
+	actor      string
+	timeout    time.Duration
+	retryWait  time.Duration
+	maxRetries int
+}
+
+// New creates a client for the server at baseURL, such as
+// http://localhost:8080
+func New(baseURL string, opts Options) *Client {
+	c := &Client{
+		http:       opts.HTTPClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      opts.Token,
+		actor:      opts.Actor,
+		timeout:    opts.Timeout,
+		retryWait:  opts.RetryWait,
+		maxRetries: opts.MaxRetries,
+	}
+	if c.http == nil {
+		c.http = &http.Client{}
+	}
+	if c.timeout <= 0 {
+		c.timeout = DefaultTimeout
+	}
+	if c.retryWait <= 0 {
+		c.retryWait = DefaultRetryWait
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = DefaultMaxRetries
+	}
+	return c
+}
+
+// BaseURL returns the address of the server
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// WithToken returns a copy of the client that authenticates with token
+func (c *Client) WithToken(token string) *Client {
+	cp := *c
+	cp.token = token
+	return &cp
+}
+
+// Do sends a JSON request to path under /api/v1 and decodes the JSON
+// response into out, which may be nil. It is the escape hatch for
+// endpoints without a typed method.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// Download sends a GET request to path under /api/v1 and copies the raw
+// response body to w
+func (c *Client) Download(ctx context.Context, path string, w io.Writer) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("reading response: %w", err)
+	}
+	return n, nil
+}
+
+// send performs a request, retrying idempotent ones, and returns the
+// response of a successful status code. The caller must close its body.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	retries := 0
+	if idempotent(method) {
+		retries = max(c.maxRetries, 0)
+	}
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, method, c.baseURL+"/api/v1"+path, body != nil, data)
+		if attempt == retries || !retryable(ctx, resp, err) {
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= http.StatusBadRequest {
+				defer resp.Body.Close()
+				return nil, decodeError(resp)
+			}
+			return resp, nil
+		}
+
+		delay := wait
+		if resp != nil {
+			delay = retryAfter(resp, wait)
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("contacting Simplify server at %s: %w", c.baseURL, ctx.Err())
+		case <-time.After(delay):
+		}
+		wait = min(wait*2, maxRetryWait)
+	}
+}
+
+// attempt sends a request to url once
+func (c *Client) attempt(ctx context.Context, method, url string, hasBody bool, data []byte) (*http.Response, error) {
+	var reader io.Reader
+	if hasBody {
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	c.setHeaders(req.Header)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("contacting Simplify server at %s: %w", c.baseURL, err)
+	}
+	return resp, nil
+}
+
+// setHeaders sets the credentials and actor of the client on a request
+func (c *Client) setHeaders(h http.Header) {
+	if c.token != "" {
+		h.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.actor != "" {
+		h.Set(ActorHeader, c.actor)
+	}
+}
+
+// idempotent reports whether a request may be sent again without changing
+// its effect
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a failed attempt is worth repeating: the server
+// could not be reached, or it or a proxy in front of it answered that it is
+// overloaded or restarting
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	case http.StatusServiceUnavailable:
+		// The server's own UnavailableErrors, such as metrics being
+		// disabled, are answers rather than outages
+		return !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	}
+	return false
+}
+
+// retryAfter returns the wait asked for by a Retry-After header in
+// seconds, capped at maxRetryWait, or wait without one
+func retryAfter(resp *http.Response, wait time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return wait
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryWait)
+}
+
+// errorResponse mirrors the server's structured error body
+type errorResponse struct {
+	Error struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		Resource  string `json:"resource"`
+		ID        string `json:"id"`
+		Field     string `json:"field"`
+		RequestID string `json:"request_id"`
+	} `json:"error"`
+}
+
+// decodeError converts an API error response into the typed error the
+// server returned
+func decodeError(resp *http.Response) error {
+	var body errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error.Code == "" {
+		return errors.NewInternalError(fmt.Sprintf("server returned %s", resp.Status))
+	}
+
+	base := errors.BaseError{
+		Code:     body.Error.Code,
+		Message:  body.Error.Message,
+		Resource: body.Error.Resource,
+		ID:       body.Error.ID,
+	}
+
+	switch body.Error.Code {
+	case errors.CodeNotFound:
+		return &errors.NotFoundError{BaseError: base}
+	case errors.CodeAlreadyExists:
+		return &errors.AlreadyExistsError{BaseError: base}
+	case errors.CodeInvalidInput:
+		return &errors.InvalidInputError{BaseError: base, Field: body.Error.Field}
+	case errors.CodePermissionDenied:
+		return &errors.PermissionError{BaseError: base}
+	case errors.CodeConflictState:
+		return &errors.ConflictStateError{BaseError: base}
+	case errors.CodeUnavailable:
+		return &errors.UnavailableError{BaseError: base}
+	case errors.CodeTimeout:
+		return &errors.TimeoutError{BaseError: base}
+	default:
+		// Server-side failures are traced by request ID in the server logs
+		if body.Error.RequestID != "" {
+			base.Message = fmt.Sprintf("%s (request ID: %s)", base.Message, body.Error.RequestID)
+		}
+		return &errors.InternalError{BaseError: base}
+	}
+}
+
+// list sends a GET request and decodes the JSON array it returns
+func list[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var out []T
+	if err := c.Do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// call sends a request and decodes the JSON object it returns
+func call[T any](ctx context.Context, c *Client, method, path string, body any) (*T, error) {
+	var out T
+	if err := c.Do(ctx, method, path, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client of a server answering with handler, which
+// retries without waiting
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return New(srv.URL, Options{Token: "secret", Actor: "alice@laptop", RetryWait: time.Millisecond})
+}
+
+func TestHeaders(t *testing.T) {
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		assert.Equal(t, "/api/v1/teams", r.URL.Path)
+		fmt.Fprint(w, `[{"id":"t1","name":"payments"}]`)
+	})
+
+	teams, err := c.ListTeams(context.Background())
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	assert.Equal(t, "payments", teams[0].Name)
+	assert.Equal(t, "Bearer secret", got.Get("Authorization"))
+	assert.Equal(t, "alice@laptop", got.Get(ActorHeader))
+
+	_, err = c.WithToken("other").ListTeams(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer other", got.Get("Authorization"))
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id":"a1","name":"web"}`)
+	})
+
+	app, err := c.GetApplication(context.Background(), "a1")
+	require.NoError(t, err)
+	assert.Equal(t, "web", app.Name)
+	assert.Equal(t, int32(3), calls.Load(), "GET is retried until it succeeds")
+
+	calls.Store(0)
+	_, err = c.CreateApplication(context.Background(), &Application{Name: "web"})
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "POST is not retried")
+
+	calls.Store(-10)
+	err = c.DeleteApplication(context.Background(), "a1")
+	require.Error(t, err)
+	assert.Equal(t, int32(-10+1+DefaultMaxRetries), calls.Load(), "retries stop after MaxRetries")
+
+	calls.Store(0)
+	unavailable := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"code":"UNAVAILABLE","message":"metrics collection is disabled"}}`)
+	})
+	_, err = unavailable.GetMetrics(context.Background(), "a1", time.Hour, 0)
+	assert.True(t, IsUnavailable(err))
+	assert.Equal(t, int32(1), calls.Load(), "errors of the server itself are not retried")
+
+	calls.Store(-10)
+	noRetry := New(c.BaseURL(), Options{MaxRetries: -1})
+	_, err = noRetry.ListApplications(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(-9), calls.Load())
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/volumes/v1":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"code":"NOT_FOUND","message":"volume not found: v1","resource":"volume","id":"v1"}}`)
+		case "/api/v1/volumes":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"code":"INVALID_INPUT","message":"name is required","field":"name"}}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"code":"INTERNAL","message":"boom","request_id":"r1"}}`)
+		}
+	})
+	ctx := context.Background()
+
+	_, err := c.GetVolume(ctx, "v1")
+	assert.True(t, IsNotFound(err))
+
+	_, err = c.CreateVolume(ctx, &Volume{})
+	require.True(t, IsInvalidInput(err))
+	var invalid *InvalidInputError
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "name", invalid.Field)
+
+	_, err = c.ListNodes(ctx)
+	var internal *InternalError
+	require.ErrorAs(t, err, &internal)
+	assert.Contains(t, err.Error(), "request ID: r1")
+}
+
+func TestStreamLogs(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/applications/a1/logs", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("replica"))
+		assert.Equal(t, "10", r.URL.Query().Get("tail"))
+		assert.Equal(t, "true", r.URL.Query().Get("follow"))
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"stream":"stdout","text":"listening on :8080"}`)
+		fmt.Fprintln(w, `{"stream":"stderr","text":"warning: no cache"}`)
+	})
+
+	var lines []LogLine
+	err := c.StreamLogs(context.Background(), "a1", LogOptions{Replica: 1, Tail: 10, Follow: true}, func(line LogLine) error {
+		lines = append(lines, line)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []LogLine{
+		{Stream: "stdout", Text: "listening on :8080"},
+		{Stream: "stderr", Text: "warning: no cache"},
+	}, lines)
+}
+
+func TestReady(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/readyz", r.URL.Path)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"status":"unhealthy","checks":{"database":{"status":"unhealthy","message":"closed"}}}`)
+	})
+
+	status, err := c.Ready(context.Background())
+	require.Error(t, err)
+	require.NotNil(t, status, "the status is returned with the error")
+	assert.Equal(t, "closed", status.Checks["database"].Message)
+}
@@ -0,0 +1,33 @@
+package client
+
+import "github.com/AkMo3/simplify/internal/errors"
+
+// Errors returned for error responses, by the code the server answered
+// with. Failures to reach the server are returned as they are.
+type (
+	NotFoundError      = errors.NotFoundError
+	AlreadyExistsError = errors.AlreadyExistsError
+	InvalidInputError  = errors.InvalidInputError // Field names the offending field, when known
+	PermissionError    = errors.PermissionError
+	ConflictStateError = errors.ConflictStateError
+	UnavailableError   = errors.UnavailableError
+	TimeoutError       = errors.TimeoutError
+	InternalError      = errors.InternalError
+)
+
+// IsNotFound reports whether the server answered that a resource does not exist
+func IsNotFound(err error) bool { return errors.IsNotFound(err) }
+
+// IsAlreadyExists reports whether the server answered that a resource already exists
+func IsAlreadyExists(err error) bool { return errors.IsAlreadyExists(err) }
+
+// IsInvalidInput reports whether the server rejected the request as invalid
+func IsInvalidInput(err error) bool { return errors.IsInvalidInput(err) }
+
+// IsConflictState reports whether the resource is in a state that does not
+// allow the request, such as a volume still attached to an application
+func IsConflictState(err error) bool { return errors.IsConflictState(err) }
+
+// IsUnavailable reports whether the server or one of its dependencies,
+// such as the builder or the backup target, is unavailable
+func IsUnavailable(err error) bool { return errors.IsUnavailable(err) }
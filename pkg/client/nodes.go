@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListNodes returns the registered agent nodes
+func (c *Client) ListNodes(ctx context.Context) ([]Node, error) {
+	return list[Node](ctx, c, "/nodes")
+}
+
+// GetNode returns the node with the given ID
+func (c *Client) GetNode(ctx context.Context, id string) (*Node, error) {
+	return call[Node](ctx, c, http.MethodGet, "/nodes/"+url.PathEscape(id), nil)
+}
+
+// RegisterNode registers an agent's node, or re-registers it under the
+// same name, and returns it with the token the agent authenticates with.
+// Re-registering needs a client carrying the node's current token.
+func (c *Client) RegisterNode(ctx context.Context, req *RegisterNodeRequest) (*Node, error) {
+	return call[Node](ctx, c, http.MethodPost, "/nodes", req)
+}
+
+// DeleteNode removes a node
+func (c *Client) DeleteNode(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/nodes/"+url.PathEscape(id), nil, nil)
+}
+
+// Heartbeat reports that a node is alive, with the state of the
+// applications it runs
+func (c *Client) Heartbeat(ctx context.Context, nodeID string, hb *HeartbeatRequest) (*Node, error) {
+	return call[Node](ctx, c, http.MethodPost, "/nodes/"+url.PathEscape(nodeID)+"/heartbeat", hb)
+}
+
+// GetNodeState returns the desired state of the applications scheduled on
+// a node and the resources they use
+func (c *Client) GetNodeState(ctx context.Context, nodeID string) (*NodeState, error) {
+	return call[NodeState](ctx, c, http.MethodGet, "/nodes/"+url.PathEscape(nodeID)+"/state", nil)
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListTeams returns all teams
+func (c *Client) ListTeams(ctx context.Context) ([]Team, error) {
+	return list[Team](ctx, c, "/teams")
+}
+
+// GetTeam returns the team with the given ID
+func (c *Client) GetTeam(ctx context.Context, id string) (*Team, error) {
+	return call[Team](ctx, c, http.MethodGet, "/teams/"+url.PathEscape(id), nil)
+}
+
+// CreateTeam creates a team
+func (c *Client) CreateTeam(ctx context.Context, team *Team) (*Team, error) {
+	return call[Team](ctx, c, http.MethodPost, "/teams", team)
+}
+
+// UpdateTeam replaces the team with team.ID
+func (c *Client) UpdateTeam(ctx context.Context, team *Team) (*Team, error) {
+	return call[Team](ctx, c, http.MethodPut, "/teams/"+url.PathEscape(team.ID), team)
+}
+
+// DeleteTeam deletes a team
+func (c *Client) DeleteTeam(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/teams/"+url.PathEscape(id), nil, nil)
+}
+
+// ListTeamMembers returns the users that belong to a team
+func (c *Client) ListTeamMembers(ctx context.Context, teamID string) ([]User, error) {
+	return list[User](ctx, c, "/teams/"+url.PathEscape(teamID)+"/members")
+}
+
+// SetTeamMember adds a user to a team with role, or changes its role there
+func (c *Client) SetTeamMember(ctx context.Context, teamID, userID, role string) (*User, error) {
+	path := "/teams/" + url.PathEscape(teamID) + "/members/" + url.PathEscape(userID)
+	return call[User](ctx, c, http.MethodPut, path, TeamMembership{Role: role})
+}
+
+// RemoveTeamMember removes a user from a team
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	path := "/teams/" + url.PathEscape(teamID) + "/members/" + url.PathEscape(userID)
+	return c.Do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// ListUsers returns all users
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	return list[User](ctx, c, "/users")
+}
+
+// GetUser returns the user with the given ID
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	return call[User](ctx, c, http.MethodGet, "/users/"+url.PathEscape(id), nil)
+}
+
+// CreateUser creates a user
+func (c *Client) CreateUser(ctx context.Context, user *User) (*User, error) {
+	return call[User](ctx, c, http.MethodPost, "/users", user)
+}
+
+// UpdateUser replaces the user with user.ID
+func (c *Client) UpdateUser(ctx context.Context, user *User) (*User, error) {
+	return call[User](ctx, c, http.MethodPut, "/users/"+url.PathEscape(user.ID), user)
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil)
+}
+
+// CreateAPIKey generates an API key for a user. The returned key is the
+// only time its secret is readable.
+func (c *Client) CreateAPIKey(ctx context.Context, userID string, key *APIKey) (*APIKey, error) {
+	return call[APIKey](ctx, c, http.MethodPost, "/users/"+url.PathEscape(userID)+"/apikeys", key)
+}
+
+// ListAPIKeys returns the API keys of a user, without their secrets
+func (c *Client) ListAPIKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	return list[APIKey](ctx, c, "/users/"+url.PathEscape(userID)+"/apikeys")
+}
+
+// DeleteAPIKey revokes an API key of a user
+func (c *Client) DeleteAPIKey(ctx context.Context, userID, keyID string) error {
+	path := "/users/" + url.PathEscape(userID) + "/apikeys/" + url.PathEscape(keyID)
+	return c.Do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// WhoAmI returns the user and API key the client authenticates with. It
+// fails with a permission error when the token is not a valid API key.
+func (c *Client) WhoAmI(ctx context.Context) (*WhoAmIResponse, error) {
+	return call[WhoAmIResponse](ctx, c, http.MethodGet, "/whoami", nil)
+}
+
+// ListProjects returns all projects
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	return list[Project](ctx, c, "/projects")
+}
+
+// GetProject returns the project with the given ID
+func (c *Client) GetProject(ctx context.Context, id string) (*Project, error) {
+	return call[Project](ctx, c, http.MethodGet, "/projects/"+url.PathEscape(id), nil)
+}
+
+// CreateProject creates a project
+func (c *Client) CreateProject(ctx context.Context, project *Project) (*Project, error) {
+	return call[Project](ctx, c, http.MethodPost, "/projects", project)
+}
+
+// UpdateProject replaces the project with project.ID
+func (c *Client) UpdateProject(ctx context.Context, project *Project) (*Project, error) {
+	return call[Project](ctx, c, http.MethodPut, "/projects/"+url.PathEscape(project.ID), project)
+}
+
+// DeleteProject deletes a project
+func (c *Client) DeleteProject(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/projects/"+url.PathEscape(id), nil, nil)
+}
+
+// ListEnvironments returns all environments
+func (c *Client) ListEnvironments(ctx context.Context) ([]Environment, error) {
+	return list[Environment](ctx, c, "/environments")
+}
+
+// GetEnvironment returns the environment with the given ID
+func (c *Client) GetEnvironment(ctx context.Context, id string) (*Environment, error) {
+	return call[Environment](ctx, c, http.MethodGet, "/environments/"+url.PathEscape(id), nil)
+}
+
+// CreateEnvironment creates an environment
+func (c *Client) CreateEnvironment(ctx context.Context, env *Environment) (*Environment, error) {
+	return call[Environment](ctx, c, http.MethodPost, "/environments", env)
+}
+
+// UpdateEnvironment replaces the environment with env.ID
+func (c *Client) UpdateEnvironment(ctx context.Context, env *Environment) (*Environment, error) {
+	return call[Environment](ctx, c, http.MethodPut, "/environments/"+url.PathEscape(env.ID), env)
+}
+
+// DeleteEnvironment deletes an environment
+func (c *Client) DeleteEnvironment(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/environments/"+url.PathEscape(id), nil, nil)
+}
+
+// CloneEnvironment copies an environment and its applications into a new
+// environment
+func (c *Client) CloneEnvironment(ctx context.Context, id string, req *CloneEnvironmentRequest) (*CloneEnvironmentResponse, error) {
+	return call[CloneEnvironmentResponse](ctx, c, http.MethodPost, "/environments/"+url.PathEscape(id)+"/clone", req)
+}
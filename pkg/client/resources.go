@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ImageInfo describes a container image
+type ImageInfo struct {
+	ID           string   `json:"id"`
+	ExposedPorts []string `json:"exposed_ports"`
+}
+
+// InspectImage returns the ID and exposed ports of an image, pulling it
+// when the server does not have it yet
+func (c *Client) InspectImage(ctx context.Context, image string) (*ImageInfo, error) {
+	return call[ImageInfo](ctx, c, http.MethodGet, "/images/inspect"+encode(url.Values{"image": {image}}), nil)
+}
+
+// ListPods returns all pods
+func (c *Client) ListPods(ctx context.Context) ([]Pod, error) {
+	return list[Pod](ctx, c, "/pods")
+}
+
+// GetPod returns the pod with the given ID
+func (c *Client) GetPod(ctx context.Context, id string) (*Pod, error) {
+	return call[Pod](ctx, c, http.MethodGet, "/pods/"+url.PathEscape(id), nil)
+}
+
+// CreatePod creates a pod
+func (c *Client) CreatePod(ctx context.Context, pod *Pod) (*Pod, error) {
+	return call[Pod](ctx, c, http.MethodPost, "/pods", pod)
+}
+
+// UpdatePod replaces the pod with pod.ID
+func (c *Client) UpdatePod(ctx context.Context, pod *Pod) (*Pod, error) {
+	return call[Pod](ctx, c, http.MethodPut, "/pods/"+url.PathEscape(pod.ID), pod)
+}
+
+// DeletePod deletes a pod
+func (c *Client) DeletePod(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/pods/"+url.PathEscape(id), nil, nil)
+}
+
+// ListNetworks returns all networks
+func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
+	return list[Network](ctx, c, "/networks")
+}
+
+// CreateNetwork creates a network
+func (c *Client) CreateNetwork(ctx context.Context, network *Network) (*Network, error) {
+	return call[Network](ctx, c, http.MethodPost, "/networks", network)
+}
+
+// DeleteNetwork deletes a network
+func (c *Client) DeleteNetwork(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/networks/"+url.PathEscape(id), nil, nil)
+}
+
+// ListSecrets returns all secrets, without their values
+func (c *Client) ListSecrets(ctx context.Context) ([]Secret, error) {
+	return list[Secret](ctx, c, "/secrets")
+}
+
+// GetSecret returns the secret with the given ID, without its value
+func (c *Client) GetSecret(ctx context.Context, id string) (*Secret, error) {
+	return call[Secret](ctx, c, http.MethodGet, "/secrets/"+url.PathEscape(id), nil)
+}
+
+// CreateSecret creates a secret
+func (c *Client) CreateSecret(ctx context.Context, secret *Secret) (*Secret, error) {
+	return call[Secret](ctx, c, http.MethodPost, "/secrets", secret)
+}
+
+// UpdateSecret replaces the value of the secret with secret.ID
+func (c *Client) UpdateSecret(ctx context.Context, secret *Secret) (*Secret, error) {
+	return call[Secret](ctx, c, http.MethodPut, "/secrets/"+url.PathEscape(secret.ID), secret)
+}
+
+// DeleteSecret deletes a secret
+func (c *Client) DeleteSecret(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/secrets/"+url.PathEscape(id), nil, nil)
+}
+
+// ListVolumes returns all volumes
+func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
+	return list[Volume](ctx, c, "/volumes")
+}
+
+// GetVolume returns the volume with the given ID
+func (c *Client) GetVolume(ctx context.Context, id string) (*Volume, error) {
+	return call[Volume](ctx, c, http.MethodGet, "/volumes/"+url.PathEscape(id), nil)
+}
+
+// CreateVolume creates a volume
+func (c *Client) CreateVolume(ctx context.Context, volume *Volume) (*Volume, error) {
+	return call[Volume](ctx, c, http.MethodPost, "/volumes", volume)
+}
+
+// UpdateVolume replaces the volume with volume.ID
+func (c *Client) UpdateVolume(ctx context.Context, volume *Volume) (*Volume, error) {
+	return call[Volume](ctx, c, http.MethodPut, "/volumes/"+url.PathEscape(volume.ID), volume)
+}
+
+// DeleteVolume deletes a volume. Volumes still mounted by an application
+// are refused with a ConflictStateError.
+func (c *Client) DeleteVolume(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/volumes/"+url.PathEscape(id), nil, nil)
+}
+
+// ListDomains returns all domains
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	return list[Domain](ctx, c, "/domains")
+}
+
+// GetDomain returns the domain with the given ID
+func (c *Client) GetDomain(ctx context.Context, id string) (*Domain, error) {
+	return call[Domain](ctx, c, http.MethodGet, "/domains/"+url.PathEscape(id), nil)
+}
+
+// CreateDomain adds a domain to an application
+func (c *Client) CreateDomain(ctx context.Context, domain *Domain) (*Domain, error) {
+	return call[Domain](ctx, c, http.MethodPost, "/domains", domain)
+}
+
+// UpdateDomain replaces the domain with domain.ID
+func (c *Client) UpdateDomain(ctx context.Context, domain *Domain) (*Domain, error) {
+	return call[Domain](ctx, c, http.MethodPut, "/domains/"+url.PathEscape(domain.ID), domain)
+}
+
+// DeleteDomain deletes a domain
+func (c *Client) DeleteDomain(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/domains/"+url.PathEscape(id), nil, nil)
+}
+
+// VerifyDomain checks the DNS records of a domain and returns it with the
+// result
+func (c *Client) VerifyDomain(ctx context.Context, id string) (*Domain, error) {
+	return call[Domain](ctx, c, http.MethodPost, "/domains/"+url.PathEscape(id)+"/verify", nil)
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListStacks returns all stacks
+func (c *Client) ListStacks(ctx context.Context) ([]Stack, error) {
+	return list[Stack](ctx, c, "/stacks")
+}
+
+// GetStack returns the stack with the given ID
+func (c *Client) GetStack(ctx context.Context, id string) (*Stack, error) {
+	return call[Stack](ctx, c, http.MethodGet, "/stacks/"+url.PathEscape(id), nil)
+}
+
+// CreateStack creates a stack
+func (c *Client) CreateStack(ctx context.Context, stack *Stack) (*Stack, error) {
+	return call[Stack](ctx, c, http.MethodPost, "/stacks", stack)
+}
+
+// UpdateStack replaces the stack with stack.ID
+func (c *Client) UpdateStack(ctx context.Context, stack *Stack) (*Stack, error) {
+	return call[Stack](ctx, c, http.MethodPut, "/stacks/"+url.PathEscape(stack.ID), stack)
+}
+
+// DeleteStack deletes a stack and its applications
+func (c *Client) DeleteStack(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/stacks/"+url.PathEscape(id), nil, nil)
+}
+
+// DeployStack starts the applications of a stack
+func (c *Client) DeployStack(ctx context.Context, id string) (*Stack, error) {
+	return call[Stack](ctx, c, http.MethodPost, "/stacks/"+url.PathEscape(id)+"/deploy", nil)
+}
+
+// StopStack stops the applications of a stack
+func (c *Client) StopStack(ctx context.Context, id string) (*Stack, error) {
+	return call[Stack](ctx, c, http.MethodPost, "/stacks/"+url.PathEscape(id)+"/stop", nil)
+}
+
+// ListLinks returns the links between applications. With appID set, only
+// the links from or to that application are returned.
+func (c *Client) ListLinks(ctx context.Context, appID string) ([]Link, error) {
+	query := url.Values{}
+	if appID != "" {
+		query.Set("application", appID)
+	}
+	return list[Link](ctx, c, "/links"+encode(query))
+}
+
+// CreateLink links two applications. Links that would form a dependency
+// cycle are refused.
+func (c *Client) CreateLink(ctx context.Context, link *Link) (*Link, error) {
+	return call[Link](ctx, c, http.MethodPost, "/links", link)
+}
+
+// DeleteLink deletes a link
+func (c *Client) DeleteLink(ctx context.Context, id string) error {
+	return c.Do(ctx, http.MethodDelete, "/links/"+url.PathEscape(id), nil, nil)
+}
+
+// GetGraph returns the service map: every application, in dependency
+// order, and the links between them
+func (c *Client) GetGraph(ctx context.Context) (*Graph, error) {
+	return call[Graph](ctx, c, http.MethodGet, "/graph", nil)
+}
+
+// ListActivity returns the activity feed, newest first
+func (c *Client) ListActivity(ctx context.Context, filter ActivityFilter) ([]Activity, error) {
+	query := url.Values{}
+	if filter.TeamID != "" {
+		query.Set("team", filter.TeamID)
+	}
+	if filter.ProjectID != "" {
+		query.Set("project", filter.ProjectID)
+	}
+	if filter.ApplicationID != "" {
+		query.Set("application", filter.ApplicationID)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if !filter.Before.IsZero() {
+		query.Set("before", filter.Before.UTC().Format(time.RFC3339Nano))
+	}
+	return list[Activity](ctx, c, "/activity"+encode(query))
+}
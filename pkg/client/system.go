@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ListTemplates returns the built-in application templates
+func (c *Client) ListTemplates(ctx context.Context) ([]Template, error) {
+	return list[Template](ctx, c, "/templates")
+}
+
+// GetTemplate returns the template with the given ID
+func (c *Client) GetTemplate(ctx context.Context, id string) (*Template, error) {
+	return call[Template](ctx, c, http.MethodGet, "/templates/"+url.PathEscape(id), nil)
+}
+
+// InstantiateTemplate creates an application from a template
+func (c *Client) InstantiateTemplate(ctx context.Context, id string, req *InstantiateRequest) (*InstantiateResponse, error) {
+	return call[InstantiateResponse](ctx, c, http.MethodPost, "/templates/"+url.PathEscape(id)+"/instantiate", req)
+}
+
+// DiskUsage returns the space used by the images, containers and volumes
+// of the server's container engine
+func (c *Client) DiskUsage(ctx context.Context) (*DiskUsage, error) {
+	return call[DiskUsage](ctx, c, http.MethodGet, "/system/df", nil)
+}
+
+// SystemInfo returns the version of the server's container engine and the
+// capacity of its host
+func (c *Client) SystemInfo(ctx context.Context) (*SystemInfo, error) {
+	return call[SystemInfo](ctx, c, http.MethodGet, "/system/info", nil)
+}
+
+// GetLogLevel returns the log level of the server
+func (c *Client) GetLogLevel(ctx context.Context) (string, error) {
+	level, err := call[LogLevel](ctx, c, http.MethodGet, "/logging/level", nil)
+	if err != nil {
+		return "", err
+	}
+	return level.Level, nil
+}
+
+// SetLogLevel changes the log level of the running server and returns the
+// new level
+func (c *Client) SetLogLevel(ctx context.Context, level string) (string, error) {
+	result, err := call[LogLevel](ctx, c, http.MethodPut, "/logging/level", LogLevel{Level: level})
+	if err != nil {
+		return "", err
+	}
+	return result.Level, nil
+}
+
+// BackupSummary describes a database snapshot
+type BackupSummary struct {
+	Records map[string]int `json:"records"` // record count per bucket
+	Size    int64          `json:"size"`
+}
+
+// DownloadBackup writes a consistent snapshot of the server's database to w
+func (c *Client) DownloadBackup(ctx context.Context, w io.Writer) (int64, error) {
+	return c.Download(ctx, "/backup", w)
+}
+
+// RestoreBackup verifies a snapshot written by DownloadBackup and replaces
+// the server's database with it
+func (c *Client) RestoreBackup(ctx context.Context, snapshot []byte) (*BackupSummary, error) {
+	return call[BackupSummary](ctx, c, http.MethodPost, "/backup/restore", BackupRestoreRequest{Snapshot: snapshot})
+}
+
+// ListBackups returns the backups stored at the server's backup target
+func (c *Client) ListBackups(ctx context.Context) ([]Backup, error) {
+	return list[Backup](ctx, c, "/backups")
+}
+
+// CreateBackup backs up the database and the configured volumes to the
+// backup target
+func (c *Client) CreateBackup(ctx context.Context) (*Backup, error) {
+	return call[Backup](ctx, c, http.MethodPost, "/backups", nil)
+}
+
+// RestoreStoredBackup restores a backup from the backup target, with its
+// volume archives when volumes is set
+func (c *Client) RestoreStoredBackup(ctx context.Context, name string, volumes bool) (*Backup, error) {
+	path := "/backups/" + url.PathEscape(name) + "/restore"
+	return call[Backup](ctx, c, http.MethodPost, path, StoredRestoreRequest{Volumes: volumes})
+}
+
+// Health returns the liveness of the server
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	return c.health(ctx, "/healthz")
+}
+
+// Ready returns the readiness of the server and its dependencies. A server
+// that is not ready answers with its status too, so the result is returned
+// along with the error.
+func (c *Client) Ready(ctx context.Context) (*HealthStatus, error) {
+	return c.health(ctx, "/readyz")
+}
+
+// health reads a health endpoint, which is served outside /api/v1 and is
+// not retried: its answer is the point
+func (c *Client) health(ctx context.Context, path string) (*HealthStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.attempt(ctx, http.MethodGet, c.baseURL+path, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status HealthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &status, fmt.Errorf("server is %s: %s", status.Status, resp.Status)
+	}
+	return &status, nil
+}
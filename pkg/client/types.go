@@ -0,0 +1,209 @@
+package client
+
+import (
+	"time"
+
+	"github.com/AkMo3/simplify/internal/core"
+	"github.com/AkMo3/simplify/internal/templates"
+)
+
+// Resources of the API, as returned by the server
+type (
+	Application    = core.Application
+	Instance       = core.Instance
+	Deployment     = core.Deployment
+	Build          = core.Build
+	MetricSample   = core.MetricSample
+	Team           = core.Team
+	TeamMembership = core.TeamMembership
+	User           = core.User
+	APIKey         = core.APIKey
+	Project        = core.Project
+	Environment    = core.Environment
+	Pod            = core.Pod
+	Network        = core.Network
+	Secret         = core.Secret
+	Volume         = core.Volume
+	Domain         = core.Domain
+	Stack          = core.Stack
+	Link           = core.Link
+	Graph          = core.Graph
+	Activity       = core.Activity
+	ActivityFilter = core.ActivityFilter
+	Node           = core.Node
+	NodeCapacity   = core.NodeCapacity
+	NodeState      = core.NodeState
+	Template       = templates.Template
+)
+
+// BuildRequest starts a build of an application's project repository
+type BuildRequest struct {
+	Ref           string `json:"ref,omitempty"`
+	Strategy      string `json:"strategy,omitempty"`
+	Containerfile string `json:"containerfile,omitempty"`
+	Context       string `json:"context,omitempty"`
+}
+
+// MetricsResponse is the resource usage history of an application
+type MetricsResponse struct {
+	Range   string              `json:"range"`
+	Step    string              `json:"step"`    // period each sample covers
+	Samples []core.MetricSample `json:"samples"` // oldest first
+}
+
+// RegisterNodeRequest is the body of POST /nodes. Re-registering an
+// existing node also needs its current token as the bearer token.
+type RegisterNodeRequest struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Name      string            `json:"name"`
+	JoinToken string            `json:"join_token"` // the server's server.join_token
+	Capacity  core.NodeCapacity `json:"capacity"`
+}
+
+// HeartbeatRequest is the body of POST /nodes/{id}/heartbeat
+type HeartbeatRequest struct {
+	AppStatus map[string]string `json:"app_status,omitempty"` // application ID to the status of its first replica
+	AppHealth map[string]string `json:"app_health,omitempty"` // application ID to its health, see core.Application.HealthStatus
+	Capacity  core.NodeCapacity `json:"capacity"`
+}
+
+// InstantiateRequest is the body of POST /templates/{id}/instantiate
+type InstantiateRequest struct {
+	Values        map[string]string `json:"values,omitempty"` // env variable values, overriding defaults
+	Ports         map[string]string `json:"ports,omitempty"`  // replaces the template's ports when set
+	Name          string            `json:"name"`
+	EnvironmentID string            `json:"environment_id,omitempty"`
+}
+
+// InstantiateResponse is the application created from a template. Generated
+// values are only returned here, as secret values cannot be read back.
+type InstantiateResponse struct {
+	Generated   map[string]string `json:"generated,omitempty"`
+	Application core.Application  `json:"application"`
+}
+
+// CloneEnvironmentRequest is the body of POST /environments/{id}/clone
+type CloneEnvironmentRequest struct {
+	Name           string `json:"name"`                      // of the new environment
+	NameSuffix     string `json:"name_suffix,omitempty"`     // appended to application and volume names, "-<name>" when empty
+	HostnameSuffix string `json:"hostname_suffix,omitempty"` // appended to the first label of domain hostnames, or a subdomain of apex domains; NameSuffix when empty
+	PortOffset     int    `json:"port_offset,omitempty"`     // added to published host ports; 0 publishes none
+}
+
+// CloneEnvironmentResponse is the environment created by a clone, with the
+// copies of the source environment's applications and their domains
+type CloneEnvironmentResponse struct {
+	Environment  core.Environment   `json:"environment"`
+	Applications []core.Application `json:"applications"`
+	Domains      []core.Domain      `json:"domains,omitempty"`
+}
+
+// BackupRestoreRequest carries a snapshot produced by the backup endpoint.
+// The snapshot is base64 encoded in JSON.
+type BackupRestoreRequest struct {
+	Snapshot []byte `json:"snapshot"`
+}
+
+// StoredRestoreRequest restores a stored backup
+type StoredRestoreRequest struct {
+	Volumes bool `json:"volumes"` // also extract the backup's volume archives
+}
+
+// Backup describes a stored backup
+type Backup struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Records   map[string]int `json:"records"` // database record count per bucket
+	Name      string         `json:"name"`
+	Volumes   []string       `json:"volumes,omitempty"`
+	Size      int64          `json:"size"` // bytes of the database and volume archives
+}
+
+// HealthStatus represents the health check response
+type HealthStatus struct {
+	Checks map[string]ComponentHealth `json:"checks,omitempty"`
+	Status string                     `json:"status"`
+}
+
+// ComponentHealth represents the health of a single component
+type ComponentHealth struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// DiskUsage is the space the server's container engine uses
+type DiskUsage struct {
+	Images     DiskUsageSummary `json:"images"`
+	Containers DiskUsageSummary `json:"containers"`
+	Volumes    DiskUsageSummary `json:"volumes"`
+}
+
+// DiskUsageSummary is the space used by one kind of resource. Reclaimable
+// is what removing the unused ones would free.
+type DiskUsageSummary struct {
+	Size        int64 `json:"size"`
+	Reclaimable int64 `json:"reclaimable"`
+	Total       int   `json:"total"`
+	Active      int   `json:"active"` // images used by a container, running containers, volumes mounted by a container
+}
+
+// SystemInfo describes the host running the server's container engine
+type SystemInfo struct {
+	Version       string `json:"version"`        // Podman version
+	StorageDriver string `json:"storage_driver"` // e.g. overlay
+	OS            string `json:"os"`
+	Distribution  string `json:"distribution,omitempty"` // e.g. fedora 40
+	Kernel        string `json:"kernel"`
+	Arch          string `json:"arch"`
+	Hostname      string `json:"hostname"`
+	MemTotal      int64  `json:"mem_total"`
+	MemFree       int64  `json:"mem_free"`
+	StorageSize   uint64 `json:"storage_size"` // size of the file system holding images and containers
+	StorageUsed   uint64 `json:"storage_used"`
+	CPUs          int    `json:"cpus"`
+}
+
+// WhoAmIResponse is the user and API key a request authenticated with
+type WhoAmIResponse struct {
+	APIKey APIKey `json:"api_key"`
+	User   User   `json:"user"`
+}
+
+// LogLevel is the body of the log level endpoints
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+// ExecRequest is the body of POST /applications/{id}/exec
+type ExecRequest struct {
+	User    string   `json:"user,omitempty"`
+	WorkDir string   `json:"workdir,omitempty"`
+	Command []string `json:"command"`
+	Env     []string `json:"env,omitempty"`     // KEY=VALUE, added to the container's environment
+	Replica int      `json:"replica,omitempty"` // index of the replica, as in Instance.Replica
+}
+
+// ExecOutput is one line of the newline delimited JSON answer of the exec
+// endpoint: a chunk of output, then the exit code, or an error when the
+// command could not be run to the end
+type ExecOutput struct {
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Stream   string `json:"stream,omitempty"` // "stdout" or "stderr"
+	Data     string `json:"data,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// LogOptions selects the log lines of an application
+type LogOptions struct {
+	Since      string // timestamp or duration such as 10m
+	Tail       int    // lines from the end, 0 for all
+	Replica    int    // index of the replica, as in Instance.Replica
+	Follow     bool   // keep streaming new lines until the context is canceled
+	Timestamps bool   // set LogLine.Time
+}
+
+// LogLine is a single line of container output
+type LogLine struct {
+	Time   time.Time `json:"time,omitzero"` // when the line was written, with timestamps=true
+	Stream string    `json:"stream"`        // "stdout" or "stderr"
+	Text   string    `json:"text"`
+}
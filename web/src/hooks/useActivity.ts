@@ -0,0 +1,20 @@
+import { useQuery } from '@tanstack/react-query'
+import { listActivity } from '@/lib/api'
+import type { ActivityFilter } from '@/types/api'
+
+// Query keys for cache management
+export const queryKeys = {
+  activity: (filter: ActivityFilter) => ['activity', filter] as const,
+}
+
+// =============================================================================
+// Activity Hooks
+// =============================================================================
+
+export function useActivity(filter: ActivityFilter = {}) {
+  return useQuery({
+    queryKey: queryKeys.activity(filter),
+    queryFn: () => listActivity(filter),
+    refetchInterval: 15000, // Poll every 15 seconds
+  })
+}
@@ -10,6 +10,8 @@ import type {
   CreatePodRequest,
   Network,
   CreateNetworkRequest,
+  ActivityEntry,
+  ActivityFilter,
 } from '@/types/api'
 
 const API_BASE = '/api/v1'
@@ -166,3 +168,17 @@ export async function deleteNetwork(id: string): Promise<void> {
     method: 'DELETE',
   })
 }
+
+// =============================================================================
+// Activity Endpoints
+// =============================================================================
+
+export async function listActivity(filter: ActivityFilter = {}): Promise<ActivityEntry[]> {
+  const params = new URLSearchParams()
+  if (filter.team) params.set('team', filter.team)
+  if (filter.project) params.set('project', filter.project)
+  if (filter.application) params.set('application', filter.application)
+  if (filter.limit) params.set('limit', String(filter.limit))
+  const query = params.toString()
+  return fetchApi<ActivityEntry[]>(query ? `/activity?${query}` : '/activity')
+}
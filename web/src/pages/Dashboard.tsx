@@ -1,12 +1,34 @@
 import { Link } from 'react-router-dom'
-import { Activity, Box, CheckCircle, XCircle, ArrowRight } from 'lucide-react'
+import { Activity, Box, CheckCircle, XCircle, ArrowRight, History } from 'lucide-react'
 import { useHealth, useReadiness, useApplications } from '@/hooks/useApplications'
+import { useActivity } from '@/hooks/useActivity'
 import { cn } from '@/lib/utils'
+import type { ActivityEntry } from '@/types/api'
+
+/**
+ * Format a timestamp as a short age such as "5m ago"
+ */
+function timeAgo(dateString: string): string {
+  const seconds = Math.max(0, Math.floor((Date.now() - new Date(dateString).getTime()) / 1000))
+  if (seconds < 60) return `${seconds}s ago`
+  if (seconds < 3600) return `${Math.floor(seconds / 60)}m ago`
+  if (seconds < 86400) return `${Math.floor(seconds / 3600)}h ago`
+  return `${Math.floor(seconds / 86400)}d ago`
+}
+
+/**
+ * Describe an activity entry in one line, e.g. "alice@laptop updated application web"
+ */
+function describeActivity(entry: ActivityEntry): string {
+  const resource = entry.resource_name || entry.resource_id
+  return `${entry.actor} ${entry.action} ${entry.resource_type} ${resource}`
+}
 
 export function Dashboard() {
   const healthQuery = useHealth()
   const readyQuery = useReadiness()
   const appsQuery = useApplications()
+  const activityQuery = useActivity({ limit: 10 })
 
   return (
     <div className="space-y-6 animate-fade-in">
@@ -127,6 +149,42 @@ export function Dashboard() {
           </div>
         </Link>
       </div>
+
+      {/* Recent Activity */}
+      <div className="card p-4">
+        <div className="flex items-center gap-3 mb-4">
+          <div className="p-2 rounded-md bg-primary/10">
+            <History className="h-5 w-5 text-primary" />
+          </div>
+          <div>
+            <p className="text-sm font-medium">Recent activity</p>
+            <p className="text-xs text-muted-foreground">Changes and deploy events</p>
+          </div>
+        </div>
+        {activityQuery.isLoading ? (
+          <p className="text-sm text-muted-foreground">Loading...</p>
+        ) : activityQuery.isError ? (
+          <p className="text-sm text-destructive">Failed to load activity</p>
+        ) : !activityQuery.data?.length ? (
+          <p className="text-sm text-muted-foreground">No activity recorded yet</p>
+        ) : (
+          <ul className="divide-y divide-border">
+            {activityQuery.data.map((entry) => (
+              <li key={entry.id} className="py-2 flex items-start justify-between gap-4">
+                <div className="min-w-0">
+                  <p className="text-sm truncate">{describeActivity(entry)}</p>
+                  {entry.message && (
+                    <p className="text-xs text-muted-foreground truncate">{entry.message}</p>
+                  )}
+                </div>
+                <span className="text-xs text-muted-foreground whitespace-nowrap">
+                  {timeAgo(entry.created_at)}
+                </span>
+              </li>
+            ))}
+          </ul>
+        )}
+      </div>
     </div>
   )
 }
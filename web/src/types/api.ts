@@ -140,3 +140,24 @@ export interface CreateNetworkRequest {
   name: string
 }
 
+
+export interface ActivityEntry {
+  id: string
+  created_at: string
+  actor: string
+  action: string
+  resource_type: string
+  resource_id: string
+  resource_name?: string
+  team_id?: string
+  project_id?: string
+  application_id?: string
+  message?: string
+}
+
+export interface ActivityFilter {
+  team?: string
+  project?: string
+  application?: string
+  limit?: number
+}